@@ -2,13 +2,21 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/dir01/mediary/downloader"
+	"github.com/dir01/mediary/downloader/archive"
+	"github.com/dir01/mediary/downloader/hls"
+	"github.com/dir01/mediary/downloader/local"
+	"github.com/dir01/mediary/downloader/podcast"
+	"github.com/dir01/mediary/downloader/s3"
 	"github.com/dir01/mediary/downloader/torrent"
 	"github.com/dir01/mediary/downloader/ytdlp"
 	mediary_http "github.com/dir01/mediary/http"
@@ -35,6 +43,21 @@ func main() {
 		bindAddr = os.Getenv("BIND_ADDR")
 	}
 
+	// localRoots are directories that file:// URLs are allowed to point to, separated by os.PathListSeparator
+	var localRoots []string
+	if val := os.Getenv("LOCAL_ROOTS"); val != "" {
+		localRoots = filepath.SplitList(val)
+	}
+
+	// s3Endpoints are S3-compatible services other than the default one, as JSON like
+	// {"http://minio:9000": {"accessKeyId": "...", "secretAccessKey": "...", "buckets": ["recordings"]}}
+	var s3Endpoints map[string]s3.Endpoint
+	if val := os.Getenv("S3_SOURCE_ENDPOINTS"); val != "" {
+		if err := json.Unmarshal([]byte(val), &s3Endpoints); err != nil {
+			log.Fatalf("invalid S3_SOURCE_ENDPOINTS: %v", err)
+		}
+	}
+
 	var isDebug bool
 	if val, exists := os.LookupEnv("DEBUG"); exists && val != "" && val != "0" && val != "false" {
 		isDebug = true
@@ -78,8 +101,58 @@ func main() {
 		log.Fatalf("error creating ytdl downloader: %v", err)
 	}
 
+	downloaders := []service.Downloader{torrentDownloader}
+
+	// localDownloader serves files already present on local filesystem, e.g. a NAS mount
+	if len(localRoots) > 0 {
+		localDownloader, err := local.New(localRoots, logger)
+		if err != nil {
+			log.Fatalf("error creating local downloader: %v", err)
+		}
+		downloaders = append(downloaders, localDownloader)
+	}
+
+	// s3Downloader fetches s3://bucket/prefix/ listings and presigned GET URLs.
+	// Credentials come from the standard AWS chain, unless overridden with S3_SOURCE_* variables,
+	// and buckets of S3_SOURCE_ENDPOINTS are fetched from their endpoints with their credentials.
+	s3Downloader, err := s3.New(context.Background(), os.TempDir(), s3.Config{
+		Endpoint:        os.Getenv("S3_SOURCE_ENDPOINT"),
+		Region:          os.Getenv("S3_SOURCE_REGION"),
+		AccessKeyID:     os.Getenv("S3_SOURCE_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("S3_SOURCE_SECRET_ACCESS_KEY"),
+		Endpoints:       s3Endpoints,
+	}, logger)
+	if err != nil {
+		log.Fatalf("error creating s3 downloader: %v", err)
+	}
+	downloaders = append(downloaders, s3Downloader)
+
+	// podcastDownloader treats episodes of RSS/Atom feeds as variants
+	podcastDownloader, err := podcast.New(os.TempDir(), logger)
+	if err != nil {
+		log.Fatalf("error creating podcast downloader: %v", err)
+	}
+	downloaders = append(downloaders, podcastDownloader)
+
+	// hlsDownloader fetches HLS (.m3u8) and DASH (.mpd) streams, one variant per quality level
+	hlsDownloader, err := hls.New(os.TempDir(), logger)
+	if err != nil {
+		log.Fatalf("error creating hls downloader: %v", err)
+	}
+	downloaders = append(downloaders, hlsDownloader)
+
+	// yt-dlp goes last, since it is the slowest to figure out whether it accepts a URL
+	downloaders = append(downloaders, ytdlDownloader)
+
 	// dwn is a composite downloader: it can download anything, as long as one of its minions knows how to
-	dwn := downloader.NewCompositeDownloader([]service.Downloader{torrentDownloader, ytdlDownloader})
+	dwn := downloader.NewCompositeDownloader(downloaders)
+	if priority := os.Getenv("DOWNLOADER_PRIORITY"); priority != "" {
+		if err := dwn.SetPriority(strings.Split(priority, ",")); err != nil {
+			log.Fatalf("error setting downloader priority: %v", err)
+		}
+	}
+	// files inside of zip/rar/7z archives become variants of their own, like "book.zip!/01.mp3"
+	dwn.SetArchiveExpander(archive.NewExpander(os.TempDir(), logger))
 
 	db, err := storage.OpenSQLiteDB(sqliteDBPath)
 	if err != nil {
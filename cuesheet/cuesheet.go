@@ -0,0 +1,186 @@
+// Package cuesheet parses CUE sheets, which describe tracks of a disc ripped into one or more files.
+// See https://wiki.hydrogenaud.io/index.php?title=Cue_sheet
+package cuesheet
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// framesPerSecond is the resolution of CUE timestamps, which come from audio CD sectors
+const framesPerSecond = 75
+
+type Sheet struct {
+	Title     string
+	Performer string
+	// Genre and Date come from REM comments, which most rippers write
+	Genre string
+	Date  string
+	Files []File
+}
+
+type File struct {
+	// Name is the file name as written in the sheet, often with a stale extension (e.g. .wav for a .flac rip)
+	Name   string
+	Tracks []Track
+}
+
+type Track struct {
+	Number    int
+	Title     string
+	Performer string
+	// Start is the position of INDEX 01 within the file
+	Start time.Duration
+}
+
+// Parse reads a CUE sheet. Sheets that are not valid UTF-8 are assumed to be Latin-1,
+// the usual encoding of sheets produced by older rippers.
+func Parse(r io.Reader) (*Sheet, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		data = latin1ToUTF8(data)
+	}
+
+	sheet := &Sheet{}
+	var file *File
+	var track *Track
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		command, args := splitLine(scanner.Text())
+		switch command {
+		case "FILE":
+			if len(args) == 0 {
+				return nil, fmt.Errorf("line %d: FILE without a name", lineNum)
+			}
+			sheet.Files = append(sheet.Files, File{Name: args[0]})
+			file = &sheet.Files[len(sheet.Files)-1]
+			track = nil
+		case "TRACK":
+			if file == nil {
+				return nil, fmt.Errorf("line %d: TRACK before FILE", lineNum)
+			}
+			if len(args) == 0 {
+				return nil, fmt.Errorf("line %d: TRACK without a number", lineNum)
+			}
+			number, err := strconv.Atoi(args[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid track number %q", lineNum, args[0])
+			}
+			file.Tracks = append(file.Tracks, Track{Number: number})
+			track = &file.Tracks[len(file.Tracks)-1]
+		case "INDEX":
+			if track == nil || len(args) < 2 {
+				return nil, fmt.Errorf("line %d: misplaced INDEX", lineNum)
+			}
+			// INDEX 00 is the pregap, the track itself starts at INDEX 01
+			if args[0] != "01" && args[0] != "1" {
+				continue
+			}
+			start, err := ParseTimestamp(args[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			track.Start = start
+		case "REM":
+			if file != nil || len(args) < 2 {
+				continue
+			}
+			switch strings.ToUpper(args[0]) {
+			case "GENRE":
+				sheet.Genre = strings.Join(args[1:], " ")
+			case "DATE":
+				sheet.Date = strings.Join(args[1:], " ")
+			}
+		case "TITLE", "PERFORMER":
+			if len(args) == 0 {
+				continue
+			}
+			value := strings.Join(args, " ")
+			switch {
+			case track != nil && command == "TITLE":
+				track.Title = value
+			case track != nil:
+				track.Performer = value
+			case command == "TITLE":
+				sheet.Title = value
+			default:
+				sheet.Performer = value
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sheet, nil
+}
+
+// ParseTimestamp parses mm:ss:ff, where ff are frames, 75 per second
+func ParseTimestamp(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	var values [3]int
+	for i, p := range parts {
+		v, err := strconv.Atoi(p)
+		if err != nil || v < 0 {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		values[i] = v
+	}
+	minutes, seconds, frames := values[0], values[1], values[2]
+	if seconds >= 60 || frames >= framesPerSecond {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	return time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second +
+		time.Duration(frames)*time.Second/framesPerSecond, nil
+}
+
+// splitLine splits a line into an upper-cased command and its arguments, honoring double quotes
+func splitLine(line string) (string, []string) {
+	var fields []string
+	var current strings.Builder
+	inQuotes, hasField := false, false
+	for _, r := range strings.TrimSpace(line) {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			hasField = true
+		case (r == ' ' || r == '\t') && !inQuotes:
+			if hasField {
+				fields = append(fields, current.String())
+				current.Reset()
+				hasField = false
+			}
+		default:
+			current.WriteRune(r)
+			hasField = true
+		}
+	}
+	if hasField {
+		fields = append(fields, current.String())
+	}
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return strings.ToUpper(fields[0]), fields[1:]
+}
+
+func latin1ToUTF8(data []byte) []byte {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return []byte(string(runes))
+}
@@ -0,0 +1,71 @@
+package cuesheet
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	input := "\xef\xbb\xbfREM GENRE Rock\r\n" +
+		"REM DATE 1997\r\n" +
+		"PERFORMER \"Some Band\"\r\n" +
+		"TITLE \"Live at the \"\"Club\"\"\"\r\n" +
+		"FILE \"CD1 - part one.wav\" WAVE\r\n" +
+		"  TRACK 01 AUDIO\r\n" +
+		"    TITLE \"Intro\"\r\n" +
+		"    INDEX 01 00:00:00\r\n" +
+		"  TRACK 02 AUDIO\r\n" +
+		"    TITLE Opening Song\r\n" +
+		"    PERFORMER \"Guest\"\r\n" +
+		"    INDEX 00 03:10:00\r\n" +
+		"    INDEX 01 03:12:37\r\n" +
+		"FILE \"part two.flac\" WAVE\r\n" +
+		"  TRACK 03 AUDIO\r\n" +
+		"    INDEX 01 00:00:00\r\n"
+
+	sheet, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := &Sheet{
+		Title:     "Live at the Club",
+		Performer: "Some Band",
+		Genre:     "Rock",
+		Date:      "1997",
+		Files: []File{
+			{Name: "CD1 - part one.wav", Tracks: []Track{
+				{Number: 1, Title: "Intro"},
+				{Number: 2, Title: "Opening Song", Performer: "Guest", Start: 3*time.Minute + 12*time.Second + 37*time.Second/75},
+			}},
+			{Name: "part two.flac", Tracks: []Track{{Number: 3}}},
+		},
+	}
+	if !reflect.DeepEqual(sheet, want) {
+		t.Errorf("got %+v\nwant %+v", sheet, want)
+	}
+}
+
+func TestParse_Latin1(t *testing.T) {
+	sheet, err := Parse(strings.NewReader("TITLE \"Caf\xe9\"\nFILE \"a.wav\" WAVE\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if sheet.Title != "Café" {
+		t.Errorf("title = %q", sheet.Title)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, input := range []string{
+		"TRACK 01 AUDIO\n",
+		"FILE \"a.wav\" WAVE\nTRACK xx AUDIO\n",
+		"FILE \"a.wav\" WAVE\nTRACK 01 AUDIO\nINDEX 01 00:61:00\n",
+		"FILE \"a.wav\" WAVE\nINDEX 01 00:00:00\n",
+	} {
+		if _, err := Parse(strings.NewReader(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
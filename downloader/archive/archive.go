@@ -0,0 +1,454 @@
+package archive
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/samber/oops"
+
+	"github.com/dir01/mediary/service"
+)
+
+// MemberSeparator separates archive variant from the path of a file inside of it,
+// like "book.zip!/cd1/01.mp3"
+const MemberSeparator = "!/"
+
+var ErrMemberNotFound = fmt.Errorf("archive member not found")
+
+// ErrTooLarge means that requested members of an archive unpack into more than the limit, see NewExpander
+var ErrTooLarge = fmt.Errorf("archive members are too large to extract")
+
+// DefaultMaxExtractedBytes is how much members of archives a single download may unpack into,
+// so that a zip bomb doesn't fill up the disk
+const DefaultMaxExtractedBytes = 16 << 30
+
+// errNotListable means an archive can't be listed without downloading it
+var errNotListable = errors.New("archive can't be listed without downloading it")
+
+// NewExpander creates an Expander that extracts archive members into dataDir,
+// no more than DefaultMaxExtractedBytes per download.
+// Zip archives are handled natively; rar and 7z require 7z binary in PATH.
+func NewExpander(dataDir string, logger *slog.Logger) *Expander {
+	e := &Expander{dataDir: dataDir, maxExtractedBytes: DefaultMaxExtractedBytes, log: logger}
+	if sevenZip, err := exec.LookPath("7z"); err == nil {
+		e.sevenZip = sevenZip
+	} else {
+		logger.Debug("7z not found, rar and 7z archives will not be expanded")
+	}
+	return e
+}
+
+// Expander presents files inside archives as virtual variants of the URL the archive came from
+type Expander struct {
+	dataDir           string
+	maxExtractedBytes int64
+	log               *slog.Logger
+	// sevenZip is a path to 7z binary, empty if it is not available
+	sevenZip string
+}
+
+type member struct {
+	Path     string
+	LenBytes int64
+}
+
+// SplitMember splits virtual variant into archive variant and member path.
+// ok is false if variant is not an archive member.
+func SplitMember(variant string) (archiveVariant string, memberPath string, ok bool) {
+	i := strings.Index(variant, MemberSeparator)
+	if i == -1 || !isArchive(variant[:i]) {
+		return "", "", false
+	}
+	return variant[:i], variant[i+len(MemberSeparator):], true
+}
+
+// ExpandMetadata lists every archive among metadata variants and adds its files as variants,
+// right after the archive itself. Archives are never downloaded for that: the ones that can't be listed
+// otherwise, or fail to be listed, are left as is. Metadata of a single archive, like a direct link to it,
+// allows multiple variants once it is expanded, since its files can be picked together.
+func (e *Expander) ExpandMetadata(ctx context.Context, dwn service.Downloader, metadata *service.Metadata) {
+	variants := make([]service.VariantMetadata, 0, len(metadata.Variants))
+	for _, v := range metadata.Variants {
+		variants = append(variants, v)
+		if !e.canExpand(v.ID) {
+			continue
+		}
+		logAttrs := []any{slog.String("url", metadata.URL), slog.String("archive", v.ID)}
+
+		members, err := e.listMembers(ctx, dwn, metadata.URL, v)
+		if errors.Is(err, errNotListable) {
+			e.log.Debug("archive can't be listed without downloading it, leaving it unexpanded", logAttrs...)
+			continue
+		} else if err != nil {
+			e.log.Warn("failed to list archive, leaving it unexpanded", append(logAttrs, slog.Any("error", err))...)
+			continue
+		}
+		e.log.Debug("expanded archive", append(logAttrs, slog.Int("members", len(members)))...)
+
+		if len(members) > 0 {
+			metadata.AllowMultipleVariants = true
+		}
+		for _, m := range members {
+			lenBytes := m.LenBytes
+			variants = append(variants, service.VariantMetadata{
+				ID:       v.ID + MemberSeparator + m.Path,
+				LenBytes: &lenBytes,
+			})
+		}
+	}
+	metadata.Variants = variants
+}
+
+// Download downloads requested variants with dwn.
+// Archive members are extracted from their archives, and only requested members are extracted,
+// into a directory of their own, so that concurrent downloads of the same archive don't overwrite each other's files.
+func (e *Expander) Download(ctx context.Context, dwn service.Downloader, url string, filepaths []string) (map[string]string, error) {
+	// region figure out what to ask the downloader for
+	toDownload := make([]string, 0, len(filepaths))
+	seen := make(map[string]struct{}, len(filepaths))
+	requested := make(map[string]struct{}, len(filepaths))
+	membersByArchive := make(map[string][]string)
+	for _, fp := range filepaths {
+		requested[fp] = struct{}{}
+		downloadable := fp
+		if archiveVariant, memberPath, ok := SplitMember(fp); ok {
+			membersByArchive[archiveVariant] = append(membersByArchive[archiveVariant], memberPath)
+			downloadable = archiveVariant
+		}
+		if _, exists := seen[downloadable]; !exists {
+			seen[downloadable] = struct{}{}
+			toDownload = append(toDownload, downloadable)
+		}
+	}
+	// endregion
+
+	downloaded, err := dwn.Download(ctx, url, toDownload)
+	if err != nil {
+		return nil, err
+	}
+	if len(membersByArchive) == 0 {
+		return downloaded, nil
+	}
+
+	filepathsMap := make(map[string]string, len(filepaths))
+	for fp, localPath := range downloaded {
+		if _, ok := requested[fp]; ok {
+			filepathsMap[fp] = localPath
+		}
+	}
+
+	budget := e.maxExtractedBytes
+	for archiveVariant, memberPaths := range membersByArchive {
+		errCtx := oops.With("url", url, "archive", archiveVariant, "members", memberPaths)
+		archivePath, ok := downloaded[archiveVariant]
+		if !ok {
+			return nil, errCtx.Errorf("downloader did not return archive")
+		}
+		destDir, err := e.workspaceDir(url, archiveVariant)
+		if err != nil {
+			return nil, errCtx.Wrapf(err, "failed to create workspace")
+		}
+		extracted, extractedBytes, err := e.extract(ctx, archivePath, memberPaths, destDir, budget)
+		budget -= extractedBytes
+		if err != nil {
+			_ = os.RemoveAll(destDir)
+			return nil, errCtx.Wrapf(err, "failed to extract archive members")
+		}
+		for memberPath, localPath := range extracted {
+			filepathsMap[archiveVariant+MemberSeparator+memberPath] = localPath
+		}
+	}
+
+	return filepathsMap, nil
+}
+
+func (e *Expander) canExpand(variant string) bool {
+	switch strings.ToLower(path.Ext(variant)) {
+	case ".zip":
+		return true
+	case ".rar", ".7z":
+		return e.sevenZip != ""
+	default:
+		return false
+	}
+}
+
+// listMembers reads the table of contents of an archive without downloading it, which is only possible
+// when the downloader can stream it. Zip keeps its table of contents at the end of the file, so only that part
+// is read. 7z needs a file on disk, so rar and 7z archives are only listed when the stream is of a local file.
+// Anything else is left to errNotListable, since listing it would download the whole archive.
+func (e *Expander) listMembers(ctx context.Context, dwn service.Downloader, url string, variant service.VariantMetadata) ([]member, error) {
+	streamer, ok := dwn.(service.Streamer)
+	if !ok {
+		return nil, errNotListable
+	}
+	stream, err := streamer.Stream(ctx, url, variant.ID)
+	if errors.Is(err, service.ErrStreamingNotSupported) {
+		return nil, errNotListable
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to stream archive: %w", err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	if isZip(variant.ID) {
+		zr, err := zip.NewReader(&readerAt{rs: stream}, stream.Size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read zip directory: %w", err)
+		}
+		return zipMembers(zr), nil
+	}
+	if file, ok := stream.ReadSeekCloser.(*os.File); ok {
+		return e.sevenZipMembers(ctx, file.Name())
+	}
+	return nil, errNotListable
+}
+
+// extract extracts members into destDir, unless they unpack into more than limit bytes,
+// and tells how many bytes they took
+func (e *Expander) extract(
+	ctx context.Context, archivePath string, memberPaths []string, destDir string, limit int64,
+) (map[string]string, int64, error) {
+	for _, mp := range memberPaths {
+		if !filepath.IsLocal(mp) {
+			return nil, 0, fmt.Errorf("refusing to extract %q: path escapes archive", mp)
+		}
+	}
+	if isZip(archivePath) {
+		return extractZip(archivePath, memberPaths, destDir, limit)
+	}
+	return e.extractSevenZip(ctx, archivePath, memberPaths, destDir, limit)
+}
+
+// workspaceDir creates a new directory to extract members of a given archive to.
+// Its name starts the same for the same archive, so that it can be told where the files came from.
+func (e *Expander) workspaceDir(url string, archiveVariant string) (string, error) {
+	if err := os.MkdirAll(e.dataDir, 0o755); err != nil {
+		return "", err
+	}
+	hash := md5.Sum([]byte(url + MemberSeparator + archiveVariant))
+	return os.MkdirTemp(e.dataDir, "archive_"+hex.EncodeToString(hash[:])+"_*")
+}
+
+func zipMembers(zr *zip.Reader) []member {
+	members := make([]member, 0, len(zr.File))
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		members = append(members, member{Path: f.Name, LenBytes: int64(f.UncompressedSize64)})
+	}
+	return members
+}
+
+// extractZip checks sizes the archive declares before extracting anything, and then the actual sizes,
+// since the declared ones may be a lie
+func extractZip(archivePath string, memberPaths []string, destDir string, limit int64) (map[string]string, int64, error) {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open zip: %w", err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var declared uint64
+	for _, mp := range memberPaths {
+		f, ok := files[mp]
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: %s", ErrMemberNotFound, mp)
+		}
+		declared += f.UncompressedSize64
+	}
+	if declared > uint64(max(limit, 0)) {
+		return nil, 0, fmt.Errorf("%w: %d bytes, limit is %d", ErrTooLarge, declared, limit)
+	}
+
+	extracted := make(map[string]string, len(memberPaths))
+	var total int64
+	for _, mp := range memberPaths {
+		localPath := filepath.Join(destDir, filepath.FromSlash(mp))
+		written, err := extractZipFile(files[mp], localPath, limit-total)
+		total += written
+		if err != nil {
+			return nil, total, fmt.Errorf("failed to extract %s: %w", mp, err)
+		}
+		extracted[mp] = localPath
+	}
+	return extracted, total, nil
+}
+
+func extractZipFile(f *zip.File, localPath string, limit int64) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+		return 0, err
+	}
+	src, err := f.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = src.Close() }()
+
+	dst, err := os.Create(localPath)
+	if err != nil {
+		return 0, err
+	}
+	written, err := io.Copy(dst, io.LimitReader(src, limit+1))
+	if err == nil && written > limit {
+		err = fmt.Errorf("%w: limit is %d bytes", ErrTooLarge, limit)
+	}
+	if err != nil {
+		_ = dst.Close()
+		return written, err
+	}
+	return written, dst.Close()
+}
+
+// sevenZipMembers parses technical listing (`7z l -slt`), which is a sequence of "Key = Value" blocks
+func (e *Expander) sevenZipMembers(ctx context.Context, archivePath string) ([]member, error) {
+	cmd := exec.CommandContext(ctx, e.sevenZip, "l", "-slt", "-ba", "--", archivePath)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, oops.With("cmd", cmd.String()).Wrapf(err, "failed to list archive")
+	}
+	return parseSevenZipListing(out), nil
+}
+
+func parseSevenZipListing(out []byte) []member {
+	var members []member
+	var current member
+	isDir := false
+	flush := func() {
+		if current.Path != "" && !isDir {
+			members = append(members, current)
+		}
+		current = member{}
+		isDir = false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, " = ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Path":
+			flush()
+			current.Path = filepath.ToSlash(value)
+		case "Size":
+			current.LenBytes, _ = strconv.ParseInt(value, 10, 64)
+		case "Folder":
+			isDir = value == "+"
+		case "Attributes":
+			isDir = isDir || strings.HasPrefix(value, "D")
+		}
+	}
+	flush()
+	return members
+}
+
+// extractSevenZip checks sizes the listing of the archive declares before extracting it,
+// 7z has no way to stop once the limit is hit
+func (e *Expander) extractSevenZip(
+	ctx context.Context, archivePath string, memberPaths []string, destDir string, limit int64,
+) (map[string]string, int64, error) {
+	if e.sevenZip == "" {
+		return nil, 0, fmt.Errorf("7z is not available")
+	}
+	members, err := e.sevenZipMembers(ctx, archivePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	sizes := make(map[string]int64, len(members))
+	for _, m := range members {
+		sizes[m.Path] = m.LenBytes
+	}
+	var declared int64
+	for _, mp := range memberPaths {
+		declared += sizes[mp]
+	}
+	if declared > limit {
+		return nil, 0, fmt.Errorf("%w: %d bytes, limit is %d", ErrTooLarge, declared, limit)
+	}
+
+	cmd := exec.CommandContext(ctx, e.sevenZip, sevenZipExtractArgs(archivePath, memberPaths, destDir)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, 0, oops.With("cmd", cmd.String(), "output", string(out)).Wrapf(err, "failed to run 7z")
+	}
+
+	extracted := make(map[string]string, len(memberPaths))
+	var total int64
+	for _, mp := range memberPaths {
+		localPath := filepath.Join(destDir, filepath.FromSlash(mp))
+		stat, err := os.Stat(localPath)
+		if err != nil {
+			return nil, total, fmt.Errorf("%w: %s", ErrMemberNotFound, mp)
+		}
+		total += stat.Size()
+		extracted[mp] = localPath
+	}
+	if total > limit {
+		return nil, total, fmt.Errorf("%w: %d bytes, limit is %d", ErrTooLarge, total, limit)
+	}
+	return extracted, total, nil
+}
+
+// sevenZipExtractArgs treat member names literally: -spd turns off wildcards,
+// and "--" keeps names starting with "-" from being taken for switches
+func sevenZipExtractArgs(archivePath string, memberPaths []string, destDir string) []string {
+	return append([]string{"x", "-y", "-spd", "-o" + destDir, "--", archivePath}, memberPaths...)
+}
+
+func isArchive(variant string) bool {
+	switch strings.ToLower(path.Ext(variant)) {
+	case ".zip", ".rar", ".7z":
+		return true
+	default:
+		return false
+	}
+}
+
+func isZip(p string) bool {
+	return strings.EqualFold(path.Ext(p), ".zip")
+}
+
+// readerAt adapts a seekable stream to io.ReaderAt, which is what archive/zip needs
+type readerAt struct {
+	mu sync.Mutex
+	rs io.ReadSeeker
+}
+
+func (r *readerAt) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.rs.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(r.rs, p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
@@ -0,0 +1,280 @@
+package archive
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/dir01/mediary/service"
+	"github.com/dir01/mediary/service/mocks"
+	"github.com/gojuno/minimock/v3"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func makeZip(t *testing.T, files map[string]string) string {
+	t.Helper()
+	zipPath := filepath.Join(t.TempDir(), "book.zip")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatalf("create zip: %v", err)
+	}
+	// entries are written in a stable order, since it's the order members are listed in
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	zw := zip.NewWriter(f)
+	for _, name := range names {
+		content := files[name]
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("create zip entry: %v", err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("write zip entry: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip writer: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	return zipPath
+}
+
+func TestSplitMember(t *testing.T) {
+	tests := []struct {
+		variant     string
+		wantArchive string
+		wantMember  string
+		wantOk      bool
+	}{
+		{variant: "book.zip!/cd1/01.mp3", wantArchive: "book.zip", wantMember: "cd1/01.mp3", wantOk: true},
+		{variant: "dir/Book.RAR!/01.mp3", wantArchive: "dir/Book.RAR", wantMember: "01.mp3", wantOk: true},
+		{variant: "chapter1.mp3"},
+		{variant: "wow!/chapter1.mp3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.variant, func(t *testing.T) {
+			archive, member, ok := SplitMember(tt.variant)
+			if archive != tt.wantArchive || member != tt.wantMember || ok != tt.wantOk {
+				t.Errorf("SplitMember(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.variant, archive, member, ok, tt.wantArchive, tt.wantMember, tt.wantOk)
+			}
+		})
+	}
+}
+
+// streamingDownloader streams variants from local files
+type streamingDownloader struct {
+	*mocks.DownloaderMock
+	files map[string]string
+}
+
+func (d *streamingDownloader) Stream(_ context.Context, _ string, variant string) (*service.VariantStream, error) {
+	fp, ok := d.files[variant]
+	if !ok {
+		return nil, service.ErrVariantNotFound
+	}
+	f, err := os.Open(fp)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &service.VariantStream{ReadSeekCloser: f, Name: filepath.Base(fp), Size: stat.Size()}, nil
+}
+
+func TestExpandMetadataAndDownload(t *testing.T) {
+	mc := minimock.NewController(t)
+	zipPath := makeZip(t, map[string]string{
+		"cd1/01.mp3": "first chapter",
+		"cd1/02.mp3": "second chapter, a bit longer",
+	})
+	url := "magnet:?xt=urn:btih:deadbeef"
+
+	dwn := &streamingDownloader{DownloaderMock: mocks.NewDownloaderMock(mc), files: map[string]string{"book.zip": zipPath}}
+	dwn.DownloadMock.Set(func(_ context.Context, _ string, filepaths []string) (map[string]string, error) {
+		result := make(map[string]string)
+		for _, fp := range filepaths {
+			switch fp {
+			case "book.zip":
+				result[fp] = zipPath
+			case "cover.jpg":
+				result[fp] = "/downloads/cover.jpg"
+			default:
+				t.Fatalf("unexpected download of %s", fp)
+			}
+		}
+		return result, nil
+	})
+
+	metadata := &service.Metadata{
+		URL:                   url,
+		Variants:              []service.VariantMetadata{{ID: "book.zip"}, {ID: "cover.jpg"}},
+		AllowMultipleVariants: true,
+	}
+
+	expander := NewExpander(t.TempDir(), testLogger)
+	expander.ExpandMetadata(context.Background(), dwn, metadata)
+	if dwn.DownloadAfterCounter() != 0 {
+		t.Fatal("archive was downloaded to be listed")
+	}
+
+	var ids []string
+	for _, v := range metadata.Variants {
+		ids = append(ids, v.ID)
+	}
+	wantIDs := []string{"book.zip", "book.zip!/cd1/01.mp3", "book.zip!/cd1/02.mp3", "cover.jpg"}
+	if !reflect.DeepEqual(ids, wantIDs) {
+		t.Fatalf("variants = %v, want %v", ids, wantIDs)
+	}
+	if *metadata.Variants[1].LenBytes != int64(len("first chapter")) {
+		t.Errorf("member length = %d", *metadata.Variants[1].LenBytes)
+	}
+
+	filepathsMap, err := expander.Download(context.Background(), dwn, url, []string{"book.zip!/cd1/02.mp3", "cover.jpg"})
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if len(filepathsMap) != 2 {
+		t.Fatalf("expected exactly requested variants, got %v", filepathsMap)
+	}
+	content, err := os.ReadFile(filepathsMap["book.zip!/cd1/02.mp3"])
+	if err != nil {
+		t.Fatalf("failed to read extracted member: %v", err)
+	}
+	if string(content) != "second chapter, a bit longer" {
+		t.Errorf("extracted content = %q", content)
+	}
+	if filepathsMap["cover.jpg"] != "/downloads/cover.jpg" {
+		t.Errorf("plain variant path = %q", filepathsMap["cover.jpg"])
+	}
+}
+
+func TestExpandMetadata_SingleArchive(t *testing.T) {
+	mc := minimock.NewController(t)
+	zipPath := makeZip(t, map[string]string{"01.mp3": "first chapter", "02.mp3": "second chapter"})
+	dwn := &streamingDownloader{DownloaderMock: mocks.NewDownloaderMock(mc), files: map[string]string{"book.zip": zipPath}}
+
+	// like a direct link to an archive
+	metadata := &service.Metadata{URL: "https://example.com/book.zip", Variants: []service.VariantMetadata{{ID: "book.zip"}}}
+	NewExpander(t.TempDir(), testLogger).ExpandMetadata(context.Background(), dwn, metadata)
+
+	if len(metadata.Variants) != 3 || !metadata.AllowMultipleVariants {
+		t.Errorf("unexpected metadata: %+v", metadata)
+	}
+}
+
+func TestDownload_ExtractsIntoDirectoryOfItsOwn(t *testing.T) {
+	mc := minimock.NewController(t)
+	zipPath := makeZip(t, map[string]string{"01.mp3": "first chapter"})
+	dwn := mocks.NewDownloaderMock(mc)
+	dwn.DownloadMock.Return(map[string]string{"book.zip": zipPath}, nil)
+
+	expander := NewExpander(t.TempDir(), testLogger)
+	var paths []string
+	for range 2 {
+		filepathsMap, err := expander.Download(context.Background(), dwn, "magnet:?xt=urn:btih:deadbeef", []string{"book.zip!/01.mp3"})
+		if err != nil {
+			t.Fatalf("Download failed: %v", err)
+		}
+		paths = append(paths, filepathsMap["book.zip!/01.mp3"])
+	}
+	if paths[0] == paths[1] {
+		t.Errorf("both downloads extracted into %s", paths[0])
+	}
+}
+
+func TestDownload_TooLarge(t *testing.T) {
+	mc := minimock.NewController(t)
+	zipPath := makeZip(t, map[string]string{"01.mp3": "first chapter", "02.mp3": "second chapter"})
+	dwn := mocks.NewDownloaderMock(mc)
+	dwn.DownloadMock.Return(map[string]string{"book.zip": zipPath}, nil)
+
+	dataDir := t.TempDir()
+	expander := NewExpander(dataDir, testLogger)
+	expander.maxExtractedBytes = int64(len("first chapter") + 1)
+
+	_, err := expander.Download(context.Background(), dwn, "magnet:?xt=urn:btih:deadbeef", []string{"book.zip!/01.mp3", "book.zip!/02.mp3"})
+	if !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dataDir); len(entries) != 0 {
+		t.Errorf("workspace is left behind: %v", entries)
+	}
+}
+
+func TestExtractZipFile_ChecksActualSize(t *testing.T) {
+	zipPath := makeZip(t, map[string]string{"01.mp3": "first chapter"})
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		t.Fatalf("open zip: %v", err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	// the limit is checked while copying too, whatever size the archive declares
+	_, err = extractZipFile(zr.File[0], filepath.Join(t.TempDir(), "01.mp3"), 5)
+	if !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+}
+
+func TestExpandMetadata_LeavesArchivesThatCantBeStreamed(t *testing.T) {
+	mc := minimock.NewController(t)
+	// no Download is expected: listing an archive must not download it
+	dwn := mocks.NewDownloaderMock(mc)
+
+	zipLen := int64(4 << 30)
+	metadata := &service.Metadata{
+		URL:      "magnet:?xt=urn:btih:deadbeef",
+		Variants: []service.VariantMetadata{{ID: "book.zip", LenBytes: &zipLen}, {ID: "book.rar"}, {ID: "book.7z"}},
+	}
+	expander := &Expander{dataDir: t.TempDir(), log: testLogger, sevenZip: "7z"}
+	expander.ExpandMetadata(context.Background(), dwn, metadata)
+
+	if len(metadata.Variants) != 3 {
+		t.Errorf("variants = %+v, want archives left as they are", metadata.Variants)
+	}
+}
+
+func TestSevenZipExtractArgs(t *testing.T) {
+	got := sevenZipExtractArgs("/tmp/book.7z", []string{"-rf.mp3", "cd1/*.mp3"}, "/tmp/out")
+	want := []string{"x", "-y", "-spd", "-o/tmp/out", "--", "/tmp/book.7z", "-rf.mp3", "cd1/*.mp3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sevenZipExtractArgs() = %q, want %q", got, want)
+	}
+}
+
+func TestParseSevenZipListing(t *testing.T) {
+	out := `Path = cd1
+Folder = +
+Size = 0
+
+Path = cd1/01.mp3
+Folder = -
+Size = 1234
+
+Path = cd1/02.mp3
+Size = 5678
+Attributes = A
+`
+	got := parseSevenZipListing([]byte(out))
+	want := []member{{Path: "cd1/01.mp3", LenBytes: 1234}, {Path: "cd1/02.mp3", LenBytes: 5678}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSevenZipListing() = %v, want %v", got, want)
+	}
+}
@@ -3,20 +3,87 @@ package downloader
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/dir01/mediary/downloader/archive"
 	"github.com/dir01/mediary/service"
 )
 
 var ErrUrlNotSupported = fmt.Errorf("url not supported")
 
 func NewCompositeDownloader(downloaders []service.Downloader) *Downloader {
-	downloader := &Downloader{downloaders}
+	downloader := &Downloader{downloaders: downloaders}
 	var _ service.Downloader = downloader
+	var _ service.Streamer = downloader
+	var _ service.SectionDownloader = downloader
+	var _ service.DownloaderSelector = downloader
 	return downloader
 }
 
 type Downloader struct {
 	downloaders []service.Downloader
+	// archives, when set, expands archives into virtual variants, see SetArchiveExpander
+	archives *archive.Expander
+	// forced is set on downloaders picked by Select, which accept any URL of their schemes
+	forced bool
+}
+
+// SetArchiveExpander makes files inside of archives (zip, rar, 7z) available as variants,
+// like "book.zip!/cd1/01.mp3"
+func (d *Downloader) SetArchiveExpander(expander *archive.Expander) {
+	d.archives = expander
+}
+
+// SetPriority reorders downloaders: the named ones go first, in the given order, the rest keep their original order.
+// This matters when several downloaders accept the same URL, e.g. a direct mp3 link that yt-dlp claims too.
+func (d *Downloader) SetPriority(names []string) error {
+	byName := make(map[string]service.Downloader, len(d.downloaders))
+	for _, downloader := range d.downloaders {
+		byName[describe(downloader).Name] = downloader
+	}
+
+	prioritized := make([]service.Downloader, 0, len(d.downloaders))
+	seen := make(map[service.Downloader]struct{}, len(d.downloaders))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		downloader, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("%w: %s", service.ErrUnknownDownloader, name)
+		}
+		if _, ok := seen[downloader]; ok {
+			continue
+		}
+		seen[downloader] = struct{}{}
+		prioritized = append(prioritized, downloader)
+	}
+	for _, downloader := range d.downloaders {
+		if _, ok := seen[downloader]; !ok {
+			prioritized = append(prioritized, downloader)
+		}
+	}
+	d.downloaders = prioritized
+	return nil
+}
+
+// Downloaders lists registered downloaders, in order of priority
+func (d *Downloader) Downloaders() []service.DownloaderInfo {
+	infos := make([]service.DownloaderInfo, 0, len(d.downloaders))
+	for _, downloader := range d.downloaders {
+		infos = append(infos, describe(downloader))
+	}
+	return infos
+}
+
+// Select returns a composite downloader that only consists of the downloader with the given name.
+// It accepts any URL of the schemes the downloader describes, even one the downloader can't tell
+// it supports by the URL alone, like a podcast feed at an arbitrary address. Archive expansion still applies.
+func (d *Downloader) Select(name string) (service.Downloader, error) {
+	for _, downloader := range d.downloaders {
+		if describe(downloader).Name == name {
+			return &Downloader{downloaders: []service.Downloader{downloader}, archives: d.archives, forced: true}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", service.ErrUnknownDownloader, name)
 }
 
 func (d *Downloader) AcceptsURL(url string) bool {
@@ -28,26 +95,87 @@ func (d *Downloader) AcceptsURL(url string) bool {
 }
 
 func (d *Downloader) GetMetadata(ctx context.Context, url string) (*service.Metadata, error) {
-	if downloader := d.getConcreteDownloader(url); downloader == nil {
+	downloader := d.getConcreteDownloader(url)
+	if downloader == nil {
 		return nil, ErrUrlNotSupported
-	} else {
-		return downloader.GetMetadata(ctx, url)
 	}
+	metadata, err := downloader.GetMetadata(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if d.archives != nil {
+		d.archives.ExpandMetadata(ctx, downloader, metadata)
+	}
+	return metadata, nil
 }
 
 func (d *Downloader) Download(ctx context.Context, url string, filepaths []string) (filepathsMap map[string]string, err error) {
 	if downloader := d.getConcreteDownloader(url); downloader == nil {
 		return nil, ErrUrlNotSupported
+	} else if d.archives != nil {
+		return d.archives.Download(ctx, downloader, url, filepaths)
 	} else {
 		return downloader.Download(ctx, url, filepaths)
 	}
 }
 
+// describe falls back to the type name for downloaders that can't describe themselves
+func describe(downloader service.Downloader) service.DownloaderInfo {
+	if describer, ok := downloader.(service.Describer); ok {
+		return describer.Describe()
+	}
+	return service.DownloaderInfo{Name: fmt.Sprintf("%T", downloader)}
+}
+
 func (d *Downloader) getConcreteDownloader(url string) service.Downloader {
 	for _, downloader := range d.downloaders {
-		if downloader.AcceptsURL(url) {
+		if downloader.AcceptsURL(url) || (d.forced && hasScheme(url, describe(downloader).Schemes)) {
 			return downloader
 		}
 	}
 	return nil
 }
+
+func hasScheme(url string, schemes []string) bool {
+	for _, scheme := range schemes {
+		if strings.HasPrefix(strings.ToLower(url), scheme+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// Stream delegates to the concrete downloader, provided that it supports streaming
+func (d *Downloader) Stream(ctx context.Context, url string, variant string) (*service.VariantStream, error) {
+	downloader := d.getConcreteDownloader(url)
+	if downloader == nil {
+		return nil, ErrUrlNotSupported
+	}
+	if _, _, isMember := archive.SplitMember(variant); isMember {
+		// members are compressed, there is no way to seek within them without extracting first
+		return nil, service.ErrStreamingNotSupported
+	}
+	streamer, ok := downloader.(service.Streamer)
+	if !ok {
+		return nil, service.ErrStreamingNotSupported
+	}
+	return streamer.Stream(ctx, url, variant)
+}
+
+// DownloadSections delegates to the concrete downloader, provided that it can download sections
+func (d *Downloader) DownloadSections(
+	ctx context.Context, url string, variant string, ranges []service.TimeRange, fast bool,
+) ([]string, error) {
+	downloader := d.getConcreteDownloader(url)
+	if downloader == nil {
+		return nil, ErrUrlNotSupported
+	}
+	if _, _, isMember := archive.SplitMember(variant); isMember {
+		return nil, service.ErrSectionsNotSupported
+	}
+	sectionDownloader, ok := downloader.(service.SectionDownloader)
+	if !ok {
+		return nil, service.ErrSectionsNotSupported
+	}
+	return sectionDownloader.DownloadSections(ctx, url, variant, ranges, fast)
+}
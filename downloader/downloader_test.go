@@ -0,0 +1,103 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dir01/mediary/service"
+	"github.com/dir01/mediary/service/mocks"
+	"github.com/gojuno/minimock/v3"
+)
+
+type namedDownloader struct {
+	*mocks.DownloaderMock
+	name string
+}
+
+func (n namedDownloader) Describe() service.DownloaderInfo {
+	return service.DownloaderInfo{Name: n.name, Schemes: []string{"https"}}
+}
+
+func names(infos []service.DownloaderInfo) []string {
+	result := make([]string, 0, len(infos))
+	for _, info := range infos {
+		result = append(result, info.Name)
+	}
+	return result
+}
+
+func TestSetPriority(t *testing.T) {
+	mc := minimock.NewController(t)
+	d := NewCompositeDownloader([]service.Downloader{
+		namedDownloader{mocks.NewDownloaderMock(mc), "torrent"},
+		namedDownloader{mocks.NewDownloaderMock(mc), "podcast"},
+		namedDownloader{mocks.NewDownloaderMock(mc), "hls"},
+		namedDownloader{mocks.NewDownloaderMock(mc), "ytdl"},
+	})
+
+	if err := d.SetPriority([]string{"hls", " ytdl", "hls"}); err != nil {
+		t.Fatalf("SetPriority failed: %v", err)
+	}
+	if got, want := names(d.Downloaders()), []string{"hls", "ytdl", "torrent", "podcast"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %q, want %q", got, want)
+	}
+
+	if err := d.SetPriority([]string{"nope"}); !errors.Is(err, service.ErrUnknownDownloader) {
+		t.Errorf("expected ErrUnknownDownloader, got %v", err)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	mc := minimock.NewController(t)
+	ytdl := mocks.NewDownloaderMock(mc)
+	ytdl.AcceptsURLMock.Optional().Return(true)
+	direct := mocks.NewDownloaderMock(mc)
+	direct.AcceptsURLMock.Return(true)
+	direct.GetMetadataMock.Return(&service.Metadata{DownloaderName: "direct"}, nil)
+
+	d := NewCompositeDownloader([]service.Downloader{
+		namedDownloader{ytdl, "ytdl"},
+		namedDownloader{direct, "direct"},
+	})
+
+	selected, err := d.Select("direct")
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	// ytdl comes first and accepts the URL too, but it must not be asked
+	metadata, err := selected.GetMetadata(context.Background(), "https://example.com/episode.mp3")
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if metadata.DownloaderName != "direct" {
+		t.Errorf("metadata came from %q", metadata.DownloaderName)
+	}
+
+	if _, err := d.Select("nope"); !errors.Is(err, service.ErrUnknownDownloader) {
+		t.Errorf("expected ErrUnknownDownloader, got %v", err)
+	}
+}
+
+func TestSelect_AcceptsAnyURLOfItsSchemes(t *testing.T) {
+	mc := minimock.NewController(t)
+	podcast := mocks.NewDownloaderMock(mc)
+	// the feed URL doesn't look like one
+	podcast.AcceptsURLMock.Return(false)
+
+	d := NewCompositeDownloader([]service.Downloader{namedDownloader{podcast, "podcast"}})
+	if d.AcceptsURL("https://example.com/show") {
+		t.Error("URL must not be accepted unless the downloader is forced")
+	}
+	selected, err := d.Select("podcast")
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if !selected.AcceptsURL("https://example.com/show") {
+		t.Error("forced downloader must accept any URL of its schemes")
+	}
+	if selected.AcceptsURL("magnet:?xt=urn:btih:deadbeef") {
+		t.Error("forced downloader must not accept URLs of other schemes")
+	}
+}
@@ -0,0 +1,414 @@
+package hls
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/samber/oops"
+
+	"github.com/dir01/mediary/service"
+)
+
+const (
+	defaultConcurrency = 4
+	// maxManifestBytes protects from reading a huge file while fetching a manifest
+	maxManifestBytes = 10 * 1024 * 1024
+)
+
+// ErrLiveStream is returned for live playlists, which only list the latest few segments
+var ErrLiveStream = fmt.Errorf("live streams are not supported")
+
+func New(dataDir string, logger *slog.Logger) (*Downloader, error) {
+	d := &Downloader{
+		dataDir:    dataDir,
+		httpClient: http.DefaultClient,
+		fetcher:    newSegmentFetcher(http.DefaultClient, defaultConcurrency, logger),
+		log:        logger,
+	}
+	var _ service.Downloader = d
+	var _ service.Describer = d
+	return d, nil
+}
+
+// Downloader handles HLS (.m3u8) and DASH (.mpd) manifests.
+// Every variant (quality level) becomes a variant, segments are fetched concurrently and remuxed into a single file.
+// Only complete recordings are supported: live streams have no end to download up to, so they are rejected.
+type Downloader struct {
+	dataDir    string
+	httpClient *http.Client
+	fetcher    *segmentFetcher
+	log        *slog.Logger
+}
+
+// trackSource is either an HLS media playlist that is yet to be fetched, or an already expanded DASH track
+type trackSource struct {
+	PlaylistURL string
+	Track       *mediaPlaylist
+}
+
+type variantSource struct {
+	ID    string
+	Video *trackSource
+	// Audio is a separate audio track to be muxed in, if any. For audio-only variants it is the only track.
+	Audio *trackSource
+}
+
+func (d *Downloader) Describe() service.DownloaderInfo {
+	return service.DownloaderInfo{
+		Name:     "hls",
+		Schemes:  []string{"http", "https"},
+		Patterns: []string{"*.m3u8", "*.mpd"},
+	}
+}
+
+func (d *Downloader) AcceptsURL(url string) bool {
+	parsed, err := neturl.Parse(url)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return false
+	}
+	switch strings.ToLower(path.Ext(parsed.Path)) {
+	case ".m3u8", ".mpd":
+		return true
+	default:
+		return false
+	}
+}
+
+func (d *Downloader) GetMetadata(ctx context.Context, url string) (*service.Metadata, error) {
+	variants, err := d.listVariants(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	metadata := &service.Metadata{
+		URL:                   url,
+		Name:                  manifestName(url),
+		AllowMultipleVariants: false,
+		DownloaderName:        "hls",
+	}
+	for _, v := range variants {
+		metadata.Variants = append(metadata.Variants, service.VariantMetadata{ID: v.ID})
+	}
+	return metadata, nil
+}
+
+func (d *Downloader) Download(ctx context.Context, url string, filepaths []string) (filepathsMap map[string]string, err error) {
+	if len(filepaths) != 1 {
+		return nil, fmt.Errorf("expected 1 filepath, got %d", len(filepaths))
+	}
+	errCtx := oops.With("url", url, "variant", filepaths[0])
+
+	variants, err := d.listVariants(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	var variant *variantSource
+	for i := range variants {
+		if variants[i].ID == filepaths[0] {
+			variant = &variants[i]
+			break
+		}
+	}
+	if variant == nil {
+		return nil, errCtx.Wrapf(service.ErrVariantNotFound, "unknown variant")
+	}
+
+	hash := md5.Sum([]byte(url + "\x00" + variant.ID))
+	workDir := filepath.Join(d.dataDir, "hls_"+hex.EncodeToString(hash[:]))
+	if err := os.MkdirAll(workDir, 0o755); err != nil {
+		return nil, errCtx.Wrapf(err, "failed to create work dir")
+	}
+
+	// keys are shared by tracks of the variant, and only by them
+	keys := newKeyCache()
+	var inputs []string
+	for name, source := range map[string]*trackSource{"video": variant.Video, "audio": variant.Audio} {
+		if source == nil {
+			continue
+		}
+		trackPath, err := d.fetchTrack(ctx, source, keys, filepath.Join(workDir, name))
+		if err != nil {
+			return nil, errCtx.Wrapf(err, "failed to fetch %s track", name)
+		}
+		if name == "video" {
+			inputs = append([]string{trackPath}, inputs...)
+		} else {
+			inputs = append(inputs, trackPath)
+		}
+	}
+
+	ext := ".m4a"
+	if variant.Video != nil {
+		ext = ".mp4"
+	}
+	resultPath := filepath.Join(workDir, "result"+ext)
+	if err := d.remux(ctx, inputs, resultPath); err != nil {
+		return nil, errCtx.Wrapf(err, "failed to remux segments")
+	}
+	for _, input := range inputs {
+		_ = os.Remove(input)
+	}
+
+	return map[string]string{variant.ID: resultPath}, nil
+}
+
+func (d *Downloader) fetchTrack(ctx context.Context, source *trackSource, keys *keyCache, destPathBase string) (string, error) {
+	track := source.Track
+	if track == nil {
+		data, base, err := d.fetchManifest(ctx, source.PlaylistURL)
+		if err != nil {
+			return "", err
+		}
+		if track, err = parseMediaPlaylist(data, base); err != nil {
+			return "", err
+		}
+	}
+	// fragmented mp4 segments only make sense together with their initialization section,
+	// and raw audio segments are just pieces of an audio file
+	destPath := destPathBase + ".ts"
+	if track.InitURI != "" {
+		destPath = destPathBase + ".mp4"
+	} else if track.audioOnly() {
+		destPath = destPathBase + uriExt(track.Segments[0].URI)
+	}
+	d.log.Debug("fetching track", slog.String("destPath", destPath), slog.Int("segments", len(track.Segments)))
+	if err := d.fetcher.fetchTrack(ctx, track, keys, destPath); err != nil {
+		return "", err
+	}
+	return destPath, nil
+}
+
+// remux puts all the tracks into a single container without re-encoding
+func (d *Downloader) remux(ctx context.Context, inputs []string, resultPath string) error {
+	args := []string{"-y"}
+	for _, input := range inputs {
+		args = append(args, "-i", input)
+	}
+	for i := range inputs {
+		args = append(args, "-map", fmt.Sprintf("%d", i))
+	}
+	args = append(args, "-c", "copy", resultPath)
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	d.log.Debug("running ffmpeg", slog.String("cmd", cmd.String()))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return oops.With("cmd", cmd.String(), "output", string(out)).Wrapf(err, "failed to run ffmpeg")
+	}
+	return nil
+}
+
+// listVariants fetches the manifest and turns it into variants.
+// It has to be deterministic: Download figures out which tracks to fetch by listing variants again.
+func (d *Downloader) listVariants(ctx context.Context, url string) ([]variantSource, error) {
+	data, base, err := d.fetchManifest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var variants []variantSource
+	switch {
+	case bytes.Contains(data[:min(len(data), 1024)], []byte("<MPD")):
+		representations, err := parseMPD(data, base)
+		if err != nil {
+			return nil, err
+		}
+		variants = dashVariants(representations)
+	case isMasterPlaylist(data):
+		master, err := parseMasterPlaylist(data, base)
+		if err != nil {
+			return nil, err
+		}
+		variants = hlsVariants(master)
+	default:
+		// a media playlist: there is just one variant
+		track, err := parseMediaPlaylist(data, base)
+		if err != nil {
+			return nil, err
+		}
+		if track.audioOnly() {
+			variants = []variantSource{{ID: "default", Audio: &trackSource{Track: track}}}
+		} else {
+			variants = []variantSource{{ID: "default", Video: &trackSource{Track: track}}}
+		}
+	}
+
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("manifest has no playable variants")
+	}
+	return uniqueIDs(variants), nil
+}
+
+func (d *Downloader) fetchManifest(ctx context.Context, url string) ([]byte, *neturl.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestBytes))
+	if err != nil {
+		return nil, nil, err
+	}
+	// resolve relative URIs against the final URL, after redirects
+	return data, resp.Request.URL, nil
+}
+
+func hlsVariants(master *masterPlaylist) []variantSource {
+	var variants []variantSource
+	for _, stream := range master.Streams {
+		description := describe(stream.Resolution, stream.Bandwidth)
+		if audioOnlyCodecs(stream.Codecs) {
+			variants = append(variants, variantSource{
+				ID:    "audio only: " + description,
+				Audio: &trackSource{PlaylistURL: stream.URI},
+			})
+			continue
+		}
+		video := &trackSource{PlaylistURL: stream.URI}
+
+		var audioRenditions []hlsRendition
+		for _, r := range master.Renditions[stream.AudioGroup] {
+			if r.Type == "AUDIO" && r.URI != "" {
+				audioRenditions = append(audioRenditions, r)
+			}
+		}
+		if len(audioRenditions) == 0 {
+			// audio, if any, is muxed into the stream itself
+			variants = append(variants, variantSource{ID: description, Video: video})
+			continue
+		}
+		for _, r := range audioRenditions {
+			variants = append(variants, variantSource{
+				ID:    fmt.Sprintf("%s, audio: %s", description, describeRendition(r)),
+				Video: video,
+				Audio: &trackSource{PlaylistURL: r.URI},
+			})
+		}
+	}
+
+	// audio-only renditions are variants of their own
+	seen := make(map[string]struct{})
+	for _, stream := range master.Streams {
+		for _, r := range master.Renditions[stream.AudioGroup] {
+			if r.Type != "AUDIO" || r.URI == "" {
+				continue
+			}
+			if _, ok := seen[r.URI]; ok {
+				continue
+			}
+			seen[r.URI] = struct{}{}
+			variants = append(variants, variantSource{
+				ID:    "audio only: " + describeRendition(r),
+				Audio: &trackSource{PlaylistURL: r.URI},
+			})
+		}
+	}
+	return variants
+}
+
+func dashVariants(representations []dashRepresentation) []variantSource {
+	// video representations get the best audio muxed in
+	var bestAudio *dashRepresentation
+	for i, rep := range representations {
+		if rep.ContentType == "audio" && (bestAudio == nil || rep.Bandwidth > bestAudio.Bandwidth) {
+			bestAudio = &representations[i]
+		}
+	}
+
+	var variants []variantSource
+	for _, rep := range representations {
+		if rep.ContentType != "video" {
+			continue
+		}
+		track := rep.Track
+		v := variantSource{
+			ID:    describe(resolution(rep.Width, rep.Height), rep.Bandwidth),
+			Video: &trackSource{Track: &track},
+		}
+		if bestAudio != nil {
+			audioTrack := bestAudio.Track
+			v.Audio = &trackSource{Track: &audioTrack}
+		}
+		variants = append(variants, v)
+	}
+	for _, rep := range representations {
+		if rep.ContentType != "audio" {
+			continue
+		}
+		track := rep.Track
+		id := "audio only: " + describe("", rep.Bandwidth)
+		if rep.Lang != "" {
+			id += " " + rep.Lang
+		}
+		variants = append(variants, variantSource{ID: id, Audio: &trackSource{Track: &track}})
+	}
+	return variants
+}
+
+// describe produces human-readable IDs like "1280x720 2500k"
+func describe(resolution string, bandwidth int64) string {
+	parts := make([]string, 0, 2)
+	if resolution != "" {
+		parts = append(parts, resolution)
+	}
+	if bandwidth > 0 {
+		parts = append(parts, fmt.Sprintf("%dk", bandwidth/1000))
+	}
+	if len(parts) == 0 {
+		return "default"
+	}
+	return strings.Join(parts, " ")
+}
+
+func describeRendition(r hlsRendition) string {
+	parts := []string{r.Name}
+	if r.Language != "" && !strings.EqualFold(r.Language, r.Name) {
+		parts = append(parts, "("+r.Language+")")
+	}
+	return strings.TrimSpace(strings.Join(parts, " "))
+}
+
+func resolution(width, height int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%dx%d", width, height)
+}
+
+func uniqueIDs(variants []variantSource) []variantSource {
+	seen := make(map[string]int, len(variants))
+	for i := range variants {
+		id := variants[i].ID
+		seen[id]++
+		if seen[id] > 1 {
+			variants[i].ID = fmt.Sprintf("%s #%d", id, seen[id])
+		}
+	}
+	return variants
+}
+
+func manifestName(url string) string {
+	parsed, err := neturl.Parse(url)
+	if err != nil {
+		return url
+	}
+	base := path.Base(parsed.Path)
+	return strings.TrimSuffix(base, path.Ext(base))
+}
@@ -0,0 +1,358 @@
+package hls
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+const masterFixture = `#EXTM3U
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="aac",NAME="English",LANGUAGE="en",DEFAULT=YES,URI="audio/en.m3u8"
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="aac",NAME="Deutsch",LANGUAGE="de",URI="audio/de.m3u8"
+#EXT-X-STREAM-INF:BANDWIDTH=2500000,RESOLUTION=1280x720,CODECS="avc1.4d401f,mp4a.40.2",AUDIO="aac"
+video/720.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=800000,RESOLUTION=640x360
+video/360.m3u8
+`
+
+const encryptedMediaFixture = `#EXTM3U
+#EXT-X-TARGETDURATION:4
+#EXT-X-MEDIA-SEQUENCE:7
+#EXT-X-KEY:METHOD=AES-128,URI="key.bin"
+#EXTINF:4.0,
+seg0.ts
+#EXT-X-KEY:METHOD=AES-128,URI="key.bin",IV=0x000102030405060708090a0b0c0d0e0f
+#EXTINF:3.5,
+seg1.ts
+#EXT-X-KEY:METHOD=NONE
+#EXTINF:2,
+seg2.ts
+#EXT-X-ENDLIST
+`
+
+const mpdFixture = `<?xml version="1.0"?>
+<MPD xmlns="urn:mpeg:dash:schema:mpd:2011" mediaPresentationDuration="PT10S">
+  <Period>
+    <AdaptationSet contentType="video" mimeType="video/mp4">
+      <SegmentTemplate timescale="1000" duration="4000" startNumber="1" initialization="$RepresentationID$/init.mp4" media="$RepresentationID$/seg-$Number%05d$.m4s"/>
+      <Representation id="v720" bandwidth="2500000" width="1280" height="720"/>
+    </AdaptationSet>
+    <AdaptationSet mimeType="audio/mp4" lang="en">
+      <SegmentTemplate timescale="48000" initialization="a/init.mp4" media="a/$Time$.m4s">
+        <SegmentTimeline>
+          <S t="0" d="96000" r="1"/>
+          <S d="48000"/>
+        </SegmentTimeline>
+      </SegmentTemplate>
+      <Representation id="a128" bandwidth="128000"/>
+    </AdaptationSet>
+    <AdaptationSet contentType="text" mimeType="text/vtt">
+      <Representation id="subs" bandwidth="100"><BaseURL>subs.vtt</BaseURL></Representation>
+    </AdaptationSet>
+  </Period>
+</MPD>`
+
+func mustParseURL(t *testing.T, raw string) *neturl.URL {
+	t.Helper()
+	u, err := neturl.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestParseMasterPlaylist(t *testing.T) {
+	master, err := parseMasterPlaylist([]byte(masterFixture), mustParseURL(t, "https://cdn.example.com/show/master.m3u8"))
+	if err != nil {
+		t.Fatalf("parseMasterPlaylist failed: %v", err)
+	}
+	if len(master.Streams) != 2 || master.Streams[0].URI != "https://cdn.example.com/show/video/720.m3u8" {
+		t.Fatalf("unexpected streams: %+v", master.Streams)
+	}
+	if master.Streams[0].Codecs != "avc1.4d401f,mp4a.40.2" {
+		t.Errorf("quoted attribute with comma parsed as %q", master.Streams[0].Codecs)
+	}
+
+	var ids []string
+	for _, v := range uniqueIDs(hlsVariants(master)) {
+		ids = append(ids, v.ID)
+	}
+	want := []string{
+		"1280x720 2500k, audio: English (en)",
+		"1280x720 2500k, audio: Deutsch (de)",
+		"640x360 800k",
+		"audio only: English (en)",
+		"audio only: Deutsch (de)",
+	}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("ids = %q, want %q", ids, want)
+	}
+}
+
+func TestAudioOnly(t *testing.T) {
+	base := mustParseURL(t, "https://cdn.example.com/radio/media.m3u8")
+	aac := "#EXTM3U\n#EXTINF:10,\nseg0.aac?token=1\n#EXTINF:10,\nseg1.aac\n#EXT-X-ENDLIST\n"
+	track, err := parseMediaPlaylist([]byte(aac), base)
+	if err != nil {
+		t.Fatalf("parseMediaPlaylist failed: %v", err)
+	}
+	if !track.audioOnly() {
+		t.Error("playlist of aac segments must be audio only")
+	}
+	if video, _ := parseMediaPlaylist([]byte(encryptedMediaFixture), base); video.audioOnly() {
+		t.Error("playlist of ts segments must not be audio only")
+	}
+
+	master, err := parseMasterPlaylist([]byte(`#EXTM3U
+#EXT-X-STREAM-INF:BANDWIDTH=128000,CODECS="mp4a.40.2"
+audio/128.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=800000,RESOLUTION=640x360,CODECS="avc1.4d401f,mp4a.40.2"
+video/360.m3u8
+`), base)
+	if err != nil {
+		t.Fatalf("parseMasterPlaylist failed: %v", err)
+	}
+	variants := hlsVariants(master)
+	if len(variants) != 2 || variants[0].ID != "audio only: 128k" || variants[0].Video != nil || variants[1].Video == nil {
+		t.Errorf("unexpected variants: %+v", variants)
+	}
+}
+
+func TestParseMediaPlaylist_Keys(t *testing.T) {
+	track, err := parseMediaPlaylist([]byte(encryptedMediaFixture), mustParseURL(t, "https://cdn.example.com/a/media.m3u8"))
+	if err != nil {
+		t.Fatalf("parseMediaPlaylist failed: %v", err)
+	}
+	if len(track.Segments) != 3 {
+		t.Fatalf("expected 3 segments, got %d", len(track.Segments))
+	}
+	first, second, third := track.Segments[0], track.Segments[1], track.Segments[2]
+	if first.Key == nil || first.Key.URI != "https://cdn.example.com/a/key.bin" || first.Key.Sequence != 7 || first.Key.IV != nil {
+		t.Errorf("unexpected first key: %+v", first.Key)
+	}
+	if second.Key == nil || second.Key.IV[15] != 0x0f || second.Duration != 3500*time.Millisecond {
+		t.Errorf("unexpected second segment: %+v", second)
+	}
+	if third.Key != nil {
+		t.Errorf("METHOD=NONE must disable encryption, got %+v", third.Key)
+	}
+}
+
+func TestParseMPD(t *testing.T) {
+	representations, err := parseMPD([]byte(mpdFixture), mustParseURL(t, "https://cdn.example.com/dash/manifest.mpd"))
+	if err != nil {
+		t.Fatalf("parseMPD failed: %v", err)
+	}
+	if len(representations) != 2 {
+		t.Fatalf("expected text representation to be skipped, got %+v", representations)
+	}
+
+	video := representations[0]
+	if video.Track.InitURI != "https://cdn.example.com/dash/v720/init.mp4" {
+		t.Errorf("video init = %s", video.Track.InitURI)
+	}
+	var videoURIs []string
+	for _, s := range video.Track.Segments {
+		videoURIs = append(videoURIs, s.URI)
+	}
+	if want := []string{
+		"https://cdn.example.com/dash/v720/seg-00001.m4s",
+		"https://cdn.example.com/dash/v720/seg-00002.m4s",
+		"https://cdn.example.com/dash/v720/seg-00003.m4s",
+	}; !reflect.DeepEqual(videoURIs, want) {
+		t.Errorf("video segments = %q, want %q", videoURIs, want)
+	}
+
+	audio := representations[1]
+	if audio.ContentType != "audio" || audio.Lang != "en" {
+		t.Errorf("unexpected audio representation: %+v", audio)
+	}
+	var audioURIs []string
+	for _, s := range audio.Track.Segments {
+		audioURIs = append(audioURIs, s.URI)
+	}
+	if want := []string{
+		"https://cdn.example.com/dash/a/0.m4s",
+		"https://cdn.example.com/dash/a/96000.m4s",
+		"https://cdn.example.com/dash/a/192000.m4s",
+	}; !reflect.DeepEqual(audioURIs, want) {
+		t.Errorf("audio segments = %q, want %q", audioURIs, want)
+	}
+
+	var ids []string
+	for _, v := range dashVariants(representations) {
+		ids = append(ids, v.ID)
+	}
+	if want := []string{"1280x720 2500k", "audio only: 128k en"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ids = %q, want %q", ids, want)
+	}
+}
+
+func TestParseISODuration(t *testing.T) {
+	for input, want := range map[string]time.Duration{
+		"PT10S":       10 * time.Second,
+		"PT1H2M3.5S":  time.Hour + 2*time.Minute + 3500*time.Millisecond,
+		"P1DT1S":      24*time.Hour + time.Second,
+		"":            0,
+		"ten seconds": 0,
+	} {
+		if got := parseISODuration(input); got != want {
+			t.Errorf("parseISODuration(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestFetchTrack_DecryptsAndJoinsInOrder(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	explicitIV := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	plain := []string{"first segment ", "second segment ", "third, in clear"}
+	sequenceIV := make([]byte, 16)
+	sequenceIV[15] = 7
+
+	failedOnce := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a/media.m3u8":
+			_, _ = io.WriteString(w, encryptedMediaFixture)
+		case "/a/key.bin":
+			_, _ = w.Write(key)
+		case "/a/seg0.ts":
+			_, _ = w.Write(encrypt(t, []byte(plain[0]), key, sequenceIV))
+		case "/a/seg1.ts":
+			// transient failures are retried
+			if !failedOnce {
+				failedOnce = true
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			_, _ = w.Write(encrypt(t, []byte(plain[1]), key, explicitIV))
+		case "/a/seg2.ts":
+			_, _ = io.WriteString(w, plain[2])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	d, err := New(t.TempDir(), testLogger)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	// a single worker keeps the retry bookkeeping above free of races
+	d.fetcher = newSegmentFetcher(server.Client(), 1, testLogger)
+
+	url := server.URL + "/a/media.m3u8"
+	if !d.AcceptsURL(url) || d.AcceptsURL(server.URL+"/a/seg0.ts") {
+		t.Fatalf("AcceptsURL must only accept manifests")
+	}
+
+	metadata, err := d.GetMetadata(context.Background(), url)
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if metadata.Name != "media" || len(metadata.Variants) != 1 || metadata.Variants[0].ID != "default" {
+		t.Fatalf("unexpected metadata: %+v", metadata)
+	}
+
+	variants, err := d.listVariants(context.Background(), url)
+	if err != nil {
+		t.Fatalf("listVariants failed: %v", err)
+	}
+	destPath, err := d.fetchTrack(context.Background(), variants[0].Video, newKeyCache(), filepath.Join(t.TempDir(), "video"))
+	if err != nil {
+		t.Fatalf("fetchTrack failed: %v", err)
+	}
+	data, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatalf("failed to read track: %v", err)
+	}
+	if want := strings.Join(plain, ""); string(data) != want {
+		t.Errorf("track = %q, want %q", data, want)
+	}
+	if _, err := os.Stat(destPath + ".parts"); !os.IsNotExist(err) {
+		t.Errorf("parts directory must be cleaned up")
+	}
+}
+
+func TestKeyCache_FetchesEveryKeyOnce(t *testing.T) {
+	keys := newKeyCache()
+	fastFetched := make(chan struct{})
+	var slowFetches, fastFetches atomic.Int32
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			// a slow key server doesn't hold up segments of other keys
+			_, _ = keys.get("slow", func() ([]byte, error) {
+				slowFetches.Add(1)
+				<-fastFetched
+				return []byte("slow"), nil
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			key, _ := keys.get("fast", func() ([]byte, error) {
+				fastFetches.Add(1)
+				close(fastFetched)
+				return []byte("fast"), nil
+			})
+			if string(key) != "fast" {
+				t.Errorf("key = %q", key)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if slowFetches.Load() != 1 || fastFetches.Load() != 1 {
+		t.Errorf("keys were fetched %d and %d times, want once each", slowFetches.Load(), fastFetches.Load())
+	}
+}
+
+func TestLiveStreamsAreRejected(t *testing.T) {
+	base := mustParseURL(t, "https://cdn.example.com/live/media.m3u8")
+	live := strings.Replace(encryptedMediaFixture, "#EXT-X-ENDLIST\n", "", 1)
+	if _, err := parseMediaPlaylist([]byte(live), base); !errors.Is(err, ErrLiveStream) {
+		t.Errorf("expected ErrLiveStream for a playlist without an end, got %v", err)
+	}
+	vod := strings.Replace(live, "#EXTM3U\n", "#EXTM3U\n#EXT-X-PLAYLIST-TYPE:VOD\n", 1)
+	if _, err := parseMediaPlaylist([]byte(vod), base); err != nil {
+		t.Errorf("VOD playlist: %v", err)
+	}
+
+	dynamic := strings.Replace(mpdFixture, "<MPD ", `<MPD type="dynamic" `, 1)
+	if _, err := parseMPD([]byte(dynamic), base); !errors.Is(err, ErrLiveStream) {
+		t.Errorf("expected ErrLiveStream for a dynamic mpd, got %v", err)
+	}
+}
+
+func encrypt(t *testing.T, plain, key, iv []byte) []byte {
+	t.Helper()
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	padding := aes.BlockSize - len(plain)%aes.BlockSize
+	padded := append(append([]byte{}, plain...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	encrypted := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, padded)
+	return encrypted
+}
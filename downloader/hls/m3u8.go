@@ -0,0 +1,275 @@
+package hls
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	neturl "net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// masterPlaylist is a parsed HLS master playlist. URIs are already resolved against playlist URL.
+type masterPlaylist struct {
+	Streams []hlsStream
+	// Renditions are alternative (mostly audio) renditions, by group ID
+	Renditions map[string][]hlsRendition
+}
+
+type hlsStream struct {
+	URI        string
+	Bandwidth  int64
+	Resolution string
+	Codecs     string
+	AudioGroup string
+}
+
+type hlsRendition struct {
+	Type     string
+	GroupID  string
+	Name     string
+	Language string
+	Default  bool
+	URI      string
+}
+
+// mediaPlaylist is a parsed HLS media playlist: the list of segments to fetch
+type mediaPlaylist struct {
+	Segments []segment
+	// InitURI is EXT-X-MAP: initialization section of fragmented mp4 playlists
+	InitURI string
+}
+
+// segment is a piece of a track, regardless of whether it came from HLS or DASH
+type segment struct {
+	URI      string
+	Duration time.Duration
+	// Key is set for segments encrypted with AES-128
+	Key *segmentKey
+}
+
+type segmentKey struct {
+	URI string
+	// IV is explicit initialization vector, when nil the media sequence number is used
+	IV []byte
+	// Sequence is the media sequence number of the segment
+	Sequence int64
+}
+
+// audioSegmentExts are extensions of segments that carry nothing but audio
+var audioSegmentExts = map[string]bool{".aac": true, ".mp3": true, ".m4a": true, ".ac3": true, ".ec3": true}
+
+// audioOnly tells whether the playlist carries nothing but audio, judging by its segments,
+// since media playlists don't list their codecs
+func (p *mediaPlaylist) audioOnly() bool {
+	for _, seg := range p.Segments {
+		if !audioSegmentExts[uriExt(seg.URI)] {
+			return false
+		}
+	}
+	return p.InitURI == "" || audioSegmentExts[uriExt(p.InitURI)]
+}
+
+// audioOnlyCodecs tells whether codecs of a stream, like "mp4a.40.2", are all audio ones
+func audioOnlyCodecs(codecs string) bool {
+	if codecs == "" {
+		return false
+	}
+	for _, codec := range strings.Split(codecs, ",") {
+		codec = strings.ToLower(strings.TrimSpace(codec))
+		if !strings.HasPrefix(codec, "mp4a") && !strings.HasPrefix(codec, "ac-3") && !strings.HasPrefix(codec, "ec-3") &&
+			codec != "opus" && codec != "flac" {
+			return false
+		}
+	}
+	return true
+}
+
+func uriExt(uri string) string {
+	if parsed, err := neturl.Parse(uri); err == nil {
+		uri = parsed.Path
+	}
+	return strings.ToLower(path.Ext(uri))
+}
+
+func isMasterPlaylist(data []byte) bool {
+	return bytes.Contains(data, []byte("#EXT-X-STREAM-INF"))
+}
+
+func parseMasterPlaylist(data []byte, base *neturl.URL) (*masterPlaylist, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("#EXTM3U")) {
+		return nil, fmt.Errorf("not an m3u8 playlist")
+	}
+	playlist := &masterPlaylist{Renditions: make(map[string][]hlsRendition)}
+
+	var pending *hlsStream
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#EXT-X-STREAM-INF:"):
+			attrs := parseAttributes(strings.TrimPrefix(line, "#EXT-X-STREAM-INF:"))
+			bandwidth, _ := strconv.ParseInt(attrs["BANDWIDTH"], 10, 64)
+			pending = &hlsStream{
+				Bandwidth:  bandwidth,
+				Resolution: attrs["RESOLUTION"],
+				Codecs:     attrs["CODECS"],
+				AudioGroup: attrs["AUDIO"],
+			}
+		case strings.HasPrefix(line, "#EXT-X-MEDIA:"):
+			attrs := parseAttributes(strings.TrimPrefix(line, "#EXT-X-MEDIA:"))
+			rendition := hlsRendition{
+				Type:     attrs["TYPE"],
+				GroupID:  attrs["GROUP-ID"],
+				Name:     attrs["NAME"],
+				Language: attrs["LANGUAGE"],
+				Default:  attrs["DEFAULT"] == "YES",
+			}
+			if uri := attrs["URI"]; uri != "" {
+				resolved, err := resolve(base, uri)
+				if err != nil {
+					return nil, err
+				}
+				rendition.URI = resolved
+			}
+			playlist.Renditions[rendition.GroupID] = append(playlist.Renditions[rendition.GroupID], rendition)
+		case strings.HasPrefix(line, "#"):
+			continue
+		default:
+			if pending == nil {
+				continue
+			}
+			resolved, err := resolve(base, line)
+			if err != nil {
+				return nil, err
+			}
+			pending.URI = resolved
+			playlist.Streams = append(playlist.Streams, *pending)
+			pending = nil
+		}
+	}
+	return playlist, scanner.Err()
+}
+
+func parseMediaPlaylist(data []byte, base *neturl.URL) (*mediaPlaylist, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("#EXTM3U")) {
+		return nil, fmt.Errorf("not an m3u8 playlist")
+	}
+	playlist := &mediaPlaylist{}
+
+	var sequence int64
+	var duration time.Duration
+	var key *segmentKey
+	// a playlist without an end is live, and has nothing but the latest few segments
+	ended := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case line == "#EXT-X-ENDLIST" || line == "#EXT-X-PLAYLIST-TYPE:VOD":
+			ended = true
+		case strings.HasPrefix(line, "#EXT-X-MEDIA-SEQUENCE:"):
+			sequence, _ = strconv.ParseInt(strings.TrimPrefix(line, "#EXT-X-MEDIA-SEQUENCE:"), 10, 64)
+		case strings.HasPrefix(line, "#EXTINF:"):
+			value, _, _ := strings.Cut(strings.TrimPrefix(line, "#EXTINF:"), ",")
+			seconds, _ := strconv.ParseFloat(value, 64)
+			duration = time.Duration(seconds * float64(time.Second))
+		case strings.HasPrefix(line, "#EXT-X-MAP:"):
+			attrs := parseAttributes(strings.TrimPrefix(line, "#EXT-X-MAP:"))
+			resolved, err := resolve(base, attrs["URI"])
+			if err != nil {
+				return nil, err
+			}
+			playlist.InitURI = resolved
+		case strings.HasPrefix(line, "#EXT-X-KEY:"):
+			attrs := parseAttributes(strings.TrimPrefix(line, "#EXT-X-KEY:"))
+			switch attrs["METHOD"] {
+			case "NONE":
+				key = nil
+			case "AES-128":
+				resolved, err := resolve(base, attrs["URI"])
+				if err != nil {
+					return nil, err
+				}
+				key = &segmentKey{URI: resolved}
+				if iv := attrs["IV"]; iv != "" {
+					decoded, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(iv, "0x"), "0X"))
+					if err != nil || len(decoded) != 16 {
+						return nil, fmt.Errorf("invalid IV: %s", iv)
+					}
+					key.IV = decoded
+				}
+			default:
+				return nil, fmt.Errorf("unsupported encryption method: %s", attrs["METHOD"])
+			}
+		case strings.HasPrefix(line, "#"):
+			continue
+		default:
+			resolved, err := resolve(base, line)
+			if err != nil {
+				return nil, err
+			}
+			seg := segment{URI: resolved, Duration: duration}
+			if key != nil {
+				segKey := *key
+				segKey.Sequence = sequence
+				seg.Key = &segKey
+			}
+			playlist.Segments = append(playlist.Segments, seg)
+			sequence++
+			duration = 0
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(playlist.Segments) == 0 {
+		return nil, fmt.Errorf("playlist has no segments")
+	}
+	if !ended {
+		return nil, ErrLiveStream
+	}
+	return playlist, nil
+}
+
+// parseAttributes parses attribute lists like `BANDWIDTH=1280000,CODECS="avc1.4d401f,mp4a.40.2"`
+func parseAttributes(s string) map[string]string {
+	attrs := make(map[string]string)
+	for s != "" {
+		name, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			break
+		}
+		name = strings.TrimSpace(name)
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end == -1 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+			rest = strings.TrimPrefix(rest, ",")
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		attrs[name] = value
+		s = rest
+	}
+	return attrs
+}
+
+func resolve(base *neturl.URL, ref string) (string, error) {
+	parsed, err := neturl.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid uri %q: %w", ref, err)
+	}
+	return base.ResolveReference(parsed).String(), nil
+}
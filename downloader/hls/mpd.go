@@ -0,0 +1,320 @@
+package hls
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	neturl "net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type mpdDocument struct {
+	XMLName                   xml.Name    `xml:"MPD"`
+	Type                      string      `xml:"type,attr"`
+	MediaPresentationDuration string      `xml:"mediaPresentationDuration,attr"`
+	BaseURL                   string      `xml:"BaseURL"`
+	Periods                   []mpdPeriod `xml:"Period"`
+}
+
+type mpdPeriod struct {
+	Duration       string             `xml:"duration,attr"`
+	BaseURL        string             `xml:"BaseURL"`
+	AdaptationSets []mpdAdaptationSet `xml:"AdaptationSet"`
+}
+
+type mpdAdaptationSet struct {
+	MimeType        string              `xml:"mimeType,attr"`
+	ContentType     string              `xml:"contentType,attr"`
+	Lang            string              `xml:"lang,attr"`
+	Codecs          string              `xml:"codecs,attr"`
+	BaseURL         string              `xml:"BaseURL"`
+	SegmentTemplate *mpdSegmentTemplate `xml:"SegmentTemplate"`
+	SegmentList     *mpdSegmentList     `xml:"SegmentList"`
+	Representations []mpdRepresentation `xml:"Representation"`
+}
+
+type mpdRepresentation struct {
+	ID              string              `xml:"id,attr"`
+	Bandwidth       int64               `xml:"bandwidth,attr"`
+	Width           int                 `xml:"width,attr"`
+	Height          int                 `xml:"height,attr"`
+	Codecs          string              `xml:"codecs,attr"`
+	MimeType        string              `xml:"mimeType,attr"`
+	BaseURL         string              `xml:"BaseURL"`
+	SegmentTemplate *mpdSegmentTemplate `xml:"SegmentTemplate"`
+	SegmentList     *mpdSegmentList     `xml:"SegmentList"`
+}
+
+type mpdSegmentTemplate struct {
+	Timescale       int64  `xml:"timescale,attr"`
+	Duration        int64  `xml:"duration,attr"`
+	StartNumber     *int64 `xml:"startNumber,attr"`
+	Initialization  string `xml:"initialization,attr"`
+	Media           string `xml:"media,attr"`
+	SegmentTimeline *struct {
+		S []struct {
+			T *int64 `xml:"t,attr"`
+			D int64  `xml:"d,attr"`
+			R int64  `xml:"r,attr"`
+		} `xml:"S"`
+	} `xml:"SegmentTimeline"`
+}
+
+type mpdSegmentList struct {
+	Initialization *struct {
+		SourceURL string `xml:"sourceURL,attr"`
+	} `xml:"Initialization"`
+	SegmentURLs []struct {
+		Media string `xml:"media,attr"`
+	} `xml:"SegmentURL"`
+}
+
+// dashRepresentation is a single downloadable representation with its segments already expanded
+type dashRepresentation struct {
+	ID          string
+	ContentType string // "video" or "audio"
+	Bandwidth   int64
+	Width       int
+	Height      int
+	Codecs      string
+	Lang        string
+	Track       mediaPlaylist
+}
+
+func parseMPD(data []byte, manifestURL *neturl.URL) ([]dashRepresentation, error) {
+	var doc mpdDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse mpd: %w", err)
+	}
+	if doc.Type == "dynamic" {
+		return nil, ErrLiveStream
+	}
+	if len(doc.Periods) == 0 {
+		return nil, fmt.Errorf("mpd has no periods")
+	}
+	// Multi-period manifests are mostly ad insertion, the first period is the content
+	period := doc.Periods[0]
+	periodDuration := parseISODuration(period.Duration)
+	if periodDuration == 0 {
+		periodDuration = parseISODuration(doc.MediaPresentationDuration)
+	}
+
+	base, err := joinBase(manifestURL, doc.BaseURL, period.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var representations []dashRepresentation
+	for _, as := range period.AdaptationSets {
+		asBase, err := joinBase(base, as.BaseURL)
+		if err != nil {
+			return nil, err
+		}
+		for _, rep := range as.Representations {
+			repBase, err := joinBase(asBase, rep.BaseURL)
+			if err != nil {
+				return nil, err
+			}
+			mimeType := firstNonEmpty(rep.MimeType, as.MimeType)
+			contentType := as.ContentType
+			if contentType == "" {
+				contentType, _, _ = strings.Cut(mimeType, "/")
+			}
+			if contentType != "video" && contentType != "audio" {
+				continue // subtitles, thumbnails
+			}
+
+			track, err := expandSegments(rep, as, repBase, periodDuration)
+			if err != nil {
+				return nil, fmt.Errorf("representation %s: %w", rep.ID, err)
+			}
+			representations = append(representations, dashRepresentation{
+				ID:          rep.ID,
+				ContentType: contentType,
+				Bandwidth:   rep.Bandwidth,
+				Width:       rep.Width,
+				Height:      rep.Height,
+				Codecs:      firstNonEmpty(rep.Codecs, as.Codecs),
+				Lang:        as.Lang,
+				Track:       *track,
+			})
+		}
+	}
+	return representations, nil
+}
+
+func expandSegments(rep mpdRepresentation, as mpdAdaptationSet, base *neturl.URL, periodDuration time.Duration) (*mediaPlaylist, error) {
+	if tmpl := firstTemplate(rep.SegmentTemplate, as.SegmentTemplate); tmpl != nil {
+		return expandTemplate(tmpl, rep, base, periodDuration)
+	}
+	if list := firstList(rep.SegmentList, as.SegmentList); list != nil {
+		track := &mediaPlaylist{}
+		if list.Initialization != nil && list.Initialization.SourceURL != "" {
+			init, err := resolve(base, list.Initialization.SourceURL)
+			if err != nil {
+				return nil, err
+			}
+			track.InitURI = init
+		}
+		for _, su := range list.SegmentURLs {
+			uri, err := resolve(base, su.Media)
+			if err != nil {
+				return nil, err
+			}
+			track.Segments = append(track.Segments, segment{URI: uri})
+		}
+		return track, nil
+	}
+	// No segmentation info: the whole representation is a single file at BaseURL
+	return &mediaPlaylist{Segments: []segment{{URI: base.String()}}}, nil
+}
+
+func expandTemplate(tmpl *mpdSegmentTemplate, rep mpdRepresentation, base *neturl.URL, periodDuration time.Duration) (*mediaPlaylist, error) {
+	timescale := tmpl.Timescale
+	if timescale == 0 {
+		timescale = 1
+	}
+	number := int64(1)
+	if tmpl.StartNumber != nil {
+		number = *tmpl.StartNumber
+	}
+
+	track := &mediaPlaylist{}
+	if tmpl.Initialization != "" {
+		init, err := resolve(base, fillTemplate(tmpl.Initialization, rep, 0, 0))
+		if err != nil {
+			return nil, err
+		}
+		track.InitURI = init
+	}
+
+	addSegment := func(number, t, d int64) error {
+		uri, err := resolve(base, fillTemplate(tmpl.Media, rep, number, t))
+		if err != nil {
+			return err
+		}
+		track.Segments = append(track.Segments, segment{
+			URI:      uri,
+			Duration: time.Duration(float64(d) / float64(timescale) * float64(time.Second)),
+		})
+		return nil
+	}
+
+	if tmpl.SegmentTimeline != nil {
+		var t int64
+		for _, s := range tmpl.SegmentTimeline.S {
+			if s.T != nil {
+				t = *s.T
+			}
+			for i := int64(0); i <= s.R; i++ {
+				if err := addSegment(number, t, s.D); err != nil {
+					return nil, err
+				}
+				number++
+				t += s.D
+			}
+		}
+		return track, nil
+	}
+
+	if tmpl.Duration == 0 || periodDuration == 0 {
+		return nil, fmt.Errorf("segment template has neither timeline nor duration")
+	}
+	segmentDuration := float64(tmpl.Duration) / float64(timescale)
+	count := int64(math.Ceil(periodDuration.Seconds() / segmentDuration))
+	for i := int64(0); i < count; i++ {
+		if err := addSegment(number+i, i*tmpl.Duration, tmpl.Duration); err != nil {
+			return nil, err
+		}
+	}
+	return track, nil
+}
+
+var templateIdentifierRe = regexp.MustCompile(`\$(RepresentationID|Number|Bandwidth|Time)(%0(\d+)d)?\$`)
+
+// fillTemplate substitutes $RepresentationID$, $Number$, $Bandwidth$ and $Time$, including the %05d width form
+func fillTemplate(tmpl string, rep mpdRepresentation, number, t int64) string {
+	filled := templateIdentifierRe.ReplaceAllStringFunc(tmpl, func(match string) string {
+		parts := templateIdentifierRe.FindStringSubmatch(match)
+		var value string
+		switch parts[1] {
+		case "RepresentationID":
+			return rep.ID
+		case "Number":
+			value = strconv.FormatInt(number, 10)
+		case "Bandwidth":
+			value = strconv.FormatInt(rep.Bandwidth, 10)
+		case "Time":
+			value = strconv.FormatInt(t, 10)
+		}
+		if width, err := strconv.Atoi(parts[3]); err == nil && len(value) < width {
+			value = strings.Repeat("0", width-len(value)) + value
+		}
+		return value
+	})
+	return strings.ReplaceAll(filled, "$$", "$")
+}
+
+var isoDurationRe = regexp.MustCompile(`^P(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// parseISODuration parses durations like "PT1H2M3.5S". Zero is returned for anything it can't parse.
+func parseISODuration(s string) time.Duration {
+	parts := isoDurationRe.FindStringSubmatch(strings.TrimSpace(s))
+	if parts == nil {
+		return 0
+	}
+	var total float64
+	for i, unit := range []float64{24 * 3600, 3600, 60, 1} {
+		if parts[i+1] == "" {
+			continue
+		}
+		value, _ := strconv.ParseFloat(parts[i+1], 64)
+		total += value * unit
+	}
+	return time.Duration(total * float64(time.Second))
+}
+
+func joinBase(base *neturl.URL, refs ...string) (*neturl.URL, error) {
+	for _, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+		parsed, err := neturl.Parse(ref)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BaseURL %q: %w", ref, err)
+		}
+		base = base.ResolveReference(parsed)
+	}
+	return base, nil
+}
+
+func firstTemplate(templates ...*mpdSegmentTemplate) *mpdSegmentTemplate {
+	for _, t := range templates {
+		if t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+func firstList(lists ...*mpdSegmentList) *mpdSegmentList {
+	for _, l := range lists {
+		if l != nil {
+			return l
+		}
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
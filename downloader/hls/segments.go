@@ -0,0 +1,244 @@
+package hls
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// segmentFetcher downloads tracks segment by segment, several segments at a time
+type segmentFetcher struct {
+	client      *http.Client
+	concurrency int
+	attempts    int
+	log         *slog.Logger
+}
+
+func newSegmentFetcher(client *http.Client, concurrency int, logger *slog.Logger) *segmentFetcher {
+	return &segmentFetcher{
+		client:      client,
+		concurrency: concurrency,
+		attempts:    3,
+		log:         logger,
+	}
+}
+
+// keyCache holds AES-128 keys of a single download. Every key is fetched once, by the first segment
+// that needs it, while segments of other keys go on.
+type keyCache struct {
+	mutex sync.Mutex
+	keys  map[string]*cachedKey
+}
+
+type cachedKey struct {
+	once sync.Once
+	key  []byte
+	err  error
+}
+
+func newKeyCache() *keyCache {
+	return &keyCache{keys: make(map[string]*cachedKey)}
+}
+
+func (c *keyCache) get(uri string, fetch func() ([]byte, error)) ([]byte, error) {
+	c.mutex.Lock()
+	cached, ok := c.keys[uri]
+	if !ok {
+		cached = &cachedKey{}
+		c.keys[uri] = cached
+	}
+	c.mutex.Unlock()
+
+	cached.once.Do(func() { cached.key, cached.err = fetch() })
+	return cached.key, cached.err
+}
+
+// fetchTrack downloads (and decrypts) every segment of a track and joins them, in order, into destPath
+func (f *segmentFetcher) fetchTrack(ctx context.Context, track *mediaPlaylist, keys *keyCache, destPath string) error {
+	partsDir := destPath + ".parts"
+	if err := os.MkdirAll(partsDir, 0o755); err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(partsDir) }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	indexes := make(chan int)
+	errs := make(chan error, f.concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < f.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				seg := track.Segments[idx]
+				if err := f.fetchSegment(ctx, seg, keys, partPath(partsDir, idx)); err != nil {
+					errs <- fmt.Errorf("segment %d (%s): %w", idx, seg.URI, err)
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+loop:
+	for idx := range track.Segments {
+		select {
+		case indexes <- idx:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return f.join(ctx, track, partsDir, destPath)
+}
+
+func (f *segmentFetcher) join(ctx context.Context, track *mediaPlaylist, partsDir string, destPath string) error {
+	dest, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dest.Close() }()
+
+	if track.InitURI != "" {
+		data, err := f.get(ctx, track.InitURI)
+		if err != nil {
+			return fmt.Errorf("failed to fetch initialization segment: %w", err)
+		}
+		if _, err := dest.Write(data); err != nil {
+			return err
+		}
+	}
+
+	for idx := range track.Segments {
+		part, err := os.Open(partPath(partsDir, idx))
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(dest, part)
+		_ = part.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return dest.Close()
+}
+
+func (f *segmentFetcher) fetchSegment(ctx context.Context, seg segment, keys *keyCache, destPath string) error {
+	data, err := f.get(ctx, seg.URI)
+	if err != nil {
+		return err
+	}
+	if seg.Key != nil {
+		key, err := keys.get(seg.Key.URI, func() ([]byte, error) { return f.key(ctx, seg.Key.URI) })
+		if err != nil {
+			return fmt.Errorf("failed to fetch key: %w", err)
+		}
+		if data, err = decryptSegment(data, key, segmentIV(seg.Key)); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(destPath, data, 0o644)
+}
+
+func (f *segmentFetcher) key(ctx context.Context, uri string) ([]byte, error) {
+	key, err := f.get(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 16 {
+		return nil, fmt.Errorf("expected 16 byte AES-128 key, got %d bytes", len(key))
+	}
+	return key, nil
+}
+
+// get fetches the whole body, retrying failed attempts with a linear backoff
+func (f *segmentFetcher) get(ctx context.Context, uri string) ([]byte, error) {
+	var lastErr error
+	for attempt := 1; attempt <= f.attempts; attempt++ {
+		data, err := f.getOnce(ctx, uri)
+		if err == nil {
+			return data, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		f.log.Debug("failed to fetch, retrying", slog.String("uri", uri), slog.Int("attempt", attempt), slog.Any("error", err))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Duration(attempt) * 500 * time.Millisecond):
+		}
+	}
+	return nil, lastErr
+}
+
+func (f *segmentFetcher) getOnce(ctx context.Context, uri string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// segmentIV is the explicit IV, or the media sequence number as a big-endian 128-bit integer
+func segmentIV(key *segmentKey) []byte {
+	if key.IV != nil {
+		return key.IV
+	}
+	iv := make([]byte, aes.BlockSize)
+	binary.BigEndian.PutUint64(iv[8:], uint64(key.Sequence))
+	return iv
+}
+
+// decryptSegment decrypts AES-128-CBC with PKCS7 padding, as mandated by HLS spec
+func decryptSegment(data []byte, key []byte, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("encrypted segment size %d is not a multiple of block size", len(data))
+	}
+	decrypted := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, data)
+
+	padding := int(decrypted[len(decrypted)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(decrypted) {
+		return nil, fmt.Errorf("invalid padding, wrong key?")
+	}
+	return decrypted[:len(decrypted)-padding], nil
+}
+
+func partPath(partsDir string, idx int) string {
+	return filepath.Join(partsDir, fmt.Sprintf("%06d", idx))
+}
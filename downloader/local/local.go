@@ -0,0 +1,216 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dir01/mediary/service"
+)
+
+var ErrOutsideOfRoots = fmt.Errorf("path is outside of allowed roots")
+
+// New creates a downloader for file:// URLs.
+// Only files under one of roots are accessible, everything else is rejected.
+func New(roots []string, logger *slog.Logger) (*Downloader, error) {
+	resolvedRoots := make([]string, 0, len(roots))
+	for _, root := range roots {
+		resolved, err := filepath.EvalSymlinks(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve root %s: %w", root, err)
+		}
+		resolved, err = filepath.Abs(resolved)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve root %s: %w", root, err)
+		}
+		resolvedRoots = append(resolvedRoots, resolved)
+	}
+	d := &Downloader{roots: resolvedRoots, log: logger}
+	var _ service.Downloader = d
+	var _ service.Describer = d
+	var _ service.Streamer = d
+	return d, nil
+}
+
+// Downloader serves files that are already on local filesystem (e.g. NAS mount).
+// Nothing is copied: Download returns the original paths.
+type Downloader struct {
+	roots []string
+	log   *slog.Logger
+}
+
+func (d *Downloader) Describe() service.DownloaderInfo {
+	patterns := make([]string, 0, len(d.roots))
+	for _, root := range d.roots {
+		patterns = append(patterns, (&neturl.URL{Scheme: "file", Path: filepath.ToSlash(root)}).String()+"/*")
+	}
+	return service.DownloaderInfo{
+		Name:                  "local",
+		Schemes:               []string{"file"},
+		Patterns:              patterns,
+		AllowMultipleVariants: true,
+	}
+}
+
+func (d *Downloader) AcceptsURL(url string) bool {
+	if !strings.HasPrefix(url, "file://") {
+		return false
+	}
+	if _, err := d.resolve(url); err != nil {
+		d.log.Debug("not accepting url", slog.String("url", url), slog.Any("error", err))
+		return false
+	}
+	return true
+}
+
+// GetMetadata lists files under the directory, recursively, as variants.
+// When URL points to a single file, it is the only variant.
+func (d *Downloader) GetMetadata(ctx context.Context, url string) (*service.Metadata, error) {
+	p, err := d.resolve(url)
+	if err != nil {
+		return nil, err
+	}
+	baseDir, err := d.baseDir(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var variants []service.VariantMetadata
+	err = filepath.WalkDir(p, func(fp string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(baseDir, fp)
+		if err != nil {
+			return err
+		}
+		size := info.Size()
+		variants = append(variants, service.VariantMetadata{ID: filepath.ToSlash(rel), LenBytes: &size})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", p, err)
+	}
+	sort.Slice(variants, func(i, j int) bool {
+		return variants[i].ID < variants[j].ID
+	})
+
+	return &service.Metadata{
+		URL:                   url,
+		Name:                  filepath.Base(p),
+		Variants:              variants,
+		AllowMultipleVariants: true,
+		DownloaderName:        "local",
+	}, nil
+}
+
+// Download does not download anything, it just maps variants to their location on disk
+func (d *Downloader) Download(ctx context.Context, url string, filepaths []string) (filepathsMap map[string]string, err error) {
+	p, err := d.resolve(url)
+	if err != nil {
+		return nil, err
+	}
+	baseDir, err := d.baseDir(p)
+	if err != nil {
+		return nil, err
+	}
+
+	filepathsMap = make(map[string]string, len(filepaths))
+	for _, variant := range filepaths {
+		if !filepath.IsLocal(filepath.FromSlash(variant)) {
+			return nil, fmt.Errorf("%w: %s", ErrOutsideOfRoots, variant)
+		}
+		// a URL of a single file gives access to that file only, not to its siblings
+		if baseDir != p && filepath.FromSlash(variant) != filepath.Base(p) {
+			return nil, fmt.Errorf("%w: %s", service.ErrVariantNotFound, variant)
+		}
+		fp, err := d.resolvePath(filepath.Join(baseDir, filepath.FromSlash(variant)))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", service.ErrVariantNotFound, variant)
+		} else if err != nil {
+			return nil, err
+		}
+		if stat, err := os.Stat(fp); err != nil {
+			return nil, fmt.Errorf("%w: %s", service.ErrVariantNotFound, variant)
+		} else if !stat.Mode().IsRegular() {
+			return nil, fmt.Errorf("%s is not a regular file", variant)
+		}
+		filepathsMap[variant] = fp
+	}
+	d.log.Debug("filepaths map", slog.String("url", url), slog.Any("filepathsMap", filepathsMap))
+
+	return filepathsMap, nil
+}
+
+func (d *Downloader) Stream(ctx context.Context, url string, variant string) (*service.VariantStream, error) {
+	filepathsMap, err := d.Download(ctx, url, []string{variant})
+	if err != nil {
+		return nil, err
+	}
+	fp := filepathsMap[variant]
+	file, err := os.Open(fp)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &service.VariantStream{ReadSeekCloser: file, Name: filepath.Base(fp), Size: stat.Size()}, nil
+}
+
+// resolve converts file:// URL into absolute path, making sure it is located under one of the roots
+func (d *Downloader) resolve(url string) (string, error) {
+	parsed, err := neturl.Parse(url)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse url: %w", err)
+	}
+	if parsed.Scheme != "file" || (parsed.Host != "" && parsed.Host != "localhost") {
+		return "", fmt.Errorf("not a local file url: %s", url)
+	}
+	return d.resolvePath(filepath.FromSlash(parsed.Path))
+}
+
+// resolvePath follows symlinks, so that a link can not be used to escape the roots
+func (d *Downloader) resolvePath(p string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return "", err
+	}
+	for _, root := range d.roots {
+		if rel, err := filepath.Rel(root, resolved); err == nil && (rel == "." || filepath.IsLocal(rel)) {
+			return resolved, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrOutsideOfRoots, p)
+}
+
+// baseDir is the directory variant IDs are relative to
+func (d *Downloader) baseDir(p string) (string, error) {
+	stat, err := os.Stat(p)
+	if err != nil {
+		return "", err
+	}
+	if stat.IsDir() {
+		return p, nil
+	}
+	return filepath.Dir(p), nil
+}
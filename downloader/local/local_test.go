@@ -0,0 +1,134 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dir01/mediary/service"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func setupRoot(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"book/cd1/01.mp3": "chapter one",
+		"book/cd1/02.mp3": "chapter two!",
+		"book/cover.jpg":  "jpeg",
+	} {
+		fp := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fp), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(fp, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return root
+}
+
+func TestGetMetadata_Directory(t *testing.T) {
+	root := setupRoot(t)
+	d, err := New([]string{root}, testLogger)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	url := "file://" + filepath.ToSlash(filepath.Join(root, "book"))
+	if !d.AcceptsURL(url) {
+		t.Fatalf("expected %s to be accepted", url)
+	}
+	metadata, err := d.GetMetadata(context.Background(), url)
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+
+	var ids []string
+	for _, v := range metadata.Variants {
+		ids = append(ids, v.ID)
+	}
+	if want := []string{"cd1/01.mp3", "cd1/02.mp3", "cover.jpg"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("variants = %v, want %v", ids, want)
+	}
+	if *metadata.Variants[1].LenBytes != int64(len("chapter two!")) {
+		t.Errorf("length = %d", *metadata.Variants[1].LenBytes)
+	}
+	if !metadata.AllowMultipleVariants || metadata.Name != "book" {
+		t.Errorf("unexpected metadata: %+v", metadata)
+	}
+}
+
+func TestDownload_ReturnsOriginalPaths(t *testing.T) {
+	root := setupRoot(t)
+	d, err := New([]string{root}, testLogger)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	url := "file://" + filepath.ToSlash(filepath.Join(root, "book"))
+	filepathsMap, err := d.Download(context.Background(), url, []string{"cd1/02.mp3"})
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	resolvedRoot, _ := filepath.EvalSymlinks(root)
+	if want := filepath.Join(resolvedRoot, "book", "cd1", "02.mp3"); filepathsMap["cd1/02.mp3"] != want {
+		t.Errorf("path = %q, want %q", filepathsMap["cd1/02.mp3"], want)
+	}
+}
+
+func TestDownload_VariantNotFound(t *testing.T) {
+	root := setupRoot(t)
+	d, err := New([]string{root}, testLogger)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	dirURL := "file://" + filepath.ToSlash(filepath.Join(root, "book"))
+	fileURL := "file://" + filepath.ToSlash(filepath.Join(root, "book", "cd1", "01.mp3"))
+	for _, tc := range []struct{ url, variant string }{
+		{dirURL, "cd1/03.mp3"},
+		// siblings of a single file are not accessible through its URL
+		{fileURL, "02.mp3"},
+	} {
+		if _, err := d.Download(context.Background(), tc.url, []string{tc.variant}); !errors.Is(err, service.ErrVariantNotFound) {
+			t.Errorf("Download(%q): expected ErrVariantNotFound, got %v", tc.variant, err)
+		}
+	}
+	if _, err := d.Download(context.Background(), fileURL, []string{"01.mp3"}); err != nil {
+		t.Errorf("Download of the file itself failed: %v", err)
+	}
+}
+
+func TestOutsideOfRootsIsRejected(t *testing.T) {
+	root := setupRoot(t)
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "book", "link")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	d, err := New([]string{filepath.Join(root, "book")}, testLogger)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if d.AcceptsURL("file://" + filepath.ToSlash(outside)) {
+		t.Error("directory outside of roots must not be accepted")
+	}
+
+	url := "file://" + filepath.ToSlash(filepath.Join(root, "book"))
+	for _, variant := range []string{"../book/cover.jpg", "link/secret.txt"} {
+		if _, err := d.Download(context.Background(), url, []string{variant}); !errors.Is(err, ErrOutsideOfRoots) {
+			t.Errorf("Download(%q): expected ErrOutsideOfRoots, got %v", variant, err)
+		}
+	}
+}
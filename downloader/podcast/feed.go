@@ -0,0 +1,173 @@
+package podcast
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// episode is a single enclosure of a feed, regardless of whether the feed is RSS or Atom
+type episode struct {
+	Title     string
+	URL       string
+	MimeType  string
+	LenBytes  *int64
+	Duration  time.Duration
+	Published time.Time
+}
+
+type feed struct {
+	Title    string
+	Episodes []episode
+}
+
+type rssDocument struct {
+	XMLName xml.Name `xml:"rss"`
+	Channel struct {
+		Title string `xml:"title"`
+		Items []struct {
+			Title     string `xml:"title"`
+			PubDate   string `xml:"pubDate"`
+			Duration  string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+			Enclosure *struct {
+				URL    string `xml:"url,attr"`
+				Length string `xml:"length,attr"`
+				Type   string `xml:"type,attr"`
+			} `xml:"enclosure"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+type atomDocument struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string   `xml:"title"`
+	Entries []struct {
+		Title     string `xml:"title"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+		Duration  string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+		Links     []struct {
+			Rel    string `xml:"rel,attr"`
+			Href   string `xml:"href,attr"`
+			Length string `xml:"length,attr"`
+			Type   string `xml:"type,attr"`
+		} `xml:"link"`
+	} `xml:"entry"`
+}
+
+// parseFeed parses RSS 2.0 or Atom feed. Items without enclosures are skipped.
+func parseFeed(data []byte) (*feed, error) {
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("not an xml document: %w", err)
+	}
+
+	switch root.XMLName.Local {
+	case "rss":
+		return parseRSS(data)
+	case "feed":
+		return parseAtom(data)
+	default:
+		return nil, fmt.Errorf("unexpected root element: %s", root.XMLName.Local)
+	}
+}
+
+func parseRSS(data []byte) (*feed, error) {
+	var doc rssDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse rss: %w", err)
+	}
+	f := &feed{Title: strings.TrimSpace(doc.Channel.Title)}
+	for _, item := range doc.Channel.Items {
+		if item.Enclosure == nil || item.Enclosure.URL == "" {
+			continue
+		}
+		f.Episodes = append(f.Episodes, episode{
+			Title:     strings.TrimSpace(item.Title),
+			URL:       item.Enclosure.URL,
+			MimeType:  item.Enclosure.Type,
+			LenBytes:  parseLength(item.Enclosure.Length),
+			Duration:  parseItunesDuration(item.Duration),
+			Published: parseDate(item.PubDate),
+		})
+	}
+	return f, nil
+}
+
+func parseAtom(data []byte) (*feed, error) {
+	var doc atomDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse atom: %w", err)
+	}
+	f := &feed{Title: strings.TrimSpace(doc.Title)}
+	for _, entry := range doc.Entries {
+		published := entry.Published
+		if published == "" {
+			published = entry.Updated
+		}
+		for _, link := range entry.Links {
+			if link.Rel != "enclosure" || link.Href == "" {
+				continue
+			}
+			f.Episodes = append(f.Episodes, episode{
+				Title:     strings.TrimSpace(entry.Title),
+				URL:       link.Href,
+				MimeType:  link.Type,
+				LenBytes:  parseLength(link.Length),
+				Duration:  parseItunesDuration(entry.Duration),
+				Published: parseDate(published),
+			})
+			break
+		}
+	}
+	return f, nil
+}
+
+func parseLength(s string) *int64 {
+	length, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || length <= 0 {
+		return nil
+	}
+	return &length
+}
+
+// parseItunesDuration understands "HH:MM:SS", "MM:SS" and plain seconds.
+// Zero is returned for anything else.
+func parseItunesDuration(s string) time.Duration {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	var total float64
+	for _, part := range strings.Split(s, ":") {
+		value, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0
+		}
+		total = total*60 + value
+	}
+	return time.Duration(total * float64(time.Second))
+}
+
+var dateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC3339,
+}
+
+func parseDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
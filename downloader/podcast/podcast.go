@@ -0,0 +1,313 @@
+package podcast
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samber/oops"
+
+	"github.com/dir01/mediary/service"
+)
+
+// maxFeedBytes protects from accidentally reading a huge media file instead of a feed
+const maxFeedBytes = 20 * 1024 * 1024
+
+// feedTTL is how long a fetched feed is reused, so that a job doesn't fetch the feed its metadata was just read from
+const feedTTL = 10 * time.Minute
+
+func New(dataDir string, logger *slog.Logger) (*Downloader, error) {
+	d := &Downloader{dataDir: dataDir, httpClient: http.DefaultClient, log: logger, feeds: make(map[string]cachedFeed)}
+	var _ service.Downloader = d
+	var _ service.Describer = d
+	return d, nil
+}
+
+// Downloader treats episodes of an RSS/Atom podcast feed as variants
+type Downloader struct {
+	dataDir    string
+	httpClient *http.Client
+	log        *slog.Logger
+
+	feedsMutex sync.Mutex
+	feeds      map[string]cachedFeed
+}
+
+type cachedFeed struct {
+	feed      *feed
+	fetchedAt time.Time
+}
+
+func (d *Downloader) Describe() service.DownloaderInfo {
+	return service.DownloaderInfo{
+		Name:    "podcast",
+		Schemes: []string{"http", "https"},
+		Patterns: []string{
+			"*.rss", "*.xml", "*.atom", "*/feed", "*/rss", "https://feeds.*", "https://rss.*",
+			"any RSS/Atom feed with enclosures, when forced",
+		},
+		AllowMultipleVariants: true,
+	}
+}
+
+// AcceptsURL only looks at the URL, since it is asked about every URL, many times per request.
+// Feeds at URLs that don't look like ones are fetched when the downloader is forced.
+func (d *Downloader) AcceptsURL(url string) bool {
+	parsed, err := neturl.Parse(url)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if strings.HasPrefix(host, "feeds.") || strings.HasPrefix(host, "feed.") || strings.HasPrefix(host, "rss.") {
+		return true
+	}
+	p := strings.ToLower(strings.TrimSuffix(parsed.Path, "/"))
+	switch path.Ext(p) {
+	case ".rss", ".xml", ".atom":
+		return true
+	}
+	switch path.Base(p) {
+	case "feed", "rss", "atom":
+		return true
+	}
+	return false
+}
+
+// GetMetadata lists episodes from the oldest to the newest, so that a season can be concatenated as is
+func (d *Downloader) GetMetadata(ctx context.Context, url string) (*service.Metadata, error) {
+	f, err := d.fetchFeed(ctx, url)
+	if err != nil {
+		return nil, oops.With("url", url).Wrapf(err, "failed to fetch feed")
+	}
+	if len(f.Episodes) == 0 {
+		return nil, oops.With("url", url).Errorf("feed has no episodes with enclosures")
+	}
+
+	episodes := episodesByID(f)
+	variants := make([]service.VariantMetadata, 0, len(episodes))
+	for _, ep := range sortedEpisodes(episodes) {
+		variants = append(variants, service.VariantMetadata{
+			ID:       ep.id,
+			LenBytes: ep.LenBytes,
+			Title:    ep.Title,
+			Duration: ep.Duration,
+		})
+	}
+
+	return &service.Metadata{
+		URL:                   url,
+		Name:                  f.Title,
+		Variants:              variants,
+		AllowMultipleVariants: true,
+		DownloaderName:        "podcast",
+	}, nil
+}
+
+func (d *Downloader) Download(ctx context.Context, url string, filepaths []string) (filepathsMap map[string]string, err error) {
+	f, err := d.fetchFeed(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	episodes := episodesByID(f)
+
+	hash := md5.Sum([]byte(url))
+	destDir := filepath.Join(d.dataDir, "podcast_"+hex.EncodeToString(hash[:]))
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create download dir: %w", err)
+	}
+
+	filepathsMap = make(map[string]string, len(filepaths))
+	for _, variant := range filepaths {
+		ep, ok := episodes[variant]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", service.ErrVariantNotFound, variant)
+		}
+		destPath := filepath.Join(destDir, variant)
+		d.log.Debug("downloading episode", slog.String("url", url), slog.String("variant", variant), slog.String("enclosure", ep.URL))
+		if err := d.downloadFile(ctx, ep.URL, destPath); err != nil {
+			return nil, oops.With("url", url, "variant", variant, "enclosure", ep.URL).Wrapf(err, "failed to download episode")
+		}
+		filepathsMap[variant] = destPath
+	}
+	return filepathsMap, nil
+}
+
+// fetchFeed returns the feed fetched within feedTTL, or fetches it again
+func (d *Downloader) fetchFeed(ctx context.Context, url string) (*feed, error) {
+	d.feedsMutex.Lock()
+	cached, ok := d.feeds[url]
+	d.feedsMutex.Unlock()
+	if ok && time.Since(cached.fetchedAt) < feedTTL {
+		return cached.feed, nil
+	}
+
+	f, err := d.downloadFeed(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	d.feedsMutex.Lock()
+	defer d.feedsMutex.Unlock()
+	for u, c := range d.feeds {
+		if time.Since(c.fetchedAt) >= feedTTL {
+			delete(d.feeds, u)
+		}
+	}
+	d.feeds[url] = cachedFeed{feed: f, fetchedAt: time.Now()}
+	return f, nil
+}
+
+func (d *Downloader) downloadFeed(ctx context.Context, url string) (*feed, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); strings.HasPrefix(contentType, "audio/") || strings.HasPrefix(contentType, "video/") {
+		return nil, fmt.Errorf("unexpected content type: %s", contentType)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes))
+	if err != nil {
+		return nil, err
+	}
+	return parseFeed(data)
+}
+
+func (d *Downloader) downloadFile(ctx context.Context, url string, destPath string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	file, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+type identifiedEpisode struct {
+	episode
+	id string
+}
+
+// episodesByID assigns every episode a readable ID like "2021-03-04 Episode title.mp3".
+// It has to be deterministic: Download figures out enclosure URLs by re-fetching the feed, once it is not cached.
+func episodesByID(f *feed) map[string]identifiedEpisode {
+	episodes := make(map[string]identifiedEpisode, len(f.Episodes))
+	for _, ep := range f.Episodes {
+		base := sanitize(ep.Title)
+		if base == "" {
+			base = sanitize(strings.TrimSuffix(path.Base(enclosurePath(ep.URL)), path.Ext(enclosurePath(ep.URL))))
+		}
+		if !ep.Published.IsZero() {
+			base = ep.Published.UTC().Format("2006-01-02") + " " + base
+		}
+		ext := extension(ep)
+
+		id := base + ext
+		for i := 2; ; i++ {
+			if _, exists := episodes[id]; !exists {
+				break
+			}
+			id = fmt.Sprintf("%s (%d)%s", base, i, ext)
+		}
+		episodes[id] = identifiedEpisode{episode: ep, id: id}
+	}
+	return episodes
+}
+
+func sortedEpisodes(episodes map[string]identifiedEpisode) []identifiedEpisode {
+	sorted := make([]identifiedEpisode, 0, len(episodes))
+	for _, ep := range episodes {
+		sorted = append(sorted, ep)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].Published.Equal(sorted[j].Published) {
+			return sorted[i].Published.Before(sorted[j].Published)
+		}
+		return sorted[i].id < sorted[j].id
+	})
+	return sorted
+}
+
+// enclosureExtensions covers common enclosure types, for which mime package would pick an odd extension
+var enclosureExtensions = map[string]string{
+	"audio/mpeg":  ".mp3",
+	"audio/mp3":   ".mp3",
+	"audio/mp4":   ".m4a",
+	"audio/x-m4a": ".m4a",
+	"audio/aac":   ".aac",
+	"audio/ogg":   ".ogg",
+	"audio/opus":  ".opus",
+	"video/mp4":   ".mp4",
+}
+
+func extension(ep episode) string {
+	if ext := path.Ext(enclosurePath(ep.URL)); ext != "" && len(ext) <= 5 {
+		return strings.ToLower(ext)
+	}
+	if ext, ok := enclosureExtensions[ep.MimeType]; ok {
+		return ext
+	}
+	if exts, err := mime.ExtensionsByType(ep.MimeType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ".mp3"
+}
+
+func enclosurePath(url string) string {
+	if parsed, err := neturl.Parse(url); err == nil {
+		return parsed.Path
+	}
+	return url
+}
+
+// sanitize makes a title usable as a file name
+func sanitize(title string) string {
+	title = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '-'
+		}
+		if r < 0x20 {
+			return -1
+		}
+		return r
+	}, title)
+	return strings.TrimSpace(title)
+}
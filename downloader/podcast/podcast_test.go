@@ -0,0 +1,216 @@
+package podcast
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+const rssFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+  <channel>
+    <title>Backlog Radio</title>
+    <item>
+      <title>Episode 2: The Sequel</title>
+      <pubDate>Wed, 08 Jan 2020 10:00:00 +0000</pubDate>
+      <itunes:duration>1:02:03</itunes:duration>
+      <enclosure url="{{server}}/media/ep2.mp3" length="23" type="audio/mpeg"/>
+    </item>
+    <item>
+      <title>Show notes only, no audio</title>
+      <pubDate>Tue, 07 Jan 2020 10:00:00 +0000</pubDate>
+    </item>
+    <item>
+      <title>Episode 1: Pilot</title>
+      <pubDate>Wed, 01 Jan 2020 10:00:00 +0000</pubDate>
+      <itunes:duration>45:30</itunes:duration>
+      <enclosure url="{{server}}/media/ep1?token=abc" length="22" type="audio/mpeg"/>
+    </item>
+  </channel>
+</rss>`
+
+const atomFixture = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Atom Cast</title>
+  <entry>
+    <title>First</title>
+    <published>2021-05-01T12:00:00Z</published>
+    <link rel="alternate" href="https://example.com/first"/>
+    <link rel="enclosure" href="https://example.com/first.m4a" length="100" type="audio/mp4"/>
+  </entry>
+</feed>`
+
+func serveFeed(t *testing.T) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feed.xml":
+			w.Header().Set("Content-Type", "application/rss+xml")
+			_, _ = io.WriteString(w, strings.ReplaceAll(rssFixture, "{{server}}", server.URL))
+		case "/media/ep1":
+			w.Header().Set("Content-Type", "audio/mpeg")
+			_, _ = io.WriteString(w, "episode one audio data")
+		case "/media/ep2.mp3":
+			w.Header().Set("Content-Type", "audio/mpeg")
+			_, _ = io.WriteString(w, "episode two audio data!")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return server
+}
+
+func TestGetMetadataAndDownload(t *testing.T) {
+	server := serveFeed(t)
+	defer server.Close()
+
+	d, err := New(t.TempDir(), testLogger)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	url := server.URL + "/feed.xml"
+	if !d.AcceptsURL(url) {
+		t.Fatalf("expected feed to be accepted")
+	}
+	if d.AcceptsURL(server.URL + "/media/ep1") {
+		t.Errorf("media file must not be accepted as a feed")
+	}
+	fetches := 0
+	d.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/feed.xml" {
+			fetches++
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+
+	metadata, err := d.GetMetadata(context.Background(), url)
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if metadata.Name != "Backlog Radio" || !metadata.AllowMultipleVariants {
+		t.Errorf("unexpected metadata: %+v", metadata)
+	}
+
+	var ids, titles []string
+	for _, v := range metadata.Variants {
+		ids = append(ids, v.ID)
+		titles = append(titles, v.Title)
+	}
+	// oldest first, regardless of order in the feed
+	if want := []string{"2020-01-01 Episode 1- Pilot.mp3", "2020-01-08 Episode 2- The Sequel.mp3"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("ids = %q, want %q", ids, want)
+	}
+	if want := []string{"Episode 1: Pilot", "Episode 2: The Sequel"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("titles = %q, want %q", titles, want)
+	}
+	if metadata.Variants[0].Duration != 45*time.Minute+30*time.Second {
+		t.Errorf("duration = %v", metadata.Variants[0].Duration)
+	}
+	if *metadata.Variants[1].LenBytes != 23 {
+		t.Errorf("length = %d", *metadata.Variants[1].LenBytes)
+	}
+
+	filepathsMap, err := d.Download(context.Background(), url, []string{ids[0]})
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	data, err := os.ReadFile(filepathsMap[ids[0]])
+	if err != nil {
+		t.Fatalf("failed to read downloaded episode: %v", err)
+	}
+	if string(data) != "episode one audio data" {
+		t.Errorf("downloaded content = %q", data)
+	}
+	if fetches != 1 {
+		t.Errorf("feed was fetched %d times, want once", fetches)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestAcceptsURL(t *testing.T) {
+	d, err := New(t.TempDir(), testLogger)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	// nothing is fetched to tell
+	d.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		t.Errorf("unexpected request to %s", req.URL)
+		return nil, http.ErrNotSupported
+	})}
+	for url, want := range map[string]bool{
+		"https://feeds.megaphone.fm/show":             true,
+		"https://rss.art19.com/show":                  true,
+		"https://example.com/podcast.rss":             true,
+		"https://example.com/feed.xml?token=abc":      true,
+		"https://example.com/blog/feed/":              true,
+		"https://anchor.fm/s/1234/podcast/rss":        true,
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ": false,
+		"https://cdn.example.com/live/master.m3u8":    false,
+		"https://example.com/episode.mp3":             false,
+		"s3://bucket/feed.xml":                        false,
+	} {
+		if got := d.AcceptsURL(url); got != want {
+			t.Errorf("AcceptsURL(%s) = %v, want %v", url, got, want)
+		}
+	}
+}
+
+func TestGetMetadata_NotAFeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = io.WriteString(w, "<html><body>not a feed</body></html>")
+	}))
+	defer server.Close()
+
+	d, err := New(t.TempDir(), testLogger)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if _, err := d.GetMetadata(context.Background(), server.URL+"/show"); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestParseFeed_Atom(t *testing.T) {
+	f, err := parseFeed([]byte(atomFixture))
+	if err != nil {
+		t.Fatalf("parseFeed failed: %v", err)
+	}
+	if f.Title != "Atom Cast" || len(f.Episodes) != 1 {
+		t.Fatalf("unexpected feed: %+v", f)
+	}
+	ep := f.Episodes[0]
+	if ep.URL != "https://example.com/first.m4a" || *ep.LenBytes != 100 || ep.Published.Year() != 2021 {
+		t.Errorf("unexpected episode: %+v", ep)
+	}
+}
+
+func TestParseItunesDuration(t *testing.T) {
+	for input, want := range map[string]time.Duration{
+		"1:02:03": time.Hour + 2*time.Minute + 3*time.Second,
+		"45:30":   45*time.Minute + 30*time.Second,
+		"3600":    time.Hour,
+		"":        0,
+		"soon":    0,
+	} {
+		if got := parseItunesDuration(input); got != want {
+			t.Errorf("parseItunesDuration(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
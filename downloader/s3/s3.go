@@ -0,0 +1,539 @@
+package s3
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/samber/oops"
+
+	"github.com/dir01/mediary/service"
+)
+
+const (
+	defaultPartSize    = 8 * 1024 * 1024
+	defaultConcurrency = 4
+	partAttempts       = 3
+)
+
+// Config tweaks how the downloader talks to S3.
+// Zero value means AWS defaults: credentials and region come from the standard chain.
+type Config struct {
+	// Endpoint overrides S3 endpoint, e.g. for minio or localstack. Path-style addressing is used with it.
+	Endpoint string
+	Region   string
+	// AccessKeyID and SecretAccessKey, when set, take precedence over the standard credentials chain
+	AccessKeyID     string
+	SecretAccessKey string
+	// Endpoints are other S3-compatible services, by endpoint URL, with credentials of their own.
+	// Buckets listed for them are fetched from them, the rest from the endpoint above.
+	Endpoints map[string]Endpoint
+	// PartSize is the size of a single ranged GET
+	PartSize int64
+	// Concurrency is how many ranged GETs are run in parallel for a single object
+	Concurrency int
+}
+
+// Endpoint is an S3-compatible service that holds some of the buckets
+type Endpoint struct {
+	Region string `json:"region"`
+	// AccessKeyID and SecretAccessKey, when set, take precedence over the standard credentials chain
+	AccessKeyID     string   `json:"accessKeyId"`
+	SecretAccessKey string   `json:"secretAccessKey"`
+	Buckets         []string `json:"buckets"`
+}
+
+func New(ctx context.Context, dataDir string, cfg Config, logger *slog.Logger) (*Downloader, error) {
+	client, err := newClient(ctx, cfg.Endpoint, Endpoint{
+		Region:          cfg.Region,
+		AccessKeyID:     cfg.AccessKeyID,
+		SecretAccessKey: cfg.SecretAccessKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	bucketClients := make(map[string]*awss3.Client)
+	for endpointURL, endpoint := range cfg.Endpoints {
+		endpointClient, err := newClient(ctx, endpointURL, endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %s: %w", endpointURL, err)
+		}
+		for _, bucket := range endpoint.Buckets {
+			if _, exists := bucketClients[bucket]; exists {
+				return nil, fmt.Errorf("bucket %s is listed for several endpoints", bucket)
+			}
+			bucketClients[bucket] = endpointClient
+		}
+	}
+
+	if cfg.PartSize <= 0 {
+		cfg.PartSize = defaultPartSize
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaultConcurrency
+	}
+
+	d := &Downloader{
+		client:        client,
+		bucketClients: bucketClients,
+		httpClient:    http.DefaultClient,
+		dataDir:       dataDir,
+		partSize:      cfg.PartSize,
+		concurrency:   cfg.Concurrency,
+		log:           logger,
+	}
+	var _ service.Downloader = d
+	var _ service.Describer = d
+	return d, nil
+}
+
+// newClient makes a client of the endpoint, of the default one when endpointURL is empty
+func newClient(ctx context.Context, endpointURL string, endpoint Endpoint) (*awss3.Client, error) {
+	var opts []func(*config.LoadOptions) error
+	if endpoint.Region != "" {
+		opts = append(opts, config.WithRegion(endpoint.Region))
+	}
+	if endpoint.AccessKeyID != "" {
+		opts = append(opts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(endpoint.AccessKeyID, endpoint.SecretAccessKey, ""),
+		))
+	}
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load aws config: %w", err)
+	}
+	return awss3.NewFromConfig(awsCfg, func(o *awss3.Options) {
+		if endpointURL != "" {
+			o.BaseEndpoint = aws.String(endpointURL)
+			o.UsePathStyle = true
+		}
+	}), nil
+}
+
+// Downloader fetches objects from S3: either everything under s3://bucket/prefix/,
+// or a single object via presigned GET URL
+type Downloader struct {
+	client *awss3.Client
+	// bucketClients are of buckets that live at other endpoints
+	bucketClients map[string]*awss3.Client
+	httpClient    *http.Client
+	dataDir       string
+	partSize      int64
+	concurrency   int
+	log           *slog.Logger
+}
+
+// clientFor returns the client of the endpoint the bucket lives at
+func (d *Downloader) clientFor(bucket string) *awss3.Client {
+	if client, ok := d.bucketClients[bucket]; ok {
+		return client
+	}
+	return d.client
+}
+
+func (d *Downloader) Describe() service.DownloaderInfo {
+	return service.DownloaderInfo{
+		Name:                  "s3",
+		Schemes:               []string{"s3", "http", "https"},
+		Patterns:              []string{"s3://bucket/prefix/", "presigned GET URLs (X-Amz-Signature)"},
+		AllowMultipleVariants: true,
+	}
+}
+
+func (d *Downloader) AcceptsURL(url string) bool {
+	if strings.HasPrefix(url, "s3://") {
+		return true
+	}
+	return isPresignedURL(url)
+}
+
+func (d *Downloader) GetMetadata(ctx context.Context, url string) (*service.Metadata, error) {
+	if isPresignedURL(url) {
+		return d.getPresignedMetadata(ctx, url)
+	}
+
+	bucket, prefix, err := parseS3URL(url)
+	if err != nil {
+		return nil, err
+	}
+	errCtx := oops.With("url", url, "bucket", bucket, "prefix", prefix)
+
+	// s3://bucket/some/key.mp3 is a single object, unless there is no such object
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		head, err := d.clientFor(bucket).HeadObject(ctx, &awss3.HeadObjectInput{Bucket: &bucket, Key: &prefix})
+		if err == nil {
+			return &service.Metadata{
+				URL:  url,
+				Name: path.Base(prefix),
+				Variants: []service.VariantMetadata{{
+					ID:       path.Base(prefix),
+					LenBytes: head.ContentLength,
+					ETag:     strings.Trim(aws.ToString(head.ETag), `"`),
+				}},
+				AllowMultipleVariants: false,
+				DownloaderName:        "s3",
+			}, nil
+		}
+		var notFound *types.NotFound
+		if !errors.As(err, &notFound) {
+			return nil, errCtx.Wrapf(err, "failed to head object")
+		}
+		prefix += "/"
+	}
+
+	var variants []service.VariantMetadata
+	paginator := awss3.NewListObjectsV2Paginator(d.clientFor(bucket), &awss3.ListObjectsV2Input{Bucket: &bucket, Prefix: &prefix})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, errCtx.Wrapf(err, "failed to list objects")
+		}
+		for _, obj := range page.Contents {
+			key := aws.ToString(obj.Key)
+			if strings.HasSuffix(key, "/") {
+				continue // "directory" placeholder
+			}
+			variants = append(variants, service.VariantMetadata{
+				ID:       strings.TrimPrefix(key, prefix),
+				LenBytes: obj.Size,
+				ETag:     strings.Trim(aws.ToString(obj.ETag), `"`),
+			})
+		}
+	}
+	sort.Slice(variants, func(i, j int) bool {
+		return variants[i].ID < variants[j].ID
+	})
+
+	name := path.Base(strings.TrimSuffix(prefix, "/"))
+	if prefix == "" {
+		name = bucket
+	}
+	return &service.Metadata{
+		URL:                   url,
+		Name:                  name,
+		Variants:              variants,
+		AllowMultipleVariants: true,
+		DownloaderName:        "s3",
+	}, nil
+}
+
+func (d *Downloader) Download(ctx context.Context, url string, filepaths []string) (filepathsMap map[string]string, err error) {
+	hash := md5.Sum([]byte(url))
+	destDir := filepath.Join(d.dataDir, "s3_"+hex.EncodeToString(hash[:]))
+
+	filepathsMap = make(map[string]string, len(filepaths))
+	for _, variant := range filepaths {
+		if !filepath.IsLocal(filepath.FromSlash(variant)) {
+			return nil, fmt.Errorf("invalid variant: %s", variant)
+		}
+		destPath := filepath.Join(destDir, filepath.FromSlash(variant))
+		errCtx := oops.With("url", url, "variant", variant, "destPath", destPath)
+
+		var fetcher rangeFetcher
+		if isPresignedURL(url) {
+			fetcher = &httpRangeFetcher{client: d.httpClient, url: url}
+		} else {
+			bucket, key, err := d.objectKey(url, variant)
+			if err != nil {
+				return nil, errCtx.Wrap(err)
+			}
+			fetcher = &s3RangeFetcher{client: d.clientFor(bucket), bucket: bucket, key: key}
+		}
+
+		d.log.Debug("downloading object", slog.String("url", url), slog.String("variant", variant))
+		if err := d.download(ctx, fetcher, destPath); err != nil {
+			return nil, errCtx.Wrapf(err, "failed to download object")
+		}
+		filepathsMap[variant] = destPath
+	}
+
+	return filepathsMap, nil
+}
+
+// objectKey figures out the key of a variant: it is either the object itself, or relative to the prefix
+func (d *Downloader) objectKey(url string, variant string) (bucket string, key string, err error) {
+	bucket, prefix, err := parseS3URL(url)
+	if err != nil {
+		return "", "", err
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		if path.Base(prefix) == variant {
+			return bucket, prefix, nil
+		}
+		prefix += "/"
+	}
+	return bucket, prefix + variant, nil
+}
+
+// download fetches the object as a sequence of parts in parallel and writes them in place
+func (d *Downloader) download(ctx context.Context, fetcher rangeFetcher, destPath string) error {
+	size, err := fetcher.Size(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get object size: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	if err := file.Truncate(size); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	parts := make(chan int64)
+	errs := make(chan error, d.concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < d.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range parts {
+				end := min(start+d.partSize, size) - 1
+				if err := d.downloadPart(ctx, fetcher, file, start, end); err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+loop:
+	for start := int64(0); start < size; start += d.partSize {
+		select {
+		case parts <- start:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(parts)
+	wg.Wait()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+func (d *Downloader) downloadPart(ctx context.Context, fetcher rangeFetcher, file *os.File, start, end int64) error {
+	var err error
+	for attempt := 1; attempt <= partAttempts; attempt++ {
+		if err = fetchPart(ctx, fetcher, file, start, end); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		d.log.Debug("failed to fetch part, retrying",
+			slog.Int64("start", start), slog.Int64("end", end), slog.Int("attempt", attempt), slog.Any("error", err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * 500 * time.Millisecond):
+		}
+	}
+	return fmt.Errorf("failed to fetch bytes %d-%d: %w", start, end, err)
+}
+
+func fetchPart(ctx context.Context, fetcher rangeFetcher, file *os.File, start, end int64) error {
+	body, err := fetcher.Range(ctx, start, end)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+
+	n, err := io.Copy(io.NewOffsetWriter(file, start), io.LimitReader(body, end-start+1))
+	if err != nil {
+		return err
+	}
+	if n != end-start+1 {
+		return fmt.Errorf("short read: got %d bytes, expected %d", n, end-start+1)
+	}
+	return nil
+}
+
+// rangeFetcher abstracts over SDK calls and plain HTTP calls to presigned URLs
+type rangeFetcher interface {
+	Size(ctx context.Context) (int64, error)
+	Range(ctx context.Context, start, end int64) (io.ReadCloser, error)
+}
+
+type s3RangeFetcher struct {
+	client *awss3.Client
+	bucket string
+	key    string
+}
+
+func (f *s3RangeFetcher) Size(ctx context.Context) (int64, error) {
+	head, err := f.client.HeadObject(ctx, &awss3.HeadObjectInput{Bucket: &f.bucket, Key: &f.key})
+	if err != nil {
+		return 0, err
+	}
+	return aws.ToInt64(head.ContentLength), nil
+}
+
+func (f *s3RangeFetcher) Range(ctx context.Context, start, end int64) (io.ReadCloser, error) {
+	out, err := f.client.GetObject(ctx, &awss3.GetObjectInput{
+		Bucket: &f.bucket,
+		Key:    &f.key,
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+// httpRangeFetcher works with presigned GET URLs.
+// Signature covers the method, so even the size is figured out with a GET of a single byte.
+type httpRangeFetcher struct {
+	client *http.Client
+	url    string
+}
+
+func (f *httpRangeFetcher) Size(ctx context.Context) (int64, error) {
+	size, _, err := presignedObjectInfo(ctx, f.client, f.url)
+	return size, err
+}
+
+func (f *httpRangeFetcher) Range(ctx context.Context, start, end int64) (io.ReadCloser, error) {
+	resp, err := rangeGet(ctx, f.client, f.url, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+func (d *Downloader) getPresignedMetadata(ctx context.Context, url string) (*service.Metadata, error) {
+	size, etag, err := presignedObjectInfo(ctx, d.httpClient, url)
+	if err != nil {
+		return nil, oops.With("url", url).Wrapf(err, "failed to get object info")
+	}
+	name := presignedObjectName(url)
+	return &service.Metadata{
+		URL:                   url,
+		Name:                  name,
+		Variants:              []service.VariantMetadata{{ID: name, LenBytes: &size, ETag: etag}},
+		AllowMultipleVariants: false,
+		DownloaderName:        "s3",
+	}, nil
+}
+
+func presignedObjectInfo(ctx context.Context, client *http.Client, url string) (size int64, etag string, err error) {
+	resp, err := rangeGet(ctx, client, url, 0, 0)
+	if err != nil {
+		return 0, "", err
+	}
+	// the body is a single byte at most, unless Range was ignored, in which case it is not read at all
+	defer func() { _ = resp.Body.Close() }()
+
+	etag = strings.Trim(resp.Header.Get("ETag"), `"`)
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		// Content-Range: bytes 0-0/12345
+		if size, err = contentRangeSize(resp.Header.Get("Content-Range")); err != nil {
+			return 0, "", err
+		}
+		return size, etag, nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Content-Range: bytes */0, an empty object has no first byte
+		if size, err = contentRangeSize(resp.Header.Get("Content-Range")); err != nil || size != 0 {
+			return 0, "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+		return 0, etag, nil
+	case http.StatusOK:
+		// parts are fetched with ranged GETs too, which would not work either
+		return 0, "", fmt.Errorf("server does not support Range requests")
+	default:
+		return 0, "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+}
+
+// contentRangeSize returns the complete length from Content-Range header
+func contentRangeSize(contentRange string) (int64, error) {
+	i := strings.LastIndex(contentRange, "/")
+	if i == -1 {
+		return 0, fmt.Errorf("unexpected Content-Range: %q", contentRange)
+	}
+	size, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected Content-Range: %q", contentRange)
+	}
+	return size, nil
+}
+
+func rangeGet(ctx context.Context, client *http.Client, url string, start, end int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	return client.Do(req)
+}
+
+func isPresignedURL(url string) bool {
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return false
+	}
+	parsed, err := neturl.Parse(url)
+	if err != nil {
+		return false
+	}
+	query := parsed.Query()
+	return query.Get("X-Amz-Signature") != "" && query.Get("X-Amz-Credential") != ""
+}
+
+func presignedObjectName(url string) string {
+	parsed, err := neturl.Parse(url)
+	if err != nil || path.Base(parsed.Path) == "/" {
+		return "object"
+	}
+	return path.Base(parsed.Path)
+}
+
+// parseS3URL splits s3://bucket/some/prefix into bucket and key prefix
+func parseS3URL(url string) (bucket string, prefix string, err error) {
+	rest, ok := strings.CutPrefix(url, "s3://")
+	if !ok {
+		return "", "", fmt.Errorf("not an s3 url: %s", url)
+	}
+	bucket, prefix, _ = strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("no bucket in url: %s", url)
+	}
+	return bucket, prefix, nil
+}
@@ -0,0 +1,299 @@
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// fakeS3 serves just enough of S3 REST API (path-style) for the downloader:
+// ListObjectsV2, HeadObject and ranged GetObject
+func fakeS3(t *testing.T, bucket string, objects map[string][]byte) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		if b != bucket {
+			http.Error(w, "no such bucket", http.StatusNotFound)
+			return
+		}
+
+		if key == "" && r.URL.Query().Get("list-type") == "2" {
+			type content struct {
+				Key  string `xml:"Key"`
+				Size int64  `xml:"Size"`
+				ETag string `xml:"ETag"`
+			}
+			type listResult struct {
+				XMLName     xml.Name  `xml:"ListBucketResult"`
+				Name        string    `xml:"Name"`
+				Prefix      string    `xml:"Prefix"`
+				KeyCount    int       `xml:"KeyCount"`
+				IsTruncated bool      `xml:"IsTruncated"`
+				Contents    []content `xml:"Contents"`
+			}
+			prefix := r.URL.Query().Get("prefix")
+			result := listResult{Name: bucket, Prefix: prefix}
+			for k, data := range objects {
+				if strings.HasPrefix(k, prefix) {
+					result.Contents = append(result.Contents, content{Key: k, Size: int64(len(data)), ETag: `"etag-` + k + `"`})
+				}
+			}
+			result.KeyCount = len(result.Contents)
+			w.Header().Set("Content-Type", "application/xml")
+			_ = xml.NewEncoder(w).Encode(result)
+			return
+		}
+
+		data, ok := objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", `"etag-`+key+`"`)
+		if r.Method == http.MethodHead {
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			return
+		}
+		var start, end int
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &end); err != nil {
+			_, _ = w.Write(data)
+			return
+		}
+		if start >= len(data) {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", len(data)))
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		end = min(end, len(data)-1)
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+		w.Header().Set("Content-Length", strconv.Itoa(end-start+1))
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write(data[start : end+1])
+	}))
+}
+
+func newTestDownloader(t *testing.T, endpoint string) *Downloader {
+	t.Helper()
+	d, err := New(context.Background(), t.TempDir(), Config{
+		Endpoint:        endpoint,
+		Region:          "us-east-1",
+		AccessKeyID:     "dummy",
+		SecretAccessKey: "dummy",
+		PartSize:        7, // tiny parts, so that every object is fetched in several parallel GETs
+		Concurrency:     3,
+	}, testLogger)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return d
+}
+
+func TestGetMetadataAndDownload_Prefix(t *testing.T) {
+	objects := map[string][]byte{
+		"books/dune/01.mp3": []byte("the first chapter of dune"),
+		"books/dune/02.mp3": []byte("the second chapter of dune, longer"),
+		"books/other.mp3":   []byte("unrelated"),
+	}
+	server := fakeS3(t, "media", objects)
+	defer server.Close()
+	d := newTestDownloader(t, server.URL)
+
+	url := "s3://media/books/dune/"
+	if !d.AcceptsURL(url) {
+		t.Fatalf("expected %s to be accepted", url)
+	}
+	metadata, err := d.GetMetadata(context.Background(), url)
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	var ids []string
+	for _, v := range metadata.Variants {
+		ids = append(ids, v.ID)
+	}
+	if want := []string{"01.mp3", "02.mp3"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("variants = %v, want %v", ids, want)
+	}
+	if metadata.Variants[0].ETag != "etag-books/dune/01.mp3" {
+		t.Errorf("etag = %q", metadata.Variants[0].ETag)
+	}
+	if *metadata.Variants[1].LenBytes != int64(len(objects["books/dune/02.mp3"])) {
+		t.Errorf("length = %d", *metadata.Variants[1].LenBytes)
+	}
+
+	filepathsMap, err := d.Download(context.Background(), url, []string{"02.mp3"})
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	got, err := os.ReadFile(filepathsMap["02.mp3"])
+	if err != nil {
+		t.Fatalf("failed to read downloaded file: %v", err)
+	}
+	if string(got) != string(objects["books/dune/02.mp3"]) {
+		t.Errorf("downloaded content = %q", got)
+	}
+}
+
+func TestGetMetadataAndDownload_SingleObject(t *testing.T) {
+	objects := map[string][]byte{"lecture.mp4": []byte("not really a video")}
+	server := fakeS3(t, "media", objects)
+	defer server.Close()
+	d := newTestDownloader(t, server.URL)
+
+	url := "s3://media/lecture.mp4"
+	metadata, err := d.GetMetadata(context.Background(), url)
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if len(metadata.Variants) != 1 || metadata.Variants[0].ID != "lecture.mp4" || metadata.AllowMultipleVariants {
+		t.Fatalf("unexpected metadata: %+v", metadata)
+	}
+
+	filepathsMap, err := d.Download(context.Background(), url, []string{"lecture.mp4"})
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	got, _ := os.ReadFile(filepathsMap["lecture.mp4"])
+	if string(got) != "not really a video" {
+		t.Errorf("downloaded content = %q", got)
+	}
+}
+
+func TestGetMetadataAndDownload_PresignedURL(t *testing.T) {
+	objects := map[string][]byte{"raw/interview.wav": []byte("RIFF....WAVEfmt and some more bytes")}
+	server := fakeS3(t, "media", objects)
+	defer server.Close()
+	d := newTestDownloader(t, server.URL)
+
+	url := server.URL + "/media/raw/interview.wav?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=dummy&X-Amz-Signature=abc"
+	if !d.AcceptsURL(url) {
+		t.Fatalf("expected presigned url to be accepted")
+	}
+	if d.AcceptsURL(server.URL + "/media/raw/interview.wav") {
+		t.Errorf("plain http url must not be accepted")
+	}
+
+	metadata, err := d.GetMetadata(context.Background(), url)
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	variant := metadata.Variants[0]
+	if variant.ID != "interview.wav" || *variant.LenBytes != int64(len(objects["raw/interview.wav"])) || variant.ETag != "etag-raw/interview.wav" {
+		t.Fatalf("unexpected variant: %+v", variant)
+	}
+
+	filepathsMap, err := d.Download(context.Background(), url, []string{"interview.wav"})
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	got, _ := os.ReadFile(filepathsMap["interview.wav"])
+	if string(got) != string(objects["raw/interview.wav"]) {
+		t.Errorf("downloaded content = %q", got)
+	}
+}
+
+func TestGetMetadata_PresignedURLOfEmptyObject(t *testing.T) {
+	server := fakeS3(t, "media", map[string][]byte{"empty.wav": {}})
+	defer server.Close()
+	d := newTestDownloader(t, server.URL)
+
+	url := server.URL + "/media/empty.wav?X-Amz-Credential=dummy&X-Amz-Signature=abc"
+	metadata, err := d.GetMetadata(context.Background(), url)
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if size := *metadata.Variants[0].LenBytes; size != 0 {
+		t.Errorf("size = %d, want 0", size)
+	}
+	filepathsMap, err := d.Download(context.Background(), url, []string{"empty.wav"})
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if stat, err := os.Stat(filepathsMap["empty.wav"]); err != nil || stat.Size() != 0 {
+		t.Errorf("downloaded file: %v, %v", stat, err)
+	}
+}
+
+func TestGetMetadata_PresignedURLWithoutRangeSupport(t *testing.T) {
+	var sent atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a large body, which must not be read through
+		for range 1024 {
+			n, err := w.Write(make([]byte, 64<<10))
+			sent.Add(int64(n))
+			if err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+	d := newTestDownloader(t, server.URL)
+
+	_, err := d.GetMetadata(context.Background(), server.URL+"/media/big.wav?X-Amz-Credential=dummy&X-Amz-Signature=abc")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if sent.Load() == 64<<20 {
+		t.Error("the whole body was read")
+	}
+}
+
+func TestGetMetadata_EndpointOfBucket(t *testing.T) {
+	defaultServer := fakeS3(t, "media", map[string][]byte{"a.mp3": []byte("default")})
+	defer defaultServer.Close()
+	minio := fakeS3(t, "recordings", map[string][]byte{"b.mp3": []byte("minio")})
+	defer minio.Close()
+	// the other endpoint only lets its own credentials in
+	minioWithAuth := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Authorization"), "Credential=minio-key/") {
+			http.Error(w, "access denied", http.StatusForbidden)
+			return
+		}
+		minio.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer minioWithAuth.Close()
+
+	d, err := New(context.Background(), t.TempDir(), Config{
+		Endpoint:        defaultServer.URL,
+		Region:          "us-east-1",
+		AccessKeyID:     "dummy",
+		SecretAccessKey: "dummy",
+		Endpoints: map[string]Endpoint{minioWithAuth.URL: {
+			Region:          "us-east-1",
+			AccessKeyID:     "minio-key",
+			SecretAccessKey: "minio-secret",
+			Buckets:         []string{"recordings"},
+		}},
+	}, testLogger)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for url, want := range map[string]string{"s3://media/": "a.mp3", "s3://recordings/": "b.mp3"} {
+		metadata, err := d.GetMetadata(context.Background(), url)
+		if err != nil {
+			t.Fatalf("GetMetadata(%s) failed: %v", url, err)
+		}
+		if len(metadata.Variants) != 1 || metadata.Variants[0].ID != want {
+			t.Errorf("GetMetadata(%s) variants = %+v", url, metadata.Variants)
+		}
+	}
+	filepathsMap, err := d.Download(context.Background(), "s3://recordings/", []string{"b.mp3"})
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if got, _ := os.ReadFile(filepathsMap["b.mp3"]); string(got) != "minio" {
+		t.Errorf("downloaded content = %q", got)
+	}
+}
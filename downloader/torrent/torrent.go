@@ -15,6 +15,10 @@ import (
 	"github.com/dir01/mediary/service"
 )
 
+// streamReadahead is how many bytes past the current read position are prioritized while streaming.
+// A few megabytes is enough to cover a couple of minutes of a typical audiobook.
+const streamReadahead = 5 * 1024 * 1024
+
 func New(dataDir string, logger *slog.Logger, isDebug bool) (*Downloader, error) {
 	cfg := anacrolixTorrent.NewDefaultClientConfig()
 	cfg.ListenPort = 0
@@ -26,6 +30,8 @@ func New(dataDir string, logger *slog.Logger, isDebug bool) (*Downloader, error)
 	}
 	d := &Downloader{torrentClient: torrentClient, dataDir: dataDir, log: logger}
 	var _ service.Downloader = d
+	var _ service.Describer = d
+	var _ service.Streamer = d
 	return d, nil
 }
 
@@ -53,6 +59,15 @@ func (td *Downloader) addMagnet(url string) (*anacrolixTorrent.Torrent, error) {
 	return torr, nil
 }
 
+func (td *Downloader) Describe() service.DownloaderInfo {
+	return service.DownloaderInfo{
+		Name:                  "torrent",
+		Schemes:               []string{"magnet"},
+		Patterns:              []string{"magnet:?xt=urn:btih:*"},
+		AllowMultipleVariants: true,
+	}
+}
+
 func (td *Downloader) AcceptsURL(url string) bool {
 	return strings.HasPrefix(url, "magnet:")
 }
@@ -156,3 +171,39 @@ func (td *Downloader) Download(ctx context.Context, url string, filepaths []stri
 
 	return filepathsMap, nil
 }
+
+// Stream returns a reader over a single file of the torrent.
+// Pieces under and right after the read position are prioritized,
+// so reading can start long before the whole file is downloaded.
+func (td *Downloader) Stream(ctx context.Context, url string, variant string) (*service.VariantStream, error) {
+	torr, err := td.addMagnet(url)
+	if err != nil {
+		td.log.Debug("failed to add magnet", slog.String("url", url), slog.Any("error", err))
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-torr.GotInfo():
+		break
+	}
+
+	for _, tf := range torr.Files() {
+		if tf.DisplayPath() != variant {
+			continue
+		}
+		td.log.Debug("streaming file", slog.String("filepath", variant), slog.String("url", url))
+		reader := tf.NewReader()
+		reader.SetContext(ctx)
+		reader.SetResponsive()
+		reader.SetReadahead(streamReadahead)
+		return &service.VariantStream{
+			ReadSeekCloser: reader,
+			Name:           path.Base(variant),
+			Size:           tf.Length(),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", service.ErrVariantNotFound, variant)
+}
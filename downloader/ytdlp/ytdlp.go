@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -28,6 +29,8 @@ const (
 func New(dataDir string, logger *slog.Logger) (*YtdlpDownloader, error) {
 	d := &YtdlpDownloader{dataDir: dataDir, log: logger}
 	var _ service.Downloader = d
+	var _ service.Describer = d
+	var _ service.SectionDownloader = d
 	return d, nil
 }
 
@@ -37,6 +40,14 @@ type YtdlpDownloader struct {
 	log     *slog.Logger
 }
 
+func (y *YtdlpDownloader) Describe() service.DownloaderInfo {
+	return service.DownloaderInfo{
+		Name:     "ytdl",
+		Schemes:  []string{"http", "https"},
+		Patterns: []string{"any page supported by yt-dlp"},
+	}
+}
+
 func (y *YtdlpDownloader) AcceptsURL(url string) bool {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
@@ -86,26 +97,13 @@ func (y *YtdlpDownloader) Download(ctx context.Context, url string, filepaths []
 		return nil, fmt.Errorf("expected 1 filepath, got %d", len(filepaths))
 	}
 
-	args := []string{url, "--prefer-ffmpeg"}
-	destinationPathBase := path.Join(y.dataDir, uuid.New().String())
-	var destinationPath string
 	ytFormat := filepaths[0]
-	switch ytFormat {
-	case formatTypeVideo:
-		destinationPath = destinationPathBase + ".mp4"
-		args = append(args, "--format", "mp4")
-	case formatTypeAudioHQ:
-		destinationPath = destinationPathBase + ".mp3"
-		args = append(args, "--extract-audio", "--audio-format", "mp3", "--audio-quality", "0")
-	case formatTypeAudioMQ:
-		destinationPath = destinationPathBase + ".mp3"
-		args = append(args, "--extract-audio", "--audio-format", "mp3", "--audio-quality", "5")
-	case formatTypeAudioLQ:
-		destinationPath = destinationPathBase + ".mp3"
-		args = append(args, "--extract-audio", "--audio-format", "mp3", "--audio-quality", "9")
-	default:
-		return nil, fmt.Errorf("unknown format: %s", ytFormat)
+	ext, formatArgs, err := formatArgs(ytFormat)
+	if err != nil {
+		return nil, err
 	}
+	destinationPath := path.Join(y.dataDir, uuid.New().String()) + ext
+	args := append([]string{url, "--prefer-ffmpeg"}, formatArgs...)
 	args = append(args, "--output", destinationPath)
 
 	if _, err := y.runYTDLP(ctx, args...); err != nil {
@@ -115,6 +113,65 @@ func (y *YtdlpDownloader) Download(ctx context.Context, url string, filepaths []
 	return map[string]string{ytFormat: destinationPath}, nil
 }
 
+// DownloadSections downloads ranges of the video, one at a time. Unless fast, cuts are made at exact positions,
+// which makes yt-dlp re-encode the video around them. Sections downloaded before a failure are removed.
+func (y *YtdlpDownloader) DownloadSections(
+	ctx context.Context, url string, variant string, ranges []service.TimeRange, fast bool,
+) ([]string, error) {
+	ext, formatArgs, err := formatArgs(variant)
+	if err != nil {
+		return nil, err
+	}
+
+	filepaths := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		destinationPath := path.Join(y.dataDir, uuid.New().String()) + ext
+		args := append([]string{url, "--prefer-ffmpeg"}, formatArgs...)
+		args = append(args, sectionArgs(r, fast, destinationPath)...)
+		if _, err := y.runYTDLP(ctx, args...); err != nil {
+			for _, fp := range append(filepaths, destinationPath) {
+				_ = os.Remove(fp)
+			}
+			return nil, err
+		}
+		filepaths = append(filepaths, destinationPath)
+	}
+	return filepaths, nil
+}
+
+func sectionArgs(r service.TimeRange, fast bool, destinationPath string) []string {
+	args := []string{"--download-sections", sectionSpec(r)}
+	if !fast {
+		args = append(args, "--force-keyframes-at-cuts")
+	}
+	return append(args, "--output", destinationPath)
+}
+
+// sectionSpec formats the range for --download-sections, where "*" marks a time range rather than a chapter title
+func sectionSpec(r service.TimeRange) string {
+	end := "inf"
+	if r.End > 0 {
+		end = strconv.FormatFloat(r.End.Seconds(), 'f', -1, 64)
+	}
+	return "*" + strconv.FormatFloat(r.Start.Seconds(), 'f', -1, 64) + "-" + end
+}
+
+// formatArgs returns the extension of files of the variant, and yt-dlp arguments to download it
+func formatArgs(ytFormat string) (string, []string, error) {
+	switch ytFormat {
+	case formatTypeVideo:
+		return ".mp4", []string{"--format", "mp4"}, nil
+	case formatTypeAudioHQ:
+		return ".mp3", []string{"--extract-audio", "--audio-format", "mp3", "--audio-quality", "0"}, nil
+	case formatTypeAudioMQ:
+		return ".mp3", []string{"--extract-audio", "--audio-format", "mp3", "--audio-quality", "5"}, nil
+	case formatTypeAudioLQ:
+		return ".mp3", []string{"--extract-audio", "--audio-format", "mp3", "--audio-quality", "9"}, nil
+	default:
+		return "", nil, fmt.Errorf("unknown format: %s", ytFormat)
+	}
+}
+
 func (y *YtdlpDownloader) runYTDLP(ctx context.Context, args ...string) (out []byte, err error) {
 	y.log.Debug("running yt-dlp", slog.Any("args", args))
 
@@ -0,0 +1,22 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dir01/mediary/service"
+)
+
+func handleListDownloaders(svc *service.Service) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			respond(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		downloaders := svc.Downloaders()
+		if downloaders == nil {
+			downloaders = []service.DownloaderInfo{}
+		}
+		respond(w, http.StatusOK, downloaders)
+	}
+}
@@ -15,6 +15,10 @@ func PrepareHTTPServerMux(service *service.Service) http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/metadata", handleGetMetadata(service, 100*time.Millisecond))
 	mux.HandleFunc("/metadata/long-polling", handleGetMetadata(service, 5*time.Minute))
+	mux.HandleFunc("/stream", handleStream(service))
+	mux.HandleFunc("/probe", handleProbe(service))
+	mux.HandleFunc("/downloaders", handleListDownloaders(service))
+	mux.HandleFunc("/jobs/{id}/waveform", handleGetWaveform(service))
 	mux.HandleFunc("/jobs/", handleGetJob(service))
 	mux.HandleFunc("/jobs", handleCreateJob(service))
 	mux.HandleFunc("/", handleDocs())
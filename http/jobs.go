@@ -2,8 +2,10 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/dir01/mediary/service"
@@ -23,6 +25,9 @@ func handleCreateJob(svc *service.Service) func(http.ResponseWriter, *http.Reque
 		if job, err := svc.CreateJob(req.Context(), params); err == nil {
 			respond(w, http.StatusAccepted, fmt.Sprintf(`{"status": "accepted", "id": "%s"}`, job.ID))
 			return
+		} else if errors.Is(err, service.ErrUnknownDownloader) {
+			respond(w, http.StatusBadRequest, fmt.Errorf("failed to create job: %w", err))
+			return
 		} else {
 			respond(w, http.StatusInternalServerError, fmt.Errorf("failed to create job: %w", err))
 			return
@@ -49,3 +54,37 @@ func handleGetJob(svc *service.Service) func(http.ResponseWriter, *http.Request)
 		respond(w, http.StatusOK, job)
 	}
 }
+
+// handleGetWaveform returns stored peaks of a job result. Parts are numbered from 1, like in upload URL templates.
+func handleGetWaveform(svc *service.Service) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			respond(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		part := 1
+		if p := req.URL.Query().Get("part"); p != "" {
+			var err error
+			if part, err = strconv.Atoi(p); err != nil || part < 1 {
+				respond(w, http.StatusBadRequest, fmt.Errorf("invalid part: %q", p))
+				return
+			}
+		}
+		peaks, err := svc.GetWaveform(req.Context(), req.PathValue("id"), part-1)
+		if err != nil {
+			respond(w, http.StatusInternalServerError, fmt.Errorf("failed to get waveform: %w", err))
+			return
+		}
+		if peaks == nil {
+			respond(w, http.StatusNotFound, fmt.Errorf("waveform not found"))
+			return
+		}
+		// compact, since indented peaks take a line each
+		data, err := json.Marshal(peaks)
+		if err != nil {
+			respond(w, http.StatusInternalServerError, fmt.Errorf("failed to marshal waveform: %w", err))
+			return
+		}
+		respond(w, http.StatusOK, string(data))
+	}
+}
@@ -13,6 +13,9 @@ import (
 	"github.com/dir01/mediary/service"
 )
 
+// reservedQueryParams are mediary's own query parameters that may follow an unescaped magnet URL.
+var reservedQueryParams = []string{"variant", "downloader", "probe"}
+
 // extractURLParam extracts the "url" query parameter from a GET request.
 // Magnet URLs contain literal '&' separating parameters (e.g. &tr=, &dn=)
 // which standard query parsing splits on, truncating the URL.
@@ -21,7 +24,7 @@ func extractURLParam(req *http.Request) string {
 	url := req.URL.Query().Get("url")
 	if strings.HasPrefix(url, "magnet:") {
 		if i := strings.Index(req.URL.RawQuery, "url="); i != -1 {
-			if raw, err := neturl.QueryUnescape(req.URL.RawQuery[i+4:]); err == nil {
+			if raw, err := neturl.QueryUnescape(stripReservedParams(req.URL.RawQuery[i+4:])); err == nil {
 				return raw
 			}
 		}
@@ -29,6 +32,25 @@ func extractURLParam(req *http.Request) string {
 	return url
 }
 
+// stripReservedParams cuts mediary's own parameters out of a raw magnet URL,
+// e.g. "magnet:?xt=...&variant=a.mp3&dn=Foo" becomes "magnet:?xt=...&dn=Foo"
+func stripReservedParams(raw string) string {
+	for _, name := range reservedQueryParams {
+		for {
+			i := strings.Index(raw, "&"+name+"=")
+			if i == -1 {
+				break
+			}
+			if end := strings.Index(raw[i+1:], "&"); end == -1 {
+				raw = raw[:i]
+			} else {
+				raw = raw[:i] + raw[i+1+end:]
+			}
+		}
+	}
+	return raw
+}
+
 func handleGetMetadata(svc *service.Service, timeout time.Duration) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
@@ -38,29 +60,27 @@ func handleGetMetadata(svc *service.Service, timeout time.Duration) func(http.Re
 			ctx = ctx1
 		}
 
-		var url string
+		var metadataReq service.MetadataRequest
 		switch req.Method {
 		case http.MethodGet:
-			url = extractURLParam(req)
+			metadataReq.URL = extractURLParam(req)
+			metadataReq.Downloader = req.URL.Query().Get("downloader")
+			metadataReq.Probe = req.URL.Query().Get("probe") == "true"
 		case http.MethodPost:
 			// read json body
-			var body struct {
-				URL string `json:"url"`
-			}
-			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			if err := json.NewDecoder(req.Body).Decode(&metadataReq); err != nil {
 				respond(w, http.StatusBadRequest, err)
 				return
 			}
-			url = body.URL
 		default:
 			respond(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
 		}
-		if url == "" {
+		if metadataReq.URL == "" {
 			respond(w, http.StatusBadRequest, errors.New("missing url parameter"))
 			return
 		}
 
-		if metadata, err := svc.GetMetadata(ctx, url); err == nil {
+		if metadata, err := svc.GetMetadata(ctx, metadataReq); err == nil {
 			respond(w, http.StatusOK, metadata)
 			return
 		} else if errors.Is(err, context.DeadlineExceeded) {
@@ -69,6 +89,9 @@ func handleGetMetadata(svc *service.Service, timeout time.Duration) func(http.Re
 		} else if errors.Is(err, service.ErrUrlNotSupported) {
 			respond(w, http.StatusBadRequest, fmt.Errorf("url not supported: %w", err))
 			return
+		} else if errors.Is(err, service.ErrUnknownDownloader) {
+			respond(w, http.StatusBadRequest, err)
+			return
 		} else {
 			respond(w, http.StatusInternalServerError, err)
 			return
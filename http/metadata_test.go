@@ -27,3 +27,17 @@ func TestExtractURLParam_PlainURL(t *testing.T) {
 		t.Errorf("extractURLParam() = %q", got)
 	}
 }
+
+func TestExtractURLParam_MagnetFollowedByReservedParam(t *testing.T) {
+	rawQuery := "url=magnet:?xt=urn:btih:0B1313000B0C900685793A9A50DA13D260246F4B&variant=cd1%2F01.mp3&dn=Foo"
+	req, _ := http.NewRequest(http.MethodGet, "/stream?"+rawQuery, nil)
+
+	got := extractURLParam(req)
+	want := "magnet:?xt=urn:btih:0B1313000B0C900685793A9A50DA13D260246F4B&dn=Foo"
+	if got != want {
+		t.Errorf("extractURLParam() =\n  %q\nwant\n  %q", got, want)
+	}
+	if variant := req.URL.Query().Get("variant"); variant != "cd1/01.mp3" {
+		t.Errorf("variant = %q", variant)
+	}
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dir01/mediary/service"
+)
+
+// handleProbe tells what is inside a single variant, downloading it first
+func handleProbe(svc *service.Service) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			respond(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+
+		probeReq := service.ProbeRequest{
+			URL:        extractURLParam(req),
+			Variant:    req.URL.Query().Get("variant"),
+			Downloader: req.URL.Query().Get("downloader"),
+		}
+		if probeReq.URL == "" {
+			respond(w, http.StatusBadRequest, errors.New("missing url parameter"))
+			return
+		}
+		if probeReq.Variant == "" {
+			respond(w, http.StatusBadRequest, errors.New("missing variant parameter"))
+			return
+		}
+
+		info, err := svc.Probe(req.Context(), probeReq)
+		if errors.Is(err, service.ErrUrlNotSupported) || errors.Is(err, service.ErrUnknownDownloader) {
+			respond(w, http.StatusBadRequest, err)
+			return
+		} else if errors.Is(err, service.ErrVariantNotFound) {
+			respond(w, http.StatusNotFound, err)
+			return
+		} else if err != nil {
+			respond(w, http.StatusInternalServerError, fmt.Errorf("failed to probe: %w", err))
+			return
+		}
+		respond(w, http.StatusOK, info)
+	}
+}
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dir01/mediary/service"
+)
+
+// handleStream serves a single variant over HTTP while it is being downloaded.
+// Range requests are supported, so players can seek within the file.
+func handleStream(svc *service.Service) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			respond(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+
+		url := extractURLParam(req)
+		if url == "" {
+			respond(w, http.StatusBadRequest, errors.New("missing url parameter"))
+			return
+		}
+		variant := req.URL.Query().Get("variant")
+		if variant == "" {
+			respond(w, http.StatusBadRequest, errors.New("missing variant parameter"))
+			return
+		}
+
+		stream, err := svc.Stream(req.Context(), url, variant)
+		if errors.Is(err, service.ErrUrlNotSupported) || errors.Is(err, service.ErrStreamingNotSupported) {
+			respond(w, http.StatusBadRequest, err)
+			return
+		} else if errors.Is(err, service.ErrVariantNotFound) {
+			respond(w, http.StatusNotFound, err)
+			return
+		} else if err != nil {
+			respond(w, http.StatusInternalServerError, fmt.Errorf("failed to open stream: %w", err))
+			return
+		}
+		defer func() { _ = stream.Close() }()
+
+		http.ServeContent(w, req, stream.Name, time.Time{}, stream)
+	}
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dir01/mediary/service"
+	"github.com/dir01/mediary/service/mocks"
+	"github.com/gojuno/minimock/v3"
+)
+
+// fakeStreamer serves variants from memory
+type fakeStreamer struct {
+	variants map[string][]byte
+}
+
+func (f *fakeStreamer) AcceptsURL(url string) bool {
+	return strings.HasPrefix(url, "magnet:")
+}
+
+func (f *fakeStreamer) GetMetadata(context.Context, string) (*service.Metadata, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeStreamer) Download(context.Context, string, []string) (map[string]string, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeStreamer) Stream(_ context.Context, _ string, variant string) (*service.VariantStream, error) {
+	data, ok := f.variants[variant]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", service.ErrVariantNotFound, variant)
+	}
+	return &service.VariantStream{
+		ReadSeekCloser: nopCloser{bytes.NewReader(data)},
+		Name:           variant,
+		Size:           int64(len(data)),
+	}, nil
+}
+
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error { return nil }
+
+func TestHandleStream(t *testing.T) {
+	mc := minimock.NewController(t)
+	content := []byte("0123456789abcdefghij")
+	svc := service.NewService(&fakeStreamer{variants: map[string][]byte{"book/01.mp3": content}},
+		mocks.NewStorageMock(mc), mocks.NewJobsQueueMock(mc), mocks.NewMediaProcessorMock(mc), mocks.NewUploaderMock(mc),
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	handler := handleStream(svc)
+
+	for _, tc := range []struct {
+		name             string
+		query            string
+		rangeHeader      string
+		wantStatus       int
+		wantBody         string
+		wantContentRange string
+	}{
+		{name: "whole variant", query: "url=magnet:?xt=urn:btih:deadbeef&variant=book/01.mp3",
+			wantStatus: http.StatusOK, wantBody: string(content)},
+		{name: "range", query: "url=magnet:?xt=urn:btih:deadbeef&variant=book/01.mp3", rangeHeader: "bytes=5-9",
+			wantStatus: http.StatusPartialContent, wantBody: "56789", wantContentRange: "bytes 5-9/20"},
+		{name: "open-ended range", query: "url=magnet:?xt=urn:btih:deadbeef&variant=book/01.mp3", rangeHeader: "bytes=15-",
+			wantStatus: http.StatusPartialContent, wantBody: "fghij", wantContentRange: "bytes 15-19/20"},
+		{name: "suffix range", query: "url=magnet:?xt=urn:btih:deadbeef&variant=book/01.mp3", rangeHeader: "bytes=-3",
+			wantStatus: http.StatusPartialContent, wantBody: "hij", wantContentRange: "bytes 17-19/20"},
+		{name: "unsatisfiable range", query: "url=magnet:?xt=urn:btih:deadbeef&variant=book/01.mp3", rangeHeader: "bytes=20-30",
+			wantStatus: http.StatusRequestedRangeNotSatisfiable, wantContentRange: "bytes */20"},
+		{name: "unknown variant", query: "url=magnet:?xt=urn:btih:deadbeef&variant=book/02.mp3",
+			wantStatus: http.StatusNotFound},
+		{name: "unsupported url", query: "url=ftp://example.com/01.mp3&variant=01.mp3",
+			wantStatus: http.StatusBadRequest},
+		{name: "no variant", query: "url=magnet:?xt=urn:btih:deadbeef",
+			wantStatus: http.StatusBadRequest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/stream?"+tc.query, nil)
+			if tc.rangeHeader != "" {
+				req.Header.Set("Range", tc.rangeHeader)
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tc.wantStatus, rec.Body)
+			}
+			if tc.wantBody != "" && rec.Body.String() != tc.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body, tc.wantBody)
+			}
+			if got := rec.Header().Get("Content-Range"); got != tc.wantContentRange {
+				t.Errorf("Content-Range = %q, want %q", got, tc.wantContentRange)
+			}
+			if tc.wantStatus < 300 && rec.Header().Get("Accept-Ranges") != "bytes" {
+				t.Errorf("Accept-Ranges = %q", rec.Header().Get("Accept-Ranges"))
+			}
+		})
+	}
+}
+
+func TestHandleStream_HeadRequest(t *testing.T) {
+	mc := minimock.NewController(t)
+	svc := service.NewService(&fakeStreamer{variants: map[string][]byte{"01.mp3": []byte("0123456789")}},
+		mocks.NewStorageMock(mc), mocks.NewJobsQueueMock(mc), mocks.NewMediaProcessorMock(mc), mocks.NewUploaderMock(mc),
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	req := httptest.NewRequest(http.MethodHead, "/stream?url=magnet:?xt=urn:btih:deadbeef&variant=01.mp3", nil)
+	rec := httptest.NewRecorder()
+	handleStream(svc)(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Length") != "10" || rec.Body.Len() != 0 {
+		t.Errorf("status %d, Content-Length %q, body of %d bytes",
+			rec.Code, rec.Header().Get("Content-Length"), rec.Body.Len())
+	}
+	if contentType := rec.Header().Get("Content-Type"); contentType != "audio/mpeg" {
+		t.Errorf("Content-Type = %q, want audio/mpeg", contentType)
+	}
+}
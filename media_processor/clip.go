@@ -0,0 +1,143 @@
+package media_processor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/dir01/mediary/service"
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Clip cuts every range into a file of its own, in the container of the original.
+// Seeking happens before decoding, which is both fast and, when re-encoding, frame-accurate.
+func (conv *FFMpegMediaProcessor) Clip(ctx context.Context, fp string, opts service.ClipOptions) ([]string, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.Clip",
+		trace.WithAttributes(
+			attribute.String("filepath", fp),
+			attribute.Int("ranges.count", len(opts.Ranges)),
+			attribute.Bool("fast", opts.Fast),
+		),
+	)
+	defer span.End()
+
+	errCtx := oops.With("filepath", fp, "opts", opts)
+	fail := func(err error) ([]string, error) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	container, ok := service.ContainerByExt(filepath.Ext(fp))
+	if opts.Container != "" {
+		var err error
+		if container, err = service.LookupContainer(opts.Container); err != nil {
+			return fail(errCtx.Wrap(err))
+		}
+	} else if !ok {
+		return fail(errCtx.Wrap(fmt.Errorf("%w: %s", service.ErrUnknownContainer, filepath.Ext(fp))))
+	}
+	duration, err := conv.GetDuration(fp)
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to get duration"))
+	}
+	var probe audioProbe
+	if reencodeClip(opts) {
+		if probe, err = conv.probeAudio(ctx, fp); err != nil {
+			return fail(errCtx.Wrapf(err, "failed to probe file"))
+		}
+	}
+
+	var clips []string
+	removeClips := func() {
+		for _, clip := range clips {
+			_ = os.Remove(clip)
+		}
+	}
+	for i, r := range opts.Ranges {
+		if r.End == 0 || r.End > duration {
+			r.End = duration
+		}
+		if r.Start >= r.End {
+			removeClips()
+			return fail(errCtx.With("range", i+1).Errorf("range starts after the end of media"))
+		}
+
+		file, err := os.CreateTemp("", fmt.Sprintf("*-clip%03d%s", i+1, container.Ext))
+		if err != nil {
+			removeClips()
+			return fail(errCtx.Wrapf(err, "failed to create temp file"))
+		}
+		_ = file.Close()
+		clips = append(clips, file.Name())
+
+		cmd := exec.CommandContext(ctx, "ffmpeg", clipArgs(fp, file.Name(), r, opts, probe, container)...)
+		conv.log.Debug("cutting clip", slog.String("filepath", fp), slog.Int("range", i+1), slog.String("cmd", cmd.String()))
+		if output, err := cmd.CombinedOutput(); err != nil {
+			removeClips()
+			return fail(errCtx.With("cmd", cmd.String(), "output", string(output)).Wrapf(err, "failed to run ffmpeg"))
+		}
+	}
+	return clips, nil
+}
+
+func reencodeClip(opts service.ClipOptions) bool {
+	return !opts.Fast || opts.FadeIn > 0 || opts.FadeOut > 0 || opts.Container != ""
+}
+
+// clipArgs cut a range that is known to end within media
+func clipArgs(input, output string, r service.TimeRange, opts service.ClipOptions, probe audioProbe, container service.Container) []string {
+	length := r.End - r.Start
+	args := []string{
+		"-y", "-hide_banner", "-nostats",
+		"-ss", formatFloat(r.Start.Seconds()), "-i", input, "-t", formatFloat(length.Seconds()),
+		"-map_metadata", "0", "-map_chapters", "-1",
+	}
+	if container.DefaultVideoCodec == "" {
+		args = append(args, "-map", "0:a")
+	} else {
+		args = append(args, "-map", "0:v:0?", "-map", "0:a")
+	}
+
+	if !reencodeClip(opts) {
+		// timestamps of copied streams start where the keyframe before the range was
+		return append(args, "-c", "copy", "-avoid_negative_ts", "make_zero", output)
+	}
+
+	audioFilters, videoFilters := fadeFilters("afade", opts, length), fadeFilters("fade", opts, length)
+	if audioFilters != "" {
+		args = append(args, "-af", audioFilters)
+	}
+	if container.DefaultVideoCodec != "" {
+		if videoFilters != "" {
+			args = append(args, "-vf", videoFilters)
+		}
+		args = append(args, "-c:v", container.DefaultVideoCodec)
+	}
+	args = append(args, reencodeArgs(probe, container)...)
+	return append(args, output)
+}
+
+// fadeFilters make fades with either afade or fade filter, which share their syntax
+func fadeFilters(filter string, opts service.ClipOptions, length time.Duration) string {
+	var filters string
+	if opts.FadeIn > 0 {
+		filters = fmt.Sprintf("%s=t=in:st=0:d=%s", filter, formatFloat(min(opts.FadeIn, length).Seconds()))
+	}
+	if opts.FadeOut > 0 {
+		fadeOut := min(opts.FadeOut, length)
+		if filters != "" {
+			filters += ","
+		}
+		filters += fmt.Sprintf("%s=t=out:st=%s:d=%s", filter, formatFloat((length - fadeOut).Seconds()), formatFloat(fadeOut.Seconds()))
+	}
+	return filters
+}
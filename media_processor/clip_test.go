@@ -0,0 +1,62 @@
+package media_processor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/service"
+)
+
+func TestClipArgs(t *testing.T) {
+	r := service.TimeRange{Start: 12*time.Minute + 30*time.Second, End: 47 * time.Minute}
+	mp4, _ := service.ContainerByExt(".mp4")
+	mp3, _ := service.ContainerByExt(".mp3")
+	probe := audioProbe{FormatName: "mp3", Codec: "mp3", SampleRate: 44100, Channels: 2, BitRate: 128000}
+
+	for _, tc := range []struct {
+		name      string
+		opts      service.ClipOptions
+		container service.Container
+		want      []string
+	}{
+		{
+			name:      "fast copies streams",
+			opts:      service.ClipOptions{Fast: true},
+			container: mp4,
+			want: []string{
+				"-y", "-hide_banner", "-nostats", "-ss", "750", "-i", "in", "-t", "2070",
+				"-map_metadata", "0", "-map_chapters", "-1", "-map", "0:v:0?", "-map", "0:a",
+				"-c", "copy", "-avoid_negative_ts", "make_zero", "out",
+			},
+		},
+		{
+			name:      "fades re-encode even when fast",
+			opts:      service.ClipOptions{Fast: true, FadeIn: 2 * time.Second, FadeOut: 3 * time.Second},
+			container: mp3,
+			want: []string{
+				"-y", "-hide_banner", "-nostats", "-ss", "750", "-i", "in", "-t", "2070",
+				"-map_metadata", "0", "-map_chapters", "-1", "-map", "0:a",
+				"-af", "afade=t=in:st=0:d=2,afade=t=out:st=2067:d=3",
+				"-c:a", "libmp3lame", "-b:a", "128000", "-ar", "44100", "out",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := clipArgs("in", "out", r, tc.opts, probe, tc.container); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFadeFilters(t *testing.T) {
+	opts := service.ClipOptions{FadeIn: 5 * time.Second, FadeOut: 5 * time.Second}
+	// fades of a clip shorter than them span the whole clip
+	if got, want := fadeFilters("fade", opts, 3*time.Second), "fade=t=in:st=0:d=3,fade=t=out:st=0:d=3"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := fadeFilters("afade", service.ClipOptions{}, time.Minute); got != "" {
+		t.Errorf("no fades: got %q", got)
+	}
+}
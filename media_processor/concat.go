@@ -0,0 +1,348 @@
+package media_processor
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/dir01/mediary/service"
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type concatMethod string
+
+const (
+	// concatProtocol glues files byte by byte, which only works for MPEG audio
+	concatProtocol concatMethod = "protocol"
+	// concatDemuxer reads files one after another, streams have to share codec parameters
+	concatDemuxer concatMethod = "demuxer"
+	// concatFilter decodes everything and resamples it to common parameters
+	concatFilter concatMethod = "filter"
+)
+
+// containerCodecs are codecs (as reported by ffprobe) that a container can hold without re-encoding
+var containerCodecs = map[string][]string{
+	"mp3":  {"mp3"},
+	"m4a":  {"aac", "alac"},
+	"m4b":  {"aac", "alac"},
+	"mp4":  {"aac", "alac", "mp3", "ac3", "eac3"},
+	"mkv":  {"aac", "alac", "mp3", "ac3", "eac3", "dts", "opus", "vorbis", "flac", "pcm_s16le", "pcm_s24le"},
+	"opus": {"opus"},
+	"ogg":  {"vorbis", "opus", "flac"},
+	"flac": {"flac"},
+	"wav":  {"pcm_s16le", "pcm_s24le", "pcm_s32le", "pcm_f32le", "pcm_u8"},
+	"webm": {"opus", "vorbis"},
+}
+
+// audioProbe is what concatenation needs to know about an audio stream of a file, the first one unless selected
+type audioProbe struct {
+	FormatName string
+	// Stream is the position of the stream among audio streams of the file
+	Stream     int
+	Codec      string
+	SampleRate int
+	Channels   int
+	// BitRate is in bits per second, zero when unknown
+	BitRate int
+}
+
+type concatPlan struct {
+	method     concatMethod
+	audioCodec string
+	// bitrate is only set when re-encoding into a lossy codec
+	bitrate string
+	// sampleRate and channels are common parameters for the concat filter
+	sampleRate int
+	channels   int
+}
+
+func (conv *FFMpegMediaProcessor) Concatenate(ctx context.Context, filepaths []string, opts service.ConcatenateOptions) (string, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.Concatenate",
+		trace.WithAttributes(
+			attribute.Int("files.count", len(filepaths)),
+			attribute.String("container", opts.Container),
+			attribute.String("audio_codec", opts.AudioCodec),
+		),
+	)
+	defer span.End()
+
+	if len(filepaths) == 0 {
+		return "", fmt.Errorf("filepaths cannot be empty")
+	}
+
+	errCtx := oops.With("opts", opts, "filepaths", filepaths)
+	logAttrs := []any{slog.Any("opts", opts), slog.Any("filepaths", filepaths)}
+	fail := func(err error) (string, error) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+
+	span.SetAttributes(attribute.String("concat.engine", cmp.Or(opts.Engine, service.ConcatEngineFFmpeg)))
+	switch opts.Engine {
+	case "", service.ConcatEngineFFmpeg:
+	case service.ConcatEngineNative:
+		resultFilepath, err := conv.concatenateNative(ctx, span, filepaths, opts)
+		if err != nil {
+			return fail(err)
+		}
+		return resultFilepath, nil
+	default:
+		return fail(errCtx.Errorf("unknown concatenation engine: %s", opts.Engine))
+	}
+
+	container, err := service.LookupContainer(opts.Container)
+	if err != nil {
+		return fail(errCtx.Wrap(err))
+	}
+
+	infos := make([]*service.MediaInfo, len(filepaths))
+	videos := 0
+	for i, fp := range filepaths {
+		if infos[i], err = conv.probe(ctx, fp); err != nil {
+			return fail(errCtx.Wrapf(err, "failed to probe %s", fp))
+		}
+		if infos[i].VideoStream() != nil {
+			videos++
+		}
+	}
+
+	// video containers get videos when every part has one, audio of audio-only parts goes anywhere
+	var method concatMethod
+	var planString string
+	var buildArgs func(listFilepath, output string) []string
+	if container.DefaultVideoCodec != "" && videos > 0 {
+		if videos < len(filepaths) {
+			return fail(errCtx.Errorf("%d of %d parts have no video", len(filepaths)-videos, len(filepaths)))
+		}
+		parts := make([]videoPart, len(infos))
+		for i, info := range infos {
+			if parts[i], err = videoPartOf(info, opts.AudioStream); err != nil {
+				return fail(errCtx.Wrapf(err, "failed to probe %s", filepaths[i]))
+			}
+		}
+		plan, err := planVideoConcat(parts, container, opts)
+		if err != nil {
+			return fail(errCtx.Wrap(err))
+		}
+		method, planString = plan.method, fmt.Sprintf("%+v", plan)
+		buildArgs = func(listFilepath, output string) []string {
+			return videoConcatArgs(plan, parts, filepaths, listFilepath, output)
+		}
+	} else {
+		probes := make([]audioProbe, len(infos))
+		for i, info := range infos {
+			if probes[i], err = audioProbeOf(info, service.AudioStreamSelector{}); err != nil {
+				return fail(errCtx.Wrapf(err, "failed to probe %s", filepaths[i]))
+			}
+		}
+		plan := planConcat(probes, container, opts.AudioCodec)
+		method, planString = plan.method, fmt.Sprintf("%+v", plan)
+		buildArgs = func(listFilepath, output string) []string {
+			return concatArgs(plan, filepaths, listFilepath, output)
+		}
+	}
+	errCtx = errCtx.With("method", method, "plan", planString)
+	logAttrs = append(logAttrs, slog.String("method", string(method)), slog.String("plan", planString))
+	span.SetAttributes(attribute.String("concat.method", string(method)), attribute.Bool("concat.video", videos > 0))
+
+	file, err := os.CreateTemp("", "*"+container.Ext)
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to create temp file"))
+	}
+	_ = file.Close()
+	resultFilepath := file.Name()
+	errCtx = errCtx.With("resultFilepath", resultFilepath)
+	logAttrs = append(logAttrs, slog.String("resultFilepath", resultFilepath))
+	span.SetAttributes(attribute.String("result.filepath", resultFilepath))
+
+	var listFilepath string
+	if method == concatDemuxer {
+		listFilepath, err = writeConcatList(filepaths)
+		if err != nil {
+			return fail(errCtx.Wrapf(err, "failed to write concat list"))
+		}
+		defer func() { _ = os.Remove(listFilepath) }()
+	}
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", buildArgs(listFilepath, resultFilepath)...)
+	errCtx = errCtx.With("cmd", cmd.String())
+	logAttrs = append(logAttrs, slog.String("cmd", cmd.String()))
+
+	conv.log.Debug("running ffmpeg", logAttrs...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		_ = os.Remove(resultFilepath)
+		return fail(errCtx.With("output", string(output)).Wrapf(err, "failed to run ffmpeg"))
+	}
+	conv.log.Debug("ffmpeg finished successfully", logAttrs...)
+
+	return resultFilepath, nil
+}
+
+// planConcat picks the cheapest way to concatenate the files that still produces a valid result
+func planConcat(probes []audioProbe, container service.Container, audioCodec string) concatPlan {
+	first := probes[0]
+	identical := true
+	for _, p := range probes[1:] {
+		if p.Codec != first.Codec || p.SampleRate != first.SampleRate || p.Channels != first.Channels {
+			identical = false
+			break
+		}
+	}
+
+	if audioCodec == "" || audioCodec == "copy" {
+		audioCodec = "copy"
+		if !identical || !containerHolds(container.Name, first.Codec) {
+			audioCodec = container.DefaultAudioCodec
+		}
+	}
+
+	plan := concatPlan{audioCodec: audioCodec}
+	if !losslessCodecs[audioCodec] {
+		// match the source quality, otherwise ffmpeg uses its default bitrate (128kbps for MP3),
+		// which inflates low-bitrate audiobooks. Bitrates of lossless sources say nothing about quality.
+		bitrate := 0
+		for _, p := range probes {
+			if !losslessCodecs[p.Codec] && !strings.HasPrefix(p.Codec, "pcm_") {
+				bitrate = max(bitrate, p.BitRate)
+			}
+		}
+		if bitrate > 0 {
+			plan.bitrate = strconv.Itoa(bitrate)
+		}
+	}
+
+	switch {
+	case !identical:
+		plan.method = concatFilter
+		for _, p := range probes {
+			plan.sampleRate = max(plan.sampleRate, p.SampleRate)
+			plan.channels = max(plan.channels, p.Channels)
+		}
+		if plan.sampleRate == 0 || plan.channels == 0 {
+			plan.sampleRate, plan.channels = 44100, 2
+		}
+		plan.sampleRate, plan.channels = encoderLimits(audioCodec, plan.sampleRate, plan.channels)
+	case allFormats(probes, "mp3") && container.Name == "mp3":
+		plan.method = concatProtocol
+	default:
+		plan.method = concatDemuxer
+	}
+	return plan
+}
+
+// encoderLimits clamps stream parameters to what the encoder supports
+func encoderLimits(audioCodec string, sampleRate int, channels int) (int, int) {
+	switch audioCodec {
+	case "libopus":
+		// opus always works at 48kHz internally
+		return 48000, channels
+	case "libmp3lame":
+		return min(sampleRate, 48000), min(channels, 2)
+	}
+	return sampleRate, channels
+}
+
+func containerHolds(container string, codec string) bool {
+	for _, c := range containerCodecs[container] {
+		if c == codec {
+			return true
+		}
+	}
+	return false
+}
+
+func allFormats(probes []audioProbe, formatName string) bool {
+	for _, p := range probes {
+		if p.FormatName != formatName {
+			return false
+		}
+	}
+	return true
+}
+
+// concatArgs map the first audio stream of every input, like the one that was probed.
+// Video, subtitles and other audio streams are dropped.
+func concatArgs(plan concatPlan, inputs []string, listFilepath string, output string) []string {
+	args := []string{"-y"}
+	switch plan.method {
+	case concatProtocol:
+		args = append(args, "-i", "concat:"+strings.Join(inputs, "|"), "-map", "0:a:0")
+	case concatDemuxer:
+		args = append(args, "-f", "concat", "-safe", "0", "-i", listFilepath, "-map", "0:a:0")
+	case concatFilter:
+		var filter strings.Builder
+		for i, input := range inputs {
+			args = append(args, "-i", input)
+			filter.WriteString(fmt.Sprintf("[%d:a:0]aformat=sample_rates=%d:channel_layouts=%s[a%d];",
+				i, plan.sampleRate, channelLayout(plan.channels), i))
+		}
+		for i := range inputs {
+			filter.WriteString(fmt.Sprintf("[a%d]", i))
+		}
+		filter.WriteString(fmt.Sprintf("concat=n=%d:v=0:a=1[out]", len(inputs)))
+		args = append(args, "-filter_complex", filter.String(), "-map", "[out]")
+	}
+	args = append(args, "-c:a", plan.audioCodec)
+	if plan.bitrate != "" {
+		args = append(args, "-b:a", plan.bitrate)
+	}
+	return append(args, output)
+}
+
+func channelLayout(channels int) string {
+	switch channels {
+	case 1:
+		return "mono"
+	case 2:
+		return "stereo"
+	default:
+		return fmt.Sprintf("%dc", channels)
+	}
+}
+
+// writeConcatList writes a list of files for the concat demuxer,
+// see https://ffmpeg.org/ffmpeg-formats.html#concat-1
+func writeConcatList(filepaths []string) (string, error) {
+	file, err := os.CreateTemp("", "*.ffconcat")
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("ffconcat version 1.0\n")
+	for _, fp := range filepaths {
+		b.WriteString("file '" + strings.ReplaceAll(fp, "'", `'\''`) + "'\n")
+	}
+	if _, err := file.WriteString(b.String()); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), file.Close()
+}
+
+func (conv *FFMpegMediaProcessor) probeAudio(ctx context.Context, filepath string) (audioProbe, error) {
+	return conv.probeAudioStream(ctx, filepath, service.AudioStreamSelector{})
+}
+
+func (conv *FFMpegMediaProcessor) probeAudioStream(ctx context.Context, filepath string, sel service.AudioStreamSelector) (audioProbe, error) {
+	info, err := conv.probe(ctx, filepath)
+	if err != nil {
+		return audioProbe{}, err
+	}
+	probe, err := audioProbeOf(info, sel)
+	if err != nil {
+		return audioProbe{}, oops.With("filepath", filepath).Wrap(err)
+	}
+	return probe, nil
+}
@@ -0,0 +1,63 @@
+package media_processor
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/dir01/mediary/mp3"
+	"github.com/dir01/mediary/service"
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// concatenateNative joins MP3 frames of the files without ffmpeg, leaving their tags and Xing frames out
+func (conv *FFMpegMediaProcessor) concatenateNative(ctx context.Context, span trace.Span, filepaths []string, opts service.ConcatenateOptions) (string, error) {
+	errCtx := oops.With("opts", opts, "filepaths", filepaths)
+	logAttrs := []any{slog.Any("opts", opts), slog.Any("filepaths", filepaths)}
+	if (opts.Container != "" && opts.Container != "mp3") || (opts.AudioCodec != "" && opts.AudioCodec != "copy") {
+		return "", errCtx.Errorf("%s engine only concatenates mp3 without re-encoding", service.ConcatEngineNative)
+	}
+
+	inputs := make([]io.Reader, len(filepaths))
+	for i, fp := range filepaths {
+		f, err := os.Open(fp)
+		if err != nil {
+			return "", errCtx.Wrapf(err, "failed to open %s", fp)
+		}
+		defer func() { _ = f.Close() }()
+		inputs[i] = f
+	}
+
+	file, err := os.CreateTemp("", "*.mp3")
+	if err != nil {
+		return "", errCtx.Wrapf(err, "failed to create temp file")
+	}
+	defer func() { _ = file.Close() }()
+	resultFilepath := file.Name()
+	errCtx = errCtx.With("resultFilepath", resultFilepath)
+	logAttrs = append(logAttrs, slog.String("resultFilepath", resultFilepath))
+	span.SetAttributes(attribute.String("result.filepath", resultFilepath))
+
+	conv.log.Debug("concatenating mp3 frames", logAttrs...)
+	stats, err := mp3.Concatenate(ctx, file, inputs)
+	if err == nil {
+		err = file.Close()
+	}
+	if err != nil {
+		_ = os.Remove(resultFilepath)
+		return "", errCtx.Wrapf(err, "failed to concatenate mp3 frames")
+	}
+	span.SetAttributes(
+		attribute.Int("concat.frames", stats.Frames),
+		attribute.Int64("concat.skipped_bytes", stats.Skipped),
+	)
+	conv.log.Debug("mp3 frames concatenated", append(logAttrs,
+		slog.Int("frames", stats.Frames),
+		slog.Duration("duration", stats.Duration),
+		slog.Int64("skippedBytes", stats.Skipped),
+	)...)
+	return resultFilepath, nil
+}
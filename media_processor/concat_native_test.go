@@ -0,0 +1,121 @@
+package media_processor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	id3v2 "github.com/bogem/id3v2/v2"
+	"github.com/dir01/mediary/service"
+)
+
+// writeSilentMP3 writes a tagged mp3 of silent 128 kbps frames, which need no encoder
+func writeSilentMP3(t *testing.T, frames int) string {
+	t.Helper()
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x64})
+
+	tag := id3v2.NewEmptyTag()
+	tag.SetTitle("part")
+	var buf bytes.Buffer
+	if _, err := tag.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	buf.Write(bytes.Repeat(frame, frames))
+
+	fp := filepath.Join(t.TempDir(), "part.mp3")
+	if err := os.WriteFile(fp, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return fp
+}
+
+func TestConcatenate_NativeEngine(t *testing.T) {
+	processor := &FFMpegMediaProcessor{log: testLogger}
+	files := []string{writeSilentMP3(t, 10), writeSilentMP3(t, 20)}
+
+	resultPath, err := processor.Concatenate(context.Background(), files,
+		service.ConcatenateOptions{Container: "mp3", AudioCodec: "copy", Engine: service.ConcatEngineNative})
+	if err != nil {
+		t.Fatalf("Concatenate failed: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Remove(resultPath) })
+
+	data, err := os.ReadFile(resultPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// tags of the inputs are left out, an Info frame of 64 kbps comes first
+	if len(data) != 208+30*417 {
+		t.Fatalf("result is %d bytes, want %d", len(data), 208+30*417)
+	}
+	if info := string(data[36:40]); info != "Info" {
+		t.Errorf("result starts with %q instead of an Info frame", info)
+	}
+
+	// chapters are written the same way as for ffmpeg results
+	chapters := []service.Chapter{{Title: "One", EndTime: 1}, {Title: "Two", StartTime: 1, EndTime: 2}}
+	if err := processor.AddChapterTags(context.Background(), resultPath, chapters); err != nil {
+		t.Fatalf("AddChapterTags failed: %v", err)
+	}
+	tag, err := id3v2.Open(resultPath, id3v2.Options{Parse: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = tag.Close() }()
+	if n := len(tag.GetFrames(tag.CommonID("Chapters"))); n != len(chapters) {
+		t.Errorf("%d chapters, want %d", n, len(chapters))
+	}
+}
+
+func TestConcatenate_NativeEngineRejectsReencoding(t *testing.T) {
+	processor := &FFMpegMediaProcessor{log: testLogger}
+	files := []string{writeSilentMP3(t, 1), writeSilentMP3(t, 1)}
+
+	for _, opts := range []service.ConcatenateOptions{
+		{Container: "m4a", Engine: service.ConcatEngineNative},
+		{Container: "mp3", AudioCodec: "libmp3lame", Engine: service.ConcatEngineNative},
+		{Container: "mp3", Engine: "sox"},
+	} {
+		if _, err := processor.Concatenate(context.Background(), files, opts); err == nil {
+			t.Errorf("expected an error for %+v", opts)
+		}
+	}
+}
+
+// BenchmarkConcatenate compares the engines on the most common job, joining mp3s without re-encoding
+func BenchmarkConcatenate(b *testing.B) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		b.Skip("ffmpeg is not installed")
+	}
+	files := make([]string, 10)
+	for i := range files {
+		files[i] = filepath.Join(b.TempDir(), fmt.Sprintf("part%d.mp3", i))
+		cmd := exec.Command("ffmpeg", "-y",
+			"-f", "lavfi", "-i", "sine=frequency=440:duration=600",
+			"-ar", "44100", "-ac", "1", "-b:a", "64k",
+			files[i],
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			b.Fatalf("ffmpeg failed to generate test MP3: %v\n%s", err, out)
+		}
+	}
+
+	processor := &FFMpegMediaProcessor{log: testLogger}
+	for _, engine := range []string{service.ConcatEngineFFmpeg, service.ConcatEngineNative} {
+		b.Run(engine, func(b *testing.B) {
+			for b.Loop() {
+				resultPath, err := processor.Concatenate(context.Background(), files,
+					service.ConcatenateOptions{Container: "mp3", AudioCodec: "copy", Engine: engine})
+				if err != nil {
+					b.Fatal(err)
+				}
+				_ = os.Remove(resultPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package media_processor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dir01/mediary/service"
+)
+
+func TestPlanConcat(t *testing.T) {
+	mp3 := audioProbe{FormatName: "mp3", Codec: "mp3", SampleRate: 44100, Channels: 1, BitRate: 64000}
+	m4a := audioProbe{FormatName: "mov,mp4,m4a,3gp,3g2,mj2", Codec: "aac", SampleRate: 22050, Channels: 1, BitRate: 48000}
+	flac := audioProbe{FormatName: "flac", Codec: "flac", SampleRate: 96000, Channels: 2, BitRate: 2500000}
+
+	for _, tc := range []struct {
+		name       string
+		probes     []audioProbe
+		container  string
+		audioCodec string
+		want       concatPlan
+	}{
+		{
+			name:      "identical mp3s are glued as is",
+			probes:    []audioProbe{mp3, mp3},
+			container: "mp3", audioCodec: "copy",
+			want: concatPlan{method: concatProtocol, audioCodec: "copy"},
+		},
+		{
+			name:      "identical mp3s re-encoded keep their bitrate",
+			probes:    []audioProbe{mp3, mp3},
+			container: "mp3", audioCodec: "libmp3lame",
+			want: concatPlan{method: concatProtocol, audioCodec: "libmp3lame", bitrate: "64000"},
+		},
+		{
+			name:      "identical m4as go through the demuxer",
+			probes:    []audioProbe{m4a, m4a},
+			container: "m4a", audioCodec: "copy",
+			want: concatPlan{method: concatDemuxer, audioCodec: "copy"},
+		},
+		{
+			name:      "identical streams that don't fit the container are re-encoded",
+			probes:    []audioProbe{m4a, m4a},
+			container: "mp3",
+			want:      concatPlan{method: concatDemuxer, audioCodec: "libmp3lame", bitrate: "48000"},
+		},
+		{
+			name:      "mixed inputs are resampled, ignoring bitrate of lossless ones",
+			probes:    []audioProbe{mp3, m4a, flac},
+			container: "mp3", audioCodec: "copy",
+			want: concatPlan{method: concatFilter, audioCodec: "libmp3lame", bitrate: "64000", sampleRate: 48000, channels: 2},
+		},
+		{
+			name:      "mixed inputs into a lossless container",
+			probes:    []audioProbe{m4a, flac},
+			container: "flac",
+			want:      concatPlan{method: concatFilter, audioCodec: "flac", sampleRate: 96000, channels: 2},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			container, err := service.LookupContainer(tc.container)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := planConcat(tc.probes, container, tc.audioCodec); got != tc.want {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestConcatArgs(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		plan concatPlan
+		want []string
+	}{
+		{
+			name: "protocol",
+			plan: concatPlan{method: concatProtocol, audioCodec: "copy"},
+			want: []string{"-y", "-i", "concat:a.mp3|b.mp3", "-map", "0:a:0", "-c:a", "copy", "out"},
+		},
+		{
+			name: "demuxer",
+			plan: concatPlan{method: concatDemuxer, audioCodec: "copy"},
+			want: []string{"-y", "-f", "concat", "-safe", "0", "-i", "list", "-map", "0:a:0", "-c:a", "copy", "out"},
+		},
+		{
+			name: "filter",
+			plan: concatPlan{method: concatFilter, audioCodec: "libmp3lame", bitrate: "64000", sampleRate: 44100, channels: 1},
+			want: []string{
+				"-y", "-i", "a.mp3", "-i", "b.mp3",
+				"-filter_complex", "[0:a:0]aformat=sample_rates=44100:channel_layouts=mono[a0];" +
+					"[1:a:0]aformat=sample_rates=44100:channel_layouts=mono[a1];[a0][a1]concat=n=2:v=0:a=1[out]",
+				"-map", "[out]", "-c:a", "libmp3lame", "-b:a", "64000", "out",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := concatArgs(tc.plan, []string{"a.mp3", "b.mp3"}, "list", "out")
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q\nwant %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package media_processor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dir01/mediary/service"
+)
+
+// videoContainerCodecs are video codecs (as reported by ffprobe) that a container can hold without re-encoding
+var videoContainerCodecs = map[string][]string{
+	"mp4":  {"h264", "hevc", "av1", "mpeg4"},
+	"mkv":  {"h264", "hevc", "av1", "vp8", "vp9", "mpeg4", "mpeg2video"},
+	"webm": {"vp8", "vp9", "av1"},
+}
+
+// videoPart is what video concatenation needs to know about a part: its video and the selected audio stream
+type videoPart struct {
+	Codec     string
+	Width     int
+	Height    int
+	FrameRate float64
+	// Audio is only set when HasAudio is, parts of screen recordings may be silent
+	Audio    audioProbe
+	HasAudio bool
+}
+
+type videoConcatPlan struct {
+	// method is either a demuxer, when parts share every parameter, or a filter
+	method     concatMethod
+	videoCodec string
+	crf        int
+	// width, height and frameRate are common parameters for the concat filter, frameRate is zero when unknown
+	width     int
+	height    int
+	frameRate float64
+	// audio is the plan of the audio streams, unless parts are silent
+	audio *concatPlan
+}
+
+func videoPartOf(info *service.MediaInfo, sel service.AudioStreamSelector) (videoPart, error) {
+	video := info.VideoStream()
+	if video == nil {
+		return videoPart{}, fmt.Errorf("no video stream")
+	}
+	part := videoPart{Codec: video.Codec, Width: video.Width, Height: video.Height, FrameRate: video.FrameRate}
+	if info.AudioStream() == nil {
+		return part, nil
+	}
+	audio, err := audioProbeOf(info, sel)
+	if err != nil {
+		return videoPart{}, err
+	}
+	part.Audio, part.HasAudio = audio, true
+	return part, nil
+}
+
+// planVideoConcat stream-copies parts that share codecs, resolution and frame rate,
+// and scales the rest to a common size with the concat filter
+func planVideoConcat(parts []videoPart, container service.Container, opts service.ConcatenateOptions) (videoConcatPlan, error) {
+	first := parts[0]
+	identical := opts.Video.Width == 0
+	var probes []audioProbe
+	for _, p := range parts {
+		if p.HasAudio != first.HasAudio {
+			return videoConcatPlan{}, fmt.Errorf("some parts have no audio, they can't be concatenated with the rest")
+		}
+		if p.Codec != first.Codec || p.Width != first.Width || p.Height != first.Height || p.FrameRate != first.FrameRate ||
+			p.Audio.Stream != first.Audio.Stream {
+			identical = false
+		}
+		if p.HasAudio {
+			probes = append(probes, p.Audio)
+		}
+	}
+
+	plan := videoConcatPlan{method: concatDemuxer, videoCodec: opts.Video.Codec, crf: opts.Video.CRF}
+	if len(probes) > 0 {
+		audio := planConcat(probes, container, opts.AudioCodec)
+		if audio.method == concatFilter {
+			identical = false
+		}
+		plan.audio = &audio
+	}
+	if !identical {
+		plan.method = concatFilter
+	}
+
+	if plan.videoCodec == "" || plan.videoCodec == "copy" {
+		plan.videoCodec = "copy"
+		if plan.method == concatFilter || opts.Video.Reencodes() || !videoContainerHolds(container.Name, first.Codec) {
+			plan.videoCodec = container.DefaultVideoCodec
+		}
+	}
+	if plan.method == concatDemuxer {
+		return plan, nil
+	}
+
+	// filtered streams are decoded, so nothing can be copied
+	if plan.audio != nil && plan.audio.audioCodec == "copy" {
+		audio := planConcat(probes, container, container.DefaultAudioCodec)
+		plan.audio = &audio
+	}
+	if plan.audio != nil && plan.audio.sampleRate == 0 {
+		plan.audio.sampleRate, plan.audio.channels = encoderLimits(plan.audio.audioCodec, first.Audio.SampleRate, first.Audio.Channels)
+	}
+	plan.width, plan.height = opts.Video.Width, opts.Video.Height
+	for _, p := range parts {
+		if opts.Video.Width == 0 && p.Width*p.Height > plan.width*plan.height {
+			plan.width, plan.height = p.Width, p.Height
+		}
+		plan.frameRate = max(plan.frameRate, p.FrameRate)
+	}
+	// most encoders only take even sizes, because of chroma subsampling
+	plan.width, plan.height = plan.width&^1, plan.height&^1
+	return plan, nil
+}
+
+func videoContainerHolds(container string, codec string) bool {
+	for _, c := range videoContainerCodecs[container] {
+		if c == codec {
+			return true
+		}
+	}
+	return false
+}
+
+// videoConcatArgs map the video (but not embedded pictures) and the selected audio stream of every part.
+// Subtitles and other streams are dropped.
+func videoConcatArgs(plan videoConcatPlan, parts []videoPart, inputs []string, listFilepath string, output string) []string {
+	args := []string{"-y"}
+	switch plan.method {
+	case concatDemuxer:
+		args = append(args, "-f", "concat", "-safe", "0", "-i", listFilepath, "-map", "0:V:0")
+		if plan.audio != nil {
+			args = append(args, "-map", fmt.Sprintf("0:a:%d", parts[0].Audio.Stream))
+		}
+	case concatFilter:
+		var filter strings.Builder
+		for i, input := range inputs {
+			args = append(args, "-i", input)
+			// letterbox parts of other aspect ratios instead of stretching them
+			filter.WriteString(fmt.Sprintf(
+				"[%d:V:0]scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2,setsar=1",
+				i, plan.width, plan.height, plan.width, plan.height))
+			if plan.frameRate > 0 {
+				filter.WriteString(",fps=" + formatFloat(plan.frameRate))
+			}
+			filter.WriteString(fmt.Sprintf(",format=yuv420p[v%d];", i))
+			if plan.audio != nil {
+				filter.WriteString(fmt.Sprintf("[%d:a:%d]aformat=sample_rates=%d:channel_layouts=%s[a%d];",
+					i, parts[i].Audio.Stream, plan.audio.sampleRate, channelLayout(plan.audio.channels), i))
+			}
+		}
+		for i := range inputs {
+			filter.WriteString(fmt.Sprintf("[v%d]", i))
+			if plan.audio != nil {
+				filter.WriteString(fmt.Sprintf("[a%d]", i))
+			}
+		}
+		if plan.audio != nil {
+			filter.WriteString(fmt.Sprintf("concat=n=%d:v=1:a=1[outv][outa]", len(inputs)))
+			args = append(args, "-filter_complex", filter.String(), "-map", "[outv]", "-map", "[outa]")
+		} else {
+			filter.WriteString(fmt.Sprintf("concat=n=%d:v=1:a=0[outv]", len(inputs)))
+			args = append(args, "-filter_complex", filter.String(), "-map", "[outv]")
+		}
+	}
+
+	args = append(args, "-c:v", plan.videoCodec)
+	if plan.crf != 0 {
+		args = append(args, "-crf", strconv.Itoa(plan.crf))
+		if plan.videoCodec == "libvpx-vp9" {
+			// otherwise VP9 caps the quality with its default bitrate
+			args = append(args, "-b:v", "0")
+		}
+	}
+	if plan.audio != nil {
+		args = append(args, "-c:a", plan.audio.audioCodec)
+		if plan.audio.bitrate != "" {
+			args = append(args, "-b:a", plan.audio.bitrate)
+		}
+	}
+	if strings.HasSuffix(output, ".mp4") {
+		// the index goes first, so that players start before the whole file is downloaded
+		args = append(args, "-movflags", "+faststart")
+	}
+	return append(args, output)
+}
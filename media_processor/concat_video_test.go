@@ -0,0 +1,141 @@
+package media_processor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dir01/mediary/service"
+)
+
+func TestPlanVideoConcat(t *testing.T) {
+	aac := audioProbe{Codec: "aac", SampleRate: 48000, Channels: 2, BitRate: 128000}
+	hd := videoPart{Codec: "h264", Width: 1280, Height: 720, FrameRate: 25, Audio: aac, HasAudio: true}
+	fullHD := videoPart{Codec: "h264", Width: 1920, Height: 1080, FrameRate: 30, Audio: aac, HasAudio: true}
+	vp9 := videoPart{Codec: "vp9", Width: 1280, Height: 720, FrameRate: 25}
+
+	for _, tc := range []struct {
+		name      string
+		parts     []videoPart
+		container string
+		opts      service.ConcatenateOptions
+		want      videoConcatPlan
+		wantErr   bool
+	}{
+		{
+			name:      "identical parts are copied",
+			parts:     []videoPart{hd, hd},
+			container: "mp4",
+			want: videoConcatPlan{method: concatDemuxer, videoCodec: "copy",
+				audio: &concatPlan{method: concatDemuxer, audioCodec: "copy"}},
+		},
+		{
+			name:      "identical parts that don't fit the container are re-encoded",
+			parts:     []videoPart{vp9, vp9},
+			container: "mp4",
+			want:      videoConcatPlan{method: concatDemuxer, videoCodec: "libx264"},
+		},
+		{
+			name:      "crf re-encodes identical parts without filtering",
+			parts:     []videoPart{hd, hd},
+			container: "mkv",
+			opts:      service.ConcatenateOptions{Video: service.VideoOptions{CRF: 23}},
+			want: videoConcatPlan{method: concatDemuxer, videoCodec: "libx264", crf: 23,
+				audio: &concatPlan{method: concatDemuxer, audioCodec: "copy"}},
+		},
+		{
+			name:      "different resolutions are scaled to the largest one",
+			parts:     []videoPart{hd, fullHD},
+			container: "mp4",
+			want: videoConcatPlan{method: concatFilter, videoCodec: "libx264", width: 1920, height: 1080, frameRate: 30,
+				audio: &concatPlan{method: concatDemuxer, audioCodec: "aac", bitrate: "128000", sampleRate: 48000, channels: 2}},
+		},
+		{
+			name:      "requested resolution is used for identical parts",
+			parts:     []videoPart{fullHD, fullHD},
+			container: "webm",
+			opts:      service.ConcatenateOptions{Video: service.VideoOptions{Codec: "libvpx-vp9", Width: 640, Height: 360}},
+			want: videoConcatPlan{method: concatFilter, videoCodec: "libvpx-vp9", width: 640, height: 360, frameRate: 30,
+				audio: &concatPlan{method: concatDemuxer, audioCodec: "libopus", bitrate: "128000", sampleRate: 48000, channels: 2}},
+		},
+		{
+			name:      "silent parts can't go with sound ones",
+			parts:     []videoPart{hd, vp9},
+			container: "mkv",
+			wantErr:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			container, err := service.LookupContainer(tc.container)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.opts.Container = tc.container
+			got, err := planVideoConcat(tc.parts, container, tc.opts)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("planVideoConcat() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v (audio %+v), want %+v (audio %+v)", got, got.audio, tc.want, tc.want.audio)
+			}
+		})
+	}
+}
+
+func TestVideoConcatArgs(t *testing.T) {
+	stereo := audioProbe{Codec: "aac", Stream: 1}
+	parts := []videoPart{{Audio: stereo, HasAudio: true}, {Audio: audioProbe{Codec: "aac"}, HasAudio: true}}
+
+	for _, tc := range []struct {
+		name   string
+		plan   videoConcatPlan
+		parts  []videoPart
+		output string
+		want   []string
+	}{
+		{
+			name:   "demuxer",
+			plan:   videoConcatPlan{method: concatDemuxer, videoCodec: "copy", audio: &concatPlan{audioCodec: "copy"}},
+			parts:  parts,
+			output: "out.mkv",
+			want: []string{"-y", "-f", "concat", "-safe", "0", "-i", "list", "-map", "0:V:0", "-map", "0:a:1",
+				"-c:v", "copy", "-c:a", "copy", "out.mkv"},
+		},
+		{
+			name: "filter",
+			plan: videoConcatPlan{method: concatFilter, videoCodec: "libx264", crf: 20, width: 1280, height: 720, frameRate: 25,
+				audio: &concatPlan{audioCodec: "aac", bitrate: "128000", sampleRate: 48000, channels: 2}},
+			parts:  parts,
+			output: "out.mp4",
+			want: []string{
+				"-y", "-i", "a.mp4", "-i", "b.mkv",
+				"-filter_complex", "[0:V:0]scale=1280:720:force_original_aspect_ratio=decrease,pad=1280:720:(ow-iw)/2:(oh-ih)/2," +
+					"setsar=1,fps=25,format=yuv420p[v0];[0:a:1]aformat=sample_rates=48000:channel_layouts=stereo[a0];" +
+					"[1:V:0]scale=1280:720:force_original_aspect_ratio=decrease,pad=1280:720:(ow-iw)/2:(oh-ih)/2," +
+					"setsar=1,fps=25,format=yuv420p[v1];[1:a:0]aformat=sample_rates=48000:channel_layouts=stereo[a1];" +
+					"[v0][a0][v1][a1]concat=n=2:v=1:a=1[outv][outa]",
+				"-map", "[outv]", "-map", "[outa]", "-c:v", "libx264", "-crf", "20", "-c:a", "aac", "-b:a", "128000",
+				"-movflags", "+faststart", "out.mp4",
+			},
+		},
+		{
+			name:   "silent vp9",
+			plan:   videoConcatPlan{method: concatFilter, videoCodec: "libvpx-vp9", crf: 31, width: 640, height: 360},
+			parts:  []videoPart{{}, {}},
+			output: "out.webm",
+			want: []string{
+				"-y", "-i", "a.mp4", "-i", "b.mkv",
+				"-filter_complex", "[0:V:0]scale=640:360:force_original_aspect_ratio=decrease,pad=640:360:(ow-iw)/2:(oh-ih)/2," +
+					"setsar=1,format=yuv420p[v0];[1:V:0]scale=640:360:force_original_aspect_ratio=decrease," +
+					"pad=640:360:(ow-iw)/2:(oh-ih)/2,setsar=1,format=yuv420p[v1];[v0][v1]concat=n=2:v=1:a=0[outv]",
+				"-map", "[outv]", "-c:v", "libvpx-vp9", "-crf", "31", "-b:v", "0", "out.webm",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := videoConcatArgs(tc.plan, tc.parts, []string{"a.mp4", "b.mkv"}, "list", tc.output)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q\nwant %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package media_processor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/dir01/mediary/service"
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// audioContainers are audio-only containers in order of preference, when the stream fits more than one
+var audioContainers = []string{"mp3", "m4a", "opus", "ogg", "flac", "wav"}
+
+// ExtractAudio maps the selected audio stream alone, so video, subtitles and other audio streams are dropped.
+// Tags and chapters are kept, since ffmpeg takes them from the only input.
+func (conv *FFMpegMediaProcessor) ExtractAudio(ctx context.Context, fp string, opts service.ExtractAudioOptions) (string, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.ExtractAudio",
+		trace.WithAttributes(
+			attribute.String("filepath", fp),
+			attribute.String("container", opts.Container),
+			attribute.String("audio_codec", opts.AudioCodec),
+		),
+	)
+	defer span.End()
+
+	errCtx := oops.With("filepath", fp, "opts", opts)
+	fail := func(err error) (string, error) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+
+	probe, err := conv.probeAudioStream(ctx, fp, opts.AudioStream)
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to probe file"))
+	}
+	containerName := opts.Container
+	if containerName == "" {
+		containerName = extractContainer(probe.Codec)
+	}
+	container, err := service.LookupContainer(containerName)
+	if err != nil {
+		return fail(errCtx.Wrap(err))
+	}
+	if container.DefaultVideoCodec != "" {
+		return fail(errCtx.Errorf("%s is not an audio container", container.Name))
+	}
+	span.SetAttributes(attribute.Int("audio_stream", probe.Stream), attribute.String("source_codec", probe.Codec))
+
+	file, err := os.CreateTemp("", "*"+container.Ext)
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to create temp file"))
+	}
+	_ = file.Close()
+	resultFilepath := file.Name()
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", extractAudioArgs(fp, resultFilepath, probe, container, opts)...)
+	conv.log.Debug("extracting audio", slog.String("filepath", fp), slog.String("cmd", cmd.String()))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		_ = os.Remove(resultFilepath)
+		return fail(errCtx.With("cmd", cmd.String(), "output", string(output)).Wrapf(err, "failed to run ffmpeg"))
+	}
+	return resultFilepath, nil
+}
+
+// extractContainer picks a container that holds the codec as it is, falling back to mp3
+func extractContainer(codec string) string {
+	for _, c := range audioContainers {
+		if containerHolds(c, codec) {
+			return c
+		}
+	}
+	return "mp3"
+}
+
+func extractAudioArgs(input, output string, probe audioProbe, container service.Container, opts service.ExtractAudioOptions) []string {
+	audioCodec := opts.AudioCodec
+	if audioCodec == "" || audioCodec == "copy" {
+		audioCodec = "copy"
+		if !containerHolds(container.Name, probe.Codec) {
+			audioCodec = container.DefaultAudioCodec
+		}
+	}
+
+	args := []string{
+		"-y", "-hide_banner", "-nostats", "-i", input,
+		"-map", fmt.Sprintf("0:a:%d", probe.Stream), "-c:a", audioCodec,
+	}
+	if audioCodec == "copy" {
+		return append(args, output)
+	}
+
+	if !losslessCodecs[audioCodec] {
+		// like concatenation, re-encoding matches the source quality rather than ffmpeg's default bitrate
+		bitrate := opts.Bitrate
+		if bitrate == "" && probe.BitRate > 0 && !losslessCodecs[probe.Codec] && !strings.HasPrefix(probe.Codec, "pcm_") {
+			bitrate = strconv.Itoa(probe.BitRate)
+		}
+		if bitrate != "" {
+			args = append(args, "-b:a", bitrate)
+		}
+	}
+	// surround tracks of films don't fit MP3, and opus only works at 48kHz
+	if sampleRate, channels := encoderLimits(audioCodec, probe.SampleRate, probe.Channels); sampleRate != probe.SampleRate || channels != probe.Channels {
+		args = append(args, "-ar", strconv.Itoa(sampleRate), "-ac", strconv.Itoa(channels))
+	}
+	return append(args, output)
+}
@@ -0,0 +1,57 @@
+package media_processor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dir01/mediary/service"
+)
+
+func TestExtractContainer(t *testing.T) {
+	for codec, want := range map[string]string{"aac": "m4a", "opus": "opus", "vorbis": "ogg", "mp3": "mp3", "ac3": "mp3"} {
+		if got := extractContainer(codec); got != want {
+			t.Errorf("%s: got %s, want %s", codec, got, want)
+		}
+	}
+}
+
+func TestExtractAudioArgs(t *testing.T) {
+	aac := audioProbe{Stream: 1, Codec: "aac", SampleRate: 48000, Channels: 2, BitRate: 96000}
+	surround := audioProbe{Codec: "ac3", SampleRate: 48000, Channels: 6, BitRate: 384000}
+	for _, tc := range []struct {
+		name      string
+		probe     audioProbe
+		container string
+		opts      service.ExtractAudioOptions
+		want      []string
+	}{
+		{
+			name:  "stream that fits the container is copied",
+			probe: aac, container: "m4a",
+			want: []string{"-y", "-hide_banner", "-nostats", "-i", "in.mkv", "-map", "0:a:1", "-c:a", "copy", "out"},
+		},
+		{
+			name:  "re-encoding keeps the bitrate",
+			probe: aac, container: "mp3",
+			want: []string{"-y", "-hide_banner", "-nostats", "-i", "in.mkv", "-map", "0:a:1", "-c:a", "libmp3lame", "-b:a", "96000", "out"},
+		},
+		{
+			name:  "surround is downmixed for mp3",
+			probe: surround, container: "mp3", opts: service.ExtractAudioOptions{Bitrate: "64k"},
+			want: []string{
+				"-y", "-hide_banner", "-nostats", "-i", "in.mkv", "-map", "0:a:0", "-c:a", "libmp3lame", "-b:a", "64k",
+				"-ar", "48000", "-ac", "2", "out",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			container, err := service.LookupContainer(tc.container)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := extractAudioArgs("in.mkv", "out", tc.probe, container, tc.opts); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q\nwant %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -6,9 +6,6 @@ import (
 	"io"
 	"log/slog"
 	"os"
-	"os/exec"
-	"strconv"
-	"strings"
 	"time"
 
 	id3v2 "github.com/bogem/id3v2/v2"
@@ -29,184 +26,40 @@ type FFMpegMediaProcessor struct {
 }
 
 func (conv *FFMpegMediaProcessor) GetInfo(ctx context.Context, filepath string) (info *service.MediaInfo, err error) {
-	_, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.GetInfo",
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.GetInfo",
 		trace.WithAttributes(attribute.String("filepath", filepath)),
 	)
 	defer span.End()
 
-	info = &service.MediaInfo{}
-
-	if state, err := os.Stat(filepath); err == nil {
-		info.FileLenBytes = state.Size()
-	} else {
+	state, err := os.Stat(filepath)
+	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		return nil, fmt.Errorf("failed to stat file: %w", err)
 	}
 
-	if duration, err := conv.GetDuration(filepath); err == nil {
-		info.Duration = duration
-	} else {
+	if info, err = conv.probe(ctx, filepath); err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		return nil, fmt.Errorf("failed to get duration: %w", err)
+		return nil, fmt.Errorf("failed to probe file: %w", err)
 	}
+	info.FileLenBytes = state.Size()
 
 	span.SetAttributes(
 		attribute.Int64("file.bytes", info.FileLenBytes),
 		attribute.Float64("media.duration_seconds", info.Duration.Seconds()),
+		attribute.String("media.container", info.Container),
+		attribute.Int("media.streams", len(info.Streams)),
 	)
 	return info, nil
 }
 
-func (conv *FFMpegMediaProcessor) Concatenate(ctx context.Context, filepaths []string, audioCodec string) (string, error) {
-	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.Concatenate",
-		trace.WithAttributes(
-			attribute.Int("files.count", len(filepaths)),
-			attribute.String("audio_codec", audioCodec),
-		),
-	)
-	defer span.End()
-
-	if len(filepaths) == 0 {
-		return "", fmt.Errorf("filepaths cannot be empty")
-	}
-
-	firstExtIndex := strings.LastIndex(filepaths[0], ".")
-	if firstExtIndex == -1 {
-		return "", fmt.Errorf("first filepath must include extension")
-	}
-
-	ext := filepaths[0][firstExtIndex:]
-	errCtx := oops.With("ext", ext, "audioCodec", audioCodec, "filepaths", filepaths)
-	logAttrs := []any{
-		slog.String("ext", ext),
-		slog.String("audioCodec", audioCodec),
-		slog.Any("filepaths", filepaths),
-	}
-
-	file, err := os.CreateTemp("", "*"+ext)
-	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-		return "", errCtx.Wrapf(err, "failed to create temp file")
-	}
-	resultFilepath := file.Name()
-	errCtx = errCtx.With("resultFilepath", resultFilepath)
-	logAttrs = append(logAttrs, slog.String("resultFilepath", resultFilepath))
-	span.SetAttributes(attribute.String("result.filepath", resultFilepath))
-
-	args := []string{"-y", "-i", "concat:" + strings.Join(filepaths, "|"), "-acodec", audioCodec}
-
-	// When re-encoding (audioCodec != "copy"), probe the source bitrate and
-	// pass it to ffmpeg so the output matches the source quality. Without this,
-	// ffmpeg uses its default bitrate (128kbps for MP3) which can inflate the output.
-	if audioCodec != "copy" {
-		if bitrate, probeErr := conv.getAudioBitrate(filepaths[0]); probeErr == nil {
-			args = append(args, "-b:a", bitrate)
-			span.SetAttributes(attribute.String("source_bitrate", bitrate))
-			logAttrs = append(logAttrs, slog.String("sourceBitrate", bitrate))
-		} else {
-			conv.log.Warn("failed to probe source bitrate, using ffmpeg default",
-				append(logAttrs, slog.Any("error", probeErr))...)
-		}
-	}
-
-	args = append(args, resultFilepath)
-	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
-	errCtx = errCtx.With("cmd", cmd.String())
-	logAttrs = append(logAttrs, slog.String("cmd", cmd.String()))
-
-	conv.log.Debug("running ffmpeg", logAttrs...)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-		return "", errCtx.With("output", string(output)).Wrapf(err, "failed to run ffmpeg")
-	}
-	conv.log.Debug("ffmpeg finished successfully", logAttrs...)
-
-	return resultFilepath, nil
-}
-
-// getAudioBitrate probes the audio bitrate of the given file using ffprobe.
-// Returns bitrate as a string like "128000" (bits per second).
-func (conv *FFMpegMediaProcessor) getAudioBitrate(filepath string) (string, error) {
-	cmd := exec.Command(
-		"ffprobe",
-		"-v", "error",
-		"-select_streams", "a:0",
-		"-show_entries", "stream=bit_rate",
-		"-of", "default=noprint_wrappers=1:nokey=1",
-		filepath,
-	)
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return "", fmt.Errorf("ffprobe failed: %w", err)
-	}
-
-	bitrate := strings.TrimSpace(string(out))
-	if bitrate == "" || bitrate == "N/A" {
-		return "", fmt.Errorf("ffprobe returned no bitrate for %s", filepath)
-	}
-	return bitrate, nil
-}
-
-func (conv *FFMpegMediaProcessor) ExtractCoverArt(filepath string) (coverArtFilePath string, err error) {
-	errCtx := oops.With("filepath", filepath)
-	coverArtFilePath = filepath + ".jpg"
-	errCtx = errCtx.With("coverArtFilePath", coverArtFilePath)
-
-	cmd := exec.Command("ffmpeg", "-i", filepath, "-map", "0:v", "-map", "-0:V", "-c", "copy", "-y", coverArtFilePath)
-	errCtx = errCtx.With("cmd", cmd.String())
-
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return "", errCtx.With("output", string(out)).Wrapf(err, "failed to run ffmpeg")
-	}
-
-	return coverArtFilePath, nil
-}
-
 func (conv *FFMpegMediaProcessor) GetDuration(filepath string) (time.Duration, error) {
-	cmd := exec.Command(
-		"ffprobe",
-		"-v", "error",
-		"-show_entries", "format=duration",
-		"-of", "default=noprint_wrappers=1:nokey=1",
-		filepath,
-	)
-	errCtx := oops.With("filepath", filepath, "cmd", cmd.String())
-
-	out, err := cmd.CombinedOutput()
-	errCtx = errCtx.With("output", string(out))
+	info, err := conv.probe(context.Background(), filepath)
 	if err != nil {
-		return 0, errCtx.Wrapf(err, "failed to run ffprobe")
+		return 0, err
 	}
-
-	seconds, err := parseDurationOutput(string(out))
-	if err != nil {
-		return 0, errCtx.Wrapf(err, "failed to parse duration from ffprobe output")
-	}
-
-	return time.Duration(seconds * float64(time.Second)), nil
-}
-
-// parseDurationOutput extracts the duration float from ffprobe output.
-// ffprobe may emit warning/error lines (e.g. "[bmp @ 0x...] bad magic number")
-// before the actual value, so we scan lines in reverse for the last parseable float.
-func parseDurationOutput(output string) (float64, error) {
-	lines := strings.Split(strings.TrimSpace(output), "\n")
-	for i := len(lines) - 1; i >= 0; i-- {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
-			continue
-		}
-		if seconds, err := strconv.ParseFloat(line, 64); err == nil {
-			return seconds, nil
-		}
-	}
-	return 0, fmt.Errorf("no valid float found in output: %q", output)
+	return info.Duration, nil
 }
 
 func (conv *FFMpegMediaProcessor) AddChapterTags(ctx context.Context, filepath string, chapters []service.Chapter) error {
@@ -228,7 +81,19 @@ func (conv *FFMpegMediaProcessor) AddChapterTags(ctx context.Context, filepath s
 	defer func() { _ = tag.Close() }()
 
 	tag.SetVersion(4) // ID3v2.4 — supports UTF-8 text encoding natively
+	addChapterFrames(tag, chapters)
+
+	conv.log.Debug("writing ID3 chapter tags", logAttrs...)
+	if err := tag.Save(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return errCtx.Wrapf(err, "failed to save ID3 tags")
+	}
+	return nil
+}
 
+// addChapterFrames adds a CHAP frame per chapter and a CTOC frame listing them
+func addChapterFrames(tag *id3v2.Tag, chapters []service.Chapter) {
 	childIDs := make([]string, 0, len(chapters))
 
 	for i, ch := range chapters {
@@ -250,20 +115,35 @@ func (conv *FFMpegMediaProcessor) AddChapterTags(ctx context.Context, filepath s
 
 	// Add CTOC (Table of Contents) frame — required by the ID3v2 chapters spec
 	// for podcast players to discover and navigate chapters.
+	addTOCFrames(tag, "toc", childIDs)
+}
+
+// maxCTOCEntries is the most entries a single CTOC frame can list, since the count is a single byte
+const maxCTOCEntries = 255
+
+// addTOCFrames adds the top-level CTOC frame. When there are too many children for a single frame,
+// they are grouped into nested CTOC frames, level by level, until the top level fits.
+func addTOCFrames(tag *id3v2.Tag, elementID string, childIDs []string) {
+	for level := 0; len(childIDs) > maxCTOCEntries; level++ {
+		groupIDs := make([]string, 0, len(childIDs)/maxCTOCEntries+1)
+		for start := 0; start < len(childIDs); start += maxCTOCEntries {
+			end := min(start+maxCTOCEntries, len(childIDs))
+			groupID := fmt.Sprintf("%s%d_%d", elementID, level, len(groupIDs))
+			tag.AddFrame("CTOC", ctocFrame{
+				ElementID: groupID,
+				Ordered:   true,
+				ChildIDs:  childIDs[start:end],
+			})
+			groupIDs = append(groupIDs, groupID)
+		}
+		childIDs = groupIDs
+	}
 	tag.AddFrame("CTOC", ctocFrame{
-		ElementID: "toc",
+		ElementID: elementID,
 		TopLevel:  true,
 		Ordered:   true,
 		ChildIDs:  childIDs,
 	})
-
-	conv.log.Debug("writing ID3 chapter tags", logAttrs...)
-	if err := tag.Save(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-		return errCtx.Wrapf(err, "failed to save ID3 tags")
-	}
-	return nil
 }
 
 // ctocFrame implements id3v2.Framer for the CTOC (Table of Contents) frame,
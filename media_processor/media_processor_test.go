@@ -1,13 +1,16 @@
 package media_processor
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
@@ -17,67 +20,21 @@ import (
 
 var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
 
-func TestParseDurationOutput(t *testing.T) {
-	tests := []struct {
-		name    string
-		output  string
-		want    float64
-		wantErr bool
-	}{
-		{
-			name:   "clean output",
-			output: "22930.241438\n",
-			want:   22930.241438,
-		},
-		{
-			name:   "bmp bad magic number warning before value",
-			output: "[bmp @ 0xffffa5eaeb40] bad magic number\n22930.241438\n",
-			want:   22930.241438,
-		},
-		{
-			name:   "multiple warning lines before value",
-			output: "[foo @ 0x1] some warning\n[bar @ 0x2] another warning\n123.456\n",
-			want:   123.456,
-		},
-		{
-			name:    "no valid float",
-			output:  "[bmp @ 0x1] bad magic number\n",
-			wantErr: true,
-		},
-		{
-			name:    "empty output",
-			output:  "",
-			wantErr: true,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := parseDurationOutput(tt.output)
-			if (err != nil) != tt.wantErr {
-				t.Fatalf("parseDurationOutput() error = %v, wantErr %v", err, tt.wantErr)
-			}
-			if err == nil && got != tt.want {
-				t.Errorf("parseDurationOutput() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
 func TestConcatenate_EmptyFilepathsReturnsError(t *testing.T) {
 	processor := &FFMpegMediaProcessor{log: testLogger}
 
-	_, err := processor.Concatenate(context.Background(), nil, "aac")
+	_, err := processor.Concatenate(context.Background(), nil, service.ConcatenateOptions{Container: "m4a", AudioCodec: "aac"})
 	if err == nil {
 		t.Fatal("expected error for empty filepaths")
 	}
 }
 
-func TestConcatenate_FirstFilepathWithoutExtensionReturnsError(t *testing.T) {
+func TestConcatenate_UnknownContainerReturnsError(t *testing.T) {
 	processor := &FFMpegMediaProcessor{log: testLogger}
 
-	_, err := processor.Concatenate(context.Background(), []string{"/tmp/audio"}, "aac")
-	if err == nil {
-		t.Fatal("expected error when first filepath has no extension")
+	_, err := processor.Concatenate(context.Background(), []string{"/tmp/audio"}, service.ConcatenateOptions{Container: "avi"})
+	if !errors.Is(err, service.ErrUnknownContainer) {
+		t.Fatalf("expected unknown container error, got %v", err)
 	}
 }
 
@@ -232,6 +189,56 @@ func TestAddChapterTags_FileWithoutExistingTag(t *testing.T) {
 	}
 }
 
+// TestAddChapterTags_ManyChapters verifies that more chapters than a single CTOC frame
+// can count are grouped into nested CTOC frames instead of overflowing the entry count.
+func TestAddChapterTags_ManyChapters(t *testing.T) {
+	filepath := createTestMP3WithoutTag(t)
+
+	chapters := make([]service.Chapter, 600)
+	for i := range chapters {
+		chapters[i] = service.Chapter{
+			Title:     fmt.Sprintf("Track %d", i+1),
+			StartTime: time.Duration(i) * time.Minute,
+			EndTime:   time.Duration(i+1) * time.Minute,
+		}
+	}
+
+	processor := &FFMpegMediaProcessor{log: testLogger}
+	if err := processor.AddChapterTags(context.Background(), filepath, chapters); err != nil {
+		t.Fatalf("AddChapterTags failed: %v", err)
+	}
+
+	tag, err := id3v2.Open(filepath, id3v2.Options{Parse: true})
+	if err != nil {
+		t.Fatalf("failed to open tagged file: %v", err)
+	}
+	defer func() { _ = tag.Close() }()
+
+	if got := len(tag.GetFrames("CHAP")); got != len(chapters) {
+		t.Fatalf("expected %d chapter frames, got %d", len(chapters), got)
+	}
+
+	// element ID -> entry count, parsed from raw frame bodies
+	entries := make(map[string]int)
+	var topLevel []string
+	for _, f := range tag.GetFrames("CTOC") {
+		body := f.(id3v2.UnknownFrame).Body
+		idEnd := bytes.IndexByte(body, 0)
+		elementID, flags, count := string(body[:idEnd]), body[idEnd+1], int(body[idEnd+2])
+		entries[elementID] = count
+		if flags&0x02 != 0 {
+			topLevel = append(topLevel, elementID)
+		}
+	}
+	if len(topLevel) != 1 || topLevel[0] != "toc" {
+		t.Fatalf("expected a single top-level toc, got %v", topLevel)
+	}
+	want := map[string]int{"toc": 3, "toc0_0": 255, "toc0_1": 255, "toc0_2": 90}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("CTOC entries = %v, want %v", entries, want)
+	}
+}
+
 // makeTestMP3 generates a short valid MP3 file with the given bitrate using FFmpeg.
 func makeTestMP3(t *testing.T, duration float64, bitrate string) string {
 	t.Helper()
@@ -267,7 +274,7 @@ func TestConcatenate_OutputSizeNotLargerThanInputs(t *testing.T) {
 	inputTotal := stat1.Size() + stat2.Size()
 
 	processor := &FFMpegMediaProcessor{log: testLogger}
-	resultPath, err := processor.Concatenate(context.Background(), []string{file1, file2}, "mp3")
+	resultPath, err := processor.Concatenate(context.Background(), []string{file1, file2}, service.ConcatenateOptions{Container: "mp3", AudioCodec: "mp3"})
 	if err != nil {
 		t.Fatalf("Concatenate failed: %v", err)
 	}
@@ -0,0 +1,321 @@
+package media_processor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	id3v2 "github.com/bogem/id3v2/v2"
+	"github.com/dir01/mediary/service"
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// coverArtExts are containers that can hold cover art. Video containers have frames of their own,
+// and ffmpeg can't write pictures into ogg/opus/wav.
+var coverArtExts = map[string]bool{
+	".mp3":  true,
+	".m4a":  true,
+	".m4b":  true,
+	".flac": true,
+}
+
+// tagField is a single tag, along with its names in ffmetadata and ID3v2
+type tagField struct {
+	ffmpegKey string
+	id3Frame  string
+	value     string
+}
+
+func tagFields(tags service.Tags) []tagField {
+	artist := tags.Artist
+	if artist == "" {
+		artist = tags.Author
+	}
+	fields := []tagField{
+		{"title", "TIT2", tags.Title},
+		{"artist", "TPE1", artist},
+		{"album", "TALB", tags.Album},
+		{"album_artist", "TPE2", tags.AlbumArtist},
+		{"date", "TDRC", tags.Year},
+		{"genre", "TCON", tags.Genre},
+		{"track", "TRCK", tags.Track},
+		// there is no standard narrator tag: audiobook players, Apple Books included, read it from composer
+		{"composer", "TCOM", tags.Narrator},
+		{"comment", "COMM", tags.Comment},
+	}
+	nonEmpty := fields[:0]
+	for _, f := range fields {
+		if f.value != "" {
+			nonEmpty = append(nonEmpty, f)
+		}
+	}
+	return nonEmpty
+}
+
+func (conv *FFMpegMediaProcessor) WriteMetadata(ctx context.Context, fp string, metadata service.FileMetadata) (string, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.WriteMetadata",
+		trace.WithAttributes(
+			attribute.String("filepath", fp),
+			attribute.Int("chapters.count", len(metadata.Chapters)),
+			attribute.Bool("cover_art", metadata.CoverArtFilepath != ""),
+		),
+	)
+	defer span.End()
+
+	ext := strings.ToLower(filepath.Ext(fp))
+	if metadata.CoverArtFilepath != "" && !coverArtExts[ext] {
+		conv.log.Debug("container can't hold cover art, skipping it", slog.String("filepath", fp))
+		metadata.CoverArtFilepath = ""
+	}
+
+	var resultFilepath string
+	var err error
+	if ext == ".mp3" {
+		resultFilepath, err = conv.writeID3(fp, metadata)
+	} else {
+		resultFilepath, err = conv.writeFFMetadata(ctx, fp, metadata)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+	return resultFilepath, nil
+}
+
+// writeID3 copies the file and updates ID3v2 tag of the copy, keeping frames that are not overwritten
+func (conv *FFMpegMediaProcessor) writeID3(fp string, metadata service.FileMetadata) (string, error) {
+	errCtx := oops.With("filepath", fp, "coverArt", metadata.CoverArtFilepath)
+
+	resultFilepath, err := copyToTemp(fp)
+	if err != nil {
+		return "", errCtx.Wrapf(err, "failed to copy file")
+	}
+	errCtx = errCtx.With("resultFilepath", resultFilepath)
+
+	tag, err := id3v2.Open(resultFilepath, id3v2.Options{Parse: true})
+	if err != nil {
+		return "", errCtx.Wrapf(err, "failed to open file for ID3 tagging")
+	}
+	defer func() { _ = tag.Close() }()
+	tag.SetVersion(4)
+
+	for _, f := range tagFields(metadata.Tags) {
+		if f.id3Frame == "COMM" {
+			tag.DeleteFrames("COMM")
+			tag.AddCommentFrame(id3v2.CommentFrame{
+				Encoding: id3v2.EncodingUTF8,
+				Language: "eng",
+				Text:     f.value,
+			})
+			continue
+		}
+		tag.AddTextFrame(f.id3Frame, id3v2.EncodingUTF8, f.value)
+	}
+
+	if len(metadata.Chapters) > 0 {
+		tag.DeleteFrames("CHAP")
+		tag.DeleteFrames("CTOC")
+		addChapterFrames(tag, metadata.Chapters)
+	}
+
+	if metadata.CoverArtFilepath != "" {
+		picture, err := os.ReadFile(metadata.CoverArtFilepath)
+		if err != nil {
+			return "", errCtx.Wrapf(err, "failed to read cover art")
+		}
+		tag.DeleteFrames("APIC")
+		tag.AddAttachedPicture(id3v2.PictureFrame{
+			Encoding:    id3v2.EncodingUTF8,
+			MimeType:    imageMimeType(metadata.CoverArtFilepath),
+			PictureType: id3v2.PTFrontCover,
+			Description: "Cover",
+			Picture:     picture,
+		})
+	}
+
+	conv.log.Debug("writing ID3 tags", slog.String("filepath", resultFilepath))
+	if err := tag.Save(); err != nil {
+		return "", errCtx.Wrapf(err, "failed to save ID3 tags")
+	}
+	return resultFilepath, nil
+}
+
+// writeFFMetadata remuxes the file with tags and chapters from an ffmetadata file
+func (conv *FFMpegMediaProcessor) writeFFMetadata(ctx context.Context, fp string, metadata service.FileMetadata) (string, error) {
+	errCtx := oops.With("filepath", fp, "chapters", len(metadata.Chapters), "coverArt", metadata.CoverArtFilepath)
+
+	metadataFile, err := os.CreateTemp("", "*.ffmetadata")
+	if err != nil {
+		return "", errCtx.Wrapf(err, "failed to create ffmetadata file")
+	}
+	defer func() { _ = os.Remove(metadataFile.Name()) }()
+	if _, err := metadataFile.WriteString(buildFFMetadata(metadata.Tags, metadata.Chapters)); err != nil {
+		_ = metadataFile.Close()
+		return "", errCtx.Wrapf(err, "failed to write ffmetadata file")
+	}
+	if err := metadataFile.Close(); err != nil {
+		return "", errCtx.Wrapf(err, "failed to write ffmetadata file")
+	}
+
+	resultFile, err := os.CreateTemp("", "*"+filepath.Ext(fp))
+	if err != nil {
+		return "", errCtx.Wrapf(err, "failed to create temp file")
+	}
+	_ = resultFile.Close()
+	resultFilepath := resultFile.Name()
+
+	args := writeMetadataArgs(fp, metadataFile.Name(), metadata.CoverArtFilepath, len(metadata.Chapters) > 0, resultFilepath)
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	errCtx = errCtx.With("cmd", cmd.String())
+
+	conv.log.Debug("running ffmpeg", slog.String("cmd", cmd.String()))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		_ = os.Remove(resultFilepath)
+		return "", errCtx.With("output", string(output)).Wrapf(err, "failed to run ffmpeg")
+	}
+	return resultFilepath, nil
+}
+
+func writeMetadataArgs(input, metadataFilepath, coverArtFilepath string, withChapters bool, output string) []string {
+	args := []string{"-y", "-i", input, "-i", metadataFilepath}
+	if coverArtFilepath != "" {
+		args = append(args, "-i", coverArtFilepath)
+	}
+	// tags come from ffmetadata file only, the original ones are dropped; so is the original cover art
+	args = append(args, "-map", "0:a", "-map", "0:V?", "-map_metadata", "1")
+	if withChapters {
+		args = append(args, "-map_chapters", "1")
+	} else {
+		args = append(args, "-map_chapters", "0")
+	}
+	if coverArtFilepath != "" {
+		args = append(args, "-map", "2:v", "-disposition:v:0", "attached_pic")
+	}
+	return append(args, "-c", "copy", output)
+}
+
+// buildFFMetadata renders tags and chapters in ffmpeg's metadata format,
+// see https://ffmpeg.org/ffmpeg-formats.html#Metadata-2
+func buildFFMetadata(tags service.Tags, chapters []service.Chapter) string {
+	var b strings.Builder
+	b.WriteString(";FFMETADATA1\n")
+	for _, f := range tagFields(tags) {
+		b.WriteString(f.ffmpegKey + "=" + escapeFFMetadata(f.value) + "\n")
+	}
+	for _, ch := range chapters {
+		b.WriteString("\n[CHAPTER]\nTIMEBASE=1/1000\n")
+		b.WriteString(fmt.Sprintf("START=%d\nEND=%d\n", ch.StartTime.Milliseconds(), ch.EndTime.Milliseconds()))
+		if ch.Title != "" {
+			b.WriteString("title=" + escapeFFMetadata(ch.Title) + "\n")
+		}
+	}
+	return b.String()
+}
+
+var ffmetadataEscaper = strings.NewReplacer(`\`, `\\`, "=", `\=`, ";", `\;`, "#", `\#`, "\n", "\\\n")
+
+func escapeFFMetadata(value string) string {
+	return ffmetadataEscaper.Replace(value)
+}
+
+func (conv *FFMpegMediaProcessor) ExtractCoverArt(ctx context.Context, fp string) (coverArtFilePath string, err error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.ExtractCoverArt",
+		trace.WithAttributes(attribute.String("filepath", fp)),
+	)
+	defer span.End()
+
+	errCtx := oops.With("filepath", fp)
+	info, err := conv.GetInfo(ctx, fp)
+	if err != nil {
+		return "", errCtx.Wrapf(err, "failed to probe file")
+	}
+	var coverArt *service.StreamInfo
+	for i := range info.Streams {
+		if info.Streams[i].CoverArt {
+			coverArt = &info.Streams[i]
+			break
+		}
+	}
+	if coverArt == nil {
+		return "", errCtx.Errorf("no cover art")
+	}
+	ext, ok := pictureExts[coverArt.Codec]
+	if !ok {
+		return "", errCtx.Errorf("unsupported cover art codec %q", coverArt.Codec)
+	}
+
+	// not next to the source file: it might not be ours to litter around
+	coverArtFile, err := os.CreateTemp("", "*"+ext)
+	if err != nil {
+		return "", errCtx.Wrapf(err, "failed to create temp file")
+	}
+	_ = coverArtFile.Close()
+	coverArtFilePath = coverArtFile.Name()
+	errCtx = errCtx.With("coverArtFilePath", coverArtFilePath)
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-i", fp, "-map", fmt.Sprintf("0:%d", coverArt.Index), "-c", "copy", "-y", coverArtFilePath)
+	errCtx = errCtx.With("cmd", cmd.String())
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		_ = os.Remove(coverArtFilePath)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", errCtx.With("output", string(out)).Wrapf(err, "failed to run ffmpeg")
+	}
+
+	return coverArtFilePath, nil
+}
+
+// pictureExts are extensions of pictures by their codecs, as ffprobe names them
+var pictureExts = map[string]string{
+	"mjpeg": ".jpg",
+	"png":   ".png",
+	"gif":   ".gif",
+	"bmp":   ".bmp",
+	"webp":  ".webp",
+}
+
+func imageMimeType(fp string) string {
+	switch strings.ToLower(filepath.Ext(fp)) {
+	case ".png":
+		return "image/png"
+	case ".gif":
+		return "image/gif"
+	case ".bmp":
+		return "image/bmp"
+	case ".webp":
+		return "image/webp"
+	default:
+		return "image/jpeg"
+	}
+}
+
+func copyToTemp(fp string) (string, error) {
+	src, err := os.Open(fp)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = src.Close() }()
+
+	dst, err := os.CreateTemp("", "*"+filepath.Ext(fp))
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(dst.Name())
+		return "", err
+	}
+	return dst.Name(), dst.Close()
+}
@@ -0,0 +1,139 @@
+package media_processor
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	id3v2 "github.com/bogem/id3v2/v2"
+	"github.com/dir01/mediary/service"
+)
+
+func TestBuildFFMetadata(t *testing.T) {
+	got := buildFFMetadata(
+		service.Tags{Title: "Book; Part=1", Author: "Author", Year: "2021", Narrator: "Narrator"},
+		[]service.Chapter{
+			{Title: "Intro", StartTime: 0, EndTime: 1500 * time.Millisecond},
+			{Title: "#2\nnext", StartTime: 1500 * time.Millisecond, EndTime: time.Minute},
+		},
+	)
+	want := ";FFMETADATA1\n" +
+		"title=Book\\; Part\\=1\n" +
+		"artist=Author\n" +
+		"date=2021\n" +
+		"composer=Narrator\n" +
+		"\n[CHAPTER]\nTIMEBASE=1/1000\nSTART=0\nEND=1500\ntitle=Intro\n" +
+		"\n[CHAPTER]\nTIMEBASE=1/1000\nSTART=1500\nEND=60000\ntitle=\\#2\\\nnext\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteMetadataArgs(t *testing.T) {
+	got := writeMetadataArgs("in.m4b", "meta.txt", "cover.jpg", true, "out.m4b")
+	want := []string{
+		"-y", "-i", "in.m4b", "-i", "meta.txt", "-i", "cover.jpg",
+		"-map", "0:a", "-map", "0:V?", "-map_metadata", "1", "-map_chapters", "1",
+		"-map", "2:v", "-disposition:v:0", "attached_pic",
+		"-c", "copy", "out.m4b",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestWriteMetadata_MP3(t *testing.T) {
+	fp := createTestMP3WithTag(t)
+	coverArt, err := os.CreateTemp("", "cover_*.png")
+	if err != nil {
+		t.Fatalf("failed to create cover art: %v", err)
+	}
+	_, _ = coverArt.Write([]byte("\x89PNG fake"))
+	_ = coverArt.Close()
+	t.Cleanup(func() { _ = os.Remove(coverArt.Name()) })
+
+	processor := &FFMpegMediaProcessor{log: testLogger}
+	resultFilepath, err := processor.WriteMetadata(context.Background(), fp, service.FileMetadata{
+		Tags:             service.Tags{Title: "Episode", Album: "Show", Narrator: "Someone"},
+		CoverArtFilepath: coverArt.Name(),
+	})
+	if err != nil {
+		t.Fatalf("WriteMetadata: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Remove(resultFilepath) })
+	if resultFilepath == fp {
+		t.Fatal("expected metadata to be written into a copy")
+	}
+
+	original, err := id3v2.Open(fp, id3v2.Options{Parse: true})
+	if err != nil {
+		t.Fatalf("failed to open original: %v", err)
+	}
+	defer func() { _ = original.Close() }()
+	if original.Title() != "" {
+		t.Errorf("original file was modified, title %q", original.Title())
+	}
+
+	tag, err := id3v2.Open(resultFilepath, id3v2.Options{Parse: true})
+	if err != nil {
+		t.Fatalf("failed to open result: %v", err)
+	}
+	defer func() { _ = tag.Close() }()
+	if tag.Title() != "Episode" || tag.Album() != "Show" {
+		t.Errorf("got title %q, album %q", tag.Title(), tag.Album())
+	}
+	if tag.Artist() != "test" {
+		t.Errorf("expected existing artist to be kept, got %q", tag.Artist())
+	}
+	if got := tag.GetTextFrame("TCOM").Text; got != "Someone" {
+		t.Errorf("expected narrator in TCOM, got %q", got)
+	}
+	pictures := tag.GetFrames("APIC")
+	if len(pictures) != 1 {
+		t.Fatalf("expected 1 picture, got %d", len(pictures))
+	}
+	if pic := pictures[0].(id3v2.PictureFrame); pic.MimeType != "image/png" || pic.PictureType != id3v2.PTFrontCover {
+		t.Errorf("unexpected picture frame: %s, type %d", pic.MimeType, pic.PictureType)
+	}
+}
+
+func TestExtractCoverArt_KeepsPictureFormat(t *testing.T) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		t.Skip("ffmpeg is not installed")
+	}
+	var picture bytes.Buffer
+	if err := png.Encode(&picture, image.NewGray(image.Rect(0, 0, 8, 8))); err != nil {
+		t.Fatal(err)
+	}
+	fp := writeSilentMP3(t, 10)
+	tag, err := id3v2.Open(fp, id3v2.Options{Parse: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag.AddAttachedPicture(id3v2.PictureFrame{Encoding: id3v2.EncodingUTF8, MimeType: "image/png",
+		PictureType: id3v2.PTFrontCover, Picture: picture.Bytes()})
+	if err := tag.Save(); err != nil {
+		t.Fatal(err)
+	}
+	_ = tag.Close()
+
+	processor := &FFMpegMediaProcessor{log: testLogger}
+	coverArt, err := processor.ExtractCoverArt(context.Background(), fp)
+	if err != nil {
+		t.Fatalf("ExtractCoverArt failed: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Remove(coverArt) })
+	if ext := filepath.Ext(coverArt); ext != ".png" {
+		t.Errorf("png cover art is extracted into %s", ext)
+	}
+	if extracted, err := os.ReadFile(coverArt); err != nil || !bytes.Equal(extracted, picture.Bytes()) {
+		t.Errorf("cover art is not copied as it is: %v", err)
+	}
+}
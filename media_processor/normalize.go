@@ -0,0 +1,201 @@
+package media_processor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dir01/mediary/service"
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// loudnormMeasurement is what loudnorm prints with print_format=json, all numbers are strings
+type loudnormMeasurement struct {
+	InputI            string `json:"input_i"`
+	InputTP           string `json:"input_tp"`
+	InputLRA          string `json:"input_lra"`
+	InputThresh       string `json:"input_thresh"`
+	OutputI           string `json:"output_i"`
+	OutputTP          string `json:"output_tp"`
+	OutputLRA         string `json:"output_lra"`
+	NormalizationType string `json:"normalization_type"`
+	TargetOffset      string `json:"target_offset"`
+}
+
+// Normalize measures loudness in the first pass, and applies it in the second one,
+// which allows loudnorm to use linear gain whenever the loudness range fits the target.
+// See https://ffmpeg.org/ffmpeg-filters.html#loudnorm
+func (conv *FFMpegMediaProcessor) Normalize(ctx context.Context, fp string, opts service.NormalizeOptions) (string, *service.LoudnessStats, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.Normalize",
+		trace.WithAttributes(
+			attribute.String("filepath", fp),
+			attribute.Float64("target.integrated_lufs", opts.IntegratedLUFS),
+		),
+	)
+	defer span.End()
+
+	errCtx := oops.With("filepath", fp, "opts", opts)
+	logAttrs := []any{slog.String("filepath", fp), slog.Any("opts", opts)}
+	fail := func(err error) (string, *service.LoudnessStats, error) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", nil, err
+	}
+
+	container, ok := service.ContainerByExt(filepath.Ext(fp))
+	if !ok {
+		return fail(errCtx.Wrap(fmt.Errorf("%w: %s", service.ErrUnknownContainer, filepath.Ext(fp))))
+	}
+	probe, err := conv.probeAudio(ctx, fp)
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to probe file"))
+	}
+
+	measureCmd := exec.CommandContext(ctx, "ffmpeg", loudnormMeasureArgs(fp, opts)...)
+	conv.log.Debug("measuring loudness", append(logAttrs, slog.String("cmd", measureCmd.String()))...)
+	output, err := measureCmd.CombinedOutput()
+	if err != nil {
+		return fail(errCtx.With("cmd", measureCmd.String(), "output", string(output)).Wrapf(err, "failed to run ffmpeg"))
+	}
+	measured, err := parseLoudnormOutput(string(output))
+	if err != nil {
+		return fail(errCtx.With("output", string(output)).Wrapf(err, "failed to parse loudness measurement"))
+	}
+	if v, _ := strconv.ParseFloat(measured.InputI, 64); math.IsInf(v, 0) {
+		// silence can't be made louder, and loudnorm refuses to apply infinite measurements
+		conv.log.Debug("file is silent, skipping normalization", logAttrs...)
+		return fp, &service.LoudnessStats{NormalizationType: "none"}, nil
+	}
+
+	file, err := os.CreateTemp("", "*"+container.Ext)
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to create temp file"))
+	}
+	_ = file.Close()
+	resultFilepath := file.Name()
+	errCtx = errCtx.With("resultFilepath", resultFilepath)
+
+	applyCmd := exec.CommandContext(ctx, "ffmpeg", loudnormApplyArgs(fp, resultFilepath, opts, measured, probe, container)...)
+	conv.log.Debug("normalizing loudness", append(logAttrs, slog.String("cmd", applyCmd.String()))...)
+	output, err = applyCmd.CombinedOutput()
+	if err != nil {
+		_ = os.Remove(resultFilepath)
+		return fail(errCtx.With("cmd", applyCmd.String(), "output", string(output)).Wrapf(err, "failed to run ffmpeg"))
+	}
+	applied, err := parseLoudnormOutput(string(output))
+	if err != nil {
+		_ = os.Remove(resultFilepath)
+		return fail(errCtx.With("output", string(output)).Wrapf(err, "failed to parse loudness stats"))
+	}
+
+	stats := applied.stats()
+	span.SetAttributes(
+		attribute.Float64("input.integrated_lufs", stats.InputI),
+		attribute.Float64("output.integrated_lufs", stats.OutputI),
+		attribute.String("normalization_type", stats.NormalizationType),
+	)
+	return resultFilepath, stats, nil
+}
+
+func loudnormMeasureArgs(input string, opts service.NormalizeOptions) []string {
+	return []string{
+		"-hide_banner", "-nostats", "-i", input, "-map", "0:a:0",
+		"-af", loudnormFilter(opts, nil), "-f", "null", "-",
+	}
+}
+
+func loudnormApplyArgs(
+	input, output string, opts service.NormalizeOptions, measured *loudnormMeasurement, probe audioProbe, container service.Container,
+) []string {
+	args := []string{
+		"-y", "-hide_banner", "-nostats", "-i", input, "-map", "0:a:0", "-map_metadata", "0",
+		"-af", loudnormFilter(opts, measured),
+	}
+	// loudnorm upsamples to 192kHz internally, reencodeArgs restores the original sample rate
+	args = append(args, reencodeArgs(probe, container)...)
+	return append(args, output)
+}
+
+// reencodeArgs encode audio into the default codec of the container, keeping quality and sample rate of the source
+func reencodeArgs(probe audioProbe, container service.Container) []string {
+	args := []string{"-c:a", container.DefaultAudioCodec}
+	if !losslessCodecs[container.DefaultAudioCodec] && !losslessCodecs[probe.Codec] && probe.BitRate > 0 {
+		args = append(args, "-b:a", strconv.Itoa(probe.BitRate))
+	}
+	if sampleRate, _ := encoderLimits(container.DefaultAudioCodec, probe.SampleRate, probe.Channels); sampleRate > 0 {
+		args = append(args, "-ar", strconv.Itoa(sampleRate))
+	}
+	return args
+}
+
+// loudnormFilter makes the filter of the measuring pass, or of the applying pass when measurements are given
+func loudnormFilter(opts service.NormalizeOptions, measured *loudnormMeasurement) string {
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	params := []string{
+		"I=" + format(opts.IntegratedLUFS),
+		"TP=" + format(opts.TruePeak),
+		"LRA=" + format(opts.LRA),
+	}
+	if measured != nil {
+		params = append(params,
+			"measured_I="+measured.InputI,
+			"measured_TP="+measured.InputTP,
+			"measured_LRA="+measured.InputLRA,
+			"measured_thresh="+measured.InputThresh,
+			"offset="+measured.TargetOffset,
+			"linear=true",
+		)
+	}
+	params = append(params, "print_format=json")
+	return "loudnorm=" + strings.Join(params, ":")
+}
+
+// parseLoudnormOutput finds loudnorm's JSON at the end of ffmpeg's output
+func parseLoudnormOutput(output string) (*loudnormMeasurement, error) {
+	start := strings.LastIndex(output, "{")
+	end := strings.LastIndex(output, "}")
+	if start == -1 || end < start {
+		return nil, fmt.Errorf("no loudnorm stats in ffmpeg output")
+	}
+	m := &loudnormMeasurement{}
+	if err := json.Unmarshal([]byte(output[start:end+1]), m); err != nil {
+		return nil, fmt.Errorf("failed to parse loudnorm stats: %w", err)
+	}
+	if m.InputI == "" {
+		return nil, fmt.Errorf("no loudnorm stats in ffmpeg output")
+	}
+	return m, nil
+}
+
+func (m *loudnormMeasurement) stats() *service.LoudnessStats {
+	// infinities, reported for silent parts, can't be stored as JSON
+	parse := func(s string) float64 {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsInf(v, 0) || math.IsNaN(v) {
+			return 0
+		}
+		return v
+	}
+	return &service.LoudnessStats{
+		InputI:            parse(m.InputI),
+		InputTP:           parse(m.InputTP),
+		InputLRA:          parse(m.InputLRA),
+		InputThresh:       parse(m.InputThresh),
+		OutputI:           parse(m.OutputI),
+		OutputTP:          parse(m.OutputTP),
+		OutputLRA:         parse(m.OutputLRA),
+		NormalizationType: strings.ToLower(m.NormalizationType),
+		TargetOffset:      parse(m.TargetOffset),
+	}
+}
@@ -0,0 +1,65 @@
+package media_processor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dir01/mediary/service"
+)
+
+const loudnormOutput = `Input #0, mp3, from 'in.mp3':
+  Duration: 00:00:10.00, start: 0.000000, bitrate: 64 kb/s
+[Parsed_loudnorm_0 @ 0x5581] 
+{
+	"input_i" : "-27.61",
+	"input_tp" : "-4.47",
+	"input_lra" : "18.06",
+	"input_thresh" : "-39.20",
+	"output_i" : "-16.58",
+	"output_tp" : "-1.50",
+	"output_lra" : "14.78",
+	"output_thresh" : "-27.71",
+	"normalization_type" : "Dynamic",
+	"target_offset" : "0.58"
+}
+`
+
+func TestParseLoudnormOutput(t *testing.T) {
+	m, err := parseLoudnormOutput(loudnormOutput)
+	if err != nil {
+		t.Fatalf("parseLoudnormOutput: %v", err)
+	}
+	want := &service.LoudnessStats{
+		InputI: -27.61, InputTP: -4.47, InputLRA: 18.06, InputThresh: -39.2,
+		OutputI: -16.58, OutputTP: -1.5, OutputLRA: 14.78,
+		NormalizationType: "dynamic", TargetOffset: 0.58,
+	}
+	if got := m.stats(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if _, err := parseLoudnormOutput("Input #0, mp3, from 'in.mp3':\n"); err == nil {
+		t.Error("expected error for output without stats")
+	}
+}
+
+func TestLoudnormApplyArgs(t *testing.T) {
+	m, err := parseLoudnormOutput(loudnormOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	container, _ := service.ContainerByExt(".mp3")
+	opts := service.NormalizeOptions{IntegratedLUFS: -16, TruePeak: -1.5, LRA: 11}
+	probe := audioProbe{FormatName: "mp3", Codec: "mp3", SampleRate: 44100, Channels: 1, BitRate: 64000}
+
+	got := loudnormApplyArgs("in.mp3", "out.mp3", opts, m, probe, container)
+	want := []string{
+		"-y", "-hide_banner", "-nostats", "-i", "in.mp3", "-map", "0:a:0", "-map_metadata", "0",
+		"-af", "loudnorm=I=-16:TP=-1.5:LRA=11:measured_I=-27.61:measured_TP=-4.47:measured_LRA=18.06:" +
+			"measured_thresh=-39.20:offset=0.58:linear=true:print_format=json",
+		"-c:a", "libmp3lame", "-b:a", "64000", "-ar", "44100", "out.mp3",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
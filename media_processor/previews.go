@@ -0,0 +1,141 @@
+package media_processor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/dir01/mediary/service"
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContactSheet selects a frame in the middle of every equal share of the video, and tiles them
+func (conv *FFMpegMediaProcessor) ContactSheet(ctx context.Context, fp string, opts service.ContactSheetOptions) (string, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.ContactSheet",
+		trace.WithAttributes(
+			attribute.String("filepath", fp),
+			attribute.Int("frames", opts.Frames),
+		),
+	)
+	defer span.End()
+
+	info, err := conv.probe(ctx, fp)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", oops.With("filepath", fp).Wrapf(err, "failed to probe file")
+	}
+	return conv.renderPreview(ctx, span, fp, ".jpg", contactSheetArgs(fp, info.Duration, opts))
+}
+
+// PosterFrame seeks to the frame before decoding, so that it is quick however long the video is
+func (conv *FFMpegMediaProcessor) PosterFrame(ctx context.Context, fp string, opts service.PosterFrameOptions) (string, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.PosterFrame",
+		trace.WithAttributes(
+			attribute.String("filepath", fp),
+			attribute.Float64("at_seconds", opts.At.Seconds()),
+		),
+	)
+	defer span.End()
+
+	info, err := conv.probe(ctx, fp)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", oops.With("filepath", fp).Wrapf(err, "failed to probe file")
+	}
+	// seeking beyond the end yields no frame at all
+	opts.At = min(opts.At, max(0, info.Duration-time.Second))
+	return conv.renderPreview(ctx, span, fp, ".jpg", posterFrameArgs(fp, opts))
+}
+
+// PreviewClip re-encodes a part of the video with H.264 and AAC, which play anywhere
+func (conv *FFMpegMediaProcessor) PreviewClip(ctx context.Context, fp string, opts service.PreviewClipOptions) (string, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.PreviewClip",
+		trace.WithAttributes(
+			attribute.String("filepath", fp),
+			attribute.Float64("start_seconds", opts.Start.Seconds()),
+			attribute.Float64("duration_seconds", opts.Duration.Seconds()),
+		),
+	)
+	defer span.End()
+
+	info, err := conv.probe(ctx, fp)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", oops.With("filepath", fp).Wrapf(err, "failed to probe file")
+	}
+	// a clip from near the end is shorter rather than empty
+	opts.Start = min(opts.Start, max(0, info.Duration-opts.Duration))
+	return conv.renderPreview(ctx, span, fp, ".mp4", previewClipArgs(fp, opts))
+}
+
+// renderPreview runs ffmpeg with args that lack the output, which is a temp file with the given extension
+func (conv *FFMpegMediaProcessor) renderPreview(ctx context.Context, span trace.Span, fp string, ext string, args []string) (string, error) {
+	errCtx := oops.With("filepath", fp)
+	file, err := os.CreateTemp("", "*-preview"+ext)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", errCtx.Wrapf(err, "failed to create temp file")
+	}
+	_ = file.Close()
+	previewFilepath := file.Name()
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", append(args, previewFilepath)...)
+	conv.log.Debug("rendering preview", slog.String("filepath", fp), slog.String("cmd", cmd.String()))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		_ = os.Remove(previewFilepath)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", errCtx.With("cmd", cmd.String(), "output", string(output)).Wrapf(err, "failed to run ffmpeg")
+	}
+	return previewFilepath, nil
+}
+
+func contactSheetArgs(input string, duration time.Duration, opts service.ContactSheetOptions) []string {
+	interval := duration / time.Duration(opts.Frames)
+	rows := (opts.Frames + opts.Columns - 1) / opts.Columns
+	// the first frame after the middle of the first share, then every frame at least an interval after the previous one
+	filter := fmt.Sprintf(
+		`select=gte(t\,%s)*(isnan(prev_selected_t)+gte(t-prev_selected_t\,%s)),scale=%d:-2,tile=%dx%d`,
+		formatFloat((interval / 2).Seconds()), formatFloat(interval.Seconds()), opts.Width, opts.Columns, rows,
+	)
+	return []string{
+		"-y", "-hide_banner", "-nostats", "-i", input, "-map", "0:V:0",
+		"-vf", filter, "-frames:v", "1", "-q:v", "3", "-update", "1",
+	}
+}
+
+func posterFrameArgs(input string, opts service.PosterFrameOptions) []string {
+	args := []string{
+		"-y", "-hide_banner", "-nostats", "-ss", formatFloat(opts.At.Seconds()), "-i", input, "-map", "0:V:0",
+		"-frames:v", "1",
+	}
+	if opts.Width != 0 {
+		args = append(args, "-vf", fmt.Sprintf("scale=%d:-2", opts.Width))
+	}
+	return append(args, "-q:v", "2", "-update", "1")
+}
+
+// previewClipArgs keep the height even, as H.264 needs it
+func previewClipArgs(input string, opts service.PreviewClipOptions) []string {
+	return []string{
+		"-y", "-hide_banner", "-nostats",
+		"-ss", formatFloat(opts.Start.Seconds()), "-i", input, "-t", formatFloat(opts.Duration.Seconds()),
+		"-map", "0:V:0", "-map", "0:a:0?", "-map_metadata", "-1", "-map_chapters", "-1",
+		"-vf", "scale=-2:trunc(min(" + strconv.Itoa(opts.Height) + `\,ih)/2)*2`,
+		"-c:v", "libx264", "-preset", "veryfast", "-b:v", opts.Bitrate, "-pix_fmt", "yuv420p",
+		"-c:a", "aac", "-b:a", "64k", "-ac", "2",
+		"-movflags", "+faststart",
+	}
+}
@@ -0,0 +1,49 @@
+package media_processor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/service"
+)
+
+func TestContactSheetArgs(t *testing.T) {
+	got := contactSheetArgs("in.mp4", 10*time.Minute, service.ContactSheetOptions{Frames: 6, Columns: 4, Width: 320})
+	want := []string{"-y", "-hide_banner", "-nostats", "-i", "in.mp4", "-map", "0:V:0",
+		"-vf", `select=gte(t\,50)*(isnan(prev_selected_t)+gte(t-prev_selected_t\,100)),scale=320:-2,tile=4x2`,
+		"-frames:v", "1", "-q:v", "3", "-update", "1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
+
+func TestPosterFrameArgs(t *testing.T) {
+	got := posterFrameArgs("in.mp4", service.PosterFrameOptions{At: 90 * time.Second, Width: 640})
+	want := []string{"-y", "-hide_banner", "-nostats", "-ss", "90", "-i", "in.mp4", "-map", "0:V:0",
+		"-frames:v", "1", "-vf", "scale=640:-2", "-q:v", "2", "-update", "1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+
+	got = posterFrameArgs("in.mp4", service.PosterFrameOptions{At: 1500 * time.Millisecond})
+	for _, arg := range got {
+		if arg == "-vf" {
+			t.Errorf("poster of the video's own size is scaled: %q", got)
+		}
+	}
+}
+
+func TestPreviewClipArgs(t *testing.T) {
+	got := previewClipArgs("in.mkv", service.PreviewClipOptions{Start: time.Minute, Duration: 10 * time.Second, Height: 360, Bitrate: "400k"})
+	want := []string{"-y", "-hide_banner", "-nostats",
+		"-ss", "60", "-i", "in.mkv", "-t", "10",
+		"-map", "0:V:0", "-map", "0:a:0?", "-map_metadata", "-1", "-map_chapters", "-1",
+		"-vf", `scale=-2:trunc(min(360\,ih)/2)*2`,
+		"-c:v", "libx264", "-preset", "veryfast", "-b:v", "400k", "-pix_fmt", "yuv420p",
+		"-c:a", "aac", "-b:a", "64k", "-ac", "2",
+		"-movflags", "+faststart"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,182 @@
+package media_processor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/dir01/mediary/service"
+	"github.com/samber/oops"
+)
+
+// probe runs ffprobe once for everything there is to know about the file:
+// its format, streams, tags and chapters
+func (conv *FFMpegMediaProcessor) probe(ctx context.Context, filepath string) (*service.MediaInfo, error) {
+	cmd := exec.CommandContext(
+		ctx,
+		"ffprobe",
+		"-v", "error",
+		"-print_format", "json",
+		"-show_format", "-show_streams", "-show_chapters",
+		filepath,
+	)
+	errCtx := oops.With("filepath", filepath, "cmd", cmd.String())
+
+	// warnings go to stderr, so stdout is JSON alone
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errCtx.Wrapf(err, "failed to run ffprobe")
+	}
+	info, err := parseProbe(out)
+	if err != nil {
+		return nil, errCtx.With("output", string(out)).Wrap(err)
+	}
+	return info, nil
+}
+
+// ffprobeOutput is the JSON output of ffprobe, where most numbers are strings
+type ffprobeOutput struct {
+	Format struct {
+		FormatName string            `json:"format_name"`
+		Duration   string            `json:"duration"`
+		Size       string            `json:"size"`
+		BitRate    string            `json:"bit_rate"`
+		Tags       map[string]string `json:"tags"`
+	} `json:"format"`
+	Streams []struct {
+		Index         int    `json:"index"`
+		CodecType     string `json:"codec_type"`
+		CodecName     string `json:"codec_name"`
+		BitRate       string `json:"bit_rate"`
+		SampleRate    string `json:"sample_rate"`
+		Channels      int    `json:"channels"`
+		ChannelLayout string `json:"channel_layout"`
+		Width         int    `json:"width"`
+		Height        int    `json:"height"`
+		AvgFrameRate  string `json:"avg_frame_rate"`
+		RFrameRate    string `json:"r_frame_rate"`
+		Disposition   struct {
+			AttachedPic int `json:"attached_pic"`
+		} `json:"disposition"`
+		Tags map[string]string `json:"tags"`
+	} `json:"streams"`
+	Chapters []struct {
+		StartTime string            `json:"start_time"`
+		EndTime   string            `json:"end_time"`
+		Tags      map[string]string `json:"tags"`
+	} `json:"chapters"`
+}
+
+func parseProbe(output []byte) (*service.MediaInfo, error) {
+	var parsed ffprobeOutput
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	seconds, err := strconv.ParseFloat(parsed.Format.Duration, 64)
+	if err != nil {
+		return nil, fmt.Errorf("no valid duration in ffprobe output: %q", parsed.Format.Duration)
+	}
+	info := &service.MediaInfo{
+		Duration:  secondsToDuration(seconds),
+		Container: parsed.Format.FormatName,
+		Tags:      lowercaseKeys(parsed.Format.Tags),
+		Streams:   make([]service.StreamInfo, 0, len(parsed.Streams)),
+	}
+	info.Title = info.Tags["title"]
+	info.FileLenBytes, _ = strconv.ParseInt(parsed.Format.Size, 10, 64)
+	info.BitRate, _ = strconv.ParseInt(parsed.Format.BitRate, 10, 64)
+
+	for _, s := range parsed.Streams {
+		stream := service.StreamInfo{
+			Index:         s.Index,
+			Type:          s.CodecType,
+			Codec:         s.CodecName,
+			Channels:      s.Channels,
+			ChannelLayout: s.ChannelLayout,
+			Width:         s.Width,
+			Height:        s.Height,
+			CoverArt:      s.Disposition.AttachedPic == 1,
+			Language:      lowercaseKeys(s.Tags)["language"],
+		}
+		stream.BitRate, _ = strconv.ParseInt(s.BitRate, 10, 64)
+		stream.SampleRate, _ = strconv.Atoi(s.SampleRate)
+		if s.CodecType == service.StreamVideo && !stream.CoverArt {
+			// the average rate is unknown for some containers, the base one is the best guess then
+			if stream.FrameRate = parseFrameRate(s.AvgFrameRate); stream.FrameRate == 0 {
+				stream.FrameRate = parseFrameRate(s.RFrameRate)
+			}
+		}
+		info.Streams = append(info.Streams, stream)
+	}
+
+	for _, ch := range parsed.Chapters {
+		start, err := strconv.ParseFloat(ch.StartTime, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chapter start: %q", ch.StartTime)
+		}
+		end, err := strconv.ParseFloat(ch.EndTime, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chapter end: %q", ch.EndTime)
+		}
+		info.Chapters = append(info.Chapters, service.Chapter{
+			Title:     lowercaseKeys(ch.Tags)["title"],
+			StartTime: secondsToDuration(start),
+			EndTime:   secondsToDuration(end),
+		})
+	}
+	return info, nil
+}
+
+// parseFrameRate parses rates like "30000/1001", "0/0" and malformed ones are zero
+func parseFrameRate(rate string) float64 {
+	num, den, ok := strings.Cut(rate, "/")
+	if !ok {
+		den = "1"
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil || d == 0 {
+		return 0
+	}
+	return n / d
+}
+
+// lowercaseKeys makes tags look the same for every container: Vorbis comments are uppercase, ID3 ones are not
+func lowercaseKeys(tags map[string]string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	lowercase := make(map[string]string, len(tags))
+	for k, v := range tags {
+		lowercase[strings.ToLower(k)] = v
+	}
+	return lowercase
+}
+
+// audioProbeOf picks what concatenation and re-encoding need to know about the selected audio stream
+func audioProbeOf(info *service.MediaInfo, sel service.AudioStreamSelector) (audioProbe, error) {
+	position, stream, err := info.SelectAudioStream(sel)
+	if err != nil {
+		return audioProbe{}, err
+	}
+	probe := audioProbe{
+		FormatName: info.Container,
+		Stream:     position,
+		Codec:      stream.Codec,
+		SampleRate: stream.SampleRate,
+		Channels:   stream.Channels,
+		BitRate:    int(stream.BitRate),
+	}
+	// stream bitrate is unknown for some containers (e.g. VBR in matroska), format's one is close enough
+	if probe.BitRate == 0 {
+		probe.BitRate = int(info.BitRate)
+	}
+	return probe, nil
+}
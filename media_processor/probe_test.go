@@ -0,0 +1,94 @@
+package media_processor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/service"
+)
+
+func TestParseProbe(t *testing.T) {
+	output := []byte(`{
+		"programs": [],
+		"streams": [
+			{"index": 0, "codec_name": "aac", "codec_type": "audio", "sample_rate": "44100", "channels": 2,
+			 "channel_layout": "stereo", "bit_rate": "64000", "disposition": {"attached_pic": 0},
+			 "tags": {"language": "eng", "handler_name": "SoundHandler"}},
+			{"index": 1, "codec_name": "mjpeg", "codec_type": "video", "width": 600, "height": 600,
+			 "disposition": {"attached_pic": 1}}
+		],
+		"chapters": [
+			{"id": 0, "time_base": "1/1000", "start": 0, "start_time": "0.000000", "end": 90500, "end_time": "90.500000",
+			 "tags": {"title": "Intro"}},
+			{"id": 1, "time_base": "1/1000", "start": 90500, "start_time": "90.500000", "end": 600000, "end_time": "600.000000"}
+		],
+		"format": {"format_name": "mov,mp4,m4a,3gp,3g2,mj2", "duration": "600.000000", "size": "4812345",
+		 "bit_rate": "65123", "tags": {"TITLE": "A Book", "artist": "Someone"}}
+	}`)
+	got, err := parseProbe(output)
+	if err != nil {
+		t.Fatalf("parseProbe: %v", err)
+	}
+	want := &service.MediaInfo{
+		Duration:     10 * time.Minute,
+		FileLenBytes: 4812345,
+		Title:        "A Book",
+		Container:    "mov,mp4,m4a,3gp,3g2,mj2",
+		BitRate:      65123,
+		Streams: []service.StreamInfo{
+			{Index: 0, Type: "audio", Codec: "aac", BitRate: 64000, SampleRate: 44100, Channels: 2, ChannelLayout: "stereo", Language: "eng"},
+			{Index: 1, Type: "video", Codec: "mjpeg", Width: 600, Height: 600, CoverArt: true},
+		},
+		Tags: map[string]string{"title": "A Book", "artist": "Someone"},
+		Chapters: []service.Chapter{
+			{Title: "Intro", StartTime: 0, EndTime: 90500 * time.Millisecond},
+			{StartTime: 90500 * time.Millisecond, EndTime: 10 * time.Minute},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if _, err := parseProbe([]byte(`{"streams": [], "format": {"format_name": "mp3"}}`)); err == nil {
+		t.Error("expected error for output without duration")
+	}
+}
+
+func TestAudioProbeOf(t *testing.T) {
+	info := &service.MediaInfo{
+		Container: "matroska,webm",
+		BitRate:   65123,
+		Streams: []service.StreamInfo{
+			{Type: "video", Codec: "h264"},
+			{Type: "audio", Codec: "opus", SampleRate: 48000, Channels: 2},
+		},
+	}
+	got, err := audioProbeOf(info, service.AudioStreamSelector{})
+	if err != nil {
+		t.Fatalf("audioProbeOf: %v", err)
+	}
+	// the stream has no bitrate of its own, so the format's one is used
+	want := audioProbe{FormatName: "matroska,webm", Codec: "opus", SampleRate: 48000, Channels: 2, BitRate: 65123}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if _, err := audioProbeOf(&service.MediaInfo{Streams: info.Streams[:1]}, service.AudioStreamSelector{}); err == nil {
+		t.Error("expected error for a file without audio")
+	}
+}
+
+func TestParseFrameRate(t *testing.T) {
+	for rate, want := range map[string]float64{
+		"25/1":       25,
+		"30000/1001": 30000.0 / 1001,
+		"24":         24,
+		"0/0":        0,
+		"":           0,
+	} {
+		if got := parseFrameRate(rate); got != want {
+			t.Errorf("parseFrameRate(%q) = %v, want %v", rate, got, want)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package media_processor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dir01/mediary/service"
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// edgeTolerance is how close to the edge of a file a silence has to be to count as leading or trailing
+const edgeTolerance = 50 * time.Millisecond
+
+// silence is a pause reported by silencedetect
+type silence struct {
+	start time.Duration
+	// end is -1 when the silence lasts until the end of the file
+	end time.Duration
+}
+
+var silenceLineRe = regexp.MustCompile(`silence_(start|end): (-?[0-9.]+)`)
+
+// TrimSilence cuts leading and trailing silences found by silencedetect off, and, when asked to,
+// shortens inner ones with silenceremove. Edges are cut by position rather than by silenceremove,
+// since trimming the end with it requires reversing, and thus buffering, the whole file.
+// See https://ffmpeg.org/ffmpeg-filters.html#silencedetect
+func (conv *FFMpegMediaProcessor) TrimSilence(ctx context.Context, fp string, opts service.TrimSilenceOptions) (string, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.TrimSilence",
+		trace.WithAttributes(
+			attribute.String("filepath", fp),
+			attribute.Float64("noise_db", opts.NoiseDB),
+		),
+	)
+	defer span.End()
+
+	errCtx := oops.With("filepath", fp, "opts", opts)
+	logAttrs := []any{slog.String("filepath", fp), slog.Any("opts", opts)}
+	fail := func(err error) (string, error) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+
+	container, ok := service.ContainerByExt(filepath.Ext(fp))
+	if !ok {
+		return fail(errCtx.Wrap(fmt.Errorf("%w: %s", service.ErrUnknownContainer, filepath.Ext(fp))))
+	}
+	silences, duration, err := conv.detectSilences(ctx, fp, opts.SilenceOptions)
+	if err != nil {
+		return fail(errCtx.Wrap(err))
+	}
+	filter := trimSilenceFilter(silences, duration, opts)
+	if filter == "" {
+		conv.log.Debug("no silence to trim", logAttrs...)
+		return fp, nil
+	}
+	probe, err := conv.probeAudio(ctx, fp)
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to probe file"))
+	}
+
+	file, err := os.CreateTemp("", "*"+container.Ext)
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to create temp file"))
+	}
+	_ = file.Close()
+	resultFilepath := file.Name()
+	errCtx = errCtx.With("resultFilepath", resultFilepath)
+
+	args := []string{"-y", "-hide_banner", "-nostats", "-i", fp, "-map", "0:a:0", "-map_metadata", "0", "-af", filter}
+	args = append(args, reencodeArgs(probe, container)...)
+	cmd := exec.CommandContext(ctx, "ffmpeg", append(args, resultFilepath)...)
+	conv.log.Debug("trimming silence", append(logAttrs, slog.String("cmd", cmd.String()))...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		_ = os.Remove(resultFilepath)
+		return fail(errCtx.With("cmd", cmd.String(), "output", string(output)).Wrapf(err, "failed to run ffmpeg"))
+	}
+	return resultFilepath, nil
+}
+
+// DetectSilenceChapters splits the file at its longest pauses, keeping chapters at least opts.MinChapterLength long
+func (conv *FFMpegMediaProcessor) DetectSilenceChapters(ctx context.Context, fp string, opts service.SilenceChapterOptions) ([]service.Chapter, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.DetectSilenceChapters",
+		trace.WithAttributes(
+			attribute.String("filepath", fp),
+			attribute.Float64("noise_db", opts.NoiseDB),
+		),
+	)
+	defer span.End()
+
+	silences, duration, err := conv.detectSilences(ctx, fp, opts.SilenceOptions)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, oops.With("filepath", fp, "opts", opts).Wrap(err)
+	}
+	chapters := silenceChapters(silences, duration, opts.MinChapterLength)
+	span.SetAttributes(attribute.Int("chapters.count", len(chapters)))
+	return chapters, nil
+}
+
+func (conv *FFMpegMediaProcessor) detectSilences(ctx context.Context, fp string, opts service.SilenceOptions) ([]silence, time.Duration, error) {
+	duration, err := conv.GetDuration(fp)
+	if err != nil {
+		return nil, 0, oops.Wrapf(err, "failed to get duration")
+	}
+	cmd := exec.CommandContext(ctx, "ffmpeg", silencedetectArgs(fp, opts)...)
+	conv.log.Debug("detecting silence", slog.String("filepath", fp), slog.String("cmd", cmd.String()))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, 0, oops.With("cmd", cmd.String(), "output", string(output)).Wrapf(err, "failed to run ffmpeg")
+	}
+	return parseSilencedetectOutput(string(output)), duration, nil
+}
+
+func silencedetectArgs(input string, opts service.SilenceOptions) []string {
+	return []string{
+		"-hide_banner", "-nostats", "-i", input, "-map", "0:a:0",
+		"-af", fmt.Sprintf("silencedetect=noise=%sdB:d=%s", formatFloat(opts.NoiseDB), formatFloat(opts.MinSilence.Seconds())),
+		"-f", "null", "-",
+	}
+}
+
+// parseSilencedetectOutput collects silences from lines like
+// "[silencedetect @ 0x5581] silence_end: 15.678 | silence_duration: 3.333"
+func parseSilencedetectOutput(output string) []silence {
+	var silences []silence
+	for _, m := range silenceLineRe.FindAllStringSubmatch(output, -1) {
+		seconds, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			continue
+		}
+		// silences at the very beginning are reported to start slightly before zero
+		at := time.Duration(max(seconds, 0) * float64(time.Second))
+		switch {
+		case m[1] == "start":
+			silences = append(silences, silence{start: at, end: -1})
+		case len(silences) > 0 && silences[len(silences)-1].end == -1:
+			silences[len(silences)-1].end = at
+		}
+	}
+	return silences
+}
+
+// trimSilenceFilter makes a filter that trims the silences, or returns an empty string when there is nothing to trim
+func trimSilenceFilter(silences []silence, duration time.Duration, opts service.TrimSilenceOptions) string {
+	start, end := time.Duration(0), duration
+	compress := false
+	for _, s := range silences {
+		switch {
+		case s.start <= edgeTolerance:
+			start = max(start, s.end)
+		case s.end == -1 || s.end >= duration-edgeTolerance:
+			end = min(end, s.start)
+		case opts.MaxInnerSilence > 0 && s.end-s.start > opts.MaxInnerSilence:
+			compress = true
+		}
+	}
+	if start >= end {
+		// the file is silent as a whole, there is nothing to keep
+		return ""
+	}
+
+	var filters []string
+	if start > 0 || end < duration {
+		filters = append(filters,
+			fmt.Sprintf("atrim=start=%s:end=%s", formatFloat(start.Seconds()), formatFloat(end.Seconds())),
+			"asetpts=PTS-STARTPTS",
+		)
+	}
+	if compress {
+		keep := formatFloat(opts.MaxInnerSilence.Seconds())
+		filters = append(filters, fmt.Sprintf("silenceremove=stop_periods=-1:stop_duration=%s:stop_threshold=%sdB:stop_silence=%s",
+			keep, formatFloat(opts.NoiseDB), keep))
+	}
+	return strings.Join(filters, ",")
+}
+
+// silenceChapters splits media in the middles of inner silences, longest silences first,
+// skipping those that would make a chapter shorter than minChapterLength
+func silenceChapters(silences []silence, duration time.Duration, minChapterLength time.Duration) []service.Chapter {
+	var inner []silence
+	for _, s := range silences {
+		if s.start > edgeTolerance && s.end != -1 && s.end < duration-edgeTolerance {
+			inner = append(inner, s)
+		}
+	}
+	sort.SliceStable(inner, func(i, j int) bool { return inner[i].end-inner[i].start > inner[j].end-inner[j].start })
+
+	bounds := []time.Duration{0, duration}
+	for _, s := range inner {
+		at := s.start + (s.end-s.start)/2
+		i := sort.Search(len(bounds), func(i int) bool { return bounds[i] >= at })
+		if at-bounds[i-1] < minChapterLength || bounds[i]-at < minChapterLength {
+			continue
+		}
+		bounds = append(bounds[:i], append([]time.Duration{at}, bounds[i:]...)...)
+	}
+
+	chapters := make([]service.Chapter, 0, len(bounds)-1)
+	for i := 0; i+1 < len(bounds); i++ {
+		chapters = append(chapters, service.Chapter{
+			Title:     fmt.Sprintf("Chapter %d", i+1),
+			StartTime: bounds[i],
+			EndTime:   bounds[i+1],
+		})
+	}
+	return chapters
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
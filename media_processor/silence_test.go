@@ -0,0 +1,86 @@
+package media_processor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/service"
+)
+
+const silencedetectOutput = `Input #0, mp3, from 'in.mp3':
+  Duration: 00:10:00.00, start: 0.000000, bitrate: 64 kb/s
+[silencedetect @ 0x5581] silence_start: -0.00133
+[silencedetect @ 0x5581] silence_end: 4.5 | silence_duration: 4.50133
+[silencedetect @ 0x5581] silence_start: 120
+[silencedetect @ 0x5581] silence_end: 122 | silence_duration: 2
+[silencedetect @ 0x5581] silence_start: 300
+[silencedetect @ 0x5581] silence_end: 305 | silence_duration: 5
+[silencedetect @ 0x5581] silence_start: 590
+size=N/A time=00:10:00.00 bitrate=N/A speed= 412x
+`
+
+func TestParseSilencedetectOutput(t *testing.T) {
+	got := parseSilencedetectOutput(silencedetectOutput)
+	want := []silence{
+		{start: 0, end: 4500 * time.Millisecond},
+		{start: 120 * time.Second, end: 122 * time.Second},
+		{start: 300 * time.Second, end: 305 * time.Second},
+		{start: 590 * time.Second, end: -1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestTrimSilenceFilter(t *testing.T) {
+	silences := parseSilencedetectOutput(silencedetectOutput)
+	opts := service.TrimSilenceOptions{SilenceOptions: service.SilenceOptions{NoiseDB: -50, MinSilence: time.Second}}
+
+	if got, want := trimSilenceFilter(silences, 10*time.Minute, opts), "atrim=start=4.5:end=590,asetpts=PTS-STARTPTS"; got != want {
+		t.Errorf("edges: got %q, want %q", got, want)
+	}
+
+	opts.MaxInnerSilence = 3 * time.Second
+	want := "atrim=start=4.5:end=590,asetpts=PTS-STARTPTS," +
+		"silenceremove=stop_periods=-1:stop_duration=3:stop_threshold=-50dB:stop_silence=3"
+	if got := trimSilenceFilter(silences, 10*time.Minute, opts); got != want {
+		t.Errorf("inner: got %q, want %q", got, want)
+	}
+
+	if got := trimSilenceFilter(silences[1:2], 10*time.Minute, opts); got != "" {
+		t.Errorf("nothing to trim: got %q", got)
+	}
+	if got := trimSilenceFilter([]silence{{start: 0, end: -1}}, 10*time.Minute, opts); got != "" {
+		t.Errorf("silent file: got %q", got)
+	}
+}
+
+func TestSilenceChapters(t *testing.T) {
+	silences := parseSilencedetectOutput(silencedetectOutput)
+
+	got := silenceChapters(silences, 10*time.Minute, time.Minute)
+	want := []service.Chapter{
+		{Title: "Chapter 1", StartTime: 0, EndTime: 121 * time.Second},
+		{Title: "Chapter 2", StartTime: 121 * time.Second, EndTime: 302500 * time.Millisecond},
+		{Title: "Chapter 3", StartTime: 302500 * time.Millisecond, EndTime: 10 * time.Minute},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// the longer pause wins when both can't be chapter boundaries
+	got = silenceChapters(silences, 10*time.Minute, 4*time.Minute)
+	want = []service.Chapter{
+		{Title: "Chapter 1", StartTime: 0, EndTime: 302500 * time.Millisecond},
+		{Title: "Chapter 2", StartTime: 302500 * time.Millisecond, EndTime: 10 * time.Minute},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	got = silenceChapters(nil, 10*time.Minute, time.Minute)
+	if len(got) != 1 || got[0].EndTime != 10*time.Minute {
+		t.Errorf("no silences: got %+v", got)
+	}
+}
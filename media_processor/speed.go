@@ -0,0 +1,135 @@
+package media_processor
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dir01/mediary/service"
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxAtempo is the largest factor a single atempo filter takes without skipping samples,
+// larger ones are reached by chaining filters
+const maxAtempo = 2.0
+
+// ChangeSpeed re-encodes the file with atempo, which keeps the pitch, into its own container
+// or into opts.Container when ours can't write the file's one.
+// ffmpeg copies chapters as they are, so they are replaced with scaled ones.
+func (conv *FFMpegMediaProcessor) ChangeSpeed(ctx context.Context, fp string, opts service.SpeedOptions) (string, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.ChangeSpeed",
+		trace.WithAttributes(
+			attribute.String("filepath", fp),
+			attribute.Float64("speed", opts.Speed),
+			attribute.String("container", opts.Container),
+		),
+	)
+	defer span.End()
+
+	speed := opts.Speed
+	errCtx := oops.With("filepath", fp, "opts", opts)
+	fail := func(err error) (string, error) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+
+	container, ok := service.ContainerByExt(filepath.Ext(fp))
+	if !ok {
+		var err error
+		if container, err = service.LookupContainer(opts.Container); err != nil {
+			return fail(errCtx.Wrapf(err, "no container for %s", filepath.Ext(fp)))
+		}
+	}
+	probe, err := conv.probeAudio(ctx, fp)
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to probe file"))
+	}
+	chaptersFilepath, err := conv.writeScaledChapters(ctx, fp, speed)
+	if err != nil {
+		return fail(errCtx.Wrap(err))
+	}
+	if chaptersFilepath != "" {
+		defer func() { _ = os.Remove(chaptersFilepath) }()
+	}
+
+	file, err := os.CreateTemp("", "*"+container.Ext)
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to create temp file"))
+	}
+	_ = file.Close()
+	resultFilepath := file.Name()
+	errCtx = errCtx.With("resultFilepath", resultFilepath)
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", speedArgs(fp, chaptersFilepath, resultFilepath, speed, probe, container)...)
+	conv.log.Debug("changing speed", slog.String("filepath", fp), slog.String("cmd", cmd.String()))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		_ = os.Remove(resultFilepath)
+		return fail(errCtx.With("cmd", cmd.String(), "output", string(output)).Wrapf(err, "failed to run ffmpeg"))
+	}
+	return resultFilepath, nil
+}
+
+func speedArgs(input, chaptersFilepath, output string, speed float64, probe audioProbe, container service.Container) []string {
+	args := []string{"-y", "-hide_banner", "-nostats", "-i", input}
+	if chaptersFilepath != "" {
+		args = append(args, "-i", chaptersFilepath, "-map_chapters", "1")
+	}
+	// cover art is kept where it can be written
+	args = append(args, "-map", "0:a:0")
+	if coverArtExts[container.Ext] {
+		args = append(args, "-map", "0:v?", "-c:v", "copy")
+	}
+	args = append(args, "-map_metadata", "0", "-af", atempoFilter(speed))
+	args = append(args, reencodeArgs(probe, container)...)
+	return append(args, output)
+}
+
+// atempoFilter chains atempo filters, so that each of them stays within [0.5, 2]
+func atempoFilter(speed float64) string {
+	var filters []string
+	for speed > maxAtempo {
+		filters = append(filters, "atempo="+formatFloat(maxAtempo))
+		speed /= maxAtempo
+	}
+	return strings.Join(append(filters, "atempo="+formatFloat(speed)), ",")
+}
+
+// writeScaledChapters writes chapters of the file, scaled to the speed, into an ffmetadata file.
+// It returns an empty path when the file has no chapters.
+func (conv *FFMpegMediaProcessor) writeScaledChapters(ctx context.Context, fp string, speed float64) (string, error) {
+	chapters, err := conv.readChapters(ctx, fp)
+	if err != nil || len(chapters) == 0 {
+		return "", err
+	}
+	file, err := os.CreateTemp("", "*.ffmetadata")
+	if err != nil {
+		return "", oops.Wrapf(err, "failed to create ffmetadata file")
+	}
+	if _, err := file.WriteString(buildFFMetadata(service.Tags{}, service.ScaleChapters(chapters, speed))); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return "", oops.Wrapf(err, "failed to write ffmetadata file")
+	}
+	return file.Name(), file.Close()
+}
+
+func (conv *FFMpegMediaProcessor) readChapters(ctx context.Context, fp string) ([]service.Chapter, error) {
+	info, err := conv.probe(ctx, fp)
+	if err != nil {
+		return nil, err
+	}
+	return info.Chapters, nil
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
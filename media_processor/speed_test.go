@@ -0,0 +1,49 @@
+package media_processor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dir01/mediary/service"
+)
+
+func TestAtempoFilter(t *testing.T) {
+	for speed, want := range map[float64]string{
+		0.5:  "atempo=0.5",
+		1.25: "atempo=1.25",
+		2:    "atempo=2",
+		3:    "atempo=2,atempo=1.5",
+		4:    "atempo=2,atempo=2",
+	} {
+		if got := atempoFilter(speed); got != want {
+			t.Errorf("atempoFilter(%g) = %q, want %q", speed, got, want)
+		}
+	}
+}
+
+func TestSpeedArgs(t *testing.T) {
+	container, _ := service.ContainerByExt(".mp3")
+	probe := audioProbe{FormatName: "mp3", Codec: "mp3", SampleRate: 44100, Channels: 1, BitRate: 64000}
+
+	got := speedArgs("in.mp3", "chapters.ffmetadata", "out.mp3", 1.5, probe, container)
+	want := []string{
+		"-y", "-hide_banner", "-nostats", "-i", "in.mp3", "-i", "chapters.ffmetadata", "-map_chapters", "1",
+		"-map", "0:a:0", "-map", "0:v?", "-c:v", "copy", "-map_metadata", "0", "-af", "atempo=1.5",
+		"-c:a", "libmp3lame", "-b:a", "64000", "-ar", "44100", "out.mp3",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// ffmpeg can't write pictures into opus
+	container, _ = service.ContainerByExt(".opus")
+	got = speedArgs("in.mp3", "", "out.opus", 1.5, probe, container)
+	want = []string{
+		"-y", "-hide_banner", "-nostats", "-i", "in.mp3",
+		"-map", "0:a:0", "-map_metadata", "0", "-af", "atempo=1.5",
+		"-c:a", "libopus", "-b:a", "64000", "-ar", "48000", "out.opus",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
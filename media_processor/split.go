@@ -0,0 +1,247 @@
+package media_processor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/dir01/mediary/service"
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// sizeMargin leaves room for container overhead and bitrate peaks of VBR files when splitting by size,
+// since the size of a part is only estimated from its duration
+const sizeMargin = 0.95
+
+// maxSizeAttempts is how many times a split by size is planned and cut, each time into shorter parts,
+// before giving up on parts that turn out larger than they may be
+const maxSizeAttempts = 3
+
+// Split cuts the file with stream copy, so parts start at frame boundaries close to the planned ones.
+// Parts of a split by size are only estimated to fit, so the file is split again into shorter parts
+// when any of them turns out too large.
+func (conv *FFMpegMediaProcessor) Split(ctx context.Context, fp string, opts service.SplitOptions) ([]service.SplitPart, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.Split",
+		trace.WithAttributes(
+			attribute.String("filepath", fp),
+			attribute.String("mode", opts.Mode),
+		),
+	)
+	defer span.End()
+
+	errCtx := oops.With("filepath", fp, "opts", opts)
+	fail := func(err error) ([]service.SplitPart, error) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	planOpts := opts
+	for attempt := 1; ; attempt++ {
+		parts, err := conv.planSplit(ctx, fp, planOpts)
+		if err != nil {
+			return fail(errCtx.Wrap(err))
+		}
+		span.SetAttributes(attribute.Int("parts.count", len(parts)), attribute.Int("attempts", attempt))
+
+		if err := conv.cutParts(ctx, fp, parts); err != nil {
+			return fail(errCtx.Wrap(err))
+		}
+		if opts.Mode != service.SplitBySize {
+			return parts, nil
+		}
+		largest, err := largestPart(parts)
+		if err != nil {
+			removeParts(parts)
+			return fail(errCtx.Wrap(err))
+		}
+		if largest <= opts.MaxBytes {
+			return parts, nil
+		}
+		removeParts(parts)
+		if attempt == maxSizeAttempts {
+			return fail(errCtx.Errorf("parts are still up to %d bytes large after %d attempts", largest, attempt))
+		}
+
+		// parts are shortened by as much as the largest one is over the limit
+		var longest time.Duration
+		for _, p := range parts {
+			longest = max(longest, p.EndTime-p.StartTime)
+		}
+		conv.log.Debug("parts are too large, splitting again",
+			slog.String("filepath", fp), slog.Int64("largest", largest), slog.Int64("maxBytes", opts.MaxBytes))
+		planOpts.Mode = service.SplitByDuration
+		planOpts.MaxDuration = time.Duration(float64(longest) * float64(opts.MaxBytes) / float64(largest) * sizeMargin)
+	}
+}
+
+// cutParts cuts planned parts into temp files, which are all removed when any of them fails
+func (conv *FFMpegMediaProcessor) cutParts(ctx context.Context, fp string, parts []service.SplitPart) error {
+	for i := range parts {
+		file, err := os.CreateTemp("", fmt.Sprintf("*-part%03d%s", i+1, filepath.Ext(fp)))
+		if err != nil {
+			removeParts(parts[:i])
+			return oops.Wrapf(err, "failed to create temp file")
+		}
+		_ = file.Close()
+		parts[i].Filepath = file.Name()
+
+		cmd := exec.CommandContext(ctx, "ffmpeg", splitArgs(fp, parts[i], i, len(parts))...)
+		conv.log.Debug("cutting part", slog.String("filepath", fp), slog.Int("part", i+1), slog.String("cmd", cmd.String()))
+		if output, err := cmd.CombinedOutput(); err != nil {
+			removeParts(parts[:i+1])
+			return oops.With("cmd", cmd.String(), "output", string(output)).Wrapf(err, "failed to run ffmpeg")
+		}
+	}
+	return nil
+}
+
+// largestPart is the size of the largest of the cut parts
+func largestPart(parts []service.SplitPart) (int64, error) {
+	var largest int64
+	for _, p := range parts {
+		stat, err := os.Stat(p.Filepath)
+		if err != nil {
+			return 0, oops.Wrapf(err, "failed to stat part")
+		}
+		largest = max(largest, stat.Size())
+	}
+	return largest, nil
+}
+
+// planSplit finds where parts start and end, without cutting anything
+func (conv *FFMpegMediaProcessor) planSplit(ctx context.Context, fp string, opts service.SplitOptions) ([]service.SplitPart, error) {
+	duration, err := conv.GetDuration(fp)
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to get duration")
+	}
+
+	var maxPart time.Duration
+	switch opts.Mode {
+	case service.SplitByChapters:
+		chapters, err := conv.readChapters(ctx, fp)
+		if err != nil {
+			return nil, err
+		}
+		if len(chapters) == 0 {
+			return nil, oops.Errorf("file has no chapters")
+		}
+		return chapterParts(chapters, duration), nil
+	case service.SplitByDuration:
+		maxPart = opts.MaxDuration
+	case service.SplitBySize:
+		stat, err := os.Stat(fp)
+		if err != nil {
+			return nil, oops.Wrapf(err, "failed to stat file")
+		}
+		maxPart = time.Duration(float64(duration) * float64(opts.MaxBytes) / float64(stat.Size()) * sizeMargin)
+	default:
+		return nil, oops.Errorf("unknown split mode: %s", opts.Mode)
+	}
+	if maxPart <= 0 {
+		return nil, oops.Errorf("parts can't be empty")
+	}
+
+	var silences []silence
+	if opts.SilenceWindow > 0 && duration > maxPart {
+		if silences, _, err = conv.detectSilences(ctx, fp, opts.Silence); err != nil {
+			return nil, err
+		}
+	}
+	return pointsToParts(splitPoints(duration, maxPart, silences, opts.SilenceWindow), duration), nil
+}
+
+// splitPoints divides media into parts of even duration, no longer than maxPart.
+// With silences, every boundary is moved back to the middle of the latest pause within the window before it,
+// and the rest of media is divided evenly again.
+func splitPoints(duration, maxPart time.Duration, silences []silence, window time.Duration) []time.Duration {
+	var points []time.Duration
+	var prev time.Duration
+	for duration-prev > maxPart {
+		remaining := duration - prev
+		partsLeft := (remaining + maxPart - 1) / maxPart
+		at := prev + remaining/partsLeft
+		if window > 0 {
+			at = latestPause(silences, max(at-window, prev), at)
+		}
+		points = append(points, at)
+		prev = at
+	}
+	return points
+}
+
+// latestPause returns the middle of the latest pause within (from, to], or to when there is none
+func latestPause(silences []silence, from, to time.Duration) time.Duration {
+	found := time.Duration(-1)
+	for _, s := range silences {
+		if s.end == -1 {
+			continue
+		}
+		if at := s.start + (s.end-s.start)/2; at > from && at <= to && at > found {
+			found = at
+		}
+	}
+	if found == -1 {
+		return to
+	}
+	return found
+}
+
+func pointsToParts(points []time.Duration, duration time.Duration) []service.SplitPart {
+	bounds := append(append([]time.Duration{0}, points...), duration)
+	parts := make([]service.SplitPart, 0, len(bounds)-1)
+	for i := 0; i+1 < len(bounds); i++ {
+		parts = append(parts, service.SplitPart{StartTime: bounds[i], EndTime: bounds[i+1]})
+	}
+	return parts
+}
+
+func chapterParts(chapters []service.Chapter, duration time.Duration) []service.SplitPart {
+	parts := make([]service.SplitPart, 0, len(chapters))
+	for i, ch := range chapters {
+		// gaps between chapters are kept within the previous part, so that nothing gets lost
+		end := duration
+		if i+1 < len(chapters) {
+			end = chapters[i+1].StartTime
+		}
+		start := ch.StartTime
+		if i == 0 {
+			start = 0
+		}
+		parts = append(parts, service.SplitPart{Title: ch.Title, StartTime: start, EndTime: end})
+	}
+	return parts
+}
+
+// splitArgs cut the part with stream copy. Chapters of the original would be misplaced, so they are dropped,
+// and parts are numbered as tracks instead.
+func splitArgs(input string, part service.SplitPart, index, total int) []string {
+	args := []string{
+		"-y", "-hide_banner", "-nostats",
+		"-ss", formatFloat(part.StartTime.Seconds()), "-i", input,
+		"-t", formatFloat((part.EndTime - part.StartTime).Seconds()),
+		"-map", "0:a", "-map", "0:V?", "-map_metadata", "0", "-map_chapters", "-1",
+		"-metadata", "track=" + strconv.Itoa(index+1) + "/" + strconv.Itoa(total),
+	}
+	if part.Title != "" {
+		args = append(args, "-metadata", "title="+part.Title)
+	}
+	return append(args, "-c", "copy", part.Filepath)
+}
+
+func removeParts(parts []service.SplitPart) {
+	for _, p := range parts {
+		if p.Filepath != "" {
+			_ = os.Remove(p.Filepath)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package media_processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/service"
+)
+
+func TestSplitPoints(t *testing.T) {
+	hour := time.Hour
+	for _, tc := range []struct {
+		name     string
+		duration time.Duration
+		maxPart  time.Duration
+		silences []silence
+		window   time.Duration
+		want     []time.Duration
+	}{
+		{name: "fits", duration: 3 * hour, maxPart: 4 * hour},
+		{name: "even parts", duration: 10 * hour, maxPart: 4 * hour, want: []time.Duration{200 * time.Minute, 400 * time.Minute}},
+		{
+			name:     "moved back to pauses",
+			duration: 10 * hour,
+			maxPart:  4 * hour,
+			silences: []silence{
+				{start: 190 * time.Minute, end: 190*time.Minute + 2*time.Second},
+				{start: 196 * time.Minute, end: 196*time.Minute + 2*time.Second},
+				// past the boundary
+				{start: 201 * time.Minute, end: 201*time.Minute + 2*time.Second},
+			},
+			window: 5 * time.Minute,
+			// the rest is divided evenly again
+			want: []time.Duration{196*time.Minute + time.Second, 398*time.Minute + 500*time.Millisecond},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := splitPoints(tc.duration, tc.maxPart, tc.silences, tc.window)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				prev := time.Duration(0)
+				if i > 0 {
+					prev = got[i-1]
+				}
+				if got[i]-prev > tc.maxPart {
+					t.Errorf("part %d is longer than %v", i+1, tc.maxPart)
+				}
+			}
+		})
+	}
+}
+
+func TestChapterParts(t *testing.T) {
+	chapters := []service.Chapter{
+		{Title: "One", StartTime: 500 * time.Millisecond, EndTime: time.Minute},
+		{Title: "Two", StartTime: 61 * time.Second, EndTime: 2 * time.Minute},
+	}
+	want := []service.SplitPart{
+		{Title: "One", StartTime: 0, EndTime: 61 * time.Second},
+		{Title: "Two", StartTime: 61 * time.Second, EndTime: 3 * time.Minute},
+	}
+	if got := chapterParts(chapters, 3*time.Minute); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestLargestPart(t *testing.T) {
+	dir := t.TempDir()
+	var parts []service.SplitPart
+	for i, size := range []int{10, 30, 20} {
+		fp := filepath.Join(dir, fmt.Sprintf("part%d.mp3", i))
+		if err := os.WriteFile(fp, make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		parts = append(parts, service.SplitPart{Filepath: fp})
+	}
+	if got, err := largestPart(parts); err != nil || got != 30 {
+		t.Errorf("got %d, %v, want 30", got, err)
+	}
+
+	parts = append(parts, service.SplitPart{Filepath: filepath.Join(dir, "missing.mp3")})
+	if _, err := largestPart(parts); err == nil {
+		t.Error("expected an error for a missing part")
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	part := service.SplitPart{Filepath: "part2.mp3", Title: "Two", StartTime: 90 * time.Second, EndTime: 150500 * time.Millisecond}
+	want := []string{
+		"-y", "-hide_banner", "-nostats", "-ss", "90", "-i", "in.mp3", "-t", "60.5",
+		"-map", "0:a", "-map", "0:V?", "-map_metadata", "0", "-map_chapters", "-1",
+		"-metadata", "track=2/3", "-metadata", "title=Two", "-c", "copy", "part2.mp3",
+	}
+	if got := splitArgs("in.mp3", part, 1, 3); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
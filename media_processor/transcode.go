@@ -0,0 +1,129 @@
+package media_processor
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/dir01/mediary/service"
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// losslessCodecs ignore bitrate and quality settings
+var losslessCodecs = map[string]bool{
+	"flac":      true,
+	"alac":      true,
+	"pcm_s16le": true,
+	"pcm_s24le": true,
+	"copy":      true,
+}
+
+func (conv *FFMpegMediaProcessor) Transcode(ctx context.Context, filepath string, opts service.TranscodeOptions) (string, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.Transcode",
+		trace.WithAttributes(
+			attribute.String("filepath", filepath),
+			attribute.String("container", opts.Container),
+			attribute.String("audio_codec", opts.AudioCodec),
+		),
+	)
+	defer span.End()
+
+	errCtx := oops.With("filepath", filepath, "opts", opts)
+	logAttrs := []any{slog.String("filepath", filepath), slog.Any("opts", opts)}
+
+	container, err := service.LookupContainer(opts.Container)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", errCtx.Wrap(err)
+	}
+
+	file, err := os.CreateTemp("", "*"+container.Ext)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", errCtx.Wrapf(err, "failed to create temp file")
+	}
+	_ = file.Close()
+	resultFilepath := file.Name()
+	errCtx = errCtx.With("resultFilepath", resultFilepath)
+	span.SetAttributes(attribute.String("result.filepath", resultFilepath))
+
+	var chaptersFilepath string
+	if opts.Speed != 0 && opts.Speed != 1 {
+		if chaptersFilepath, err = conv.writeScaledChapters(ctx, filepath, opts.Speed); err != nil {
+			_ = os.Remove(resultFilepath)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return "", errCtx.Wrap(err)
+		}
+		if chaptersFilepath != "" {
+			defer func() { _ = os.Remove(chaptersFilepath) }()
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", transcodeArgs(filepath, chaptersFilepath, resultFilepath, container, opts)...)
+	errCtx = errCtx.With("cmd", cmd.String())
+	logAttrs = append(logAttrs, slog.String("cmd", cmd.String()))
+
+	conv.log.Debug("running ffmpeg", logAttrs...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		_ = os.Remove(resultFilepath)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", errCtx.With("output", string(output)).Wrapf(err, "failed to run ffmpeg")
+	}
+	conv.log.Debug("ffmpeg finished successfully", logAttrs...)
+
+	return resultFilepath, nil
+}
+
+// transcodeArgs take chapters from chaptersFilepath when it is given, which is how speed changes get scaled chapters
+func transcodeArgs(input, chaptersFilepath, output string, container service.Container, opts service.TranscodeOptions) []string {
+	audioCodec := opts.AudioCodec
+	if audioCodec == "" {
+		audioCodec = container.DefaultAudioCodec
+	}
+	changeSpeed := opts.Speed != 0 && opts.Speed != 1
+
+	args := []string{"-y", "-i", input}
+	if chaptersFilepath != "" {
+		args = append(args, "-i", chaptersFilepath, "-map_chapters", "1")
+	}
+	if container.DefaultVideoCodec == "" {
+		// audio-only containers: drop video streams, including embedded cover art
+		args = append(args, "-vn")
+	} else {
+		// "V" leaves out attached pictures: cover art is not a video to be encoded and sped up,
+		// and video containers are not given any, see coverArtExts
+		args = append(args, "-map", "0:V?", "-map", "0:a?", "-c:v", container.DefaultVideoCodec)
+		if changeSpeed {
+			args = append(args, "-filter:v", "setpts=PTS/"+formatFloat(opts.Speed))
+		}
+	}
+	if changeSpeed {
+		args = append(args, "-filter:a", atempoFilter(opts.Speed))
+	}
+	args = append(args, "-c:a", audioCodec)
+
+	if !losslessCodecs[audioCodec] {
+		if opts.Quality != nil {
+			args = append(args, "-q:a", strconv.Itoa(*opts.Quality))
+		} else if opts.Bitrate != "" {
+			args = append(args, "-b:a", opts.Bitrate)
+		}
+	}
+	if opts.SampleRate != 0 {
+		args = append(args, "-ar", strconv.Itoa(opts.SampleRate))
+	}
+	if opts.Channels != 0 {
+		args = append(args, "-ac", strconv.Itoa(opts.Channels))
+	}
+	return append(args, output)
+}
@@ -0,0 +1,58 @@
+package media_processor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dir01/mediary/service"
+)
+
+func TestTranscodeArgs(t *testing.T) {
+	quality := 2
+	for _, tc := range []struct {
+		name     string
+		opts     service.TranscodeOptions
+		chapters string
+		want     []string
+	}{
+		{
+			name: "audio container drops video and applies everything",
+			opts: service.TranscodeOptions{Container: "mp3", AudioCodec: "libmp3lame", Bitrate: "64k", SampleRate: 44100, Channels: 1},
+			want: []string{"-y", "-i", "in.mkv", "-vn", "-c:a", "libmp3lame", "-b:a", "64k", "-ar", "44100", "-ac", "1", "out"},
+		},
+		{
+			name: "quality wins over bitrate",
+			opts: service.TranscodeOptions{Container: "mp3", Bitrate: "64k", Quality: &quality},
+			want: []string{"-y", "-i", "in.mkv", "-vn", "-c:a", "libmp3lame", "-q:a", "2", "out"},
+		},
+		{
+			name: "lossless codec ignores bitrate",
+			opts: service.TranscodeOptions{Container: "flac", Bitrate: "64k"},
+			want: []string{"-y", "-i", "in.mkv", "-vn", "-c:a", "flac", "out"},
+		},
+		{
+			name: "video container keeps video",
+			opts: service.TranscodeOptions{Container: "webm"},
+			want: []string{"-y", "-i", "in.mkv", "-map", "0:V?", "-map", "0:a?", "-c:v", "libvpx-vp9", "-c:a", "libopus", "out"},
+		},
+		{
+			name:     "speed scales audio, video and chapters",
+			opts:     service.TranscodeOptions{Container: "webm", Speed: 1.5},
+			chapters: "chapters.ffmetadata",
+			want: []string{
+				"-y", "-i", "in.mkv", "-i", "chapters.ffmetadata", "-map_chapters", "1",
+				"-map", "0:V?", "-map", "0:a?", "-c:v", "libvpx-vp9", "-filter:v", "setpts=PTS/1.5", "-filter:a", "atempo=1.5", "-c:a", "libopus", "out",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			container, err := service.LookupContainer(tc.opts.Container)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := transcodeArgs("in.mkv", tc.chapters, "out", container, tc.opts); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
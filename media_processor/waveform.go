@@ -0,0 +1,202 @@
+package media_processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/dir01/mediary/service"
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ComputePeaks decodes the audio into 16-bit mono PCM at its own sample rate, and reads it as it is decoded,
+// so that hours of audio are never kept in memory or on disk
+func (conv *FFMpegMediaProcessor) ComputePeaks(ctx context.Context, fp string, opts service.WaveformOptions) (*service.WaveformPeaks, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.ComputePeaks",
+		trace.WithAttributes(
+			attribute.String("filepath", fp),
+			attribute.Int("pixels_per_second", opts.PixelsPerSecond),
+		),
+	)
+	defer span.End()
+
+	errCtx := oops.With("filepath", fp, "opts", opts)
+	fail := func(err error) (*service.WaveformPeaks, error) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	probe, err := conv.probeAudio(ctx, fp)
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to probe file"))
+	}
+	sampleRate := probe.SampleRate
+	if sampleRate == 0 {
+		sampleRate = 44100
+	}
+	samplesPerPixel := max(1, sampleRate/opts.PixelsPerSecond)
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", peaksArgs(fp, sampleRate)...)
+	errCtx = errCtx.With("cmd", cmd.String())
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to pipe ffmpeg output"))
+	}
+	conv.log.Debug("computing peaks", slog.String("filepath", fp), slog.String("cmd", cmd.String()))
+	if err := cmd.Start(); err != nil {
+		return fail(errCtx.Wrapf(err, "failed to run ffmpeg"))
+	}
+	data, samplesPerPixel, readErr := readPeaks(stdout, samplesPerPixel, opts.Bits, service.MaxWaveformPixels)
+	if readErr != nil {
+		// ffmpeg would block writing into a pipe that is no longer read
+		_ = cmd.Process.Kill()
+	}
+	if err := cmd.Wait(); err != nil && readErr == nil {
+		return fail(errCtx.With("output", stderr.String()).Wrapf(err, "failed to run ffmpeg"))
+	}
+	if readErr != nil {
+		return fail(errCtx.Wrapf(readErr, "failed to read samples"))
+	}
+
+	span.SetAttributes(attribute.Int("peaks.length", len(data)/2), attribute.Int("samples_per_pixel", samplesPerPixel))
+	return &service.WaveformPeaks{
+		Version:         2,
+		Channels:        1,
+		SampleRate:      sampleRate,
+		SamplesPerPixel: samplesPerPixel,
+		Bits:            opts.Bits,
+		Length:          len(data) / 2,
+		Data:            data,
+	}, nil
+}
+
+func peaksArgs(input string, sampleRate int) []string {
+	return []string{
+		"-hide_banner", "-nostats", "-v", "error", "-i", input, "-map", "0:a:0",
+		"-ac", "1", "-ar", strconv.Itoa(sampleRate), "-c:a", "pcm_s16le", "-f", "s16le", "-",
+	}
+}
+
+// readPeaks reads 16-bit little-endian samples and returns min and max of every samplesPerPixel of them,
+// scaled down to 8 bits unless bits is 16. The last pixel may have fewer samples.
+// Media too long for maxPixels gets fewer pixels per second: whenever there are maxPixels of them already,
+// every two adjacent pixels are merged into one, and the number of samples per pixel it ends up with is returned.
+func readPeaks(r io.Reader, samplesPerPixel int, bits int, maxPixels int) ([]int16, int, error) {
+	shift := 0
+	if bits == 8 {
+		shift = 8
+	}
+	var data []int16
+	lo, hi, n := int16(math.MaxInt16), int16(math.MinInt16), 0
+	buf := make([]byte, 64<<10)
+	for {
+		read, err := io.ReadFull(r, buf)
+		for i := 0; i+1 < read; i += 2 {
+			sample := int16(binary.LittleEndian.Uint16(buf[i:]))
+			lo, hi, n = min(lo, sample), max(hi, sample), n+1
+			if n < samplesPerPixel {
+				continue
+			}
+			data = append(data, lo>>shift, hi>>shift)
+			lo, hi, n = math.MaxInt16, math.MinInt16, 0
+			if len(data)/2 >= maxPixels {
+				data = mergePixels(data)
+				samplesPerPixel *= 2
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	if n > 0 {
+		data = append(data, lo>>shift, hi>>shift)
+	}
+	return data, samplesPerPixel, nil
+}
+
+// mergePixels merges every two adjacent pixels of min and max pairs into one, in place
+func mergePixels(data []int16) []int16 {
+	merged := data[:0]
+	for i := 0; i < len(data); i += 4 {
+		if i+2 >= len(data) {
+			merged = append(merged, data[i], data[i+1])
+			break
+		}
+		merged = append(merged, min(data[i], data[i+2]), max(data[i+1], data[i+3]))
+	}
+	return merged
+}
+
+// RenderWaveform draws the whole audio into a single PNG frame with showwavespic or showspectrumpic
+func (conv *FFMpegMediaProcessor) RenderWaveform(ctx context.Context, fp string, opts service.WaveformImageOptions) (string, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/media_processor").Start(ctx, "media_processor.RenderWaveform",
+		trace.WithAttributes(
+			attribute.String("filepath", fp),
+			attribute.String("kind", opts.Kind),
+		),
+	)
+	defer span.End()
+
+	errCtx := oops.With("filepath", fp, "opts", opts)
+	fail := func(err error) (string, error) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+
+	args, err := waveformImageArgs(fp, opts)
+	if err != nil {
+		return fail(errCtx.Wrap(err))
+	}
+	file, err := os.CreateTemp("", "*.png")
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to create temp file"))
+	}
+	_ = file.Close()
+	imageFilepath := file.Name()
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", append(args, imageFilepath)...)
+	conv.log.Debug("rendering waveform", slog.String("filepath", fp), slog.String("cmd", cmd.String()))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		_ = os.Remove(imageFilepath)
+		return fail(errCtx.With("cmd", cmd.String(), "output", string(output)).Wrapf(err, "failed to run ffmpeg"))
+	}
+	return imageFilepath, nil
+}
+
+// waveformImageArgs lack the output, which comes last
+func waveformImageArgs(input string, opts service.WaveformImageOptions) ([]string, error) {
+	size := fmt.Sprintf("%dx%d", opts.Width, opts.Height)
+	var filter string
+	switch opts.Kind {
+	case service.WaveformImageWaveform:
+		// channels are drawn over each other, like the mono mix of peaks
+		filter = "[0:a:0]showwavespic=s=" + size
+		if opts.Color != "" {
+			filter += ":colors=" + opts.Color
+		}
+	case service.WaveformImageSpectrogram:
+		filter = "[0:a:0]showspectrumpic=s=" + size + ":legend=0"
+	default:
+		return nil, fmt.Errorf("unknown waveform image: %s", opts.Kind)
+	}
+	return []string{"-y", "-hide_banner", "-nostats", "-i", input, "-filter_complex", filter, "-frames:v", "1"}, nil
+}
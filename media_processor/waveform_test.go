@@ -0,0 +1,75 @@
+package media_processor
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/dir01/mediary/service"
+)
+
+func TestReadPeaks(t *testing.T) {
+	samples := []int16{100, -200, 32767, -32768, 512, 256, -1}
+	var pcm bytes.Buffer
+	if err := binary.Write(&pcm, binary.LittleEndian, samples); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name                string
+		samplesPerPixel     int
+		bits                int
+		maxPixels           int
+		want                []int16
+		wantSamplesPerPixel int
+	}{
+		{name: "16 bits", samplesPerPixel: 2, bits: 16, want: []int16{-200, 100, -32768, 32767, 256, 512, -1, -1}},
+		{name: "8 bits", samplesPerPixel: 2, bits: 8, want: []int16{-1, 0, -128, 127, 1, 2, -1, -1}},
+		{name: "a pixel per file", samplesPerPixel: 100, bits: 16, want: []int16{-32768, 32767}},
+		{
+			name: "too many pixels", samplesPerPixel: 1, bits: 16, maxPixels: 4,
+			want:                []int16{-200, 100, -32768, 32767, 256, 512, -1, -1},
+			wantSamplesPerPixel: 2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			maxPixels := cmp.Or(tc.maxPixels, service.MaxWaveformPixels)
+			got, samplesPerPixel, err := readPeaks(bytes.NewReader(pcm.Bytes()), tc.samplesPerPixel, tc.bits, maxPixels)
+			if err != nil {
+				t.Fatalf("readPeaks: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+			if want := cmp.Or(tc.wantSamplesPerPixel, tc.samplesPerPixel); samplesPerPixel != want {
+				t.Errorf("samples per pixel = %d, want %d", samplesPerPixel, want)
+			}
+		})
+	}
+}
+
+func TestWaveformImageArgs(t *testing.T) {
+	got, err := waveformImageArgs("in.mp3", service.WaveformImageOptions{Kind: "waveform", Width: 1800, Height: 280, Color: "0x3b82f6"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"-y", "-hide_banner", "-nostats", "-i", "in.mp3",
+		"-filter_complex", "[0:a:0]showwavespic=s=1800x280:colors=0x3b82f6", "-frames:v", "1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+
+	got, err = waveformImageArgs("in.mp3", service.WaveformImageOptions{Kind: "spectrogram", Width: 1024, Height: 512})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[6] != "[0:a:0]showspectrumpic=s=1024x512:legend=0" {
+		t.Errorf("unexpected spectrogram filter: %q", got[6])
+	}
+
+	if _, err := waveformImageArgs("in.mp3", service.WaveformImageOptions{Kind: "oscilloscope"}); err == nil {
+		t.Error("expected error for unknown kind")
+	}
+}
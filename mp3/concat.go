@@ -0,0 +1,153 @@
+package mp3
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Stats describe a concatenation
+type Stats struct {
+	Frames   int
+	Duration time.Duration
+	// Skipped counts bytes of junk between frames, tags aside
+	Skipped int64
+}
+
+// Concatenate writes frames of the inputs into w one after another, after an Xing frame (an Info frame,
+// when the bitrate is constant) that describes all of them. Tags and Xing frames of the inputs are left out.
+// Inputs have to share MPEG version, sample rate, and being mono or not, as decoders expect of a single stream.
+// The Xing frame is written last, at the position w was at, so w has to be seekable.
+//
+// The LAME extension tells encoder delay of the first input and padding of the last one, which players trim.
+// Joins are not gapless though: delay and padding of the inputs in between stay in the stream as short silences,
+// since frames can't be cut without decoding them.
+func Concatenate(ctx context.Context, w io.WriteSeeker, inputs []io.Reader) (*Stats, error) {
+	start, err := w.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	c := &concatenator{w: bufio.NewWriterSize(w, 256<<10), cbr: true}
+	for i, input := range inputs {
+		if err := c.copyInput(ctx, input); err != nil {
+			return nil, fmt.Errorf("input %d: %w", i+1, err)
+		}
+	}
+	if c.stream == nil {
+		return nil, fmt.Errorf("no MPEG audio frames in the inputs")
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+
+	h := xingHeader(*c.stream)
+	total := int64(h.size()) + c.audioBytes
+	stats := xingStats{
+		frames:   len(c.sizes),
+		bytes:    total,
+		toc:      xingTOC(c.sizes, h.size(), total),
+		cbr:      c.cbr,
+		lame:     c.firstLame,
+		musicCRC: c.crc,
+	}
+	if c.firstLame != nil {
+		stats.delay = c.firstLame.delay
+	}
+	if c.lastLame != nil {
+		stats.padding = c.lastLame.padding
+	}
+	if _, err := w.Seek(start, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(xingFrame(h, stats)); err != nil {
+		return nil, err
+	}
+	if _, err := w.Seek(0, io.SeekEnd); err != nil {
+		return nil, err
+	}
+
+	return &Stats{
+		Frames:   len(c.sizes),
+		Duration: samplesDuration(int64(len(c.sizes))*int64(c.stream.samples()), c.stream.sampleRate),
+		Skipped:  c.skipped,
+	}, nil
+}
+
+// samplesDuration counts in microseconds, as nanoseconds of long streams overflow int64 when multiplied by samples
+func samplesDuration(samples int64, sampleRate int) time.Duration {
+	return time.Duration(samples*int64(time.Second/time.Microsecond)/int64(sampleRate)) * time.Microsecond
+}
+
+type concatenator struct {
+	w *bufio.Writer
+	// stream is the header of the first audio frame, which the rest have to match
+	stream *header
+	// sizes of audio frames make the seek table
+	sizes      []uint16
+	audioBytes int64
+	crc        uint16
+	cbr        bool
+	// LAME extensions of the first and the last inputs tell encoder delay and padding of the whole stream
+	firstLame, lastLame *lameTag
+	skipped             int64
+}
+
+// copyInput copies audio frames of a single input
+func (c *concatenator) copyInput(ctx context.Context, input io.Reader) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fr := newFrameReader(input)
+	defer func() { c.skipped += fr.skipped }()
+
+	frames := 0
+	var lame *lameTag
+	for i := 0; ; i++ {
+		frame, h, err := fr.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			var isXing bool
+			if isXing, lame = parseXing(frame, h); isXing {
+				continue
+			}
+		}
+
+		if c.stream == nil {
+			c.stream = &h
+			c.firstLame = lame
+			// a placeholder for the Xing frame, which is only known in the end
+			if _, err := c.w.Write(make([]byte, xingHeader(h).size())); err != nil {
+				return err
+			}
+		} else if !h.sameStream(*c.stream) {
+			return fmt.Errorf("%s doesn't match %s of the first input", h, *c.stream)
+		}
+		if h.bitrateIndex != c.stream.bitrateIndex {
+			c.cbr = false
+		}
+
+		if _, err := c.w.Write(frame); err != nil {
+			return err
+		}
+		c.sizes = append(c.sizes, uint16(len(frame)))
+		c.audioBytes += int64(len(frame))
+		c.crc = updateCRC16(c.crc, frame)
+		if frames++; frames%4096 == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+	if frames == 0 {
+		return fmt.Errorf("no MPEG audio frames")
+	}
+	c.lastLame = lame
+	return nil
+}
@@ -0,0 +1,220 @@
+package mp3
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConcatenate(t *testing.T) {
+	first := [][]byte{testFrame(9, 0, 1), testFrame(9, 0, 2), testFrame(9, 0, 3)}
+	second := [][]byte{testFrame(9, 0, 4), testFrame(9, 0, 5)}
+	var input1, input2 bytes.Buffer
+	input1.Write(testID3v2(100))
+	input1.Write(testInfoFrame(576, 100))
+	input1.Write(bytes.Join(first, nil))
+	input1.Write(testID3v1())
+	input2.Write([]byte("garbage"))
+	input2.Write(testInfoFrame(576, 1234))
+	input2.Write(bytes.Join(second, nil))
+	input2.Write(testAPETag([]byte("items")))
+
+	// the output starts with a tag of chapters, which the Xing frame follows
+	output, err := os.Create(filepath.Join(t.TempDir(), "output.mp3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = output.Close() }()
+	prefix := testID3v2(50)
+	if _, err := output.Write(prefix); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := Concatenate(context.Background(), output, []io.Reader{&input1, &input2})
+	if err != nil {
+		t.Fatalf("Concatenate: %v", err)
+	}
+	wantStats := Stats{Frames: 5, Duration: 130612 * time.Microsecond, Skipped: int64(len("garbage"))}
+	if *stats != wantStats {
+		t.Errorf("stats = %+v, want %+v", *stats, wantStats)
+	}
+
+	data, err := os.ReadFile(output.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, prefix) {
+		t.Fatal("the output doesn't start where the writer was")
+	}
+	data = data[len(prefix):]
+	h, ok := parseHeader(data)
+	if !ok {
+		t.Fatal("no Xing frame")
+	}
+	xing, audio := data[:h.size()], data[h.size():]
+	if want := bytes.Join(append(first, second...), nil); !bytes.Equal(audio, want) {
+		t.Errorf("frames are not copied as they are: %d bytes, want %d", len(audio), len(want))
+	}
+
+	isXing, lame := parseXing(xing, h)
+	if !isXing || lame == nil {
+		t.Fatal("no Xing frame with a LAME extension")
+	}
+	pos := 4 + h.sideInfoSize()
+	if id := string(xing[pos : pos+4]); id != "Info" {
+		t.Errorf("constant bitrate stream has %s frame", id)
+	}
+	if frames := binary.BigEndian.Uint32(xing[pos+8:]); frames != 5 {
+		t.Errorf("frames = %d, want 5", frames)
+	}
+	if size := binary.BigEndian.Uint32(xing[pos+12:]); int(size) != len(data) {
+		t.Errorf("bytes = %d, want %d", size, len(data))
+	}
+	// every frame is a fifth of the duration
+	toc := xing[pos+16 : pos+116]
+	for i, percent := range []int{0, 20, 40, 60, 80} {
+		want := byte((len(xing) + i*len(first[0])) * 256 / len(data))
+		if toc[percent] != want {
+			t.Errorf("toc[%d] = %d, want %d", percent, toc[percent], want)
+		}
+	}
+	// delay of the first input, and padding of the last one
+	if lame.delay != 576 || lame.padding != 1234 {
+		t.Errorf("delay %d, padding %d", lame.delay, lame.padding)
+	}
+	if lowpass := lame.raw[10]; lowpass != 160 {
+		t.Errorf("encoder settings are not kept: lowpass = %d", lowpass)
+	}
+	if length := binary.BigEndian.Uint32(lame.raw[28:]); int(length) != len(data) {
+		t.Errorf("music length = %d, want %d", length, len(data))
+	}
+	if crc := binary.BigEndian.Uint16(lame.raw[32:]); crc != updateCRC16(0, audio) {
+		t.Errorf("music CRC = %x, want %x", crc, updateCRC16(0, audio))
+	}
+	if crc := binary.BigEndian.Uint16(lame.raw[34:]); crc != updateCRC16(0, xing[:pos+xingSize+34]) {
+		t.Errorf("tag CRC = %x, want %x", crc, updateCRC16(0, xing[:pos+xingSize+34]))
+	}
+}
+
+func TestConcatenate_InnerInputs(t *testing.T) {
+	output, err := os.Create(filepath.Join(t.TempDir(), "output.mp3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = output.Close() }()
+
+	// Info frames of the inputs in the middle are left out along with the rest
+	var frames [][]byte
+	var inputs []io.Reader
+	for i, lame := range [][2]int{{576, 100}, {1105, 200}, {529, 1234}} {
+		input := [][]byte{testFrame(9, 0, byte(2*i)), testFrame(9, 0, byte(2*i+1))}
+		frames = append(frames, input...)
+		inputs = append(inputs, bytes.NewReader(append(testInfoFrame(lame[0], lame[1]), bytes.Join(input, nil)...)))
+	}
+	stats, err := Concatenate(context.Background(), output, inputs)
+	if err != nil {
+		t.Fatalf("Concatenate: %v", err)
+	}
+	if stats.Frames != 6 {
+		t.Errorf("frames = %d, want 6", stats.Frames)
+	}
+
+	data, err := os.ReadFile(output.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, _ := parseHeader(data)
+	if audio := data[h.size():]; !bytes.Equal(audio, bytes.Join(frames, nil)) {
+		t.Errorf("frames are not copied as they are: %d bytes, want %d", len(audio), len(bytes.Join(frames, nil)))
+	}
+	// delay and padding of the middle input are not the stream's
+	_, lame := parseXing(data[:h.size()], h)
+	if lame == nil || lame.delay != 576 || lame.padding != 1234 {
+		t.Errorf("LAME extension %+v, want delay 576 and padding 1234", lame)
+	}
+}
+
+func TestSamplesDuration(t *testing.T) {
+	// 100 hours at 48 kHz, which overflows when counted in nanoseconds
+	if d := samplesDuration(100*3600*48000, 48000); d != 100*time.Hour {
+		t.Errorf("duration = %s, want 100h", d)
+	}
+}
+
+func TestConcatenate_VariableBitrate(t *testing.T) {
+	output, err := os.Create(filepath.Join(t.TempDir(), "output.mp3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = output.Close() }()
+
+	inputs := []io.Reader{bytes.NewReader(testFrame(9, 0, 1)), bytes.NewReader(testFrame(14, 0, 2))}
+	if _, err := Concatenate(context.Background(), output, inputs); err != nil {
+		t.Fatalf("Concatenate: %v", err)
+	}
+	data, err := os.ReadFile(output.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, _ := parseHeader(data)
+	pos := 4 + h.sideInfoSize()
+	if id := string(data[pos : pos+4]); id != "Xing" {
+		t.Errorf("variable bitrate stream has %s frame", id)
+	}
+}
+
+func TestConcatenate_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		inputs [][]byte
+	}{
+		{name: "different sample rates", inputs: [][]byte{testFrame(9, 0, 1), testFrame(9, 1, 1)}},
+		{name: "no frames", inputs: [][]byte{testFrame(9, 0, 1), testID3v1()}},
+		{name: "nothing but an Info frame", inputs: [][]byte{testInfoFrame(576, 0)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := os.Create(filepath.Join(t.TempDir(), "output.mp3"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = output.Close() }()
+
+			inputs := make([]io.Reader, len(tc.inputs))
+			for i, input := range tc.inputs {
+				inputs[i] = bytes.NewReader(input)
+			}
+			if _, err := Concatenate(context.Background(), output, inputs); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+// BenchmarkConcatenate joins ten 25-minute inputs of 128 kbps
+func BenchmarkConcatenate(b *testing.B) {
+	input := bytes.Repeat(testFrame(9, 0, 1), 25*60*44100/1152)
+	output, err := os.Create(filepath.Join(b.TempDir(), "output.mp3"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer func() { _ = output.Close() }()
+
+	b.SetBytes(int64(len(input)) * 10)
+	for b.Loop() {
+		if _, err := output.Seek(0, io.SeekStart); err != nil {
+			b.Fatal(err)
+		}
+		inputs := make([]io.Reader, 10)
+		for i := range inputs {
+			inputs[i] = bytes.NewReader(input)
+		}
+		if _, err := Concatenate(context.Background(), output, inputs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+// Package mp3 joins MPEG-1/2/2.5 Layer III streams frame by frame, without decoding them.
+// See http://www.mp3-tech.org/programmer/frame_header.html
+package mp3
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// MPEG versions, as numbered in frame headers
+const (
+	mpeg25 = 0
+	mpeg2  = 2
+	mpeg1  = 3
+)
+
+const channelModeMono = 3
+
+// bitrates are in kbps, by bitrate index of Layer III frames. Index 0 is free format, which is not supported.
+var bitrates = map[bool][15]int{
+	true:  {0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+	false: {0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+}
+
+var sampleRates = map[int][3]int{
+	mpeg1:  {44100, 48000, 32000},
+	mpeg2:  {22050, 24000, 16000},
+	mpeg25: {11025, 12000, 8000},
+}
+
+// header is a parsed 4-byte frame header
+type header struct {
+	raw          uint32
+	version      int
+	bitrateIndex int
+	sampleRate   int
+	padding      bool
+	channelMode  int
+}
+
+func parseHeader(b []byte) (header, bool) {
+	if len(b) < 4 || b[0] != 0xFF || b[1]&0xE0 != 0xE0 {
+		return header{}, false
+	}
+	h := header{
+		raw:          binary.BigEndian.Uint32(b),
+		version:      int(b[1]>>3) & 3,
+		bitrateIndex: int(b[2] >> 4),
+		padding:      b[2]&0x02 != 0,
+		channelMode:  int(b[3] >> 6),
+	}
+	layer := (b[1] >> 1) & 3
+	sampleRateIndex := (b[2] >> 2) & 3
+	if h.version == 1 || layer != 1 || h.bitrateIndex == 0 || h.bitrateIndex == 15 || sampleRateIndex == 3 {
+		return header{}, false
+	}
+	h.sampleRate = sampleRates[h.version][sampleRateIndex]
+	return h, true
+}
+
+func (h header) bitrate() int {
+	return bitrates[h.version == mpeg1][h.bitrateIndex]
+}
+
+// samples is the number of samples per channel in a frame
+func (h header) samples() int {
+	if h.version == mpeg1 {
+		return 1152
+	}
+	return 576
+}
+
+// size is the length of the frame, header included
+func (h header) size() int {
+	size := h.samples() / 8 * h.bitrate() * 1000 / h.sampleRate
+	if h.padding {
+		size++
+	}
+	return size
+}
+
+// sideInfoSize is the length of side information, which follows the header (and its CRC, if any)
+func (h header) sideInfoSize() int {
+	switch {
+	case h.version == mpeg1 && h.channelMode == channelModeMono:
+		return 17
+	case h.version == mpeg1:
+		return 32
+	case h.channelMode == channelModeMono:
+		return 9
+	default:
+		return 17
+	}
+}
+
+// sameStream tells whether frames may follow each other in a single stream:
+// the bitrate may vary, the rest may not
+func (h header) sameStream(o header) bool {
+	return h.version == o.version && h.sampleRate == o.sampleRate &&
+		(h.channelMode == channelModeMono) == (o.channelMode == channelModeMono)
+}
+
+func (h header) String() string {
+	version := map[int]string{mpeg1: "MPEG-1", mpeg2: "MPEG-2", mpeg25: "MPEG-2.5"}[h.version]
+	channels := "stereo"
+	if h.channelMode == channelModeMono {
+		channels = "mono"
+	}
+	return fmt.Sprintf("%s %d Hz %s", version, h.sampleRate, channels)
+}
+
+// frameReader reads frames of a single input. Tags are skipped wherever they are, and so is anything else
+// that is not a frame of the stream the first frame started.
+type frameReader struct {
+	r      *bufio.Reader
+	stream *header
+	// pending is the length of the frame returned last, which is still in the buffer
+	pending int
+	// skipped counts bytes of junk, tags aside
+	skipped int64
+}
+
+func newFrameReader(r io.Reader) *frameReader {
+	return &frameReader{r: bufio.NewReaderSize(r, 64<<10)}
+}
+
+// next returns the next frame, which is only valid until the next call, or io.EOF.
+// Bytes after the last whole frame are dropped.
+func (fr *frameReader) next() ([]byte, header, error) {
+	if _, err := fr.r.Discard(fr.pending); err != nil {
+		return nil, header{}, err
+	}
+	fr.pending = 0
+	for {
+		b, err := fr.r.Peek(apeHeaderSize)
+		if len(b) < 4 {
+			if err == nil || err == io.EOF {
+				err = io.EOF
+			}
+			return nil, header{}, err
+		}
+		if n := tagSize(b); n > 0 {
+			if _, err := fr.r.Discard(n); err != nil && err != io.EOF {
+				return nil, header{}, err
+			}
+			continue
+		}
+		if h, ok := parseHeader(b); ok && (fr.stream == nil || h.sameStream(*fr.stream)) && fr.followedByFrame(h) {
+			frame, err := fr.r.Peek(h.size())
+			if err != nil {
+				return nil, header{}, err
+			}
+			if fr.stream == nil {
+				fr.stream = &h
+			}
+			fr.pending = h.size()
+			return frame, h, nil
+		}
+		if _, err := fr.r.Discard(1); err != nil {
+			return nil, header{}, err
+		}
+		fr.skipped++
+	}
+}
+
+// followedByFrame tells whether a frame with the header is whole, and is followed by another frame of the same
+// stream, a tag, or the end of input. This tells frames from sync words that happen to be in junk.
+func (fr *frameReader) followedByFrame(h header) bool {
+	b, _ := fr.r.Peek(h.size() + 4)
+	switch {
+	case len(b) == h.size():
+		return true
+	case len(b) < h.size()+4:
+		return false
+	}
+	next := b[h.size():]
+	if after, ok := parseHeader(next); ok {
+		return after.sameStream(h)
+	}
+	return bytes.HasPrefix(next, []byte("ID3")) || bytes.HasPrefix(next, []byte("TAG")) || bytes.HasPrefix(next, []byte("APET"))
+}
+
+const apeHeaderSize = 32
+
+// tagSize returns the length of an ID3v2, ID3v1 or APE tag that b starts with, or zero.
+// See https://mutagen-specs.readthedocs.io/en/latest/id3/id3v2.4.0-structure.html and
+// https://wiki.hydrogenaud.io/index.php?title=APEv2_specification
+func tagSize(b []byte) int {
+	switch {
+	case len(b) >= 10 && bytes.HasPrefix(b, []byte("ID3")):
+		// the size is a syncsafe integer, of 7-bit bytes
+		size := 10 + (int(b[6])<<21 | int(b[7])<<14 | int(b[8])<<7 | int(b[9]))
+		// a footer repeats the header
+		if b[5]&0x10 != 0 {
+			size += 10
+		}
+		return size
+	case bytes.HasPrefix(b, []byte("TAG")):
+		return 128
+	case len(b) >= apeHeaderSize && bytes.HasPrefix(b, []byte("APETAGEX")):
+		// a header is followed by items and a footer, which the size counts; a footer follows the items
+		if flags := binary.LittleEndian.Uint32(b[20:]); flags&(1<<29) != 0 {
+			return apeHeaderSize + int(binary.LittleEndian.Uint32(b[12:]))
+		}
+		return apeHeaderSize
+	}
+	return 0
+}
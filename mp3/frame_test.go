@@ -0,0 +1,111 @@
+package mp3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+// testFrame makes an MPEG-1 Layer III frame without CRC, at 44.1 kHz unless sampleRateIndex says otherwise,
+// filled with the byte
+func testFrame(bitrateIndex int, sampleRateIndex int, fill byte) []byte {
+	h, _ := parseHeader([]byte{0xFF, 0xFB, byte(bitrateIndex<<4 | sampleRateIndex<<2), 0x00})
+	frame := bytes.Repeat([]byte{fill}, h.size())
+	copy(frame, []byte{0xFF, 0xFB, byte(bitrateIndex<<4 | sampleRateIndex<<2), 0x00})
+	return frame
+}
+
+// testInfoFrame makes an Info frame like LAME writes, with delay and padding in its extension
+func testInfoFrame(delay, padding int) []byte {
+	frame := testFrame(9, 0, 0)
+	pos := 4 + 32
+	copy(frame[pos:], "Info")
+	binary.BigEndian.PutUint32(frame[pos+4:], xingHasFrames|xingHasBytes|xingHasTOC|xingHasQuality)
+	lame := frame[pos+xingSize:]
+	copy(lame, "LAME3.100")
+	lame[10] = 160
+	lame[21], lame[22], lame[23] = byte(delay>>4), byte(delay<<4)|byte(padding>>8), byte(padding)
+	return frame
+}
+
+func testID3v2(payload int) []byte {
+	return append([]byte{'I', 'D', '3', 4, 0, 0, 0, 0, byte(payload >> 7), byte(payload & 0x7F)}, make([]byte, payload)...)
+}
+
+func testID3v1() []byte {
+	return append([]byte("TAG"), bytes.Repeat([]byte{'x'}, 125)...)
+}
+
+// testAPETag makes a tag with both a header and a footer, as APEv2 tags usually have
+func testAPETag(items []byte) []byte {
+	block := func(flags uint32) []byte {
+		b := make([]byte, apeHeaderSize)
+		copy(b, "APETAGEX")
+		binary.LittleEndian.PutUint32(b[8:], 2000)
+		binary.LittleEndian.PutUint32(b[12:], uint32(len(items)+apeHeaderSize))
+		binary.LittleEndian.PutUint32(b[20:], flags)
+		return b
+	}
+	tag := append(block(1<<31|1<<29), items...)
+	return append(tag, block(1<<31)...)
+}
+
+func TestParseHeader(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		header    []byte
+		wantOK    bool
+		wantSize  int
+		wantShort string
+	}{
+		{name: "MPEG-1 128 kbps 44.1 kHz", header: []byte{0xFF, 0xFB, 0x90, 0x64}, wantOK: true, wantSize: 417, wantShort: "MPEG-1 44100 Hz stereo"},
+		{name: "padded", header: []byte{0xFF, 0xFB, 0x92, 0x64}, wantOK: true, wantSize: 418, wantShort: "MPEG-1 44100 Hz stereo"},
+		{name: "MPEG-2 64 kbps 22.05 kHz mono", header: []byte{0xFF, 0xF3, 0x80, 0xC4}, wantOK: true, wantSize: 208, wantShort: "MPEG-2 22050 Hz mono"},
+		{name: "MPEG-2.5 8 kbps 8 kHz", header: []byte{0xFF, 0xE3, 0x18, 0x00}, wantOK: true, wantSize: 72, wantShort: "MPEG-2.5 8000 Hz stereo"},
+		{name: "layer II", header: []byte{0xFF, 0xFD, 0x90, 0x64}},
+		{name: "free format", header: []byte{0xFF, 0xFB, 0x00, 0x64}},
+		{name: "reserved sample rate", header: []byte{0xFF, 0xFB, 0x9C, 0x64}},
+		{name: "no sync", header: []byte{0xFF, 0x1B, 0x90, 0x64}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h, ok := parseHeader(tc.header)
+			if ok != tc.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tc.wantOK)
+			}
+			if ok && (h.size() != tc.wantSize || h.String() != tc.wantShort) {
+				t.Errorf("got %s of %d bytes, want %s of %d", h, h.size(), tc.wantShort, tc.wantSize)
+			}
+		})
+	}
+}
+
+func TestFrameReader_SkipsTagsAndJunk(t *testing.T) {
+	frames := [][]byte{testFrame(9, 0, 1), testFrame(11, 0, 2), testFrame(9, 0, 3)}
+	var input bytes.Buffer
+	input.Write(testID3v2(300))
+	input.Write(frames[0])
+	// a sync word in junk, followed by no frame
+	input.Write([]byte{0xFF, 0xFB, 0x90, 0x64, 'j', 'u', 'n', 'k'})
+	input.Write(frames[1])
+	input.Write(testAPETag(bytes.Repeat([]byte{0xFF, 0xFB}, 100)))
+	input.Write(frames[2])
+	input.Write(testID3v1())
+
+	fr := newFrameReader(&input)
+	for i, want := range frames {
+		frame, _, err := fr.next()
+		if err != nil {
+			t.Fatalf("frame %d: %v", i, err)
+		}
+		if !bytes.Equal(frame, want) {
+			t.Errorf("frame %d differs", i)
+		}
+	}
+	if _, _, err := fr.next(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+	if fr.skipped != 8 {
+		t.Errorf("skipped %d bytes, want 8", fr.skipped)
+	}
+}
@@ -0,0 +1,179 @@
+package mp3
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// An Xing frame comes first in VBR streams, and, called an Info frame, in CBR ones. It is a frame of silence
+// that tells the number of frames and a seek table, and its LAME extension tells encoder delay and padding
+// for gapless playback. See http://gabriel.mp3-tech.org/mp3infotag.html
+
+const (
+	xingHasFrames  = 0x1
+	xingHasBytes   = 0x2
+	xingHasTOC     = 0x4
+	xingHasQuality = 0x8
+)
+
+const (
+	// xingSize is the length of the tag, its ID, flags, frames, bytes, TOC and quality
+	xingSize = 120
+	lameSize = 36
+)
+
+// lameTag is a LAME extension of an Xing frame
+type lameTag struct {
+	raw     [lameSize]byte
+	delay   int
+	padding int
+}
+
+// parseXing tells whether the frame is an Xing, Info or VBRI frame rather than audio,
+// and returns the LAME extension of an Xing frame, if it has one
+func parseXing(frame []byte, h header) (bool, *lameTag) {
+	// VBRI frames of Fraunhofer encoders are always at the same offset
+	if len(frame) >= 40 && string(frame[36:40]) == "VBRI" {
+		return true, nil
+	}
+	offset := 4 + h.sideInfoSize()
+	if len(frame) < offset+8 {
+		return false, nil
+	}
+	if id := string(frame[offset : offset+4]); id != "Xing" && id != "Info" {
+		return false, nil
+	}
+
+	flags := binary.BigEndian.Uint32(frame[offset+4:])
+	pos := offset + 8
+	for _, field := range []struct {
+		flag uint32
+		size int
+	}{{xingHasFrames, 4}, {xingHasBytes, 4}, {xingHasTOC, 100}, {xingHasQuality, 4}} {
+		if flags&field.flag != 0 {
+			pos += field.size
+		}
+	}
+	// ffmpeg writes the extension too, naming itself as the encoder
+	if len(frame) < pos+lameSize || !hasAnyPrefix(frame[pos:], "LAME", "Lavf", "Lavc") {
+		return true, nil
+	}
+	lame := &lameTag{
+		delay:   int(frame[pos+21])<<4 | int(frame[pos+22])>>4,
+		padding: int(frame[pos+22]&0x0F)<<8 | int(frame[pos+23]),
+	}
+	copy(lame.raw[:], frame[pos:])
+	return true, lame
+}
+
+func hasAnyPrefix(b []byte, prefixes ...string) bool {
+	for _, p := range prefixes {
+		if bytes.HasPrefix(b, []byte(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// xingHeader is the header of an Xing frame of the stream: of the lowest bitrate that fits the tag,
+// without padding and CRC
+func xingHeader(stream header) header {
+	h := stream
+	h.padding = false
+	h.raw &^= 0xF000 | 0x0200
+	h.raw |= 0x10000
+	for h.bitrateIndex = 1; h.bitrateIndex < 14; h.bitrateIndex++ {
+		if h.size() >= 4+h.sideInfoSize()+xingSize+lameSize {
+			break
+		}
+	}
+	h.raw |= uint32(h.bitrateIndex) << 12
+	return h
+}
+
+// xingStats are what an Xing frame tells about the stream that follows it
+type xingStats struct {
+	frames int
+	// bytes are of the whole stream, the Xing frame included
+	bytes int64
+	toc   [100]byte
+	cbr   bool
+	// lame is the LAME extension of the first input, whose encoder settings are kept
+	lame    *lameTag
+	delay   int
+	padding int
+	// musicCRC is of the frames that follow the Xing frame
+	musicCRC uint16
+}
+
+// xingFrame makes an Xing frame with a LAME extension
+func xingFrame(h header, stats xingStats) []byte {
+	frame := make([]byte, h.size())
+	binary.BigEndian.PutUint32(frame, h.raw)
+
+	pos := 4 + h.sideInfoSize()
+	id := "Xing"
+	if stats.cbr {
+		id = "Info"
+	}
+	copy(frame[pos:], id)
+	binary.BigEndian.PutUint32(frame[pos+4:], xingHasFrames|xingHasBytes|xingHasTOC|xingHasQuality)
+	binary.BigEndian.PutUint32(frame[pos+8:], uint32(stats.frames))
+	binary.BigEndian.PutUint32(frame[pos+12:], uint32(stats.bytes))
+	copy(frame[pos+16:], stats.toc[:])
+	// quality stays zero, as it is unknown for a stream of several encodings
+
+	lame := frame[pos+xingSize : pos+xingSize+lameSize]
+	if stats.lame != nil {
+		copy(lame, stats.lame.raw[:])
+		// peak amplitude and replay gains were measured for the first input only
+		clear(lame[11:19])
+	} else {
+		copy(lame, "LAME3.100")
+	}
+	lame[21] = byte(stats.delay >> 4)
+	lame[22] = byte(stats.delay<<4) | byte(stats.padding>>8&0x0F)
+	lame[23] = byte(stats.padding)
+	binary.BigEndian.PutUint32(lame[28:], uint32(stats.bytes))
+	binary.BigEndian.PutUint16(lame[32:], stats.musicCRC)
+	binary.BigEndian.PutUint16(lame[34:], updateCRC16(0, frame[:pos+xingSize+34]))
+	return frame
+}
+
+// xingTOC maps every percent of the duration to the position of the frame that starts it,
+// in 256ths of the whole stream. Frames follow an Xing frame of the given size.
+func xingTOC(sizes []uint16, xingFrameSize int, total int64) [100]byte {
+	var toc [100]byte
+	pos, frame := int64(xingFrameSize), 0
+	for i := range toc {
+		for target := i * len(sizes) / 100; frame < target; frame++ {
+			pos += int64(sizes[frame])
+		}
+		toc[i] = byte(min(255, pos*256/total))
+	}
+	return toc
+}
+
+// crc16Table is of CRC-16/ARC, which the LAME extension uses
+var crc16Table = func() [256]uint16 {
+	var table [256]uint16
+	for i := range table {
+		crc := uint16(i)
+		for range 8 {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+func updateCRC16(crc uint16, p []byte) uint16 {
+	for _, b := range p {
+		crc = crc>>8 ^ crc16Table[byte(crc)^b]
+	}
+	return crc
+}
@@ -0,0 +1,25 @@
+package service
+
+import "context"
+
+// makeArtifacts makes the requested waveforms and previews of every result of the job, in order of parts.
+// A job with a single result passes it alone, as the only part.
+func (svc *Service) makeArtifacts(
+	ctx context.Context, jobID string, results []string, waveform *WaveformParams, previews *PreviewParams,
+) error {
+	if waveform != nil {
+		for i, fp := range results {
+			if err := svc.makeWaveform(ctx, jobID, fp, i, len(results), waveform); err != nil {
+				return err
+			}
+		}
+	}
+	if previews != nil {
+		for i, fp := range results {
+			if err := svc.makePreviews(ctx, jobID, fp, i, len(results), previews); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
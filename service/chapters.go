@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dir01/mediary/cuesheet"
+	"github.com/samber/oops"
+)
+
+// Sources of chapter titles
+const (
+	// ChapterTitlesAuto uses variant titles provided by the downloader, then title tags, then file names
+	ChapterTitlesAuto     = "auto"
+	ChapterTitlesTag      = "tag"
+	ChapterTitlesFilename = "filename"
+	// ChapterTitlesCue takes both chapters and their titles from a CUE sheet
+	ChapterTitlesCue = "cue"
+)
+
+// ChapterOptions describe where chapters of a job result come from.
+// By default, every input file becomes a chapter.
+type ChapterOptions struct {
+	ChapterTitles string `json:"chapterTitles"`
+	// Chapters are supplied by the user. With start times they replace per-file chapters altogether,
+	// without them they are titles of per-file chapters, in order.
+	Chapters []ChapterSpec `json:"chapters"`
+	// CueSheet is a variant ID of a CUE sheet, the one next to the first variant is used when empty
+	CueSheet string `json:"cueSheet"`
+	// SilenceChapters, when set, split the result at long pauses instead, titled by Chapters when given
+	SilenceChapters *SilenceChapterParams `json:"silenceChapters"`
+}
+
+type ChapterSpec struct {
+	Title string     `json:"title"`
+	Start *Timestamp `json:"start,omitempty"`
+}
+
+func (opts ChapterOptions) Validate() error {
+	switch opts.ChapterTitles {
+	case "", ChapterTitlesAuto, ChapterTitlesTag, ChapterTitlesFilename, ChapterTitlesCue:
+	default:
+		return fmt.Errorf("unknown chapter titles source: %s", opts.ChapterTitles)
+	}
+	if opts.SilenceChapters != nil {
+		if opts.ChapterTitles == ChapterTitlesCue {
+			return fmt.Errorf("silence chapters can't be combined with a CUE sheet")
+		}
+		if len(opts.Chapters) > 0 && opts.Chapters[0].Start != nil {
+			return fmt.Errorf("silence chapters can't be combined with chapter start times")
+		}
+		if _, err := opts.SilenceChapters.Options(); err != nil {
+			return err
+		}
+	}
+	if len(opts.Chapters) == 0 {
+		return nil
+	}
+	if opts.ChapterTitles == ChapterTitlesCue {
+		return fmt.Errorf("chapters can't be combined with a CUE sheet")
+	}
+	withStart := opts.Chapters[0].Start != nil
+	var prev time.Duration
+	for i, ch := range opts.Chapters {
+		if (ch.Start != nil) != withStart {
+			return fmt.Errorf("either all chapters or none should have a start")
+		}
+		if withStart && i > 0 && time.Duration(*ch.Start) <= prev {
+			return fmt.Errorf("chapter %d starts before the previous one", i+1)
+		}
+		if withStart {
+			prev = time.Duration(*ch.Start)
+		}
+	}
+	return nil
+}
+
+// explicit tells whether chapters were asked for, as opposed to per-file chapters by default
+func (opts ChapterOptions) explicit() bool {
+	return len(opts.Chapters) > 0 || opts.ChapterTitles == ChapterTitlesCue
+}
+
+// Timestamp is a position within media, written either as seconds or as [hh:]mm:ss[.fff]
+type Timestamp time.Duration
+
+func (ts *Timestamp) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err == nil {
+		if seconds < 0 {
+			return fmt.Errorf("negative timestamp: %s", data)
+		}
+		*ts = Timestamp(secondsToDuration(seconds))
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("timestamp should be a number of seconds or a string: %s", data)
+	}
+	d, err := ParseTimestamp(s)
+	if err != nil {
+		return err
+	}
+	*ts = Timestamp(d)
+	return nil
+}
+
+func (ts Timestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(ts).Seconds())
+}
+
+// ParseTimestamp parses [hh:]mm:ss[.fff], as well as plain seconds
+func ParseTimestamp(s string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp: %q", s)
+	}
+	var seconds float64
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		last := i == len(parts)-1
+		if err != nil || v < 0 || (!last && v != math.Trunc(v)) || (i > 0 && v >= 60) {
+			return 0, fmt.Errorf("invalid timestamp: %q", s)
+		}
+		seconds = seconds*60 + v
+	}
+	return secondsToDuration(seconds), nil
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Round(seconds*1000)) * time.Millisecond
+}
+
+// buildChapters returns chapters of the concatenation of the files, in the order given.
+// It returns no chapters when the durations of the files are unknown.
+func (svc *Service) buildChapters(
+	ctx context.Context, downloader Downloader, downloaderName string, url string,
+	opts ChapterOptions, variants []string, filepaths []string,
+) ([]Chapter, error) {
+	logAttrs := []any{slog.String("url", url)}
+
+	infos := make([]*MediaInfo, len(filepaths))
+	var total time.Duration
+	for i, fp := range filepaths {
+		info, err := svc.mediaProcessor.GetInfo(ctx, fp)
+		if err != nil {
+			svc.log.Warn("failed to get duration for chapter, skipping chapter tags",
+				append(logAttrs, slog.Any("error", err), slog.String("variant", variants[i]))...)
+			return nil, nil
+		}
+		infos[i] = info
+		total += info.Duration
+	}
+
+	if opts.ChapterTitles == ChapterTitlesCue {
+		cueSheet := opts.CueSheet
+		if cueSheet == "" && len(variants) > 0 {
+			cueSheet = svc.findVariant(ctx, url, downloaderName, variants[0], func(id string) (int, bool) {
+				return 0, strings.EqualFold(filepath.Ext(id), ".cue")
+			})
+		}
+		sheet, err := svc.loadCueSheet(ctx, downloader, url, cueSheet)
+		if err != nil {
+			return nil, err
+		}
+		return cueChapters(sheet, variants, infos)
+	}
+
+	if len(opts.Chapters) > 0 && opts.Chapters[0].Start != nil {
+		return userChapters(opts.Chapters, total), nil
+	}
+
+	var variantTitles map[string]string
+	if opts.ChapterTitles == "" || opts.ChapterTitles == ChapterTitlesAuto {
+		variantTitles = svc.variantTitles(ctx, url, downloaderName)
+	}
+	chapters := make([]Chapter, 0, len(variants))
+	var offset time.Duration
+	for i, variant := range variants {
+		title := fileChapterTitle(opts.ChapterTitles, variant, infos[i], variantTitles)
+		if i < len(opts.Chapters) && opts.Chapters[i].Title != "" {
+			title = opts.Chapters[i].Title
+		}
+		chapters = append(chapters, Chapter{
+			Title:     title,
+			StartTime: offset,
+			EndTime:   offset + infos[i].Duration,
+		})
+		offset += infos[i].Duration
+	}
+	return chapters, nil
+}
+
+func fileChapterTitle(source string, variant string, info *MediaInfo, variantTitles map[string]string) string {
+	if title, ok := variantTitles[variant]; ok {
+		return title
+	}
+	if source != ChapterTitlesFilename && info.Title != "" {
+		return info.Title
+	}
+	return strings.TrimSuffix(filepath.Base(variant), filepath.Ext(variant))
+}
+
+// cueFileStem is the lower-cased name of a file without directories and extension.
+// CUE sheets written on Windows separate directories with backslashes.
+func cueFileStem(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	return strings.ToLower(strings.TrimSuffix(name, path.Ext(name)))
+}
+
+// userChapters makes chapters out of user-supplied ones, each lasting until the next one starts.
+// Chapters that start after the end of media are dropped.
+func userChapters(specs []ChapterSpec, total time.Duration) []Chapter {
+	chapters := make([]Chapter, 0, len(specs))
+	for i, spec := range specs {
+		start := time.Duration(*spec.Start)
+		if start >= total {
+			break
+		}
+		end := total
+		if i+1 < len(specs) {
+			end = min(time.Duration(*specs[i+1].Start), total)
+		}
+		chapters = append(chapters, Chapter{Title: spec.Title, StartTime: start, EndTime: end})
+	}
+	return chapters
+}
+
+func (svc *Service) loadCueSheet(ctx context.Context, downloader Downloader, url string, variant string) (*cuesheet.Sheet, error) {
+	errCtx := oops.With("url", url, "cueSheet", variant)
+	if variant == "" {
+		return nil, errCtx.Errorf("no CUE sheet found")
+	}
+
+	filepathsMap, err := downloader.Download(ctx, url, []string{variant})
+	if err != nil {
+		return nil, errCtx.Wrapf(err, "failed to download CUE sheet")
+	}
+	file, err := os.Open(filepathsMap[variant])
+	if err != nil {
+		return nil, errCtx.Wrapf(err, "failed to open CUE sheet")
+	}
+	defer func() { _ = file.Close() }()
+
+	sheet, err := cuesheet.Parse(file)
+	if err != nil {
+		return nil, errCtx.Wrapf(err, "failed to parse CUE sheet")
+	}
+	return sheet, nil
+}
+
+// cueChapters maps tracks of the sheet onto the files. Sheets often refer to files by a stale extension
+// (e.g. .wav of the original rip for a .flac file), so files are matched by their names without extensions.
+// Tracks of files that are not among the variants are skipped.
+func cueChapters(sheet *cuesheet.Sheet, variants []string, infos []*MediaInfo) ([]Chapter, error) {
+	stem := cueFileStem
+	offsets := make(map[string]time.Duration, len(variants))
+	ends := make(map[string]time.Duration, len(variants))
+	var offset time.Duration
+	for i, v := range variants {
+		offsets[stem(v)] = offset
+		offset += infos[i].Duration
+		ends[stem(v)] = offset
+	}
+
+	var chapters []Chapter
+	for _, file := range sheet.Files {
+		key := stem(file.Name)
+		if len(sheet.Files) == 1 && len(variants) == 1 {
+			key = stem(variants[0])
+		}
+		fileOffset, ok := offsets[key]
+		if !ok {
+			continue
+		}
+		for _, track := range file.Tracks {
+			start := fileOffset + track.Start
+			if start >= ends[key] {
+				continue
+			}
+			title := track.Title
+			if title == "" {
+				title = fmt.Sprintf("Track %02d", track.Number)
+			}
+			// a track lasts until the next one starts, or until the end of its file
+			chapters = append(chapters, Chapter{Title: title, StartTime: start, EndTime: ends[key]})
+		}
+	}
+	if len(chapters) == 0 {
+		return nil, oops.Errorf("CUE sheet doesn't describe any of the variants")
+	}
+
+	sort.SliceStable(chapters, func(i, j int) bool { return chapters[i].StartTime < chapters[j].StartTime })
+	for i := 0; i+1 < len(chapters); i++ {
+		chapters[i].EndTime = min(chapters[i].EndTime, chapters[i+1].StartTime)
+	}
+	return chapters, nil
+}
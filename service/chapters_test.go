@@ -0,0 +1,58 @@
+package service_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/service"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	for input, want := range map[string]time.Duration{
+		"90":         90 * time.Second,
+		"1:30":       90 * time.Second,
+		"01:02:03.5": time.Hour + 2*time.Minute + 3500*time.Millisecond,
+		"0:00.25":    250 * time.Millisecond,
+	} {
+		got, err := service.ParseTimestamp(input)
+		if err != nil || got != want {
+			t.Errorf("ParseTimestamp(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "1:60", "1.5:00", "-1", "1:2:3:4", "abc"} {
+		if _, err := service.ParseTimestamp(input); err == nil {
+			t.Errorf("ParseTimestamp(%q): expected error", input)
+		}
+	}
+}
+
+func TestChapterOptions_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		json    string
+		wantErr bool
+	}{
+		{name: "defaults", json: `{}`},
+		{name: "titles only", json: `{"chapters": [{"title": "One"}, {"title": "Two"}]}`},
+		{name: "mixed start formats", json: `{"chapters": [{"title": "One", "start": 0}, {"title": "Two", "start": "1:30"}]}`},
+		{name: "unknown source", json: `{"chapterTitles": "guess"}`, wantErr: true},
+		{name: "some without start", json: `{"chapters": [{"title": "One", "start": 0}, {"title": "Two"}]}`, wantErr: true},
+		{name: "unordered", json: `{"chapters": [{"title": "One", "start": 60}, {"title": "Two", "start": 30}]}`, wantErr: true},
+		{name: "chapters with cue", json: `{"chapterTitles": "cue", "chapters": [{"title": "One"}]}`, wantErr: true},
+		{name: "silence with titles", json: `{"silenceChapters": {}, "chapters": [{"title": "One"}]}`},
+		{name: "silence with starts", json: `{"silenceChapters": {}, "chapters": [{"title": "One", "start": 0}]}`, wantErr: true},
+		{name: "silence with cue", json: `{"silenceChapters": {}, "chapterTitles": "cue"}`, wantErr: true},
+		{name: "silence too loud", json: `{"silenceChapters": {"noiseDb": -3}}`, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var opts service.ChapterOptions
+			if err := json.Unmarshal([]byte(tc.json), &opts); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if err := opts.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var ErrSectionsNotSupported = fmt.Errorf("downloading sections not supported")
+
+// Clip modes
+const (
+	ClipAccurate = "accurate"
+	ClipFast     = "fast"
+)
+
+// ClipSpec is a variant along with ranges to cut out of it
+type ClipSpec struct {
+	Variant string      `json:"variant"`
+	Ranges  []RangeSpec `json:"ranges"`
+}
+
+// RangeSpec is a range of media, lasting until the end when End is omitted
+type RangeSpec struct {
+	Start Timestamp  `json:"start"`
+	End   *Timestamp `json:"end"`
+}
+
+func (r RangeSpec) timeRange() TimeRange {
+	tr := TimeRange{Start: time.Duration(r.Start)}
+	if r.End != nil {
+		tr.End = time.Duration(*r.End)
+	}
+	return tr
+}
+
+func (svc *Service) newClipFlow(jobID string, job *Job) (func(ctx context.Context) error, error) {
+	logAttrs := []any{slog.String("jobID", jobID), slog.Any("job", job)}
+	errCtx := oops.With("jobID", jobID, "job", job)
+	type Params struct {
+		Clips []ClipSpec `json:"clips"`
+		// Mode is either ClipAccurate (default) or ClipFast
+		Mode    string    `json:"mode"`
+		FadeIn  Timestamp `json:"fadeIn"`
+		FadeOut Timestamp `json:"fadeOut"`
+		// Concatenate glues all the ranges into a single file
+		Concatenate bool `json:"concatenate"`
+		// UploadURL is where a single result goes, that is, a concatenation or the only range
+		UploadURL string `json:"uploadUrl"`
+		// UploadURLs and UploadURLTemplate are for a file per range, see the split job
+		UploadURLs        []string `json:"uploadUrls"`
+		UploadURLTemplate string   `json:"uploadUrlTemplate"`
+		// Waveform, when set, makes waveform peaks and images of the result
+		Waveform *WaveformParams `json:"waveform"`
+		// Previews, when set, makes visual previews of the resulting video
+		Previews *PreviewParams `json:"previews"`
+	}
+	params := Params{}
+	if err := mapToStruct(job.Params, &params); err != nil {
+		return nil, errCtx.Wrapf(err, "failed to parse job params")
+	}
+	if len(params.Clips) == 0 {
+		return nil, errCtx.Errorf("no clips provided")
+	}
+	total := 0
+	for i, clip := range params.Clips {
+		if clip.Variant == "" || len(clip.Ranges) == 0 {
+			return nil, errCtx.Errorf("clip %d should have a variant and ranges", i+1)
+		}
+		for j, r := range clip.Ranges {
+			if r.End != nil && *r.End <= r.Start {
+				return nil, errCtx.Errorf("range %d of clip %d ends before it starts", j+1, i+1)
+			}
+		}
+		total += len(clip.Ranges)
+	}
+	switch params.Mode {
+	case "", ClipAccurate, ClipFast:
+	default:
+		return nil, errCtx.Errorf("unknown clip mode: %s", params.Mode)
+	}
+	singleResult := params.Concatenate || total == 1
+	if singleResult {
+		if params.UploadURL == "" {
+			return nil, errCtx.Errorf("no upload URL provided")
+		}
+	} else {
+		if err := validatePartUploads(params.UploadURLs, params.UploadURLTemplate); err != nil {
+			return nil, errCtx.Wrap(err)
+		}
+		if len(params.UploadURLs) > 0 && len(params.UploadURLs) < total {
+			return nil, errCtx.Errorf("got %d upload URLs for %d ranges", len(params.UploadURLs), total)
+		}
+	}
+	if err := params.Waveform.validate(!singleResult); err != nil {
+		return nil, errCtx.Wrapf(err, "invalid waveform")
+	}
+	if err := params.Previews.validate(!singleResult); err != nil {
+		return nil, errCtx.Wrapf(err, "invalid previews")
+	}
+	clipOpts := ClipOptions{
+		Fast:    params.Mode == ClipFast,
+		FadeIn:  time.Duration(params.FadeIn),
+		FadeOut: time.Duration(params.FadeOut),
+	}
+	logAttrs = append(logAttrs, slog.Any("params", params))
+	errCtx = errCtx.With("params", params)
+	svc.log.Debug("parsed job params", logAttrs...)
+
+	return func(jobCtx context.Context) error {
+		jobCtx, span := otel.Tracer("github.com/dir01/mediary/service").Start(jobCtx, "service.ClipFlow",
+			trace.WithAttributes(
+				attribute.String("job.id", jobID),
+				attribute.Int("clips.count", len(params.Clips)),
+				attribute.Int("ranges.count", total),
+			),
+		)
+		defer span.End()
+
+		ctx, cancel := context.WithTimeout(jobCtx, 10*time.Second)
+		defer cancel()
+		job, err := svc.storage.GetJob(ctx, jobID)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrapf(err, "failed to get job")
+		}
+
+		updateJobStatus := func(status string) {
+			statusCtx, statusCancel := context.WithTimeout(jobCtx, 10*time.Second)
+			defer statusCancel()
+			job.DisplayStatus = status
+			if err = svc.storage.SaveJob(statusCtx, job); err != nil {
+				attrs := append([]any{
+					slog.String("state", job.DisplayStatus),
+					slog.Any("error", err),
+				}, logAttrs...)
+				svc.log.Error("failed to save job state, proceeding", attrs...)
+			}
+		}
+
+		updateJobStatus(JobStatusDownloading)
+		svc.log.Debug("starting download", logAttrs...)
+
+		downloader, err := svc.selectDownloader(job.Downloader)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrapf(err, "failed to select downloader")
+		}
+
+		clipCtx, clipCancel := context.WithTimeout(jobCtx, 2*time.Hour)
+		defer clipCancel()
+
+		clips, err := svc.clip(clipCtx, downloader, job.Downloader, job.URL, params.Clips, clipOpts, updateJobStatus)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrap(err)
+		}
+
+		var ranges []TimeRange
+		for _, clip := range params.Clips {
+			for _, r := range clip.Ranges {
+				ranges = append(ranges, r.timeRange())
+			}
+		}
+
+		results := clips
+		if params.Concatenate && len(clips) > 1 {
+			if anyVideo(clips) && !allVideo(clips) {
+				err := fmt.Errorf("video clips can't be concatenated with audio ones")
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return errCtx.Wrap(err)
+			}
+			concatOpts := ConcatenateOptions{Container: concatContainer("", clips, "")}
+			resultFilepath, err := svc.mediaProcessor.Concatenate(clipCtx, clips, concatOpts)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return errCtx.Wrapf(err, "failed to concatenate clips")
+			}
+			results = []string{resultFilepath}
+		}
+
+		job.ResultParts, job.ResultMediaInfo = nil, nil
+		job.ResultMediaDuration, job.ResultFileBytes = 0, 0
+		for i, fp := range results {
+			info, err := svc.mediaProcessor.GetInfo(clipCtx, fp)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return errCtx.Wrapf(err, "failed to get info about result file")
+			}
+			job.ResultMediaDuration += info.Duration
+			job.ResultFileBytes += info.FileLenBytes
+			if singleResult {
+				job.ResultMediaInfo = info
+			} else {
+				job.ResultParts = append(job.ResultParts, ResultPart{
+					StartTime: ranges[i].Start,
+					Duration:  info.Duration,
+					FileBytes: info.FileLenBytes,
+					UploadURL: partUploadURL(params.UploadURLs, params.UploadURLTemplate, i, len(results)),
+				})
+			}
+		}
+		span.SetAttributes(
+			attribute.Int64("result.bytes", job.ResultFileBytes),
+			attribute.Float64("result.duration_seconds", job.ResultMediaDuration.Seconds()),
+		)
+
+		if params.Waveform != nil || params.Previews != nil {
+			updateJobStatus(JobStatusProcessing)
+			if err := svc.makeArtifacts(jobCtx, jobID, results, params.Waveform, params.Previews); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return errCtx.Wrap(err)
+			}
+		}
+
+		updateJobStatus(JobStatusUploading)
+		svc.log.Debug("starting upload", logAttrs...)
+
+		uploadCtx, uploadCancel := context.WithTimeout(jobCtx, 2*time.Hour)
+		defer uploadCancel()
+
+		for i, fp := range results {
+			url := params.UploadURL
+			if !singleResult {
+				url = job.ResultParts[i].UploadURL
+			}
+			if err := svc.uploader.Upload(uploadCtx, fp, url); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return errCtx.Wrapf(err, "failed to upload result")
+			}
+		}
+
+		updateJobStatus(JobStatusComplete)
+		svc.log.Debug("job complete", logAttrs...)
+		return nil
+	}, nil
+}
+
+// clip returns a file per range, in order. Downloaders that can download sections only fetch the ranges,
+// the rest of variants are downloaded as a whole and cut afterwards.
+func (svc *Service) clip(
+	ctx context.Context, downloader Downloader, downloaderName string, url string,
+	specs []ClipSpec, opts ClipOptions, updateJobStatus func(string),
+) ([]string, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/service").Start(ctx, "service.Clip")
+	defer span.End()
+
+	clips := make([][]string, len(specs))
+	var wholeVariants []string
+	for i, spec := range specs {
+		ranges := make([]TimeRange, len(spec.Ranges))
+		for j, r := range spec.Ranges {
+			ranges[j] = r.timeRange()
+		}
+		sectionDownloader, ok := downloader.(SectionDownloader)
+		if !ok {
+			wholeVariants = append(wholeVariants, spec.Variant)
+			continue
+		}
+		sections, err := sectionDownloader.DownloadSections(ctx, url, spec.Variant, ranges, opts.Fast)
+		if errors.Is(err, ErrSectionsNotSupported) {
+			wholeVariants = append(wholeVariants, spec.Variant)
+			continue
+		}
+		if err != nil {
+			return nil, oops.With("variant", spec.Variant).Wrapf(err, "failed to download sections")
+		}
+		if opts.FadeIn > 0 || opts.FadeOut > 0 {
+			// sections are cut already, only fades are left
+			fadeOpts := opts
+			fadeOpts.Ranges = []TimeRange{{}}
+			for j, section := range sections {
+				faded, err := svc.mediaProcessor.Clip(ctx, section, fadeOpts)
+				if err != nil {
+					return nil, oops.With("variant", spec.Variant).Wrapf(err, "failed to fade section")
+				}
+				sections[j] = faded[0]
+			}
+		}
+		clips[i] = sections
+	}
+	span.SetAttributes(attribute.Int("whole_variants.count", len(wholeVariants)))
+
+	if len(wholeVariants) > 0 {
+		filepathsMap, err := svc.download(ctx, downloader, downloaderName, url, uniqueStrings(wholeVariants))
+		if err != nil {
+			return nil, oops.Wrapf(err, "failed to download variants")
+		}
+		updateJobStatus(JobStatusProcessing)
+		for i, spec := range specs {
+			if clips[i] != nil {
+				continue
+			}
+			specOpts := opts
+			for _, r := range spec.Ranges {
+				specOpts.Ranges = append(specOpts.Ranges, r.timeRange())
+			}
+			if clips[i], err = svc.mediaProcessor.Clip(ctx, filepathsMap[spec.Variant], specOpts); err != nil {
+				return nil, oops.With("variant", spec.Variant).Wrapf(err, "failed to cut clips")
+			}
+		}
+	}
+
+	var result []string
+	for _, c := range clips {
+		result = append(result, c...)
+	}
+	return result, nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := values[:0:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,176 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/service"
+	"github.com/dir01/mediary/service/mocks"
+	"github.com/gojuno/minimock/v3"
+)
+
+// sectionDownloader is a downloader that can download sections, like yt-dlp
+type sectionDownloader struct {
+	*mocks.DownloaderMock
+	*mocks.SectionDownloaderMock
+}
+
+func TestClipFlow_DownloadsSections(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := sectionDownloader{mocks.NewDownloaderMock(mc), mocks.NewSectionDownloaderMock(mc)}
+	mp := mocks.NewMediaProcessorMock(mc)
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-clip-sections"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  "https://www.youtube.com/watch?v=deadbeef",
+			Type: "clip",
+			Params: map[string]interface{}{
+				"clips": []interface{}{map[string]interface{}{
+					"variant": "Video (mp4)",
+					"ranges":  []interface{}{map[string]interface{}{"start": "12:30", "end": "47:00"}},
+				}},
+				"fadeIn":    1,
+				"uploadUrl": "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+
+	dwn.DownloadSectionsMock.Set(func(_ context.Context, _ string, variant string, ranges []service.TimeRange, fast bool) ([]string, error) {
+		want := []service.TimeRange{{Start: 750 * time.Second, End: 47 * time.Minute}}
+		// clips are accurate by default
+		if variant != "Video (mp4)" || !reflect.DeepEqual(ranges, want) || fast {
+			t.Errorf("unexpected sections of %s: %+v", variant, ranges)
+		}
+		return []string{"/tmp/dl/section.mp4"}, nil
+	})
+	mp.ClipMock.Set(func(_ context.Context, fp string, opts service.ClipOptions) ([]string, error) {
+		want := service.ClipOptions{Ranges: []service.TimeRange{{}}, FadeIn: time.Second}
+		if fp != "/tmp/dl/section.mp4" || !reflect.DeepEqual(opts, want) {
+			t.Errorf("unexpected clip of %s: %+v", fp, opts)
+		}
+		return []string{"/tmp/faded.mp4"}, nil
+	})
+	mp.GetInfoMock.Return(&service.MediaInfo{Duration: 2070 * time.Second, FileLenBytes: 1024}, nil)
+	upl.UploadMock.Set(func(_ context.Context, fp string, url string) error {
+		if fp != "/tmp/faded.mp4" || url != "http://example.com/upload" {
+			t.Errorf("uploaded %s to %s", fp, url)
+		}
+		return nil
+	})
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+	if job.ResultMediaDuration != 2070*time.Second || job.ResultParts != nil {
+		t.Errorf("unexpected result: %v, %+v", job.ResultMediaDuration, job.ResultParts)
+	}
+}
+
+func TestClipFlow_CutsAndConcatenatesDownloadedVariants(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-clip-concatenate"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  "magnet:?xt=urn:btih:deadbeef",
+			Type: "clip",
+			Params: map[string]interface{}{
+				"clips": []interface{}{
+					map[string]interface{}{"variant": "talk.mp3", "ranges": []interface{}{
+						map[string]interface{}{"start": 0, "end": 60},
+						map[string]interface{}{"start": "10:00"},
+					}},
+					map[string]interface{}{"variant": "qa.mp3", "ranges": []interface{}{
+						map[string]interface{}{"start": 30, "end": 90},
+					}},
+				},
+				"mode":        "fast",
+				"concatenate": true,
+				"uploadUrl":   "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+
+	dwn.DownloadMock.Set(func(_ context.Context, _ string, variants []string) (map[string]string, error) {
+		if !reflect.DeepEqual(variants, []string{"talk.mp3", "qa.mp3"}) {
+			t.Errorf("downloaded %v", variants)
+		}
+		return map[string]string{"talk.mp3": "/tmp/dl/talk.mp3", "qa.mp3": "/tmp/dl/qa.mp3"}, nil
+	})
+	mp.ClipMock.Set(func(_ context.Context, fp string, opts service.ClipOptions) ([]string, error) {
+		if !opts.Fast {
+			t.Errorf("expected fast clips")
+		}
+		if fp == "/tmp/dl/talk.mp3" {
+			want := []service.TimeRange{{Start: 0, End: time.Minute}, {Start: 10 * time.Minute}}
+			if !reflect.DeepEqual(opts.Ranges, want) {
+				t.Errorf("unexpected ranges: %+v", opts.Ranges)
+			}
+			return []string{"/tmp/talk1.mp3", "/tmp/talk2.mp3"}, nil
+		}
+		return []string{"/tmp/qa1.mp3"}, nil
+	})
+	mp.ConcatenateMock.Set(func(_ context.Context, fps []string, opts service.ConcatenateOptions) (string, error) {
+		if !reflect.DeepEqual(fps, []string{"/tmp/talk1.mp3", "/tmp/talk2.mp3", "/tmp/qa1.mp3"}) || opts.Container != "mp3" {
+			t.Errorf("unexpected concatenation of %v into %s", fps, opts.Container)
+		}
+		return "/tmp/result.mp3", nil
+	})
+	mp.GetInfoMock.Return(&service.MediaInfo{Duration: 5 * time.Minute, FileLenBytes: 1024}, nil)
+	upl.UploadMock.Set(func(_ context.Context, fp string, url string) error {
+		if fp != "/tmp/result.mp3" {
+			t.Errorf("uploaded %s", fp)
+		}
+		return nil
+	})
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+}
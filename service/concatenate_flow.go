@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,22 +15,116 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
-func (svc *Service) newConcatenateFlow(jobID string, job *Job) (func(ctx context.Context) error, error) {
-	logAttrs := []any{slog.String("jobID", jobID), slog.Any("job", job)}
-	errCtx := oops.With("jobID", jobID, "job", job)
-	type Params struct {
-		Variants   []string `json:"variants"`
-		AudioCodec string   `json:"audioCodec"`
-		UploadURL  string   `json:"uploadUrl"`
+const outputM4B = "m4b"
+
+type concatenateParams struct {
+	ChapterOptions
+	Variants   []string `json:"variants"`
+	AudioCodec string   `json:"audioCodec"`
+	// Container of the concatenation, the one shared by the inputs when empty
+	Container string `json:"container"`
+	// AudioStream picks one of several audio streams of every input.
+	// Audio of video inputs is extracted, unless they are concatenated into a video container.
+	AudioStream AudioStreamSelector `json:"audioStream"`
+	// Video describes the result of concatenating video inputs into a video container
+	Video VideoOptions `json:"video"`
+	// Engine concatenates, ConcatEngineFFmpeg when empty. ConcatEngineNative is a faster one for mp3 without re-encoding.
+	Engine string `json:"engine"`
+	// Output is either empty (same format as inputs) or "m4b" for an audiobook with native chapters
+	Output string `json:"output"`
+	Tags   Tags   `json:"tags"`
+	// TrimSilence, when set, trims leading and trailing silences of every input
+	TrimSilence *TrimSilenceParams `json:"trimSilence"`
+	// Normalize, when set, evens out loudness of the inputs or of the result
+	Normalize *NormalizeParams `json:"normalize"`
+	// Speed is a playback speed of the result, see TranscodeOptions.Speed
+	Speed float64 `json:"speed"`
+	// CoverArt is a URL or a variant ID of an image, picked automatically when empty
+	CoverArt  string `json:"coverArt"`
+	UploadURL string `json:"uploadUrl"`
+	// Waveform, when set, makes waveform peaks and images of the result
+	Waveform *WaveformParams `json:"waveform"`
+	// Previews, when set, makes visual previews of the resulting video
+	Previews *PreviewParams `json:"previews"`
+
+	trimSilenceOpts TrimSilenceOptions
+	normalizeOpts   NormalizeOptions
+}
+
+// parseConcatenateParams parses and validates params of a concatenate job, resolving options of its steps
+func parseConcatenateParams(jobParams map[string]interface{}) (*concatenateParams, error) {
+	params := &concatenateParams{}
+	if err := mapToStruct(jobParams, params); err != nil {
+		return nil, oops.Wrapf(err, "failed to parse job params")
 	}
-	params := Params{}
-	err := mapToStruct(job.Params, &params)
-	if err != nil {
-		return nil, errCtx.Wrapf(err, "failed to parse job params")
+	if err := params.Waveform.validate(false); err != nil {
+		return nil, oops.Wrapf(err, "invalid waveform")
+	}
+	if err := params.Previews.validate(false); err != nil {
+		return nil, oops.Wrapf(err, "invalid previews")
 	}
 	if params.AudioCodec == "" {
 		params.AudioCodec = "copy"
 	}
+	if params.Output != "" && params.Output != outputM4B {
+		return nil, oops.Errorf("unsupported output: %s", params.Output)
+	}
+	if params.Container != "" {
+		if _, err := LookupContainer(params.Container); err != nil {
+			return nil, oops.Wrap(err)
+		}
+	}
+	if err := params.AudioStream.Validate(); err != nil {
+		return nil, oops.Wrap(err)
+	}
+	if err := params.Video.Validate(); err != nil {
+		return nil, oops.Wrapf(err, "invalid video")
+	}
+	if params.Output == outputM4B && params.Video != (VideoOptions{}) {
+		return nil, oops.Errorf("audiobooks have no video")
+	}
+	switch params.Engine {
+	case "", ConcatEngineFFmpeg:
+	case ConcatEngineNative:
+		if params.Output == outputM4B || params.AudioCodec != "copy" || (params.Container != "" && params.Container != "mp3") ||
+			params.Video != (VideoOptions{}) {
+			return nil, oops.Errorf("%s engine only concatenates mp3 without re-encoding", ConcatEngineNative)
+		}
+	default:
+		return nil, oops.Errorf("unknown concatenation engine: %s", params.Engine)
+	}
+	if err := params.ChapterOptions.Validate(); err != nil {
+		return nil, oops.Wrapf(err, "invalid chapters")
+	}
+	if err := ValidateSpeed(params.Speed); err != nil {
+		return nil, oops.Wrap(err)
+	}
+	var err error
+	if params.TrimSilence != nil {
+		if params.trimSilenceOpts, err = params.TrimSilence.Options(); err != nil {
+			return nil, oops.Wrapf(err, "invalid silence trimming")
+		}
+	}
+	if params.Normalize != nil {
+		if params.normalizeOpts, err = params.Normalize.Options(); err != nil {
+			return nil, oops.Wrapf(err, "invalid normalization")
+		}
+	}
+	return params, nil
+}
+
+// normalizesInputs tells whether loudness of every input is evened out, rather than that of the result
+func (params *concatenateParams) normalizesInputs() bool {
+	return params.Normalize != nil && params.Normalize.Mode == NormalizeInputs && len(params.Variants) > 1
+}
+
+func (svc *Service) newConcatenateFlow(jobID string, job *Job) (func(ctx context.Context) error, error) {
+	logAttrs := []any{slog.String("jobID", jobID), slog.Any("job", job)}
+	errCtx := oops.With("jobID", jobID, "job", job)
+	params, err := parseConcatenateParams(job.Params)
+	if err != nil {
+		return nil, errCtx.Wrap(err)
+	}
 	logAttrs = append(logAttrs, slog.Any("params", params))
 	errCtx = errCtx.With("params", params)
 	svc.log.Debug("parsed job params", logAttrs...)
@@ -43,14 +138,17 @@ func (svc *Service) newConcatenateFlow(jobID string, job *Job) (func(ctx context
 			),
 		)
 		defer span.End()
+		fail := func(err error) error {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
 
 		ctx, cancel := context.WithTimeout(jobCtx, 10*time.Second)
 		defer cancel()
 		job, err := svc.storage.GetJob(ctx, jobID)
 		if err != nil {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, err.Error())
-			return errCtx.Wrapf(err, "failed to get job")
+			return fail(errCtx.Wrapf(err, "failed to get job"))
 		}
 		logAttrs = append(logAttrs, slog.Any("job", job))
 		errCtx = errCtx.With("job", job)
@@ -81,7 +179,14 @@ func (svc *Service) newConcatenateFlow(jobID string, job *Job) (func(ctx context
 				attribute.Int("variants.count", len(params.Variants)),
 			),
 		)
-		filepathsMap, err := svc.downloader.Download(downloadCtx, job.URL, params.Variants)
+		downloader, err := svc.selectDownloader(job.Downloader)
+		if err != nil {
+			downloadSpan.RecordError(err)
+			downloadSpan.SetStatus(codes.Error, err.Error())
+			downloadSpan.End()
+			return errCtx.Wrapf(err, "failed to select downloader")
+		}
+		filepathsMap, err := svc.download(downloadCtx, downloader, job.Downloader, job.URL, params.Variants)
 		if err != nil {
 			downloadSpan.RecordError(err)
 			downloadSpan.SetStatus(codes.Error, err.Error())
@@ -90,94 +195,144 @@ func (svc *Service) newConcatenateFlow(jobID string, job *Job) (func(ctx context
 		}
 		downloadSpan.End()
 
-		var resultFilepath string
-		if len(params.Variants) == 1 {
-			resultFilepath = filepathsMap[params.Variants[0]]
-		} else {
+		// tracks of a CUE sheet are tagged from it, unless tags are set explicitly
+		tags := params.Tags.WithDefaults(svc.cueTags(downloadCtx, job.URL, job.Downloader, params.Variants))
+
+		// translate requested variants into actual fs filepaths while preserving order
+		fsFilepaths := make([]string, 0, len(filepathsMap))
+		for _, fp := range params.Variants {
+			fsFilepaths = append(fsFilepaths, filepathsMap[fp])
+		}
+		// extraction, trimming and normalization drop embedded cover art, so it is looked up in the original files
+		sourceFilepaths := fsFilepaths
+		container := concatContainer(params.Container, fsFilepaths, params.Output)
+
+		// video inputs are concatenated as videos into video containers, otherwise only their audio is kept
+		videoOutput := false
+		if params.Output != outputM4B && allVideo(fsFilepaths) {
+			c, _ := LookupContainer(container)
+			videoOutput = c.DefaultVideoCodec != ""
+		}
+		if videoOutput && (params.TrimSilence != nil || params.Normalize != nil || changesSpeed(params.Speed)) {
+			return fail(errCtx.Errorf("silence trimming, normalization and speed changes are not supported for video"))
+		}
+
+		if selectsStream := params.AudioStream != (AudioStreamSelector{}); !videoOutput && (selectsStream || anyVideo(fsFilepaths)) {
 			updateJobStatus(JobStatusProcessing)
-			// translate requested variants into actual fs filepaths while preserving order
-			fsFilepaths := make([]string, 0, len(filepathsMap))
-			for _, fp := range params.Variants {
-				fsFilepaths = append(fsFilepaths, filepathsMap[fp])
+			if fsFilepaths, err = svc.extractAudioOfEach(jobCtx, fsFilepaths, params.Variants, params.AudioStream); err != nil {
+				return fail(errCtx.Wrap(err))
 			}
-
-			// collect per-file durations for chapter markers
-			var chapters []Chapter
-			var offset time.Duration
-			for i, variant := range params.Variants {
-				fileInfo, infoErr := svc.mediaProcessor.GetInfo(downloadCtx, fsFilepaths[i])
-				if infoErr != nil {
-					svc.log.Warn("failed to get duration for chapter, skipping chapter tags",
-						append(logAttrs, slog.Any("error", infoErr), slog.String("variant", variant))...)
-					chapters = nil
-					break
-				}
-				name := strings.TrimSuffix(filepath.Base(variant), filepath.Ext(variant))
-				chapters = append(chapters, Chapter{
-					Title:     name,
-					StartTime: offset,
-					EndTime:   offset + fileInfo.Duration,
-				})
-				offset += fileInfo.Duration
+		}
+		if params.TrimSilence != nil {
+			updateJobStatus(JobStatusProcessing)
+			if fsFilepaths, err = svc.trimSilenceOfEach(jobCtx, fsFilepaths, params.Variants, params.trimSilenceOpts); err != nil {
+				return fail(errCtx.Wrap(err))
+			}
+		}
+		if params.normalizesInputs() {
+			updateJobStatus(JobStatusProcessing)
+			var loudness []LoudnessStats
+			if fsFilepaths, loudness, err = svc.normalizeEach(jobCtx, fsFilepaths, params.Variants, params.normalizeOpts); err != nil {
+				return fail(errCtx.Wrap(err))
 			}
+			job.ResultLoudness = append(job.ResultLoudness, loudness...)
+		}
 
-			svc.log.Debug("starting conversion", logAttrs...)
-			concatCtx, concatCancel := context.WithTimeout(jobCtx, 1*time.Hour)
-			defer concatCancel()
-
-			concatCtx, concatSpan := otel.Tracer("github.com/dir01/mediary/service").Start(concatCtx, "service.Concatenate",
-				trace.WithAttributes(
-					attribute.String("job.id", jobID),
-					attribute.Int("files.count", len(fsFilepaths)),
-					attribute.String("audio_codec", params.AudioCodec),
-				),
-			)
-			resultFilepath, err = svc.mediaProcessor.Concatenate(concatCtx, fsFilepaths, params.AudioCodec)
+		var chapters []Chapter
+		if params.SilenceChapters == nil && (len(params.Variants) > 1 || params.ChapterOptions.explicit()) {
+			updateJobStatus(JobStatusProcessing)
+			chapters, err = svc.buildChapters(downloadCtx, downloader, job.Downloader, job.URL, params.ChapterOptions, params.Variants, fsFilepaths)
 			if err != nil {
-				concatSpan.RecordError(err)
-				concatSpan.SetStatus(codes.Error, err.Error())
-				concatSpan.End()
-				return errCtx.Wrapf(err, "failed to concatenate files")
+				return fail(errCtx.Wrapf(err, "failed to build chapters"))
 			}
-			concatSpan.End()
-
-			// write ID3 chapter tags into the concatenated file
-			if len(chapters) > 0 {
-				if chapErr := svc.mediaProcessor.AddChapterTags(concatCtx, resultFilepath, chapters); chapErr != nil {
-					svc.log.Warn("failed to add chapter tags, proceeding without chapters",
-						append(logAttrs, slog.Any("error", chapErr))...)
-				}
+		}
+
+		concatOpts := ConcatenateOptions{Container: container, AudioCodec: params.AudioCodec, Engine: params.Engine}
+		if videoOutput {
+			concatOpts.Video, concatOpts.AudioStream = params.Video, params.AudioStream
+		}
+		svc.log.Debug("starting conversion", logAttrs...)
+		resultFilepath, err := svc.concatenateInputs(jobCtx, jobID, fsFilepaths, params.Container, concatOpts)
+		if err != nil {
+			return fail(errCtx.Wrapf(err, "failed to concatenate files"))
+		}
+
+		if params.Normalize != nil && !params.normalizesInputs() {
+			updateJobStatus(JobStatusProcessing)
+			var stats *LoudnessStats
+			if resultFilepath, stats, err = svc.normalize(jobCtx, resultFilepath, "", params.normalizeOpts); err != nil {
+				return fail(errCtx.Wrap(err))
+			}
+			job.ResultLoudness = append(job.ResultLoudness, *stats)
+		}
+
+		if changesSpeed(params.Speed) {
+			updateJobStatus(JobStatusProcessing)
+			speedOpts := SpeedOptions{Speed: params.Speed, Container: container}
+			if resultFilepath, err = svc.changeSpeed(jobCtx, resultFilepath, speedOpts); err != nil {
+				return fail(errCtx.Wrap(err))
 			}
+			chapters = ScaleChapters(chapters, params.Speed)
+		}
+
+		// pauses are detected in the final audio, so these chapters need no scaling
+		if params.SilenceChapters != nil {
+			updateJobStatus(JobStatusProcessing)
+			if chapters, err = svc.silenceChapters(jobCtx, resultFilepath, params.ChapterOptions); err != nil {
+				return fail(errCtx.Wrap(err))
+			}
+		}
+
+		coverArt, err := svc.resolveCoverArt(jobCtx, downloader, job.Downloader, job.URL, params.CoverArt, params.Variants, sourceFilepaths)
+		if err != nil {
+			return fail(errCtx.Wrapf(err, "failed to get cover art"))
+		}
+
+		if params.Output == outputM4B {
+			updateJobStatus(JobStatusProcessing)
+		}
+		// a downloaded file is not ours to modify in place
+		ownResult := resultFilepath != sourceFilepaths[0]
+		resultFilepath, err = svc.writeConcatMetadata(jobCtx, resultFilepath, ownResult, params.Output, FileMetadata{
+			Tags:             tags,
+			Chapters:         chapters,
+			CoverArtFilepath: coverArt,
+		})
+		if err != nil {
+			return fail(errCtx.Wrap(err))
 		}
 		logAttrs = append(logAttrs, slog.String("localFilename", resultFilepath))
 		errCtx = errCtx.With("localFilename", resultFilepath)
 
 		info, err := svc.mediaProcessor.GetInfo(downloadCtx, resultFilepath)
 		if err != nil {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, err.Error())
-			return errCtx.Wrapf(err, "failed to get info about result file")
+			return fail(errCtx.Wrapf(err, "failed to get info about result file"))
 		}
 		logAttrs = append(logAttrs, slog.Any("info", info))
 		errCtx = errCtx.With("info", info)
 		job.ResultMediaDuration = info.Duration
 		job.ResultFileBytes = info.FileLenBytes
+		job.ResultMediaInfo = info
 		span.SetAttributes(
 			attribute.Int64("result.bytes", info.FileLenBytes),
 			attribute.Float64("result.duration_seconds", info.Duration.Seconds()),
 		)
 
+		if params.Waveform != nil || params.Previews != nil {
+			updateJobStatus(JobStatusProcessing)
+			if err := svc.makeArtifacts(jobCtx, jobID, []string{resultFilepath}, params.Waveform, params.Previews); err != nil {
+				return fail(errCtx.Wrap(err))
+			}
+		}
+
 		updateJobStatus(JobStatusUploading)
 		svc.log.Debug("starting upload", logAttrs...)
 
 		uploadCtx, uploadCancel := context.WithTimeout(jobCtx, 2*time.Hour)
 		defer uploadCancel()
 
-		err = svc.uploader.Upload(uploadCtx, resultFilepath, params.UploadURL)
-		if err != nil {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, err.Error())
-			return errCtx.Wrapf(err, "failed to upload result")
+		if err = svc.uploader.Upload(uploadCtx, resultFilepath, params.UploadURL); err != nil {
+			return fail(errCtx.Wrapf(err, "failed to upload result"))
 		}
 
 		updateJobStatus(JobStatusComplete)
@@ -185,3 +340,226 @@ func (svc *Service) newConcatenateFlow(jobID string, job *Job) (func(ctx context
 		return nil
 	}, nil
 }
+
+// extractAudioOfEach extracts audio of video files, or of every file when a stream is selected
+func (svc *Service) extractAudioOfEach(ctx context.Context, filepaths []string, variants []string, sel AudioStreamSelector) ([]string, error) {
+	extracted := make([]string, len(filepaths))
+	for i, fp := range filepaths {
+		if sel == (AudioStreamSelector{}) && !isVideo(fp) {
+			extracted[i] = fp
+			continue
+		}
+		var err error
+		if extracted[i], err = svc.extractAudio(ctx, fp, variants[i], ExtractAudioOptions{AudioStream: sel}); err != nil {
+			return nil, err
+		}
+	}
+	return extracted, nil
+}
+
+// trimSilenceOfEach trims silences of every file on its own
+func (svc *Service) trimSilenceOfEach(ctx context.Context, filepaths []string, variants []string, opts TrimSilenceOptions) ([]string, error) {
+	trimmed := make([]string, len(filepaths))
+	for i, fp := range filepaths {
+		var err error
+		if trimmed[i], err = svc.trimSilence(ctx, fp, variants[i], opts); err != nil {
+			return nil, err
+		}
+	}
+	return trimmed, nil
+}
+
+// normalizeEach evens out loudness of every file on its own, returning loudness of each
+func (svc *Service) normalizeEach(
+	ctx context.Context, filepaths []string, variants []string, opts NormalizeOptions,
+) ([]string, []LoudnessStats, error) {
+	normalized := make([]string, len(filepaths))
+	loudness := make([]LoudnessStats, len(filepaths))
+	for i, fp := range filepaths {
+		var stats *LoudnessStats
+		var err error
+		if normalized[i], stats, err = svc.normalize(ctx, fp, variants[i], opts); err != nil {
+			return nil, nil, err
+		}
+		loudness[i] = *stats
+	}
+	return normalized, loudness, nil
+}
+
+// concatenateInputs joins the files into one. A single file is left as it is, unless it is asked to be converted:
+// into another container, with another codec or into a video of its own.
+func (svc *Service) concatenateInputs(
+	ctx context.Context, jobID string, filepaths []string, requestedContainer string, opts ConcatenateOptions,
+) (string, error) {
+	if len(filepaths) == 1 && opts.AudioCodec == "copy" && opts.Video == (VideoOptions{}) {
+		c, ok := ContainerByExt(filepath.Ext(filepaths[0]))
+		if requestedContainer == "" || (ok && c.Name == requestedContainer) {
+			return filepaths[0], nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Hour)
+	defer cancel()
+
+	ctx, span := otel.Tracer("github.com/dir01/mediary/service").Start(ctx, "service.Concatenate",
+		trace.WithAttributes(
+			attribute.String("job.id", jobID),
+			attribute.Int("files.count", len(filepaths)),
+			attribute.String("audio_codec", opts.AudioCodec),
+			attribute.String("container", opts.Container),
+			attribute.String("engine", opts.Engine),
+		),
+	)
+	defer span.End()
+
+	resultFilepath, err := svc.mediaProcessor.Concatenate(ctx, filepaths, opts)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+	return resultFilepath, nil
+}
+
+// writeConcatMetadata writes tags, chapters and cover art into the result. Audiobooks are encoded into m4b
+// along the way, our own mp3 gets ID3 chapter tags, and the rest (including mp4 and mkv videos) get native chapters
+// along with tags. Results that are not ours, like a downloaded file, are written into a copy.
+func (svc *Service) writeConcatMetadata(ctx context.Context, fp string, ownResult bool, output string, metadata FileMetadata) (string, error) {
+	if output == outputM4B {
+		resultFilepath, err := svc.makeAudiobook(ctx, fp, metadata)
+		if err != nil {
+			return "", oops.Wrapf(err, "failed to make audiobook")
+		}
+		return resultFilepath, nil
+	}
+
+	if len(metadata.Chapters) > 0 && ownResult && filepath.Ext(fp) == ".mp3" {
+		if err := svc.mediaProcessor.AddChapterTags(ctx, fp, metadata.Chapters); err != nil {
+			svc.log.Warn("failed to add chapter tags, proceeding without chapters",
+				slog.String("filepath", fp), slog.Any("error", err))
+		}
+		metadata.Chapters = nil
+	}
+	if metadata.Tags.IsEmpty() && metadata.CoverArtFilepath == "" && len(metadata.Chapters) == 0 {
+		return fp, nil
+	}
+	resultFilepath, err := svc.mediaProcessor.WriteMetadata(ctx, fp, metadata)
+	if err != nil {
+		return "", oops.Wrapf(err, "failed to write tags")
+	}
+	return resultFilepath, nil
+}
+
+// makeAudiobook encodes the file into m4b and writes tags, chapters and cover art into it.
+// Tags that audiobook players rely on are derived from title and author, unless set explicitly.
+func (svc *Service) makeAudiobook(ctx context.Context, fp string, metadata FileMetadata) (string, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/service").Start(ctx, "service.MakeAudiobook",
+		trace.WithAttributes(attribute.Int("chapters.count", len(metadata.Chapters))),
+	)
+	defer span.End()
+
+	encodeCtx, encodeCancel := context.WithTimeout(ctx, 1*time.Hour)
+	defer encodeCancel()
+
+	opts, err := ResolveTranscodeOptions("", TranscodeOptions{Container: outputM4B})
+	if err != nil {
+		return "", err
+	}
+	// match the source quality, otherwise the encoder's default bitrate inflates low-bitrate audiobooks
+	if info, err := svc.mediaProcessor.GetInfo(encodeCtx, fp); err != nil {
+		svc.log.Warn("failed to probe audiobook, encoding it at the default bitrate",
+			slog.String("filepath", fp), slog.Any("error", err))
+	} else if bitrate := lossyBitRate(info); bitrate > 0 {
+		opts.Bitrate = strconv.FormatInt(bitrate, 10)
+	}
+	span.SetAttributes(attribute.String("bitrate", opts.Bitrate))
+	encodedFilepath, err := svc.mediaProcessor.Transcode(encodeCtx, fp, opts)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", oops.Wrapf(err, "failed to encode into m4b")
+	}
+
+	tags := &metadata.Tags
+	if tags.Album == "" {
+		tags.Album = tags.Title
+	}
+	if tags.AlbumArtist == "" {
+		tags.AlbumArtist = tags.Author
+	}
+	if tags.Genre == "" {
+		tags.Genre = "Audiobook"
+	}
+
+	resultFilepath, err := svc.mediaProcessor.WriteMetadata(encodeCtx, encodedFilepath, metadata)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", oops.Wrapf(err, "failed to write audiobook metadata")
+	}
+	return resultFilepath, nil
+}
+
+// lossyBitRate is the bitrate of the audio, zero when it is unknown or lossless,
+// since bitrates of lossless audio say nothing about quality
+func lossyBitRate(info *MediaInfo) int64 {
+	audio := info.AudioStream()
+	if audio == nil {
+		return 0
+	}
+	switch {
+	case audio.Codec == "flac", audio.Codec == "alac", audio.Codec == "ape", audio.Codec == "wavpack",
+		strings.HasPrefix(audio.Codec, "pcm_"):
+		return 0
+	case audio.BitRate != 0:
+		return audio.BitRate
+	default:
+		return info.BitRate
+	}
+}
+
+// concatContainer picks the container of the concatenation: the requested one, or the one all files share.
+// Mixed inputs are re-encoded into the most compatible container, or into m4a for audiobooks.
+func concatContainer(requested string, filepaths []string, output string) string {
+	if requested != "" {
+		return requested
+	}
+	var shared string
+	for i, fp := range filepaths {
+		c, ok := ContainerByExt(filepath.Ext(fp))
+		if !ok || (i > 0 && c.Name != shared) {
+			shared = ""
+			break
+		}
+		shared = c.Name
+	}
+	switch {
+	case shared != "":
+		return shared
+	case output == outputM4B:
+		return "m4a"
+	case allVideo(filepaths):
+		// videos of different containers all fit into matroska
+		return "mkv"
+	default:
+		return "mp3"
+	}
+}
+
+func allVideo(filepaths []string) bool {
+	for _, fp := range filepaths {
+		if !isVideo(fp) {
+			return false
+		}
+	}
+	return len(filepaths) > 0
+}
+
+func anyVideo(filepaths []string) bool {
+	for _, fp := range filepaths {
+		if isVideo(fp) {
+			return true
+		}
+	}
+	return false
+}
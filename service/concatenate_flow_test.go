@@ -4,6 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,6 +28,7 @@ func TestConcatenateFlow_GetInfoErrorSkipsChapters(t *testing.T) {
 	queue := mocks.NewJobsQueueMock(mc)
 	dwn := mocks.NewDownloaderMock(mc)
 	mp := mocks.NewMediaProcessorMock(mc)
+	mp.ExtractCoverArtMock.Optional().Return("", errors.New("no cover art"))
 	upl := mocks.NewUploaderMock(mc)
 
 	var onJob func(ctx context.Context, payloadBytes []byte) error
@@ -57,6 +62,7 @@ func TestConcatenateFlow_GetInfoErrorSkipsChapters(t *testing.T) {
 	storage.SaveJobMock.Set(func(_ context.Context, _ *service.Job) error {
 		return nil
 	})
+	storage.GetMetadataMock.Optional().Return(nil, nil)
 
 	fpMap := map[string]string{
 		"intro.mp3":    "/tmp/dl/intro.mp3",
@@ -82,7 +88,10 @@ func TestConcatenateFlow_GetInfoErrorSkipsChapters(t *testing.T) {
 		return nil, errors.New("ffprobe: failed to parse duration")
 	})
 
-	mp.ConcatenateMock.Set(func(_ context.Context, fps []string, codec string) (string, error) {
+	mp.ConcatenateMock.Set(func(_ context.Context, fps []string, opts service.ConcatenateOptions) (string, error) {
+		if opts != (service.ConcatenateOptions{Container: "mp3", AudioCodec: "copy"}) {
+			t.Errorf("unexpected concatenate options: %+v", opts)
+		}
 		return resultPath, nil
 	})
 
@@ -114,6 +123,7 @@ func TestConcatenateFlow_ChapterTimestamps(t *testing.T) {
 	queue := mocks.NewJobsQueueMock(mc)
 	dwn := mocks.NewDownloaderMock(mc)
 	mp := mocks.NewMediaProcessorMock(mc)
+	mp.ExtractCoverArtMock.Optional().Return("", errors.New("no cover art"))
 	upl := mocks.NewUploaderMock(mc)
 
 	// Capture the queue subscriber callback so we can invoke it directly.
@@ -156,6 +166,7 @@ func TestConcatenateFlow_ChapterTimestamps(t *testing.T) {
 	storage.SaveJobMock.Set(func(_ context.Context, _ *service.Job) error {
 		return nil
 	})
+	storage.GetMetadataMock.Optional().Return(nil, nil)
 
 	// Downloader returns a deterministic filepath mapping.
 	variants := []string{"intro.mp3", "chapter1.mp3", "chapter2.mp3"}
@@ -184,7 +195,10 @@ func TestConcatenateFlow_ChapterTimestamps(t *testing.T) {
 		return &service.MediaInfo{Duration: 270 * time.Second, FileLenBytes: 3072}, nil
 	})
 
-	mp.ConcatenateMock.Set(func(_ context.Context, fps []string, codec string) (string, error) {
+	mp.ConcatenateMock.Set(func(_ context.Context, fps []string, opts service.ConcatenateOptions) (string, error) {
+		if opts != (service.ConcatenateOptions{Container: "mp3", AudioCodec: "copy"}) {
+			t.Errorf("unexpected concatenate options: %+v", opts)
+		}
 		return resultPath, nil
 	})
 
@@ -232,3 +246,948 @@ func TestConcatenateFlow_ChapterTimestamps(t *testing.T) {
 		}
 	}
 }
+
+func TestConcatenateFlow_ChapterTitlesFromMetadata(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	mp.ExtractCoverArtMock.Optional().Return("", errors.New("no cover art"))
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-titles"
+	jobURL := "https://example.com/feed.xml"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  jobURL,
+			Type: "concatenate",
+			Params: map[string]interface{}{
+				"variants":  []interface{}{"2020-01-01 Pilot.mp3", "2020-01-08 untitled.mp3"},
+				"uploadUrl": "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+	// podcast downloader provides episode titles, but not for every episode
+	storage.GetMetadataMock.Set(func(_ context.Context, url string, _ string) (*service.Metadata, error) {
+		if url != jobURL {
+			t.Errorf("unexpected metadata url: %s", url)
+		}
+		return &service.Metadata{
+			URL: jobURL,
+			Variants: []service.VariantMetadata{
+				{ID: "2020-01-01 Pilot.mp3", Title: "Pilot: How It All Started"},
+				{ID: "2020-01-08 untitled.mp3"},
+			},
+		}, nil
+	})
+
+	dwn.DownloadMock.Return(map[string]string{
+		"2020-01-01 Pilot.mp3":    "/tmp/dl/1.mp3",
+		"2020-01-08 untitled.mp3": "/tmp/dl/2.mp3",
+	}, nil)
+	mp.GetInfoMock.Return(&service.MediaInfo{Duration: time.Minute, FileLenBytes: 1024}, nil)
+	mp.ConcatenateMock.Return("/tmp/result/output.mp3", nil)
+
+	var gotChapters []service.Chapter
+	mp.AddChapterTagsMock.Set(func(_ context.Context, _ string, chapters []service.Chapter) error {
+		gotChapters = chapters
+		return nil
+	})
+	upl.UploadMock.Return(nil)
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+
+	if len(gotChapters) != 2 {
+		t.Fatalf("expected 2 chapters, got %d", len(gotChapters))
+	}
+	if gotChapters[0].Title != "Pilot: How It All Started" {
+		t.Errorf("chapter 0 Title: got %q", gotChapters[0].Title)
+	}
+	if gotChapters[1].Title != "2020-01-08 untitled" {
+		t.Errorf("chapter 1 Title: got %q", gotChapters[1].Title)
+	}
+}
+
+func TestConcatenateFlow_M4BWritesNativeChapters(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-m4b"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  "http://example.com/audio",
+			Type: "concatenate",
+			Params: map[string]interface{}{
+				"variants": []interface{}{"01.mp3", "02.mp3"},
+				"output":   "m4b",
+				"tags": map[string]interface{}{
+					"title":    "The Book",
+					"author":   "Some Author",
+					"narrator": "Some Narrator",
+				},
+				"uploadUrl": "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+	storage.GetMetadataMock.Optional().Return(nil, nil)
+
+	dwn.DownloadMock.Return(map[string]string{"01.mp3": "/tmp/dl/01.mp3", "02.mp3": "/tmp/dl/02.mp3"}, nil)
+
+	concatPath := "/tmp/result/concat.mp3"
+	m4bPath := "/tmp/result/book.m4b"
+	taggedPath := "/tmp/result/book.tagged.m4b"
+	mp.GetInfoMock.Set(func(_ context.Context, fp string) (*service.MediaInfo, error) {
+		return &service.MediaInfo{
+			Duration: time.Minute,
+			Streams:  []service.StreamInfo{{Type: service.StreamAudio, Codec: "mp3", BitRate: 64000}},
+		}, nil
+	})
+	mp.ConcatenateMock.Return(concatPath, nil)
+	mp.TranscodeMock.Set(func(_ context.Context, fp string, opts service.TranscodeOptions) (string, error) {
+		// the source bitrate is kept
+		if fp != concatPath || opts.Container != "m4b" || opts.AudioCodec != "aac" || opts.Bitrate != "64000" {
+			t.Errorf("unexpected transcode of %s with %+v", fp, opts)
+		}
+		return m4bPath, nil
+	})
+	// the first file has no cover art, the second one does
+	mp.ExtractCoverArtMock.Set(func(_ context.Context, fp string) (string, error) {
+		if fp == "/tmp/dl/01.mp3" {
+			return "", errors.New("no video stream")
+		}
+		return fp + ".jpg", nil
+	})
+
+	var written service.FileMetadata
+	mp.WriteMetadataMock.Set(func(_ context.Context, fp string, metadata service.FileMetadata) (string, error) {
+		if fp != m4bPath {
+			t.Errorf("metadata written into %s", fp)
+		}
+		written = metadata
+		return taggedPath, nil
+	})
+
+	upl.UploadMock.Set(func(_ context.Context, fp string, url string) error {
+		if fp != taggedPath {
+			t.Errorf("uploaded %s instead of m4b", fp)
+		}
+		return nil
+	})
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+
+	wantTags := service.Tags{
+		Title:       "The Book",
+		Album:       "The Book",
+		AlbumArtist: "Some Author",
+		Genre:       "Audiobook",
+		Author:      "Some Author",
+		Narrator:    "Some Narrator",
+	}
+	if written.Tags != wantTags {
+		t.Errorf("unexpected tags: %+v", written.Tags)
+	}
+	if written.CoverArtFilepath != "/tmp/dl/02.mp3.jpg" {
+		t.Errorf("unexpected cover art: %s", written.CoverArtFilepath)
+	}
+	if len(written.Chapters) != 2 || written.Chapters[1].Title != "02" || written.Chapters[1].StartTime != time.Minute {
+		t.Errorf("unexpected chapters: %+v", written.Chapters)
+	}
+}
+
+func TestConcatenateFlow_ChaptersFromCueSheet(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	mp.ExtractCoverArtMock.Optional().Return("", errors.New("no cover art"))
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	// the sheet refers to the original .wav rip, while the torrent has .flac files
+	cuePath := filepath.Join(t.TempDir(), "album.cue")
+	cue := "TITLE \"Album\"\n" +
+		"FILE \"CD1.wav\" WAVE\n" +
+		"  TRACK 01 AUDIO\n    TITLE \"First\"\n    INDEX 01 00:00:00\n" +
+		"  TRACK 02 AUDIO\n    TITLE \"Second\"\n    INDEX 01 02:00:00\n" +
+		"FILE \"CD2.wav\" WAVE\n" +
+		"  TRACK 03 AUDIO\n    INDEX 01 00:00:00\n"
+	if err := os.WriteFile(cuePath, []byte(cue), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	jobID := "test-job-cue"
+	jobURL := "magnet:?xt=urn:btih:deadbeef"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  jobURL,
+			Type: "concatenate",
+			Params: map[string]interface{}{
+				"variants":      []interface{}{"Album/CD1.flac", "Album/CD2.flac"},
+				"chapterTitles": "cue",
+				"uploadUrl":     "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+	storage.GetMetadataMock.Return(&service.Metadata{
+		URL: jobURL,
+		Variants: []service.VariantMetadata{
+			{ID: "Album/CD1.flac"}, {ID: "Album/CD2.flac"}, {ID: "Other/other.cue"}, {ID: "Album/Album.cue"},
+		},
+	}, nil)
+
+	dwn.DownloadMock.Set(func(_ context.Context, _ string, variants []string) (map[string]string, error) {
+		if len(variants) == 1 {
+			if variants[0] != "Album/Album.cue" {
+				t.Errorf("unexpected download of %v", variants)
+			}
+			return map[string]string{variants[0]: cuePath}, nil
+		}
+		return map[string]string{"Album/CD1.flac": "/tmp/dl/CD1.flac", "Album/CD2.flac": "/tmp/dl/CD2.flac"}, nil
+	})
+	mp.GetInfoMock.Return(&service.MediaInfo{Duration: 5 * time.Minute, FileLenBytes: 1024}, nil)
+	mp.ConcatenateMock.Set(func(_ context.Context, _ []string, opts service.ConcatenateOptions) (string, error) {
+		if opts.Container != "flac" {
+			t.Errorf("unexpected container %s", opts.Container)
+		}
+		return "/tmp/result/output.flac", nil
+	})
+
+	// ID3 chapters don't suit flac, so chapters are written along with tags
+	var gotChapters []service.Chapter
+	mp.WriteMetadataMock.Set(func(_ context.Context, fp string, metadata service.FileMetadata) (string, error) {
+		gotChapters = metadata.Chapters
+		return "/tmp/result/tagged.flac", nil
+	})
+	upl.UploadMock.Return(nil)
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+
+	want := []service.Chapter{
+		{Title: "First", StartTime: 0, EndTime: 2 * time.Minute},
+		{Title: "Second", StartTime: 2 * time.Minute, EndTime: 5 * time.Minute},
+		{Title: "Track 03", StartTime: 5 * time.Minute, EndTime: 10 * time.Minute},
+	}
+	if !reflect.DeepEqual(gotChapters, want) {
+		t.Errorf("chapters = %+v, want %+v", gotChapters, want)
+	}
+}
+
+func TestConcatenateFlow_UserChaptersForSingleFile(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	mp.ExtractCoverArtMock.Optional().Return("", errors.New("no cover art"))
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-user-chapters"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  "https://www.youtube.com/watch?v=deadbeef",
+			Type: "concatenate",
+			Params: map[string]interface{}{
+				"variants": []interface{}{"audio"},
+				"chapters": []interface{}{
+					map[string]interface{}{"title": "Intro", "start": 0},
+					map[string]interface{}{"title": "Talk", "start": "1:30"},
+					map[string]interface{}{"title": "Too late", "start": "1:00:00"},
+				},
+				"uploadUrl": "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+	storage.GetMetadataMock.Optional().Return(nil, nil)
+
+	dwn.DownloadMock.Return(map[string]string{"audio": "/tmp/dl/audio.mp3"}, nil)
+	mp.GetInfoMock.Return(&service.MediaInfo{Duration: 10 * time.Minute, FileLenBytes: 1024}, nil)
+
+	var written service.FileMetadata
+	mp.WriteMetadataMock.Set(func(_ context.Context, fp string, metadata service.FileMetadata) (string, error) {
+		if fp != "/tmp/dl/audio.mp3" {
+			t.Errorf("metadata written into %s", fp)
+		}
+		written = metadata
+		return "/tmp/result/audio.mp3", nil
+	})
+	upl.UploadMock.Set(func(_ context.Context, fp string, url string) error {
+		if fp != "/tmp/result/audio.mp3" {
+			t.Errorf("uploaded %s instead of a copy with chapters", fp)
+		}
+		return nil
+	})
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+
+	want := []service.Chapter{
+		{Title: "Intro", StartTime: 0, EndTime: 90 * time.Second},
+		{Title: "Talk", StartTime: 90 * time.Second, EndTime: 10 * time.Minute},
+	}
+	if !reflect.DeepEqual(written.Chapters, want) {
+		t.Errorf("chapters = %+v, want %+v", written.Chapters, want)
+	}
+}
+
+func TestConcatenateFlow_ContainerOfSingleFile(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	mp.ExtractCoverArtMock.Optional().Return("", errors.New("no cover art"))
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-single-file-container"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  "https://www.youtube.com/watch?v=deadbeef",
+			Type: "concatenate",
+			Params: map[string]interface{}{
+				"variants":  []interface{}{"audio"},
+				"container": "m4a",
+				"uploadUrl": "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+	storage.GetMetadataMock.Optional().Return(nil, nil)
+
+	dwn.DownloadMock.Return(map[string]string{"audio": "/tmp/dl/audio.mp3"}, nil)
+	mp.GetInfoMock.Return(&service.MediaInfo{Duration: 10 * time.Minute, FileLenBytes: 1024}, nil)
+	// a single file is converted into the requested container all the same
+	mp.ConcatenateMock.Set(func(_ context.Context, filepaths []string, opts service.ConcatenateOptions) (string, error) {
+		if !reflect.DeepEqual(filepaths, []string{"/tmp/dl/audio.mp3"}) || opts.Container != "m4a" {
+			t.Errorf("unexpected concatenation of %v: %+v", filepaths, opts)
+		}
+		return "/tmp/result/audio.m4a", nil
+	})
+	upl.UploadMock.Set(func(_ context.Context, fp string, url string) error {
+		if fp != "/tmp/result/audio.m4a" {
+			t.Errorf("uploaded %s instead of m4a", fp)
+		}
+		return nil
+	})
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+}
+
+func TestConcatenateFlow_NormalizeInputs(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-normalize"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  "magnet:?xt=urn:btih:deadbeef",
+			Type: "concatenate",
+			Params: map[string]interface{}{
+				"variants":  []interface{}{"quiet.mp3", "loud.mp3"},
+				"normalize": map[string]interface{}{"mode": "inputs", "targetLufs": -18},
+				"uploadUrl": "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+	storage.GetMetadataMock.Optional().Return(nil, nil)
+
+	dwn.DownloadMock.Return(map[string]string{"quiet.mp3": "/tmp/dl/quiet.mp3", "loud.mp3": "/tmp/dl/loud.mp3"}, nil)
+	mp.NormalizeMock.Set(func(_ context.Context, fp string, opts service.NormalizeOptions) (string, *service.LoudnessStats, error) {
+		if opts != (service.NormalizeOptions{IntegratedLUFS: -18, TruePeak: -1.5, LRA: 11}) {
+			t.Errorf("unexpected options: %+v", opts)
+		}
+		stats := &service.LoudnessStats{InputI: -30, OutputI: -18, NormalizationType: "linear"}
+		if fp == "/tmp/dl/loud.mp3" {
+			stats.InputI = -10
+		}
+		return strings.Replace(fp, "/dl/", "/normalized/", 1), stats, nil
+	})
+	// embedded cover art is looked up in the originals, since normalization drops it
+	mp.ExtractCoverArtMock.Set(func(_ context.Context, fp string) (string, error) {
+		if !strings.HasPrefix(fp, "/tmp/dl/") {
+			t.Errorf("cover art looked up in %s", fp)
+		}
+		return "", errors.New("no cover art")
+	})
+	mp.GetInfoMock.Return(&service.MediaInfo{Duration: time.Minute, FileLenBytes: 1024}, nil)
+	mp.ConcatenateMock.Set(func(_ context.Context, fps []string, _ service.ConcatenateOptions) (string, error) {
+		if !reflect.DeepEqual(fps, []string{"/tmp/normalized/quiet.mp3", "/tmp/normalized/loud.mp3"}) {
+			t.Errorf("concatenated %v instead of normalized files", fps)
+		}
+		return "/tmp/result/output.mp3", nil
+	})
+	mp.AddChapterTagsMock.Return(nil)
+	upl.UploadMock.Return(nil)
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+
+	want := []service.LoudnessStats{
+		{Variant: "quiet.mp3", InputI: -30, OutputI: -18, NormalizationType: "linear"},
+		{Variant: "loud.mp3", InputI: -10, OutputI: -18, NormalizationType: "linear"},
+	}
+	if !reflect.DeepEqual(job.ResultLoudness, want) {
+		t.Errorf("ResultLoudness = %+v, want %+v", job.ResultLoudness, want)
+	}
+}
+
+func TestConcatenateFlow_TrimSilenceAndSilenceChapters(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	mp.ExtractCoverArtMock.Optional().Return("", errors.New("no cover art"))
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-silence"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  "https://example.com/lecture",
+			Type: "concatenate",
+			Params: map[string]interface{}{
+				"variants":        []interface{}{"lecture.mp3"},
+				"trimSilence":     map[string]interface{}{"maxInnerSilence": 3},
+				"silenceChapters": map[string]interface{}{"noiseDb": -40, "minChapterLength": "10:00"},
+				"chapters":        []interface{}{map[string]interface{}{"title": "Introduction"}},
+				"uploadUrl":       "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+	storage.GetMetadataMock.Optional().Return(nil, nil)
+
+	dwn.DownloadMock.Return(map[string]string{"lecture.mp3": "/tmp/dl/lecture.mp3"}, nil)
+	mp.TrimSilenceMock.Set(func(_ context.Context, fp string, opts service.TrimSilenceOptions) (string, error) {
+		want := service.DefaultTrimSilenceOptions
+		want.MaxInnerSilence = 3 * time.Second
+		if opts != want {
+			t.Errorf("unexpected trim options: %+v", opts)
+		}
+		return "/tmp/trimmed/lecture.mp3", nil
+	})
+	mp.DetectSilenceChaptersMock.Set(func(_ context.Context, fp string, opts service.SilenceChapterOptions) ([]service.Chapter, error) {
+		if fp != "/tmp/trimmed/lecture.mp3" {
+			t.Errorf("chapters detected in %s instead of the trimmed file", fp)
+		}
+		want := service.SilenceChapterOptions{
+			SilenceOptions:   service.SilenceOptions{NoiseDB: -40, MinSilence: 2 * time.Second},
+			MinChapterLength: 10 * time.Minute,
+		}
+		if opts != want {
+			t.Errorf("unexpected detection options: %+v", opts)
+		}
+		return []service.Chapter{
+			{Title: "Chapter 1", StartTime: 0, EndTime: 20 * time.Minute},
+			{Title: "Chapter 2", StartTime: 20 * time.Minute, EndTime: 50 * time.Minute},
+		}, nil
+	})
+	var tagged []service.Chapter
+	mp.AddChapterTagsMock.Set(func(_ context.Context, fp string, chapters []service.Chapter) error {
+		if fp != "/tmp/trimmed/lecture.mp3" {
+			t.Errorf("chapter tags written into %s", fp)
+		}
+		tagged = chapters
+		return nil
+	})
+	mp.GetInfoMock.Return(&service.MediaInfo{Duration: 50 * time.Minute, FileLenBytes: 1024}, nil)
+	upl.UploadMock.Set(func(_ context.Context, fp string, url string) error {
+		if fp != "/tmp/trimmed/lecture.mp3" {
+			t.Errorf("uploaded %s instead of the trimmed file", fp)
+		}
+		return nil
+	})
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+
+	want := []service.Chapter{
+		{Title: "Introduction", StartTime: 0, EndTime: 20 * time.Minute},
+		{Title: "Chapter 2", StartTime: 20 * time.Minute, EndTime: 50 * time.Minute},
+	}
+	if !reflect.DeepEqual(tagged, want) {
+		t.Errorf("chapters = %+v, want %+v", tagged, want)
+	}
+}
+
+func TestConcatenateFlow_SpeedScalesChapters(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	mp.ExtractCoverArtMock.Optional().Return("", errors.New("no cover art"))
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-speed"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  "magnet:?xt=urn:btih:deadbeef",
+			Type: "concatenate",
+			Params: map[string]interface{}{
+				"variants":  []interface{}{"01.mp3", "02.mp3"},
+				"speed":     1.5,
+				"uploadUrl": "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+	storage.GetMetadataMock.Optional().Return(nil, nil)
+
+	dwn.DownloadMock.Return(map[string]string{"01.mp3": "/tmp/dl/01.mp3", "02.mp3": "/tmp/dl/02.mp3"}, nil)
+	mp.GetInfoMock.Set(func(_ context.Context, fp string) (*service.MediaInfo, error) {
+		if fp == "/tmp/fast/output.mp3" {
+			return &service.MediaInfo{Duration: 80 * time.Second, FileLenBytes: 512}, nil
+		}
+		return &service.MediaInfo{Duration: time.Minute, FileLenBytes: 1024}, nil
+	})
+	mp.ConcatenateMock.Return("/tmp/result/output.mp3", nil)
+	mp.ChangeSpeedMock.Set(func(_ context.Context, fp string, opts service.SpeedOptions) (string, error) {
+		if fp != "/tmp/result/output.mp3" || opts != (service.SpeedOptions{Speed: 1.5, Container: "mp3"}) {
+			t.Errorf("unexpected speed change of %s: %+v", fp, opts)
+		}
+		return "/tmp/fast/output.mp3", nil
+	})
+	var tagged []service.Chapter
+	mp.AddChapterTagsMock.Set(func(_ context.Context, fp string, chapters []service.Chapter) error {
+		if fp != "/tmp/fast/output.mp3" {
+			t.Errorf("chapter tags written into %s", fp)
+		}
+		tagged = chapters
+		return nil
+	})
+	upl.UploadMock.Return(nil)
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+
+	want := []service.Chapter{
+		{Title: "01", StartTime: 0, EndTime: 40 * time.Second},
+		{Title: "02", StartTime: 40 * time.Second, EndTime: 80 * time.Second},
+	}
+	if !reflect.DeepEqual(tagged, want) {
+		t.Errorf("chapters = %+v, want %+v", tagged, want)
+	}
+	if job.ResultMediaDuration != 80*time.Second {
+		t.Errorf("ResultMediaDuration = %v, want 80s", job.ResultMediaDuration)
+	}
+}
+
+func TestConcatenateFlow_ExtractsAudioOfVideos(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	mp.ExtractCoverArtMock.Optional().Return("", errors.New("no cover art"))
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-video-lectures"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  "magnet:?xt=urn:btih:deadbeef",
+			Type: "concatenate",
+			Params: map[string]interface{}{
+				"variants":    []interface{}{"01.mkv", "02.mp4"},
+				"audioStream": map[string]interface{}{"language": "eng"},
+				"container":   "mp3",
+				"uploadUrl":   "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+	storage.GetMetadataMock.Optional().Return(nil, nil)
+
+	dwn.DownloadMock.Return(map[string]string{"01.mkv": "/tmp/dl/01.mkv", "02.mp4": "/tmp/dl/02.mp4"}, nil)
+	mp.ExtractAudioMock.Set(func(_ context.Context, fp string, opts service.ExtractAudioOptions) (string, error) {
+		if opts.AudioStream.Language != "eng" || opts.Container != "" {
+			t.Errorf("unexpected extraction options: %+v", opts)
+		}
+		return strings.TrimSuffix(fp, filepath.Ext(fp)) + ".m4a", nil
+	})
+	mp.GetInfoMock.Return(&service.MediaInfo{Duration: time.Minute, FileLenBytes: 1024}, nil)
+	mp.ConcatenateMock.Set(func(_ context.Context, fps []string, opts service.ConcatenateOptions) (string, error) {
+		if !reflect.DeepEqual(fps, []string{"/tmp/dl/01.m4a", "/tmp/dl/02.m4a"}) || opts.Container != "mp3" {
+			t.Errorf("unexpected concatenation of %v into %s", fps, opts.Container)
+		}
+		return "/tmp/result/output.mp3", nil
+	})
+	mp.AddChapterTagsMock.Return(nil)
+	upl.UploadMock.Return(nil)
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+	if mp.ExtractAudioAfterCounter() != 2 {
+		t.Errorf("expected audio of both videos to be extracted, got %d", mp.ExtractAudioAfterCounter())
+	}
+}
+
+func TestConcatenateFlow_ConcatenatesVideos(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-movie-parts"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  "magnet:?xt=urn:btih:deadbeef",
+			Type: "concatenate",
+			Params: map[string]interface{}{
+				"variants":    []interface{}{"cd1.avi", "cd2.mkv"},
+				"audioStream": map[string]interface{}{"language": "eng"},
+				"video":       map[string]interface{}{"crf": 23, "width": 1280, "height": 720},
+				"uploadUrl":   "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+	storage.GetMetadataMock.Optional().Return(nil, nil)
+
+	dwn.DownloadMock.Return(map[string]string{"cd1.avi": "/tmp/dl/cd1.avi", "cd2.mkv": "/tmp/dl/cd2.mkv"}, nil)
+	mp.GetInfoMock.Return(&service.MediaInfo{Duration: time.Hour, FileLenBytes: 1024}, nil)
+	mp.ConcatenateMock.Set(func(_ context.Context, fps []string, opts service.ConcatenateOptions) (string, error) {
+		if !reflect.DeepEqual(fps, []string{"/tmp/dl/cd1.avi", "/tmp/dl/cd2.mkv"}) {
+			t.Errorf("expected videos to be concatenated as they are, got %v", fps)
+		}
+		want := service.ConcatenateOptions{
+			Container:   "mkv",
+			AudioCodec:  "copy",
+			Video:       service.VideoOptions{CRF: 23, Width: 1280, Height: 720},
+			AudioStream: service.AudioStreamSelector{Language: "eng"},
+		}
+		if !reflect.DeepEqual(opts, want) {
+			t.Errorf("got options %+v, want %+v", opts, want)
+		}
+		return "/tmp/result/output.mkv", nil
+	})
+	mp.WriteMetadataMock.Set(func(_ context.Context, fp string, metadata service.FileMetadata) (string, error) {
+		// matroska chapters are written natively, not as ID3 tags
+		if len(metadata.Chapters) != 2 || metadata.Chapters[1].StartTime != time.Hour {
+			t.Errorf("unexpected chapters: %+v", metadata.Chapters)
+		}
+		return "/tmp/result/tagged.mkv", nil
+	})
+	mp.ExtractCoverArtMock.Optional().Return("", errors.New("no cover art"))
+	upl.UploadMock.Set(func(_ context.Context, fp string, _ string) error {
+		if fp != "/tmp/result/tagged.mkv" {
+			t.Errorf("expected tagged result to be uploaded, got %s", fp)
+		}
+		return nil
+	})
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+}
+
+func TestVideoOptions_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		opts    service.VideoOptions
+		wantErr bool
+	}{
+		{name: "empty", opts: service.VideoOptions{}},
+		{name: "full", opts: service.VideoOptions{Codec: "libx265", CRF: 28, Width: 1920, Height: 1080}},
+		{name: "crf out of range", opts: service.VideoOptions{CRF: 64}, wantErr: true},
+		{name: "width alone", opts: service.VideoOptions{Width: 1280}, wantErr: true},
+		{name: "odd height", opts: service.VideoOptions{Width: 1280, Height: 719}, wantErr: true},
+		{name: "copy with crf", opts: service.VideoOptions{Codec: "copy", CRF: 20}, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.opts.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestConcatenateFlow_NativeEngine(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	mp.ExtractCoverArtMock.Optional().Return("", errors.New("no cover art"))
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-native"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  "http://example.com/audio",
+			Type: "concatenate",
+			Params: map[string]interface{}{
+				"variants":  []interface{}{"part1.mp3", "part2.mp3"},
+				"engine":    "native",
+				"uploadUrl": "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+	storage.GetMetadataMock.Optional().Return(nil, nil)
+	dwn.AcceptsURLMock.Optional().Return(true)
+	dwn.DownloadMock.Return(map[string]string{"part1.mp3": "/tmp/dl/part1.mp3", "part2.mp3": "/tmp/dl/part2.mp3"}, nil)
+	mp.GetInfoMock.Return(&service.MediaInfo{Duration: time.Minute, FileLenBytes: 1024}, nil)
+
+	resultPath := "/tmp/result/output.mp3"
+	mp.ConcatenateMock.Set(func(_ context.Context, _ []string, opts service.ConcatenateOptions) (string, error) {
+		want := service.ConcatenateOptions{Container: "mp3", AudioCodec: "copy", Engine: service.ConcatEngineNative}
+		if opts != want {
+			t.Errorf("unexpected concatenate options: %+v", opts)
+		}
+		return resultPath, nil
+	})
+	// the native engine leaves chapters to ID3 tags, like ffmpeg does
+	mp.AddChapterTagsMock.Set(func(_ context.Context, fp string, chapters []service.Chapter) error {
+		if fp != resultPath || len(chapters) != 2 {
+			t.Errorf("unexpected chapter tags of %s: %+v", fp, chapters)
+		}
+		return nil
+	})
+	upl.UploadMock.Return(nil)
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+}
+
+func TestConcatenateFlow_InvalidEngine(t *testing.T) {
+	mc := minimock.NewController(t)
+	queue := mocks.NewJobsQueueMock(mc)
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, _ func(context.Context, []byte) error) {})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(mocks.NewDownloaderMock(mc), mocks.NewStorageMock(mc), queue,
+		mocks.NewMediaProcessorMock(mc), mocks.NewUploaderMock(mc), logger)
+	svc.Start()
+	defer svc.Stop()
+
+	for _, tc := range []struct {
+		name   string
+		params map[string]interface{}
+	}{
+		{name: "unknown", params: map[string]interface{}{"engine": "sox"}},
+		{name: "re-encoding", params: map[string]interface{}{"engine": "native", "audioCodec": "libmp3lame"}},
+		{name: "other container", params: map[string]interface{}{"engine": "native", "container": "m4a"}},
+		{name: "audiobook", params: map[string]interface{}{"engine": "native", "output": "m4b"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.params["variants"] = []interface{}{"part1.mp3", "part2.mp3"}
+			tc.params["uploadUrl"] = "http://example.com/upload"
+			_, err := svc.CreateJob(context.Background(), &service.JobParams{
+				URL:    "magnet:?xt=urn:btih:deadbeef",
+				Type:   "concatenate",
+				Params: tc.params,
+			})
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
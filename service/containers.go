@@ -0,0 +1,56 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownContainer = fmt.Errorf("unknown container")
+
+// Container describes an output file format
+type Container struct {
+	Name        string
+	Ext         string
+	ContentType string
+	// DefaultAudioCodec is an ffmpeg encoder used when no codec is requested explicitly
+	DefaultAudioCodec string
+	// DefaultVideoCodec is empty for audio-only containers: video streams are dropped
+	DefaultVideoCodec string
+}
+
+var containers = []Container{
+	{Name: "mp3", Ext: ".mp3", ContentType: "audio/mpeg", DefaultAudioCodec: "libmp3lame"},
+	{Name: "m4a", Ext: ".m4a", ContentType: "audio/mp4", DefaultAudioCodec: "aac"},
+	// m4b is m4a for audiobooks: players remember position and show chapters
+	{Name: "m4b", Ext: ".m4b", ContentType: "audio/mp4", DefaultAudioCodec: "aac"},
+	{Name: "opus", Ext: ".opus", ContentType: "audio/opus", DefaultAudioCodec: "libopus"},
+	{Name: "ogg", Ext: ".ogg", ContentType: "audio/ogg", DefaultAudioCodec: "libvorbis"},
+	{Name: "flac", Ext: ".flac", ContentType: "audio/flac", DefaultAudioCodec: "flac"},
+	{Name: "wav", Ext: ".wav", ContentType: "audio/wav", DefaultAudioCodec: "pcm_s16le"},
+	{Name: "mp4", Ext: ".mp4", ContentType: "video/mp4", DefaultAudioCodec: "aac", DefaultVideoCodec: "libx264"},
+	// mkv holds nearly any codec, so parts of different origins are concatenated into it without re-encoding
+	{Name: "mkv", Ext: ".mkv", ContentType: "video/x-matroska", DefaultAudioCodec: "aac", DefaultVideoCodec: "libx264"},
+	{Name: "webm", Ext: ".webm", ContentType: "video/webm", DefaultAudioCodec: "libopus", DefaultVideoCodec: "libvpx-vp9"},
+}
+
+// LookupContainer finds a container by its name, like "mp3"
+func LookupContainer(name string) (Container, error) {
+	name = strings.ToLower(strings.TrimPrefix(name, "."))
+	for _, c := range containers {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+	return Container{}, fmt.Errorf("%w: %s", ErrUnknownContainer, name)
+}
+
+// ContainerByExt finds a container by file extension, like ".mp3"
+func ContainerByExt(ext string) (Container, bool) {
+	ext = strings.ToLower(ext)
+	for _, c := range containers {
+		if c.Ext == ext {
+			return c, true
+		}
+	}
+	return Container{}, false
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/samber/oops"
+)
+
+// coverArtNames are file names conventionally used for album art, in order of preference
+var coverArtNames = []string{"cover.jpg", "cover.jpeg", "cover.png", "folder.jpg", "folder.jpeg", "folder.png"}
+
+// maxCoverArtBytes protects from downloading something huge by mistake
+const maxCoverArtBytes = 20 * 1024 * 1024
+
+// resolveCoverArt returns a local path of cover art for the job result, or an empty string if there is none.
+// coverArt is either a URL of an image or a variant ID. When it's empty, cover art is picked automatically:
+// first picture embedded into one of the source files wins, then cover.jpg/folder.jpg variants are considered.
+func (svc *Service) resolveCoverArt(
+	ctx context.Context, downloader Downloader, downloaderName string, url string,
+	coverArt string, variants []string, sourceFilepaths []string,
+) (string, error) {
+	errCtx := oops.With("url", url, "coverArt", coverArt)
+
+	if strings.HasPrefix(coverArt, "http://") || strings.HasPrefix(coverArt, "https://") {
+		fp, err := fetchCoverArt(ctx, coverArt)
+		if err != nil {
+			return "", errCtx.Wrapf(err, "failed to fetch cover art")
+		}
+		return fp, nil
+	}
+	if coverArt != "" {
+		filepathsMap, err := downloader.Download(ctx, url, []string{coverArt})
+		if err != nil {
+			return "", errCtx.Wrapf(err, "failed to download cover art variant")
+		}
+		return filepathsMap[coverArt], nil
+	}
+
+	for _, source := range sourceFilepaths {
+		if fp, err := svc.mediaProcessor.ExtractCoverArt(ctx, source); err == nil {
+			return fp, nil
+		} else {
+			svc.log.Debug("no embedded cover art", slog.String("filepath", source), slog.Any("error", err))
+		}
+	}
+
+	variant := svc.findCoverArtVariant(ctx, url, downloaderName, variants)
+	if variant == "" {
+		return "", nil
+	}
+	filepathsMap, err := downloader.Download(ctx, url, []string{variant})
+	if err != nil {
+		// it was just a guess anyway
+		svc.log.Warn("failed to download cover art variant", slog.String("variant", variant), slog.Any("error", err))
+		return "", nil
+	}
+	return filepathsMap[variant], nil
+}
+
+// findCoverArtVariant looks for cover.jpg and alike among the variants of the URL,
+// preferring the ones in the same directory as the first requested variant
+func (svc *Service) findCoverArtVariant(ctx context.Context, url string, downloader string, variants []string) string {
+	if len(variants) == 0 {
+		return ""
+	}
+	return svc.findVariant(ctx, url, downloader, variants[0], func(id string) (int, bool) {
+		for i, name := range coverArtNames {
+			if strings.EqualFold(path.Base(id), name) {
+				return i, true
+			}
+		}
+		return 0, false
+	})
+}
+
+func fetchCoverArt(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	ext := ".jpg"
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "image/png") || strings.HasSuffix(strings.ToLower(req.URL.Path), ".png") {
+		ext = ".png"
+	}
+	file, err := os.CreateTemp("", "cover_*"+ext)
+	if err != nil {
+		return "", err
+	}
+	// a byte past the limit tells a picture that is too large from one that just fits
+	n, err := io.Copy(file, io.LimitReader(resp.Body, maxCoverArtBytes+1))
+	if err == nil && n > maxCoverArtBytes {
+		err = fmt.Errorf("cover art is larger than %d bytes", maxCoverArtBytes)
+	}
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), file.Close()
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFetchCoverArt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size := maxCoverArtBytes
+		if r.URL.Path == "/large.png" {
+			size++
+		}
+		_, _ = w.Write(bytes.Repeat([]byte{'x'}, size))
+	}))
+	defer server.Close()
+
+	fp, err := fetchCoverArt(context.Background(), server.URL+"/cover.png")
+	if err != nil {
+		t.Fatalf("fetchCoverArt failed: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Remove(fp) })
+	if stat, err := os.Stat(fp); err != nil || stat.Size() != maxCoverArtBytes || filepath.Ext(fp) != ".png" {
+		t.Errorf("cover art is not fetched whole: %s, %v", fp, err)
+	}
+
+	if fp, err := fetchCoverArt(context.Background(), server.URL+"/large.png"); err == nil {
+		_ = os.Remove(fp)
+		t.Error("expected an error for cover art over the limit")
+	}
+}
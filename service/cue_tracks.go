@@ -0,0 +1,223 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/dir01/mediary/cuesheet"
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// cueTrackSep separates the ID of a single-file rip from a track number in IDs of its CUE tracks,
+// like "Album/Album.flac#03"
+const cueTrackSep = "#"
+
+// cueRipExts are extensions of files that albums are ripped into as a whole, all of them lossless
+var cueRipExts = []string{".flac", ".ape", ".wv", ".wav", ".tta"}
+
+// addCueTracks finds CUE sheets next to single-file rips, and adds tracks they describe right after the rips,
+// as virtual variants. Sheets are downloaded to be read, and a sheet that fails to is only logged.
+func (svc *Service) addCueTracks(ctx context.Context, downloader Downloader, url string, metadata *Metadata) {
+	rips := make(map[string][]string)
+	for _, v := range metadata.Variants {
+		if slices.Contains(cueRipExts, strings.ToLower(path.Ext(v.ID))) {
+			rips[path.Dir(v.ID)] = append(rips[path.Dir(v.ID)], v.ID)
+		}
+	}
+	if len(rips) == 0 {
+		return
+	}
+
+	ctx, span := otel.Tracer("github.com/dir01/mediary/service").Start(ctx, "service.AddCueTracks",
+		trace.WithAttributes(attribute.String("url", url)),
+	)
+	defer span.End()
+
+	tracksOf := make(map[string][]VariantMetadata)
+	added := 0
+	for _, v := range metadata.Variants {
+		dirRips := rips[path.Dir(v.ID)]
+		if !strings.EqualFold(path.Ext(v.ID), ".cue") || len(dirRips) == 0 {
+			continue
+		}
+		sheet, err := svc.loadCueSheet(ctx, downloader, url, v.ID)
+		if err != nil {
+			svc.log.Warn("failed to read CUE sheet, its tracks are not listed",
+				slog.String("url", url), slog.String("cueSheet", v.ID), slog.Any("error", err))
+			continue
+		}
+		// there may be several sheets of the same rip, like one in UTF-8 and one in a legacy encoding
+		if rip, tracks := sheetTracks(sheet, dirRips); rip != "" && tracksOf[rip] == nil {
+			tracksOf[rip] = tracks
+			added += len(tracks)
+		}
+	}
+	span.SetAttributes(attribute.Int("tracks.count", added))
+	if added == 0 {
+		return
+	}
+
+	variants := make([]VariantMetadata, 0, len(metadata.Variants)+added)
+	for _, v := range metadata.Variants {
+		variants = append(variants, v)
+		variants = append(variants, tracksOf[v.ID]...)
+	}
+	metadata.Variants = variants
+}
+
+// sheetTracks describes tracks of the sheet as variants, if it is a sheet of one of the rips, which is returned.
+// Sheets of several files describe files that are tracks already, so they have no tracks to add.
+func sheetTracks(sheet *cuesheet.Sheet, rips []string) (string, []VariantMetadata) {
+	if len(sheet.Files) != 1 || len(sheet.Files[0].Tracks) < 2 {
+		return "", nil
+	}
+	file := sheet.Files[0]
+	// sheets often refer to the rip by a stale extension, and a lone rip is the one anyway
+	var rip string
+	for _, r := range rips {
+		if cueFileStem(r) == cueFileStem(file.Name) {
+			rip = r
+		}
+	}
+	if rip == "" && len(rips) == 1 {
+		rip = rips[0]
+	}
+	if rip == "" {
+		return "", nil
+	}
+
+	tracks := make([]VariantMetadata, 0, len(file.Tracks))
+	for i, t := range file.Tracks {
+		track := &CueTrack{
+			Source:         rip,
+			Number:         t.Number,
+			Total:          len(file.Tracks),
+			Performer:      t.Performer,
+			Album:          sheet.Title,
+			AlbumPerformer: sheet.Performer,
+			Genre:          sheet.Genre,
+			Date:           sheet.Date,
+			Start:          t.Start,
+		}
+		v := VariantMetadata{
+			ID:       fmt.Sprintf("%s%s%02d", rip, cueTrackSep, t.Number),
+			Title:    cmp.Or(t.Title, fmt.Sprintf("Track %02d", t.Number)),
+			CueTrack: track,
+		}
+		if i+1 < len(file.Tracks) {
+			track.End = file.Tracks[i+1].Start
+			v.Duration = track.End - track.Start
+		}
+		tracks = append(tracks, v)
+	}
+	return rip, tracks
+}
+
+// cueTracksOf returns CUE tracks among the variants, by their IDs.
+// Stored metadata is only looked up when some of the variants look like tracks.
+func (svc *Service) cueTracksOf(ctx context.Context, url string, downloader string, variants []string) map[string]VariantMetadata {
+	if !slices.ContainsFunc(variants, func(v string) bool { return strings.Contains(v, cueTrackSep) }) {
+		return nil
+	}
+	metadata, err := svc.storedMetadata(ctx, url, downloader)
+	if err != nil || metadata == nil {
+		return nil
+	}
+	tracks := make(map[string]VariantMetadata)
+	for _, v := range metadata.Variants {
+		if v.CueTrack != nil && slices.Contains(variants, v.ID) {
+			tracks[v.ID] = v
+		}
+	}
+	return tracks
+}
+
+// download downloads the variants. CUE tracks among them are cut out of their rips, which are downloaded instead,
+// into lossless FLAC whatever the rip is, since ffmpeg can't encode APE or WavPack.
+func (svc *Service) download(
+	ctx context.Context, downloader Downloader, downloaderName string, url string, variants []string,
+) (map[string]string, error) {
+	tracks := svc.cueTracksOf(ctx, url, downloaderName, variants)
+	if len(tracks) == 0 {
+		return downloader.Download(ctx, url, variants)
+	}
+
+	toDownload := make([]string, 0, len(variants))
+	// tracks of the same rip are cut in one go, in the order they were requested
+	var rips []string
+	tracksOfRip := make(map[string][]string)
+	for _, v := range uniqueStrings(variants) {
+		track, ok := tracks[v]
+		if !ok {
+			toDownload = append(toDownload, v)
+			continue
+		}
+		if tracksOfRip[track.CueTrack.Source] == nil {
+			rips = append(rips, track.CueTrack.Source)
+			toDownload = append(toDownload, track.CueTrack.Source)
+		}
+		tracksOfRip[track.CueTrack.Source] = append(tracksOfRip[track.CueTrack.Source], v)
+	}
+
+	filepathsMap, err := downloader.Download(ctx, url, uniqueStrings(toDownload))
+	if err != nil {
+		return nil, err
+	}
+	for _, rip := range rips {
+		ids := tracksOfRip[rip]
+		ranges := make([]TimeRange, len(ids))
+		for i, id := range ids {
+			ranges[i] = TimeRange{Start: tracks[id].CueTrack.Start, End: tracks[id].CueTrack.End}
+		}
+		clips, err := svc.mediaProcessor.Clip(ctx, filepathsMap[rip], ClipOptions{Ranges: ranges, Container: "flac"})
+		if err != nil {
+			return nil, oops.With("url", url, "variant", rip).Wrapf(err, "failed to cut CUE tracks")
+		}
+		for i, id := range ids {
+			filepathsMap[id] = clips[i]
+		}
+	}
+	return filepathsMap, nil
+}
+
+// cueTags are tags of the variants if they are all tracks of the same CUE sheet: those of the track
+// for a single one, and those of the album for several. Otherwise there are none.
+func (svc *Service) cueTags(ctx context.Context, url string, downloader string, variants []string) Tags {
+	tracks := svc.cueTracksOf(ctx, url, downloader, variants)
+	if len(variants) == 0 || len(tracks) == 0 {
+		return Tags{}
+	}
+	first, ok := tracks[variants[0]]
+	if !ok {
+		return Tags{}
+	}
+	for _, v := range variants[1:] {
+		if track, ok := tracks[v]; !ok || track.CueTrack.Source != first.CueTrack.Source {
+			return Tags{}
+		}
+	}
+
+	album := first.CueTrack
+	tags := Tags{
+		Title:       album.Album,
+		Artist:      album.AlbumPerformer,
+		Album:       album.Album,
+		AlbumArtist: album.AlbumPerformer,
+		Year:        album.Date,
+		Genre:       album.Genre,
+	}
+	if len(variants) == 1 {
+		tags.Title = first.Title
+		tags.Artist = cmp.Or(album.Performer, album.AlbumPerformer)
+		tags.Track = fmt.Sprintf("%d/%d", album.Number, album.Total)
+	}
+	return tags
+}
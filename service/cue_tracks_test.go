@@ -0,0 +1,204 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/service"
+	"github.com/dir01/mediary/service/mocks"
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestGetMetadata_CueTracks(t *testing.T) {
+	mc := minimock.NewController(t)
+	dwn := mocks.NewDownloaderMock(mc)
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	queue.SubscribeMock.Optional().Set(func(context.Context, string, func(context.Context, []byte) error) {})
+	queue.RunMock.Optional().Set(func() {})
+	queue.ShutdownMock.Optional().Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, nil, nil, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	// the sheet refers to the .wav the album was ripped into before it was compressed
+	cuePath := filepath.Join(t.TempDir(), "album.cue")
+	cue := "REM GENRE Jazz\nREM DATE 1959\nPERFORMER \"Band\"\nTITLE \"Album\"\n" +
+		"FILE \"Band - Album.wav\" WAVE\n" +
+		"  TRACK 01 AUDIO\n    TITLE \"First\"\n    INDEX 01 00:00:00\n" +
+		"  TRACK 02 AUDIO\n    TITLE \"Second\"\n    PERFORMER \"Guest\"\n    INDEX 00 05:58:00\n    INDEX 01 06:00:00\n"
+	if err := os.WriteFile(cuePath, []byte(cue), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	url := "magnet:?xt=urn:btih:deadbeef"
+	storage.GetMetadataMock.Return(nil, nil)
+	dwn.AcceptsURLMock.Return(true)
+	dwn.GetMetadataMock.Return(&service.Metadata{
+		URL: url,
+		Variants: []service.VariantMetadata{
+			{ID: "Album/Band - Album.flac"}, {ID: "Album/Band - Album.cue"}, {ID: "Album/cover.jpg"},
+			// a sheet of separate tracks adds nothing
+			{ID: "Other/01.flac"}, {ID: "Other/02.flac"}, {ID: "Other/other.cue"},
+		},
+	}, nil)
+	dwn.DownloadMock.Set(func(_ context.Context, _ string, variants []string) (map[string]string, error) {
+		if len(variants) != 1 {
+			t.Fatalf("unexpected download of %v", variants)
+		}
+		if variants[0] == "Other/other.cue" {
+			return nil, errors.New("no peers")
+		}
+		return map[string]string{variants[0]: cuePath}, nil
+	})
+	var saved *service.Metadata
+	storage.SaveMetadataMock.Set(func(_ context.Context, _ string, metadata *service.Metadata) error {
+		saved = metadata
+		return nil
+	})
+
+	metadata, err := svc.GetMetadata(context.Background(), service.MetadataRequest{URL: url})
+	if err != nil {
+		t.Fatalf("GetMetadata: %v", err)
+	}
+	album := service.CueTrack{
+		Source: "Album/Band - Album.flac", Total: 2,
+		Album: "Album", AlbumPerformer: "Band", Genre: "Jazz", Date: "1959",
+	}
+	first, second := album, album
+	first.Number, first.End = 1, 6*time.Minute
+	second.Number, second.Performer, second.Start = 2, "Guest", 6*time.Minute
+	want := []service.VariantMetadata{
+		{ID: "Album/Band - Album.flac"},
+		{ID: "Album/Band - Album.flac#01", Title: "First", Duration: 6 * time.Minute, CueTrack: &first},
+		{ID: "Album/Band - Album.flac#02", Title: "Second", CueTrack: &second},
+		{ID: "Album/Band - Album.cue"}, {ID: "Album/cover.jpg"},
+		{ID: "Other/01.flac"}, {ID: "Other/02.flac"}, {ID: "Other/other.cue"},
+	}
+	if !reflect.DeepEqual(metadata.Variants, want) {
+		t.Errorf("variants = %+v\nwant %+v", metadata.Variants, want)
+	}
+	if saved != metadata {
+		t.Error("metadata with tracks is not saved")
+	}
+}
+
+func TestConcatenateFlow_CueTracks(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	mp.ExtractCoverArtMock.Optional().Return("", errors.New("no cover art"))
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-cue-tracks"
+	jobURL := "magnet:?xt=urn:btih:deadbeef"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  jobURL,
+			Type: "concatenate",
+			Params: map[string]interface{}{
+				"variants":  []interface{}{"Album/Album.ape#03", "Album/Album.ape#01"},
+				"container": "mp3",
+				"tags":      map[string]interface{}{"title": "Favourites"},
+				"uploadUrl": "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+	track := func(number int, title string, start, end time.Duration) service.VariantMetadata {
+		return service.VariantMetadata{
+			ID:    fmt.Sprintf("Album/Album.ape#%02d", number),
+			Title: title,
+			CueTrack: &service.CueTrack{
+				Source: "Album/Album.ape", Number: number, Total: 3,
+				Album: "Album", AlbumPerformer: "Band", Date: "1959", Start: start, End: end,
+			},
+		}
+	}
+	storage.GetMetadataMock.Return(&service.Metadata{
+		URL: jobURL,
+		Variants: []service.VariantMetadata{
+			{ID: "Album/Album.ape"},
+			track(1, "First", 0, 2*time.Minute),
+			track(2, "Second", 2*time.Minute, 5*time.Minute),
+			track(3, "Third", 5*time.Minute, 0),
+		},
+	}, nil)
+
+	dwn.DownloadMock.Set(func(_ context.Context, _ string, variants []string) (map[string]string, error) {
+		if !reflect.DeepEqual(variants, []string{"Album/Album.ape"}) {
+			t.Errorf("unexpected download of %v", variants)
+		}
+		return map[string]string{"Album/Album.ape": "/tmp/dl/Album.ape"}, nil
+	})
+	mp.ClipMock.Set(func(_ context.Context, fp string, opts service.ClipOptions) ([]string, error) {
+		want := service.ClipOptions{
+			Ranges:    []service.TimeRange{{Start: 5 * time.Minute}, {Start: 0, End: 2 * time.Minute}},
+			Container: "flac",
+		}
+		if fp != "/tmp/dl/Album.ape" || !reflect.DeepEqual(opts, want) {
+			t.Errorf("unexpected clip of %s: %+v", fp, opts)
+		}
+		return []string{"/tmp/clip001.flac", "/tmp/clip002.flac"}, nil
+	})
+	mp.GetInfoMock.Return(&service.MediaInfo{Duration: 2 * time.Minute, FileLenBytes: 1024}, nil)
+	mp.ConcatenateMock.Set(func(_ context.Context, fps []string, opts service.ConcatenateOptions) (string, error) {
+		if !reflect.DeepEqual(fps, []string{"/tmp/clip001.flac", "/tmp/clip002.flac"}) {
+			t.Errorf("concatenated %v", fps)
+		}
+		return "/tmp/result/output.mp3", nil
+	})
+	var gotChapters []service.Chapter
+	mp.AddChapterTagsMock.Set(func(_ context.Context, _ string, chapters []service.Chapter) error {
+		gotChapters = chapters
+		return nil
+	})
+	var gotTags service.Tags
+	mp.WriteMetadataMock.Set(func(_ context.Context, fp string, metadata service.FileMetadata) (string, error) {
+		gotTags = metadata.Tags
+		return "/tmp/result/tagged.mp3", nil
+	})
+	upl.UploadMock.Return(nil)
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+
+	wantChapters := []service.Chapter{
+		{Title: "Third", StartTime: 0, EndTime: 2 * time.Minute},
+		{Title: "First", StartTime: 2 * time.Minute, EndTime: 4 * time.Minute},
+	}
+	if !reflect.DeepEqual(gotChapters, wantChapters) {
+		t.Errorf("chapters = %+v, want %+v", gotChapters, wantChapters)
+	}
+	wantTags := service.Tags{Title: "Favourites", Artist: "Band", Album: "Album", AlbumArtist: "Band", Year: "1959"}
+	if gotTags != wantTags {
+		t.Errorf("tags = %+v, want %+v", gotTags, wantTags)
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/samber/oops"
+)
+
+var ErrUnknownDownloader = fmt.Errorf("unknown downloader")
+
+// DownloaderInfo describes what a downloader is capable of
+type DownloaderInfo struct {
+	Name string `json:"name"`
+	// Schemes are URL schemes the downloader accepts, like "magnet" or "https"
+	Schemes []string `json:"schemes"`
+	// Patterns are human-readable descriptions of URLs the downloader accepts, like "*.m3u8"
+	Patterns              []string `json:"patterns,omitempty"`
+	AllowMultipleVariants bool     `json:"allow_multiple_variants"`
+}
+
+// Describer is implemented by downloaders that can tell what they are capable of.
+// Name returned in DownloaderInfo should match Metadata.DownloaderName.
+type Describer interface {
+	Describe() DownloaderInfo
+}
+
+// DownloaderSelector is implemented by composite downloaders,
+// allowing to bypass URL-based selection and force a specific downloader.
+type DownloaderSelector interface {
+	// Downloaders lists registered downloaders, in order of priority
+	Downloaders() []DownloaderInfo
+	// Select returns a downloader that only uses the downloader with the given name, or ErrUnknownDownloader
+	Select(name string) (Downloader, error)
+}
+
+// Downloaders lists registered downloaders, in order of priority
+func (svc *Service) Downloaders() []DownloaderInfo {
+	if selector, ok := svc.downloader.(DownloaderSelector); ok {
+		return selector.Downloaders()
+	}
+	if describer, ok := svc.downloader.(Describer); ok {
+		return []DownloaderInfo{describer.Describe()}
+	}
+	return nil
+}
+
+// selectDownloader returns the downloader with the given name, or the default one if name is empty
+func (svc *Service) selectDownloader(name string) (Downloader, error) {
+	if name == "" {
+		return svc.downloader, nil
+	}
+	selector, ok := svc.downloader.(DownloaderSelector)
+	if !ok {
+		return nil, oops.With("downloader", name).Wrapf(ErrUnknownDownloader, "downloader selection is not supported")
+	}
+	return selector.Select(name)
+}
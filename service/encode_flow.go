@@ -19,14 +19,27 @@ func (svc *Service) newUploadOriginalFlow(jobID string, job *Job) (func(ctx cont
 	}
 	errCtx := oops.With("jobID", jobID, "job", job)
 	type Params struct {
-		Variant   string `json:"variant"`
+		Variant string `json:"variant"`
+		// Tags and CoverArt, when set, are written into a copy of the original
+		Tags      Tags   `json:"tags"`
+		CoverArt  string `json:"coverArt"`
 		UploadURL string `json:"uploadUrl"`
+		// Waveform, when set, makes waveform peaks and images of the result
+		Waveform *WaveformParams `json:"waveform"`
+		// Previews, when set, makes visual previews of the resulting video
+		Previews *PreviewParams `json:"previews"`
 	}
 	params := Params{}
 	err := mapToStruct(job.Params, &params)
 	if err != nil {
 		return nil, errCtx.Wrapf(err, "failed to parse job params")
 	}
+	if err := params.Waveform.validate(false); err != nil {
+		return nil, errCtx.Wrapf(err, "invalid waveform")
+	}
+	if err := params.Previews.validate(false); err != nil {
+		return nil, errCtx.Wrapf(err, "invalid previews")
+	}
 	logAttrs = append(logAttrs, slog.Any("params", params))
 	errCtx = errCtx.With("params", params)
 	svc.log.Debug("parsed job params", logAttrs...)
@@ -79,7 +92,14 @@ func (svc *Service) newUploadOriginalFlow(jobID string, job *Job) (func(ctx cont
 				attribute.String("variant", params.Variant),
 			),
 		)
-		filepathsMap, err := svc.downloader.Download(downloadCtx, job.URL, []string{params.Variant})
+		downloader, err := svc.selectDownloader(job.Downloader)
+		if err != nil {
+			downloadSpan.RecordError(err)
+			downloadSpan.SetStatus(codes.Error, err.Error())
+			downloadSpan.End()
+			return errCtx.Wrapf(err, "failed to select downloader")
+		}
+		filepathsMap, err := svc.download(downloadCtx, downloader, job.Downloader, job.URL, []string{params.Variant})
 		if err != nil {
 			downloadSpan.RecordError(err)
 			downloadSpan.SetStatus(codes.Error, err.Error())
@@ -89,6 +109,8 @@ func (svc *Service) newUploadOriginalFlow(jobID string, job *Job) (func(ctx cont
 		downloadSpan.End()
 
 		downloadedFilepath := filepathsMap[params.Variant]
+		// tracks of a CUE sheet are tagged from it, unless tags are set explicitly
+		tags := params.Tags.WithDefaults(svc.cueTags(downloadCtx, job.URL, job.Downloader, []string{params.Variant}))
 		logAttrs = append(logAttrs, slog.String("downloadedFilepath", downloadedFilepath))
 		errCtx = errCtx.With("downloadedFilepath", downloadedFilepath)
 		svc.log.Debug("downloaded file", logAttrs...)
@@ -104,12 +126,41 @@ func (svc *Service) newUploadOriginalFlow(jobID string, job *Job) (func(ctx cont
 			svc.log.Debug("got info about result file", logAttrs...)
 			job.ResultMediaDuration = info.Duration
 			job.ResultFileBytes = info.FileLenBytes
+			job.ResultMediaInfo = info
 			span.SetAttributes(
 				attribute.Int64("result.bytes", info.FileLenBytes),
 				attribute.Float64("result.duration_seconds", info.Duration.Seconds()),
 			)
 		}
 
+		if !tags.IsEmpty() || params.CoverArt != "" {
+			updateJobStatus(JobStatusProcessing)
+			coverArt, err := svc.resolveCoverArt(downloadCtx, downloader, job.Downloader, job.URL, params.CoverArt, []string{params.Variant}, []string{downloadedFilepath})
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return errCtx.Wrapf(err, "failed to get cover art")
+			}
+			downloadedFilepath, err = svc.mediaProcessor.WriteMetadata(downloadCtx, downloadedFilepath, FileMetadata{
+				Tags:             tags,
+				CoverArtFilepath: coverArt,
+			})
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return errCtx.Wrapf(err, "failed to write tags")
+			}
+		}
+
+		if params.Waveform != nil || params.Previews != nil {
+			updateJobStatus(JobStatusProcessing)
+			if err := svc.makeArtifacts(jobCtx, jobID, []string{downloadedFilepath}, params.Waveform, params.Previews); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return errCtx.Wrap(err)
+			}
+		}
+
 		updateJobStatus(JobStatusUploading)
 		svc.log.Debug("starting upload", logAttrs...)
 
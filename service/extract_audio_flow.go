@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// videoExts are extensions of video files mediary can't write, but can take audio out of
+var videoExts = []string{".avi", ".mov", ".m4v", ".wmv", ".flv", ".ts"}
+
+func (svc *Service) newExtractAudioFlow(jobID string, job *Job) (func(ctx context.Context) error, error) {
+	logAttrs := []any{slog.String("jobID", jobID), slog.Any("job", job)}
+	errCtx := oops.With("jobID", jobID, "job", job)
+	type Params struct {
+		Variant string `json:"variant"`
+		// AudioStream picks one of several audio streams, the first one when empty
+		AudioStream AudioStreamSelector `json:"audioStream"`
+		// Container is an audio container, like "mp3"; the one that holds the stream as it is when empty
+		Container  string `json:"container"`
+		AudioCodec string `json:"audioCodec"`
+		Bitrate    string `json:"bitrate"`
+		Tags       Tags   `json:"tags"`
+		// CoverArt is a URL or a variant ID of an image, picked automatically when empty
+		CoverArt  string `json:"coverArt"`
+		UploadURL string `json:"uploadUrl"`
+		// Waveform, when set, makes waveform peaks and images of the result
+		Waveform *WaveformParams `json:"waveform"`
+	}
+	params := Params{}
+	if err := mapToStruct(job.Params, &params); err != nil {
+		return nil, errCtx.Wrapf(err, "failed to parse job params")
+	}
+	if err := params.Waveform.validate(false); err != nil {
+		return nil, errCtx.Wrapf(err, "invalid waveform")
+	}
+	if params.Variant == "" {
+		return nil, errCtx.Errorf("no variant provided")
+	}
+	if params.UploadURL == "" {
+		return nil, errCtx.Errorf("no upload URL provided")
+	}
+	if err := params.AudioStream.Validate(); err != nil {
+		return nil, errCtx.Wrap(err)
+	}
+	if params.Container != "" {
+		container, err := LookupContainer(params.Container)
+		if err != nil {
+			return nil, errCtx.Wrap(err)
+		}
+		if container.DefaultVideoCodec != "" {
+			return nil, errCtx.Errorf("%s is not an audio container", container.Name)
+		}
+	}
+	opts := ExtractAudioOptions{
+		Container:   params.Container,
+		AudioCodec:  params.AudioCodec,
+		Bitrate:     params.Bitrate,
+		AudioStream: params.AudioStream,
+	}
+	logAttrs = append(logAttrs, slog.Any("params", params))
+	errCtx = errCtx.With("params", params)
+	svc.log.Debug("parsed job params", logAttrs...)
+
+	return func(jobCtx context.Context) error {
+		jobCtx, span := otel.Tracer("github.com/dir01/mediary/service").Start(jobCtx, "service.ExtractAudioFlow",
+			trace.WithAttributes(
+				attribute.String("job.id", jobID),
+				attribute.String("variant", params.Variant),
+				attribute.String("container", params.Container),
+			),
+		)
+		defer span.End()
+
+		ctx, cancel := context.WithTimeout(jobCtx, 10*time.Second)
+		defer cancel()
+		job, err := svc.storage.GetJob(ctx, jobID)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrapf(err, "failed to get job")
+		}
+
+		updateJobStatus := func(status string) {
+			statusCtx, statusCancel := context.WithTimeout(jobCtx, 10*time.Second)
+			defer statusCancel()
+			job.DisplayStatus = status
+			if err = svc.storage.SaveJob(statusCtx, job); err != nil {
+				attrs := append([]any{
+					slog.String("state", job.DisplayStatus),
+					slog.Any("error", err),
+				}, logAttrs...)
+				svc.log.Error("failed to save job state, proceeding", attrs...)
+			}
+		}
+
+		updateJobStatus(JobStatusDownloading)
+		svc.log.Debug("starting download", logAttrs...)
+
+		downloadCtx, downloadCancel := context.WithTimeout(jobCtx, 1*time.Hour)
+		defer downloadCancel()
+
+		downloader, err := svc.selectDownloader(job.Downloader)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrapf(err, "failed to select downloader")
+		}
+		filepathsMap, err := svc.download(downloadCtx, downloader, job.Downloader, job.URL, []string{params.Variant})
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrapf(err, "failed to download files")
+		}
+		downloadedFilepath := filepathsMap[params.Variant]
+		logAttrs = append(logAttrs, slog.String("downloadedFilepath", downloadedFilepath))
+		errCtx = errCtx.With("downloadedFilepath", downloadedFilepath)
+
+		updateJobStatus(JobStatusProcessing)
+		svc.log.Debug("starting extraction", logAttrs...)
+
+		resultFilepath, err := svc.extractAudio(jobCtx, downloadedFilepath, params.Variant, opts)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrap(err)
+		}
+
+		coverArt, err := svc.resolveCoverArt(downloadCtx, downloader, job.Downloader, job.URL, params.CoverArt, []string{params.Variant}, []string{downloadedFilepath})
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrapf(err, "failed to get cover art")
+		}
+		if !params.Tags.IsEmpty() || coverArt != "" {
+			resultFilepath, err = svc.mediaProcessor.WriteMetadata(jobCtx, resultFilepath, FileMetadata{
+				Tags:             params.Tags,
+				CoverArtFilepath: coverArt,
+			})
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return errCtx.Wrapf(err, "failed to write tags")
+			}
+		}
+		logAttrs = append(logAttrs, slog.String("resultFilepath", resultFilepath))
+		errCtx = errCtx.With("resultFilepath", resultFilepath)
+
+		info, err := svc.mediaProcessor.GetInfo(jobCtx, resultFilepath)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrapf(err, "failed to get info about result file")
+		}
+		job.ResultMediaDuration = info.Duration
+		job.ResultFileBytes = info.FileLenBytes
+		job.ResultMediaInfo = info
+		span.SetAttributes(
+			attribute.Int64("result.bytes", info.FileLenBytes),
+			attribute.Float64("result.duration_seconds", info.Duration.Seconds()),
+		)
+
+		if params.Waveform != nil {
+			updateJobStatus(JobStatusProcessing)
+			if err := svc.makeArtifacts(jobCtx, jobID, []string{resultFilepath}, params.Waveform, nil); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return errCtx.Wrap(err)
+			}
+		}
+
+		updateJobStatus(JobStatusUploading)
+		svc.log.Debug("starting upload", logAttrs...)
+
+		uploadCtx, uploadCancel := context.WithTimeout(jobCtx, 2*time.Hour)
+		defer uploadCancel()
+
+		if err := svc.uploader.Upload(uploadCtx, resultFilepath, params.UploadURL); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrapf(err, "failed to upload result")
+		}
+
+		updateJobStatus(JobStatusComplete)
+		svc.log.Debug("job complete", logAttrs...)
+		return nil
+	}, nil
+}
+
+func (svc *Service) extractAudio(ctx context.Context, fp string, variant string, opts ExtractAudioOptions) (string, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/service").Start(ctx, "service.ExtractAudio",
+		trace.WithAttributes(attribute.String("variant", variant)),
+	)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Hour)
+	defer cancel()
+
+	resultFilepath, err := svc.mediaProcessor.ExtractAudio(ctx, fp, opts)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", oops.With("filepath", fp, "variant", variant).Wrapf(err, "failed to extract audio")
+	}
+	return resultFilepath, nil
+}
+
+// isVideo tells whether the file is a video, judging by its extension
+func isVideo(fp string) bool {
+	ext := strings.ToLower(filepath.Ext(fp))
+	if c, ok := ContainerByExt(ext); ok {
+		return c.DefaultVideoCodec != ""
+	}
+	for _, videoExt := range videoExts {
+		if ext == videoExt {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/service"
+	"github.com/dir01/mediary/service/mocks"
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestExtractAudioFlow(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	mp.ExtractCoverArtMock.Optional().Return("", errors.New("no cover art"))
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-extract-audio"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  "magnet:?xt=urn:btih:deadbeef",
+			Type: "extract_audio",
+			Params: map[string]interface{}{
+				"variant":     "lecture.mkv",
+				"audioStream": map[string]interface{}{"index": 1},
+				"container":   "mp3",
+				"bitrate":     "64k",
+				"uploadUrl":   "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+	storage.GetMetadataMock.Optional().Return(nil, nil)
+
+	dwn.DownloadMock.Return(map[string]string{"lecture.mkv": "/tmp/dl/lecture.mkv"}, nil)
+	mp.ExtractAudioMock.Set(func(_ context.Context, fp string, opts service.ExtractAudioOptions) (string, error) {
+		if fp != "/tmp/dl/lecture.mkv" || opts.Container != "mp3" || opts.Bitrate != "64k" ||
+			opts.AudioStream.Index == nil || *opts.AudioStream.Index != 1 {
+			t.Errorf("unexpected extraction of %s: %+v", fp, opts)
+		}
+		return "/tmp/result/lecture.mp3", nil
+	})
+	mp.GetInfoMock.Return(&service.MediaInfo{Duration: time.Hour, FileLenBytes: 28800000}, nil)
+	upl.UploadMock.Set(func(_ context.Context, fp string, url string) error {
+		if fp != "/tmp/result/lecture.mp3" {
+			t.Errorf("uploaded %s instead of extracted audio", fp)
+		}
+		return nil
+	})
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+	if job.DisplayStatus != service.JobStatusComplete || job.ResultMediaInfo == nil {
+		t.Errorf("unexpected job state: %+v", job)
+	}
+}
+
+func TestExtractAudioFlow_InvalidParams(t *testing.T) {
+	mc := minimock.NewController(t)
+	queue := mocks.NewJobsQueueMock(mc)
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, _ func(context.Context, []byte) error) {})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(mocks.NewDownloaderMock(mc), mocks.NewStorageMock(mc), queue,
+		mocks.NewMediaProcessorMock(mc), mocks.NewUploaderMock(mc), logger)
+	svc.Start()
+	defer svc.Stop()
+
+	for name, params := range map[string]map[string]interface{}{
+		"video container": {"variant": "a.mkv", "container": "mp4", "uploadUrl": "http://example.com/upload"},
+		"both selectors": {
+			"variant": "a.mkv", "audioStream": map[string]interface{}{"index": 1, "language": "eng"},
+			"uploadUrl": "http://example.com/upload",
+		},
+		"no upload URL": {"variant": "a.mkv"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.CreateJob(context.Background(), &service.JobParams{
+				URL: "magnet:?xt=urn:btih:deadbeef", Type: "extract_audio", Params: params,
+			})
+			if err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -27,11 +27,17 @@ type JobParams struct {
 	URL    string                 `json:"url"`
 	Type   string                 `json:"type"`
 	Params map[string]interface{} `json:"params"`
+	// Downloader forces a specific downloader, instead of the first one that accepts the URL
+	Downloader string `json:"downloader,omitempty"`
 }
 
 const (
 	jobTypeConcatenate    = "concatenate"
 	jobTypeUploadOriginal = "upload_original"
+	jobTypeTranscode      = "transcode"
+	jobTypeSplit          = "split"
+	jobTypeClip           = "clip"
+	jobTypeExtractAudio   = "extract_audio"
 )
 
 type Job struct {
@@ -40,6 +46,12 @@ type Job struct {
 	DisplayStatus       string        `json:"status"`
 	ResultMediaDuration time.Duration `json:"result_media_duration,omitempty"`
 	ResultFileBytes     int64         `json:"result_file_bytes,omitempty"`
+	// ResultLoudness are measurements of loudness normalization, if it was requested
+	ResultLoudness []LoudnessStats `json:"result_loudness,omitempty"`
+	// ResultParts are parts of split and clip jobs, in order; result duration and bytes are their totals
+	ResultParts []ResultPart `json:"result_parts,omitempty"`
+	// ResultMediaInfo describes the result file of jobs that produce a single one
+	ResultMediaInfo *MediaInfo `json:"result_media_info,omitempty"`
 }
 
 const JobStatusCreated = "created"
@@ -80,6 +92,12 @@ func (svc *Service) CreateJob(ctx context.Context, params *JobParams) (*Job, err
 		return nil, err
 	}
 
+	if _, err := svc.selectDownloader(params.Downloader); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
 	// disallow duplicate jobs
 	if existingState, err := svc.storage.GetJob(ctx, jobID); err != nil {
 		svc.log.Error("failed to get job state", slog.Any("error", err))
@@ -194,6 +212,14 @@ func (svc *Service) constructFlow(jobID string, jobState *Job) (func(ctx context
 		return svc.newConcatenateFlow(jobID, jobState)
 	case jobTypeUploadOriginal:
 		return svc.newUploadOriginalFlow(jobID, jobState)
+	case jobTypeTranscode:
+		return svc.newTranscodeFlow(jobID, jobState)
+	case jobTypeSplit:
+		return svc.newSplitFlow(jobID, jobState)
+	case jobTypeClip:
+		return svc.newClipFlow(jobID, jobState)
+	case jobTypeExtractAudio:
+		return svc.newExtractAudioFlow(jobID, jobState)
 	default:
 		return nil, oops.With("jobType", jobState.Type).Wrapf(errUnsupportedJobType, "unsupported job type: %s", jobState.Type)
 	}
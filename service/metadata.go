@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math"
+	"path"
 	"time"
 
 	"github.com/samber/oops"
@@ -26,18 +28,58 @@ type Metadata struct {
 type VariantMetadata struct {
 	ID       string `json:"id"`
 	LenBytes *int64 `json:"length_bytes,omitempty"`
+	// ETag identifies the exact version of the variant, when the source provides one (e.g. S3)
+	ETag string `json:"etag,omitempty"`
+	// Title is a human-readable name of the variant, when the source provides one (e.g. podcast episode title).
+	// It is used for chapter titles instead of the file name.
+	Title    string        `json:"title,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+	// Codec is the codec of the first audio stream, known once the variant is probed
+	Codec string `json:"codec,omitempty"`
+	// BitRate is in bits per second, known once the variant is probed
+	BitRate int64 `json:"bit_rate,omitempty"`
+	// CueTrack is set for virtual variants, which are tracks of a single-file rip described by a CUE sheet
+	CueTrack *CueTrack `json:"cue_track,omitempty"`
 }
 
-func (svc *Service) GetMetadata(ctx context.Context, url string) (*Metadata, error) {
+// CueTrack is a track of a single-file rip, cut out of the file when the variant is downloaded
+type CueTrack struct {
+	// Source is the variant ID of the file that holds the track
+	Source    string `json:"source"`
+	Number    int    `json:"number"`
+	Total     int    `json:"total"`
+	Performer string `json:"performer,omitempty"`
+	// Album, AlbumPerformer, Genre and Date describe the whole sheet
+	Album          string        `json:"album,omitempty"`
+	AlbumPerformer string        `json:"album_performer,omitempty"`
+	Genre          string        `json:"genre,omitempty"`
+	Date           string        `json:"date,omitempty"`
+	Start          time.Duration `json:"start"`
+	// End is zero for the last track, which lasts till the end of the file
+	End time.Duration `json:"end,omitempty"`
+}
+
+type MetadataRequest struct {
+	URL string `json:"url"`
+	// Downloader forces a specific downloader, instead of the first one that accepts the URL
+	Downloader string `json:"downloader,omitempty"`
+	// Probe fills in durations, codecs and bitrates of media variants, reading only parts of them
+	Probe bool `json:"probe,omitempty"`
+}
+
+func (svc *Service) GetMetadata(ctx context.Context, req MetadataRequest) (*Metadata, error) {
 	var metadata *Metadata
 	var err error
 	done := make(chan struct{})
 
-	svc.execSynced(url, func() {
+	svc.execSynced(req.URL, func() {
 		ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Minute)
 		defer cancel()
 
-		metadata, err = svc.doGetMetadata(ctx, url)
+		metadata, err = svc.doGetMetadata(ctx, req)
+		if err == nil && req.Probe {
+			svc.probeVariants(ctx, req, metadata)
+		}
 		close(done)
 	})
 
@@ -49,18 +91,29 @@ func (svc *Service) GetMetadata(ctx context.Context, url string) (*Metadata, err
 	}
 }
 
-func (svc *Service) doGetMetadata(ctx context.Context, url string) (*Metadata, error) {
+func (svc *Service) doGetMetadata(ctx context.Context, req MetadataRequest) (*Metadata, error) {
+	url := req.URL
 	tracer := otel.Tracer("github.com/dir01/mediary/service")
 	ctx, span := tracer.Start(ctx, "service.GetMetadata",
-		trace.WithAttributes(attribute.String("url", url)),
+		trace.WithAttributes(
+			attribute.String("url", url),
+			attribute.String("downloader", req.Downloader),
+		),
 	)
 	defer span.End()
 
-	logAttrs := []any{slog.String("url", url)}
-	errCtx := oops.With("url", url)
+	logAttrs := []any{slog.String("url", url), slog.String("downloader", req.Downloader)}
+	errCtx := oops.With("url", url, "downloader", req.Downloader)
 	svc.log.Debug("getting metadata", logAttrs...)
 
-	if metadata, err := svc.storage.GetMetadata(ctx, url); err != nil {
+	downloader, err := svc.selectDownloader(req.Downloader)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, errCtx.Wrapf(err, "failed to select downloader")
+	}
+
+	if metadata, err := svc.storedMetadata(ctx, url, req.Downloader); err != nil {
 		svc.log.Error(
 			"error getting metadata from storage, will continue",
 			append([]any{slog.Any("error", err)}, logAttrs...)...,
@@ -71,7 +124,7 @@ func (svc *Service) doGetMetadata(ctx context.Context, url string) (*Metadata, e
 		return metadata, nil
 	}
 
-	if !svc.downloader.AcceptsURL(url) {
+	if !downloader.AcceptsURL(url) {
 		err := errCtx.Wrapf(ErrUrlNotSupported, "failed to get metadata")
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -79,7 +132,7 @@ func (svc *Service) doGetMetadata(ctx context.Context, url string) (*Metadata, e
 	}
 
 	svc.log.Debug("fetching metadata from downloader", slog.String("url", url))
-	metadata, err := svc.downloader.GetMetadata(ctx, url)
+	metadata, err := downloader.GetMetadata(ctx, url)
 	if err != nil {
 		svc.log.Error("error getting metadata from downloader", append([]any{slog.Any("error", err)}, logAttrs...)...)
 		span.RecordError(err)
@@ -92,7 +145,9 @@ func (svc *Service) doGetMetadata(ctx context.Context, url string) (*Metadata, e
 		attribute.Int("metadata.variant_count", len(metadata.Variants)),
 	)
 
-	if err := svc.storage.SaveMetadata(ctx, metadata); err != nil {
+	svc.addCueTracks(ctx, downloader, url, metadata)
+
+	if err := svc.storage.SaveMetadata(ctx, req.Downloader, metadata); err != nil {
 		svc.log.Error(
 			"error saving metadata to storage, will continue",
 			append([]any{slog.Any("error", err)}, logAttrs...)...,
@@ -101,3 +156,62 @@ func (svc *Service) doGetMetadata(ctx context.Context, url string) (*Metadata, e
 
 	return metadata, nil
 }
+
+// storedMetadata returns metadata of the URL fetched with the named downloader, "" standing for the default one.
+// Metadata fetched with the default downloader does for the named one, as long as that is where it came from.
+func (svc *Service) storedMetadata(ctx context.Context, url string, downloader string) (*Metadata, error) {
+	metadata, err := svc.storage.GetMetadata(ctx, url, downloader)
+	if err != nil || metadata != nil || downloader == "" {
+		return metadata, err
+	}
+	metadata, err = svc.storage.GetMetadata(ctx, url, "")
+	if err != nil || metadata == nil || metadata.DownloaderName != downloader {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// variantTitles returns human-readable titles of variants, for those variants the downloader had titles for.
+// Metadata is only looked up in storage: if it is not there, there are no titles.
+func (svc *Service) variantTitles(ctx context.Context, url string, downloader string) map[string]string {
+	titles := make(map[string]string)
+	metadata, err := svc.storedMetadata(ctx, url, downloader)
+	if err != nil {
+		svc.log.Warn("failed to get metadata for variant titles", slog.String("url", url), slog.Any("error", err))
+		return titles
+	}
+	if metadata == nil {
+		return titles
+	}
+	for _, v := range metadata.Variants {
+		if v.Title != "" {
+			titles[v.ID] = v.Title
+		}
+	}
+	return titles
+}
+
+// findVariant returns the stored variant of the URL that matches with the lowest rank,
+// or an empty string if none does. Variants in the same directory as the near one win over the rest.
+func (svc *Service) findVariant(ctx context.Context, url string, downloader string, near string, match func(id string) (rank int, ok bool)) string {
+	metadata, err := svc.storedMetadata(ctx, url, downloader)
+	if err != nil || metadata == nil {
+		return ""
+	}
+	dir := path.Dir(near)
+
+	best, bestRank, found := "", 0, false
+	for _, v := range metadata.Variants {
+		rank, ok := match(v.ID)
+		if !ok {
+			continue
+		}
+		if path.Dir(v.ID) != dir {
+			rank += math.MaxInt32
+		}
+		if !found || rank < bestRank {
+			best, bestRank, found = v.ID, rank, true
+		}
+	}
+	return best
+}
@@ -1,13 +1,17 @@
 package service_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"os"
 	"reflect"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/dir01/mediary/service"
 	"github.com/dir01/mediary/service/mocks"
@@ -93,7 +97,7 @@ func TestGetMetadata(t *testing.T) {
 			defer svc.Stop()
 
 			storage.
-				GetMetadataMock.Optional().Set(func(ctx context.Context, u string) (r *service.Metadata, err error) {
+				GetMetadataMock.Optional().Set(func(ctx context.Context, u string, _ string) (r *service.Metadata, err error) {
 				if u != url {
 					t.Fatalf("expected url %s, got %s", url, u)
 				}
@@ -117,7 +121,7 @@ func TestGetMetadata(t *testing.T) {
 
 			storage.SaveMetadataMock.Optional().Return(tc.StorageSaveResponse)
 
-			result, err := svc.GetMetadata(context.TODO(), url)
+			result, err := svc.GetMetadata(context.TODO(), service.MetadataRequest{URL: url})
 
 			if tc.ExpectedError != nil {
 				if err == nil || err.Error() != tc.ExpectedError.Error() {
@@ -133,3 +137,248 @@ func TestGetMetadata(t *testing.T) {
 		})
 	}
 }
+
+// selectingDownloader is a composite downloader stub that supports selection by name
+type selectingDownloader struct {
+	*mocks.DownloaderMock
+	byName map[string]service.Downloader
+}
+
+func (s selectingDownloader) Downloaders() []service.DownloaderInfo {
+	return nil
+}
+
+func (s selectingDownloader) Select(name string) (service.Downloader, error) {
+	if d, ok := s.byName[name]; ok {
+		return d, nil
+	}
+	return nil, service.ErrUnknownDownloader
+}
+
+func TestGetMetadata_ForcedDownloader(t *testing.T) {
+	url := "https://example.com/episode.mp3"
+	mc := minimock.NewController(t)
+
+	direct := mocks.NewDownloaderMock(mc)
+	direct.AcceptsURLMock.Return(true)
+	direct.GetMetadataMock.Return(&service.Metadata{URL: url, DownloaderName: "direct"}, nil)
+	dwn := selectingDownloader{
+		DownloaderMock: mocks.NewDownloaderMock(mc),
+		byName:         map[string]service.Downloader{"direct": direct},
+	}
+
+	storage := mocks.NewStorageMock(mc)
+	// metadata cached by the default downloader must not be served when another one is requested
+	storage.GetMetadataMock.Set(func(_ context.Context, _ string, downloader string) (*service.Metadata, error) {
+		if downloader == "" {
+			return &service.Metadata{URL: url, DownloaderName: "ytdl"}, nil
+		}
+		return nil, nil
+	})
+	storage.SaveMetadataMock.Set(func(_ context.Context, downloader string, _ *service.Metadata) error {
+		if downloader != "direct" {
+			t.Errorf("metadata of the forced downloader is saved for %q", downloader)
+		}
+		return nil
+	})
+
+	svc := service.NewService(dwn, storage, nil, nil, nil, logger)
+
+	metadata, err := svc.GetMetadata(context.Background(), service.MetadataRequest{URL: url, Downloader: "direct"})
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if metadata.DownloaderName != "direct" {
+		t.Errorf("metadata came from %q", metadata.DownloaderName)
+	}
+
+	_, err = svc.GetMetadata(context.Background(), service.MetadataRequest{URL: url, Downloader: "nope"})
+	if !errors.Is(err, service.ErrUnknownDownloader) {
+		t.Errorf("expected ErrUnknownDownloader, got %v", err)
+	}
+}
+
+func TestGetMetadata_ForcedDownloaderKeepsDefaultMetadata(t *testing.T) {
+	url := "https://example.com/feed.xml"
+	mc := minimock.NewController(t)
+
+	podcast := mocks.NewDownloaderMock(mc)
+	podcast.AcceptsURLMock.Return(true)
+	podcast.GetMetadataMock.Return(&service.Metadata{URL: url, DownloaderName: "podcast",
+		Variants: []service.VariantMetadata{{ID: "episode.mp3"}}}, nil)
+	dwn := selectingDownloader{
+		DownloaderMock: mocks.NewDownloaderMock(mc),
+		byName:         map[string]service.Downloader{"podcast": podcast},
+	}
+	dwn.AcceptsURLMock.Return(true)
+	dwn.GetMetadataMock.Return(&service.Metadata{URL: url, DownloaderName: "direct",
+		Variants: []service.VariantMetadata{{ID: "feed.xml"}}}, nil)
+
+	stored := make(map[string]service.Metadata)
+	storage := mocks.NewStorageMock(mc)
+	storage.GetMetadataMock.Set(func(_ context.Context, url string, downloader string) (*service.Metadata, error) {
+		if metadata, ok := stored[downloader+" "+url]; ok {
+			return &metadata, nil
+		}
+		return nil, nil
+	})
+	storage.SaveMetadataMock.Set(func(_ context.Context, downloader string, metadata *service.Metadata) error {
+		stored[downloader+" "+metadata.URL] = *metadata
+		return nil
+	})
+
+	svc := service.NewService(dwn, storage, nil, nil, nil, logger)
+
+	forced, err := svc.GetMetadata(context.Background(), service.MetadataRequest{URL: url, Downloader: "podcast"})
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if forced.Variants[0].ID != "episode.mp3" {
+		t.Errorf("forced downloader returned %+v", forced.Variants)
+	}
+
+	metadata, err := svc.GetMetadata(context.Background(), service.MetadataRequest{URL: url})
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if metadata.DownloaderName != "direct" || metadata.Variants[0].ID != "feed.xml" {
+		t.Errorf("default downloader returned metadata of %q: %+v", metadata.DownloaderName, metadata.Variants)
+	}
+
+	// both are served from storage from now on
+	if _, err := svc.GetMetadata(context.Background(), service.MetadataRequest{URL: url, Downloader: "podcast"}); err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if podcast.GetMetadataAfterCounter() != 1 || dwn.GetMetadataAfterCounter() != 1 {
+		t.Errorf("metadata is fetched %d times by the forced downloader and %d by the default one",
+			podcast.GetMetadataAfterCounter(), dwn.GetMetadataAfterCounter())
+	}
+}
+
+// streamingDownloader streams variants from memory
+type streamingDownloader struct {
+	*mocks.DownloaderMock
+	content map[string][]byte
+}
+
+func (d streamingDownloader) Stream(_ context.Context, _ string, variant string) (*service.VariantStream, error) {
+	content, ok := d.content[variant]
+	if !ok {
+		return nil, service.ErrVariantNotFound
+	}
+	return &service.VariantStream{
+		ReadSeekCloser: nopCloser{bytes.NewReader(content)},
+		Name:           variant,
+		Size:           int64(len(content)),
+	}, nil
+}
+
+type nopCloser struct{ io.ReadSeeker }
+
+func (nopCloser) Close() error { return nil }
+
+func TestGetMetadata_Probe(t *testing.T) {
+	url := "magnet:?xt=urn:btih:deadbeef"
+	mc := minimock.NewController(t)
+
+	// the index of the book is at the end, so the head alone is not enough
+	book := bytes.Repeat([]byte{'h'}, 20<<20)
+	copy(book[len(book)-1024:], bytes.Repeat([]byte{'t'}, 1024))
+	dwn := streamingDownloader{
+		DownloaderMock: mocks.NewDownloaderMock(mc),
+		content:        map[string][]byte{"book.m4b": book},
+	}
+
+	storage := mocks.NewStorageMock(mc)
+	storage.GetMetadataMock.Return(&service.Metadata{URL: url, Variants: []service.VariantMetadata{
+		{ID: "book.m4b"},
+		{ID: "cover.jpg"},
+		{ID: "intro.mp3", Codec: "mp3", Duration: time.Minute},
+	}}, nil)
+	storage.SaveMetadataMock.Set(func(_ context.Context, _ string, metadata *service.Metadata) error {
+		if v := metadata.Variants[0]; v.Codec != "aac" || v.Duration != time.Hour || v.BitRate != 64000 {
+			t.Errorf("book is not probed: %+v", v)
+		}
+		return nil
+	})
+
+	mp := mocks.NewMediaProcessorMock(mc)
+	mp.GetInfoMock.Set(func(_ context.Context, fp string) (*service.MediaInfo, error) {
+		partial, err := os.ReadFile(fp)
+		if err != nil {
+			t.Fatalf("failed to read partial file: %v", err)
+		}
+		if len(partial) != len(book) || partial[0] != 'h' || partial[len(partial)/2] != 0 {
+			t.Fatalf("partial file should be as large as the book, with a hole in the middle")
+		}
+		if partial[len(partial)-1] != 't' {
+			return nil, errors.New("moov atom not found")
+		}
+		return &service.MediaInfo{
+			Duration: time.Hour,
+			BitRate:  65000,
+			Streams:  []service.StreamInfo{{Type: service.StreamAudio, Codec: "aac", BitRate: 64000}},
+		}, nil
+	})
+
+	svc := service.NewService(dwn, storage, nil, mp, nil, logger)
+
+	metadata, err := svc.GetMetadata(context.Background(), service.MetadataRequest{URL: url, Probe: true})
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if mp.GetInfoAfterCounter() != 2 {
+		t.Errorf("expected the book to be probed twice, head first, got %d", mp.GetInfoAfterCounter())
+	}
+	if v := metadata.Variants[2]; v.Duration != time.Minute {
+		t.Errorf("probed variant was probed again: %+v", v)
+	}
+}
+
+func TestGetMetadata_ProbeIsBounded(t *testing.T) {
+	url := "magnet:?xt=urn:btih:deadbeef"
+	mc := minimock.NewController(t)
+
+	// a hundred variants at most are probed at once, the rest are left to the requests that follow
+	content := make(map[string][]byte)
+	var variants []service.VariantMetadata
+	for i := range 150 {
+		id := fmt.Sprintf("%03d.mp3", i)
+		content[id] = []byte("mp3")
+		variants = append(variants, service.VariantMetadata{ID: id})
+	}
+	dwn := streamingDownloader{DownloaderMock: mocks.NewDownloaderMock(mc), content: content}
+	storage := mocks.NewStorageMock(mc)
+	storage.GetMetadataMock.Return(&service.Metadata{URL: url, Variants: variants}, nil)
+	storage.SaveMetadataMock.Return(nil)
+
+	var running, maxRunning atomic.Int32
+	mp := mocks.NewMediaProcessorMock(mc)
+	mp.GetInfoMock.Set(func(_ context.Context, _ string) (*service.MediaInfo, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			if m := maxRunning.Load(); n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return &service.MediaInfo{Duration: time.Minute, Streams: []service.StreamInfo{{Type: service.StreamAudio, Codec: "mp3"}}}, nil
+	})
+
+	svc := service.NewService(dwn, storage, nil, mp, nil, logger)
+
+	metadata, err := svc.GetMetadata(context.Background(), service.MetadataRequest{URL: url, Probe: true})
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if n := mp.GetInfoAfterCounter(); n != 100 {
+		t.Errorf("%d variants probed, want 100", n)
+	}
+	if metadata.Variants[99].Codec != "mp3" || metadata.Variants[100].Codec != "" {
+		t.Errorf("the first hundred variants are expected to be probed")
+	}
+	if n := maxRunning.Load(); n < 2 || n > 4 {
+		t.Errorf("%d probes ran at once, want 2 to 4", n)
+	}
+}
@@ -24,19 +24,124 @@ type MediaProcessorMock struct {
 	beforeAddChapterTagsCounter uint64
 	AddChapterTagsMock          mMediaProcessorMockAddChapterTags
 
-	funcConcatenate          func(ctx context.Context, filepaths []string, audioCodec string) (resultFilepath string, err error)
+	funcChangeSpeed          func(ctx context.Context, filepath string, opts mm_service.SpeedOptions) (resultFilepath string, err error)
+	funcChangeSpeedOrigin    string
+	inspectFuncChangeSpeed   func(ctx context.Context, filepath string, opts mm_service.SpeedOptions)
+	afterChangeSpeedCounter  uint64
+	beforeChangeSpeedCounter uint64
+	ChangeSpeedMock          mMediaProcessorMockChangeSpeed
+
+	funcClip          func(ctx context.Context, filepath string, opts mm_service.ClipOptions) (filepaths []string, err error)
+	funcClipOrigin    string
+	inspectFuncClip   func(ctx context.Context, filepath string, opts mm_service.ClipOptions)
+	afterClipCounter  uint64
+	beforeClipCounter uint64
+	ClipMock          mMediaProcessorMockClip
+
+	funcComputePeaks          func(ctx context.Context, filepath string, opts mm_service.WaveformOptions) (peaks *mm_service.WaveformPeaks, err error)
+	funcComputePeaksOrigin    string
+	inspectFuncComputePeaks   func(ctx context.Context, filepath string, opts mm_service.WaveformOptions)
+	afterComputePeaksCounter  uint64
+	beforeComputePeaksCounter uint64
+	ComputePeaksMock          mMediaProcessorMockComputePeaks
+
+	funcConcatenate          func(ctx context.Context, filepaths []string, opts mm_service.ConcatenateOptions) (resultFilepath string, err error)
 	funcConcatenateOrigin    string
-	inspectFuncConcatenate   func(ctx context.Context, filepaths []string, audioCodec string)
+	inspectFuncConcatenate   func(ctx context.Context, filepaths []string, opts mm_service.ConcatenateOptions)
 	afterConcatenateCounter  uint64
 	beforeConcatenateCounter uint64
 	ConcatenateMock          mMediaProcessorMockConcatenate
 
+	funcContactSheet          func(ctx context.Context, filepath string, opts mm_service.ContactSheetOptions) (imageFilepath string, err error)
+	funcContactSheetOrigin    string
+	inspectFuncContactSheet   func(ctx context.Context, filepath string, opts mm_service.ContactSheetOptions)
+	afterContactSheetCounter  uint64
+	beforeContactSheetCounter uint64
+	ContactSheetMock          mMediaProcessorMockContactSheet
+
+	funcDetectSilenceChapters          func(ctx context.Context, filepath string, opts mm_service.SilenceChapterOptions) (chapters []mm_service.Chapter, err error)
+	funcDetectSilenceChaptersOrigin    string
+	inspectFuncDetectSilenceChapters   func(ctx context.Context, filepath string, opts mm_service.SilenceChapterOptions)
+	afterDetectSilenceChaptersCounter  uint64
+	beforeDetectSilenceChaptersCounter uint64
+	DetectSilenceChaptersMock          mMediaProcessorMockDetectSilenceChapters
+
+	funcExtractAudio          func(ctx context.Context, filepath string, opts mm_service.ExtractAudioOptions) (resultFilepath string, err error)
+	funcExtractAudioOrigin    string
+	inspectFuncExtractAudio   func(ctx context.Context, filepath string, opts mm_service.ExtractAudioOptions)
+	afterExtractAudioCounter  uint64
+	beforeExtractAudioCounter uint64
+	ExtractAudioMock          mMediaProcessorMockExtractAudio
+
+	funcExtractCoverArt          func(ctx context.Context, filepath string) (coverArtFilepath string, err error)
+	funcExtractCoverArtOrigin    string
+	inspectFuncExtractCoverArt   func(ctx context.Context, filepath string)
+	afterExtractCoverArtCounter  uint64
+	beforeExtractCoverArtCounter uint64
+	ExtractCoverArtMock          mMediaProcessorMockExtractCoverArt
+
 	funcGetInfo          func(ctx context.Context, filepath string) (info *mm_service.MediaInfo, err error)
 	funcGetInfoOrigin    string
 	inspectFuncGetInfo   func(ctx context.Context, filepath string)
 	afterGetInfoCounter  uint64
 	beforeGetInfoCounter uint64
 	GetInfoMock          mMediaProcessorMockGetInfo
+
+	funcNormalize          func(ctx context.Context, filepath string, opts mm_service.NormalizeOptions) (resultFilepath string, stats *mm_service.LoudnessStats, err error)
+	funcNormalizeOrigin    string
+	inspectFuncNormalize   func(ctx context.Context, filepath string, opts mm_service.NormalizeOptions)
+	afterNormalizeCounter  uint64
+	beforeNormalizeCounter uint64
+	NormalizeMock          mMediaProcessorMockNormalize
+
+	funcPosterFrame          func(ctx context.Context, filepath string, opts mm_service.PosterFrameOptions) (imageFilepath string, err error)
+	funcPosterFrameOrigin    string
+	inspectFuncPosterFrame   func(ctx context.Context, filepath string, opts mm_service.PosterFrameOptions)
+	afterPosterFrameCounter  uint64
+	beforePosterFrameCounter uint64
+	PosterFrameMock          mMediaProcessorMockPosterFrame
+
+	funcPreviewClip          func(ctx context.Context, filepath string, opts mm_service.PreviewClipOptions) (resultFilepath string, err error)
+	funcPreviewClipOrigin    string
+	inspectFuncPreviewClip   func(ctx context.Context, filepath string, opts mm_service.PreviewClipOptions)
+	afterPreviewClipCounter  uint64
+	beforePreviewClipCounter uint64
+	PreviewClipMock          mMediaProcessorMockPreviewClip
+
+	funcRenderWaveform          func(ctx context.Context, filepath string, opts mm_service.WaveformImageOptions) (imageFilepath string, err error)
+	funcRenderWaveformOrigin    string
+	inspectFuncRenderWaveform   func(ctx context.Context, filepath string, opts mm_service.WaveformImageOptions)
+	afterRenderWaveformCounter  uint64
+	beforeRenderWaveformCounter uint64
+	RenderWaveformMock          mMediaProcessorMockRenderWaveform
+
+	funcSplit          func(ctx context.Context, filepath string, opts mm_service.SplitOptions) (parts []mm_service.SplitPart, err error)
+	funcSplitOrigin    string
+	inspectFuncSplit   func(ctx context.Context, filepath string, opts mm_service.SplitOptions)
+	afterSplitCounter  uint64
+	beforeSplitCounter uint64
+	SplitMock          mMediaProcessorMockSplit
+
+	funcTranscode          func(ctx context.Context, filepath string, opts mm_service.TranscodeOptions) (resultFilepath string, err error)
+	funcTranscodeOrigin    string
+	inspectFuncTranscode   func(ctx context.Context, filepath string, opts mm_service.TranscodeOptions)
+	afterTranscodeCounter  uint64
+	beforeTranscodeCounter uint64
+	TranscodeMock          mMediaProcessorMockTranscode
+
+	funcTrimSilence          func(ctx context.Context, filepath string, opts mm_service.TrimSilenceOptions) (resultFilepath string, err error)
+	funcTrimSilenceOrigin    string
+	inspectFuncTrimSilence   func(ctx context.Context, filepath string, opts mm_service.TrimSilenceOptions)
+	afterTrimSilenceCounter  uint64
+	beforeTrimSilenceCounter uint64
+	TrimSilenceMock          mMediaProcessorMockTrimSilence
+
+	funcWriteMetadata          func(ctx context.Context, filepath string, metadata mm_service.FileMetadata) (resultFilepath string, err error)
+	funcWriteMetadataOrigin    string
+	inspectFuncWriteMetadata   func(ctx context.Context, filepath string, metadata mm_service.FileMetadata)
+	afterWriteMetadataCounter  uint64
+	beforeWriteMetadataCounter uint64
+	WriteMetadataMock          mMediaProcessorMockWriteMetadata
 }
 
 // NewMediaProcessorMock returns a mock for mm_service.MediaProcessor
@@ -50,12 +155,57 @@ func NewMediaProcessorMock(t minimock.Tester) *MediaProcessorMock {
 	m.AddChapterTagsMock = mMediaProcessorMockAddChapterTags{mock: m}
 	m.AddChapterTagsMock.callArgs = []*MediaProcessorMockAddChapterTagsParams{}
 
+	m.ChangeSpeedMock = mMediaProcessorMockChangeSpeed{mock: m}
+	m.ChangeSpeedMock.callArgs = []*MediaProcessorMockChangeSpeedParams{}
+
+	m.ClipMock = mMediaProcessorMockClip{mock: m}
+	m.ClipMock.callArgs = []*MediaProcessorMockClipParams{}
+
+	m.ComputePeaksMock = mMediaProcessorMockComputePeaks{mock: m}
+	m.ComputePeaksMock.callArgs = []*MediaProcessorMockComputePeaksParams{}
+
 	m.ConcatenateMock = mMediaProcessorMockConcatenate{mock: m}
 	m.ConcatenateMock.callArgs = []*MediaProcessorMockConcatenateParams{}
 
+	m.ContactSheetMock = mMediaProcessorMockContactSheet{mock: m}
+	m.ContactSheetMock.callArgs = []*MediaProcessorMockContactSheetParams{}
+
+	m.DetectSilenceChaptersMock = mMediaProcessorMockDetectSilenceChapters{mock: m}
+	m.DetectSilenceChaptersMock.callArgs = []*MediaProcessorMockDetectSilenceChaptersParams{}
+
+	m.ExtractAudioMock = mMediaProcessorMockExtractAudio{mock: m}
+	m.ExtractAudioMock.callArgs = []*MediaProcessorMockExtractAudioParams{}
+
+	m.ExtractCoverArtMock = mMediaProcessorMockExtractCoverArt{mock: m}
+	m.ExtractCoverArtMock.callArgs = []*MediaProcessorMockExtractCoverArtParams{}
+
 	m.GetInfoMock = mMediaProcessorMockGetInfo{mock: m}
 	m.GetInfoMock.callArgs = []*MediaProcessorMockGetInfoParams{}
 
+	m.NormalizeMock = mMediaProcessorMockNormalize{mock: m}
+	m.NormalizeMock.callArgs = []*MediaProcessorMockNormalizeParams{}
+
+	m.PosterFrameMock = mMediaProcessorMockPosterFrame{mock: m}
+	m.PosterFrameMock.callArgs = []*MediaProcessorMockPosterFrameParams{}
+
+	m.PreviewClipMock = mMediaProcessorMockPreviewClip{mock: m}
+	m.PreviewClipMock.callArgs = []*MediaProcessorMockPreviewClipParams{}
+
+	m.RenderWaveformMock = mMediaProcessorMockRenderWaveform{mock: m}
+	m.RenderWaveformMock.callArgs = []*MediaProcessorMockRenderWaveformParams{}
+
+	m.SplitMock = mMediaProcessorMockSplit{mock: m}
+	m.SplitMock.callArgs = []*MediaProcessorMockSplitParams{}
+
+	m.TranscodeMock = mMediaProcessorMockTranscode{mock: m}
+	m.TranscodeMock.callArgs = []*MediaProcessorMockTranscodeParams{}
+
+	m.TrimSilenceMock = mMediaProcessorMockTrimSilence{mock: m}
+	m.TrimSilenceMock.callArgs = []*MediaProcessorMockTrimSilenceParams{}
+
+	m.WriteMetadataMock = mMediaProcessorMockWriteMetadata{mock: m}
+	m.WriteMetadataMock.callArgs = []*MediaProcessorMockWriteMetadataParams{}
+
 	t.Cleanup(m.MinimockFinish)
 
 	return m
@@ -434,56 +584,56 @@ func (m *MediaProcessorMock) MinimockAddChapterTagsInspect() {
 	}
 }
 
-type mMediaProcessorMockConcatenate struct {
+type mMediaProcessorMockChangeSpeed struct {
 	optional           bool
 	mock               *MediaProcessorMock
-	defaultExpectation *MediaProcessorMockConcatenateExpectation
-	expectations       []*MediaProcessorMockConcatenateExpectation
+	defaultExpectation *MediaProcessorMockChangeSpeedExpectation
+	expectations       []*MediaProcessorMockChangeSpeedExpectation
 
-	callArgs []*MediaProcessorMockConcatenateParams
+	callArgs []*MediaProcessorMockChangeSpeedParams
 	mutex    sync.RWMutex
 
 	expectedInvocations       uint64
 	expectedInvocationsOrigin string
 }
 
-// MediaProcessorMockConcatenateExpectation specifies expectation struct of the MediaProcessor.Concatenate
-type MediaProcessorMockConcatenateExpectation struct {
+// MediaProcessorMockChangeSpeedExpectation specifies expectation struct of the MediaProcessor.ChangeSpeed
+type MediaProcessorMockChangeSpeedExpectation struct {
 	mock               *MediaProcessorMock
-	params             *MediaProcessorMockConcatenateParams
-	paramPtrs          *MediaProcessorMockConcatenateParamPtrs
-	expectationOrigins MediaProcessorMockConcatenateExpectationOrigins
-	results            *MediaProcessorMockConcatenateResults
+	params             *MediaProcessorMockChangeSpeedParams
+	paramPtrs          *MediaProcessorMockChangeSpeedParamPtrs
+	expectationOrigins MediaProcessorMockChangeSpeedExpectationOrigins
+	results            *MediaProcessorMockChangeSpeedResults
 	returnOrigin       string
 	Counter            uint64
 }
 
-// MediaProcessorMockConcatenateParams contains parameters of the MediaProcessor.Concatenate
-type MediaProcessorMockConcatenateParams struct {
-	ctx        context.Context
-	filepaths  []string
-	audioCodec string
+// MediaProcessorMockChangeSpeedParams contains parameters of the MediaProcessor.ChangeSpeed
+type MediaProcessorMockChangeSpeedParams struct {
+	ctx      context.Context
+	filepath string
+	opts     mm_service.SpeedOptions
 }
 
-// MediaProcessorMockConcatenateParamPtrs contains pointers to parameters of the MediaProcessor.Concatenate
-type MediaProcessorMockConcatenateParamPtrs struct {
-	ctx        *context.Context
-	filepaths  *[]string
-	audioCodec *string
+// MediaProcessorMockChangeSpeedParamPtrs contains pointers to parameters of the MediaProcessor.ChangeSpeed
+type MediaProcessorMockChangeSpeedParamPtrs struct {
+	ctx      *context.Context
+	filepath *string
+	opts     *mm_service.SpeedOptions
 }
 
-// MediaProcessorMockConcatenateResults contains results of the MediaProcessor.Concatenate
-type MediaProcessorMockConcatenateResults struct {
+// MediaProcessorMockChangeSpeedResults contains results of the MediaProcessor.ChangeSpeed
+type MediaProcessorMockChangeSpeedResults struct {
 	resultFilepath string
 	err            error
 }
 
-// MediaProcessorMockConcatenateOrigins contains origins of expectations of the MediaProcessor.Concatenate
-type MediaProcessorMockConcatenateExpectationOrigins struct {
-	origin           string
-	originCtx        string
-	originFilepaths  string
-	originAudioCodec string
+// MediaProcessorMockChangeSpeedOrigins contains origins of expectations of the MediaProcessor.ChangeSpeed
+type MediaProcessorMockChangeSpeedExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originFilepath string
+	originOpts     string
 }
 
 // Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
@@ -491,370 +641,373 @@ type MediaProcessorMockConcatenateExpectationOrigins struct {
 // Optional() makes method check to work in '0 or more' mode.
 // It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
 // catch the problems when the expected method call is totally skipped during test run.
-func (mmConcatenate *mMediaProcessorMockConcatenate) Optional() *mMediaProcessorMockConcatenate {
-	mmConcatenate.optional = true
-	return mmConcatenate
+func (mmChangeSpeed *mMediaProcessorMockChangeSpeed) Optional() *mMediaProcessorMockChangeSpeed {
+	mmChangeSpeed.optional = true
+	return mmChangeSpeed
 }
 
-// Expect sets up expected params for MediaProcessor.Concatenate
-func (mmConcatenate *mMediaProcessorMockConcatenate) Expect(ctx context.Context, filepaths []string, audioCodec string) *mMediaProcessorMockConcatenate {
-	if mmConcatenate.mock.funcConcatenate != nil {
-		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Set")
+// Expect sets up expected params for MediaProcessor.ChangeSpeed
+func (mmChangeSpeed *mMediaProcessorMockChangeSpeed) Expect(ctx context.Context, filepath string, opts mm_service.SpeedOptions) *mMediaProcessorMockChangeSpeed {
+	if mmChangeSpeed.mock.funcChangeSpeed != nil {
+		mmChangeSpeed.mock.t.Fatalf("MediaProcessorMock.ChangeSpeed mock is already set by Set")
 	}
 
-	if mmConcatenate.defaultExpectation == nil {
-		mmConcatenate.defaultExpectation = &MediaProcessorMockConcatenateExpectation{}
+	if mmChangeSpeed.defaultExpectation == nil {
+		mmChangeSpeed.defaultExpectation = &MediaProcessorMockChangeSpeedExpectation{}
 	}
 
-	if mmConcatenate.defaultExpectation.paramPtrs != nil {
-		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by ExpectParams functions")
+	if mmChangeSpeed.defaultExpectation.paramPtrs != nil {
+		mmChangeSpeed.mock.t.Fatalf("MediaProcessorMock.ChangeSpeed mock is already set by ExpectParams functions")
 	}
 
-	mmConcatenate.defaultExpectation.params = &MediaProcessorMockConcatenateParams{ctx, filepaths, audioCodec}
-	mmConcatenate.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
-	for _, e := range mmConcatenate.expectations {
-		if minimock.Equal(e.params, mmConcatenate.defaultExpectation.params) {
-			mmConcatenate.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmConcatenate.defaultExpectation.params)
+	mmChangeSpeed.defaultExpectation.params = &MediaProcessorMockChangeSpeedParams{ctx, filepath, opts}
+	mmChangeSpeed.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmChangeSpeed.expectations {
+		if minimock.Equal(e.params, mmChangeSpeed.defaultExpectation.params) {
+			mmChangeSpeed.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmChangeSpeed.defaultExpectation.params)
 		}
 	}
 
-	return mmConcatenate
+	return mmChangeSpeed
 }
 
-// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.Concatenate
-func (mmConcatenate *mMediaProcessorMockConcatenate) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockConcatenate {
-	if mmConcatenate.mock.funcConcatenate != nil {
-		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Set")
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.ChangeSpeed
+func (mmChangeSpeed *mMediaProcessorMockChangeSpeed) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockChangeSpeed {
+	if mmChangeSpeed.mock.funcChangeSpeed != nil {
+		mmChangeSpeed.mock.t.Fatalf("MediaProcessorMock.ChangeSpeed mock is already set by Set")
 	}
 
-	if mmConcatenate.defaultExpectation == nil {
-		mmConcatenate.defaultExpectation = &MediaProcessorMockConcatenateExpectation{}
+	if mmChangeSpeed.defaultExpectation == nil {
+		mmChangeSpeed.defaultExpectation = &MediaProcessorMockChangeSpeedExpectation{}
 	}
 
-	if mmConcatenate.defaultExpectation.params != nil {
-		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Expect")
+	if mmChangeSpeed.defaultExpectation.params != nil {
+		mmChangeSpeed.mock.t.Fatalf("MediaProcessorMock.ChangeSpeed mock is already set by Expect")
 	}
 
-	if mmConcatenate.defaultExpectation.paramPtrs == nil {
-		mmConcatenate.defaultExpectation.paramPtrs = &MediaProcessorMockConcatenateParamPtrs{}
+	if mmChangeSpeed.defaultExpectation.paramPtrs == nil {
+		mmChangeSpeed.defaultExpectation.paramPtrs = &MediaProcessorMockChangeSpeedParamPtrs{}
 	}
-	mmConcatenate.defaultExpectation.paramPtrs.ctx = &ctx
-	mmConcatenate.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+	mmChangeSpeed.defaultExpectation.paramPtrs.ctx = &ctx
+	mmChangeSpeed.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
 
-	return mmConcatenate
+	return mmChangeSpeed
 }
 
-// ExpectFilepathsParam2 sets up expected param filepaths for MediaProcessor.Concatenate
-func (mmConcatenate *mMediaProcessorMockConcatenate) ExpectFilepathsParam2(filepaths []string) *mMediaProcessorMockConcatenate {
-	if mmConcatenate.mock.funcConcatenate != nil {
-		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Set")
+// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.ChangeSpeed
+func (mmChangeSpeed *mMediaProcessorMockChangeSpeed) ExpectFilepathParam2(filepath string) *mMediaProcessorMockChangeSpeed {
+	if mmChangeSpeed.mock.funcChangeSpeed != nil {
+		mmChangeSpeed.mock.t.Fatalf("MediaProcessorMock.ChangeSpeed mock is already set by Set")
 	}
 
-	if mmConcatenate.defaultExpectation == nil {
-		mmConcatenate.defaultExpectation = &MediaProcessorMockConcatenateExpectation{}
+	if mmChangeSpeed.defaultExpectation == nil {
+		mmChangeSpeed.defaultExpectation = &MediaProcessorMockChangeSpeedExpectation{}
 	}
 
-	if mmConcatenate.defaultExpectation.params != nil {
-		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Expect")
+	if mmChangeSpeed.defaultExpectation.params != nil {
+		mmChangeSpeed.mock.t.Fatalf("MediaProcessorMock.ChangeSpeed mock is already set by Expect")
 	}
 
-	if mmConcatenate.defaultExpectation.paramPtrs == nil {
-		mmConcatenate.defaultExpectation.paramPtrs = &MediaProcessorMockConcatenateParamPtrs{}
+	if mmChangeSpeed.defaultExpectation.paramPtrs == nil {
+		mmChangeSpeed.defaultExpectation.paramPtrs = &MediaProcessorMockChangeSpeedParamPtrs{}
 	}
-	mmConcatenate.defaultExpectation.paramPtrs.filepaths = &filepaths
-	mmConcatenate.defaultExpectation.expectationOrigins.originFilepaths = minimock.CallerInfo(1)
+	mmChangeSpeed.defaultExpectation.paramPtrs.filepath = &filepath
+	mmChangeSpeed.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
 
-	return mmConcatenate
+	return mmChangeSpeed
 }
 
-// ExpectAudioCodecParam3 sets up expected param audioCodec for MediaProcessor.Concatenate
-func (mmConcatenate *mMediaProcessorMockConcatenate) ExpectAudioCodecParam3(audioCodec string) *mMediaProcessorMockConcatenate {
-	if mmConcatenate.mock.funcConcatenate != nil {
-		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Set")
+// ExpectOptsParam3 sets up expected param opts for MediaProcessor.ChangeSpeed
+func (mmChangeSpeed *mMediaProcessorMockChangeSpeed) ExpectOptsParam3(opts mm_service.SpeedOptions) *mMediaProcessorMockChangeSpeed {
+	if mmChangeSpeed.mock.funcChangeSpeed != nil {
+		mmChangeSpeed.mock.t.Fatalf("MediaProcessorMock.ChangeSpeed mock is already set by Set")
 	}
 
-	if mmConcatenate.defaultExpectation == nil {
-		mmConcatenate.defaultExpectation = &MediaProcessorMockConcatenateExpectation{}
+	if mmChangeSpeed.defaultExpectation == nil {
+		mmChangeSpeed.defaultExpectation = &MediaProcessorMockChangeSpeedExpectation{}
 	}
 
-	if mmConcatenate.defaultExpectation.params != nil {
-		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Expect")
+	if mmChangeSpeed.defaultExpectation.params != nil {
+		mmChangeSpeed.mock.t.Fatalf("MediaProcessorMock.ChangeSpeed mock is already set by Expect")
 	}
 
-	if mmConcatenate.defaultExpectation.paramPtrs == nil {
-		mmConcatenate.defaultExpectation.paramPtrs = &MediaProcessorMockConcatenateParamPtrs{}
+	if mmChangeSpeed.defaultExpectation.paramPtrs == nil {
+		mmChangeSpeed.defaultExpectation.paramPtrs = &MediaProcessorMockChangeSpeedParamPtrs{}
 	}
-	mmConcatenate.defaultExpectation.paramPtrs.audioCodec = &audioCodec
-	mmConcatenate.defaultExpectation.expectationOrigins.originAudioCodec = minimock.CallerInfo(1)
+	mmChangeSpeed.defaultExpectation.paramPtrs.opts = &opts
+	mmChangeSpeed.defaultExpectation.expectationOrigins.originOpts = minimock.CallerInfo(1)
 
-	return mmConcatenate
+	return mmChangeSpeed
 }
 
-// Inspect accepts an inspector function that has same arguments as the MediaProcessor.Concatenate
-func (mmConcatenate *mMediaProcessorMockConcatenate) Inspect(f func(ctx context.Context, filepaths []string, audioCodec string)) *mMediaProcessorMockConcatenate {
-	if mmConcatenate.mock.inspectFuncConcatenate != nil {
-		mmConcatenate.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.Concatenate")
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.ChangeSpeed
+func (mmChangeSpeed *mMediaProcessorMockChangeSpeed) Inspect(f func(ctx context.Context, filepath string, opts mm_service.SpeedOptions)) *mMediaProcessorMockChangeSpeed {
+	if mmChangeSpeed.mock.inspectFuncChangeSpeed != nil {
+		mmChangeSpeed.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.ChangeSpeed")
 	}
 
-	mmConcatenate.mock.inspectFuncConcatenate = f
+	mmChangeSpeed.mock.inspectFuncChangeSpeed = f
 
-	return mmConcatenate
+	return mmChangeSpeed
 }
 
-// Return sets up results that will be returned by MediaProcessor.Concatenate
-func (mmConcatenate *mMediaProcessorMockConcatenate) Return(resultFilepath string, err error) *MediaProcessorMock {
-	if mmConcatenate.mock.funcConcatenate != nil {
-		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Set")
+// Return sets up results that will be returned by MediaProcessor.ChangeSpeed
+func (mmChangeSpeed *mMediaProcessorMockChangeSpeed) Return(resultFilepath string, err error) *MediaProcessorMock {
+	if mmChangeSpeed.mock.funcChangeSpeed != nil {
+		mmChangeSpeed.mock.t.Fatalf("MediaProcessorMock.ChangeSpeed mock is already set by Set")
 	}
 
-	if mmConcatenate.defaultExpectation == nil {
-		mmConcatenate.defaultExpectation = &MediaProcessorMockConcatenateExpectation{mock: mmConcatenate.mock}
+	if mmChangeSpeed.defaultExpectation == nil {
+		mmChangeSpeed.defaultExpectation = &MediaProcessorMockChangeSpeedExpectation{mock: mmChangeSpeed.mock}
 	}
-	mmConcatenate.defaultExpectation.results = &MediaProcessorMockConcatenateResults{resultFilepath, err}
-	mmConcatenate.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
-	return mmConcatenate.mock
+	mmChangeSpeed.defaultExpectation.results = &MediaProcessorMockChangeSpeedResults{resultFilepath, err}
+	mmChangeSpeed.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmChangeSpeed.mock
 }
 
-// Set uses given function f to mock the MediaProcessor.Concatenate method
-func (mmConcatenate *mMediaProcessorMockConcatenate) Set(f func(ctx context.Context, filepaths []string, audioCodec string) (resultFilepath string, err error)) *MediaProcessorMock {
-	if mmConcatenate.defaultExpectation != nil {
-		mmConcatenate.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.Concatenate method")
+// Set uses given function f to mock the MediaProcessor.ChangeSpeed method
+func (mmChangeSpeed *mMediaProcessorMockChangeSpeed) Set(f func(ctx context.Context, filepath string, opts mm_service.SpeedOptions) (resultFilepath string, err error)) *MediaProcessorMock {
+	if mmChangeSpeed.defaultExpectation != nil {
+		mmChangeSpeed.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.ChangeSpeed method")
 	}
 
-	if len(mmConcatenate.expectations) > 0 {
-		mmConcatenate.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.Concatenate method")
+	if len(mmChangeSpeed.expectations) > 0 {
+		mmChangeSpeed.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.ChangeSpeed method")
 	}
 
-	mmConcatenate.mock.funcConcatenate = f
-	mmConcatenate.mock.funcConcatenateOrigin = minimock.CallerInfo(1)
-	return mmConcatenate.mock
+	mmChangeSpeed.mock.funcChangeSpeed = f
+	mmChangeSpeed.mock.funcChangeSpeedOrigin = minimock.CallerInfo(1)
+	return mmChangeSpeed.mock
 }
 
-// When sets expectation for the MediaProcessor.Concatenate which will trigger the result defined by the following
+// When sets expectation for the MediaProcessor.ChangeSpeed which will trigger the result defined by the following
 // Then helper
-func (mmConcatenate *mMediaProcessorMockConcatenate) When(ctx context.Context, filepaths []string, audioCodec string) *MediaProcessorMockConcatenateExpectation {
-	if mmConcatenate.mock.funcConcatenate != nil {
-		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Set")
+func (mmChangeSpeed *mMediaProcessorMockChangeSpeed) When(ctx context.Context, filepath string, opts mm_service.SpeedOptions) *MediaProcessorMockChangeSpeedExpectation {
+	if mmChangeSpeed.mock.funcChangeSpeed != nil {
+		mmChangeSpeed.mock.t.Fatalf("MediaProcessorMock.ChangeSpeed mock is already set by Set")
 	}
 
-	expectation := &MediaProcessorMockConcatenateExpectation{
-		mock:               mmConcatenate.mock,
-		params:             &MediaProcessorMockConcatenateParams{ctx, filepaths, audioCodec},
-		expectationOrigins: MediaProcessorMockConcatenateExpectationOrigins{origin: minimock.CallerInfo(1)},
+	expectation := &MediaProcessorMockChangeSpeedExpectation{
+		mock:               mmChangeSpeed.mock,
+		params:             &MediaProcessorMockChangeSpeedParams{ctx, filepath, opts},
+		expectationOrigins: MediaProcessorMockChangeSpeedExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
-	mmConcatenate.expectations = append(mmConcatenate.expectations, expectation)
+	mmChangeSpeed.expectations = append(mmChangeSpeed.expectations, expectation)
 	return expectation
 }
 
-// Then sets up MediaProcessor.Concatenate return parameters for the expectation previously defined by the When method
-func (e *MediaProcessorMockConcatenateExpectation) Then(resultFilepath string, err error) *MediaProcessorMock {
-	e.results = &MediaProcessorMockConcatenateResults{resultFilepath, err}
+// Then sets up MediaProcessor.ChangeSpeed return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockChangeSpeedExpectation) Then(resultFilepath string, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockChangeSpeedResults{resultFilepath, err}
 	return e.mock
 }
 
-// Times sets number of times MediaProcessor.Concatenate should be invoked
-func (mmConcatenate *mMediaProcessorMockConcatenate) Times(n uint64) *mMediaProcessorMockConcatenate {
+// Times sets number of times MediaProcessor.ChangeSpeed should be invoked
+func (mmChangeSpeed *mMediaProcessorMockChangeSpeed) Times(n uint64) *mMediaProcessorMockChangeSpeed {
 	if n == 0 {
-		mmConcatenate.mock.t.Fatalf("Times of MediaProcessorMock.Concatenate mock can not be zero")
+		mmChangeSpeed.mock.t.Fatalf("Times of MediaProcessorMock.ChangeSpeed mock can not be zero")
 	}
-	mm_atomic.StoreUint64(&mmConcatenate.expectedInvocations, n)
-	mmConcatenate.expectedInvocationsOrigin = minimock.CallerInfo(1)
-	return mmConcatenate
+	mm_atomic.StoreUint64(&mmChangeSpeed.expectedInvocations, n)
+	mmChangeSpeed.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmChangeSpeed
 }
 
-func (mmConcatenate *mMediaProcessorMockConcatenate) invocationsDone() bool {
-	if len(mmConcatenate.expectations) == 0 && mmConcatenate.defaultExpectation == nil && mmConcatenate.mock.funcConcatenate == nil {
+func (mmChangeSpeed *mMediaProcessorMockChangeSpeed) invocationsDone() bool {
+	if len(mmChangeSpeed.expectations) == 0 && mmChangeSpeed.defaultExpectation == nil && mmChangeSpeed.mock.funcChangeSpeed == nil {
 		return true
 	}
 
-	totalInvocations := mm_atomic.LoadUint64(&mmConcatenate.mock.afterConcatenateCounter)
-	expectedInvocations := mm_atomic.LoadUint64(&mmConcatenate.expectedInvocations)
+	totalInvocations := mm_atomic.LoadUint64(&mmChangeSpeed.mock.afterChangeSpeedCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmChangeSpeed.expectedInvocations)
 
 	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
 }
 
-// Concatenate implements mm_service.MediaProcessor
-func (mmConcatenate *MediaProcessorMock) Concatenate(ctx context.Context, filepaths []string, audioCodec string) (resultFilepath string, err error) {
-	mm_atomic.AddUint64(&mmConcatenate.beforeConcatenateCounter, 1)
-	defer mm_atomic.AddUint64(&mmConcatenate.afterConcatenateCounter, 1)
+// ChangeSpeed implements mm_service.MediaProcessor
+func (mmChangeSpeed *MediaProcessorMock) ChangeSpeed(ctx context.Context, filepath string, opts mm_service.SpeedOptions) (resultFilepath string, err error) {
+	mm_atomic.AddUint64(&mmChangeSpeed.beforeChangeSpeedCounter, 1)
+	defer mm_atomic.AddUint64(&mmChangeSpeed.afterChangeSpeedCounter, 1)
 
-	mmConcatenate.t.Helper()
+	mmChangeSpeed.t.Helper()
 
-	if mmConcatenate.inspectFuncConcatenate != nil {
-		mmConcatenate.inspectFuncConcatenate(ctx, filepaths, audioCodec)
+	if mmChangeSpeed.inspectFuncChangeSpeed != nil {
+		mmChangeSpeed.inspectFuncChangeSpeed(ctx, filepath, opts)
 	}
 
-	mm_params := MediaProcessorMockConcatenateParams{ctx, filepaths, audioCodec}
+	mm_params := MediaProcessorMockChangeSpeedParams{ctx, filepath, opts}
 
 	// Record call args
-	mmConcatenate.ConcatenateMock.mutex.Lock()
-	mmConcatenate.ConcatenateMock.callArgs = append(mmConcatenate.ConcatenateMock.callArgs, &mm_params)
-	mmConcatenate.ConcatenateMock.mutex.Unlock()
+	mmChangeSpeed.ChangeSpeedMock.mutex.Lock()
+	mmChangeSpeed.ChangeSpeedMock.callArgs = append(mmChangeSpeed.ChangeSpeedMock.callArgs, &mm_params)
+	mmChangeSpeed.ChangeSpeedMock.mutex.Unlock()
 
-	for _, e := range mmConcatenate.ConcatenateMock.expectations {
+	for _, e := range mmChangeSpeed.ChangeSpeedMock.expectations {
 		if minimock.Equal(*e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
 			return e.results.resultFilepath, e.results.err
 		}
 	}
 
-	if mmConcatenate.ConcatenateMock.defaultExpectation != nil {
-		mm_atomic.AddUint64(&mmConcatenate.ConcatenateMock.defaultExpectation.Counter, 1)
-		mm_want := mmConcatenate.ConcatenateMock.defaultExpectation.params
-		mm_want_ptrs := mmConcatenate.ConcatenateMock.defaultExpectation.paramPtrs
+	if mmChangeSpeed.ChangeSpeedMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmChangeSpeed.ChangeSpeedMock.defaultExpectation.Counter, 1)
+		mm_want := mmChangeSpeed.ChangeSpeedMock.defaultExpectation.params
+		mm_want_ptrs := mmChangeSpeed.ChangeSpeedMock.defaultExpectation.paramPtrs
 
-		mm_got := MediaProcessorMockConcatenateParams{ctx, filepaths, audioCodec}
+		mm_got := MediaProcessorMockChangeSpeedParams{ctx, filepath, opts}
 
 		if mm_want_ptrs != nil {
 
 			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
-				mmConcatenate.t.Errorf("MediaProcessorMock.Concatenate got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
-					mmConcatenate.ConcatenateMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+				mmChangeSpeed.t.Errorf("MediaProcessorMock.ChangeSpeed got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmChangeSpeed.ChangeSpeedMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
 			}
 
-			if mm_want_ptrs.filepaths != nil && !minimock.Equal(*mm_want_ptrs.filepaths, mm_got.filepaths) {
-				mmConcatenate.t.Errorf("MediaProcessorMock.Concatenate got unexpected parameter filepaths, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
-					mmConcatenate.ConcatenateMock.defaultExpectation.expectationOrigins.originFilepaths, *mm_want_ptrs.filepaths, mm_got.filepaths, minimock.Diff(*mm_want_ptrs.filepaths, mm_got.filepaths))
+			if mm_want_ptrs.filepath != nil && !minimock.Equal(*mm_want_ptrs.filepath, mm_got.filepath) {
+				mmChangeSpeed.t.Errorf("MediaProcessorMock.ChangeSpeed got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmChangeSpeed.ChangeSpeedMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
 			}
 
-			if mm_want_ptrs.audioCodec != nil && !minimock.Equal(*mm_want_ptrs.audioCodec, mm_got.audioCodec) {
-				mmConcatenate.t.Errorf("MediaProcessorMock.Concatenate got unexpected parameter audioCodec, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
-					mmConcatenate.ConcatenateMock.defaultExpectation.expectationOrigins.originAudioCodec, *mm_want_ptrs.audioCodec, mm_got.audioCodec, minimock.Diff(*mm_want_ptrs.audioCodec, mm_got.audioCodec))
+			if mm_want_ptrs.opts != nil && !minimock.Equal(*mm_want_ptrs.opts, mm_got.opts) {
+				mmChangeSpeed.t.Errorf("MediaProcessorMock.ChangeSpeed got unexpected parameter opts, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmChangeSpeed.ChangeSpeedMock.defaultExpectation.expectationOrigins.originOpts, *mm_want_ptrs.opts, mm_got.opts, minimock.Diff(*mm_want_ptrs.opts, mm_got.opts))
 			}
 
 		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
-			mmConcatenate.t.Errorf("MediaProcessorMock.Concatenate got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
-				mmConcatenate.ConcatenateMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+			mmChangeSpeed.t.Errorf("MediaProcessorMock.ChangeSpeed got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmChangeSpeed.ChangeSpeedMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
 		}
 
-		mm_results := mmConcatenate.ConcatenateMock.defaultExpectation.results
+		mm_results := mmChangeSpeed.ChangeSpeedMock.defaultExpectation.results
 		if mm_results == nil {
-			mmConcatenate.t.Fatal("No results are set for the MediaProcessorMock.Concatenate")
+			mmChangeSpeed.t.Fatal("No results are set for the MediaProcessorMock.ChangeSpeed")
 		}
 		return (*mm_results).resultFilepath, (*mm_results).err
 	}
-	if mmConcatenate.funcConcatenate != nil {
-		return mmConcatenate.funcConcatenate(ctx, filepaths, audioCodec)
+	if mmChangeSpeed.funcChangeSpeed != nil {
+		return mmChangeSpeed.funcChangeSpeed(ctx, filepath, opts)
 	}
-	mmConcatenate.t.Fatalf("Unexpected call to MediaProcessorMock.Concatenate. %v %v %v", ctx, filepaths, audioCodec)
+	mmChangeSpeed.t.Fatalf("Unexpected call to MediaProcessorMock.ChangeSpeed. %v %v %v", ctx, filepath, opts)
 	return
 }
 
-// ConcatenateAfterCounter returns a count of finished MediaProcessorMock.Concatenate invocations
-func (mmConcatenate *MediaProcessorMock) ConcatenateAfterCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmConcatenate.afterConcatenateCounter)
+// ChangeSpeedAfterCounter returns a count of finished MediaProcessorMock.ChangeSpeed invocations
+func (mmChangeSpeed *MediaProcessorMock) ChangeSpeedAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmChangeSpeed.afterChangeSpeedCounter)
 }
 
-// ConcatenateBeforeCounter returns a count of MediaProcessorMock.Concatenate invocations
-func (mmConcatenate *MediaProcessorMock) ConcatenateBeforeCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmConcatenate.beforeConcatenateCounter)
+// ChangeSpeedBeforeCounter returns a count of MediaProcessorMock.ChangeSpeed invocations
+func (mmChangeSpeed *MediaProcessorMock) ChangeSpeedBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmChangeSpeed.beforeChangeSpeedCounter)
 }
 
-// Calls returns a list of arguments used in each call to MediaProcessorMock.Concatenate.
+// Calls returns a list of arguments used in each call to MediaProcessorMock.ChangeSpeed.
 // The list is in the same order as the calls were made (i.e. recent calls have a higher index)
-func (mmConcatenate *mMediaProcessorMockConcatenate) Calls() []*MediaProcessorMockConcatenateParams {
-	mmConcatenate.mutex.RLock()
+func (mmChangeSpeed *mMediaProcessorMockChangeSpeed) Calls() []*MediaProcessorMockChangeSpeedParams {
+	mmChangeSpeed.mutex.RLock()
 
-	argCopy := make([]*MediaProcessorMockConcatenateParams, len(mmConcatenate.callArgs))
-	copy(argCopy, mmConcatenate.callArgs)
+	argCopy := make([]*MediaProcessorMockChangeSpeedParams, len(mmChangeSpeed.callArgs))
+	copy(argCopy, mmChangeSpeed.callArgs)
 
-	mmConcatenate.mutex.RUnlock()
+	mmChangeSpeed.mutex.RUnlock()
 
 	return argCopy
 }
 
-// MinimockConcatenateDone returns true if the count of the Concatenate invocations corresponds
+// MinimockChangeSpeedDone returns true if the count of the ChangeSpeed invocations corresponds
 // the number of defined expectations
-func (m *MediaProcessorMock) MinimockConcatenateDone() bool {
-	if m.ConcatenateMock.optional {
+func (m *MediaProcessorMock) MinimockChangeSpeedDone() bool {
+	if m.ChangeSpeedMock.optional {
 		// Optional methods provide '0 or more' call count restriction.
 		return true
 	}
 
-	for _, e := range m.ConcatenateMock.expectations {
+	for _, e := range m.ChangeSpeedMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
 			return false
 		}
 	}
 
-	return m.ConcatenateMock.invocationsDone()
+	return m.ChangeSpeedMock.invocationsDone()
 }
 
-// MinimockConcatenateInspect logs each unmet expectation
-func (m *MediaProcessorMock) MinimockConcatenateInspect() {
-	for _, e := range m.ConcatenateMock.expectations {
+// MinimockChangeSpeedInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockChangeSpeedInspect() {
+	for _, e := range m.ChangeSpeedMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
-			m.t.Errorf("Expected call to MediaProcessorMock.Concatenate at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+			m.t.Errorf("Expected call to MediaProcessorMock.ChangeSpeed at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
 		}
 	}
 
-	afterConcatenateCounter := mm_atomic.LoadUint64(&m.afterConcatenateCounter)
+	afterChangeSpeedCounter := mm_atomic.LoadUint64(&m.afterChangeSpeedCounter)
 	// if default expectation was set then invocations count should be greater than zero
-	if m.ConcatenateMock.defaultExpectation != nil && afterConcatenateCounter < 1 {
-		if m.ConcatenateMock.defaultExpectation.params == nil {
-			m.t.Errorf("Expected call to MediaProcessorMock.Concatenate at\n%s", m.ConcatenateMock.defaultExpectation.returnOrigin)
+	if m.ChangeSpeedMock.defaultExpectation != nil && afterChangeSpeedCounter < 1 {
+		if m.ChangeSpeedMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.ChangeSpeed at\n%s", m.ChangeSpeedMock.defaultExpectation.returnOrigin)
 		} else {
-			m.t.Errorf("Expected call to MediaProcessorMock.Concatenate at\n%s with params: %#v", m.ConcatenateMock.defaultExpectation.expectationOrigins.origin, *m.ConcatenateMock.defaultExpectation.params)
+			m.t.Errorf("Expected call to MediaProcessorMock.ChangeSpeed at\n%s with params: %#v", m.ChangeSpeedMock.defaultExpectation.expectationOrigins.origin, *m.ChangeSpeedMock.defaultExpectation.params)
 		}
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcConcatenate != nil && afterConcatenateCounter < 1 {
-		m.t.Errorf("Expected call to MediaProcessorMock.Concatenate at\n%s", m.funcConcatenateOrigin)
+	if m.funcChangeSpeed != nil && afterChangeSpeedCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.ChangeSpeed at\n%s", m.funcChangeSpeedOrigin)
 	}
 
-	if !m.ConcatenateMock.invocationsDone() && afterConcatenateCounter > 0 {
-		m.t.Errorf("Expected %d calls to MediaProcessorMock.Concatenate at\n%s but found %d calls",
-			mm_atomic.LoadUint64(&m.ConcatenateMock.expectedInvocations), m.ConcatenateMock.expectedInvocationsOrigin, afterConcatenateCounter)
+	if !m.ChangeSpeedMock.invocationsDone() && afterChangeSpeedCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.ChangeSpeed at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.ChangeSpeedMock.expectedInvocations), m.ChangeSpeedMock.expectedInvocationsOrigin, afterChangeSpeedCounter)
 	}
 }
 
-type mMediaProcessorMockGetInfo struct {
+type mMediaProcessorMockClip struct {
 	optional           bool
 	mock               *MediaProcessorMock
-	defaultExpectation *MediaProcessorMockGetInfoExpectation
-	expectations       []*MediaProcessorMockGetInfoExpectation
+	defaultExpectation *MediaProcessorMockClipExpectation
+	expectations       []*MediaProcessorMockClipExpectation
 
-	callArgs []*MediaProcessorMockGetInfoParams
+	callArgs []*MediaProcessorMockClipParams
 	mutex    sync.RWMutex
 
 	expectedInvocations       uint64
 	expectedInvocationsOrigin string
 }
 
-// MediaProcessorMockGetInfoExpectation specifies expectation struct of the MediaProcessor.GetInfo
-type MediaProcessorMockGetInfoExpectation struct {
+// MediaProcessorMockClipExpectation specifies expectation struct of the MediaProcessor.Clip
+type MediaProcessorMockClipExpectation struct {
 	mock               *MediaProcessorMock
-	params             *MediaProcessorMockGetInfoParams
-	paramPtrs          *MediaProcessorMockGetInfoParamPtrs
-	expectationOrigins MediaProcessorMockGetInfoExpectationOrigins
-	results            *MediaProcessorMockGetInfoResults
+	params             *MediaProcessorMockClipParams
+	paramPtrs          *MediaProcessorMockClipParamPtrs
+	expectationOrigins MediaProcessorMockClipExpectationOrigins
+	results            *MediaProcessorMockClipResults
 	returnOrigin       string
 	Counter            uint64
 }
 
-// MediaProcessorMockGetInfoParams contains parameters of the MediaProcessor.GetInfo
-type MediaProcessorMockGetInfoParams struct {
+// MediaProcessorMockClipParams contains parameters of the MediaProcessor.Clip
+type MediaProcessorMockClipParams struct {
 	ctx      context.Context
 	filepath string
+	opts     mm_service.ClipOptions
 }
 
-// MediaProcessorMockGetInfoParamPtrs contains pointers to parameters of the MediaProcessor.GetInfo
-type MediaProcessorMockGetInfoParamPtrs struct {
+// MediaProcessorMockClipParamPtrs contains pointers to parameters of the MediaProcessor.Clip
+type MediaProcessorMockClipParamPtrs struct {
 	ctx      *context.Context
 	filepath *string
+	opts     *mm_service.ClipOptions
 }
 
-// MediaProcessorMockGetInfoResults contains results of the MediaProcessor.GetInfo
-type MediaProcessorMockGetInfoResults struct {
-	info *mm_service.MediaInfo
-	err  error
+// MediaProcessorMockClipResults contains results of the MediaProcessor.Clip
+type MediaProcessorMockClipResults struct {
+	filepaths []string
+	err       error
 }
 
-// MediaProcessorMockGetInfoOrigins contains origins of expectations of the MediaProcessor.GetInfo
-type MediaProcessorMockGetInfoExpectationOrigins struct {
+// MediaProcessorMockClipOrigins contains origins of expectations of the MediaProcessor.Clip
+type MediaProcessorMockClipExpectationOrigins struct {
 	origin         string
 	originCtx      string
 	originFilepath string
+	originOpts     string
 }
 
 // Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
@@ -862,292 +1015,5869 @@ type MediaProcessorMockGetInfoExpectationOrigins struct {
 // Optional() makes method check to work in '0 or more' mode.
 // It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
 // catch the problems when the expected method call is totally skipped during test run.
-func (mmGetInfo *mMediaProcessorMockGetInfo) Optional() *mMediaProcessorMockGetInfo {
-	mmGetInfo.optional = true
-	return mmGetInfo
+func (mmClip *mMediaProcessorMockClip) Optional() *mMediaProcessorMockClip {
+	mmClip.optional = true
+	return mmClip
 }
 
-// Expect sets up expected params for MediaProcessor.GetInfo
-func (mmGetInfo *mMediaProcessorMockGetInfo) Expect(ctx context.Context, filepath string) *mMediaProcessorMockGetInfo {
-	if mmGetInfo.mock.funcGetInfo != nil {
-		mmGetInfo.mock.t.Fatalf("MediaProcessorMock.GetInfo mock is already set by Set")
+// Expect sets up expected params for MediaProcessor.Clip
+func (mmClip *mMediaProcessorMockClip) Expect(ctx context.Context, filepath string, opts mm_service.ClipOptions) *mMediaProcessorMockClip {
+	if mmClip.mock.funcClip != nil {
+		mmClip.mock.t.Fatalf("MediaProcessorMock.Clip mock is already set by Set")
 	}
 
-	if mmGetInfo.defaultExpectation == nil {
-		mmGetInfo.defaultExpectation = &MediaProcessorMockGetInfoExpectation{}
+	if mmClip.defaultExpectation == nil {
+		mmClip.defaultExpectation = &MediaProcessorMockClipExpectation{}
 	}
 
-	if mmGetInfo.defaultExpectation.paramPtrs != nil {
-		mmGetInfo.mock.t.Fatalf("MediaProcessorMock.GetInfo mock is already set by ExpectParams functions")
+	if mmClip.defaultExpectation.paramPtrs != nil {
+		mmClip.mock.t.Fatalf("MediaProcessorMock.Clip mock is already set by ExpectParams functions")
 	}
 
-	mmGetInfo.defaultExpectation.params = &MediaProcessorMockGetInfoParams{ctx, filepath}
-	mmGetInfo.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
-	for _, e := range mmGetInfo.expectations {
-		if minimock.Equal(e.params, mmGetInfo.defaultExpectation.params) {
-			mmGetInfo.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmGetInfo.defaultExpectation.params)
+	mmClip.defaultExpectation.params = &MediaProcessorMockClipParams{ctx, filepath, opts}
+	mmClip.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmClip.expectations {
+		if minimock.Equal(e.params, mmClip.defaultExpectation.params) {
+			mmClip.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmClip.defaultExpectation.params)
 		}
 	}
 
-	return mmGetInfo
+	return mmClip
 }
 
-// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.GetInfo
-func (mmGetInfo *mMediaProcessorMockGetInfo) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockGetInfo {
-	if mmGetInfo.mock.funcGetInfo != nil {
-		mmGetInfo.mock.t.Fatalf("MediaProcessorMock.GetInfo mock is already set by Set")
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.Clip
+func (mmClip *mMediaProcessorMockClip) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockClip {
+	if mmClip.mock.funcClip != nil {
+		mmClip.mock.t.Fatalf("MediaProcessorMock.Clip mock is already set by Set")
 	}
 
-	if mmGetInfo.defaultExpectation == nil {
-		mmGetInfo.defaultExpectation = &MediaProcessorMockGetInfoExpectation{}
+	if mmClip.defaultExpectation == nil {
+		mmClip.defaultExpectation = &MediaProcessorMockClipExpectation{}
 	}
 
-	if mmGetInfo.defaultExpectation.params != nil {
-		mmGetInfo.mock.t.Fatalf("MediaProcessorMock.GetInfo mock is already set by Expect")
+	if mmClip.defaultExpectation.params != nil {
+		mmClip.mock.t.Fatalf("MediaProcessorMock.Clip mock is already set by Expect")
 	}
 
-	if mmGetInfo.defaultExpectation.paramPtrs == nil {
-		mmGetInfo.defaultExpectation.paramPtrs = &MediaProcessorMockGetInfoParamPtrs{}
+	if mmClip.defaultExpectation.paramPtrs == nil {
+		mmClip.defaultExpectation.paramPtrs = &MediaProcessorMockClipParamPtrs{}
 	}
-	mmGetInfo.defaultExpectation.paramPtrs.ctx = &ctx
-	mmGetInfo.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+	mmClip.defaultExpectation.paramPtrs.ctx = &ctx
+	mmClip.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
 
-	return mmGetInfo
+	return mmClip
 }
 
-// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.GetInfo
-func (mmGetInfo *mMediaProcessorMockGetInfo) ExpectFilepathParam2(filepath string) *mMediaProcessorMockGetInfo {
-	if mmGetInfo.mock.funcGetInfo != nil {
-		mmGetInfo.mock.t.Fatalf("MediaProcessorMock.GetInfo mock is already set by Set")
+// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.Clip
+func (mmClip *mMediaProcessorMockClip) ExpectFilepathParam2(filepath string) *mMediaProcessorMockClip {
+	if mmClip.mock.funcClip != nil {
+		mmClip.mock.t.Fatalf("MediaProcessorMock.Clip mock is already set by Set")
 	}
 
-	if mmGetInfo.defaultExpectation == nil {
-		mmGetInfo.defaultExpectation = &MediaProcessorMockGetInfoExpectation{}
+	if mmClip.defaultExpectation == nil {
+		mmClip.defaultExpectation = &MediaProcessorMockClipExpectation{}
 	}
 
-	if mmGetInfo.defaultExpectation.params != nil {
-		mmGetInfo.mock.t.Fatalf("MediaProcessorMock.GetInfo mock is already set by Expect")
+	if mmClip.defaultExpectation.params != nil {
+		mmClip.mock.t.Fatalf("MediaProcessorMock.Clip mock is already set by Expect")
 	}
 
-	if mmGetInfo.defaultExpectation.paramPtrs == nil {
-		mmGetInfo.defaultExpectation.paramPtrs = &MediaProcessorMockGetInfoParamPtrs{}
+	if mmClip.defaultExpectation.paramPtrs == nil {
+		mmClip.defaultExpectation.paramPtrs = &MediaProcessorMockClipParamPtrs{}
 	}
-	mmGetInfo.defaultExpectation.paramPtrs.filepath = &filepath
-	mmGetInfo.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
+	mmClip.defaultExpectation.paramPtrs.filepath = &filepath
+	mmClip.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
 
-	return mmGetInfo
+	return mmClip
 }
 
-// Inspect accepts an inspector function that has same arguments as the MediaProcessor.GetInfo
-func (mmGetInfo *mMediaProcessorMockGetInfo) Inspect(f func(ctx context.Context, filepath string)) *mMediaProcessorMockGetInfo {
-	if mmGetInfo.mock.inspectFuncGetInfo != nil {
-		mmGetInfo.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.GetInfo")
+// ExpectOptsParam3 sets up expected param opts for MediaProcessor.Clip
+func (mmClip *mMediaProcessorMockClip) ExpectOptsParam3(opts mm_service.ClipOptions) *mMediaProcessorMockClip {
+	if mmClip.mock.funcClip != nil {
+		mmClip.mock.t.Fatalf("MediaProcessorMock.Clip mock is already set by Set")
+	}
+
+	if mmClip.defaultExpectation == nil {
+		mmClip.defaultExpectation = &MediaProcessorMockClipExpectation{}
+	}
+
+	if mmClip.defaultExpectation.params != nil {
+		mmClip.mock.t.Fatalf("MediaProcessorMock.Clip mock is already set by Expect")
+	}
+
+	if mmClip.defaultExpectation.paramPtrs == nil {
+		mmClip.defaultExpectation.paramPtrs = &MediaProcessorMockClipParamPtrs{}
+	}
+	mmClip.defaultExpectation.paramPtrs.opts = &opts
+	mmClip.defaultExpectation.expectationOrigins.originOpts = minimock.CallerInfo(1)
+
+	return mmClip
+}
+
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.Clip
+func (mmClip *mMediaProcessorMockClip) Inspect(f func(ctx context.Context, filepath string, opts mm_service.ClipOptions)) *mMediaProcessorMockClip {
+	if mmClip.mock.inspectFuncClip != nil {
+		mmClip.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.Clip")
+	}
+
+	mmClip.mock.inspectFuncClip = f
+
+	return mmClip
+}
+
+// Return sets up results that will be returned by MediaProcessor.Clip
+func (mmClip *mMediaProcessorMockClip) Return(filepaths []string, err error) *MediaProcessorMock {
+	if mmClip.mock.funcClip != nil {
+		mmClip.mock.t.Fatalf("MediaProcessorMock.Clip mock is already set by Set")
+	}
+
+	if mmClip.defaultExpectation == nil {
+		mmClip.defaultExpectation = &MediaProcessorMockClipExpectation{mock: mmClip.mock}
+	}
+	mmClip.defaultExpectation.results = &MediaProcessorMockClipResults{filepaths, err}
+	mmClip.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmClip.mock
+}
+
+// Set uses given function f to mock the MediaProcessor.Clip method
+func (mmClip *mMediaProcessorMockClip) Set(f func(ctx context.Context, filepath string, opts mm_service.ClipOptions) (filepaths []string, err error)) *MediaProcessorMock {
+	if mmClip.defaultExpectation != nil {
+		mmClip.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.Clip method")
+	}
+
+	if len(mmClip.expectations) > 0 {
+		mmClip.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.Clip method")
+	}
+
+	mmClip.mock.funcClip = f
+	mmClip.mock.funcClipOrigin = minimock.CallerInfo(1)
+	return mmClip.mock
+}
+
+// When sets expectation for the MediaProcessor.Clip which will trigger the result defined by the following
+// Then helper
+func (mmClip *mMediaProcessorMockClip) When(ctx context.Context, filepath string, opts mm_service.ClipOptions) *MediaProcessorMockClipExpectation {
+	if mmClip.mock.funcClip != nil {
+		mmClip.mock.t.Fatalf("MediaProcessorMock.Clip mock is already set by Set")
+	}
+
+	expectation := &MediaProcessorMockClipExpectation{
+		mock:               mmClip.mock,
+		params:             &MediaProcessorMockClipParams{ctx, filepath, opts},
+		expectationOrigins: MediaProcessorMockClipExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmClip.expectations = append(mmClip.expectations, expectation)
+	return expectation
+}
+
+// Then sets up MediaProcessor.Clip return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockClipExpectation) Then(filepaths []string, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockClipResults{filepaths, err}
+	return e.mock
+}
+
+// Times sets number of times MediaProcessor.Clip should be invoked
+func (mmClip *mMediaProcessorMockClip) Times(n uint64) *mMediaProcessorMockClip {
+	if n == 0 {
+		mmClip.mock.t.Fatalf("Times of MediaProcessorMock.Clip mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmClip.expectedInvocations, n)
+	mmClip.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmClip
+}
+
+func (mmClip *mMediaProcessorMockClip) invocationsDone() bool {
+	if len(mmClip.expectations) == 0 && mmClip.defaultExpectation == nil && mmClip.mock.funcClip == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmClip.mock.afterClipCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmClip.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// Clip implements mm_service.MediaProcessor
+func (mmClip *MediaProcessorMock) Clip(ctx context.Context, filepath string, opts mm_service.ClipOptions) (filepaths []string, err error) {
+	mm_atomic.AddUint64(&mmClip.beforeClipCounter, 1)
+	defer mm_atomic.AddUint64(&mmClip.afterClipCounter, 1)
+
+	mmClip.t.Helper()
+
+	if mmClip.inspectFuncClip != nil {
+		mmClip.inspectFuncClip(ctx, filepath, opts)
+	}
+
+	mm_params := MediaProcessorMockClipParams{ctx, filepath, opts}
+
+	// Record call args
+	mmClip.ClipMock.mutex.Lock()
+	mmClip.ClipMock.callArgs = append(mmClip.ClipMock.callArgs, &mm_params)
+	mmClip.ClipMock.mutex.Unlock()
+
+	for _, e := range mmClip.ClipMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.filepaths, e.results.err
+		}
+	}
+
+	if mmClip.ClipMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmClip.ClipMock.defaultExpectation.Counter, 1)
+		mm_want := mmClip.ClipMock.defaultExpectation.params
+		mm_want_ptrs := mmClip.ClipMock.defaultExpectation.paramPtrs
+
+		mm_got := MediaProcessorMockClipParams{ctx, filepath, opts}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmClip.t.Errorf("MediaProcessorMock.Clip got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmClip.ClipMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.filepath != nil && !minimock.Equal(*mm_want_ptrs.filepath, mm_got.filepath) {
+				mmClip.t.Errorf("MediaProcessorMock.Clip got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmClip.ClipMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
+			}
+
+			if mm_want_ptrs.opts != nil && !minimock.Equal(*mm_want_ptrs.opts, mm_got.opts) {
+				mmClip.t.Errorf("MediaProcessorMock.Clip got unexpected parameter opts, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmClip.ClipMock.defaultExpectation.expectationOrigins.originOpts, *mm_want_ptrs.opts, mm_got.opts, minimock.Diff(*mm_want_ptrs.opts, mm_got.opts))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmClip.t.Errorf("MediaProcessorMock.Clip got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmClip.ClipMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmClip.ClipMock.defaultExpectation.results
+		if mm_results == nil {
+			mmClip.t.Fatal("No results are set for the MediaProcessorMock.Clip")
+		}
+		return (*mm_results).filepaths, (*mm_results).err
+	}
+	if mmClip.funcClip != nil {
+		return mmClip.funcClip(ctx, filepath, opts)
+	}
+	mmClip.t.Fatalf("Unexpected call to MediaProcessorMock.Clip. %v %v %v", ctx, filepath, opts)
+	return
+}
+
+// ClipAfterCounter returns a count of finished MediaProcessorMock.Clip invocations
+func (mmClip *MediaProcessorMock) ClipAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmClip.afterClipCounter)
+}
+
+// ClipBeforeCounter returns a count of MediaProcessorMock.Clip invocations
+func (mmClip *MediaProcessorMock) ClipBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmClip.beforeClipCounter)
+}
+
+// Calls returns a list of arguments used in each call to MediaProcessorMock.Clip.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmClip *mMediaProcessorMockClip) Calls() []*MediaProcessorMockClipParams {
+	mmClip.mutex.RLock()
+
+	argCopy := make([]*MediaProcessorMockClipParams, len(mmClip.callArgs))
+	copy(argCopy, mmClip.callArgs)
+
+	mmClip.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockClipDone returns true if the count of the Clip invocations corresponds
+// the number of defined expectations
+func (m *MediaProcessorMock) MinimockClipDone() bool {
+	if m.ClipMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.ClipMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.ClipMock.invocationsDone()
+}
+
+// MinimockClipInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockClipInspect() {
+	for _, e := range m.ClipMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to MediaProcessorMock.Clip at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterClipCounter := mm_atomic.LoadUint64(&m.afterClipCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ClipMock.defaultExpectation != nil && afterClipCounter < 1 {
+		if m.ClipMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.Clip at\n%s", m.ClipMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to MediaProcessorMock.Clip at\n%s with params: %#v", m.ClipMock.defaultExpectation.expectationOrigins.origin, *m.ClipMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcClip != nil && afterClipCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.Clip at\n%s", m.funcClipOrigin)
+	}
+
+	if !m.ClipMock.invocationsDone() && afterClipCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.Clip at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.ClipMock.expectedInvocations), m.ClipMock.expectedInvocationsOrigin, afterClipCounter)
+	}
+}
+
+type mMediaProcessorMockComputePeaks struct {
+	optional           bool
+	mock               *MediaProcessorMock
+	defaultExpectation *MediaProcessorMockComputePeaksExpectation
+	expectations       []*MediaProcessorMockComputePeaksExpectation
+
+	callArgs []*MediaProcessorMockComputePeaksParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// MediaProcessorMockComputePeaksExpectation specifies expectation struct of the MediaProcessor.ComputePeaks
+type MediaProcessorMockComputePeaksExpectation struct {
+	mock               *MediaProcessorMock
+	params             *MediaProcessorMockComputePeaksParams
+	paramPtrs          *MediaProcessorMockComputePeaksParamPtrs
+	expectationOrigins MediaProcessorMockComputePeaksExpectationOrigins
+	results            *MediaProcessorMockComputePeaksResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// MediaProcessorMockComputePeaksParams contains parameters of the MediaProcessor.ComputePeaks
+type MediaProcessorMockComputePeaksParams struct {
+	ctx      context.Context
+	filepath string
+	opts     mm_service.WaveformOptions
+}
+
+// MediaProcessorMockComputePeaksParamPtrs contains pointers to parameters of the MediaProcessor.ComputePeaks
+type MediaProcessorMockComputePeaksParamPtrs struct {
+	ctx      *context.Context
+	filepath *string
+	opts     *mm_service.WaveformOptions
+}
+
+// MediaProcessorMockComputePeaksResults contains results of the MediaProcessor.ComputePeaks
+type MediaProcessorMockComputePeaksResults struct {
+	peaks *mm_service.WaveformPeaks
+	err   error
+}
+
+// MediaProcessorMockComputePeaksOrigins contains origins of expectations of the MediaProcessor.ComputePeaks
+type MediaProcessorMockComputePeaksExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originFilepath string
+	originOpts     string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmComputePeaks *mMediaProcessorMockComputePeaks) Optional() *mMediaProcessorMockComputePeaks {
+	mmComputePeaks.optional = true
+	return mmComputePeaks
+}
+
+// Expect sets up expected params for MediaProcessor.ComputePeaks
+func (mmComputePeaks *mMediaProcessorMockComputePeaks) Expect(ctx context.Context, filepath string, opts mm_service.WaveformOptions) *mMediaProcessorMockComputePeaks {
+	if mmComputePeaks.mock.funcComputePeaks != nil {
+		mmComputePeaks.mock.t.Fatalf("MediaProcessorMock.ComputePeaks mock is already set by Set")
+	}
+
+	if mmComputePeaks.defaultExpectation == nil {
+		mmComputePeaks.defaultExpectation = &MediaProcessorMockComputePeaksExpectation{}
+	}
+
+	if mmComputePeaks.defaultExpectation.paramPtrs != nil {
+		mmComputePeaks.mock.t.Fatalf("MediaProcessorMock.ComputePeaks mock is already set by ExpectParams functions")
+	}
+
+	mmComputePeaks.defaultExpectation.params = &MediaProcessorMockComputePeaksParams{ctx, filepath, opts}
+	mmComputePeaks.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmComputePeaks.expectations {
+		if minimock.Equal(e.params, mmComputePeaks.defaultExpectation.params) {
+			mmComputePeaks.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmComputePeaks.defaultExpectation.params)
+		}
+	}
+
+	return mmComputePeaks
+}
+
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.ComputePeaks
+func (mmComputePeaks *mMediaProcessorMockComputePeaks) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockComputePeaks {
+	if mmComputePeaks.mock.funcComputePeaks != nil {
+		mmComputePeaks.mock.t.Fatalf("MediaProcessorMock.ComputePeaks mock is already set by Set")
+	}
+
+	if mmComputePeaks.defaultExpectation == nil {
+		mmComputePeaks.defaultExpectation = &MediaProcessorMockComputePeaksExpectation{}
+	}
+
+	if mmComputePeaks.defaultExpectation.params != nil {
+		mmComputePeaks.mock.t.Fatalf("MediaProcessorMock.ComputePeaks mock is already set by Expect")
+	}
+
+	if mmComputePeaks.defaultExpectation.paramPtrs == nil {
+		mmComputePeaks.defaultExpectation.paramPtrs = &MediaProcessorMockComputePeaksParamPtrs{}
+	}
+	mmComputePeaks.defaultExpectation.paramPtrs.ctx = &ctx
+	mmComputePeaks.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmComputePeaks
+}
+
+// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.ComputePeaks
+func (mmComputePeaks *mMediaProcessorMockComputePeaks) ExpectFilepathParam2(filepath string) *mMediaProcessorMockComputePeaks {
+	if mmComputePeaks.mock.funcComputePeaks != nil {
+		mmComputePeaks.mock.t.Fatalf("MediaProcessorMock.ComputePeaks mock is already set by Set")
+	}
+
+	if mmComputePeaks.defaultExpectation == nil {
+		mmComputePeaks.defaultExpectation = &MediaProcessorMockComputePeaksExpectation{}
+	}
+
+	if mmComputePeaks.defaultExpectation.params != nil {
+		mmComputePeaks.mock.t.Fatalf("MediaProcessorMock.ComputePeaks mock is already set by Expect")
+	}
+
+	if mmComputePeaks.defaultExpectation.paramPtrs == nil {
+		mmComputePeaks.defaultExpectation.paramPtrs = &MediaProcessorMockComputePeaksParamPtrs{}
+	}
+	mmComputePeaks.defaultExpectation.paramPtrs.filepath = &filepath
+	mmComputePeaks.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
+
+	return mmComputePeaks
+}
+
+// ExpectOptsParam3 sets up expected param opts for MediaProcessor.ComputePeaks
+func (mmComputePeaks *mMediaProcessorMockComputePeaks) ExpectOptsParam3(opts mm_service.WaveformOptions) *mMediaProcessorMockComputePeaks {
+	if mmComputePeaks.mock.funcComputePeaks != nil {
+		mmComputePeaks.mock.t.Fatalf("MediaProcessorMock.ComputePeaks mock is already set by Set")
+	}
+
+	if mmComputePeaks.defaultExpectation == nil {
+		mmComputePeaks.defaultExpectation = &MediaProcessorMockComputePeaksExpectation{}
+	}
+
+	if mmComputePeaks.defaultExpectation.params != nil {
+		mmComputePeaks.mock.t.Fatalf("MediaProcessorMock.ComputePeaks mock is already set by Expect")
+	}
+
+	if mmComputePeaks.defaultExpectation.paramPtrs == nil {
+		mmComputePeaks.defaultExpectation.paramPtrs = &MediaProcessorMockComputePeaksParamPtrs{}
+	}
+	mmComputePeaks.defaultExpectation.paramPtrs.opts = &opts
+	mmComputePeaks.defaultExpectation.expectationOrigins.originOpts = minimock.CallerInfo(1)
+
+	return mmComputePeaks
+}
+
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.ComputePeaks
+func (mmComputePeaks *mMediaProcessorMockComputePeaks) Inspect(f func(ctx context.Context, filepath string, opts mm_service.WaveformOptions)) *mMediaProcessorMockComputePeaks {
+	if mmComputePeaks.mock.inspectFuncComputePeaks != nil {
+		mmComputePeaks.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.ComputePeaks")
+	}
+
+	mmComputePeaks.mock.inspectFuncComputePeaks = f
+
+	return mmComputePeaks
+}
+
+// Return sets up results that will be returned by MediaProcessor.ComputePeaks
+func (mmComputePeaks *mMediaProcessorMockComputePeaks) Return(peaks *mm_service.WaveformPeaks, err error) *MediaProcessorMock {
+	if mmComputePeaks.mock.funcComputePeaks != nil {
+		mmComputePeaks.mock.t.Fatalf("MediaProcessorMock.ComputePeaks mock is already set by Set")
+	}
+
+	if mmComputePeaks.defaultExpectation == nil {
+		mmComputePeaks.defaultExpectation = &MediaProcessorMockComputePeaksExpectation{mock: mmComputePeaks.mock}
+	}
+	mmComputePeaks.defaultExpectation.results = &MediaProcessorMockComputePeaksResults{peaks, err}
+	mmComputePeaks.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmComputePeaks.mock
+}
+
+// Set uses given function f to mock the MediaProcessor.ComputePeaks method
+func (mmComputePeaks *mMediaProcessorMockComputePeaks) Set(f func(ctx context.Context, filepath string, opts mm_service.WaveformOptions) (peaks *mm_service.WaveformPeaks, err error)) *MediaProcessorMock {
+	if mmComputePeaks.defaultExpectation != nil {
+		mmComputePeaks.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.ComputePeaks method")
+	}
+
+	if len(mmComputePeaks.expectations) > 0 {
+		mmComputePeaks.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.ComputePeaks method")
+	}
+
+	mmComputePeaks.mock.funcComputePeaks = f
+	mmComputePeaks.mock.funcComputePeaksOrigin = minimock.CallerInfo(1)
+	return mmComputePeaks.mock
+}
+
+// When sets expectation for the MediaProcessor.ComputePeaks which will trigger the result defined by the following
+// Then helper
+func (mmComputePeaks *mMediaProcessorMockComputePeaks) When(ctx context.Context, filepath string, opts mm_service.WaveformOptions) *MediaProcessorMockComputePeaksExpectation {
+	if mmComputePeaks.mock.funcComputePeaks != nil {
+		mmComputePeaks.mock.t.Fatalf("MediaProcessorMock.ComputePeaks mock is already set by Set")
+	}
+
+	expectation := &MediaProcessorMockComputePeaksExpectation{
+		mock:               mmComputePeaks.mock,
+		params:             &MediaProcessorMockComputePeaksParams{ctx, filepath, opts},
+		expectationOrigins: MediaProcessorMockComputePeaksExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmComputePeaks.expectations = append(mmComputePeaks.expectations, expectation)
+	return expectation
+}
+
+// Then sets up MediaProcessor.ComputePeaks return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockComputePeaksExpectation) Then(peaks *mm_service.WaveformPeaks, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockComputePeaksResults{peaks, err}
+	return e.mock
+}
+
+// Times sets number of times MediaProcessor.ComputePeaks should be invoked
+func (mmComputePeaks *mMediaProcessorMockComputePeaks) Times(n uint64) *mMediaProcessorMockComputePeaks {
+	if n == 0 {
+		mmComputePeaks.mock.t.Fatalf("Times of MediaProcessorMock.ComputePeaks mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmComputePeaks.expectedInvocations, n)
+	mmComputePeaks.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmComputePeaks
+}
+
+func (mmComputePeaks *mMediaProcessorMockComputePeaks) invocationsDone() bool {
+	if len(mmComputePeaks.expectations) == 0 && mmComputePeaks.defaultExpectation == nil && mmComputePeaks.mock.funcComputePeaks == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmComputePeaks.mock.afterComputePeaksCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmComputePeaks.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// ComputePeaks implements mm_service.MediaProcessor
+func (mmComputePeaks *MediaProcessorMock) ComputePeaks(ctx context.Context, filepath string, opts mm_service.WaveformOptions) (peaks *mm_service.WaveformPeaks, err error) {
+	mm_atomic.AddUint64(&mmComputePeaks.beforeComputePeaksCounter, 1)
+	defer mm_atomic.AddUint64(&mmComputePeaks.afterComputePeaksCounter, 1)
+
+	mmComputePeaks.t.Helper()
+
+	if mmComputePeaks.inspectFuncComputePeaks != nil {
+		mmComputePeaks.inspectFuncComputePeaks(ctx, filepath, opts)
+	}
+
+	mm_params := MediaProcessorMockComputePeaksParams{ctx, filepath, opts}
+
+	// Record call args
+	mmComputePeaks.ComputePeaksMock.mutex.Lock()
+	mmComputePeaks.ComputePeaksMock.callArgs = append(mmComputePeaks.ComputePeaksMock.callArgs, &mm_params)
+	mmComputePeaks.ComputePeaksMock.mutex.Unlock()
+
+	for _, e := range mmComputePeaks.ComputePeaksMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.peaks, e.results.err
+		}
+	}
+
+	if mmComputePeaks.ComputePeaksMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmComputePeaks.ComputePeaksMock.defaultExpectation.Counter, 1)
+		mm_want := mmComputePeaks.ComputePeaksMock.defaultExpectation.params
+		mm_want_ptrs := mmComputePeaks.ComputePeaksMock.defaultExpectation.paramPtrs
+
+		mm_got := MediaProcessorMockComputePeaksParams{ctx, filepath, opts}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmComputePeaks.t.Errorf("MediaProcessorMock.ComputePeaks got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmComputePeaks.ComputePeaksMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.filepath != nil && !minimock.Equal(*mm_want_ptrs.filepath, mm_got.filepath) {
+				mmComputePeaks.t.Errorf("MediaProcessorMock.ComputePeaks got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmComputePeaks.ComputePeaksMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
+			}
+
+			if mm_want_ptrs.opts != nil && !minimock.Equal(*mm_want_ptrs.opts, mm_got.opts) {
+				mmComputePeaks.t.Errorf("MediaProcessorMock.ComputePeaks got unexpected parameter opts, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmComputePeaks.ComputePeaksMock.defaultExpectation.expectationOrigins.originOpts, *mm_want_ptrs.opts, mm_got.opts, minimock.Diff(*mm_want_ptrs.opts, mm_got.opts))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmComputePeaks.t.Errorf("MediaProcessorMock.ComputePeaks got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmComputePeaks.ComputePeaksMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmComputePeaks.ComputePeaksMock.defaultExpectation.results
+		if mm_results == nil {
+			mmComputePeaks.t.Fatal("No results are set for the MediaProcessorMock.ComputePeaks")
+		}
+		return (*mm_results).peaks, (*mm_results).err
+	}
+	if mmComputePeaks.funcComputePeaks != nil {
+		return mmComputePeaks.funcComputePeaks(ctx, filepath, opts)
+	}
+	mmComputePeaks.t.Fatalf("Unexpected call to MediaProcessorMock.ComputePeaks. %v %v %v", ctx, filepath, opts)
+	return
+}
+
+// ComputePeaksAfterCounter returns a count of finished MediaProcessorMock.ComputePeaks invocations
+func (mmComputePeaks *MediaProcessorMock) ComputePeaksAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmComputePeaks.afterComputePeaksCounter)
+}
+
+// ComputePeaksBeforeCounter returns a count of MediaProcessorMock.ComputePeaks invocations
+func (mmComputePeaks *MediaProcessorMock) ComputePeaksBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmComputePeaks.beforeComputePeaksCounter)
+}
+
+// Calls returns a list of arguments used in each call to MediaProcessorMock.ComputePeaks.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmComputePeaks *mMediaProcessorMockComputePeaks) Calls() []*MediaProcessorMockComputePeaksParams {
+	mmComputePeaks.mutex.RLock()
+
+	argCopy := make([]*MediaProcessorMockComputePeaksParams, len(mmComputePeaks.callArgs))
+	copy(argCopy, mmComputePeaks.callArgs)
+
+	mmComputePeaks.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockComputePeaksDone returns true if the count of the ComputePeaks invocations corresponds
+// the number of defined expectations
+func (m *MediaProcessorMock) MinimockComputePeaksDone() bool {
+	if m.ComputePeaksMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.ComputePeaksMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.ComputePeaksMock.invocationsDone()
+}
+
+// MinimockComputePeaksInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockComputePeaksInspect() {
+	for _, e := range m.ComputePeaksMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to MediaProcessorMock.ComputePeaks at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterComputePeaksCounter := mm_atomic.LoadUint64(&m.afterComputePeaksCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ComputePeaksMock.defaultExpectation != nil && afterComputePeaksCounter < 1 {
+		if m.ComputePeaksMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.ComputePeaks at\n%s", m.ComputePeaksMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to MediaProcessorMock.ComputePeaks at\n%s with params: %#v", m.ComputePeaksMock.defaultExpectation.expectationOrigins.origin, *m.ComputePeaksMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcComputePeaks != nil && afterComputePeaksCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.ComputePeaks at\n%s", m.funcComputePeaksOrigin)
+	}
+
+	if !m.ComputePeaksMock.invocationsDone() && afterComputePeaksCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.ComputePeaks at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.ComputePeaksMock.expectedInvocations), m.ComputePeaksMock.expectedInvocationsOrigin, afterComputePeaksCounter)
+	}
+}
+
+type mMediaProcessorMockConcatenate struct {
+	optional           bool
+	mock               *MediaProcessorMock
+	defaultExpectation *MediaProcessorMockConcatenateExpectation
+	expectations       []*MediaProcessorMockConcatenateExpectation
+
+	callArgs []*MediaProcessorMockConcatenateParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// MediaProcessorMockConcatenateExpectation specifies expectation struct of the MediaProcessor.Concatenate
+type MediaProcessorMockConcatenateExpectation struct {
+	mock               *MediaProcessorMock
+	params             *MediaProcessorMockConcatenateParams
+	paramPtrs          *MediaProcessorMockConcatenateParamPtrs
+	expectationOrigins MediaProcessorMockConcatenateExpectationOrigins
+	results            *MediaProcessorMockConcatenateResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// MediaProcessorMockConcatenateParams contains parameters of the MediaProcessor.Concatenate
+type MediaProcessorMockConcatenateParams struct {
+	ctx       context.Context
+	filepaths []string
+	opts      mm_service.ConcatenateOptions
+}
+
+// MediaProcessorMockConcatenateParamPtrs contains pointers to parameters of the MediaProcessor.Concatenate
+type MediaProcessorMockConcatenateParamPtrs struct {
+	ctx       *context.Context
+	filepaths *[]string
+	opts      *mm_service.ConcatenateOptions
+}
+
+// MediaProcessorMockConcatenateResults contains results of the MediaProcessor.Concatenate
+type MediaProcessorMockConcatenateResults struct {
+	resultFilepath string
+	err            error
+}
+
+// MediaProcessorMockConcatenateOrigins contains origins of expectations of the MediaProcessor.Concatenate
+type MediaProcessorMockConcatenateExpectationOrigins struct {
+	origin          string
+	originCtx       string
+	originFilepaths string
+	originOpts      string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmConcatenate *mMediaProcessorMockConcatenate) Optional() *mMediaProcessorMockConcatenate {
+	mmConcatenate.optional = true
+	return mmConcatenate
+}
+
+// Expect sets up expected params for MediaProcessor.Concatenate
+func (mmConcatenate *mMediaProcessorMockConcatenate) Expect(ctx context.Context, filepaths []string, opts mm_service.ConcatenateOptions) *mMediaProcessorMockConcatenate {
+	if mmConcatenate.mock.funcConcatenate != nil {
+		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Set")
+	}
+
+	if mmConcatenate.defaultExpectation == nil {
+		mmConcatenate.defaultExpectation = &MediaProcessorMockConcatenateExpectation{}
+	}
+
+	if mmConcatenate.defaultExpectation.paramPtrs != nil {
+		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by ExpectParams functions")
+	}
+
+	mmConcatenate.defaultExpectation.params = &MediaProcessorMockConcatenateParams{ctx, filepaths, opts}
+	mmConcatenate.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmConcatenate.expectations {
+		if minimock.Equal(e.params, mmConcatenate.defaultExpectation.params) {
+			mmConcatenate.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmConcatenate.defaultExpectation.params)
+		}
+	}
+
+	return mmConcatenate
+}
+
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.Concatenate
+func (mmConcatenate *mMediaProcessorMockConcatenate) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockConcatenate {
+	if mmConcatenate.mock.funcConcatenate != nil {
+		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Set")
+	}
+
+	if mmConcatenate.defaultExpectation == nil {
+		mmConcatenate.defaultExpectation = &MediaProcessorMockConcatenateExpectation{}
+	}
+
+	if mmConcatenate.defaultExpectation.params != nil {
+		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Expect")
+	}
+
+	if mmConcatenate.defaultExpectation.paramPtrs == nil {
+		mmConcatenate.defaultExpectation.paramPtrs = &MediaProcessorMockConcatenateParamPtrs{}
+	}
+	mmConcatenate.defaultExpectation.paramPtrs.ctx = &ctx
+	mmConcatenate.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmConcatenate
+}
+
+// ExpectFilepathsParam2 sets up expected param filepaths for MediaProcessor.Concatenate
+func (mmConcatenate *mMediaProcessorMockConcatenate) ExpectFilepathsParam2(filepaths []string) *mMediaProcessorMockConcatenate {
+	if mmConcatenate.mock.funcConcatenate != nil {
+		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Set")
+	}
+
+	if mmConcatenate.defaultExpectation == nil {
+		mmConcatenate.defaultExpectation = &MediaProcessorMockConcatenateExpectation{}
+	}
+
+	if mmConcatenate.defaultExpectation.params != nil {
+		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Expect")
+	}
+
+	if mmConcatenate.defaultExpectation.paramPtrs == nil {
+		mmConcatenate.defaultExpectation.paramPtrs = &MediaProcessorMockConcatenateParamPtrs{}
+	}
+	mmConcatenate.defaultExpectation.paramPtrs.filepaths = &filepaths
+	mmConcatenate.defaultExpectation.expectationOrigins.originFilepaths = minimock.CallerInfo(1)
+
+	return mmConcatenate
+}
+
+// ExpectOptsParam3 sets up expected param opts for MediaProcessor.Concatenate
+func (mmConcatenate *mMediaProcessorMockConcatenate) ExpectOptsParam3(opts mm_service.ConcatenateOptions) *mMediaProcessorMockConcatenate {
+	if mmConcatenate.mock.funcConcatenate != nil {
+		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Set")
+	}
+
+	if mmConcatenate.defaultExpectation == nil {
+		mmConcatenate.defaultExpectation = &MediaProcessorMockConcatenateExpectation{}
+	}
+
+	if mmConcatenate.defaultExpectation.params != nil {
+		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Expect")
+	}
+
+	if mmConcatenate.defaultExpectation.paramPtrs == nil {
+		mmConcatenate.defaultExpectation.paramPtrs = &MediaProcessorMockConcatenateParamPtrs{}
+	}
+	mmConcatenate.defaultExpectation.paramPtrs.opts = &opts
+	mmConcatenate.defaultExpectation.expectationOrigins.originOpts = minimock.CallerInfo(1)
+
+	return mmConcatenate
+}
+
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.Concatenate
+func (mmConcatenate *mMediaProcessorMockConcatenate) Inspect(f func(ctx context.Context, filepaths []string, opts mm_service.ConcatenateOptions)) *mMediaProcessorMockConcatenate {
+	if mmConcatenate.mock.inspectFuncConcatenate != nil {
+		mmConcatenate.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.Concatenate")
+	}
+
+	mmConcatenate.mock.inspectFuncConcatenate = f
+
+	return mmConcatenate
+}
+
+// Return sets up results that will be returned by MediaProcessor.Concatenate
+func (mmConcatenate *mMediaProcessorMockConcatenate) Return(resultFilepath string, err error) *MediaProcessorMock {
+	if mmConcatenate.mock.funcConcatenate != nil {
+		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Set")
+	}
+
+	if mmConcatenate.defaultExpectation == nil {
+		mmConcatenate.defaultExpectation = &MediaProcessorMockConcatenateExpectation{mock: mmConcatenate.mock}
+	}
+	mmConcatenate.defaultExpectation.results = &MediaProcessorMockConcatenateResults{resultFilepath, err}
+	mmConcatenate.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmConcatenate.mock
+}
+
+// Set uses given function f to mock the MediaProcessor.Concatenate method
+func (mmConcatenate *mMediaProcessorMockConcatenate) Set(f func(ctx context.Context, filepaths []string, opts mm_service.ConcatenateOptions) (resultFilepath string, err error)) *MediaProcessorMock {
+	if mmConcatenate.defaultExpectation != nil {
+		mmConcatenate.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.Concatenate method")
+	}
+
+	if len(mmConcatenate.expectations) > 0 {
+		mmConcatenate.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.Concatenate method")
+	}
+
+	mmConcatenate.mock.funcConcatenate = f
+	mmConcatenate.mock.funcConcatenateOrigin = minimock.CallerInfo(1)
+	return mmConcatenate.mock
+}
+
+// When sets expectation for the MediaProcessor.Concatenate which will trigger the result defined by the following
+// Then helper
+func (mmConcatenate *mMediaProcessorMockConcatenate) When(ctx context.Context, filepaths []string, opts mm_service.ConcatenateOptions) *MediaProcessorMockConcatenateExpectation {
+	if mmConcatenate.mock.funcConcatenate != nil {
+		mmConcatenate.mock.t.Fatalf("MediaProcessorMock.Concatenate mock is already set by Set")
+	}
+
+	expectation := &MediaProcessorMockConcatenateExpectation{
+		mock:               mmConcatenate.mock,
+		params:             &MediaProcessorMockConcatenateParams{ctx, filepaths, opts},
+		expectationOrigins: MediaProcessorMockConcatenateExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmConcatenate.expectations = append(mmConcatenate.expectations, expectation)
+	return expectation
+}
+
+// Then sets up MediaProcessor.Concatenate return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockConcatenateExpectation) Then(resultFilepath string, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockConcatenateResults{resultFilepath, err}
+	return e.mock
+}
+
+// Times sets number of times MediaProcessor.Concatenate should be invoked
+func (mmConcatenate *mMediaProcessorMockConcatenate) Times(n uint64) *mMediaProcessorMockConcatenate {
+	if n == 0 {
+		mmConcatenate.mock.t.Fatalf("Times of MediaProcessorMock.Concatenate mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmConcatenate.expectedInvocations, n)
+	mmConcatenate.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmConcatenate
+}
+
+func (mmConcatenate *mMediaProcessorMockConcatenate) invocationsDone() bool {
+	if len(mmConcatenate.expectations) == 0 && mmConcatenate.defaultExpectation == nil && mmConcatenate.mock.funcConcatenate == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmConcatenate.mock.afterConcatenateCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmConcatenate.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// Concatenate implements mm_service.MediaProcessor
+func (mmConcatenate *MediaProcessorMock) Concatenate(ctx context.Context, filepaths []string, opts mm_service.ConcatenateOptions) (resultFilepath string, err error) {
+	mm_atomic.AddUint64(&mmConcatenate.beforeConcatenateCounter, 1)
+	defer mm_atomic.AddUint64(&mmConcatenate.afterConcatenateCounter, 1)
+
+	mmConcatenate.t.Helper()
+
+	if mmConcatenate.inspectFuncConcatenate != nil {
+		mmConcatenate.inspectFuncConcatenate(ctx, filepaths, opts)
+	}
+
+	mm_params := MediaProcessorMockConcatenateParams{ctx, filepaths, opts}
+
+	// Record call args
+	mmConcatenate.ConcatenateMock.mutex.Lock()
+	mmConcatenate.ConcatenateMock.callArgs = append(mmConcatenate.ConcatenateMock.callArgs, &mm_params)
+	mmConcatenate.ConcatenateMock.mutex.Unlock()
+
+	for _, e := range mmConcatenate.ConcatenateMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.resultFilepath, e.results.err
+		}
+	}
+
+	if mmConcatenate.ConcatenateMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmConcatenate.ConcatenateMock.defaultExpectation.Counter, 1)
+		mm_want := mmConcatenate.ConcatenateMock.defaultExpectation.params
+		mm_want_ptrs := mmConcatenate.ConcatenateMock.defaultExpectation.paramPtrs
+
+		mm_got := MediaProcessorMockConcatenateParams{ctx, filepaths, opts}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmConcatenate.t.Errorf("MediaProcessorMock.Concatenate got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmConcatenate.ConcatenateMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.filepaths != nil && !minimock.Equal(*mm_want_ptrs.filepaths, mm_got.filepaths) {
+				mmConcatenate.t.Errorf("MediaProcessorMock.Concatenate got unexpected parameter filepaths, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmConcatenate.ConcatenateMock.defaultExpectation.expectationOrigins.originFilepaths, *mm_want_ptrs.filepaths, mm_got.filepaths, minimock.Diff(*mm_want_ptrs.filepaths, mm_got.filepaths))
+			}
+
+			if mm_want_ptrs.opts != nil && !minimock.Equal(*mm_want_ptrs.opts, mm_got.opts) {
+				mmConcatenate.t.Errorf("MediaProcessorMock.Concatenate got unexpected parameter opts, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmConcatenate.ConcatenateMock.defaultExpectation.expectationOrigins.originOpts, *mm_want_ptrs.opts, mm_got.opts, minimock.Diff(*mm_want_ptrs.opts, mm_got.opts))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmConcatenate.t.Errorf("MediaProcessorMock.Concatenate got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmConcatenate.ConcatenateMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmConcatenate.ConcatenateMock.defaultExpectation.results
+		if mm_results == nil {
+			mmConcatenate.t.Fatal("No results are set for the MediaProcessorMock.Concatenate")
+		}
+		return (*mm_results).resultFilepath, (*mm_results).err
+	}
+	if mmConcatenate.funcConcatenate != nil {
+		return mmConcatenate.funcConcatenate(ctx, filepaths, opts)
+	}
+	mmConcatenate.t.Fatalf("Unexpected call to MediaProcessorMock.Concatenate. %v %v %v", ctx, filepaths, opts)
+	return
+}
+
+// ConcatenateAfterCounter returns a count of finished MediaProcessorMock.Concatenate invocations
+func (mmConcatenate *MediaProcessorMock) ConcatenateAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmConcatenate.afterConcatenateCounter)
+}
+
+// ConcatenateBeforeCounter returns a count of MediaProcessorMock.Concatenate invocations
+func (mmConcatenate *MediaProcessorMock) ConcatenateBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmConcatenate.beforeConcatenateCounter)
+}
+
+// Calls returns a list of arguments used in each call to MediaProcessorMock.Concatenate.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmConcatenate *mMediaProcessorMockConcatenate) Calls() []*MediaProcessorMockConcatenateParams {
+	mmConcatenate.mutex.RLock()
+
+	argCopy := make([]*MediaProcessorMockConcatenateParams, len(mmConcatenate.callArgs))
+	copy(argCopy, mmConcatenate.callArgs)
+
+	mmConcatenate.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockConcatenateDone returns true if the count of the Concatenate invocations corresponds
+// the number of defined expectations
+func (m *MediaProcessorMock) MinimockConcatenateDone() bool {
+	if m.ConcatenateMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.ConcatenateMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.ConcatenateMock.invocationsDone()
+}
+
+// MinimockConcatenateInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockConcatenateInspect() {
+	for _, e := range m.ConcatenateMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to MediaProcessorMock.Concatenate at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterConcatenateCounter := mm_atomic.LoadUint64(&m.afterConcatenateCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ConcatenateMock.defaultExpectation != nil && afterConcatenateCounter < 1 {
+		if m.ConcatenateMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.Concatenate at\n%s", m.ConcatenateMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to MediaProcessorMock.Concatenate at\n%s with params: %#v", m.ConcatenateMock.defaultExpectation.expectationOrigins.origin, *m.ConcatenateMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcConcatenate != nil && afterConcatenateCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.Concatenate at\n%s", m.funcConcatenateOrigin)
+	}
+
+	if !m.ConcatenateMock.invocationsDone() && afterConcatenateCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.Concatenate at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.ConcatenateMock.expectedInvocations), m.ConcatenateMock.expectedInvocationsOrigin, afterConcatenateCounter)
+	}
+}
+
+type mMediaProcessorMockContactSheet struct {
+	optional           bool
+	mock               *MediaProcessorMock
+	defaultExpectation *MediaProcessorMockContactSheetExpectation
+	expectations       []*MediaProcessorMockContactSheetExpectation
+
+	callArgs []*MediaProcessorMockContactSheetParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// MediaProcessorMockContactSheetExpectation specifies expectation struct of the MediaProcessor.ContactSheet
+type MediaProcessorMockContactSheetExpectation struct {
+	mock               *MediaProcessorMock
+	params             *MediaProcessorMockContactSheetParams
+	paramPtrs          *MediaProcessorMockContactSheetParamPtrs
+	expectationOrigins MediaProcessorMockContactSheetExpectationOrigins
+	results            *MediaProcessorMockContactSheetResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// MediaProcessorMockContactSheetParams contains parameters of the MediaProcessor.ContactSheet
+type MediaProcessorMockContactSheetParams struct {
+	ctx      context.Context
+	filepath string
+	opts     mm_service.ContactSheetOptions
+}
+
+// MediaProcessorMockContactSheetParamPtrs contains pointers to parameters of the MediaProcessor.ContactSheet
+type MediaProcessorMockContactSheetParamPtrs struct {
+	ctx      *context.Context
+	filepath *string
+	opts     *mm_service.ContactSheetOptions
+}
+
+// MediaProcessorMockContactSheetResults contains results of the MediaProcessor.ContactSheet
+type MediaProcessorMockContactSheetResults struct {
+	imageFilepath string
+	err           error
+}
+
+// MediaProcessorMockContactSheetOrigins contains origins of expectations of the MediaProcessor.ContactSheet
+type MediaProcessorMockContactSheetExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originFilepath string
+	originOpts     string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmContactSheet *mMediaProcessorMockContactSheet) Optional() *mMediaProcessorMockContactSheet {
+	mmContactSheet.optional = true
+	return mmContactSheet
+}
+
+// Expect sets up expected params for MediaProcessor.ContactSheet
+func (mmContactSheet *mMediaProcessorMockContactSheet) Expect(ctx context.Context, filepath string, opts mm_service.ContactSheetOptions) *mMediaProcessorMockContactSheet {
+	if mmContactSheet.mock.funcContactSheet != nil {
+		mmContactSheet.mock.t.Fatalf("MediaProcessorMock.ContactSheet mock is already set by Set")
+	}
+
+	if mmContactSheet.defaultExpectation == nil {
+		mmContactSheet.defaultExpectation = &MediaProcessorMockContactSheetExpectation{}
+	}
+
+	if mmContactSheet.defaultExpectation.paramPtrs != nil {
+		mmContactSheet.mock.t.Fatalf("MediaProcessorMock.ContactSheet mock is already set by ExpectParams functions")
+	}
+
+	mmContactSheet.defaultExpectation.params = &MediaProcessorMockContactSheetParams{ctx, filepath, opts}
+	mmContactSheet.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmContactSheet.expectations {
+		if minimock.Equal(e.params, mmContactSheet.defaultExpectation.params) {
+			mmContactSheet.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmContactSheet.defaultExpectation.params)
+		}
+	}
+
+	return mmContactSheet
+}
+
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.ContactSheet
+func (mmContactSheet *mMediaProcessorMockContactSheet) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockContactSheet {
+	if mmContactSheet.mock.funcContactSheet != nil {
+		mmContactSheet.mock.t.Fatalf("MediaProcessorMock.ContactSheet mock is already set by Set")
+	}
+
+	if mmContactSheet.defaultExpectation == nil {
+		mmContactSheet.defaultExpectation = &MediaProcessorMockContactSheetExpectation{}
+	}
+
+	if mmContactSheet.defaultExpectation.params != nil {
+		mmContactSheet.mock.t.Fatalf("MediaProcessorMock.ContactSheet mock is already set by Expect")
+	}
+
+	if mmContactSheet.defaultExpectation.paramPtrs == nil {
+		mmContactSheet.defaultExpectation.paramPtrs = &MediaProcessorMockContactSheetParamPtrs{}
+	}
+	mmContactSheet.defaultExpectation.paramPtrs.ctx = &ctx
+	mmContactSheet.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmContactSheet
+}
+
+// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.ContactSheet
+func (mmContactSheet *mMediaProcessorMockContactSheet) ExpectFilepathParam2(filepath string) *mMediaProcessorMockContactSheet {
+	if mmContactSheet.mock.funcContactSheet != nil {
+		mmContactSheet.mock.t.Fatalf("MediaProcessorMock.ContactSheet mock is already set by Set")
+	}
+
+	if mmContactSheet.defaultExpectation == nil {
+		mmContactSheet.defaultExpectation = &MediaProcessorMockContactSheetExpectation{}
+	}
+
+	if mmContactSheet.defaultExpectation.params != nil {
+		mmContactSheet.mock.t.Fatalf("MediaProcessorMock.ContactSheet mock is already set by Expect")
+	}
+
+	if mmContactSheet.defaultExpectation.paramPtrs == nil {
+		mmContactSheet.defaultExpectation.paramPtrs = &MediaProcessorMockContactSheetParamPtrs{}
+	}
+	mmContactSheet.defaultExpectation.paramPtrs.filepath = &filepath
+	mmContactSheet.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
+
+	return mmContactSheet
+}
+
+// ExpectOptsParam3 sets up expected param opts for MediaProcessor.ContactSheet
+func (mmContactSheet *mMediaProcessorMockContactSheet) ExpectOptsParam3(opts mm_service.ContactSheetOptions) *mMediaProcessorMockContactSheet {
+	if mmContactSheet.mock.funcContactSheet != nil {
+		mmContactSheet.mock.t.Fatalf("MediaProcessorMock.ContactSheet mock is already set by Set")
+	}
+
+	if mmContactSheet.defaultExpectation == nil {
+		mmContactSheet.defaultExpectation = &MediaProcessorMockContactSheetExpectation{}
+	}
+
+	if mmContactSheet.defaultExpectation.params != nil {
+		mmContactSheet.mock.t.Fatalf("MediaProcessorMock.ContactSheet mock is already set by Expect")
+	}
+
+	if mmContactSheet.defaultExpectation.paramPtrs == nil {
+		mmContactSheet.defaultExpectation.paramPtrs = &MediaProcessorMockContactSheetParamPtrs{}
+	}
+	mmContactSheet.defaultExpectation.paramPtrs.opts = &opts
+	mmContactSheet.defaultExpectation.expectationOrigins.originOpts = minimock.CallerInfo(1)
+
+	return mmContactSheet
+}
+
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.ContactSheet
+func (mmContactSheet *mMediaProcessorMockContactSheet) Inspect(f func(ctx context.Context, filepath string, opts mm_service.ContactSheetOptions)) *mMediaProcessorMockContactSheet {
+	if mmContactSheet.mock.inspectFuncContactSheet != nil {
+		mmContactSheet.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.ContactSheet")
+	}
+
+	mmContactSheet.mock.inspectFuncContactSheet = f
+
+	return mmContactSheet
+}
+
+// Return sets up results that will be returned by MediaProcessor.ContactSheet
+func (mmContactSheet *mMediaProcessorMockContactSheet) Return(imageFilepath string, err error) *MediaProcessorMock {
+	if mmContactSheet.mock.funcContactSheet != nil {
+		mmContactSheet.mock.t.Fatalf("MediaProcessorMock.ContactSheet mock is already set by Set")
+	}
+
+	if mmContactSheet.defaultExpectation == nil {
+		mmContactSheet.defaultExpectation = &MediaProcessorMockContactSheetExpectation{mock: mmContactSheet.mock}
+	}
+	mmContactSheet.defaultExpectation.results = &MediaProcessorMockContactSheetResults{imageFilepath, err}
+	mmContactSheet.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmContactSheet.mock
+}
+
+// Set uses given function f to mock the MediaProcessor.ContactSheet method
+func (mmContactSheet *mMediaProcessorMockContactSheet) Set(f func(ctx context.Context, filepath string, opts mm_service.ContactSheetOptions) (imageFilepath string, err error)) *MediaProcessorMock {
+	if mmContactSheet.defaultExpectation != nil {
+		mmContactSheet.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.ContactSheet method")
+	}
+
+	if len(mmContactSheet.expectations) > 0 {
+		mmContactSheet.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.ContactSheet method")
+	}
+
+	mmContactSheet.mock.funcContactSheet = f
+	mmContactSheet.mock.funcContactSheetOrigin = minimock.CallerInfo(1)
+	return mmContactSheet.mock
+}
+
+// When sets expectation for the MediaProcessor.ContactSheet which will trigger the result defined by the following
+// Then helper
+func (mmContactSheet *mMediaProcessorMockContactSheet) When(ctx context.Context, filepath string, opts mm_service.ContactSheetOptions) *MediaProcessorMockContactSheetExpectation {
+	if mmContactSheet.mock.funcContactSheet != nil {
+		mmContactSheet.mock.t.Fatalf("MediaProcessorMock.ContactSheet mock is already set by Set")
+	}
+
+	expectation := &MediaProcessorMockContactSheetExpectation{
+		mock:               mmContactSheet.mock,
+		params:             &MediaProcessorMockContactSheetParams{ctx, filepath, opts},
+		expectationOrigins: MediaProcessorMockContactSheetExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmContactSheet.expectations = append(mmContactSheet.expectations, expectation)
+	return expectation
+}
+
+// Then sets up MediaProcessor.ContactSheet return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockContactSheetExpectation) Then(imageFilepath string, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockContactSheetResults{imageFilepath, err}
+	return e.mock
+}
+
+// Times sets number of times MediaProcessor.ContactSheet should be invoked
+func (mmContactSheet *mMediaProcessorMockContactSheet) Times(n uint64) *mMediaProcessorMockContactSheet {
+	if n == 0 {
+		mmContactSheet.mock.t.Fatalf("Times of MediaProcessorMock.ContactSheet mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmContactSheet.expectedInvocations, n)
+	mmContactSheet.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmContactSheet
+}
+
+func (mmContactSheet *mMediaProcessorMockContactSheet) invocationsDone() bool {
+	if len(mmContactSheet.expectations) == 0 && mmContactSheet.defaultExpectation == nil && mmContactSheet.mock.funcContactSheet == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmContactSheet.mock.afterContactSheetCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmContactSheet.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// ContactSheet implements mm_service.MediaProcessor
+func (mmContactSheet *MediaProcessorMock) ContactSheet(ctx context.Context, filepath string, opts mm_service.ContactSheetOptions) (imageFilepath string, err error) {
+	mm_atomic.AddUint64(&mmContactSheet.beforeContactSheetCounter, 1)
+	defer mm_atomic.AddUint64(&mmContactSheet.afterContactSheetCounter, 1)
+
+	mmContactSheet.t.Helper()
+
+	if mmContactSheet.inspectFuncContactSheet != nil {
+		mmContactSheet.inspectFuncContactSheet(ctx, filepath, opts)
+	}
+
+	mm_params := MediaProcessorMockContactSheetParams{ctx, filepath, opts}
+
+	// Record call args
+	mmContactSheet.ContactSheetMock.mutex.Lock()
+	mmContactSheet.ContactSheetMock.callArgs = append(mmContactSheet.ContactSheetMock.callArgs, &mm_params)
+	mmContactSheet.ContactSheetMock.mutex.Unlock()
+
+	for _, e := range mmContactSheet.ContactSheetMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.imageFilepath, e.results.err
+		}
+	}
+
+	if mmContactSheet.ContactSheetMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmContactSheet.ContactSheetMock.defaultExpectation.Counter, 1)
+		mm_want := mmContactSheet.ContactSheetMock.defaultExpectation.params
+		mm_want_ptrs := mmContactSheet.ContactSheetMock.defaultExpectation.paramPtrs
+
+		mm_got := MediaProcessorMockContactSheetParams{ctx, filepath, opts}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmContactSheet.t.Errorf("MediaProcessorMock.ContactSheet got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmContactSheet.ContactSheetMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.filepath != nil && !minimock.Equal(*mm_want_ptrs.filepath, mm_got.filepath) {
+				mmContactSheet.t.Errorf("MediaProcessorMock.ContactSheet got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmContactSheet.ContactSheetMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
+			}
+
+			if mm_want_ptrs.opts != nil && !minimock.Equal(*mm_want_ptrs.opts, mm_got.opts) {
+				mmContactSheet.t.Errorf("MediaProcessorMock.ContactSheet got unexpected parameter opts, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmContactSheet.ContactSheetMock.defaultExpectation.expectationOrigins.originOpts, *mm_want_ptrs.opts, mm_got.opts, minimock.Diff(*mm_want_ptrs.opts, mm_got.opts))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmContactSheet.t.Errorf("MediaProcessorMock.ContactSheet got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmContactSheet.ContactSheetMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmContactSheet.ContactSheetMock.defaultExpectation.results
+		if mm_results == nil {
+			mmContactSheet.t.Fatal("No results are set for the MediaProcessorMock.ContactSheet")
+		}
+		return (*mm_results).imageFilepath, (*mm_results).err
+	}
+	if mmContactSheet.funcContactSheet != nil {
+		return mmContactSheet.funcContactSheet(ctx, filepath, opts)
+	}
+	mmContactSheet.t.Fatalf("Unexpected call to MediaProcessorMock.ContactSheet. %v %v %v", ctx, filepath, opts)
+	return
+}
+
+// ContactSheetAfterCounter returns a count of finished MediaProcessorMock.ContactSheet invocations
+func (mmContactSheet *MediaProcessorMock) ContactSheetAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmContactSheet.afterContactSheetCounter)
+}
+
+// ContactSheetBeforeCounter returns a count of MediaProcessorMock.ContactSheet invocations
+func (mmContactSheet *MediaProcessorMock) ContactSheetBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmContactSheet.beforeContactSheetCounter)
+}
+
+// Calls returns a list of arguments used in each call to MediaProcessorMock.ContactSheet.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmContactSheet *mMediaProcessorMockContactSheet) Calls() []*MediaProcessorMockContactSheetParams {
+	mmContactSheet.mutex.RLock()
+
+	argCopy := make([]*MediaProcessorMockContactSheetParams, len(mmContactSheet.callArgs))
+	copy(argCopy, mmContactSheet.callArgs)
+
+	mmContactSheet.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockContactSheetDone returns true if the count of the ContactSheet invocations corresponds
+// the number of defined expectations
+func (m *MediaProcessorMock) MinimockContactSheetDone() bool {
+	if m.ContactSheetMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.ContactSheetMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.ContactSheetMock.invocationsDone()
+}
+
+// MinimockContactSheetInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockContactSheetInspect() {
+	for _, e := range m.ContactSheetMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to MediaProcessorMock.ContactSheet at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterContactSheetCounter := mm_atomic.LoadUint64(&m.afterContactSheetCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ContactSheetMock.defaultExpectation != nil && afterContactSheetCounter < 1 {
+		if m.ContactSheetMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.ContactSheet at\n%s", m.ContactSheetMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to MediaProcessorMock.ContactSheet at\n%s with params: %#v", m.ContactSheetMock.defaultExpectation.expectationOrigins.origin, *m.ContactSheetMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcContactSheet != nil && afterContactSheetCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.ContactSheet at\n%s", m.funcContactSheetOrigin)
+	}
+
+	if !m.ContactSheetMock.invocationsDone() && afterContactSheetCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.ContactSheet at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.ContactSheetMock.expectedInvocations), m.ContactSheetMock.expectedInvocationsOrigin, afterContactSheetCounter)
+	}
+}
+
+type mMediaProcessorMockDetectSilenceChapters struct {
+	optional           bool
+	mock               *MediaProcessorMock
+	defaultExpectation *MediaProcessorMockDetectSilenceChaptersExpectation
+	expectations       []*MediaProcessorMockDetectSilenceChaptersExpectation
+
+	callArgs []*MediaProcessorMockDetectSilenceChaptersParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// MediaProcessorMockDetectSilenceChaptersExpectation specifies expectation struct of the MediaProcessor.DetectSilenceChapters
+type MediaProcessorMockDetectSilenceChaptersExpectation struct {
+	mock               *MediaProcessorMock
+	params             *MediaProcessorMockDetectSilenceChaptersParams
+	paramPtrs          *MediaProcessorMockDetectSilenceChaptersParamPtrs
+	expectationOrigins MediaProcessorMockDetectSilenceChaptersExpectationOrigins
+	results            *MediaProcessorMockDetectSilenceChaptersResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// MediaProcessorMockDetectSilenceChaptersParams contains parameters of the MediaProcessor.DetectSilenceChapters
+type MediaProcessorMockDetectSilenceChaptersParams struct {
+	ctx      context.Context
+	filepath string
+	opts     mm_service.SilenceChapterOptions
+}
+
+// MediaProcessorMockDetectSilenceChaptersParamPtrs contains pointers to parameters of the MediaProcessor.DetectSilenceChapters
+type MediaProcessorMockDetectSilenceChaptersParamPtrs struct {
+	ctx      *context.Context
+	filepath *string
+	opts     *mm_service.SilenceChapterOptions
+}
+
+// MediaProcessorMockDetectSilenceChaptersResults contains results of the MediaProcessor.DetectSilenceChapters
+type MediaProcessorMockDetectSilenceChaptersResults struct {
+	chapters []mm_service.Chapter
+	err      error
+}
+
+// MediaProcessorMockDetectSilenceChaptersOrigins contains origins of expectations of the MediaProcessor.DetectSilenceChapters
+type MediaProcessorMockDetectSilenceChaptersExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originFilepath string
+	originOpts     string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmDetectSilenceChapters *mMediaProcessorMockDetectSilenceChapters) Optional() *mMediaProcessorMockDetectSilenceChapters {
+	mmDetectSilenceChapters.optional = true
+	return mmDetectSilenceChapters
+}
+
+// Expect sets up expected params for MediaProcessor.DetectSilenceChapters
+func (mmDetectSilenceChapters *mMediaProcessorMockDetectSilenceChapters) Expect(ctx context.Context, filepath string, opts mm_service.SilenceChapterOptions) *mMediaProcessorMockDetectSilenceChapters {
+	if mmDetectSilenceChapters.mock.funcDetectSilenceChapters != nil {
+		mmDetectSilenceChapters.mock.t.Fatalf("MediaProcessorMock.DetectSilenceChapters mock is already set by Set")
+	}
+
+	if mmDetectSilenceChapters.defaultExpectation == nil {
+		mmDetectSilenceChapters.defaultExpectation = &MediaProcessorMockDetectSilenceChaptersExpectation{}
+	}
+
+	if mmDetectSilenceChapters.defaultExpectation.paramPtrs != nil {
+		mmDetectSilenceChapters.mock.t.Fatalf("MediaProcessorMock.DetectSilenceChapters mock is already set by ExpectParams functions")
+	}
+
+	mmDetectSilenceChapters.defaultExpectation.params = &MediaProcessorMockDetectSilenceChaptersParams{ctx, filepath, opts}
+	mmDetectSilenceChapters.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmDetectSilenceChapters.expectations {
+		if minimock.Equal(e.params, mmDetectSilenceChapters.defaultExpectation.params) {
+			mmDetectSilenceChapters.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmDetectSilenceChapters.defaultExpectation.params)
+		}
+	}
+
+	return mmDetectSilenceChapters
+}
+
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.DetectSilenceChapters
+func (mmDetectSilenceChapters *mMediaProcessorMockDetectSilenceChapters) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockDetectSilenceChapters {
+	if mmDetectSilenceChapters.mock.funcDetectSilenceChapters != nil {
+		mmDetectSilenceChapters.mock.t.Fatalf("MediaProcessorMock.DetectSilenceChapters mock is already set by Set")
+	}
+
+	if mmDetectSilenceChapters.defaultExpectation == nil {
+		mmDetectSilenceChapters.defaultExpectation = &MediaProcessorMockDetectSilenceChaptersExpectation{}
+	}
+
+	if mmDetectSilenceChapters.defaultExpectation.params != nil {
+		mmDetectSilenceChapters.mock.t.Fatalf("MediaProcessorMock.DetectSilenceChapters mock is already set by Expect")
+	}
+
+	if mmDetectSilenceChapters.defaultExpectation.paramPtrs == nil {
+		mmDetectSilenceChapters.defaultExpectation.paramPtrs = &MediaProcessorMockDetectSilenceChaptersParamPtrs{}
+	}
+	mmDetectSilenceChapters.defaultExpectation.paramPtrs.ctx = &ctx
+	mmDetectSilenceChapters.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmDetectSilenceChapters
+}
+
+// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.DetectSilenceChapters
+func (mmDetectSilenceChapters *mMediaProcessorMockDetectSilenceChapters) ExpectFilepathParam2(filepath string) *mMediaProcessorMockDetectSilenceChapters {
+	if mmDetectSilenceChapters.mock.funcDetectSilenceChapters != nil {
+		mmDetectSilenceChapters.mock.t.Fatalf("MediaProcessorMock.DetectSilenceChapters mock is already set by Set")
+	}
+
+	if mmDetectSilenceChapters.defaultExpectation == nil {
+		mmDetectSilenceChapters.defaultExpectation = &MediaProcessorMockDetectSilenceChaptersExpectation{}
+	}
+
+	if mmDetectSilenceChapters.defaultExpectation.params != nil {
+		mmDetectSilenceChapters.mock.t.Fatalf("MediaProcessorMock.DetectSilenceChapters mock is already set by Expect")
+	}
+
+	if mmDetectSilenceChapters.defaultExpectation.paramPtrs == nil {
+		mmDetectSilenceChapters.defaultExpectation.paramPtrs = &MediaProcessorMockDetectSilenceChaptersParamPtrs{}
+	}
+	mmDetectSilenceChapters.defaultExpectation.paramPtrs.filepath = &filepath
+	mmDetectSilenceChapters.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
+
+	return mmDetectSilenceChapters
+}
+
+// ExpectOptsParam3 sets up expected param opts for MediaProcessor.DetectSilenceChapters
+func (mmDetectSilenceChapters *mMediaProcessorMockDetectSilenceChapters) ExpectOptsParam3(opts mm_service.SilenceChapterOptions) *mMediaProcessorMockDetectSilenceChapters {
+	if mmDetectSilenceChapters.mock.funcDetectSilenceChapters != nil {
+		mmDetectSilenceChapters.mock.t.Fatalf("MediaProcessorMock.DetectSilenceChapters mock is already set by Set")
+	}
+
+	if mmDetectSilenceChapters.defaultExpectation == nil {
+		mmDetectSilenceChapters.defaultExpectation = &MediaProcessorMockDetectSilenceChaptersExpectation{}
+	}
+
+	if mmDetectSilenceChapters.defaultExpectation.params != nil {
+		mmDetectSilenceChapters.mock.t.Fatalf("MediaProcessorMock.DetectSilenceChapters mock is already set by Expect")
+	}
+
+	if mmDetectSilenceChapters.defaultExpectation.paramPtrs == nil {
+		mmDetectSilenceChapters.defaultExpectation.paramPtrs = &MediaProcessorMockDetectSilenceChaptersParamPtrs{}
+	}
+	mmDetectSilenceChapters.defaultExpectation.paramPtrs.opts = &opts
+	mmDetectSilenceChapters.defaultExpectation.expectationOrigins.originOpts = minimock.CallerInfo(1)
+
+	return mmDetectSilenceChapters
+}
+
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.DetectSilenceChapters
+func (mmDetectSilenceChapters *mMediaProcessorMockDetectSilenceChapters) Inspect(f func(ctx context.Context, filepath string, opts mm_service.SilenceChapterOptions)) *mMediaProcessorMockDetectSilenceChapters {
+	if mmDetectSilenceChapters.mock.inspectFuncDetectSilenceChapters != nil {
+		mmDetectSilenceChapters.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.DetectSilenceChapters")
+	}
+
+	mmDetectSilenceChapters.mock.inspectFuncDetectSilenceChapters = f
+
+	return mmDetectSilenceChapters
+}
+
+// Return sets up results that will be returned by MediaProcessor.DetectSilenceChapters
+func (mmDetectSilenceChapters *mMediaProcessorMockDetectSilenceChapters) Return(chapters []mm_service.Chapter, err error) *MediaProcessorMock {
+	if mmDetectSilenceChapters.mock.funcDetectSilenceChapters != nil {
+		mmDetectSilenceChapters.mock.t.Fatalf("MediaProcessorMock.DetectSilenceChapters mock is already set by Set")
+	}
+
+	if mmDetectSilenceChapters.defaultExpectation == nil {
+		mmDetectSilenceChapters.defaultExpectation = &MediaProcessorMockDetectSilenceChaptersExpectation{mock: mmDetectSilenceChapters.mock}
+	}
+	mmDetectSilenceChapters.defaultExpectation.results = &MediaProcessorMockDetectSilenceChaptersResults{chapters, err}
+	mmDetectSilenceChapters.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmDetectSilenceChapters.mock
+}
+
+// Set uses given function f to mock the MediaProcessor.DetectSilenceChapters method
+func (mmDetectSilenceChapters *mMediaProcessorMockDetectSilenceChapters) Set(f func(ctx context.Context, filepath string, opts mm_service.SilenceChapterOptions) (chapters []mm_service.Chapter, err error)) *MediaProcessorMock {
+	if mmDetectSilenceChapters.defaultExpectation != nil {
+		mmDetectSilenceChapters.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.DetectSilenceChapters method")
+	}
+
+	if len(mmDetectSilenceChapters.expectations) > 0 {
+		mmDetectSilenceChapters.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.DetectSilenceChapters method")
+	}
+
+	mmDetectSilenceChapters.mock.funcDetectSilenceChapters = f
+	mmDetectSilenceChapters.mock.funcDetectSilenceChaptersOrigin = minimock.CallerInfo(1)
+	return mmDetectSilenceChapters.mock
+}
+
+// When sets expectation for the MediaProcessor.DetectSilenceChapters which will trigger the result defined by the following
+// Then helper
+func (mmDetectSilenceChapters *mMediaProcessorMockDetectSilenceChapters) When(ctx context.Context, filepath string, opts mm_service.SilenceChapterOptions) *MediaProcessorMockDetectSilenceChaptersExpectation {
+	if mmDetectSilenceChapters.mock.funcDetectSilenceChapters != nil {
+		mmDetectSilenceChapters.mock.t.Fatalf("MediaProcessorMock.DetectSilenceChapters mock is already set by Set")
+	}
+
+	expectation := &MediaProcessorMockDetectSilenceChaptersExpectation{
+		mock:               mmDetectSilenceChapters.mock,
+		params:             &MediaProcessorMockDetectSilenceChaptersParams{ctx, filepath, opts},
+		expectationOrigins: MediaProcessorMockDetectSilenceChaptersExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmDetectSilenceChapters.expectations = append(mmDetectSilenceChapters.expectations, expectation)
+	return expectation
+}
+
+// Then sets up MediaProcessor.DetectSilenceChapters return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockDetectSilenceChaptersExpectation) Then(chapters []mm_service.Chapter, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockDetectSilenceChaptersResults{chapters, err}
+	return e.mock
+}
+
+// Times sets number of times MediaProcessor.DetectSilenceChapters should be invoked
+func (mmDetectSilenceChapters *mMediaProcessorMockDetectSilenceChapters) Times(n uint64) *mMediaProcessorMockDetectSilenceChapters {
+	if n == 0 {
+		mmDetectSilenceChapters.mock.t.Fatalf("Times of MediaProcessorMock.DetectSilenceChapters mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmDetectSilenceChapters.expectedInvocations, n)
+	mmDetectSilenceChapters.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmDetectSilenceChapters
+}
+
+func (mmDetectSilenceChapters *mMediaProcessorMockDetectSilenceChapters) invocationsDone() bool {
+	if len(mmDetectSilenceChapters.expectations) == 0 && mmDetectSilenceChapters.defaultExpectation == nil && mmDetectSilenceChapters.mock.funcDetectSilenceChapters == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmDetectSilenceChapters.mock.afterDetectSilenceChaptersCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmDetectSilenceChapters.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// DetectSilenceChapters implements mm_service.MediaProcessor
+func (mmDetectSilenceChapters *MediaProcessorMock) DetectSilenceChapters(ctx context.Context, filepath string, opts mm_service.SilenceChapterOptions) (chapters []mm_service.Chapter, err error) {
+	mm_atomic.AddUint64(&mmDetectSilenceChapters.beforeDetectSilenceChaptersCounter, 1)
+	defer mm_atomic.AddUint64(&mmDetectSilenceChapters.afterDetectSilenceChaptersCounter, 1)
+
+	mmDetectSilenceChapters.t.Helper()
+
+	if mmDetectSilenceChapters.inspectFuncDetectSilenceChapters != nil {
+		mmDetectSilenceChapters.inspectFuncDetectSilenceChapters(ctx, filepath, opts)
+	}
+
+	mm_params := MediaProcessorMockDetectSilenceChaptersParams{ctx, filepath, opts}
+
+	// Record call args
+	mmDetectSilenceChapters.DetectSilenceChaptersMock.mutex.Lock()
+	mmDetectSilenceChapters.DetectSilenceChaptersMock.callArgs = append(mmDetectSilenceChapters.DetectSilenceChaptersMock.callArgs, &mm_params)
+	mmDetectSilenceChapters.DetectSilenceChaptersMock.mutex.Unlock()
+
+	for _, e := range mmDetectSilenceChapters.DetectSilenceChaptersMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.chapters, e.results.err
+		}
+	}
+
+	if mmDetectSilenceChapters.DetectSilenceChaptersMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmDetectSilenceChapters.DetectSilenceChaptersMock.defaultExpectation.Counter, 1)
+		mm_want := mmDetectSilenceChapters.DetectSilenceChaptersMock.defaultExpectation.params
+		mm_want_ptrs := mmDetectSilenceChapters.DetectSilenceChaptersMock.defaultExpectation.paramPtrs
+
+		mm_got := MediaProcessorMockDetectSilenceChaptersParams{ctx, filepath, opts}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmDetectSilenceChapters.t.Errorf("MediaProcessorMock.DetectSilenceChapters got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDetectSilenceChapters.DetectSilenceChaptersMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.filepath != nil && !minimock.Equal(*mm_want_ptrs.filepath, mm_got.filepath) {
+				mmDetectSilenceChapters.t.Errorf("MediaProcessorMock.DetectSilenceChapters got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDetectSilenceChapters.DetectSilenceChaptersMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
+			}
+
+			if mm_want_ptrs.opts != nil && !minimock.Equal(*mm_want_ptrs.opts, mm_got.opts) {
+				mmDetectSilenceChapters.t.Errorf("MediaProcessorMock.DetectSilenceChapters got unexpected parameter opts, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDetectSilenceChapters.DetectSilenceChaptersMock.defaultExpectation.expectationOrigins.originOpts, *mm_want_ptrs.opts, mm_got.opts, minimock.Diff(*mm_want_ptrs.opts, mm_got.opts))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmDetectSilenceChapters.t.Errorf("MediaProcessorMock.DetectSilenceChapters got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmDetectSilenceChapters.DetectSilenceChaptersMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmDetectSilenceChapters.DetectSilenceChaptersMock.defaultExpectation.results
+		if mm_results == nil {
+			mmDetectSilenceChapters.t.Fatal("No results are set for the MediaProcessorMock.DetectSilenceChapters")
+		}
+		return (*mm_results).chapters, (*mm_results).err
+	}
+	if mmDetectSilenceChapters.funcDetectSilenceChapters != nil {
+		return mmDetectSilenceChapters.funcDetectSilenceChapters(ctx, filepath, opts)
+	}
+	mmDetectSilenceChapters.t.Fatalf("Unexpected call to MediaProcessorMock.DetectSilenceChapters. %v %v %v", ctx, filepath, opts)
+	return
+}
+
+// DetectSilenceChaptersAfterCounter returns a count of finished MediaProcessorMock.DetectSilenceChapters invocations
+func (mmDetectSilenceChapters *MediaProcessorMock) DetectSilenceChaptersAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmDetectSilenceChapters.afterDetectSilenceChaptersCounter)
+}
+
+// DetectSilenceChaptersBeforeCounter returns a count of MediaProcessorMock.DetectSilenceChapters invocations
+func (mmDetectSilenceChapters *MediaProcessorMock) DetectSilenceChaptersBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmDetectSilenceChapters.beforeDetectSilenceChaptersCounter)
+}
+
+// Calls returns a list of arguments used in each call to MediaProcessorMock.DetectSilenceChapters.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmDetectSilenceChapters *mMediaProcessorMockDetectSilenceChapters) Calls() []*MediaProcessorMockDetectSilenceChaptersParams {
+	mmDetectSilenceChapters.mutex.RLock()
+
+	argCopy := make([]*MediaProcessorMockDetectSilenceChaptersParams, len(mmDetectSilenceChapters.callArgs))
+	copy(argCopy, mmDetectSilenceChapters.callArgs)
+
+	mmDetectSilenceChapters.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockDetectSilenceChaptersDone returns true if the count of the DetectSilenceChapters invocations corresponds
+// the number of defined expectations
+func (m *MediaProcessorMock) MinimockDetectSilenceChaptersDone() bool {
+	if m.DetectSilenceChaptersMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.DetectSilenceChaptersMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.DetectSilenceChaptersMock.invocationsDone()
+}
+
+// MinimockDetectSilenceChaptersInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockDetectSilenceChaptersInspect() {
+	for _, e := range m.DetectSilenceChaptersMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to MediaProcessorMock.DetectSilenceChapters at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterDetectSilenceChaptersCounter := mm_atomic.LoadUint64(&m.afterDetectSilenceChaptersCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.DetectSilenceChaptersMock.defaultExpectation != nil && afterDetectSilenceChaptersCounter < 1 {
+		if m.DetectSilenceChaptersMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.DetectSilenceChapters at\n%s", m.DetectSilenceChaptersMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to MediaProcessorMock.DetectSilenceChapters at\n%s with params: %#v", m.DetectSilenceChaptersMock.defaultExpectation.expectationOrigins.origin, *m.DetectSilenceChaptersMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcDetectSilenceChapters != nil && afterDetectSilenceChaptersCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.DetectSilenceChapters at\n%s", m.funcDetectSilenceChaptersOrigin)
+	}
+
+	if !m.DetectSilenceChaptersMock.invocationsDone() && afterDetectSilenceChaptersCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.DetectSilenceChapters at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.DetectSilenceChaptersMock.expectedInvocations), m.DetectSilenceChaptersMock.expectedInvocationsOrigin, afterDetectSilenceChaptersCounter)
+	}
+}
+
+type mMediaProcessorMockExtractAudio struct {
+	optional           bool
+	mock               *MediaProcessorMock
+	defaultExpectation *MediaProcessorMockExtractAudioExpectation
+	expectations       []*MediaProcessorMockExtractAudioExpectation
+
+	callArgs []*MediaProcessorMockExtractAudioParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// MediaProcessorMockExtractAudioExpectation specifies expectation struct of the MediaProcessor.ExtractAudio
+type MediaProcessorMockExtractAudioExpectation struct {
+	mock               *MediaProcessorMock
+	params             *MediaProcessorMockExtractAudioParams
+	paramPtrs          *MediaProcessorMockExtractAudioParamPtrs
+	expectationOrigins MediaProcessorMockExtractAudioExpectationOrigins
+	results            *MediaProcessorMockExtractAudioResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// MediaProcessorMockExtractAudioParams contains parameters of the MediaProcessor.ExtractAudio
+type MediaProcessorMockExtractAudioParams struct {
+	ctx      context.Context
+	filepath string
+	opts     mm_service.ExtractAudioOptions
+}
+
+// MediaProcessorMockExtractAudioParamPtrs contains pointers to parameters of the MediaProcessor.ExtractAudio
+type MediaProcessorMockExtractAudioParamPtrs struct {
+	ctx      *context.Context
+	filepath *string
+	opts     *mm_service.ExtractAudioOptions
+}
+
+// MediaProcessorMockExtractAudioResults contains results of the MediaProcessor.ExtractAudio
+type MediaProcessorMockExtractAudioResults struct {
+	resultFilepath string
+	err            error
+}
+
+// MediaProcessorMockExtractAudioOrigins contains origins of expectations of the MediaProcessor.ExtractAudio
+type MediaProcessorMockExtractAudioExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originFilepath string
+	originOpts     string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmExtractAudio *mMediaProcessorMockExtractAudio) Optional() *mMediaProcessorMockExtractAudio {
+	mmExtractAudio.optional = true
+	return mmExtractAudio
+}
+
+// Expect sets up expected params for MediaProcessor.ExtractAudio
+func (mmExtractAudio *mMediaProcessorMockExtractAudio) Expect(ctx context.Context, filepath string, opts mm_service.ExtractAudioOptions) *mMediaProcessorMockExtractAudio {
+	if mmExtractAudio.mock.funcExtractAudio != nil {
+		mmExtractAudio.mock.t.Fatalf("MediaProcessorMock.ExtractAudio mock is already set by Set")
+	}
+
+	if mmExtractAudio.defaultExpectation == nil {
+		mmExtractAudio.defaultExpectation = &MediaProcessorMockExtractAudioExpectation{}
+	}
+
+	if mmExtractAudio.defaultExpectation.paramPtrs != nil {
+		mmExtractAudio.mock.t.Fatalf("MediaProcessorMock.ExtractAudio mock is already set by ExpectParams functions")
+	}
+
+	mmExtractAudio.defaultExpectation.params = &MediaProcessorMockExtractAudioParams{ctx, filepath, opts}
+	mmExtractAudio.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmExtractAudio.expectations {
+		if minimock.Equal(e.params, mmExtractAudio.defaultExpectation.params) {
+			mmExtractAudio.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmExtractAudio.defaultExpectation.params)
+		}
+	}
+
+	return mmExtractAudio
+}
+
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.ExtractAudio
+func (mmExtractAudio *mMediaProcessorMockExtractAudio) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockExtractAudio {
+	if mmExtractAudio.mock.funcExtractAudio != nil {
+		mmExtractAudio.mock.t.Fatalf("MediaProcessorMock.ExtractAudio mock is already set by Set")
+	}
+
+	if mmExtractAudio.defaultExpectation == nil {
+		mmExtractAudio.defaultExpectation = &MediaProcessorMockExtractAudioExpectation{}
+	}
+
+	if mmExtractAudio.defaultExpectation.params != nil {
+		mmExtractAudio.mock.t.Fatalf("MediaProcessorMock.ExtractAudio mock is already set by Expect")
+	}
+
+	if mmExtractAudio.defaultExpectation.paramPtrs == nil {
+		mmExtractAudio.defaultExpectation.paramPtrs = &MediaProcessorMockExtractAudioParamPtrs{}
+	}
+	mmExtractAudio.defaultExpectation.paramPtrs.ctx = &ctx
+	mmExtractAudio.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmExtractAudio
+}
+
+// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.ExtractAudio
+func (mmExtractAudio *mMediaProcessorMockExtractAudio) ExpectFilepathParam2(filepath string) *mMediaProcessorMockExtractAudio {
+	if mmExtractAudio.mock.funcExtractAudio != nil {
+		mmExtractAudio.mock.t.Fatalf("MediaProcessorMock.ExtractAudio mock is already set by Set")
+	}
+
+	if mmExtractAudio.defaultExpectation == nil {
+		mmExtractAudio.defaultExpectation = &MediaProcessorMockExtractAudioExpectation{}
+	}
+
+	if mmExtractAudio.defaultExpectation.params != nil {
+		mmExtractAudio.mock.t.Fatalf("MediaProcessorMock.ExtractAudio mock is already set by Expect")
+	}
+
+	if mmExtractAudio.defaultExpectation.paramPtrs == nil {
+		mmExtractAudio.defaultExpectation.paramPtrs = &MediaProcessorMockExtractAudioParamPtrs{}
+	}
+	mmExtractAudio.defaultExpectation.paramPtrs.filepath = &filepath
+	mmExtractAudio.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
+
+	return mmExtractAudio
+}
+
+// ExpectOptsParam3 sets up expected param opts for MediaProcessor.ExtractAudio
+func (mmExtractAudio *mMediaProcessorMockExtractAudio) ExpectOptsParam3(opts mm_service.ExtractAudioOptions) *mMediaProcessorMockExtractAudio {
+	if mmExtractAudio.mock.funcExtractAudio != nil {
+		mmExtractAudio.mock.t.Fatalf("MediaProcessorMock.ExtractAudio mock is already set by Set")
+	}
+
+	if mmExtractAudio.defaultExpectation == nil {
+		mmExtractAudio.defaultExpectation = &MediaProcessorMockExtractAudioExpectation{}
+	}
+
+	if mmExtractAudio.defaultExpectation.params != nil {
+		mmExtractAudio.mock.t.Fatalf("MediaProcessorMock.ExtractAudio mock is already set by Expect")
+	}
+
+	if mmExtractAudio.defaultExpectation.paramPtrs == nil {
+		mmExtractAudio.defaultExpectation.paramPtrs = &MediaProcessorMockExtractAudioParamPtrs{}
+	}
+	mmExtractAudio.defaultExpectation.paramPtrs.opts = &opts
+	mmExtractAudio.defaultExpectation.expectationOrigins.originOpts = minimock.CallerInfo(1)
+
+	return mmExtractAudio
+}
+
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.ExtractAudio
+func (mmExtractAudio *mMediaProcessorMockExtractAudio) Inspect(f func(ctx context.Context, filepath string, opts mm_service.ExtractAudioOptions)) *mMediaProcessorMockExtractAudio {
+	if mmExtractAudio.mock.inspectFuncExtractAudio != nil {
+		mmExtractAudio.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.ExtractAudio")
+	}
+
+	mmExtractAudio.mock.inspectFuncExtractAudio = f
+
+	return mmExtractAudio
+}
+
+// Return sets up results that will be returned by MediaProcessor.ExtractAudio
+func (mmExtractAudio *mMediaProcessorMockExtractAudio) Return(resultFilepath string, err error) *MediaProcessorMock {
+	if mmExtractAudio.mock.funcExtractAudio != nil {
+		mmExtractAudio.mock.t.Fatalf("MediaProcessorMock.ExtractAudio mock is already set by Set")
+	}
+
+	if mmExtractAudio.defaultExpectation == nil {
+		mmExtractAudio.defaultExpectation = &MediaProcessorMockExtractAudioExpectation{mock: mmExtractAudio.mock}
+	}
+	mmExtractAudio.defaultExpectation.results = &MediaProcessorMockExtractAudioResults{resultFilepath, err}
+	mmExtractAudio.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmExtractAudio.mock
+}
+
+// Set uses given function f to mock the MediaProcessor.ExtractAudio method
+func (mmExtractAudio *mMediaProcessorMockExtractAudio) Set(f func(ctx context.Context, filepath string, opts mm_service.ExtractAudioOptions) (resultFilepath string, err error)) *MediaProcessorMock {
+	if mmExtractAudio.defaultExpectation != nil {
+		mmExtractAudio.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.ExtractAudio method")
+	}
+
+	if len(mmExtractAudio.expectations) > 0 {
+		mmExtractAudio.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.ExtractAudio method")
+	}
+
+	mmExtractAudio.mock.funcExtractAudio = f
+	mmExtractAudio.mock.funcExtractAudioOrigin = minimock.CallerInfo(1)
+	return mmExtractAudio.mock
+}
+
+// When sets expectation for the MediaProcessor.ExtractAudio which will trigger the result defined by the following
+// Then helper
+func (mmExtractAudio *mMediaProcessorMockExtractAudio) When(ctx context.Context, filepath string, opts mm_service.ExtractAudioOptions) *MediaProcessorMockExtractAudioExpectation {
+	if mmExtractAudio.mock.funcExtractAudio != nil {
+		mmExtractAudio.mock.t.Fatalf("MediaProcessorMock.ExtractAudio mock is already set by Set")
+	}
+
+	expectation := &MediaProcessorMockExtractAudioExpectation{
+		mock:               mmExtractAudio.mock,
+		params:             &MediaProcessorMockExtractAudioParams{ctx, filepath, opts},
+		expectationOrigins: MediaProcessorMockExtractAudioExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmExtractAudio.expectations = append(mmExtractAudio.expectations, expectation)
+	return expectation
+}
+
+// Then sets up MediaProcessor.ExtractAudio return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockExtractAudioExpectation) Then(resultFilepath string, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockExtractAudioResults{resultFilepath, err}
+	return e.mock
+}
+
+// Times sets number of times MediaProcessor.ExtractAudio should be invoked
+func (mmExtractAudio *mMediaProcessorMockExtractAudio) Times(n uint64) *mMediaProcessorMockExtractAudio {
+	if n == 0 {
+		mmExtractAudio.mock.t.Fatalf("Times of MediaProcessorMock.ExtractAudio mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmExtractAudio.expectedInvocations, n)
+	mmExtractAudio.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmExtractAudio
+}
+
+func (mmExtractAudio *mMediaProcessorMockExtractAudio) invocationsDone() bool {
+	if len(mmExtractAudio.expectations) == 0 && mmExtractAudio.defaultExpectation == nil && mmExtractAudio.mock.funcExtractAudio == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmExtractAudio.mock.afterExtractAudioCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmExtractAudio.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// ExtractAudio implements mm_service.MediaProcessor
+func (mmExtractAudio *MediaProcessorMock) ExtractAudio(ctx context.Context, filepath string, opts mm_service.ExtractAudioOptions) (resultFilepath string, err error) {
+	mm_atomic.AddUint64(&mmExtractAudio.beforeExtractAudioCounter, 1)
+	defer mm_atomic.AddUint64(&mmExtractAudio.afterExtractAudioCounter, 1)
+
+	mmExtractAudio.t.Helper()
+
+	if mmExtractAudio.inspectFuncExtractAudio != nil {
+		mmExtractAudio.inspectFuncExtractAudio(ctx, filepath, opts)
+	}
+
+	mm_params := MediaProcessorMockExtractAudioParams{ctx, filepath, opts}
+
+	// Record call args
+	mmExtractAudio.ExtractAudioMock.mutex.Lock()
+	mmExtractAudio.ExtractAudioMock.callArgs = append(mmExtractAudio.ExtractAudioMock.callArgs, &mm_params)
+	mmExtractAudio.ExtractAudioMock.mutex.Unlock()
+
+	for _, e := range mmExtractAudio.ExtractAudioMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.resultFilepath, e.results.err
+		}
+	}
+
+	if mmExtractAudio.ExtractAudioMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmExtractAudio.ExtractAudioMock.defaultExpectation.Counter, 1)
+		mm_want := mmExtractAudio.ExtractAudioMock.defaultExpectation.params
+		mm_want_ptrs := mmExtractAudio.ExtractAudioMock.defaultExpectation.paramPtrs
+
+		mm_got := MediaProcessorMockExtractAudioParams{ctx, filepath, opts}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmExtractAudio.t.Errorf("MediaProcessorMock.ExtractAudio got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmExtractAudio.ExtractAudioMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.filepath != nil && !minimock.Equal(*mm_want_ptrs.filepath, mm_got.filepath) {
+				mmExtractAudio.t.Errorf("MediaProcessorMock.ExtractAudio got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmExtractAudio.ExtractAudioMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
+			}
+
+			if mm_want_ptrs.opts != nil && !minimock.Equal(*mm_want_ptrs.opts, mm_got.opts) {
+				mmExtractAudio.t.Errorf("MediaProcessorMock.ExtractAudio got unexpected parameter opts, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmExtractAudio.ExtractAudioMock.defaultExpectation.expectationOrigins.originOpts, *mm_want_ptrs.opts, mm_got.opts, minimock.Diff(*mm_want_ptrs.opts, mm_got.opts))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmExtractAudio.t.Errorf("MediaProcessorMock.ExtractAudio got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmExtractAudio.ExtractAudioMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmExtractAudio.ExtractAudioMock.defaultExpectation.results
+		if mm_results == nil {
+			mmExtractAudio.t.Fatal("No results are set for the MediaProcessorMock.ExtractAudio")
+		}
+		return (*mm_results).resultFilepath, (*mm_results).err
+	}
+	if mmExtractAudio.funcExtractAudio != nil {
+		return mmExtractAudio.funcExtractAudio(ctx, filepath, opts)
+	}
+	mmExtractAudio.t.Fatalf("Unexpected call to MediaProcessorMock.ExtractAudio. %v %v %v", ctx, filepath, opts)
+	return
+}
+
+// ExtractAudioAfterCounter returns a count of finished MediaProcessorMock.ExtractAudio invocations
+func (mmExtractAudio *MediaProcessorMock) ExtractAudioAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmExtractAudio.afterExtractAudioCounter)
+}
+
+// ExtractAudioBeforeCounter returns a count of MediaProcessorMock.ExtractAudio invocations
+func (mmExtractAudio *MediaProcessorMock) ExtractAudioBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmExtractAudio.beforeExtractAudioCounter)
+}
+
+// Calls returns a list of arguments used in each call to MediaProcessorMock.ExtractAudio.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmExtractAudio *mMediaProcessorMockExtractAudio) Calls() []*MediaProcessorMockExtractAudioParams {
+	mmExtractAudio.mutex.RLock()
+
+	argCopy := make([]*MediaProcessorMockExtractAudioParams, len(mmExtractAudio.callArgs))
+	copy(argCopy, mmExtractAudio.callArgs)
+
+	mmExtractAudio.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockExtractAudioDone returns true if the count of the ExtractAudio invocations corresponds
+// the number of defined expectations
+func (m *MediaProcessorMock) MinimockExtractAudioDone() bool {
+	if m.ExtractAudioMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.ExtractAudioMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.ExtractAudioMock.invocationsDone()
+}
+
+// MinimockExtractAudioInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockExtractAudioInspect() {
+	for _, e := range m.ExtractAudioMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to MediaProcessorMock.ExtractAudio at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterExtractAudioCounter := mm_atomic.LoadUint64(&m.afterExtractAudioCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ExtractAudioMock.defaultExpectation != nil && afterExtractAudioCounter < 1 {
+		if m.ExtractAudioMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.ExtractAudio at\n%s", m.ExtractAudioMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to MediaProcessorMock.ExtractAudio at\n%s with params: %#v", m.ExtractAudioMock.defaultExpectation.expectationOrigins.origin, *m.ExtractAudioMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcExtractAudio != nil && afterExtractAudioCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.ExtractAudio at\n%s", m.funcExtractAudioOrigin)
+	}
+
+	if !m.ExtractAudioMock.invocationsDone() && afterExtractAudioCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.ExtractAudio at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.ExtractAudioMock.expectedInvocations), m.ExtractAudioMock.expectedInvocationsOrigin, afterExtractAudioCounter)
+	}
+}
+
+type mMediaProcessorMockExtractCoverArt struct {
+	optional           bool
+	mock               *MediaProcessorMock
+	defaultExpectation *MediaProcessorMockExtractCoverArtExpectation
+	expectations       []*MediaProcessorMockExtractCoverArtExpectation
+
+	callArgs []*MediaProcessorMockExtractCoverArtParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// MediaProcessorMockExtractCoverArtExpectation specifies expectation struct of the MediaProcessor.ExtractCoverArt
+type MediaProcessorMockExtractCoverArtExpectation struct {
+	mock               *MediaProcessorMock
+	params             *MediaProcessorMockExtractCoverArtParams
+	paramPtrs          *MediaProcessorMockExtractCoverArtParamPtrs
+	expectationOrigins MediaProcessorMockExtractCoverArtExpectationOrigins
+	results            *MediaProcessorMockExtractCoverArtResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// MediaProcessorMockExtractCoverArtParams contains parameters of the MediaProcessor.ExtractCoverArt
+type MediaProcessorMockExtractCoverArtParams struct {
+	ctx      context.Context
+	filepath string
+}
+
+// MediaProcessorMockExtractCoverArtParamPtrs contains pointers to parameters of the MediaProcessor.ExtractCoverArt
+type MediaProcessorMockExtractCoverArtParamPtrs struct {
+	ctx      *context.Context
+	filepath *string
+}
+
+// MediaProcessorMockExtractCoverArtResults contains results of the MediaProcessor.ExtractCoverArt
+type MediaProcessorMockExtractCoverArtResults struct {
+	coverArtFilepath string
+	err              error
+}
+
+// MediaProcessorMockExtractCoverArtOrigins contains origins of expectations of the MediaProcessor.ExtractCoverArt
+type MediaProcessorMockExtractCoverArtExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originFilepath string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmExtractCoverArt *mMediaProcessorMockExtractCoverArt) Optional() *mMediaProcessorMockExtractCoverArt {
+	mmExtractCoverArt.optional = true
+	return mmExtractCoverArt
+}
+
+// Expect sets up expected params for MediaProcessor.ExtractCoverArt
+func (mmExtractCoverArt *mMediaProcessorMockExtractCoverArt) Expect(ctx context.Context, filepath string) *mMediaProcessorMockExtractCoverArt {
+	if mmExtractCoverArt.mock.funcExtractCoverArt != nil {
+		mmExtractCoverArt.mock.t.Fatalf("MediaProcessorMock.ExtractCoverArt mock is already set by Set")
+	}
+
+	if mmExtractCoverArt.defaultExpectation == nil {
+		mmExtractCoverArt.defaultExpectation = &MediaProcessorMockExtractCoverArtExpectation{}
+	}
+
+	if mmExtractCoverArt.defaultExpectation.paramPtrs != nil {
+		mmExtractCoverArt.mock.t.Fatalf("MediaProcessorMock.ExtractCoverArt mock is already set by ExpectParams functions")
+	}
+
+	mmExtractCoverArt.defaultExpectation.params = &MediaProcessorMockExtractCoverArtParams{ctx, filepath}
+	mmExtractCoverArt.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmExtractCoverArt.expectations {
+		if minimock.Equal(e.params, mmExtractCoverArt.defaultExpectation.params) {
+			mmExtractCoverArt.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmExtractCoverArt.defaultExpectation.params)
+		}
+	}
+
+	return mmExtractCoverArt
+}
+
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.ExtractCoverArt
+func (mmExtractCoverArt *mMediaProcessorMockExtractCoverArt) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockExtractCoverArt {
+	if mmExtractCoverArt.mock.funcExtractCoverArt != nil {
+		mmExtractCoverArt.mock.t.Fatalf("MediaProcessorMock.ExtractCoverArt mock is already set by Set")
+	}
+
+	if mmExtractCoverArt.defaultExpectation == nil {
+		mmExtractCoverArt.defaultExpectation = &MediaProcessorMockExtractCoverArtExpectation{}
+	}
+
+	if mmExtractCoverArt.defaultExpectation.params != nil {
+		mmExtractCoverArt.mock.t.Fatalf("MediaProcessorMock.ExtractCoverArt mock is already set by Expect")
+	}
+
+	if mmExtractCoverArt.defaultExpectation.paramPtrs == nil {
+		mmExtractCoverArt.defaultExpectation.paramPtrs = &MediaProcessorMockExtractCoverArtParamPtrs{}
+	}
+	mmExtractCoverArt.defaultExpectation.paramPtrs.ctx = &ctx
+	mmExtractCoverArt.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmExtractCoverArt
+}
+
+// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.ExtractCoverArt
+func (mmExtractCoverArt *mMediaProcessorMockExtractCoverArt) ExpectFilepathParam2(filepath string) *mMediaProcessorMockExtractCoverArt {
+	if mmExtractCoverArt.mock.funcExtractCoverArt != nil {
+		mmExtractCoverArt.mock.t.Fatalf("MediaProcessorMock.ExtractCoverArt mock is already set by Set")
+	}
+
+	if mmExtractCoverArt.defaultExpectation == nil {
+		mmExtractCoverArt.defaultExpectation = &MediaProcessorMockExtractCoverArtExpectation{}
+	}
+
+	if mmExtractCoverArt.defaultExpectation.params != nil {
+		mmExtractCoverArt.mock.t.Fatalf("MediaProcessorMock.ExtractCoverArt mock is already set by Expect")
+	}
+
+	if mmExtractCoverArt.defaultExpectation.paramPtrs == nil {
+		mmExtractCoverArt.defaultExpectation.paramPtrs = &MediaProcessorMockExtractCoverArtParamPtrs{}
+	}
+	mmExtractCoverArt.defaultExpectation.paramPtrs.filepath = &filepath
+	mmExtractCoverArt.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
+
+	return mmExtractCoverArt
+}
+
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.ExtractCoverArt
+func (mmExtractCoverArt *mMediaProcessorMockExtractCoverArt) Inspect(f func(ctx context.Context, filepath string)) *mMediaProcessorMockExtractCoverArt {
+	if mmExtractCoverArt.mock.inspectFuncExtractCoverArt != nil {
+		mmExtractCoverArt.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.ExtractCoverArt")
+	}
+
+	mmExtractCoverArt.mock.inspectFuncExtractCoverArt = f
+
+	return mmExtractCoverArt
+}
+
+// Return sets up results that will be returned by MediaProcessor.ExtractCoverArt
+func (mmExtractCoverArt *mMediaProcessorMockExtractCoverArt) Return(coverArtFilepath string, err error) *MediaProcessorMock {
+	if mmExtractCoverArt.mock.funcExtractCoverArt != nil {
+		mmExtractCoverArt.mock.t.Fatalf("MediaProcessorMock.ExtractCoverArt mock is already set by Set")
+	}
+
+	if mmExtractCoverArt.defaultExpectation == nil {
+		mmExtractCoverArt.defaultExpectation = &MediaProcessorMockExtractCoverArtExpectation{mock: mmExtractCoverArt.mock}
+	}
+	mmExtractCoverArt.defaultExpectation.results = &MediaProcessorMockExtractCoverArtResults{coverArtFilepath, err}
+	mmExtractCoverArt.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmExtractCoverArt.mock
+}
+
+// Set uses given function f to mock the MediaProcessor.ExtractCoverArt method
+func (mmExtractCoverArt *mMediaProcessorMockExtractCoverArt) Set(f func(ctx context.Context, filepath string) (coverArtFilepath string, err error)) *MediaProcessorMock {
+	if mmExtractCoverArt.defaultExpectation != nil {
+		mmExtractCoverArt.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.ExtractCoverArt method")
+	}
+
+	if len(mmExtractCoverArt.expectations) > 0 {
+		mmExtractCoverArt.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.ExtractCoverArt method")
+	}
+
+	mmExtractCoverArt.mock.funcExtractCoverArt = f
+	mmExtractCoverArt.mock.funcExtractCoverArtOrigin = minimock.CallerInfo(1)
+	return mmExtractCoverArt.mock
+}
+
+// When sets expectation for the MediaProcessor.ExtractCoverArt which will trigger the result defined by the following
+// Then helper
+func (mmExtractCoverArt *mMediaProcessorMockExtractCoverArt) When(ctx context.Context, filepath string) *MediaProcessorMockExtractCoverArtExpectation {
+	if mmExtractCoverArt.mock.funcExtractCoverArt != nil {
+		mmExtractCoverArt.mock.t.Fatalf("MediaProcessorMock.ExtractCoverArt mock is already set by Set")
+	}
+
+	expectation := &MediaProcessorMockExtractCoverArtExpectation{
+		mock:               mmExtractCoverArt.mock,
+		params:             &MediaProcessorMockExtractCoverArtParams{ctx, filepath},
+		expectationOrigins: MediaProcessorMockExtractCoverArtExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmExtractCoverArt.expectations = append(mmExtractCoverArt.expectations, expectation)
+	return expectation
+}
+
+// Then sets up MediaProcessor.ExtractCoverArt return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockExtractCoverArtExpectation) Then(coverArtFilepath string, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockExtractCoverArtResults{coverArtFilepath, err}
+	return e.mock
+}
+
+// Times sets number of times MediaProcessor.ExtractCoverArt should be invoked
+func (mmExtractCoverArt *mMediaProcessorMockExtractCoverArt) Times(n uint64) *mMediaProcessorMockExtractCoverArt {
+	if n == 0 {
+		mmExtractCoverArt.mock.t.Fatalf("Times of MediaProcessorMock.ExtractCoverArt mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmExtractCoverArt.expectedInvocations, n)
+	mmExtractCoverArt.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmExtractCoverArt
+}
+
+func (mmExtractCoverArt *mMediaProcessorMockExtractCoverArt) invocationsDone() bool {
+	if len(mmExtractCoverArt.expectations) == 0 && mmExtractCoverArt.defaultExpectation == nil && mmExtractCoverArt.mock.funcExtractCoverArt == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmExtractCoverArt.mock.afterExtractCoverArtCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmExtractCoverArt.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// ExtractCoverArt implements mm_service.MediaProcessor
+func (mmExtractCoverArt *MediaProcessorMock) ExtractCoverArt(ctx context.Context, filepath string) (coverArtFilepath string, err error) {
+	mm_atomic.AddUint64(&mmExtractCoverArt.beforeExtractCoverArtCounter, 1)
+	defer mm_atomic.AddUint64(&mmExtractCoverArt.afterExtractCoverArtCounter, 1)
+
+	mmExtractCoverArt.t.Helper()
+
+	if mmExtractCoverArt.inspectFuncExtractCoverArt != nil {
+		mmExtractCoverArt.inspectFuncExtractCoverArt(ctx, filepath)
+	}
+
+	mm_params := MediaProcessorMockExtractCoverArtParams{ctx, filepath}
+
+	// Record call args
+	mmExtractCoverArt.ExtractCoverArtMock.mutex.Lock()
+	mmExtractCoverArt.ExtractCoverArtMock.callArgs = append(mmExtractCoverArt.ExtractCoverArtMock.callArgs, &mm_params)
+	mmExtractCoverArt.ExtractCoverArtMock.mutex.Unlock()
+
+	for _, e := range mmExtractCoverArt.ExtractCoverArtMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.coverArtFilepath, e.results.err
+		}
+	}
+
+	if mmExtractCoverArt.ExtractCoverArtMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmExtractCoverArt.ExtractCoverArtMock.defaultExpectation.Counter, 1)
+		mm_want := mmExtractCoverArt.ExtractCoverArtMock.defaultExpectation.params
+		mm_want_ptrs := mmExtractCoverArt.ExtractCoverArtMock.defaultExpectation.paramPtrs
+
+		mm_got := MediaProcessorMockExtractCoverArtParams{ctx, filepath}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmExtractCoverArt.t.Errorf("MediaProcessorMock.ExtractCoverArt got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmExtractCoverArt.ExtractCoverArtMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.filepath != nil && !minimock.Equal(*mm_want_ptrs.filepath, mm_got.filepath) {
+				mmExtractCoverArt.t.Errorf("MediaProcessorMock.ExtractCoverArt got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmExtractCoverArt.ExtractCoverArtMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmExtractCoverArt.t.Errorf("MediaProcessorMock.ExtractCoverArt got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmExtractCoverArt.ExtractCoverArtMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmExtractCoverArt.ExtractCoverArtMock.defaultExpectation.results
+		if mm_results == nil {
+			mmExtractCoverArt.t.Fatal("No results are set for the MediaProcessorMock.ExtractCoverArt")
+		}
+		return (*mm_results).coverArtFilepath, (*mm_results).err
+	}
+	if mmExtractCoverArt.funcExtractCoverArt != nil {
+		return mmExtractCoverArt.funcExtractCoverArt(ctx, filepath)
+	}
+	mmExtractCoverArt.t.Fatalf("Unexpected call to MediaProcessorMock.ExtractCoverArt. %v %v", ctx, filepath)
+	return
+}
+
+// ExtractCoverArtAfterCounter returns a count of finished MediaProcessorMock.ExtractCoverArt invocations
+func (mmExtractCoverArt *MediaProcessorMock) ExtractCoverArtAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmExtractCoverArt.afterExtractCoverArtCounter)
+}
+
+// ExtractCoverArtBeforeCounter returns a count of MediaProcessorMock.ExtractCoverArt invocations
+func (mmExtractCoverArt *MediaProcessorMock) ExtractCoverArtBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmExtractCoverArt.beforeExtractCoverArtCounter)
+}
+
+// Calls returns a list of arguments used in each call to MediaProcessorMock.ExtractCoverArt.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmExtractCoverArt *mMediaProcessorMockExtractCoverArt) Calls() []*MediaProcessorMockExtractCoverArtParams {
+	mmExtractCoverArt.mutex.RLock()
+
+	argCopy := make([]*MediaProcessorMockExtractCoverArtParams, len(mmExtractCoverArt.callArgs))
+	copy(argCopy, mmExtractCoverArt.callArgs)
+
+	mmExtractCoverArt.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockExtractCoverArtDone returns true if the count of the ExtractCoverArt invocations corresponds
+// the number of defined expectations
+func (m *MediaProcessorMock) MinimockExtractCoverArtDone() bool {
+	if m.ExtractCoverArtMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.ExtractCoverArtMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.ExtractCoverArtMock.invocationsDone()
+}
+
+// MinimockExtractCoverArtInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockExtractCoverArtInspect() {
+	for _, e := range m.ExtractCoverArtMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to MediaProcessorMock.ExtractCoverArt at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterExtractCoverArtCounter := mm_atomic.LoadUint64(&m.afterExtractCoverArtCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ExtractCoverArtMock.defaultExpectation != nil && afterExtractCoverArtCounter < 1 {
+		if m.ExtractCoverArtMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.ExtractCoverArt at\n%s", m.ExtractCoverArtMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to MediaProcessorMock.ExtractCoverArt at\n%s with params: %#v", m.ExtractCoverArtMock.defaultExpectation.expectationOrigins.origin, *m.ExtractCoverArtMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcExtractCoverArt != nil && afterExtractCoverArtCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.ExtractCoverArt at\n%s", m.funcExtractCoverArtOrigin)
+	}
+
+	if !m.ExtractCoverArtMock.invocationsDone() && afterExtractCoverArtCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.ExtractCoverArt at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.ExtractCoverArtMock.expectedInvocations), m.ExtractCoverArtMock.expectedInvocationsOrigin, afterExtractCoverArtCounter)
+	}
+}
+
+type mMediaProcessorMockGetInfo struct {
+	optional           bool
+	mock               *MediaProcessorMock
+	defaultExpectation *MediaProcessorMockGetInfoExpectation
+	expectations       []*MediaProcessorMockGetInfoExpectation
+
+	callArgs []*MediaProcessorMockGetInfoParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// MediaProcessorMockGetInfoExpectation specifies expectation struct of the MediaProcessor.GetInfo
+type MediaProcessorMockGetInfoExpectation struct {
+	mock               *MediaProcessorMock
+	params             *MediaProcessorMockGetInfoParams
+	paramPtrs          *MediaProcessorMockGetInfoParamPtrs
+	expectationOrigins MediaProcessorMockGetInfoExpectationOrigins
+	results            *MediaProcessorMockGetInfoResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// MediaProcessorMockGetInfoParams contains parameters of the MediaProcessor.GetInfo
+type MediaProcessorMockGetInfoParams struct {
+	ctx      context.Context
+	filepath string
+}
+
+// MediaProcessorMockGetInfoParamPtrs contains pointers to parameters of the MediaProcessor.GetInfo
+type MediaProcessorMockGetInfoParamPtrs struct {
+	ctx      *context.Context
+	filepath *string
+}
+
+// MediaProcessorMockGetInfoResults contains results of the MediaProcessor.GetInfo
+type MediaProcessorMockGetInfoResults struct {
+	info *mm_service.MediaInfo
+	err  error
+}
+
+// MediaProcessorMockGetInfoOrigins contains origins of expectations of the MediaProcessor.GetInfo
+type MediaProcessorMockGetInfoExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originFilepath string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmGetInfo *mMediaProcessorMockGetInfo) Optional() *mMediaProcessorMockGetInfo {
+	mmGetInfo.optional = true
+	return mmGetInfo
+}
+
+// Expect sets up expected params for MediaProcessor.GetInfo
+func (mmGetInfo *mMediaProcessorMockGetInfo) Expect(ctx context.Context, filepath string) *mMediaProcessorMockGetInfo {
+	if mmGetInfo.mock.funcGetInfo != nil {
+		mmGetInfo.mock.t.Fatalf("MediaProcessorMock.GetInfo mock is already set by Set")
+	}
+
+	if mmGetInfo.defaultExpectation == nil {
+		mmGetInfo.defaultExpectation = &MediaProcessorMockGetInfoExpectation{}
+	}
+
+	if mmGetInfo.defaultExpectation.paramPtrs != nil {
+		mmGetInfo.mock.t.Fatalf("MediaProcessorMock.GetInfo mock is already set by ExpectParams functions")
+	}
+
+	mmGetInfo.defaultExpectation.params = &MediaProcessorMockGetInfoParams{ctx, filepath}
+	mmGetInfo.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmGetInfo.expectations {
+		if minimock.Equal(e.params, mmGetInfo.defaultExpectation.params) {
+			mmGetInfo.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmGetInfo.defaultExpectation.params)
+		}
+	}
+
+	return mmGetInfo
+}
+
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.GetInfo
+func (mmGetInfo *mMediaProcessorMockGetInfo) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockGetInfo {
+	if mmGetInfo.mock.funcGetInfo != nil {
+		mmGetInfo.mock.t.Fatalf("MediaProcessorMock.GetInfo mock is already set by Set")
+	}
+
+	if mmGetInfo.defaultExpectation == nil {
+		mmGetInfo.defaultExpectation = &MediaProcessorMockGetInfoExpectation{}
+	}
+
+	if mmGetInfo.defaultExpectation.params != nil {
+		mmGetInfo.mock.t.Fatalf("MediaProcessorMock.GetInfo mock is already set by Expect")
+	}
+
+	if mmGetInfo.defaultExpectation.paramPtrs == nil {
+		mmGetInfo.defaultExpectation.paramPtrs = &MediaProcessorMockGetInfoParamPtrs{}
+	}
+	mmGetInfo.defaultExpectation.paramPtrs.ctx = &ctx
+	mmGetInfo.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmGetInfo
+}
+
+// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.GetInfo
+func (mmGetInfo *mMediaProcessorMockGetInfo) ExpectFilepathParam2(filepath string) *mMediaProcessorMockGetInfo {
+	if mmGetInfo.mock.funcGetInfo != nil {
+		mmGetInfo.mock.t.Fatalf("MediaProcessorMock.GetInfo mock is already set by Set")
+	}
+
+	if mmGetInfo.defaultExpectation == nil {
+		mmGetInfo.defaultExpectation = &MediaProcessorMockGetInfoExpectation{}
+	}
+
+	if mmGetInfo.defaultExpectation.params != nil {
+		mmGetInfo.mock.t.Fatalf("MediaProcessorMock.GetInfo mock is already set by Expect")
+	}
+
+	if mmGetInfo.defaultExpectation.paramPtrs == nil {
+		mmGetInfo.defaultExpectation.paramPtrs = &MediaProcessorMockGetInfoParamPtrs{}
+	}
+	mmGetInfo.defaultExpectation.paramPtrs.filepath = &filepath
+	mmGetInfo.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
+
+	return mmGetInfo
+}
+
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.GetInfo
+func (mmGetInfo *mMediaProcessorMockGetInfo) Inspect(f func(ctx context.Context, filepath string)) *mMediaProcessorMockGetInfo {
+	if mmGetInfo.mock.inspectFuncGetInfo != nil {
+		mmGetInfo.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.GetInfo")
 	}
 
 	mmGetInfo.mock.inspectFuncGetInfo = f
 
-	return mmGetInfo
+	return mmGetInfo
+}
+
+// Return sets up results that will be returned by MediaProcessor.GetInfo
+func (mmGetInfo *mMediaProcessorMockGetInfo) Return(info *mm_service.MediaInfo, err error) *MediaProcessorMock {
+	if mmGetInfo.mock.funcGetInfo != nil {
+		mmGetInfo.mock.t.Fatalf("MediaProcessorMock.GetInfo mock is already set by Set")
+	}
+
+	if mmGetInfo.defaultExpectation == nil {
+		mmGetInfo.defaultExpectation = &MediaProcessorMockGetInfoExpectation{mock: mmGetInfo.mock}
+	}
+	mmGetInfo.defaultExpectation.results = &MediaProcessorMockGetInfoResults{info, err}
+	mmGetInfo.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmGetInfo.mock
+}
+
+// Set uses given function f to mock the MediaProcessor.GetInfo method
+func (mmGetInfo *mMediaProcessorMockGetInfo) Set(f func(ctx context.Context, filepath string) (info *mm_service.MediaInfo, err error)) *MediaProcessorMock {
+	if mmGetInfo.defaultExpectation != nil {
+		mmGetInfo.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.GetInfo method")
+	}
+
+	if len(mmGetInfo.expectations) > 0 {
+		mmGetInfo.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.GetInfo method")
+	}
+
+	mmGetInfo.mock.funcGetInfo = f
+	mmGetInfo.mock.funcGetInfoOrigin = minimock.CallerInfo(1)
+	return mmGetInfo.mock
+}
+
+// When sets expectation for the MediaProcessor.GetInfo which will trigger the result defined by the following
+// Then helper
+func (mmGetInfo *mMediaProcessorMockGetInfo) When(ctx context.Context, filepath string) *MediaProcessorMockGetInfoExpectation {
+	if mmGetInfo.mock.funcGetInfo != nil {
+		mmGetInfo.mock.t.Fatalf("MediaProcessorMock.GetInfo mock is already set by Set")
+	}
+
+	expectation := &MediaProcessorMockGetInfoExpectation{
+		mock:               mmGetInfo.mock,
+		params:             &MediaProcessorMockGetInfoParams{ctx, filepath},
+		expectationOrigins: MediaProcessorMockGetInfoExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmGetInfo.expectations = append(mmGetInfo.expectations, expectation)
+	return expectation
+}
+
+// Then sets up MediaProcessor.GetInfo return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockGetInfoExpectation) Then(info *mm_service.MediaInfo, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockGetInfoResults{info, err}
+	return e.mock
+}
+
+// Times sets number of times MediaProcessor.GetInfo should be invoked
+func (mmGetInfo *mMediaProcessorMockGetInfo) Times(n uint64) *mMediaProcessorMockGetInfo {
+	if n == 0 {
+		mmGetInfo.mock.t.Fatalf("Times of MediaProcessorMock.GetInfo mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmGetInfo.expectedInvocations, n)
+	mmGetInfo.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmGetInfo
+}
+
+func (mmGetInfo *mMediaProcessorMockGetInfo) invocationsDone() bool {
+	if len(mmGetInfo.expectations) == 0 && mmGetInfo.defaultExpectation == nil && mmGetInfo.mock.funcGetInfo == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmGetInfo.mock.afterGetInfoCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmGetInfo.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// GetInfo implements mm_service.MediaProcessor
+func (mmGetInfo *MediaProcessorMock) GetInfo(ctx context.Context, filepath string) (info *mm_service.MediaInfo, err error) {
+	mm_atomic.AddUint64(&mmGetInfo.beforeGetInfoCounter, 1)
+	defer mm_atomic.AddUint64(&mmGetInfo.afterGetInfoCounter, 1)
+
+	mmGetInfo.t.Helper()
+
+	if mmGetInfo.inspectFuncGetInfo != nil {
+		mmGetInfo.inspectFuncGetInfo(ctx, filepath)
+	}
+
+	mm_params := MediaProcessorMockGetInfoParams{ctx, filepath}
+
+	// Record call args
+	mmGetInfo.GetInfoMock.mutex.Lock()
+	mmGetInfo.GetInfoMock.callArgs = append(mmGetInfo.GetInfoMock.callArgs, &mm_params)
+	mmGetInfo.GetInfoMock.mutex.Unlock()
+
+	for _, e := range mmGetInfo.GetInfoMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.info, e.results.err
+		}
+	}
+
+	if mmGetInfo.GetInfoMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmGetInfo.GetInfoMock.defaultExpectation.Counter, 1)
+		mm_want := mmGetInfo.GetInfoMock.defaultExpectation.params
+		mm_want_ptrs := mmGetInfo.GetInfoMock.defaultExpectation.paramPtrs
+
+		mm_got := MediaProcessorMockGetInfoParams{ctx, filepath}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmGetInfo.t.Errorf("MediaProcessorMock.GetInfo got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetInfo.GetInfoMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.filepath != nil && !minimock.Equal(*mm_want_ptrs.filepath, mm_got.filepath) {
+				mmGetInfo.t.Errorf("MediaProcessorMock.GetInfo got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetInfo.GetInfoMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmGetInfo.t.Errorf("MediaProcessorMock.GetInfo got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmGetInfo.GetInfoMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmGetInfo.GetInfoMock.defaultExpectation.results
+		if mm_results == nil {
+			mmGetInfo.t.Fatal("No results are set for the MediaProcessorMock.GetInfo")
+		}
+		return (*mm_results).info, (*mm_results).err
+	}
+	if mmGetInfo.funcGetInfo != nil {
+		return mmGetInfo.funcGetInfo(ctx, filepath)
+	}
+	mmGetInfo.t.Fatalf("Unexpected call to MediaProcessorMock.GetInfo. %v %v", ctx, filepath)
+	return
+}
+
+// GetInfoAfterCounter returns a count of finished MediaProcessorMock.GetInfo invocations
+func (mmGetInfo *MediaProcessorMock) GetInfoAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmGetInfo.afterGetInfoCounter)
+}
+
+// GetInfoBeforeCounter returns a count of MediaProcessorMock.GetInfo invocations
+func (mmGetInfo *MediaProcessorMock) GetInfoBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmGetInfo.beforeGetInfoCounter)
+}
+
+// Calls returns a list of arguments used in each call to MediaProcessorMock.GetInfo.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmGetInfo *mMediaProcessorMockGetInfo) Calls() []*MediaProcessorMockGetInfoParams {
+	mmGetInfo.mutex.RLock()
+
+	argCopy := make([]*MediaProcessorMockGetInfoParams, len(mmGetInfo.callArgs))
+	copy(argCopy, mmGetInfo.callArgs)
+
+	mmGetInfo.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockGetInfoDone returns true if the count of the GetInfo invocations corresponds
+// the number of defined expectations
+func (m *MediaProcessorMock) MinimockGetInfoDone() bool {
+	if m.GetInfoMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.GetInfoMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.GetInfoMock.invocationsDone()
+}
+
+// MinimockGetInfoInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockGetInfoInspect() {
+	for _, e := range m.GetInfoMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to MediaProcessorMock.GetInfo at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterGetInfoCounter := mm_atomic.LoadUint64(&m.afterGetInfoCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.GetInfoMock.defaultExpectation != nil && afterGetInfoCounter < 1 {
+		if m.GetInfoMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.GetInfo at\n%s", m.GetInfoMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to MediaProcessorMock.GetInfo at\n%s with params: %#v", m.GetInfoMock.defaultExpectation.expectationOrigins.origin, *m.GetInfoMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcGetInfo != nil && afterGetInfoCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.GetInfo at\n%s", m.funcGetInfoOrigin)
+	}
+
+	if !m.GetInfoMock.invocationsDone() && afterGetInfoCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.GetInfo at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.GetInfoMock.expectedInvocations), m.GetInfoMock.expectedInvocationsOrigin, afterGetInfoCounter)
+	}
+}
+
+type mMediaProcessorMockNormalize struct {
+	optional           bool
+	mock               *MediaProcessorMock
+	defaultExpectation *MediaProcessorMockNormalizeExpectation
+	expectations       []*MediaProcessorMockNormalizeExpectation
+
+	callArgs []*MediaProcessorMockNormalizeParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// MediaProcessorMockNormalizeExpectation specifies expectation struct of the MediaProcessor.Normalize
+type MediaProcessorMockNormalizeExpectation struct {
+	mock               *MediaProcessorMock
+	params             *MediaProcessorMockNormalizeParams
+	paramPtrs          *MediaProcessorMockNormalizeParamPtrs
+	expectationOrigins MediaProcessorMockNormalizeExpectationOrigins
+	results            *MediaProcessorMockNormalizeResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// MediaProcessorMockNormalizeParams contains parameters of the MediaProcessor.Normalize
+type MediaProcessorMockNormalizeParams struct {
+	ctx      context.Context
+	filepath string
+	opts     mm_service.NormalizeOptions
+}
+
+// MediaProcessorMockNormalizeParamPtrs contains pointers to parameters of the MediaProcessor.Normalize
+type MediaProcessorMockNormalizeParamPtrs struct {
+	ctx      *context.Context
+	filepath *string
+	opts     *mm_service.NormalizeOptions
+}
+
+// MediaProcessorMockNormalizeResults contains results of the MediaProcessor.Normalize
+type MediaProcessorMockNormalizeResults struct {
+	resultFilepath string
+	stats          *mm_service.LoudnessStats
+	err            error
+}
+
+// MediaProcessorMockNormalizeOrigins contains origins of expectations of the MediaProcessor.Normalize
+type MediaProcessorMockNormalizeExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originFilepath string
+	originOpts     string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmNormalize *mMediaProcessorMockNormalize) Optional() *mMediaProcessorMockNormalize {
+	mmNormalize.optional = true
+	return mmNormalize
+}
+
+// Expect sets up expected params for MediaProcessor.Normalize
+func (mmNormalize *mMediaProcessorMockNormalize) Expect(ctx context.Context, filepath string, opts mm_service.NormalizeOptions) *mMediaProcessorMockNormalize {
+	if mmNormalize.mock.funcNormalize != nil {
+		mmNormalize.mock.t.Fatalf("MediaProcessorMock.Normalize mock is already set by Set")
+	}
+
+	if mmNormalize.defaultExpectation == nil {
+		mmNormalize.defaultExpectation = &MediaProcessorMockNormalizeExpectation{}
+	}
+
+	if mmNormalize.defaultExpectation.paramPtrs != nil {
+		mmNormalize.mock.t.Fatalf("MediaProcessorMock.Normalize mock is already set by ExpectParams functions")
+	}
+
+	mmNormalize.defaultExpectation.params = &MediaProcessorMockNormalizeParams{ctx, filepath, opts}
+	mmNormalize.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmNormalize.expectations {
+		if minimock.Equal(e.params, mmNormalize.defaultExpectation.params) {
+			mmNormalize.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmNormalize.defaultExpectation.params)
+		}
+	}
+
+	return mmNormalize
+}
+
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.Normalize
+func (mmNormalize *mMediaProcessorMockNormalize) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockNormalize {
+	if mmNormalize.mock.funcNormalize != nil {
+		mmNormalize.mock.t.Fatalf("MediaProcessorMock.Normalize mock is already set by Set")
+	}
+
+	if mmNormalize.defaultExpectation == nil {
+		mmNormalize.defaultExpectation = &MediaProcessorMockNormalizeExpectation{}
+	}
+
+	if mmNormalize.defaultExpectation.params != nil {
+		mmNormalize.mock.t.Fatalf("MediaProcessorMock.Normalize mock is already set by Expect")
+	}
+
+	if mmNormalize.defaultExpectation.paramPtrs == nil {
+		mmNormalize.defaultExpectation.paramPtrs = &MediaProcessorMockNormalizeParamPtrs{}
+	}
+	mmNormalize.defaultExpectation.paramPtrs.ctx = &ctx
+	mmNormalize.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmNormalize
+}
+
+// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.Normalize
+func (mmNormalize *mMediaProcessorMockNormalize) ExpectFilepathParam2(filepath string) *mMediaProcessorMockNormalize {
+	if mmNormalize.mock.funcNormalize != nil {
+		mmNormalize.mock.t.Fatalf("MediaProcessorMock.Normalize mock is already set by Set")
+	}
+
+	if mmNormalize.defaultExpectation == nil {
+		mmNormalize.defaultExpectation = &MediaProcessorMockNormalizeExpectation{}
+	}
+
+	if mmNormalize.defaultExpectation.params != nil {
+		mmNormalize.mock.t.Fatalf("MediaProcessorMock.Normalize mock is already set by Expect")
+	}
+
+	if mmNormalize.defaultExpectation.paramPtrs == nil {
+		mmNormalize.defaultExpectation.paramPtrs = &MediaProcessorMockNormalizeParamPtrs{}
+	}
+	mmNormalize.defaultExpectation.paramPtrs.filepath = &filepath
+	mmNormalize.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
+
+	return mmNormalize
+}
+
+// ExpectOptsParam3 sets up expected param opts for MediaProcessor.Normalize
+func (mmNormalize *mMediaProcessorMockNormalize) ExpectOptsParam3(opts mm_service.NormalizeOptions) *mMediaProcessorMockNormalize {
+	if mmNormalize.mock.funcNormalize != nil {
+		mmNormalize.mock.t.Fatalf("MediaProcessorMock.Normalize mock is already set by Set")
+	}
+
+	if mmNormalize.defaultExpectation == nil {
+		mmNormalize.defaultExpectation = &MediaProcessorMockNormalizeExpectation{}
+	}
+
+	if mmNormalize.defaultExpectation.params != nil {
+		mmNormalize.mock.t.Fatalf("MediaProcessorMock.Normalize mock is already set by Expect")
+	}
+
+	if mmNormalize.defaultExpectation.paramPtrs == nil {
+		mmNormalize.defaultExpectation.paramPtrs = &MediaProcessorMockNormalizeParamPtrs{}
+	}
+	mmNormalize.defaultExpectation.paramPtrs.opts = &opts
+	mmNormalize.defaultExpectation.expectationOrigins.originOpts = minimock.CallerInfo(1)
+
+	return mmNormalize
+}
+
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.Normalize
+func (mmNormalize *mMediaProcessorMockNormalize) Inspect(f func(ctx context.Context, filepath string, opts mm_service.NormalizeOptions)) *mMediaProcessorMockNormalize {
+	if mmNormalize.mock.inspectFuncNormalize != nil {
+		mmNormalize.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.Normalize")
+	}
+
+	mmNormalize.mock.inspectFuncNormalize = f
+
+	return mmNormalize
+}
+
+// Return sets up results that will be returned by MediaProcessor.Normalize
+func (mmNormalize *mMediaProcessorMockNormalize) Return(resultFilepath string, stats *mm_service.LoudnessStats, err error) *MediaProcessorMock {
+	if mmNormalize.mock.funcNormalize != nil {
+		mmNormalize.mock.t.Fatalf("MediaProcessorMock.Normalize mock is already set by Set")
+	}
+
+	if mmNormalize.defaultExpectation == nil {
+		mmNormalize.defaultExpectation = &MediaProcessorMockNormalizeExpectation{mock: mmNormalize.mock}
+	}
+	mmNormalize.defaultExpectation.results = &MediaProcessorMockNormalizeResults{resultFilepath, stats, err}
+	mmNormalize.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmNormalize.mock
+}
+
+// Set uses given function f to mock the MediaProcessor.Normalize method
+func (mmNormalize *mMediaProcessorMockNormalize) Set(f func(ctx context.Context, filepath string, opts mm_service.NormalizeOptions) (resultFilepath string, stats *mm_service.LoudnessStats, err error)) *MediaProcessorMock {
+	if mmNormalize.defaultExpectation != nil {
+		mmNormalize.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.Normalize method")
+	}
+
+	if len(mmNormalize.expectations) > 0 {
+		mmNormalize.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.Normalize method")
+	}
+
+	mmNormalize.mock.funcNormalize = f
+	mmNormalize.mock.funcNormalizeOrigin = minimock.CallerInfo(1)
+	return mmNormalize.mock
+}
+
+// When sets expectation for the MediaProcessor.Normalize which will trigger the result defined by the following
+// Then helper
+func (mmNormalize *mMediaProcessorMockNormalize) When(ctx context.Context, filepath string, opts mm_service.NormalizeOptions) *MediaProcessorMockNormalizeExpectation {
+	if mmNormalize.mock.funcNormalize != nil {
+		mmNormalize.mock.t.Fatalf("MediaProcessorMock.Normalize mock is already set by Set")
+	}
+
+	expectation := &MediaProcessorMockNormalizeExpectation{
+		mock:               mmNormalize.mock,
+		params:             &MediaProcessorMockNormalizeParams{ctx, filepath, opts},
+		expectationOrigins: MediaProcessorMockNormalizeExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmNormalize.expectations = append(mmNormalize.expectations, expectation)
+	return expectation
+}
+
+// Then sets up MediaProcessor.Normalize return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockNormalizeExpectation) Then(resultFilepath string, stats *mm_service.LoudnessStats, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockNormalizeResults{resultFilepath, stats, err}
+	return e.mock
+}
+
+// Times sets number of times MediaProcessor.Normalize should be invoked
+func (mmNormalize *mMediaProcessorMockNormalize) Times(n uint64) *mMediaProcessorMockNormalize {
+	if n == 0 {
+		mmNormalize.mock.t.Fatalf("Times of MediaProcessorMock.Normalize mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmNormalize.expectedInvocations, n)
+	mmNormalize.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmNormalize
+}
+
+func (mmNormalize *mMediaProcessorMockNormalize) invocationsDone() bool {
+	if len(mmNormalize.expectations) == 0 && mmNormalize.defaultExpectation == nil && mmNormalize.mock.funcNormalize == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmNormalize.mock.afterNormalizeCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmNormalize.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// Normalize implements mm_service.MediaProcessor
+func (mmNormalize *MediaProcessorMock) Normalize(ctx context.Context, filepath string, opts mm_service.NormalizeOptions) (resultFilepath string, stats *mm_service.LoudnessStats, err error) {
+	mm_atomic.AddUint64(&mmNormalize.beforeNormalizeCounter, 1)
+	defer mm_atomic.AddUint64(&mmNormalize.afterNormalizeCounter, 1)
+
+	mmNormalize.t.Helper()
+
+	if mmNormalize.inspectFuncNormalize != nil {
+		mmNormalize.inspectFuncNormalize(ctx, filepath, opts)
+	}
+
+	mm_params := MediaProcessorMockNormalizeParams{ctx, filepath, opts}
+
+	// Record call args
+	mmNormalize.NormalizeMock.mutex.Lock()
+	mmNormalize.NormalizeMock.callArgs = append(mmNormalize.NormalizeMock.callArgs, &mm_params)
+	mmNormalize.NormalizeMock.mutex.Unlock()
+
+	for _, e := range mmNormalize.NormalizeMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.resultFilepath, e.results.stats, e.results.err
+		}
+	}
+
+	if mmNormalize.NormalizeMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmNormalize.NormalizeMock.defaultExpectation.Counter, 1)
+		mm_want := mmNormalize.NormalizeMock.defaultExpectation.params
+		mm_want_ptrs := mmNormalize.NormalizeMock.defaultExpectation.paramPtrs
+
+		mm_got := MediaProcessorMockNormalizeParams{ctx, filepath, opts}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmNormalize.t.Errorf("MediaProcessorMock.Normalize got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmNormalize.NormalizeMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.filepath != nil && !minimock.Equal(*mm_want_ptrs.filepath, mm_got.filepath) {
+				mmNormalize.t.Errorf("MediaProcessorMock.Normalize got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmNormalize.NormalizeMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
+			}
+
+			if mm_want_ptrs.opts != nil && !minimock.Equal(*mm_want_ptrs.opts, mm_got.opts) {
+				mmNormalize.t.Errorf("MediaProcessorMock.Normalize got unexpected parameter opts, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmNormalize.NormalizeMock.defaultExpectation.expectationOrigins.originOpts, *mm_want_ptrs.opts, mm_got.opts, minimock.Diff(*mm_want_ptrs.opts, mm_got.opts))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmNormalize.t.Errorf("MediaProcessorMock.Normalize got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmNormalize.NormalizeMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmNormalize.NormalizeMock.defaultExpectation.results
+		if mm_results == nil {
+			mmNormalize.t.Fatal("No results are set for the MediaProcessorMock.Normalize")
+		}
+		return (*mm_results).resultFilepath, (*mm_results).stats, (*mm_results).err
+	}
+	if mmNormalize.funcNormalize != nil {
+		return mmNormalize.funcNormalize(ctx, filepath, opts)
+	}
+	mmNormalize.t.Fatalf("Unexpected call to MediaProcessorMock.Normalize. %v %v %v", ctx, filepath, opts)
+	return
+}
+
+// NormalizeAfterCounter returns a count of finished MediaProcessorMock.Normalize invocations
+func (mmNormalize *MediaProcessorMock) NormalizeAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmNormalize.afterNormalizeCounter)
+}
+
+// NormalizeBeforeCounter returns a count of MediaProcessorMock.Normalize invocations
+func (mmNormalize *MediaProcessorMock) NormalizeBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmNormalize.beforeNormalizeCounter)
+}
+
+// Calls returns a list of arguments used in each call to MediaProcessorMock.Normalize.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmNormalize *mMediaProcessorMockNormalize) Calls() []*MediaProcessorMockNormalizeParams {
+	mmNormalize.mutex.RLock()
+
+	argCopy := make([]*MediaProcessorMockNormalizeParams, len(mmNormalize.callArgs))
+	copy(argCopy, mmNormalize.callArgs)
+
+	mmNormalize.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockNormalizeDone returns true if the count of the Normalize invocations corresponds
+// the number of defined expectations
+func (m *MediaProcessorMock) MinimockNormalizeDone() bool {
+	if m.NormalizeMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.NormalizeMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.NormalizeMock.invocationsDone()
+}
+
+// MinimockNormalizeInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockNormalizeInspect() {
+	for _, e := range m.NormalizeMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to MediaProcessorMock.Normalize at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterNormalizeCounter := mm_atomic.LoadUint64(&m.afterNormalizeCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.NormalizeMock.defaultExpectation != nil && afterNormalizeCounter < 1 {
+		if m.NormalizeMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.Normalize at\n%s", m.NormalizeMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to MediaProcessorMock.Normalize at\n%s with params: %#v", m.NormalizeMock.defaultExpectation.expectationOrigins.origin, *m.NormalizeMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcNormalize != nil && afterNormalizeCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.Normalize at\n%s", m.funcNormalizeOrigin)
+	}
+
+	if !m.NormalizeMock.invocationsDone() && afterNormalizeCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.Normalize at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.NormalizeMock.expectedInvocations), m.NormalizeMock.expectedInvocationsOrigin, afterNormalizeCounter)
+	}
+}
+
+type mMediaProcessorMockPosterFrame struct {
+	optional           bool
+	mock               *MediaProcessorMock
+	defaultExpectation *MediaProcessorMockPosterFrameExpectation
+	expectations       []*MediaProcessorMockPosterFrameExpectation
+
+	callArgs []*MediaProcessorMockPosterFrameParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// MediaProcessorMockPosterFrameExpectation specifies expectation struct of the MediaProcessor.PosterFrame
+type MediaProcessorMockPosterFrameExpectation struct {
+	mock               *MediaProcessorMock
+	params             *MediaProcessorMockPosterFrameParams
+	paramPtrs          *MediaProcessorMockPosterFrameParamPtrs
+	expectationOrigins MediaProcessorMockPosterFrameExpectationOrigins
+	results            *MediaProcessorMockPosterFrameResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// MediaProcessorMockPosterFrameParams contains parameters of the MediaProcessor.PosterFrame
+type MediaProcessorMockPosterFrameParams struct {
+	ctx      context.Context
+	filepath string
+	opts     mm_service.PosterFrameOptions
+}
+
+// MediaProcessorMockPosterFrameParamPtrs contains pointers to parameters of the MediaProcessor.PosterFrame
+type MediaProcessorMockPosterFrameParamPtrs struct {
+	ctx      *context.Context
+	filepath *string
+	opts     *mm_service.PosterFrameOptions
+}
+
+// MediaProcessorMockPosterFrameResults contains results of the MediaProcessor.PosterFrame
+type MediaProcessorMockPosterFrameResults struct {
+	imageFilepath string
+	err           error
+}
+
+// MediaProcessorMockPosterFrameOrigins contains origins of expectations of the MediaProcessor.PosterFrame
+type MediaProcessorMockPosterFrameExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originFilepath string
+	originOpts     string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmPosterFrame *mMediaProcessorMockPosterFrame) Optional() *mMediaProcessorMockPosterFrame {
+	mmPosterFrame.optional = true
+	return mmPosterFrame
+}
+
+// Expect sets up expected params for MediaProcessor.PosterFrame
+func (mmPosterFrame *mMediaProcessorMockPosterFrame) Expect(ctx context.Context, filepath string, opts mm_service.PosterFrameOptions) *mMediaProcessorMockPosterFrame {
+	if mmPosterFrame.mock.funcPosterFrame != nil {
+		mmPosterFrame.mock.t.Fatalf("MediaProcessorMock.PosterFrame mock is already set by Set")
+	}
+
+	if mmPosterFrame.defaultExpectation == nil {
+		mmPosterFrame.defaultExpectation = &MediaProcessorMockPosterFrameExpectation{}
+	}
+
+	if mmPosterFrame.defaultExpectation.paramPtrs != nil {
+		mmPosterFrame.mock.t.Fatalf("MediaProcessorMock.PosterFrame mock is already set by ExpectParams functions")
+	}
+
+	mmPosterFrame.defaultExpectation.params = &MediaProcessorMockPosterFrameParams{ctx, filepath, opts}
+	mmPosterFrame.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmPosterFrame.expectations {
+		if minimock.Equal(e.params, mmPosterFrame.defaultExpectation.params) {
+			mmPosterFrame.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmPosterFrame.defaultExpectation.params)
+		}
+	}
+
+	return mmPosterFrame
+}
+
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.PosterFrame
+func (mmPosterFrame *mMediaProcessorMockPosterFrame) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockPosterFrame {
+	if mmPosterFrame.mock.funcPosterFrame != nil {
+		mmPosterFrame.mock.t.Fatalf("MediaProcessorMock.PosterFrame mock is already set by Set")
+	}
+
+	if mmPosterFrame.defaultExpectation == nil {
+		mmPosterFrame.defaultExpectation = &MediaProcessorMockPosterFrameExpectation{}
+	}
+
+	if mmPosterFrame.defaultExpectation.params != nil {
+		mmPosterFrame.mock.t.Fatalf("MediaProcessorMock.PosterFrame mock is already set by Expect")
+	}
+
+	if mmPosterFrame.defaultExpectation.paramPtrs == nil {
+		mmPosterFrame.defaultExpectation.paramPtrs = &MediaProcessorMockPosterFrameParamPtrs{}
+	}
+	mmPosterFrame.defaultExpectation.paramPtrs.ctx = &ctx
+	mmPosterFrame.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmPosterFrame
+}
+
+// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.PosterFrame
+func (mmPosterFrame *mMediaProcessorMockPosterFrame) ExpectFilepathParam2(filepath string) *mMediaProcessorMockPosterFrame {
+	if mmPosterFrame.mock.funcPosterFrame != nil {
+		mmPosterFrame.mock.t.Fatalf("MediaProcessorMock.PosterFrame mock is already set by Set")
+	}
+
+	if mmPosterFrame.defaultExpectation == nil {
+		mmPosterFrame.defaultExpectation = &MediaProcessorMockPosterFrameExpectation{}
+	}
+
+	if mmPosterFrame.defaultExpectation.params != nil {
+		mmPosterFrame.mock.t.Fatalf("MediaProcessorMock.PosterFrame mock is already set by Expect")
+	}
+
+	if mmPosterFrame.defaultExpectation.paramPtrs == nil {
+		mmPosterFrame.defaultExpectation.paramPtrs = &MediaProcessorMockPosterFrameParamPtrs{}
+	}
+	mmPosterFrame.defaultExpectation.paramPtrs.filepath = &filepath
+	mmPosterFrame.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
+
+	return mmPosterFrame
+}
+
+// ExpectOptsParam3 sets up expected param opts for MediaProcessor.PosterFrame
+func (mmPosterFrame *mMediaProcessorMockPosterFrame) ExpectOptsParam3(opts mm_service.PosterFrameOptions) *mMediaProcessorMockPosterFrame {
+	if mmPosterFrame.mock.funcPosterFrame != nil {
+		mmPosterFrame.mock.t.Fatalf("MediaProcessorMock.PosterFrame mock is already set by Set")
+	}
+
+	if mmPosterFrame.defaultExpectation == nil {
+		mmPosterFrame.defaultExpectation = &MediaProcessorMockPosterFrameExpectation{}
+	}
+
+	if mmPosterFrame.defaultExpectation.params != nil {
+		mmPosterFrame.mock.t.Fatalf("MediaProcessorMock.PosterFrame mock is already set by Expect")
+	}
+
+	if mmPosterFrame.defaultExpectation.paramPtrs == nil {
+		mmPosterFrame.defaultExpectation.paramPtrs = &MediaProcessorMockPosterFrameParamPtrs{}
+	}
+	mmPosterFrame.defaultExpectation.paramPtrs.opts = &opts
+	mmPosterFrame.defaultExpectation.expectationOrigins.originOpts = minimock.CallerInfo(1)
+
+	return mmPosterFrame
+}
+
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.PosterFrame
+func (mmPosterFrame *mMediaProcessorMockPosterFrame) Inspect(f func(ctx context.Context, filepath string, opts mm_service.PosterFrameOptions)) *mMediaProcessorMockPosterFrame {
+	if mmPosterFrame.mock.inspectFuncPosterFrame != nil {
+		mmPosterFrame.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.PosterFrame")
+	}
+
+	mmPosterFrame.mock.inspectFuncPosterFrame = f
+
+	return mmPosterFrame
+}
+
+// Return sets up results that will be returned by MediaProcessor.PosterFrame
+func (mmPosterFrame *mMediaProcessorMockPosterFrame) Return(imageFilepath string, err error) *MediaProcessorMock {
+	if mmPosterFrame.mock.funcPosterFrame != nil {
+		mmPosterFrame.mock.t.Fatalf("MediaProcessorMock.PosterFrame mock is already set by Set")
+	}
+
+	if mmPosterFrame.defaultExpectation == nil {
+		mmPosterFrame.defaultExpectation = &MediaProcessorMockPosterFrameExpectation{mock: mmPosterFrame.mock}
+	}
+	mmPosterFrame.defaultExpectation.results = &MediaProcessorMockPosterFrameResults{imageFilepath, err}
+	mmPosterFrame.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmPosterFrame.mock
+}
+
+// Set uses given function f to mock the MediaProcessor.PosterFrame method
+func (mmPosterFrame *mMediaProcessorMockPosterFrame) Set(f func(ctx context.Context, filepath string, opts mm_service.PosterFrameOptions) (imageFilepath string, err error)) *MediaProcessorMock {
+	if mmPosterFrame.defaultExpectation != nil {
+		mmPosterFrame.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.PosterFrame method")
+	}
+
+	if len(mmPosterFrame.expectations) > 0 {
+		mmPosterFrame.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.PosterFrame method")
+	}
+
+	mmPosterFrame.mock.funcPosterFrame = f
+	mmPosterFrame.mock.funcPosterFrameOrigin = minimock.CallerInfo(1)
+	return mmPosterFrame.mock
+}
+
+// When sets expectation for the MediaProcessor.PosterFrame which will trigger the result defined by the following
+// Then helper
+func (mmPosterFrame *mMediaProcessorMockPosterFrame) When(ctx context.Context, filepath string, opts mm_service.PosterFrameOptions) *MediaProcessorMockPosterFrameExpectation {
+	if mmPosterFrame.mock.funcPosterFrame != nil {
+		mmPosterFrame.mock.t.Fatalf("MediaProcessorMock.PosterFrame mock is already set by Set")
+	}
+
+	expectation := &MediaProcessorMockPosterFrameExpectation{
+		mock:               mmPosterFrame.mock,
+		params:             &MediaProcessorMockPosterFrameParams{ctx, filepath, opts},
+		expectationOrigins: MediaProcessorMockPosterFrameExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmPosterFrame.expectations = append(mmPosterFrame.expectations, expectation)
+	return expectation
+}
+
+// Then sets up MediaProcessor.PosterFrame return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockPosterFrameExpectation) Then(imageFilepath string, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockPosterFrameResults{imageFilepath, err}
+	return e.mock
+}
+
+// Times sets number of times MediaProcessor.PosterFrame should be invoked
+func (mmPosterFrame *mMediaProcessorMockPosterFrame) Times(n uint64) *mMediaProcessorMockPosterFrame {
+	if n == 0 {
+		mmPosterFrame.mock.t.Fatalf("Times of MediaProcessorMock.PosterFrame mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmPosterFrame.expectedInvocations, n)
+	mmPosterFrame.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmPosterFrame
+}
+
+func (mmPosterFrame *mMediaProcessorMockPosterFrame) invocationsDone() bool {
+	if len(mmPosterFrame.expectations) == 0 && mmPosterFrame.defaultExpectation == nil && mmPosterFrame.mock.funcPosterFrame == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmPosterFrame.mock.afterPosterFrameCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmPosterFrame.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// PosterFrame implements mm_service.MediaProcessor
+func (mmPosterFrame *MediaProcessorMock) PosterFrame(ctx context.Context, filepath string, opts mm_service.PosterFrameOptions) (imageFilepath string, err error) {
+	mm_atomic.AddUint64(&mmPosterFrame.beforePosterFrameCounter, 1)
+	defer mm_atomic.AddUint64(&mmPosterFrame.afterPosterFrameCounter, 1)
+
+	mmPosterFrame.t.Helper()
+
+	if mmPosterFrame.inspectFuncPosterFrame != nil {
+		mmPosterFrame.inspectFuncPosterFrame(ctx, filepath, opts)
+	}
+
+	mm_params := MediaProcessorMockPosterFrameParams{ctx, filepath, opts}
+
+	// Record call args
+	mmPosterFrame.PosterFrameMock.mutex.Lock()
+	mmPosterFrame.PosterFrameMock.callArgs = append(mmPosterFrame.PosterFrameMock.callArgs, &mm_params)
+	mmPosterFrame.PosterFrameMock.mutex.Unlock()
+
+	for _, e := range mmPosterFrame.PosterFrameMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.imageFilepath, e.results.err
+		}
+	}
+
+	if mmPosterFrame.PosterFrameMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmPosterFrame.PosterFrameMock.defaultExpectation.Counter, 1)
+		mm_want := mmPosterFrame.PosterFrameMock.defaultExpectation.params
+		mm_want_ptrs := mmPosterFrame.PosterFrameMock.defaultExpectation.paramPtrs
+
+		mm_got := MediaProcessorMockPosterFrameParams{ctx, filepath, opts}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmPosterFrame.t.Errorf("MediaProcessorMock.PosterFrame got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmPosterFrame.PosterFrameMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.filepath != nil && !minimock.Equal(*mm_want_ptrs.filepath, mm_got.filepath) {
+				mmPosterFrame.t.Errorf("MediaProcessorMock.PosterFrame got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmPosterFrame.PosterFrameMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
+			}
+
+			if mm_want_ptrs.opts != nil && !minimock.Equal(*mm_want_ptrs.opts, mm_got.opts) {
+				mmPosterFrame.t.Errorf("MediaProcessorMock.PosterFrame got unexpected parameter opts, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmPosterFrame.PosterFrameMock.defaultExpectation.expectationOrigins.originOpts, *mm_want_ptrs.opts, mm_got.opts, minimock.Diff(*mm_want_ptrs.opts, mm_got.opts))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmPosterFrame.t.Errorf("MediaProcessorMock.PosterFrame got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmPosterFrame.PosterFrameMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmPosterFrame.PosterFrameMock.defaultExpectation.results
+		if mm_results == nil {
+			mmPosterFrame.t.Fatal("No results are set for the MediaProcessorMock.PosterFrame")
+		}
+		return (*mm_results).imageFilepath, (*mm_results).err
+	}
+	if mmPosterFrame.funcPosterFrame != nil {
+		return mmPosterFrame.funcPosterFrame(ctx, filepath, opts)
+	}
+	mmPosterFrame.t.Fatalf("Unexpected call to MediaProcessorMock.PosterFrame. %v %v %v", ctx, filepath, opts)
+	return
+}
+
+// PosterFrameAfterCounter returns a count of finished MediaProcessorMock.PosterFrame invocations
+func (mmPosterFrame *MediaProcessorMock) PosterFrameAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPosterFrame.afterPosterFrameCounter)
+}
+
+// PosterFrameBeforeCounter returns a count of MediaProcessorMock.PosterFrame invocations
+func (mmPosterFrame *MediaProcessorMock) PosterFrameBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPosterFrame.beforePosterFrameCounter)
+}
+
+// Calls returns a list of arguments used in each call to MediaProcessorMock.PosterFrame.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmPosterFrame *mMediaProcessorMockPosterFrame) Calls() []*MediaProcessorMockPosterFrameParams {
+	mmPosterFrame.mutex.RLock()
+
+	argCopy := make([]*MediaProcessorMockPosterFrameParams, len(mmPosterFrame.callArgs))
+	copy(argCopy, mmPosterFrame.callArgs)
+
+	mmPosterFrame.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockPosterFrameDone returns true if the count of the PosterFrame invocations corresponds
+// the number of defined expectations
+func (m *MediaProcessorMock) MinimockPosterFrameDone() bool {
+	if m.PosterFrameMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.PosterFrameMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.PosterFrameMock.invocationsDone()
+}
+
+// MinimockPosterFrameInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockPosterFrameInspect() {
+	for _, e := range m.PosterFrameMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to MediaProcessorMock.PosterFrame at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterPosterFrameCounter := mm_atomic.LoadUint64(&m.afterPosterFrameCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.PosterFrameMock.defaultExpectation != nil && afterPosterFrameCounter < 1 {
+		if m.PosterFrameMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.PosterFrame at\n%s", m.PosterFrameMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to MediaProcessorMock.PosterFrame at\n%s with params: %#v", m.PosterFrameMock.defaultExpectation.expectationOrigins.origin, *m.PosterFrameMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcPosterFrame != nil && afterPosterFrameCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.PosterFrame at\n%s", m.funcPosterFrameOrigin)
+	}
+
+	if !m.PosterFrameMock.invocationsDone() && afterPosterFrameCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.PosterFrame at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.PosterFrameMock.expectedInvocations), m.PosterFrameMock.expectedInvocationsOrigin, afterPosterFrameCounter)
+	}
+}
+
+type mMediaProcessorMockPreviewClip struct {
+	optional           bool
+	mock               *MediaProcessorMock
+	defaultExpectation *MediaProcessorMockPreviewClipExpectation
+	expectations       []*MediaProcessorMockPreviewClipExpectation
+
+	callArgs []*MediaProcessorMockPreviewClipParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// MediaProcessorMockPreviewClipExpectation specifies expectation struct of the MediaProcessor.PreviewClip
+type MediaProcessorMockPreviewClipExpectation struct {
+	mock               *MediaProcessorMock
+	params             *MediaProcessorMockPreviewClipParams
+	paramPtrs          *MediaProcessorMockPreviewClipParamPtrs
+	expectationOrigins MediaProcessorMockPreviewClipExpectationOrigins
+	results            *MediaProcessorMockPreviewClipResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// MediaProcessorMockPreviewClipParams contains parameters of the MediaProcessor.PreviewClip
+type MediaProcessorMockPreviewClipParams struct {
+	ctx      context.Context
+	filepath string
+	opts     mm_service.PreviewClipOptions
+}
+
+// MediaProcessorMockPreviewClipParamPtrs contains pointers to parameters of the MediaProcessor.PreviewClip
+type MediaProcessorMockPreviewClipParamPtrs struct {
+	ctx      *context.Context
+	filepath *string
+	opts     *mm_service.PreviewClipOptions
+}
+
+// MediaProcessorMockPreviewClipResults contains results of the MediaProcessor.PreviewClip
+type MediaProcessorMockPreviewClipResults struct {
+	resultFilepath string
+	err            error
+}
+
+// MediaProcessorMockPreviewClipOrigins contains origins of expectations of the MediaProcessor.PreviewClip
+type MediaProcessorMockPreviewClipExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originFilepath string
+	originOpts     string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmPreviewClip *mMediaProcessorMockPreviewClip) Optional() *mMediaProcessorMockPreviewClip {
+	mmPreviewClip.optional = true
+	return mmPreviewClip
+}
+
+// Expect sets up expected params for MediaProcessor.PreviewClip
+func (mmPreviewClip *mMediaProcessorMockPreviewClip) Expect(ctx context.Context, filepath string, opts mm_service.PreviewClipOptions) *mMediaProcessorMockPreviewClip {
+	if mmPreviewClip.mock.funcPreviewClip != nil {
+		mmPreviewClip.mock.t.Fatalf("MediaProcessorMock.PreviewClip mock is already set by Set")
+	}
+
+	if mmPreviewClip.defaultExpectation == nil {
+		mmPreviewClip.defaultExpectation = &MediaProcessorMockPreviewClipExpectation{}
+	}
+
+	if mmPreviewClip.defaultExpectation.paramPtrs != nil {
+		mmPreviewClip.mock.t.Fatalf("MediaProcessorMock.PreviewClip mock is already set by ExpectParams functions")
+	}
+
+	mmPreviewClip.defaultExpectation.params = &MediaProcessorMockPreviewClipParams{ctx, filepath, opts}
+	mmPreviewClip.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmPreviewClip.expectations {
+		if minimock.Equal(e.params, mmPreviewClip.defaultExpectation.params) {
+			mmPreviewClip.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmPreviewClip.defaultExpectation.params)
+		}
+	}
+
+	return mmPreviewClip
+}
+
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.PreviewClip
+func (mmPreviewClip *mMediaProcessorMockPreviewClip) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockPreviewClip {
+	if mmPreviewClip.mock.funcPreviewClip != nil {
+		mmPreviewClip.mock.t.Fatalf("MediaProcessorMock.PreviewClip mock is already set by Set")
+	}
+
+	if mmPreviewClip.defaultExpectation == nil {
+		mmPreviewClip.defaultExpectation = &MediaProcessorMockPreviewClipExpectation{}
+	}
+
+	if mmPreviewClip.defaultExpectation.params != nil {
+		mmPreviewClip.mock.t.Fatalf("MediaProcessorMock.PreviewClip mock is already set by Expect")
+	}
+
+	if mmPreviewClip.defaultExpectation.paramPtrs == nil {
+		mmPreviewClip.defaultExpectation.paramPtrs = &MediaProcessorMockPreviewClipParamPtrs{}
+	}
+	mmPreviewClip.defaultExpectation.paramPtrs.ctx = &ctx
+	mmPreviewClip.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmPreviewClip
+}
+
+// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.PreviewClip
+func (mmPreviewClip *mMediaProcessorMockPreviewClip) ExpectFilepathParam2(filepath string) *mMediaProcessorMockPreviewClip {
+	if mmPreviewClip.mock.funcPreviewClip != nil {
+		mmPreviewClip.mock.t.Fatalf("MediaProcessorMock.PreviewClip mock is already set by Set")
+	}
+
+	if mmPreviewClip.defaultExpectation == nil {
+		mmPreviewClip.defaultExpectation = &MediaProcessorMockPreviewClipExpectation{}
+	}
+
+	if mmPreviewClip.defaultExpectation.params != nil {
+		mmPreviewClip.mock.t.Fatalf("MediaProcessorMock.PreviewClip mock is already set by Expect")
+	}
+
+	if mmPreviewClip.defaultExpectation.paramPtrs == nil {
+		mmPreviewClip.defaultExpectation.paramPtrs = &MediaProcessorMockPreviewClipParamPtrs{}
+	}
+	mmPreviewClip.defaultExpectation.paramPtrs.filepath = &filepath
+	mmPreviewClip.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
+
+	return mmPreviewClip
+}
+
+// ExpectOptsParam3 sets up expected param opts for MediaProcessor.PreviewClip
+func (mmPreviewClip *mMediaProcessorMockPreviewClip) ExpectOptsParam3(opts mm_service.PreviewClipOptions) *mMediaProcessorMockPreviewClip {
+	if mmPreviewClip.mock.funcPreviewClip != nil {
+		mmPreviewClip.mock.t.Fatalf("MediaProcessorMock.PreviewClip mock is already set by Set")
+	}
+
+	if mmPreviewClip.defaultExpectation == nil {
+		mmPreviewClip.defaultExpectation = &MediaProcessorMockPreviewClipExpectation{}
+	}
+
+	if mmPreviewClip.defaultExpectation.params != nil {
+		mmPreviewClip.mock.t.Fatalf("MediaProcessorMock.PreviewClip mock is already set by Expect")
+	}
+
+	if mmPreviewClip.defaultExpectation.paramPtrs == nil {
+		mmPreviewClip.defaultExpectation.paramPtrs = &MediaProcessorMockPreviewClipParamPtrs{}
+	}
+	mmPreviewClip.defaultExpectation.paramPtrs.opts = &opts
+	mmPreviewClip.defaultExpectation.expectationOrigins.originOpts = minimock.CallerInfo(1)
+
+	return mmPreviewClip
+}
+
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.PreviewClip
+func (mmPreviewClip *mMediaProcessorMockPreviewClip) Inspect(f func(ctx context.Context, filepath string, opts mm_service.PreviewClipOptions)) *mMediaProcessorMockPreviewClip {
+	if mmPreviewClip.mock.inspectFuncPreviewClip != nil {
+		mmPreviewClip.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.PreviewClip")
+	}
+
+	mmPreviewClip.mock.inspectFuncPreviewClip = f
+
+	return mmPreviewClip
+}
+
+// Return sets up results that will be returned by MediaProcessor.PreviewClip
+func (mmPreviewClip *mMediaProcessorMockPreviewClip) Return(resultFilepath string, err error) *MediaProcessorMock {
+	if mmPreviewClip.mock.funcPreviewClip != nil {
+		mmPreviewClip.mock.t.Fatalf("MediaProcessorMock.PreviewClip mock is already set by Set")
+	}
+
+	if mmPreviewClip.defaultExpectation == nil {
+		mmPreviewClip.defaultExpectation = &MediaProcessorMockPreviewClipExpectation{mock: mmPreviewClip.mock}
+	}
+	mmPreviewClip.defaultExpectation.results = &MediaProcessorMockPreviewClipResults{resultFilepath, err}
+	mmPreviewClip.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmPreviewClip.mock
+}
+
+// Set uses given function f to mock the MediaProcessor.PreviewClip method
+func (mmPreviewClip *mMediaProcessorMockPreviewClip) Set(f func(ctx context.Context, filepath string, opts mm_service.PreviewClipOptions) (resultFilepath string, err error)) *MediaProcessorMock {
+	if mmPreviewClip.defaultExpectation != nil {
+		mmPreviewClip.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.PreviewClip method")
+	}
+
+	if len(mmPreviewClip.expectations) > 0 {
+		mmPreviewClip.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.PreviewClip method")
+	}
+
+	mmPreviewClip.mock.funcPreviewClip = f
+	mmPreviewClip.mock.funcPreviewClipOrigin = minimock.CallerInfo(1)
+	return mmPreviewClip.mock
+}
+
+// When sets expectation for the MediaProcessor.PreviewClip which will trigger the result defined by the following
+// Then helper
+func (mmPreviewClip *mMediaProcessorMockPreviewClip) When(ctx context.Context, filepath string, opts mm_service.PreviewClipOptions) *MediaProcessorMockPreviewClipExpectation {
+	if mmPreviewClip.mock.funcPreviewClip != nil {
+		mmPreviewClip.mock.t.Fatalf("MediaProcessorMock.PreviewClip mock is already set by Set")
+	}
+
+	expectation := &MediaProcessorMockPreviewClipExpectation{
+		mock:               mmPreviewClip.mock,
+		params:             &MediaProcessorMockPreviewClipParams{ctx, filepath, opts},
+		expectationOrigins: MediaProcessorMockPreviewClipExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmPreviewClip.expectations = append(mmPreviewClip.expectations, expectation)
+	return expectation
+}
+
+// Then sets up MediaProcessor.PreviewClip return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockPreviewClipExpectation) Then(resultFilepath string, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockPreviewClipResults{resultFilepath, err}
+	return e.mock
+}
+
+// Times sets number of times MediaProcessor.PreviewClip should be invoked
+func (mmPreviewClip *mMediaProcessorMockPreviewClip) Times(n uint64) *mMediaProcessorMockPreviewClip {
+	if n == 0 {
+		mmPreviewClip.mock.t.Fatalf("Times of MediaProcessorMock.PreviewClip mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmPreviewClip.expectedInvocations, n)
+	mmPreviewClip.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmPreviewClip
+}
+
+func (mmPreviewClip *mMediaProcessorMockPreviewClip) invocationsDone() bool {
+	if len(mmPreviewClip.expectations) == 0 && mmPreviewClip.defaultExpectation == nil && mmPreviewClip.mock.funcPreviewClip == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmPreviewClip.mock.afterPreviewClipCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmPreviewClip.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// PreviewClip implements mm_service.MediaProcessor
+func (mmPreviewClip *MediaProcessorMock) PreviewClip(ctx context.Context, filepath string, opts mm_service.PreviewClipOptions) (resultFilepath string, err error) {
+	mm_atomic.AddUint64(&mmPreviewClip.beforePreviewClipCounter, 1)
+	defer mm_atomic.AddUint64(&mmPreviewClip.afterPreviewClipCounter, 1)
+
+	mmPreviewClip.t.Helper()
+
+	if mmPreviewClip.inspectFuncPreviewClip != nil {
+		mmPreviewClip.inspectFuncPreviewClip(ctx, filepath, opts)
+	}
+
+	mm_params := MediaProcessorMockPreviewClipParams{ctx, filepath, opts}
+
+	// Record call args
+	mmPreviewClip.PreviewClipMock.mutex.Lock()
+	mmPreviewClip.PreviewClipMock.callArgs = append(mmPreviewClip.PreviewClipMock.callArgs, &mm_params)
+	mmPreviewClip.PreviewClipMock.mutex.Unlock()
+
+	for _, e := range mmPreviewClip.PreviewClipMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.resultFilepath, e.results.err
+		}
+	}
+
+	if mmPreviewClip.PreviewClipMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmPreviewClip.PreviewClipMock.defaultExpectation.Counter, 1)
+		mm_want := mmPreviewClip.PreviewClipMock.defaultExpectation.params
+		mm_want_ptrs := mmPreviewClip.PreviewClipMock.defaultExpectation.paramPtrs
+
+		mm_got := MediaProcessorMockPreviewClipParams{ctx, filepath, opts}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmPreviewClip.t.Errorf("MediaProcessorMock.PreviewClip got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmPreviewClip.PreviewClipMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.filepath != nil && !minimock.Equal(*mm_want_ptrs.filepath, mm_got.filepath) {
+				mmPreviewClip.t.Errorf("MediaProcessorMock.PreviewClip got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmPreviewClip.PreviewClipMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
+			}
+
+			if mm_want_ptrs.opts != nil && !minimock.Equal(*mm_want_ptrs.opts, mm_got.opts) {
+				mmPreviewClip.t.Errorf("MediaProcessorMock.PreviewClip got unexpected parameter opts, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmPreviewClip.PreviewClipMock.defaultExpectation.expectationOrigins.originOpts, *mm_want_ptrs.opts, mm_got.opts, minimock.Diff(*mm_want_ptrs.opts, mm_got.opts))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmPreviewClip.t.Errorf("MediaProcessorMock.PreviewClip got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmPreviewClip.PreviewClipMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmPreviewClip.PreviewClipMock.defaultExpectation.results
+		if mm_results == nil {
+			mmPreviewClip.t.Fatal("No results are set for the MediaProcessorMock.PreviewClip")
+		}
+		return (*mm_results).resultFilepath, (*mm_results).err
+	}
+	if mmPreviewClip.funcPreviewClip != nil {
+		return mmPreviewClip.funcPreviewClip(ctx, filepath, opts)
+	}
+	mmPreviewClip.t.Fatalf("Unexpected call to MediaProcessorMock.PreviewClip. %v %v %v", ctx, filepath, opts)
+	return
+}
+
+// PreviewClipAfterCounter returns a count of finished MediaProcessorMock.PreviewClip invocations
+func (mmPreviewClip *MediaProcessorMock) PreviewClipAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPreviewClip.afterPreviewClipCounter)
+}
+
+// PreviewClipBeforeCounter returns a count of MediaProcessorMock.PreviewClip invocations
+func (mmPreviewClip *MediaProcessorMock) PreviewClipBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPreviewClip.beforePreviewClipCounter)
+}
+
+// Calls returns a list of arguments used in each call to MediaProcessorMock.PreviewClip.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmPreviewClip *mMediaProcessorMockPreviewClip) Calls() []*MediaProcessorMockPreviewClipParams {
+	mmPreviewClip.mutex.RLock()
+
+	argCopy := make([]*MediaProcessorMockPreviewClipParams, len(mmPreviewClip.callArgs))
+	copy(argCopy, mmPreviewClip.callArgs)
+
+	mmPreviewClip.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockPreviewClipDone returns true if the count of the PreviewClip invocations corresponds
+// the number of defined expectations
+func (m *MediaProcessorMock) MinimockPreviewClipDone() bool {
+	if m.PreviewClipMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.PreviewClipMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.PreviewClipMock.invocationsDone()
+}
+
+// MinimockPreviewClipInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockPreviewClipInspect() {
+	for _, e := range m.PreviewClipMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to MediaProcessorMock.PreviewClip at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterPreviewClipCounter := mm_atomic.LoadUint64(&m.afterPreviewClipCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.PreviewClipMock.defaultExpectation != nil && afterPreviewClipCounter < 1 {
+		if m.PreviewClipMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.PreviewClip at\n%s", m.PreviewClipMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to MediaProcessorMock.PreviewClip at\n%s with params: %#v", m.PreviewClipMock.defaultExpectation.expectationOrigins.origin, *m.PreviewClipMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcPreviewClip != nil && afterPreviewClipCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.PreviewClip at\n%s", m.funcPreviewClipOrigin)
+	}
+
+	if !m.PreviewClipMock.invocationsDone() && afterPreviewClipCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.PreviewClip at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.PreviewClipMock.expectedInvocations), m.PreviewClipMock.expectedInvocationsOrigin, afterPreviewClipCounter)
+	}
+}
+
+type mMediaProcessorMockRenderWaveform struct {
+	optional           bool
+	mock               *MediaProcessorMock
+	defaultExpectation *MediaProcessorMockRenderWaveformExpectation
+	expectations       []*MediaProcessorMockRenderWaveformExpectation
+
+	callArgs []*MediaProcessorMockRenderWaveformParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// MediaProcessorMockRenderWaveformExpectation specifies expectation struct of the MediaProcessor.RenderWaveform
+type MediaProcessorMockRenderWaveformExpectation struct {
+	mock               *MediaProcessorMock
+	params             *MediaProcessorMockRenderWaveformParams
+	paramPtrs          *MediaProcessorMockRenderWaveformParamPtrs
+	expectationOrigins MediaProcessorMockRenderWaveformExpectationOrigins
+	results            *MediaProcessorMockRenderWaveformResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// MediaProcessorMockRenderWaveformParams contains parameters of the MediaProcessor.RenderWaveform
+type MediaProcessorMockRenderWaveformParams struct {
+	ctx      context.Context
+	filepath string
+	opts     mm_service.WaveformImageOptions
+}
+
+// MediaProcessorMockRenderWaveformParamPtrs contains pointers to parameters of the MediaProcessor.RenderWaveform
+type MediaProcessorMockRenderWaveformParamPtrs struct {
+	ctx      *context.Context
+	filepath *string
+	opts     *mm_service.WaveformImageOptions
+}
+
+// MediaProcessorMockRenderWaveformResults contains results of the MediaProcessor.RenderWaveform
+type MediaProcessorMockRenderWaveformResults struct {
+	imageFilepath string
+	err           error
+}
+
+// MediaProcessorMockRenderWaveformOrigins contains origins of expectations of the MediaProcessor.RenderWaveform
+type MediaProcessorMockRenderWaveformExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originFilepath string
+	originOpts     string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmRenderWaveform *mMediaProcessorMockRenderWaveform) Optional() *mMediaProcessorMockRenderWaveform {
+	mmRenderWaveform.optional = true
+	return mmRenderWaveform
+}
+
+// Expect sets up expected params for MediaProcessor.RenderWaveform
+func (mmRenderWaveform *mMediaProcessorMockRenderWaveform) Expect(ctx context.Context, filepath string, opts mm_service.WaveformImageOptions) *mMediaProcessorMockRenderWaveform {
+	if mmRenderWaveform.mock.funcRenderWaveform != nil {
+		mmRenderWaveform.mock.t.Fatalf("MediaProcessorMock.RenderWaveform mock is already set by Set")
+	}
+
+	if mmRenderWaveform.defaultExpectation == nil {
+		mmRenderWaveform.defaultExpectation = &MediaProcessorMockRenderWaveformExpectation{}
+	}
+
+	if mmRenderWaveform.defaultExpectation.paramPtrs != nil {
+		mmRenderWaveform.mock.t.Fatalf("MediaProcessorMock.RenderWaveform mock is already set by ExpectParams functions")
+	}
+
+	mmRenderWaveform.defaultExpectation.params = &MediaProcessorMockRenderWaveformParams{ctx, filepath, opts}
+	mmRenderWaveform.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmRenderWaveform.expectations {
+		if minimock.Equal(e.params, mmRenderWaveform.defaultExpectation.params) {
+			mmRenderWaveform.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmRenderWaveform.defaultExpectation.params)
+		}
+	}
+
+	return mmRenderWaveform
+}
+
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.RenderWaveform
+func (mmRenderWaveform *mMediaProcessorMockRenderWaveform) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockRenderWaveform {
+	if mmRenderWaveform.mock.funcRenderWaveform != nil {
+		mmRenderWaveform.mock.t.Fatalf("MediaProcessorMock.RenderWaveform mock is already set by Set")
+	}
+
+	if mmRenderWaveform.defaultExpectation == nil {
+		mmRenderWaveform.defaultExpectation = &MediaProcessorMockRenderWaveformExpectation{}
+	}
+
+	if mmRenderWaveform.defaultExpectation.params != nil {
+		mmRenderWaveform.mock.t.Fatalf("MediaProcessorMock.RenderWaveform mock is already set by Expect")
+	}
+
+	if mmRenderWaveform.defaultExpectation.paramPtrs == nil {
+		mmRenderWaveform.defaultExpectation.paramPtrs = &MediaProcessorMockRenderWaveformParamPtrs{}
+	}
+	mmRenderWaveform.defaultExpectation.paramPtrs.ctx = &ctx
+	mmRenderWaveform.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmRenderWaveform
+}
+
+// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.RenderWaveform
+func (mmRenderWaveform *mMediaProcessorMockRenderWaveform) ExpectFilepathParam2(filepath string) *mMediaProcessorMockRenderWaveform {
+	if mmRenderWaveform.mock.funcRenderWaveform != nil {
+		mmRenderWaveform.mock.t.Fatalf("MediaProcessorMock.RenderWaveform mock is already set by Set")
+	}
+
+	if mmRenderWaveform.defaultExpectation == nil {
+		mmRenderWaveform.defaultExpectation = &MediaProcessorMockRenderWaveformExpectation{}
+	}
+
+	if mmRenderWaveform.defaultExpectation.params != nil {
+		mmRenderWaveform.mock.t.Fatalf("MediaProcessorMock.RenderWaveform mock is already set by Expect")
+	}
+
+	if mmRenderWaveform.defaultExpectation.paramPtrs == nil {
+		mmRenderWaveform.defaultExpectation.paramPtrs = &MediaProcessorMockRenderWaveformParamPtrs{}
+	}
+	mmRenderWaveform.defaultExpectation.paramPtrs.filepath = &filepath
+	mmRenderWaveform.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
+
+	return mmRenderWaveform
+}
+
+// ExpectOptsParam3 sets up expected param opts for MediaProcessor.RenderWaveform
+func (mmRenderWaveform *mMediaProcessorMockRenderWaveform) ExpectOptsParam3(opts mm_service.WaveformImageOptions) *mMediaProcessorMockRenderWaveform {
+	if mmRenderWaveform.mock.funcRenderWaveform != nil {
+		mmRenderWaveform.mock.t.Fatalf("MediaProcessorMock.RenderWaveform mock is already set by Set")
+	}
+
+	if mmRenderWaveform.defaultExpectation == nil {
+		mmRenderWaveform.defaultExpectation = &MediaProcessorMockRenderWaveformExpectation{}
+	}
+
+	if mmRenderWaveform.defaultExpectation.params != nil {
+		mmRenderWaveform.mock.t.Fatalf("MediaProcessorMock.RenderWaveform mock is already set by Expect")
+	}
+
+	if mmRenderWaveform.defaultExpectation.paramPtrs == nil {
+		mmRenderWaveform.defaultExpectation.paramPtrs = &MediaProcessorMockRenderWaveformParamPtrs{}
+	}
+	mmRenderWaveform.defaultExpectation.paramPtrs.opts = &opts
+	mmRenderWaveform.defaultExpectation.expectationOrigins.originOpts = minimock.CallerInfo(1)
+
+	return mmRenderWaveform
+}
+
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.RenderWaveform
+func (mmRenderWaveform *mMediaProcessorMockRenderWaveform) Inspect(f func(ctx context.Context, filepath string, opts mm_service.WaveformImageOptions)) *mMediaProcessorMockRenderWaveform {
+	if mmRenderWaveform.mock.inspectFuncRenderWaveform != nil {
+		mmRenderWaveform.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.RenderWaveform")
+	}
+
+	mmRenderWaveform.mock.inspectFuncRenderWaveform = f
+
+	return mmRenderWaveform
+}
+
+// Return sets up results that will be returned by MediaProcessor.RenderWaveform
+func (mmRenderWaveform *mMediaProcessorMockRenderWaveform) Return(imageFilepath string, err error) *MediaProcessorMock {
+	if mmRenderWaveform.mock.funcRenderWaveform != nil {
+		mmRenderWaveform.mock.t.Fatalf("MediaProcessorMock.RenderWaveform mock is already set by Set")
+	}
+
+	if mmRenderWaveform.defaultExpectation == nil {
+		mmRenderWaveform.defaultExpectation = &MediaProcessorMockRenderWaveformExpectation{mock: mmRenderWaveform.mock}
+	}
+	mmRenderWaveform.defaultExpectation.results = &MediaProcessorMockRenderWaveformResults{imageFilepath, err}
+	mmRenderWaveform.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmRenderWaveform.mock
+}
+
+// Set uses given function f to mock the MediaProcessor.RenderWaveform method
+func (mmRenderWaveform *mMediaProcessorMockRenderWaveform) Set(f func(ctx context.Context, filepath string, opts mm_service.WaveformImageOptions) (imageFilepath string, err error)) *MediaProcessorMock {
+	if mmRenderWaveform.defaultExpectation != nil {
+		mmRenderWaveform.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.RenderWaveform method")
+	}
+
+	if len(mmRenderWaveform.expectations) > 0 {
+		mmRenderWaveform.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.RenderWaveform method")
+	}
+
+	mmRenderWaveform.mock.funcRenderWaveform = f
+	mmRenderWaveform.mock.funcRenderWaveformOrigin = minimock.CallerInfo(1)
+	return mmRenderWaveform.mock
+}
+
+// When sets expectation for the MediaProcessor.RenderWaveform which will trigger the result defined by the following
+// Then helper
+func (mmRenderWaveform *mMediaProcessorMockRenderWaveform) When(ctx context.Context, filepath string, opts mm_service.WaveformImageOptions) *MediaProcessorMockRenderWaveformExpectation {
+	if mmRenderWaveform.mock.funcRenderWaveform != nil {
+		mmRenderWaveform.mock.t.Fatalf("MediaProcessorMock.RenderWaveform mock is already set by Set")
+	}
+
+	expectation := &MediaProcessorMockRenderWaveformExpectation{
+		mock:               mmRenderWaveform.mock,
+		params:             &MediaProcessorMockRenderWaveformParams{ctx, filepath, opts},
+		expectationOrigins: MediaProcessorMockRenderWaveformExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmRenderWaveform.expectations = append(mmRenderWaveform.expectations, expectation)
+	return expectation
+}
+
+// Then sets up MediaProcessor.RenderWaveform return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockRenderWaveformExpectation) Then(imageFilepath string, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockRenderWaveformResults{imageFilepath, err}
+	return e.mock
+}
+
+// Times sets number of times MediaProcessor.RenderWaveform should be invoked
+func (mmRenderWaveform *mMediaProcessorMockRenderWaveform) Times(n uint64) *mMediaProcessorMockRenderWaveform {
+	if n == 0 {
+		mmRenderWaveform.mock.t.Fatalf("Times of MediaProcessorMock.RenderWaveform mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmRenderWaveform.expectedInvocations, n)
+	mmRenderWaveform.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmRenderWaveform
+}
+
+func (mmRenderWaveform *mMediaProcessorMockRenderWaveform) invocationsDone() bool {
+	if len(mmRenderWaveform.expectations) == 0 && mmRenderWaveform.defaultExpectation == nil && mmRenderWaveform.mock.funcRenderWaveform == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmRenderWaveform.mock.afterRenderWaveformCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmRenderWaveform.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// RenderWaveform implements mm_service.MediaProcessor
+func (mmRenderWaveform *MediaProcessorMock) RenderWaveform(ctx context.Context, filepath string, opts mm_service.WaveformImageOptions) (imageFilepath string, err error) {
+	mm_atomic.AddUint64(&mmRenderWaveform.beforeRenderWaveformCounter, 1)
+	defer mm_atomic.AddUint64(&mmRenderWaveform.afterRenderWaveformCounter, 1)
+
+	mmRenderWaveform.t.Helper()
+
+	if mmRenderWaveform.inspectFuncRenderWaveform != nil {
+		mmRenderWaveform.inspectFuncRenderWaveform(ctx, filepath, opts)
+	}
+
+	mm_params := MediaProcessorMockRenderWaveformParams{ctx, filepath, opts}
+
+	// Record call args
+	mmRenderWaveform.RenderWaveformMock.mutex.Lock()
+	mmRenderWaveform.RenderWaveformMock.callArgs = append(mmRenderWaveform.RenderWaveformMock.callArgs, &mm_params)
+	mmRenderWaveform.RenderWaveformMock.mutex.Unlock()
+
+	for _, e := range mmRenderWaveform.RenderWaveformMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.imageFilepath, e.results.err
+		}
+	}
+
+	if mmRenderWaveform.RenderWaveformMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmRenderWaveform.RenderWaveformMock.defaultExpectation.Counter, 1)
+		mm_want := mmRenderWaveform.RenderWaveformMock.defaultExpectation.params
+		mm_want_ptrs := mmRenderWaveform.RenderWaveformMock.defaultExpectation.paramPtrs
+
+		mm_got := MediaProcessorMockRenderWaveformParams{ctx, filepath, opts}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmRenderWaveform.t.Errorf("MediaProcessorMock.RenderWaveform got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmRenderWaveform.RenderWaveformMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.filepath != nil && !minimock.Equal(*mm_want_ptrs.filepath, mm_got.filepath) {
+				mmRenderWaveform.t.Errorf("MediaProcessorMock.RenderWaveform got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmRenderWaveform.RenderWaveformMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
+			}
+
+			if mm_want_ptrs.opts != nil && !minimock.Equal(*mm_want_ptrs.opts, mm_got.opts) {
+				mmRenderWaveform.t.Errorf("MediaProcessorMock.RenderWaveform got unexpected parameter opts, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmRenderWaveform.RenderWaveformMock.defaultExpectation.expectationOrigins.originOpts, *mm_want_ptrs.opts, mm_got.opts, minimock.Diff(*mm_want_ptrs.opts, mm_got.opts))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmRenderWaveform.t.Errorf("MediaProcessorMock.RenderWaveform got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmRenderWaveform.RenderWaveformMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmRenderWaveform.RenderWaveformMock.defaultExpectation.results
+		if mm_results == nil {
+			mmRenderWaveform.t.Fatal("No results are set for the MediaProcessorMock.RenderWaveform")
+		}
+		return (*mm_results).imageFilepath, (*mm_results).err
+	}
+	if mmRenderWaveform.funcRenderWaveform != nil {
+		return mmRenderWaveform.funcRenderWaveform(ctx, filepath, opts)
+	}
+	mmRenderWaveform.t.Fatalf("Unexpected call to MediaProcessorMock.RenderWaveform. %v %v %v", ctx, filepath, opts)
+	return
+}
+
+// RenderWaveformAfterCounter returns a count of finished MediaProcessorMock.RenderWaveform invocations
+func (mmRenderWaveform *MediaProcessorMock) RenderWaveformAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmRenderWaveform.afterRenderWaveformCounter)
+}
+
+// RenderWaveformBeforeCounter returns a count of MediaProcessorMock.RenderWaveform invocations
+func (mmRenderWaveform *MediaProcessorMock) RenderWaveformBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmRenderWaveform.beforeRenderWaveformCounter)
+}
+
+// Calls returns a list of arguments used in each call to MediaProcessorMock.RenderWaveform.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmRenderWaveform *mMediaProcessorMockRenderWaveform) Calls() []*MediaProcessorMockRenderWaveformParams {
+	mmRenderWaveform.mutex.RLock()
+
+	argCopy := make([]*MediaProcessorMockRenderWaveformParams, len(mmRenderWaveform.callArgs))
+	copy(argCopy, mmRenderWaveform.callArgs)
+
+	mmRenderWaveform.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockRenderWaveformDone returns true if the count of the RenderWaveform invocations corresponds
+// the number of defined expectations
+func (m *MediaProcessorMock) MinimockRenderWaveformDone() bool {
+	if m.RenderWaveformMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.RenderWaveformMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.RenderWaveformMock.invocationsDone()
+}
+
+// MinimockRenderWaveformInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockRenderWaveformInspect() {
+	for _, e := range m.RenderWaveformMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to MediaProcessorMock.RenderWaveform at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterRenderWaveformCounter := mm_atomic.LoadUint64(&m.afterRenderWaveformCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.RenderWaveformMock.defaultExpectation != nil && afterRenderWaveformCounter < 1 {
+		if m.RenderWaveformMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.RenderWaveform at\n%s", m.RenderWaveformMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to MediaProcessorMock.RenderWaveform at\n%s with params: %#v", m.RenderWaveformMock.defaultExpectation.expectationOrigins.origin, *m.RenderWaveformMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcRenderWaveform != nil && afterRenderWaveformCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.RenderWaveform at\n%s", m.funcRenderWaveformOrigin)
+	}
+
+	if !m.RenderWaveformMock.invocationsDone() && afterRenderWaveformCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.RenderWaveform at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.RenderWaveformMock.expectedInvocations), m.RenderWaveformMock.expectedInvocationsOrigin, afterRenderWaveformCounter)
+	}
+}
+
+type mMediaProcessorMockSplit struct {
+	optional           bool
+	mock               *MediaProcessorMock
+	defaultExpectation *MediaProcessorMockSplitExpectation
+	expectations       []*MediaProcessorMockSplitExpectation
+
+	callArgs []*MediaProcessorMockSplitParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// MediaProcessorMockSplitExpectation specifies expectation struct of the MediaProcessor.Split
+type MediaProcessorMockSplitExpectation struct {
+	mock               *MediaProcessorMock
+	params             *MediaProcessorMockSplitParams
+	paramPtrs          *MediaProcessorMockSplitParamPtrs
+	expectationOrigins MediaProcessorMockSplitExpectationOrigins
+	results            *MediaProcessorMockSplitResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// MediaProcessorMockSplitParams contains parameters of the MediaProcessor.Split
+type MediaProcessorMockSplitParams struct {
+	ctx      context.Context
+	filepath string
+	opts     mm_service.SplitOptions
+}
+
+// MediaProcessorMockSplitParamPtrs contains pointers to parameters of the MediaProcessor.Split
+type MediaProcessorMockSplitParamPtrs struct {
+	ctx      *context.Context
+	filepath *string
+	opts     *mm_service.SplitOptions
+}
+
+// MediaProcessorMockSplitResults contains results of the MediaProcessor.Split
+type MediaProcessorMockSplitResults struct {
+	parts []mm_service.SplitPart
+	err   error
+}
+
+// MediaProcessorMockSplitOrigins contains origins of expectations of the MediaProcessor.Split
+type MediaProcessorMockSplitExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originFilepath string
+	originOpts     string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmSplit *mMediaProcessorMockSplit) Optional() *mMediaProcessorMockSplit {
+	mmSplit.optional = true
+	return mmSplit
+}
+
+// Expect sets up expected params for MediaProcessor.Split
+func (mmSplit *mMediaProcessorMockSplit) Expect(ctx context.Context, filepath string, opts mm_service.SplitOptions) *mMediaProcessorMockSplit {
+	if mmSplit.mock.funcSplit != nil {
+		mmSplit.mock.t.Fatalf("MediaProcessorMock.Split mock is already set by Set")
+	}
+
+	if mmSplit.defaultExpectation == nil {
+		mmSplit.defaultExpectation = &MediaProcessorMockSplitExpectation{}
+	}
+
+	if mmSplit.defaultExpectation.paramPtrs != nil {
+		mmSplit.mock.t.Fatalf("MediaProcessorMock.Split mock is already set by ExpectParams functions")
+	}
+
+	mmSplit.defaultExpectation.params = &MediaProcessorMockSplitParams{ctx, filepath, opts}
+	mmSplit.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmSplit.expectations {
+		if minimock.Equal(e.params, mmSplit.defaultExpectation.params) {
+			mmSplit.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmSplit.defaultExpectation.params)
+		}
+	}
+
+	return mmSplit
+}
+
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.Split
+func (mmSplit *mMediaProcessorMockSplit) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockSplit {
+	if mmSplit.mock.funcSplit != nil {
+		mmSplit.mock.t.Fatalf("MediaProcessorMock.Split mock is already set by Set")
+	}
+
+	if mmSplit.defaultExpectation == nil {
+		mmSplit.defaultExpectation = &MediaProcessorMockSplitExpectation{}
+	}
+
+	if mmSplit.defaultExpectation.params != nil {
+		mmSplit.mock.t.Fatalf("MediaProcessorMock.Split mock is already set by Expect")
+	}
+
+	if mmSplit.defaultExpectation.paramPtrs == nil {
+		mmSplit.defaultExpectation.paramPtrs = &MediaProcessorMockSplitParamPtrs{}
+	}
+	mmSplit.defaultExpectation.paramPtrs.ctx = &ctx
+	mmSplit.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmSplit
+}
+
+// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.Split
+func (mmSplit *mMediaProcessorMockSplit) ExpectFilepathParam2(filepath string) *mMediaProcessorMockSplit {
+	if mmSplit.mock.funcSplit != nil {
+		mmSplit.mock.t.Fatalf("MediaProcessorMock.Split mock is already set by Set")
+	}
+
+	if mmSplit.defaultExpectation == nil {
+		mmSplit.defaultExpectation = &MediaProcessorMockSplitExpectation{}
+	}
+
+	if mmSplit.defaultExpectation.params != nil {
+		mmSplit.mock.t.Fatalf("MediaProcessorMock.Split mock is already set by Expect")
+	}
+
+	if mmSplit.defaultExpectation.paramPtrs == nil {
+		mmSplit.defaultExpectation.paramPtrs = &MediaProcessorMockSplitParamPtrs{}
+	}
+	mmSplit.defaultExpectation.paramPtrs.filepath = &filepath
+	mmSplit.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
+
+	return mmSplit
+}
+
+// ExpectOptsParam3 sets up expected param opts for MediaProcessor.Split
+func (mmSplit *mMediaProcessorMockSplit) ExpectOptsParam3(opts mm_service.SplitOptions) *mMediaProcessorMockSplit {
+	if mmSplit.mock.funcSplit != nil {
+		mmSplit.mock.t.Fatalf("MediaProcessorMock.Split mock is already set by Set")
+	}
+
+	if mmSplit.defaultExpectation == nil {
+		mmSplit.defaultExpectation = &MediaProcessorMockSplitExpectation{}
+	}
+
+	if mmSplit.defaultExpectation.params != nil {
+		mmSplit.mock.t.Fatalf("MediaProcessorMock.Split mock is already set by Expect")
+	}
+
+	if mmSplit.defaultExpectation.paramPtrs == nil {
+		mmSplit.defaultExpectation.paramPtrs = &MediaProcessorMockSplitParamPtrs{}
+	}
+	mmSplit.defaultExpectation.paramPtrs.opts = &opts
+	mmSplit.defaultExpectation.expectationOrigins.originOpts = minimock.CallerInfo(1)
+
+	return mmSplit
+}
+
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.Split
+func (mmSplit *mMediaProcessorMockSplit) Inspect(f func(ctx context.Context, filepath string, opts mm_service.SplitOptions)) *mMediaProcessorMockSplit {
+	if mmSplit.mock.inspectFuncSplit != nil {
+		mmSplit.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.Split")
+	}
+
+	mmSplit.mock.inspectFuncSplit = f
+
+	return mmSplit
+}
+
+// Return sets up results that will be returned by MediaProcessor.Split
+func (mmSplit *mMediaProcessorMockSplit) Return(parts []mm_service.SplitPart, err error) *MediaProcessorMock {
+	if mmSplit.mock.funcSplit != nil {
+		mmSplit.mock.t.Fatalf("MediaProcessorMock.Split mock is already set by Set")
+	}
+
+	if mmSplit.defaultExpectation == nil {
+		mmSplit.defaultExpectation = &MediaProcessorMockSplitExpectation{mock: mmSplit.mock}
+	}
+	mmSplit.defaultExpectation.results = &MediaProcessorMockSplitResults{parts, err}
+	mmSplit.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmSplit.mock
+}
+
+// Set uses given function f to mock the MediaProcessor.Split method
+func (mmSplit *mMediaProcessorMockSplit) Set(f func(ctx context.Context, filepath string, opts mm_service.SplitOptions) (parts []mm_service.SplitPart, err error)) *MediaProcessorMock {
+	if mmSplit.defaultExpectation != nil {
+		mmSplit.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.Split method")
+	}
+
+	if len(mmSplit.expectations) > 0 {
+		mmSplit.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.Split method")
+	}
+
+	mmSplit.mock.funcSplit = f
+	mmSplit.mock.funcSplitOrigin = minimock.CallerInfo(1)
+	return mmSplit.mock
+}
+
+// When sets expectation for the MediaProcessor.Split which will trigger the result defined by the following
+// Then helper
+func (mmSplit *mMediaProcessorMockSplit) When(ctx context.Context, filepath string, opts mm_service.SplitOptions) *MediaProcessorMockSplitExpectation {
+	if mmSplit.mock.funcSplit != nil {
+		mmSplit.mock.t.Fatalf("MediaProcessorMock.Split mock is already set by Set")
+	}
+
+	expectation := &MediaProcessorMockSplitExpectation{
+		mock:               mmSplit.mock,
+		params:             &MediaProcessorMockSplitParams{ctx, filepath, opts},
+		expectationOrigins: MediaProcessorMockSplitExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmSplit.expectations = append(mmSplit.expectations, expectation)
+	return expectation
+}
+
+// Then sets up MediaProcessor.Split return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockSplitExpectation) Then(parts []mm_service.SplitPart, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockSplitResults{parts, err}
+	return e.mock
+}
+
+// Times sets number of times MediaProcessor.Split should be invoked
+func (mmSplit *mMediaProcessorMockSplit) Times(n uint64) *mMediaProcessorMockSplit {
+	if n == 0 {
+		mmSplit.mock.t.Fatalf("Times of MediaProcessorMock.Split mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmSplit.expectedInvocations, n)
+	mmSplit.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmSplit
+}
+
+func (mmSplit *mMediaProcessorMockSplit) invocationsDone() bool {
+	if len(mmSplit.expectations) == 0 && mmSplit.defaultExpectation == nil && mmSplit.mock.funcSplit == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmSplit.mock.afterSplitCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmSplit.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// Split implements mm_service.MediaProcessor
+func (mmSplit *MediaProcessorMock) Split(ctx context.Context, filepath string, opts mm_service.SplitOptions) (parts []mm_service.SplitPart, err error) {
+	mm_atomic.AddUint64(&mmSplit.beforeSplitCounter, 1)
+	defer mm_atomic.AddUint64(&mmSplit.afterSplitCounter, 1)
+
+	mmSplit.t.Helper()
+
+	if mmSplit.inspectFuncSplit != nil {
+		mmSplit.inspectFuncSplit(ctx, filepath, opts)
+	}
+
+	mm_params := MediaProcessorMockSplitParams{ctx, filepath, opts}
+
+	// Record call args
+	mmSplit.SplitMock.mutex.Lock()
+	mmSplit.SplitMock.callArgs = append(mmSplit.SplitMock.callArgs, &mm_params)
+	mmSplit.SplitMock.mutex.Unlock()
+
+	for _, e := range mmSplit.SplitMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.parts, e.results.err
+		}
+	}
+
+	if mmSplit.SplitMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmSplit.SplitMock.defaultExpectation.Counter, 1)
+		mm_want := mmSplit.SplitMock.defaultExpectation.params
+		mm_want_ptrs := mmSplit.SplitMock.defaultExpectation.paramPtrs
+
+		mm_got := MediaProcessorMockSplitParams{ctx, filepath, opts}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmSplit.t.Errorf("MediaProcessorMock.Split got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmSplit.SplitMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.filepath != nil && !minimock.Equal(*mm_want_ptrs.filepath, mm_got.filepath) {
+				mmSplit.t.Errorf("MediaProcessorMock.Split got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmSplit.SplitMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
+			}
+
+			if mm_want_ptrs.opts != nil && !minimock.Equal(*mm_want_ptrs.opts, mm_got.opts) {
+				mmSplit.t.Errorf("MediaProcessorMock.Split got unexpected parameter opts, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmSplit.SplitMock.defaultExpectation.expectationOrigins.originOpts, *mm_want_ptrs.opts, mm_got.opts, minimock.Diff(*mm_want_ptrs.opts, mm_got.opts))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmSplit.t.Errorf("MediaProcessorMock.Split got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmSplit.SplitMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmSplit.SplitMock.defaultExpectation.results
+		if mm_results == nil {
+			mmSplit.t.Fatal("No results are set for the MediaProcessorMock.Split")
+		}
+		return (*mm_results).parts, (*mm_results).err
+	}
+	if mmSplit.funcSplit != nil {
+		return mmSplit.funcSplit(ctx, filepath, opts)
+	}
+	mmSplit.t.Fatalf("Unexpected call to MediaProcessorMock.Split. %v %v %v", ctx, filepath, opts)
+	return
+}
+
+// SplitAfterCounter returns a count of finished MediaProcessorMock.Split invocations
+func (mmSplit *MediaProcessorMock) SplitAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmSplit.afterSplitCounter)
+}
+
+// SplitBeforeCounter returns a count of MediaProcessorMock.Split invocations
+func (mmSplit *MediaProcessorMock) SplitBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmSplit.beforeSplitCounter)
+}
+
+// Calls returns a list of arguments used in each call to MediaProcessorMock.Split.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmSplit *mMediaProcessorMockSplit) Calls() []*MediaProcessorMockSplitParams {
+	mmSplit.mutex.RLock()
+
+	argCopy := make([]*MediaProcessorMockSplitParams, len(mmSplit.callArgs))
+	copy(argCopy, mmSplit.callArgs)
+
+	mmSplit.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockSplitDone returns true if the count of the Split invocations corresponds
+// the number of defined expectations
+func (m *MediaProcessorMock) MinimockSplitDone() bool {
+	if m.SplitMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.SplitMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.SplitMock.invocationsDone()
+}
+
+// MinimockSplitInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockSplitInspect() {
+	for _, e := range m.SplitMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to MediaProcessorMock.Split at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterSplitCounter := mm_atomic.LoadUint64(&m.afterSplitCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.SplitMock.defaultExpectation != nil && afterSplitCounter < 1 {
+		if m.SplitMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.Split at\n%s", m.SplitMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to MediaProcessorMock.Split at\n%s with params: %#v", m.SplitMock.defaultExpectation.expectationOrigins.origin, *m.SplitMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcSplit != nil && afterSplitCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.Split at\n%s", m.funcSplitOrigin)
+	}
+
+	if !m.SplitMock.invocationsDone() && afterSplitCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.Split at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.SplitMock.expectedInvocations), m.SplitMock.expectedInvocationsOrigin, afterSplitCounter)
+	}
+}
+
+type mMediaProcessorMockTranscode struct {
+	optional           bool
+	mock               *MediaProcessorMock
+	defaultExpectation *MediaProcessorMockTranscodeExpectation
+	expectations       []*MediaProcessorMockTranscodeExpectation
+
+	callArgs []*MediaProcessorMockTranscodeParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// MediaProcessorMockTranscodeExpectation specifies expectation struct of the MediaProcessor.Transcode
+type MediaProcessorMockTranscodeExpectation struct {
+	mock               *MediaProcessorMock
+	params             *MediaProcessorMockTranscodeParams
+	paramPtrs          *MediaProcessorMockTranscodeParamPtrs
+	expectationOrigins MediaProcessorMockTranscodeExpectationOrigins
+	results            *MediaProcessorMockTranscodeResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// MediaProcessorMockTranscodeParams contains parameters of the MediaProcessor.Transcode
+type MediaProcessorMockTranscodeParams struct {
+	ctx      context.Context
+	filepath string
+	opts     mm_service.TranscodeOptions
+}
+
+// MediaProcessorMockTranscodeParamPtrs contains pointers to parameters of the MediaProcessor.Transcode
+type MediaProcessorMockTranscodeParamPtrs struct {
+	ctx      *context.Context
+	filepath *string
+	opts     *mm_service.TranscodeOptions
+}
+
+// MediaProcessorMockTranscodeResults contains results of the MediaProcessor.Transcode
+type MediaProcessorMockTranscodeResults struct {
+	resultFilepath string
+	err            error
+}
+
+// MediaProcessorMockTranscodeOrigins contains origins of expectations of the MediaProcessor.Transcode
+type MediaProcessorMockTranscodeExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originFilepath string
+	originOpts     string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmTranscode *mMediaProcessorMockTranscode) Optional() *mMediaProcessorMockTranscode {
+	mmTranscode.optional = true
+	return mmTranscode
+}
+
+// Expect sets up expected params for MediaProcessor.Transcode
+func (mmTranscode *mMediaProcessorMockTranscode) Expect(ctx context.Context, filepath string, opts mm_service.TranscodeOptions) *mMediaProcessorMockTranscode {
+	if mmTranscode.mock.funcTranscode != nil {
+		mmTranscode.mock.t.Fatalf("MediaProcessorMock.Transcode mock is already set by Set")
+	}
+
+	if mmTranscode.defaultExpectation == nil {
+		mmTranscode.defaultExpectation = &MediaProcessorMockTranscodeExpectation{}
+	}
+
+	if mmTranscode.defaultExpectation.paramPtrs != nil {
+		mmTranscode.mock.t.Fatalf("MediaProcessorMock.Transcode mock is already set by ExpectParams functions")
+	}
+
+	mmTranscode.defaultExpectation.params = &MediaProcessorMockTranscodeParams{ctx, filepath, opts}
+	mmTranscode.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmTranscode.expectations {
+		if minimock.Equal(e.params, mmTranscode.defaultExpectation.params) {
+			mmTranscode.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmTranscode.defaultExpectation.params)
+		}
+	}
+
+	return mmTranscode
+}
+
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.Transcode
+func (mmTranscode *mMediaProcessorMockTranscode) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockTranscode {
+	if mmTranscode.mock.funcTranscode != nil {
+		mmTranscode.mock.t.Fatalf("MediaProcessorMock.Transcode mock is already set by Set")
+	}
+
+	if mmTranscode.defaultExpectation == nil {
+		mmTranscode.defaultExpectation = &MediaProcessorMockTranscodeExpectation{}
+	}
+
+	if mmTranscode.defaultExpectation.params != nil {
+		mmTranscode.mock.t.Fatalf("MediaProcessorMock.Transcode mock is already set by Expect")
+	}
+
+	if mmTranscode.defaultExpectation.paramPtrs == nil {
+		mmTranscode.defaultExpectation.paramPtrs = &MediaProcessorMockTranscodeParamPtrs{}
+	}
+	mmTranscode.defaultExpectation.paramPtrs.ctx = &ctx
+	mmTranscode.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmTranscode
+}
+
+// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.Transcode
+func (mmTranscode *mMediaProcessorMockTranscode) ExpectFilepathParam2(filepath string) *mMediaProcessorMockTranscode {
+	if mmTranscode.mock.funcTranscode != nil {
+		mmTranscode.mock.t.Fatalf("MediaProcessorMock.Transcode mock is already set by Set")
+	}
+
+	if mmTranscode.defaultExpectation == nil {
+		mmTranscode.defaultExpectation = &MediaProcessorMockTranscodeExpectation{}
+	}
+
+	if mmTranscode.defaultExpectation.params != nil {
+		mmTranscode.mock.t.Fatalf("MediaProcessorMock.Transcode mock is already set by Expect")
+	}
+
+	if mmTranscode.defaultExpectation.paramPtrs == nil {
+		mmTranscode.defaultExpectation.paramPtrs = &MediaProcessorMockTranscodeParamPtrs{}
+	}
+	mmTranscode.defaultExpectation.paramPtrs.filepath = &filepath
+	mmTranscode.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
+
+	return mmTranscode
+}
+
+// ExpectOptsParam3 sets up expected param opts for MediaProcessor.Transcode
+func (mmTranscode *mMediaProcessorMockTranscode) ExpectOptsParam3(opts mm_service.TranscodeOptions) *mMediaProcessorMockTranscode {
+	if mmTranscode.mock.funcTranscode != nil {
+		mmTranscode.mock.t.Fatalf("MediaProcessorMock.Transcode mock is already set by Set")
+	}
+
+	if mmTranscode.defaultExpectation == nil {
+		mmTranscode.defaultExpectation = &MediaProcessorMockTranscodeExpectation{}
+	}
+
+	if mmTranscode.defaultExpectation.params != nil {
+		mmTranscode.mock.t.Fatalf("MediaProcessorMock.Transcode mock is already set by Expect")
+	}
+
+	if mmTranscode.defaultExpectation.paramPtrs == nil {
+		mmTranscode.defaultExpectation.paramPtrs = &MediaProcessorMockTranscodeParamPtrs{}
+	}
+	mmTranscode.defaultExpectation.paramPtrs.opts = &opts
+	mmTranscode.defaultExpectation.expectationOrigins.originOpts = minimock.CallerInfo(1)
+
+	return mmTranscode
+}
+
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.Transcode
+func (mmTranscode *mMediaProcessorMockTranscode) Inspect(f func(ctx context.Context, filepath string, opts mm_service.TranscodeOptions)) *mMediaProcessorMockTranscode {
+	if mmTranscode.mock.inspectFuncTranscode != nil {
+		mmTranscode.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.Transcode")
+	}
+
+	mmTranscode.mock.inspectFuncTranscode = f
+
+	return mmTranscode
+}
+
+// Return sets up results that will be returned by MediaProcessor.Transcode
+func (mmTranscode *mMediaProcessorMockTranscode) Return(resultFilepath string, err error) *MediaProcessorMock {
+	if mmTranscode.mock.funcTranscode != nil {
+		mmTranscode.mock.t.Fatalf("MediaProcessorMock.Transcode mock is already set by Set")
+	}
+
+	if mmTranscode.defaultExpectation == nil {
+		mmTranscode.defaultExpectation = &MediaProcessorMockTranscodeExpectation{mock: mmTranscode.mock}
+	}
+	mmTranscode.defaultExpectation.results = &MediaProcessorMockTranscodeResults{resultFilepath, err}
+	mmTranscode.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmTranscode.mock
+}
+
+// Set uses given function f to mock the MediaProcessor.Transcode method
+func (mmTranscode *mMediaProcessorMockTranscode) Set(f func(ctx context.Context, filepath string, opts mm_service.TranscodeOptions) (resultFilepath string, err error)) *MediaProcessorMock {
+	if mmTranscode.defaultExpectation != nil {
+		mmTranscode.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.Transcode method")
+	}
+
+	if len(mmTranscode.expectations) > 0 {
+		mmTranscode.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.Transcode method")
+	}
+
+	mmTranscode.mock.funcTranscode = f
+	mmTranscode.mock.funcTranscodeOrigin = minimock.CallerInfo(1)
+	return mmTranscode.mock
+}
+
+// When sets expectation for the MediaProcessor.Transcode which will trigger the result defined by the following
+// Then helper
+func (mmTranscode *mMediaProcessorMockTranscode) When(ctx context.Context, filepath string, opts mm_service.TranscodeOptions) *MediaProcessorMockTranscodeExpectation {
+	if mmTranscode.mock.funcTranscode != nil {
+		mmTranscode.mock.t.Fatalf("MediaProcessorMock.Transcode mock is already set by Set")
+	}
+
+	expectation := &MediaProcessorMockTranscodeExpectation{
+		mock:               mmTranscode.mock,
+		params:             &MediaProcessorMockTranscodeParams{ctx, filepath, opts},
+		expectationOrigins: MediaProcessorMockTranscodeExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmTranscode.expectations = append(mmTranscode.expectations, expectation)
+	return expectation
+}
+
+// Then sets up MediaProcessor.Transcode return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockTranscodeExpectation) Then(resultFilepath string, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockTranscodeResults{resultFilepath, err}
+	return e.mock
+}
+
+// Times sets number of times MediaProcessor.Transcode should be invoked
+func (mmTranscode *mMediaProcessorMockTranscode) Times(n uint64) *mMediaProcessorMockTranscode {
+	if n == 0 {
+		mmTranscode.mock.t.Fatalf("Times of MediaProcessorMock.Transcode mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmTranscode.expectedInvocations, n)
+	mmTranscode.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmTranscode
+}
+
+func (mmTranscode *mMediaProcessorMockTranscode) invocationsDone() bool {
+	if len(mmTranscode.expectations) == 0 && mmTranscode.defaultExpectation == nil && mmTranscode.mock.funcTranscode == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmTranscode.mock.afterTranscodeCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmTranscode.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// Transcode implements mm_service.MediaProcessor
+func (mmTranscode *MediaProcessorMock) Transcode(ctx context.Context, filepath string, opts mm_service.TranscodeOptions) (resultFilepath string, err error) {
+	mm_atomic.AddUint64(&mmTranscode.beforeTranscodeCounter, 1)
+	defer mm_atomic.AddUint64(&mmTranscode.afterTranscodeCounter, 1)
+
+	mmTranscode.t.Helper()
+
+	if mmTranscode.inspectFuncTranscode != nil {
+		mmTranscode.inspectFuncTranscode(ctx, filepath, opts)
+	}
+
+	mm_params := MediaProcessorMockTranscodeParams{ctx, filepath, opts}
+
+	// Record call args
+	mmTranscode.TranscodeMock.mutex.Lock()
+	mmTranscode.TranscodeMock.callArgs = append(mmTranscode.TranscodeMock.callArgs, &mm_params)
+	mmTranscode.TranscodeMock.mutex.Unlock()
+
+	for _, e := range mmTranscode.TranscodeMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.resultFilepath, e.results.err
+		}
+	}
+
+	if mmTranscode.TranscodeMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmTranscode.TranscodeMock.defaultExpectation.Counter, 1)
+		mm_want := mmTranscode.TranscodeMock.defaultExpectation.params
+		mm_want_ptrs := mmTranscode.TranscodeMock.defaultExpectation.paramPtrs
+
+		mm_got := MediaProcessorMockTranscodeParams{ctx, filepath, opts}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmTranscode.t.Errorf("MediaProcessorMock.Transcode got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmTranscode.TranscodeMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.filepath != nil && !minimock.Equal(*mm_want_ptrs.filepath, mm_got.filepath) {
+				mmTranscode.t.Errorf("MediaProcessorMock.Transcode got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmTranscode.TranscodeMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
+			}
+
+			if mm_want_ptrs.opts != nil && !minimock.Equal(*mm_want_ptrs.opts, mm_got.opts) {
+				mmTranscode.t.Errorf("MediaProcessorMock.Transcode got unexpected parameter opts, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmTranscode.TranscodeMock.defaultExpectation.expectationOrigins.originOpts, *mm_want_ptrs.opts, mm_got.opts, minimock.Diff(*mm_want_ptrs.opts, mm_got.opts))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmTranscode.t.Errorf("MediaProcessorMock.Transcode got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmTranscode.TranscodeMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmTranscode.TranscodeMock.defaultExpectation.results
+		if mm_results == nil {
+			mmTranscode.t.Fatal("No results are set for the MediaProcessorMock.Transcode")
+		}
+		return (*mm_results).resultFilepath, (*mm_results).err
+	}
+	if mmTranscode.funcTranscode != nil {
+		return mmTranscode.funcTranscode(ctx, filepath, opts)
+	}
+	mmTranscode.t.Fatalf("Unexpected call to MediaProcessorMock.Transcode. %v %v %v", ctx, filepath, opts)
+	return
+}
+
+// TranscodeAfterCounter returns a count of finished MediaProcessorMock.Transcode invocations
+func (mmTranscode *MediaProcessorMock) TranscodeAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmTranscode.afterTranscodeCounter)
+}
+
+// TranscodeBeforeCounter returns a count of MediaProcessorMock.Transcode invocations
+func (mmTranscode *MediaProcessorMock) TranscodeBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmTranscode.beforeTranscodeCounter)
+}
+
+// Calls returns a list of arguments used in each call to MediaProcessorMock.Transcode.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmTranscode *mMediaProcessorMockTranscode) Calls() []*MediaProcessorMockTranscodeParams {
+	mmTranscode.mutex.RLock()
+
+	argCopy := make([]*MediaProcessorMockTranscodeParams, len(mmTranscode.callArgs))
+	copy(argCopy, mmTranscode.callArgs)
+
+	mmTranscode.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockTranscodeDone returns true if the count of the Transcode invocations corresponds
+// the number of defined expectations
+func (m *MediaProcessorMock) MinimockTranscodeDone() bool {
+	if m.TranscodeMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.TranscodeMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.TranscodeMock.invocationsDone()
+}
+
+// MinimockTranscodeInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockTranscodeInspect() {
+	for _, e := range m.TranscodeMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to MediaProcessorMock.Transcode at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterTranscodeCounter := mm_atomic.LoadUint64(&m.afterTranscodeCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.TranscodeMock.defaultExpectation != nil && afterTranscodeCounter < 1 {
+		if m.TranscodeMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.Transcode at\n%s", m.TranscodeMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to MediaProcessorMock.Transcode at\n%s with params: %#v", m.TranscodeMock.defaultExpectation.expectationOrigins.origin, *m.TranscodeMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcTranscode != nil && afterTranscodeCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.Transcode at\n%s", m.funcTranscodeOrigin)
+	}
+
+	if !m.TranscodeMock.invocationsDone() && afterTranscodeCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.Transcode at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.TranscodeMock.expectedInvocations), m.TranscodeMock.expectedInvocationsOrigin, afterTranscodeCounter)
+	}
+}
+
+type mMediaProcessorMockTrimSilence struct {
+	optional           bool
+	mock               *MediaProcessorMock
+	defaultExpectation *MediaProcessorMockTrimSilenceExpectation
+	expectations       []*MediaProcessorMockTrimSilenceExpectation
+
+	callArgs []*MediaProcessorMockTrimSilenceParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// MediaProcessorMockTrimSilenceExpectation specifies expectation struct of the MediaProcessor.TrimSilence
+type MediaProcessorMockTrimSilenceExpectation struct {
+	mock               *MediaProcessorMock
+	params             *MediaProcessorMockTrimSilenceParams
+	paramPtrs          *MediaProcessorMockTrimSilenceParamPtrs
+	expectationOrigins MediaProcessorMockTrimSilenceExpectationOrigins
+	results            *MediaProcessorMockTrimSilenceResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// MediaProcessorMockTrimSilenceParams contains parameters of the MediaProcessor.TrimSilence
+type MediaProcessorMockTrimSilenceParams struct {
+	ctx      context.Context
+	filepath string
+	opts     mm_service.TrimSilenceOptions
+}
+
+// MediaProcessorMockTrimSilenceParamPtrs contains pointers to parameters of the MediaProcessor.TrimSilence
+type MediaProcessorMockTrimSilenceParamPtrs struct {
+	ctx      *context.Context
+	filepath *string
+	opts     *mm_service.TrimSilenceOptions
+}
+
+// MediaProcessorMockTrimSilenceResults contains results of the MediaProcessor.TrimSilence
+type MediaProcessorMockTrimSilenceResults struct {
+	resultFilepath string
+	err            error
+}
+
+// MediaProcessorMockTrimSilenceOrigins contains origins of expectations of the MediaProcessor.TrimSilence
+type MediaProcessorMockTrimSilenceExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originFilepath string
+	originOpts     string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmTrimSilence *mMediaProcessorMockTrimSilence) Optional() *mMediaProcessorMockTrimSilence {
+	mmTrimSilence.optional = true
+	return mmTrimSilence
+}
+
+// Expect sets up expected params for MediaProcessor.TrimSilence
+func (mmTrimSilence *mMediaProcessorMockTrimSilence) Expect(ctx context.Context, filepath string, opts mm_service.TrimSilenceOptions) *mMediaProcessorMockTrimSilence {
+	if mmTrimSilence.mock.funcTrimSilence != nil {
+		mmTrimSilence.mock.t.Fatalf("MediaProcessorMock.TrimSilence mock is already set by Set")
+	}
+
+	if mmTrimSilence.defaultExpectation == nil {
+		mmTrimSilence.defaultExpectation = &MediaProcessorMockTrimSilenceExpectation{}
+	}
+
+	if mmTrimSilence.defaultExpectation.paramPtrs != nil {
+		mmTrimSilence.mock.t.Fatalf("MediaProcessorMock.TrimSilence mock is already set by ExpectParams functions")
+	}
+
+	mmTrimSilence.defaultExpectation.params = &MediaProcessorMockTrimSilenceParams{ctx, filepath, opts}
+	mmTrimSilence.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmTrimSilence.expectations {
+		if minimock.Equal(e.params, mmTrimSilence.defaultExpectation.params) {
+			mmTrimSilence.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmTrimSilence.defaultExpectation.params)
+		}
+	}
+
+	return mmTrimSilence
+}
+
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.TrimSilence
+func (mmTrimSilence *mMediaProcessorMockTrimSilence) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockTrimSilence {
+	if mmTrimSilence.mock.funcTrimSilence != nil {
+		mmTrimSilence.mock.t.Fatalf("MediaProcessorMock.TrimSilence mock is already set by Set")
+	}
+
+	if mmTrimSilence.defaultExpectation == nil {
+		mmTrimSilence.defaultExpectation = &MediaProcessorMockTrimSilenceExpectation{}
+	}
+
+	if mmTrimSilence.defaultExpectation.params != nil {
+		mmTrimSilence.mock.t.Fatalf("MediaProcessorMock.TrimSilence mock is already set by Expect")
+	}
+
+	if mmTrimSilence.defaultExpectation.paramPtrs == nil {
+		mmTrimSilence.defaultExpectation.paramPtrs = &MediaProcessorMockTrimSilenceParamPtrs{}
+	}
+	mmTrimSilence.defaultExpectation.paramPtrs.ctx = &ctx
+	mmTrimSilence.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmTrimSilence
+}
+
+// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.TrimSilence
+func (mmTrimSilence *mMediaProcessorMockTrimSilence) ExpectFilepathParam2(filepath string) *mMediaProcessorMockTrimSilence {
+	if mmTrimSilence.mock.funcTrimSilence != nil {
+		mmTrimSilence.mock.t.Fatalf("MediaProcessorMock.TrimSilence mock is already set by Set")
+	}
+
+	if mmTrimSilence.defaultExpectation == nil {
+		mmTrimSilence.defaultExpectation = &MediaProcessorMockTrimSilenceExpectation{}
+	}
+
+	if mmTrimSilence.defaultExpectation.params != nil {
+		mmTrimSilence.mock.t.Fatalf("MediaProcessorMock.TrimSilence mock is already set by Expect")
+	}
+
+	if mmTrimSilence.defaultExpectation.paramPtrs == nil {
+		mmTrimSilence.defaultExpectation.paramPtrs = &MediaProcessorMockTrimSilenceParamPtrs{}
+	}
+	mmTrimSilence.defaultExpectation.paramPtrs.filepath = &filepath
+	mmTrimSilence.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
+
+	return mmTrimSilence
+}
+
+// ExpectOptsParam3 sets up expected param opts for MediaProcessor.TrimSilence
+func (mmTrimSilence *mMediaProcessorMockTrimSilence) ExpectOptsParam3(opts mm_service.TrimSilenceOptions) *mMediaProcessorMockTrimSilence {
+	if mmTrimSilence.mock.funcTrimSilence != nil {
+		mmTrimSilence.mock.t.Fatalf("MediaProcessorMock.TrimSilence mock is already set by Set")
+	}
+
+	if mmTrimSilence.defaultExpectation == nil {
+		mmTrimSilence.defaultExpectation = &MediaProcessorMockTrimSilenceExpectation{}
+	}
+
+	if mmTrimSilence.defaultExpectation.params != nil {
+		mmTrimSilence.mock.t.Fatalf("MediaProcessorMock.TrimSilence mock is already set by Expect")
+	}
+
+	if mmTrimSilence.defaultExpectation.paramPtrs == nil {
+		mmTrimSilence.defaultExpectation.paramPtrs = &MediaProcessorMockTrimSilenceParamPtrs{}
+	}
+	mmTrimSilence.defaultExpectation.paramPtrs.opts = &opts
+	mmTrimSilence.defaultExpectation.expectationOrigins.originOpts = minimock.CallerInfo(1)
+
+	return mmTrimSilence
+}
+
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.TrimSilence
+func (mmTrimSilence *mMediaProcessorMockTrimSilence) Inspect(f func(ctx context.Context, filepath string, opts mm_service.TrimSilenceOptions)) *mMediaProcessorMockTrimSilence {
+	if mmTrimSilence.mock.inspectFuncTrimSilence != nil {
+		mmTrimSilence.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.TrimSilence")
+	}
+
+	mmTrimSilence.mock.inspectFuncTrimSilence = f
+
+	return mmTrimSilence
+}
+
+// Return sets up results that will be returned by MediaProcessor.TrimSilence
+func (mmTrimSilence *mMediaProcessorMockTrimSilence) Return(resultFilepath string, err error) *MediaProcessorMock {
+	if mmTrimSilence.mock.funcTrimSilence != nil {
+		mmTrimSilence.mock.t.Fatalf("MediaProcessorMock.TrimSilence mock is already set by Set")
+	}
+
+	if mmTrimSilence.defaultExpectation == nil {
+		mmTrimSilence.defaultExpectation = &MediaProcessorMockTrimSilenceExpectation{mock: mmTrimSilence.mock}
+	}
+	mmTrimSilence.defaultExpectation.results = &MediaProcessorMockTrimSilenceResults{resultFilepath, err}
+	mmTrimSilence.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmTrimSilence.mock
+}
+
+// Set uses given function f to mock the MediaProcessor.TrimSilence method
+func (mmTrimSilence *mMediaProcessorMockTrimSilence) Set(f func(ctx context.Context, filepath string, opts mm_service.TrimSilenceOptions) (resultFilepath string, err error)) *MediaProcessorMock {
+	if mmTrimSilence.defaultExpectation != nil {
+		mmTrimSilence.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.TrimSilence method")
+	}
+
+	if len(mmTrimSilence.expectations) > 0 {
+		mmTrimSilence.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.TrimSilence method")
+	}
+
+	mmTrimSilence.mock.funcTrimSilence = f
+	mmTrimSilence.mock.funcTrimSilenceOrigin = minimock.CallerInfo(1)
+	return mmTrimSilence.mock
+}
+
+// When sets expectation for the MediaProcessor.TrimSilence which will trigger the result defined by the following
+// Then helper
+func (mmTrimSilence *mMediaProcessorMockTrimSilence) When(ctx context.Context, filepath string, opts mm_service.TrimSilenceOptions) *MediaProcessorMockTrimSilenceExpectation {
+	if mmTrimSilence.mock.funcTrimSilence != nil {
+		mmTrimSilence.mock.t.Fatalf("MediaProcessorMock.TrimSilence mock is already set by Set")
+	}
+
+	expectation := &MediaProcessorMockTrimSilenceExpectation{
+		mock:               mmTrimSilence.mock,
+		params:             &MediaProcessorMockTrimSilenceParams{ctx, filepath, opts},
+		expectationOrigins: MediaProcessorMockTrimSilenceExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmTrimSilence.expectations = append(mmTrimSilence.expectations, expectation)
+	return expectation
+}
+
+// Then sets up MediaProcessor.TrimSilence return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockTrimSilenceExpectation) Then(resultFilepath string, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockTrimSilenceResults{resultFilepath, err}
+	return e.mock
+}
+
+// Times sets number of times MediaProcessor.TrimSilence should be invoked
+func (mmTrimSilence *mMediaProcessorMockTrimSilence) Times(n uint64) *mMediaProcessorMockTrimSilence {
+	if n == 0 {
+		mmTrimSilence.mock.t.Fatalf("Times of MediaProcessorMock.TrimSilence mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmTrimSilence.expectedInvocations, n)
+	mmTrimSilence.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmTrimSilence
+}
+
+func (mmTrimSilence *mMediaProcessorMockTrimSilence) invocationsDone() bool {
+	if len(mmTrimSilence.expectations) == 0 && mmTrimSilence.defaultExpectation == nil && mmTrimSilence.mock.funcTrimSilence == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmTrimSilence.mock.afterTrimSilenceCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmTrimSilence.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// TrimSilence implements mm_service.MediaProcessor
+func (mmTrimSilence *MediaProcessorMock) TrimSilence(ctx context.Context, filepath string, opts mm_service.TrimSilenceOptions) (resultFilepath string, err error) {
+	mm_atomic.AddUint64(&mmTrimSilence.beforeTrimSilenceCounter, 1)
+	defer mm_atomic.AddUint64(&mmTrimSilence.afterTrimSilenceCounter, 1)
+
+	mmTrimSilence.t.Helper()
+
+	if mmTrimSilence.inspectFuncTrimSilence != nil {
+		mmTrimSilence.inspectFuncTrimSilence(ctx, filepath, opts)
+	}
+
+	mm_params := MediaProcessorMockTrimSilenceParams{ctx, filepath, opts}
+
+	// Record call args
+	mmTrimSilence.TrimSilenceMock.mutex.Lock()
+	mmTrimSilence.TrimSilenceMock.callArgs = append(mmTrimSilence.TrimSilenceMock.callArgs, &mm_params)
+	mmTrimSilence.TrimSilenceMock.mutex.Unlock()
+
+	for _, e := range mmTrimSilence.TrimSilenceMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.resultFilepath, e.results.err
+		}
+	}
+
+	if mmTrimSilence.TrimSilenceMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmTrimSilence.TrimSilenceMock.defaultExpectation.Counter, 1)
+		mm_want := mmTrimSilence.TrimSilenceMock.defaultExpectation.params
+		mm_want_ptrs := mmTrimSilence.TrimSilenceMock.defaultExpectation.paramPtrs
+
+		mm_got := MediaProcessorMockTrimSilenceParams{ctx, filepath, opts}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmTrimSilence.t.Errorf("MediaProcessorMock.TrimSilence got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmTrimSilence.TrimSilenceMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.filepath != nil && !minimock.Equal(*mm_want_ptrs.filepath, mm_got.filepath) {
+				mmTrimSilence.t.Errorf("MediaProcessorMock.TrimSilence got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmTrimSilence.TrimSilenceMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
+			}
+
+			if mm_want_ptrs.opts != nil && !minimock.Equal(*mm_want_ptrs.opts, mm_got.opts) {
+				mmTrimSilence.t.Errorf("MediaProcessorMock.TrimSilence got unexpected parameter opts, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmTrimSilence.TrimSilenceMock.defaultExpectation.expectationOrigins.originOpts, *mm_want_ptrs.opts, mm_got.opts, minimock.Diff(*mm_want_ptrs.opts, mm_got.opts))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmTrimSilence.t.Errorf("MediaProcessorMock.TrimSilence got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmTrimSilence.TrimSilenceMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmTrimSilence.TrimSilenceMock.defaultExpectation.results
+		if mm_results == nil {
+			mmTrimSilence.t.Fatal("No results are set for the MediaProcessorMock.TrimSilence")
+		}
+		return (*mm_results).resultFilepath, (*mm_results).err
+	}
+	if mmTrimSilence.funcTrimSilence != nil {
+		return mmTrimSilence.funcTrimSilence(ctx, filepath, opts)
+	}
+	mmTrimSilence.t.Fatalf("Unexpected call to MediaProcessorMock.TrimSilence. %v %v %v", ctx, filepath, opts)
+	return
 }
 
-// Return sets up results that will be returned by MediaProcessor.GetInfo
-func (mmGetInfo *mMediaProcessorMockGetInfo) Return(info *mm_service.MediaInfo, err error) *MediaProcessorMock {
-	if mmGetInfo.mock.funcGetInfo != nil {
-		mmGetInfo.mock.t.Fatalf("MediaProcessorMock.GetInfo mock is already set by Set")
+// TrimSilenceAfterCounter returns a count of finished MediaProcessorMock.TrimSilence invocations
+func (mmTrimSilence *MediaProcessorMock) TrimSilenceAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmTrimSilence.afterTrimSilenceCounter)
+}
+
+// TrimSilenceBeforeCounter returns a count of MediaProcessorMock.TrimSilence invocations
+func (mmTrimSilence *MediaProcessorMock) TrimSilenceBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmTrimSilence.beforeTrimSilenceCounter)
+}
+
+// Calls returns a list of arguments used in each call to MediaProcessorMock.TrimSilence.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmTrimSilence *mMediaProcessorMockTrimSilence) Calls() []*MediaProcessorMockTrimSilenceParams {
+	mmTrimSilence.mutex.RLock()
+
+	argCopy := make([]*MediaProcessorMockTrimSilenceParams, len(mmTrimSilence.callArgs))
+	copy(argCopy, mmTrimSilence.callArgs)
+
+	mmTrimSilence.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockTrimSilenceDone returns true if the count of the TrimSilence invocations corresponds
+// the number of defined expectations
+func (m *MediaProcessorMock) MinimockTrimSilenceDone() bool {
+	if m.TrimSilenceMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
 	}
 
-	if mmGetInfo.defaultExpectation == nil {
-		mmGetInfo.defaultExpectation = &MediaProcessorMockGetInfoExpectation{mock: mmGetInfo.mock}
+	for _, e := range m.TrimSilenceMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
 	}
-	mmGetInfo.defaultExpectation.results = &MediaProcessorMockGetInfoResults{info, err}
-	mmGetInfo.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
-	return mmGetInfo.mock
+
+	return m.TrimSilenceMock.invocationsDone()
 }
 
-// Set uses given function f to mock the MediaProcessor.GetInfo method
-func (mmGetInfo *mMediaProcessorMockGetInfo) Set(f func(ctx context.Context, filepath string) (info *mm_service.MediaInfo, err error)) *MediaProcessorMock {
-	if mmGetInfo.defaultExpectation != nil {
-		mmGetInfo.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.GetInfo method")
+// MinimockTrimSilenceInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockTrimSilenceInspect() {
+	for _, e := range m.TrimSilenceMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to MediaProcessorMock.TrimSilence at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
 	}
 
-	if len(mmGetInfo.expectations) > 0 {
-		mmGetInfo.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.GetInfo method")
+	afterTrimSilenceCounter := mm_atomic.LoadUint64(&m.afterTrimSilenceCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.TrimSilenceMock.defaultExpectation != nil && afterTrimSilenceCounter < 1 {
+		if m.TrimSilenceMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.TrimSilence at\n%s", m.TrimSilenceMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to MediaProcessorMock.TrimSilence at\n%s with params: %#v", m.TrimSilenceMock.defaultExpectation.expectationOrigins.origin, *m.TrimSilenceMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcTrimSilence != nil && afterTrimSilenceCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.TrimSilence at\n%s", m.funcTrimSilenceOrigin)
 	}
 
-	mmGetInfo.mock.funcGetInfo = f
-	mmGetInfo.mock.funcGetInfoOrigin = minimock.CallerInfo(1)
-	return mmGetInfo.mock
+	if !m.TrimSilenceMock.invocationsDone() && afterTrimSilenceCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.TrimSilence at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.TrimSilenceMock.expectedInvocations), m.TrimSilenceMock.expectedInvocationsOrigin, afterTrimSilenceCounter)
+	}
 }
 
-// When sets expectation for the MediaProcessor.GetInfo which will trigger the result defined by the following
+type mMediaProcessorMockWriteMetadata struct {
+	optional           bool
+	mock               *MediaProcessorMock
+	defaultExpectation *MediaProcessorMockWriteMetadataExpectation
+	expectations       []*MediaProcessorMockWriteMetadataExpectation
+
+	callArgs []*MediaProcessorMockWriteMetadataParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// MediaProcessorMockWriteMetadataExpectation specifies expectation struct of the MediaProcessor.WriteMetadata
+type MediaProcessorMockWriteMetadataExpectation struct {
+	mock               *MediaProcessorMock
+	params             *MediaProcessorMockWriteMetadataParams
+	paramPtrs          *MediaProcessorMockWriteMetadataParamPtrs
+	expectationOrigins MediaProcessorMockWriteMetadataExpectationOrigins
+	results            *MediaProcessorMockWriteMetadataResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// MediaProcessorMockWriteMetadataParams contains parameters of the MediaProcessor.WriteMetadata
+type MediaProcessorMockWriteMetadataParams struct {
+	ctx      context.Context
+	filepath string
+	metadata mm_service.FileMetadata
+}
+
+// MediaProcessorMockWriteMetadataParamPtrs contains pointers to parameters of the MediaProcessor.WriteMetadata
+type MediaProcessorMockWriteMetadataParamPtrs struct {
+	ctx      *context.Context
+	filepath *string
+	metadata *mm_service.FileMetadata
+}
+
+// MediaProcessorMockWriteMetadataResults contains results of the MediaProcessor.WriteMetadata
+type MediaProcessorMockWriteMetadataResults struct {
+	resultFilepath string
+	err            error
+}
+
+// MediaProcessorMockWriteMetadataOrigins contains origins of expectations of the MediaProcessor.WriteMetadata
+type MediaProcessorMockWriteMetadataExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originFilepath string
+	originMetadata string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmWriteMetadata *mMediaProcessorMockWriteMetadata) Optional() *mMediaProcessorMockWriteMetadata {
+	mmWriteMetadata.optional = true
+	return mmWriteMetadata
+}
+
+// Expect sets up expected params for MediaProcessor.WriteMetadata
+func (mmWriteMetadata *mMediaProcessorMockWriteMetadata) Expect(ctx context.Context, filepath string, metadata mm_service.FileMetadata) *mMediaProcessorMockWriteMetadata {
+	if mmWriteMetadata.mock.funcWriteMetadata != nil {
+		mmWriteMetadata.mock.t.Fatalf("MediaProcessorMock.WriteMetadata mock is already set by Set")
+	}
+
+	if mmWriteMetadata.defaultExpectation == nil {
+		mmWriteMetadata.defaultExpectation = &MediaProcessorMockWriteMetadataExpectation{}
+	}
+
+	if mmWriteMetadata.defaultExpectation.paramPtrs != nil {
+		mmWriteMetadata.mock.t.Fatalf("MediaProcessorMock.WriteMetadata mock is already set by ExpectParams functions")
+	}
+
+	mmWriteMetadata.defaultExpectation.params = &MediaProcessorMockWriteMetadataParams{ctx, filepath, metadata}
+	mmWriteMetadata.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmWriteMetadata.expectations {
+		if minimock.Equal(e.params, mmWriteMetadata.defaultExpectation.params) {
+			mmWriteMetadata.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmWriteMetadata.defaultExpectation.params)
+		}
+	}
+
+	return mmWriteMetadata
+}
+
+// ExpectCtxParam1 sets up expected param ctx for MediaProcessor.WriteMetadata
+func (mmWriteMetadata *mMediaProcessorMockWriteMetadata) ExpectCtxParam1(ctx context.Context) *mMediaProcessorMockWriteMetadata {
+	if mmWriteMetadata.mock.funcWriteMetadata != nil {
+		mmWriteMetadata.mock.t.Fatalf("MediaProcessorMock.WriteMetadata mock is already set by Set")
+	}
+
+	if mmWriteMetadata.defaultExpectation == nil {
+		mmWriteMetadata.defaultExpectation = &MediaProcessorMockWriteMetadataExpectation{}
+	}
+
+	if mmWriteMetadata.defaultExpectation.params != nil {
+		mmWriteMetadata.mock.t.Fatalf("MediaProcessorMock.WriteMetadata mock is already set by Expect")
+	}
+
+	if mmWriteMetadata.defaultExpectation.paramPtrs == nil {
+		mmWriteMetadata.defaultExpectation.paramPtrs = &MediaProcessorMockWriteMetadataParamPtrs{}
+	}
+	mmWriteMetadata.defaultExpectation.paramPtrs.ctx = &ctx
+	mmWriteMetadata.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmWriteMetadata
+}
+
+// ExpectFilepathParam2 sets up expected param filepath for MediaProcessor.WriteMetadata
+func (mmWriteMetadata *mMediaProcessorMockWriteMetadata) ExpectFilepathParam2(filepath string) *mMediaProcessorMockWriteMetadata {
+	if mmWriteMetadata.mock.funcWriteMetadata != nil {
+		mmWriteMetadata.mock.t.Fatalf("MediaProcessorMock.WriteMetadata mock is already set by Set")
+	}
+
+	if mmWriteMetadata.defaultExpectation == nil {
+		mmWriteMetadata.defaultExpectation = &MediaProcessorMockWriteMetadataExpectation{}
+	}
+
+	if mmWriteMetadata.defaultExpectation.params != nil {
+		mmWriteMetadata.mock.t.Fatalf("MediaProcessorMock.WriteMetadata mock is already set by Expect")
+	}
+
+	if mmWriteMetadata.defaultExpectation.paramPtrs == nil {
+		mmWriteMetadata.defaultExpectation.paramPtrs = &MediaProcessorMockWriteMetadataParamPtrs{}
+	}
+	mmWriteMetadata.defaultExpectation.paramPtrs.filepath = &filepath
+	mmWriteMetadata.defaultExpectation.expectationOrigins.originFilepath = minimock.CallerInfo(1)
+
+	return mmWriteMetadata
+}
+
+// ExpectMetadataParam3 sets up expected param metadata for MediaProcessor.WriteMetadata
+func (mmWriteMetadata *mMediaProcessorMockWriteMetadata) ExpectMetadataParam3(metadata mm_service.FileMetadata) *mMediaProcessorMockWriteMetadata {
+	if mmWriteMetadata.mock.funcWriteMetadata != nil {
+		mmWriteMetadata.mock.t.Fatalf("MediaProcessorMock.WriteMetadata mock is already set by Set")
+	}
+
+	if mmWriteMetadata.defaultExpectation == nil {
+		mmWriteMetadata.defaultExpectation = &MediaProcessorMockWriteMetadataExpectation{}
+	}
+
+	if mmWriteMetadata.defaultExpectation.params != nil {
+		mmWriteMetadata.mock.t.Fatalf("MediaProcessorMock.WriteMetadata mock is already set by Expect")
+	}
+
+	if mmWriteMetadata.defaultExpectation.paramPtrs == nil {
+		mmWriteMetadata.defaultExpectation.paramPtrs = &MediaProcessorMockWriteMetadataParamPtrs{}
+	}
+	mmWriteMetadata.defaultExpectation.paramPtrs.metadata = &metadata
+	mmWriteMetadata.defaultExpectation.expectationOrigins.originMetadata = minimock.CallerInfo(1)
+
+	return mmWriteMetadata
+}
+
+// Inspect accepts an inspector function that has same arguments as the MediaProcessor.WriteMetadata
+func (mmWriteMetadata *mMediaProcessorMockWriteMetadata) Inspect(f func(ctx context.Context, filepath string, metadata mm_service.FileMetadata)) *mMediaProcessorMockWriteMetadata {
+	if mmWriteMetadata.mock.inspectFuncWriteMetadata != nil {
+		mmWriteMetadata.mock.t.Fatalf("Inspect function is already set for MediaProcessorMock.WriteMetadata")
+	}
+
+	mmWriteMetadata.mock.inspectFuncWriteMetadata = f
+
+	return mmWriteMetadata
+}
+
+// Return sets up results that will be returned by MediaProcessor.WriteMetadata
+func (mmWriteMetadata *mMediaProcessorMockWriteMetadata) Return(resultFilepath string, err error) *MediaProcessorMock {
+	if mmWriteMetadata.mock.funcWriteMetadata != nil {
+		mmWriteMetadata.mock.t.Fatalf("MediaProcessorMock.WriteMetadata mock is already set by Set")
+	}
+
+	if mmWriteMetadata.defaultExpectation == nil {
+		mmWriteMetadata.defaultExpectation = &MediaProcessorMockWriteMetadataExpectation{mock: mmWriteMetadata.mock}
+	}
+	mmWriteMetadata.defaultExpectation.results = &MediaProcessorMockWriteMetadataResults{resultFilepath, err}
+	mmWriteMetadata.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmWriteMetadata.mock
+}
+
+// Set uses given function f to mock the MediaProcessor.WriteMetadata method
+func (mmWriteMetadata *mMediaProcessorMockWriteMetadata) Set(f func(ctx context.Context, filepath string, metadata mm_service.FileMetadata) (resultFilepath string, err error)) *MediaProcessorMock {
+	if mmWriteMetadata.defaultExpectation != nil {
+		mmWriteMetadata.mock.t.Fatalf("Default expectation is already set for the MediaProcessor.WriteMetadata method")
+	}
+
+	if len(mmWriteMetadata.expectations) > 0 {
+		mmWriteMetadata.mock.t.Fatalf("Some expectations are already set for the MediaProcessor.WriteMetadata method")
+	}
+
+	mmWriteMetadata.mock.funcWriteMetadata = f
+	mmWriteMetadata.mock.funcWriteMetadataOrigin = minimock.CallerInfo(1)
+	return mmWriteMetadata.mock
+}
+
+// When sets expectation for the MediaProcessor.WriteMetadata which will trigger the result defined by the following
 // Then helper
-func (mmGetInfo *mMediaProcessorMockGetInfo) When(ctx context.Context, filepath string) *MediaProcessorMockGetInfoExpectation {
-	if mmGetInfo.mock.funcGetInfo != nil {
-		mmGetInfo.mock.t.Fatalf("MediaProcessorMock.GetInfo mock is already set by Set")
+func (mmWriteMetadata *mMediaProcessorMockWriteMetadata) When(ctx context.Context, filepath string, metadata mm_service.FileMetadata) *MediaProcessorMockWriteMetadataExpectation {
+	if mmWriteMetadata.mock.funcWriteMetadata != nil {
+		mmWriteMetadata.mock.t.Fatalf("MediaProcessorMock.WriteMetadata mock is already set by Set")
 	}
 
-	expectation := &MediaProcessorMockGetInfoExpectation{
-		mock:               mmGetInfo.mock,
-		params:             &MediaProcessorMockGetInfoParams{ctx, filepath},
-		expectationOrigins: MediaProcessorMockGetInfoExpectationOrigins{origin: minimock.CallerInfo(1)},
+	expectation := &MediaProcessorMockWriteMetadataExpectation{
+		mock:               mmWriteMetadata.mock,
+		params:             &MediaProcessorMockWriteMetadataParams{ctx, filepath, metadata},
+		expectationOrigins: MediaProcessorMockWriteMetadataExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
-	mmGetInfo.expectations = append(mmGetInfo.expectations, expectation)
+	mmWriteMetadata.expectations = append(mmWriteMetadata.expectations, expectation)
 	return expectation
 }
 
-// Then sets up MediaProcessor.GetInfo return parameters for the expectation previously defined by the When method
-func (e *MediaProcessorMockGetInfoExpectation) Then(info *mm_service.MediaInfo, err error) *MediaProcessorMock {
-	e.results = &MediaProcessorMockGetInfoResults{info, err}
+// Then sets up MediaProcessor.WriteMetadata return parameters for the expectation previously defined by the When method
+func (e *MediaProcessorMockWriteMetadataExpectation) Then(resultFilepath string, err error) *MediaProcessorMock {
+	e.results = &MediaProcessorMockWriteMetadataResults{resultFilepath, err}
 	return e.mock
 }
 
-// Times sets number of times MediaProcessor.GetInfo should be invoked
-func (mmGetInfo *mMediaProcessorMockGetInfo) Times(n uint64) *mMediaProcessorMockGetInfo {
+// Times sets number of times MediaProcessor.WriteMetadata should be invoked
+func (mmWriteMetadata *mMediaProcessorMockWriteMetadata) Times(n uint64) *mMediaProcessorMockWriteMetadata {
 	if n == 0 {
-		mmGetInfo.mock.t.Fatalf("Times of MediaProcessorMock.GetInfo mock can not be zero")
+		mmWriteMetadata.mock.t.Fatalf("Times of MediaProcessorMock.WriteMetadata mock can not be zero")
 	}
-	mm_atomic.StoreUint64(&mmGetInfo.expectedInvocations, n)
-	mmGetInfo.expectedInvocationsOrigin = minimock.CallerInfo(1)
-	return mmGetInfo
+	mm_atomic.StoreUint64(&mmWriteMetadata.expectedInvocations, n)
+	mmWriteMetadata.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmWriteMetadata
 }
 
-func (mmGetInfo *mMediaProcessorMockGetInfo) invocationsDone() bool {
-	if len(mmGetInfo.expectations) == 0 && mmGetInfo.defaultExpectation == nil && mmGetInfo.mock.funcGetInfo == nil {
+func (mmWriteMetadata *mMediaProcessorMockWriteMetadata) invocationsDone() bool {
+	if len(mmWriteMetadata.expectations) == 0 && mmWriteMetadata.defaultExpectation == nil && mmWriteMetadata.mock.funcWriteMetadata == nil {
 		return true
 	}
 
-	totalInvocations := mm_atomic.LoadUint64(&mmGetInfo.mock.afterGetInfoCounter)
-	expectedInvocations := mm_atomic.LoadUint64(&mmGetInfo.expectedInvocations)
+	totalInvocations := mm_atomic.LoadUint64(&mmWriteMetadata.mock.afterWriteMetadataCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmWriteMetadata.expectedInvocations)
 
 	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
 }
 
-// GetInfo implements mm_service.MediaProcessor
-func (mmGetInfo *MediaProcessorMock) GetInfo(ctx context.Context, filepath string) (info *mm_service.MediaInfo, err error) {
-	mm_atomic.AddUint64(&mmGetInfo.beforeGetInfoCounter, 1)
-	defer mm_atomic.AddUint64(&mmGetInfo.afterGetInfoCounter, 1)
+// WriteMetadata implements mm_service.MediaProcessor
+func (mmWriteMetadata *MediaProcessorMock) WriteMetadata(ctx context.Context, filepath string, metadata mm_service.FileMetadata) (resultFilepath string, err error) {
+	mm_atomic.AddUint64(&mmWriteMetadata.beforeWriteMetadataCounter, 1)
+	defer mm_atomic.AddUint64(&mmWriteMetadata.afterWriteMetadataCounter, 1)
 
-	mmGetInfo.t.Helper()
+	mmWriteMetadata.t.Helper()
 
-	if mmGetInfo.inspectFuncGetInfo != nil {
-		mmGetInfo.inspectFuncGetInfo(ctx, filepath)
+	if mmWriteMetadata.inspectFuncWriteMetadata != nil {
+		mmWriteMetadata.inspectFuncWriteMetadata(ctx, filepath, metadata)
 	}
 
-	mm_params := MediaProcessorMockGetInfoParams{ctx, filepath}
+	mm_params := MediaProcessorMockWriteMetadataParams{ctx, filepath, metadata}
 
 	// Record call args
-	mmGetInfo.GetInfoMock.mutex.Lock()
-	mmGetInfo.GetInfoMock.callArgs = append(mmGetInfo.GetInfoMock.callArgs, &mm_params)
-	mmGetInfo.GetInfoMock.mutex.Unlock()
+	mmWriteMetadata.WriteMetadataMock.mutex.Lock()
+	mmWriteMetadata.WriteMetadataMock.callArgs = append(mmWriteMetadata.WriteMetadataMock.callArgs, &mm_params)
+	mmWriteMetadata.WriteMetadataMock.mutex.Unlock()
 
-	for _, e := range mmGetInfo.GetInfoMock.expectations {
+	for _, e := range mmWriteMetadata.WriteMetadataMock.expectations {
 		if minimock.Equal(*e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
-			return e.results.info, e.results.err
+			return e.results.resultFilepath, e.results.err
 		}
 	}
 
-	if mmGetInfo.GetInfoMock.defaultExpectation != nil {
-		mm_atomic.AddUint64(&mmGetInfo.GetInfoMock.defaultExpectation.Counter, 1)
-		mm_want := mmGetInfo.GetInfoMock.defaultExpectation.params
-		mm_want_ptrs := mmGetInfo.GetInfoMock.defaultExpectation.paramPtrs
+	if mmWriteMetadata.WriteMetadataMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmWriteMetadata.WriteMetadataMock.defaultExpectation.Counter, 1)
+		mm_want := mmWriteMetadata.WriteMetadataMock.defaultExpectation.params
+		mm_want_ptrs := mmWriteMetadata.WriteMetadataMock.defaultExpectation.paramPtrs
 
-		mm_got := MediaProcessorMockGetInfoParams{ctx, filepath}
+		mm_got := MediaProcessorMockWriteMetadataParams{ctx, filepath, metadata}
 
 		if mm_want_ptrs != nil {
 
 			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
-				mmGetInfo.t.Errorf("MediaProcessorMock.GetInfo got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
-					mmGetInfo.GetInfoMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+				mmWriteMetadata.t.Errorf("MediaProcessorMock.WriteMetadata got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmWriteMetadata.WriteMetadataMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
 			}
 
 			if mm_want_ptrs.filepath != nil && !minimock.Equal(*mm_want_ptrs.filepath, mm_got.filepath) {
-				mmGetInfo.t.Errorf("MediaProcessorMock.GetInfo got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
-					mmGetInfo.GetInfoMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
+				mmWriteMetadata.t.Errorf("MediaProcessorMock.WriteMetadata got unexpected parameter filepath, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmWriteMetadata.WriteMetadataMock.defaultExpectation.expectationOrigins.originFilepath, *mm_want_ptrs.filepath, mm_got.filepath, minimock.Diff(*mm_want_ptrs.filepath, mm_got.filepath))
+			}
+
+			if mm_want_ptrs.metadata != nil && !minimock.Equal(*mm_want_ptrs.metadata, mm_got.metadata) {
+				mmWriteMetadata.t.Errorf("MediaProcessorMock.WriteMetadata got unexpected parameter metadata, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmWriteMetadata.WriteMetadataMock.defaultExpectation.expectationOrigins.originMetadata, *mm_want_ptrs.metadata, mm_got.metadata, minimock.Diff(*mm_want_ptrs.metadata, mm_got.metadata))
 			}
 
 		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
-			mmGetInfo.t.Errorf("MediaProcessorMock.GetInfo got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
-				mmGetInfo.GetInfoMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+			mmWriteMetadata.t.Errorf("MediaProcessorMock.WriteMetadata got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmWriteMetadata.WriteMetadataMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
 		}
 
-		mm_results := mmGetInfo.GetInfoMock.defaultExpectation.results
+		mm_results := mmWriteMetadata.WriteMetadataMock.defaultExpectation.results
 		if mm_results == nil {
-			mmGetInfo.t.Fatal("No results are set for the MediaProcessorMock.GetInfo")
+			mmWriteMetadata.t.Fatal("No results are set for the MediaProcessorMock.WriteMetadata")
 		}
-		return (*mm_results).info, (*mm_results).err
+		return (*mm_results).resultFilepath, (*mm_results).err
 	}
-	if mmGetInfo.funcGetInfo != nil {
-		return mmGetInfo.funcGetInfo(ctx, filepath)
+	if mmWriteMetadata.funcWriteMetadata != nil {
+		return mmWriteMetadata.funcWriteMetadata(ctx, filepath, metadata)
 	}
-	mmGetInfo.t.Fatalf("Unexpected call to MediaProcessorMock.GetInfo. %v %v", ctx, filepath)
+	mmWriteMetadata.t.Fatalf("Unexpected call to MediaProcessorMock.WriteMetadata. %v %v %v", ctx, filepath, metadata)
 	return
 }
 
-// GetInfoAfterCounter returns a count of finished MediaProcessorMock.GetInfo invocations
-func (mmGetInfo *MediaProcessorMock) GetInfoAfterCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmGetInfo.afterGetInfoCounter)
+// WriteMetadataAfterCounter returns a count of finished MediaProcessorMock.WriteMetadata invocations
+func (mmWriteMetadata *MediaProcessorMock) WriteMetadataAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmWriteMetadata.afterWriteMetadataCounter)
 }
 
-// GetInfoBeforeCounter returns a count of MediaProcessorMock.GetInfo invocations
-func (mmGetInfo *MediaProcessorMock) GetInfoBeforeCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmGetInfo.beforeGetInfoCounter)
+// WriteMetadataBeforeCounter returns a count of MediaProcessorMock.WriteMetadata invocations
+func (mmWriteMetadata *MediaProcessorMock) WriteMetadataBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmWriteMetadata.beforeWriteMetadataCounter)
 }
 
-// Calls returns a list of arguments used in each call to MediaProcessorMock.GetInfo.
+// Calls returns a list of arguments used in each call to MediaProcessorMock.WriteMetadata.
 // The list is in the same order as the calls were made (i.e. recent calls have a higher index)
-func (mmGetInfo *mMediaProcessorMockGetInfo) Calls() []*MediaProcessorMockGetInfoParams {
-	mmGetInfo.mutex.RLock()
+func (mmWriteMetadata *mMediaProcessorMockWriteMetadata) Calls() []*MediaProcessorMockWriteMetadataParams {
+	mmWriteMetadata.mutex.RLock()
 
-	argCopy := make([]*MediaProcessorMockGetInfoParams, len(mmGetInfo.callArgs))
-	copy(argCopy, mmGetInfo.callArgs)
+	argCopy := make([]*MediaProcessorMockWriteMetadataParams, len(mmWriteMetadata.callArgs))
+	copy(argCopy, mmWriteMetadata.callArgs)
 
-	mmGetInfo.mutex.RUnlock()
+	mmWriteMetadata.mutex.RUnlock()
 
 	return argCopy
 }
 
-// MinimockGetInfoDone returns true if the count of the GetInfo invocations corresponds
+// MinimockWriteMetadataDone returns true if the count of the WriteMetadata invocations corresponds
 // the number of defined expectations
-func (m *MediaProcessorMock) MinimockGetInfoDone() bool {
-	if m.GetInfoMock.optional {
+func (m *MediaProcessorMock) MinimockWriteMetadataDone() bool {
+	if m.WriteMetadataMock.optional {
 		// Optional methods provide '0 or more' call count restriction.
 		return true
 	}
 
-	for _, e := range m.GetInfoMock.expectations {
+	for _, e := range m.WriteMetadataMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
 			return false
 		}
 	}
 
-	return m.GetInfoMock.invocationsDone()
+	return m.WriteMetadataMock.invocationsDone()
 }
 
-// MinimockGetInfoInspect logs each unmet expectation
-func (m *MediaProcessorMock) MinimockGetInfoInspect() {
-	for _, e := range m.GetInfoMock.expectations {
+// MinimockWriteMetadataInspect logs each unmet expectation
+func (m *MediaProcessorMock) MinimockWriteMetadataInspect() {
+	for _, e := range m.WriteMetadataMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
-			m.t.Errorf("Expected call to MediaProcessorMock.GetInfo at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+			m.t.Errorf("Expected call to MediaProcessorMock.WriteMetadata at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
 		}
 	}
 
-	afterGetInfoCounter := mm_atomic.LoadUint64(&m.afterGetInfoCounter)
+	afterWriteMetadataCounter := mm_atomic.LoadUint64(&m.afterWriteMetadataCounter)
 	// if default expectation was set then invocations count should be greater than zero
-	if m.GetInfoMock.defaultExpectation != nil && afterGetInfoCounter < 1 {
-		if m.GetInfoMock.defaultExpectation.params == nil {
-			m.t.Errorf("Expected call to MediaProcessorMock.GetInfo at\n%s", m.GetInfoMock.defaultExpectation.returnOrigin)
+	if m.WriteMetadataMock.defaultExpectation != nil && afterWriteMetadataCounter < 1 {
+		if m.WriteMetadataMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to MediaProcessorMock.WriteMetadata at\n%s", m.WriteMetadataMock.defaultExpectation.returnOrigin)
 		} else {
-			m.t.Errorf("Expected call to MediaProcessorMock.GetInfo at\n%s with params: %#v", m.GetInfoMock.defaultExpectation.expectationOrigins.origin, *m.GetInfoMock.defaultExpectation.params)
+			m.t.Errorf("Expected call to MediaProcessorMock.WriteMetadata at\n%s with params: %#v", m.WriteMetadataMock.defaultExpectation.expectationOrigins.origin, *m.WriteMetadataMock.defaultExpectation.params)
 		}
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcGetInfo != nil && afterGetInfoCounter < 1 {
-		m.t.Errorf("Expected call to MediaProcessorMock.GetInfo at\n%s", m.funcGetInfoOrigin)
+	if m.funcWriteMetadata != nil && afterWriteMetadataCounter < 1 {
+		m.t.Errorf("Expected call to MediaProcessorMock.WriteMetadata at\n%s", m.funcWriteMetadataOrigin)
 	}
 
-	if !m.GetInfoMock.invocationsDone() && afterGetInfoCounter > 0 {
-		m.t.Errorf("Expected %d calls to MediaProcessorMock.GetInfo at\n%s but found %d calls",
-			mm_atomic.LoadUint64(&m.GetInfoMock.expectedInvocations), m.GetInfoMock.expectedInvocationsOrigin, afterGetInfoCounter)
+	if !m.WriteMetadataMock.invocationsDone() && afterWriteMetadataCounter > 0 {
+		m.t.Errorf("Expected %d calls to MediaProcessorMock.WriteMetadata at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.WriteMetadataMock.expectedInvocations), m.WriteMetadataMock.expectedInvocationsOrigin, afterWriteMetadataCounter)
 	}
 }
 
@@ -1157,9 +6887,39 @@ func (m *MediaProcessorMock) MinimockFinish() {
 		if !m.minimockDone() {
 			m.MinimockAddChapterTagsInspect()
 
+			m.MinimockChangeSpeedInspect()
+
+			m.MinimockClipInspect()
+
+			m.MinimockComputePeaksInspect()
+
 			m.MinimockConcatenateInspect()
 
+			m.MinimockContactSheetInspect()
+
+			m.MinimockDetectSilenceChaptersInspect()
+
+			m.MinimockExtractAudioInspect()
+
+			m.MinimockExtractCoverArtInspect()
+
 			m.MinimockGetInfoInspect()
+
+			m.MinimockNormalizeInspect()
+
+			m.MinimockPosterFrameInspect()
+
+			m.MinimockPreviewClipInspect()
+
+			m.MinimockRenderWaveformInspect()
+
+			m.MinimockSplitInspect()
+
+			m.MinimockTranscodeInspect()
+
+			m.MinimockTrimSilenceInspect()
+
+			m.MinimockWriteMetadataInspect()
 		}
 	})
 }
@@ -1184,6 +6944,21 @@ func (m *MediaProcessorMock) minimockDone() bool {
 	done := true
 	return done &&
 		m.MinimockAddChapterTagsDone() &&
+		m.MinimockChangeSpeedDone() &&
+		m.MinimockClipDone() &&
+		m.MinimockComputePeaksDone() &&
 		m.MinimockConcatenateDone() &&
-		m.MinimockGetInfoDone()
+		m.MinimockContactSheetDone() &&
+		m.MinimockDetectSilenceChaptersDone() &&
+		m.MinimockExtractAudioDone() &&
+		m.MinimockExtractCoverArtDone() &&
+		m.MinimockGetInfoDone() &&
+		m.MinimockNormalizeDone() &&
+		m.MinimockPosterFrameDone() &&
+		m.MinimockPreviewClipDone() &&
+		m.MinimockRenderWaveformDone() &&
+		m.MinimockSplitDone() &&
+		m.MinimockTranscodeDone() &&
+		m.MinimockTrimSilenceDone() &&
+		m.MinimockWriteMetadataDone()
 }
@@ -0,0 +1,509 @@
+// Code generated by http://github.com/gojuno/minimock (v3.4.7). DO NOT EDIT.
+
+package mocks
+
+import (
+	"context"
+	"sync"
+	mm_atomic "sync/atomic"
+	mm_time "time"
+
+	mm_service "github.com/dir01/mediary/service"
+	"github.com/gojuno/minimock/v3"
+)
+
+// SectionDownloaderMock implements mm_service.SectionDownloader
+type SectionDownloaderMock struct {
+	t          minimock.Tester
+	finishOnce sync.Once
+
+	funcDownloadSections          func(ctx context.Context, url string, variant string, ranges []mm_service.TimeRange, fast bool) (filepaths []string, err error)
+	funcDownloadSectionsOrigin    string
+	inspectFuncDownloadSections   func(ctx context.Context, url string, variant string, ranges []mm_service.TimeRange, fast bool)
+	afterDownloadSectionsCounter  uint64
+	beforeDownloadSectionsCounter uint64
+	DownloadSectionsMock          mSectionDownloaderMockDownloadSections
+}
+
+// NewSectionDownloaderMock returns a mock for mm_service.SectionDownloader
+func NewSectionDownloaderMock(t minimock.Tester) *SectionDownloaderMock {
+	m := &SectionDownloaderMock{t: t}
+
+	if controller, ok := t.(minimock.MockController); ok {
+		controller.RegisterMocker(m)
+	}
+
+	m.DownloadSectionsMock = mSectionDownloaderMockDownloadSections{mock: m}
+	m.DownloadSectionsMock.callArgs = []*SectionDownloaderMockDownloadSectionsParams{}
+
+	t.Cleanup(m.MinimockFinish)
+
+	return m
+}
+
+type mSectionDownloaderMockDownloadSections struct {
+	optional           bool
+	mock               *SectionDownloaderMock
+	defaultExpectation *SectionDownloaderMockDownloadSectionsExpectation
+	expectations       []*SectionDownloaderMockDownloadSectionsExpectation
+
+	callArgs []*SectionDownloaderMockDownloadSectionsParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// SectionDownloaderMockDownloadSectionsExpectation specifies expectation struct of the SectionDownloader.DownloadSections
+type SectionDownloaderMockDownloadSectionsExpectation struct {
+	mock               *SectionDownloaderMock
+	params             *SectionDownloaderMockDownloadSectionsParams
+	paramPtrs          *SectionDownloaderMockDownloadSectionsParamPtrs
+	expectationOrigins SectionDownloaderMockDownloadSectionsExpectationOrigins
+	results            *SectionDownloaderMockDownloadSectionsResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// SectionDownloaderMockDownloadSectionsParams contains parameters of the SectionDownloader.DownloadSections
+type SectionDownloaderMockDownloadSectionsParams struct {
+	ctx     context.Context
+	url     string
+	variant string
+	ranges  []mm_service.TimeRange
+	fast    bool
+}
+
+// SectionDownloaderMockDownloadSectionsParamPtrs contains pointers to parameters of the SectionDownloader.DownloadSections
+type SectionDownloaderMockDownloadSectionsParamPtrs struct {
+	ctx     *context.Context
+	url     *string
+	variant *string
+	ranges  *[]mm_service.TimeRange
+	fast    *bool
+}
+
+// SectionDownloaderMockDownloadSectionsResults contains results of the SectionDownloader.DownloadSections
+type SectionDownloaderMockDownloadSectionsResults struct {
+	filepaths []string
+	err       error
+}
+
+// SectionDownloaderMockDownloadSectionsOrigins contains origins of expectations of the SectionDownloader.DownloadSections
+type SectionDownloaderMockDownloadSectionsExpectationOrigins struct {
+	origin        string
+	originCtx     string
+	originUrl     string
+	originVariant string
+	originRanges  string
+	originFast    string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmDownloadSections *mSectionDownloaderMockDownloadSections) Optional() *mSectionDownloaderMockDownloadSections {
+	mmDownloadSections.optional = true
+	return mmDownloadSections
+}
+
+// Expect sets up expected params for SectionDownloader.DownloadSections
+func (mmDownloadSections *mSectionDownloaderMockDownloadSections) Expect(ctx context.Context, url string, variant string, ranges []mm_service.TimeRange, fast bool) *mSectionDownloaderMockDownloadSections {
+	if mmDownloadSections.mock.funcDownloadSections != nil {
+		mmDownloadSections.mock.t.Fatalf("SectionDownloaderMock.DownloadSections mock is already set by Set")
+	}
+
+	if mmDownloadSections.defaultExpectation == nil {
+		mmDownloadSections.defaultExpectation = &SectionDownloaderMockDownloadSectionsExpectation{}
+	}
+
+	if mmDownloadSections.defaultExpectation.paramPtrs != nil {
+		mmDownloadSections.mock.t.Fatalf("SectionDownloaderMock.DownloadSections mock is already set by ExpectParams functions")
+	}
+
+	mmDownloadSections.defaultExpectation.params = &SectionDownloaderMockDownloadSectionsParams{ctx, url, variant, ranges, fast}
+	mmDownloadSections.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmDownloadSections.expectations {
+		if minimock.Equal(e.params, mmDownloadSections.defaultExpectation.params) {
+			mmDownloadSections.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmDownloadSections.defaultExpectation.params)
+		}
+	}
+
+	return mmDownloadSections
+}
+
+// ExpectCtxParam1 sets up expected param ctx for SectionDownloader.DownloadSections
+func (mmDownloadSections *mSectionDownloaderMockDownloadSections) ExpectCtxParam1(ctx context.Context) *mSectionDownloaderMockDownloadSections {
+	if mmDownloadSections.mock.funcDownloadSections != nil {
+		mmDownloadSections.mock.t.Fatalf("SectionDownloaderMock.DownloadSections mock is already set by Set")
+	}
+
+	if mmDownloadSections.defaultExpectation == nil {
+		mmDownloadSections.defaultExpectation = &SectionDownloaderMockDownloadSectionsExpectation{}
+	}
+
+	if mmDownloadSections.defaultExpectation.params != nil {
+		mmDownloadSections.mock.t.Fatalf("SectionDownloaderMock.DownloadSections mock is already set by Expect")
+	}
+
+	if mmDownloadSections.defaultExpectation.paramPtrs == nil {
+		mmDownloadSections.defaultExpectation.paramPtrs = &SectionDownloaderMockDownloadSectionsParamPtrs{}
+	}
+	mmDownloadSections.defaultExpectation.paramPtrs.ctx = &ctx
+	mmDownloadSections.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmDownloadSections
+}
+
+// ExpectUrlParam2 sets up expected param url for SectionDownloader.DownloadSections
+func (mmDownloadSections *mSectionDownloaderMockDownloadSections) ExpectUrlParam2(url string) *mSectionDownloaderMockDownloadSections {
+	if mmDownloadSections.mock.funcDownloadSections != nil {
+		mmDownloadSections.mock.t.Fatalf("SectionDownloaderMock.DownloadSections mock is already set by Set")
+	}
+
+	if mmDownloadSections.defaultExpectation == nil {
+		mmDownloadSections.defaultExpectation = &SectionDownloaderMockDownloadSectionsExpectation{}
+	}
+
+	if mmDownloadSections.defaultExpectation.params != nil {
+		mmDownloadSections.mock.t.Fatalf("SectionDownloaderMock.DownloadSections mock is already set by Expect")
+	}
+
+	if mmDownloadSections.defaultExpectation.paramPtrs == nil {
+		mmDownloadSections.defaultExpectation.paramPtrs = &SectionDownloaderMockDownloadSectionsParamPtrs{}
+	}
+	mmDownloadSections.defaultExpectation.paramPtrs.url = &url
+	mmDownloadSections.defaultExpectation.expectationOrigins.originUrl = minimock.CallerInfo(1)
+
+	return mmDownloadSections
+}
+
+// ExpectVariantParam3 sets up expected param variant for SectionDownloader.DownloadSections
+func (mmDownloadSections *mSectionDownloaderMockDownloadSections) ExpectVariantParam3(variant string) *mSectionDownloaderMockDownloadSections {
+	if mmDownloadSections.mock.funcDownloadSections != nil {
+		mmDownloadSections.mock.t.Fatalf("SectionDownloaderMock.DownloadSections mock is already set by Set")
+	}
+
+	if mmDownloadSections.defaultExpectation == nil {
+		mmDownloadSections.defaultExpectation = &SectionDownloaderMockDownloadSectionsExpectation{}
+	}
+
+	if mmDownloadSections.defaultExpectation.params != nil {
+		mmDownloadSections.mock.t.Fatalf("SectionDownloaderMock.DownloadSections mock is already set by Expect")
+	}
+
+	if mmDownloadSections.defaultExpectation.paramPtrs == nil {
+		mmDownloadSections.defaultExpectation.paramPtrs = &SectionDownloaderMockDownloadSectionsParamPtrs{}
+	}
+	mmDownloadSections.defaultExpectation.paramPtrs.variant = &variant
+	mmDownloadSections.defaultExpectation.expectationOrigins.originVariant = minimock.CallerInfo(1)
+
+	return mmDownloadSections
+}
+
+// ExpectRangesParam4 sets up expected param ranges for SectionDownloader.DownloadSections
+func (mmDownloadSections *mSectionDownloaderMockDownloadSections) ExpectRangesParam4(ranges []mm_service.TimeRange) *mSectionDownloaderMockDownloadSections {
+	if mmDownloadSections.mock.funcDownloadSections != nil {
+		mmDownloadSections.mock.t.Fatalf("SectionDownloaderMock.DownloadSections mock is already set by Set")
+	}
+
+	if mmDownloadSections.defaultExpectation == nil {
+		mmDownloadSections.defaultExpectation = &SectionDownloaderMockDownloadSectionsExpectation{}
+	}
+
+	if mmDownloadSections.defaultExpectation.params != nil {
+		mmDownloadSections.mock.t.Fatalf("SectionDownloaderMock.DownloadSections mock is already set by Expect")
+	}
+
+	if mmDownloadSections.defaultExpectation.paramPtrs == nil {
+		mmDownloadSections.defaultExpectation.paramPtrs = &SectionDownloaderMockDownloadSectionsParamPtrs{}
+	}
+	mmDownloadSections.defaultExpectation.paramPtrs.ranges = &ranges
+	mmDownloadSections.defaultExpectation.expectationOrigins.originRanges = minimock.CallerInfo(1)
+
+	return mmDownloadSections
+}
+
+// ExpectFastParam5 sets up expected param fast for SectionDownloader.DownloadSections
+func (mmDownloadSections *mSectionDownloaderMockDownloadSections) ExpectFastParam5(fast bool) *mSectionDownloaderMockDownloadSections {
+	if mmDownloadSections.mock.funcDownloadSections != nil {
+		mmDownloadSections.mock.t.Fatalf("SectionDownloaderMock.DownloadSections mock is already set by Set")
+	}
+
+	if mmDownloadSections.defaultExpectation == nil {
+		mmDownloadSections.defaultExpectation = &SectionDownloaderMockDownloadSectionsExpectation{}
+	}
+
+	if mmDownloadSections.defaultExpectation.params != nil {
+		mmDownloadSections.mock.t.Fatalf("SectionDownloaderMock.DownloadSections mock is already set by Expect")
+	}
+
+	if mmDownloadSections.defaultExpectation.paramPtrs == nil {
+		mmDownloadSections.defaultExpectation.paramPtrs = &SectionDownloaderMockDownloadSectionsParamPtrs{}
+	}
+	mmDownloadSections.defaultExpectation.paramPtrs.fast = &fast
+	mmDownloadSections.defaultExpectation.expectationOrigins.originFast = minimock.CallerInfo(1)
+
+	return mmDownloadSections
+}
+
+// Inspect accepts an inspector function that has same arguments as the SectionDownloader.DownloadSections
+func (mmDownloadSections *mSectionDownloaderMockDownloadSections) Inspect(f func(ctx context.Context, url string, variant string, ranges []mm_service.TimeRange, fast bool)) *mSectionDownloaderMockDownloadSections {
+	if mmDownloadSections.mock.inspectFuncDownloadSections != nil {
+		mmDownloadSections.mock.t.Fatalf("Inspect function is already set for SectionDownloaderMock.DownloadSections")
+	}
+
+	mmDownloadSections.mock.inspectFuncDownloadSections = f
+
+	return mmDownloadSections
+}
+
+// Return sets up results that will be returned by SectionDownloader.DownloadSections
+func (mmDownloadSections *mSectionDownloaderMockDownloadSections) Return(filepaths []string, err error) *SectionDownloaderMock {
+	if mmDownloadSections.mock.funcDownloadSections != nil {
+		mmDownloadSections.mock.t.Fatalf("SectionDownloaderMock.DownloadSections mock is already set by Set")
+	}
+
+	if mmDownloadSections.defaultExpectation == nil {
+		mmDownloadSections.defaultExpectation = &SectionDownloaderMockDownloadSectionsExpectation{mock: mmDownloadSections.mock}
+	}
+	mmDownloadSections.defaultExpectation.results = &SectionDownloaderMockDownloadSectionsResults{filepaths, err}
+	mmDownloadSections.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmDownloadSections.mock
+}
+
+// Set uses given function f to mock the SectionDownloader.DownloadSections method
+func (mmDownloadSections *mSectionDownloaderMockDownloadSections) Set(f func(ctx context.Context, url string, variant string, ranges []mm_service.TimeRange, fast bool) (filepaths []string, err error)) *SectionDownloaderMock {
+	if mmDownloadSections.defaultExpectation != nil {
+		mmDownloadSections.mock.t.Fatalf("Default expectation is already set for the SectionDownloader.DownloadSections method")
+	}
+
+	if len(mmDownloadSections.expectations) > 0 {
+		mmDownloadSections.mock.t.Fatalf("Some expectations are already set for the SectionDownloader.DownloadSections method")
+	}
+
+	mmDownloadSections.mock.funcDownloadSections = f
+	mmDownloadSections.mock.funcDownloadSectionsOrigin = minimock.CallerInfo(1)
+	return mmDownloadSections.mock
+}
+
+// When sets expectation for the SectionDownloader.DownloadSections which will trigger the result defined by the following
+// Then helper
+func (mmDownloadSections *mSectionDownloaderMockDownloadSections) When(ctx context.Context, url string, variant string, ranges []mm_service.TimeRange, fast bool) *SectionDownloaderMockDownloadSectionsExpectation {
+	if mmDownloadSections.mock.funcDownloadSections != nil {
+		mmDownloadSections.mock.t.Fatalf("SectionDownloaderMock.DownloadSections mock is already set by Set")
+	}
+
+	expectation := &SectionDownloaderMockDownloadSectionsExpectation{
+		mock:               mmDownloadSections.mock,
+		params:             &SectionDownloaderMockDownloadSectionsParams{ctx, url, variant, ranges, fast},
+		expectationOrigins: SectionDownloaderMockDownloadSectionsExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmDownloadSections.expectations = append(mmDownloadSections.expectations, expectation)
+	return expectation
+}
+
+// Then sets up SectionDownloader.DownloadSections return parameters for the expectation previously defined by the When method
+func (e *SectionDownloaderMockDownloadSectionsExpectation) Then(filepaths []string, err error) *SectionDownloaderMock {
+	e.results = &SectionDownloaderMockDownloadSectionsResults{filepaths, err}
+	return e.mock
+}
+
+// Times sets number of times SectionDownloader.DownloadSections should be invoked
+func (mmDownloadSections *mSectionDownloaderMockDownloadSections) Times(n uint64) *mSectionDownloaderMockDownloadSections {
+	if n == 0 {
+		mmDownloadSections.mock.t.Fatalf("Times of SectionDownloaderMock.DownloadSections mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmDownloadSections.expectedInvocations, n)
+	mmDownloadSections.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmDownloadSections
+}
+
+func (mmDownloadSections *mSectionDownloaderMockDownloadSections) invocationsDone() bool {
+	if len(mmDownloadSections.expectations) == 0 && mmDownloadSections.defaultExpectation == nil && mmDownloadSections.mock.funcDownloadSections == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmDownloadSections.mock.afterDownloadSectionsCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmDownloadSections.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// DownloadSections implements mm_service.SectionDownloader
+func (mmDownloadSections *SectionDownloaderMock) DownloadSections(ctx context.Context, url string, variant string, ranges []mm_service.TimeRange, fast bool) (filepaths []string, err error) {
+	mm_atomic.AddUint64(&mmDownloadSections.beforeDownloadSectionsCounter, 1)
+	defer mm_atomic.AddUint64(&mmDownloadSections.afterDownloadSectionsCounter, 1)
+
+	mmDownloadSections.t.Helper()
+
+	if mmDownloadSections.inspectFuncDownloadSections != nil {
+		mmDownloadSections.inspectFuncDownloadSections(ctx, url, variant, ranges, fast)
+	}
+
+	mm_params := SectionDownloaderMockDownloadSectionsParams{ctx, url, variant, ranges, fast}
+
+	// Record call args
+	mmDownloadSections.DownloadSectionsMock.mutex.Lock()
+	mmDownloadSections.DownloadSectionsMock.callArgs = append(mmDownloadSections.DownloadSectionsMock.callArgs, &mm_params)
+	mmDownloadSections.DownloadSectionsMock.mutex.Unlock()
+
+	for _, e := range mmDownloadSections.DownloadSectionsMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.filepaths, e.results.err
+		}
+	}
+
+	if mmDownloadSections.DownloadSectionsMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmDownloadSections.DownloadSectionsMock.defaultExpectation.Counter, 1)
+		mm_want := mmDownloadSections.DownloadSectionsMock.defaultExpectation.params
+		mm_want_ptrs := mmDownloadSections.DownloadSectionsMock.defaultExpectation.paramPtrs
+
+		mm_got := SectionDownloaderMockDownloadSectionsParams{ctx, url, variant, ranges, fast}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmDownloadSections.t.Errorf("SectionDownloaderMock.DownloadSections got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDownloadSections.DownloadSectionsMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.url != nil && !minimock.Equal(*mm_want_ptrs.url, mm_got.url) {
+				mmDownloadSections.t.Errorf("SectionDownloaderMock.DownloadSections got unexpected parameter url, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDownloadSections.DownloadSectionsMock.defaultExpectation.expectationOrigins.originUrl, *mm_want_ptrs.url, mm_got.url, minimock.Diff(*mm_want_ptrs.url, mm_got.url))
+			}
+
+			if mm_want_ptrs.variant != nil && !minimock.Equal(*mm_want_ptrs.variant, mm_got.variant) {
+				mmDownloadSections.t.Errorf("SectionDownloaderMock.DownloadSections got unexpected parameter variant, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDownloadSections.DownloadSectionsMock.defaultExpectation.expectationOrigins.originVariant, *mm_want_ptrs.variant, mm_got.variant, minimock.Diff(*mm_want_ptrs.variant, mm_got.variant))
+			}
+
+			if mm_want_ptrs.ranges != nil && !minimock.Equal(*mm_want_ptrs.ranges, mm_got.ranges) {
+				mmDownloadSections.t.Errorf("SectionDownloaderMock.DownloadSections got unexpected parameter ranges, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDownloadSections.DownloadSectionsMock.defaultExpectation.expectationOrigins.originRanges, *mm_want_ptrs.ranges, mm_got.ranges, minimock.Diff(*mm_want_ptrs.ranges, mm_got.ranges))
+			}
+
+			if mm_want_ptrs.fast != nil && !minimock.Equal(*mm_want_ptrs.fast, mm_got.fast) {
+				mmDownloadSections.t.Errorf("SectionDownloaderMock.DownloadSections got unexpected parameter fast, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDownloadSections.DownloadSectionsMock.defaultExpectation.expectationOrigins.originFast, *mm_want_ptrs.fast, mm_got.fast, minimock.Diff(*mm_want_ptrs.fast, mm_got.fast))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmDownloadSections.t.Errorf("SectionDownloaderMock.DownloadSections got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmDownloadSections.DownloadSectionsMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmDownloadSections.DownloadSectionsMock.defaultExpectation.results
+		if mm_results == nil {
+			mmDownloadSections.t.Fatal("No results are set for the SectionDownloaderMock.DownloadSections")
+		}
+		return (*mm_results).filepaths, (*mm_results).err
+	}
+	if mmDownloadSections.funcDownloadSections != nil {
+		return mmDownloadSections.funcDownloadSections(ctx, url, variant, ranges, fast)
+	}
+	mmDownloadSections.t.Fatalf("Unexpected call to SectionDownloaderMock.DownloadSections. %v %v %v %v %v", ctx, url, variant, ranges, fast)
+	return
+}
+
+// DownloadSectionsAfterCounter returns a count of finished SectionDownloaderMock.DownloadSections invocations
+func (mmDownloadSections *SectionDownloaderMock) DownloadSectionsAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmDownloadSections.afterDownloadSectionsCounter)
+}
+
+// DownloadSectionsBeforeCounter returns a count of SectionDownloaderMock.DownloadSections invocations
+func (mmDownloadSections *SectionDownloaderMock) DownloadSectionsBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmDownloadSections.beforeDownloadSectionsCounter)
+}
+
+// Calls returns a list of arguments used in each call to SectionDownloaderMock.DownloadSections.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmDownloadSections *mSectionDownloaderMockDownloadSections) Calls() []*SectionDownloaderMockDownloadSectionsParams {
+	mmDownloadSections.mutex.RLock()
+
+	argCopy := make([]*SectionDownloaderMockDownloadSectionsParams, len(mmDownloadSections.callArgs))
+	copy(argCopy, mmDownloadSections.callArgs)
+
+	mmDownloadSections.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockDownloadSectionsDone returns true if the count of the DownloadSections invocations corresponds
+// the number of defined expectations
+func (m *SectionDownloaderMock) MinimockDownloadSectionsDone() bool {
+	if m.DownloadSectionsMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.DownloadSectionsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.DownloadSectionsMock.invocationsDone()
+}
+
+// MinimockDownloadSectionsInspect logs each unmet expectation
+func (m *SectionDownloaderMock) MinimockDownloadSectionsInspect() {
+	for _, e := range m.DownloadSectionsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to SectionDownloaderMock.DownloadSections at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterDownloadSectionsCounter := mm_atomic.LoadUint64(&m.afterDownloadSectionsCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.DownloadSectionsMock.defaultExpectation != nil && afterDownloadSectionsCounter < 1 {
+		if m.DownloadSectionsMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to SectionDownloaderMock.DownloadSections at\n%s", m.DownloadSectionsMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to SectionDownloaderMock.DownloadSections at\n%s with params: %#v", m.DownloadSectionsMock.defaultExpectation.expectationOrigins.origin, *m.DownloadSectionsMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcDownloadSections != nil && afterDownloadSectionsCounter < 1 {
+		m.t.Errorf("Expected call to SectionDownloaderMock.DownloadSections at\n%s", m.funcDownloadSectionsOrigin)
+	}
+
+	if !m.DownloadSectionsMock.invocationsDone() && afterDownloadSectionsCounter > 0 {
+		m.t.Errorf("Expected %d calls to SectionDownloaderMock.DownloadSections at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.DownloadSectionsMock.expectedInvocations), m.DownloadSectionsMock.expectedInvocationsOrigin, afterDownloadSectionsCounter)
+	}
+}
+
+// MinimockFinish checks that all mocked methods have been called the expected number of times
+func (m *SectionDownloaderMock) MinimockFinish() {
+	m.finishOnce.Do(func() {
+		if !m.minimockDone() {
+			m.MinimockDownloadSectionsInspect()
+		}
+	})
+}
+
+// MinimockWait waits for all mocked methods to be called the expected number of times
+func (m *SectionDownloaderMock) MinimockWait(timeout mm_time.Duration) {
+	timeoutCh := mm_time.After(timeout)
+	for {
+		if m.minimockDone() {
+			return
+		}
+		select {
+		case <-timeoutCh:
+			m.MinimockFinish()
+			return
+		case <-mm_time.After(10 * mm_time.Millisecond):
+		}
+	}
+}
+
+func (m *SectionDownloaderMock) minimockDone() bool {
+	done := true
+	return done &&
+		m.MinimockDownloadSectionsDone()
+}
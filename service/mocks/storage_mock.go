@@ -24,13 +24,20 @@ type StorageMock struct {
 	beforeGetJobCounter uint64
 	GetJobMock          mStorageMockGetJob
 
-	funcGetMetadata          func(ctx context.Context, url string) (mp1 *mm_service.Metadata, err error)
+	funcGetMetadata          func(ctx context.Context, url string, downloader string) (mp1 *mm_service.Metadata, err error)
 	funcGetMetadataOrigin    string
-	inspectFuncGetMetadata   func(ctx context.Context, url string)
+	inspectFuncGetMetadata   func(ctx context.Context, url string, downloader string)
 	afterGetMetadataCounter  uint64
 	beforeGetMetadataCounter uint64
 	GetMetadataMock          mStorageMockGetMetadata
 
+	funcGetWaveform          func(ctx context.Context, jobID string, part int) (wp1 *mm_service.WaveformPeaks, err error)
+	funcGetWaveformOrigin    string
+	inspectFuncGetWaveform   func(ctx context.Context, jobID string, part int)
+	afterGetWaveformCounter  uint64
+	beforeGetWaveformCounter uint64
+	GetWaveformMock          mStorageMockGetWaveform
+
 	funcSaveJob          func(ctx context.Context, job *mm_service.Job) (err error)
 	funcSaveJobOrigin    string
 	inspectFuncSaveJob   func(ctx context.Context, job *mm_service.Job)
@@ -38,12 +45,19 @@ type StorageMock struct {
 	beforeSaveJobCounter uint64
 	SaveJobMock          mStorageMockSaveJob
 
-	funcSaveMetadata          func(ctx context.Context, metadata *mm_service.Metadata) (err error)
+	funcSaveMetadata          func(ctx context.Context, downloader string, metadata *mm_service.Metadata) (err error)
 	funcSaveMetadataOrigin    string
-	inspectFuncSaveMetadata   func(ctx context.Context, metadata *mm_service.Metadata)
+	inspectFuncSaveMetadata   func(ctx context.Context, downloader string, metadata *mm_service.Metadata)
 	afterSaveMetadataCounter  uint64
 	beforeSaveMetadataCounter uint64
 	SaveMetadataMock          mStorageMockSaveMetadata
+
+	funcSaveWaveform          func(ctx context.Context, jobID string, part int, peaks *mm_service.WaveformPeaks) (err error)
+	funcSaveWaveformOrigin    string
+	inspectFuncSaveWaveform   func(ctx context.Context, jobID string, part int, peaks *mm_service.WaveformPeaks)
+	afterSaveWaveformCounter  uint64
+	beforeSaveWaveformCounter uint64
+	SaveWaveformMock          mStorageMockSaveWaveform
 }
 
 // NewStorageMock returns a mock for mm_service.Storage
@@ -60,12 +74,18 @@ func NewStorageMock(t minimock.Tester) *StorageMock {
 	m.GetMetadataMock = mStorageMockGetMetadata{mock: m}
 	m.GetMetadataMock.callArgs = []*StorageMockGetMetadataParams{}
 
+	m.GetWaveformMock = mStorageMockGetWaveform{mock: m}
+	m.GetWaveformMock.callArgs = []*StorageMockGetWaveformParams{}
+
 	m.SaveJobMock = mStorageMockSaveJob{mock: m}
 	m.SaveJobMock.callArgs = []*StorageMockSaveJobParams{}
 
 	m.SaveMetadataMock = mStorageMockSaveMetadata{mock: m}
 	m.SaveMetadataMock.callArgs = []*StorageMockSaveMetadataParams{}
 
+	m.SaveWaveformMock = mStorageMockSaveWaveform{mock: m}
+	m.SaveWaveformMock.callArgs = []*StorageMockSaveWaveformParams{}
+
 	t.Cleanup(m.MinimockFinish)
 
 	return m
@@ -440,14 +460,16 @@ type StorageMockGetMetadataExpectation struct {
 
 // StorageMockGetMetadataParams contains parameters of the Storage.GetMetadata
 type StorageMockGetMetadataParams struct {
-	ctx context.Context
-	url string
+	ctx        context.Context
+	url        string
+	downloader string
 }
 
 // StorageMockGetMetadataParamPtrs contains pointers to parameters of the Storage.GetMetadata
 type StorageMockGetMetadataParamPtrs struct {
-	ctx *context.Context
-	url *string
+	ctx        *context.Context
+	url        *string
+	downloader *string
 }
 
 // StorageMockGetMetadataResults contains results of the Storage.GetMetadata
@@ -458,9 +480,10 @@ type StorageMockGetMetadataResults struct {
 
 // StorageMockGetMetadataOrigins contains origins of expectations of the Storage.GetMetadata
 type StorageMockGetMetadataExpectationOrigins struct {
-	origin    string
-	originCtx string
-	originUrl string
+	origin           string
+	originCtx        string
+	originUrl        string
+	originDownloader string
 }
 
 // Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
@@ -474,7 +497,7 @@ func (mmGetMetadata *mStorageMockGetMetadata) Optional() *mStorageMockGetMetadat
 }
 
 // Expect sets up expected params for Storage.GetMetadata
-func (mmGetMetadata *mStorageMockGetMetadata) Expect(ctx context.Context, url string) *mStorageMockGetMetadata {
+func (mmGetMetadata *mStorageMockGetMetadata) Expect(ctx context.Context, url string, downloader string) *mStorageMockGetMetadata {
 	if mmGetMetadata.mock.funcGetMetadata != nil {
 		mmGetMetadata.mock.t.Fatalf("StorageMock.GetMetadata mock is already set by Set")
 	}
@@ -487,7 +510,7 @@ func (mmGetMetadata *mStorageMockGetMetadata) Expect(ctx context.Context, url st
 		mmGetMetadata.mock.t.Fatalf("StorageMock.GetMetadata mock is already set by ExpectParams functions")
 	}
 
-	mmGetMetadata.defaultExpectation.params = &StorageMockGetMetadataParams{ctx, url}
+	mmGetMetadata.defaultExpectation.params = &StorageMockGetMetadataParams{ctx, url, downloader}
 	mmGetMetadata.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
 	for _, e := range mmGetMetadata.expectations {
 		if minimock.Equal(e.params, mmGetMetadata.defaultExpectation.params) {
@@ -544,8 +567,31 @@ func (mmGetMetadata *mStorageMockGetMetadata) ExpectUrlParam2(url string) *mStor
 	return mmGetMetadata
 }
 
+// ExpectDownloaderParam3 sets up expected param downloader for Storage.GetMetadata
+func (mmGetMetadata *mStorageMockGetMetadata) ExpectDownloaderParam3(downloader string) *mStorageMockGetMetadata {
+	if mmGetMetadata.mock.funcGetMetadata != nil {
+		mmGetMetadata.mock.t.Fatalf("StorageMock.GetMetadata mock is already set by Set")
+	}
+
+	if mmGetMetadata.defaultExpectation == nil {
+		mmGetMetadata.defaultExpectation = &StorageMockGetMetadataExpectation{}
+	}
+
+	if mmGetMetadata.defaultExpectation.params != nil {
+		mmGetMetadata.mock.t.Fatalf("StorageMock.GetMetadata mock is already set by Expect")
+	}
+
+	if mmGetMetadata.defaultExpectation.paramPtrs == nil {
+		mmGetMetadata.defaultExpectation.paramPtrs = &StorageMockGetMetadataParamPtrs{}
+	}
+	mmGetMetadata.defaultExpectation.paramPtrs.downloader = &downloader
+	mmGetMetadata.defaultExpectation.expectationOrigins.originDownloader = minimock.CallerInfo(1)
+
+	return mmGetMetadata
+}
+
 // Inspect accepts an inspector function that has same arguments as the Storage.GetMetadata
-func (mmGetMetadata *mStorageMockGetMetadata) Inspect(f func(ctx context.Context, url string)) *mStorageMockGetMetadata {
+func (mmGetMetadata *mStorageMockGetMetadata) Inspect(f func(ctx context.Context, url string, downloader string)) *mStorageMockGetMetadata {
 	if mmGetMetadata.mock.inspectFuncGetMetadata != nil {
 		mmGetMetadata.mock.t.Fatalf("Inspect function is already set for StorageMock.GetMetadata")
 	}
@@ -570,7 +616,7 @@ func (mmGetMetadata *mStorageMockGetMetadata) Return(mp1 *mm_service.Metadata, e
 }
 
 // Set uses given function f to mock the Storage.GetMetadata method
-func (mmGetMetadata *mStorageMockGetMetadata) Set(f func(ctx context.Context, url string) (mp1 *mm_service.Metadata, err error)) *StorageMock {
+func (mmGetMetadata *mStorageMockGetMetadata) Set(f func(ctx context.Context, url string, downloader string) (mp1 *mm_service.Metadata, err error)) *StorageMock {
 	if mmGetMetadata.defaultExpectation != nil {
 		mmGetMetadata.mock.t.Fatalf("Default expectation is already set for the Storage.GetMetadata method")
 	}
@@ -586,14 +632,14 @@ func (mmGetMetadata *mStorageMockGetMetadata) Set(f func(ctx context.Context, ur
 
 // When sets expectation for the Storage.GetMetadata which will trigger the result defined by the following
 // Then helper
-func (mmGetMetadata *mStorageMockGetMetadata) When(ctx context.Context, url string) *StorageMockGetMetadataExpectation {
+func (mmGetMetadata *mStorageMockGetMetadata) When(ctx context.Context, url string, downloader string) *StorageMockGetMetadataExpectation {
 	if mmGetMetadata.mock.funcGetMetadata != nil {
 		mmGetMetadata.mock.t.Fatalf("StorageMock.GetMetadata mock is already set by Set")
 	}
 
 	expectation := &StorageMockGetMetadataExpectation{
 		mock:               mmGetMetadata.mock,
-		params:             &StorageMockGetMetadataParams{ctx, url},
+		params:             &StorageMockGetMetadataParams{ctx, url, downloader},
 		expectationOrigins: StorageMockGetMetadataExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
 	mmGetMetadata.expectations = append(mmGetMetadata.expectations, expectation)
@@ -628,17 +674,17 @@ func (mmGetMetadata *mStorageMockGetMetadata) invocationsDone() bool {
 }
 
 // GetMetadata implements mm_service.Storage
-func (mmGetMetadata *StorageMock) GetMetadata(ctx context.Context, url string) (mp1 *mm_service.Metadata, err error) {
+func (mmGetMetadata *StorageMock) GetMetadata(ctx context.Context, url string, downloader string) (mp1 *mm_service.Metadata, err error) {
 	mm_atomic.AddUint64(&mmGetMetadata.beforeGetMetadataCounter, 1)
 	defer mm_atomic.AddUint64(&mmGetMetadata.afterGetMetadataCounter, 1)
 
 	mmGetMetadata.t.Helper()
 
 	if mmGetMetadata.inspectFuncGetMetadata != nil {
-		mmGetMetadata.inspectFuncGetMetadata(ctx, url)
+		mmGetMetadata.inspectFuncGetMetadata(ctx, url, downloader)
 	}
 
-	mm_params := StorageMockGetMetadataParams{ctx, url}
+	mm_params := StorageMockGetMetadataParams{ctx, url, downloader}
 
 	// Record call args
 	mmGetMetadata.GetMetadataMock.mutex.Lock()
@@ -657,7 +703,7 @@ func (mmGetMetadata *StorageMock) GetMetadata(ctx context.Context, url string) (
 		mm_want := mmGetMetadata.GetMetadataMock.defaultExpectation.params
 		mm_want_ptrs := mmGetMetadata.GetMetadataMock.defaultExpectation.paramPtrs
 
-		mm_got := StorageMockGetMetadataParams{ctx, url}
+		mm_got := StorageMockGetMetadataParams{ctx, url, downloader}
 
 		if mm_want_ptrs != nil {
 
@@ -671,6 +717,11 @@ func (mmGetMetadata *StorageMock) GetMetadata(ctx context.Context, url string) (
 					mmGetMetadata.GetMetadataMock.defaultExpectation.expectationOrigins.originUrl, *mm_want_ptrs.url, mm_got.url, minimock.Diff(*mm_want_ptrs.url, mm_got.url))
 			}
 
+			if mm_want_ptrs.downloader != nil && !minimock.Equal(*mm_want_ptrs.downloader, mm_got.downloader) {
+				mmGetMetadata.t.Errorf("StorageMock.GetMetadata got unexpected parameter downloader, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetMetadata.GetMetadataMock.defaultExpectation.expectationOrigins.originDownloader, *mm_want_ptrs.downloader, mm_got.downloader, minimock.Diff(*mm_want_ptrs.downloader, mm_got.downloader))
+			}
+
 		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
 			mmGetMetadata.t.Errorf("StorageMock.GetMetadata got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
 				mmGetMetadata.GetMetadataMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
@@ -683,9 +734,9 @@ func (mmGetMetadata *StorageMock) GetMetadata(ctx context.Context, url string) (
 		return (*mm_results).mp1, (*mm_results).err
 	}
 	if mmGetMetadata.funcGetMetadata != nil {
-		return mmGetMetadata.funcGetMetadata(ctx, url)
+		return mmGetMetadata.funcGetMetadata(ctx, url, downloader)
 	}
-	mmGetMetadata.t.Fatalf("Unexpected call to StorageMock.GetMetadata. %v %v", ctx, url)
+	mmGetMetadata.t.Fatalf("Unexpected call to StorageMock.GetMetadata. %v %v %v", ctx, url, downloader)
 	return
 }
 
@@ -757,6 +808,380 @@ func (m *StorageMock) MinimockGetMetadataInspect() {
 	}
 }
 
+type mStorageMockGetWaveform struct {
+	optional           bool
+	mock               *StorageMock
+	defaultExpectation *StorageMockGetWaveformExpectation
+	expectations       []*StorageMockGetWaveformExpectation
+
+	callArgs []*StorageMockGetWaveformParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// StorageMockGetWaveformExpectation specifies expectation struct of the Storage.GetWaveform
+type StorageMockGetWaveformExpectation struct {
+	mock               *StorageMock
+	params             *StorageMockGetWaveformParams
+	paramPtrs          *StorageMockGetWaveformParamPtrs
+	expectationOrigins StorageMockGetWaveformExpectationOrigins
+	results            *StorageMockGetWaveformResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// StorageMockGetWaveformParams contains parameters of the Storage.GetWaveform
+type StorageMockGetWaveformParams struct {
+	ctx   context.Context
+	jobID string
+	part  int
+}
+
+// StorageMockGetWaveformParamPtrs contains pointers to parameters of the Storage.GetWaveform
+type StorageMockGetWaveformParamPtrs struct {
+	ctx   *context.Context
+	jobID *string
+	part  *int
+}
+
+// StorageMockGetWaveformResults contains results of the Storage.GetWaveform
+type StorageMockGetWaveformResults struct {
+	wp1 *mm_service.WaveformPeaks
+	err error
+}
+
+// StorageMockGetWaveformOrigins contains origins of expectations of the Storage.GetWaveform
+type StorageMockGetWaveformExpectationOrigins struct {
+	origin      string
+	originCtx   string
+	originJobID string
+	originPart  string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmGetWaveform *mStorageMockGetWaveform) Optional() *mStorageMockGetWaveform {
+	mmGetWaveform.optional = true
+	return mmGetWaveform
+}
+
+// Expect sets up expected params for Storage.GetWaveform
+func (mmGetWaveform *mStorageMockGetWaveform) Expect(ctx context.Context, jobID string, part int) *mStorageMockGetWaveform {
+	if mmGetWaveform.mock.funcGetWaveform != nil {
+		mmGetWaveform.mock.t.Fatalf("StorageMock.GetWaveform mock is already set by Set")
+	}
+
+	if mmGetWaveform.defaultExpectation == nil {
+		mmGetWaveform.defaultExpectation = &StorageMockGetWaveformExpectation{}
+	}
+
+	if mmGetWaveform.defaultExpectation.paramPtrs != nil {
+		mmGetWaveform.mock.t.Fatalf("StorageMock.GetWaveform mock is already set by ExpectParams functions")
+	}
+
+	mmGetWaveform.defaultExpectation.params = &StorageMockGetWaveformParams{ctx, jobID, part}
+	mmGetWaveform.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmGetWaveform.expectations {
+		if minimock.Equal(e.params, mmGetWaveform.defaultExpectation.params) {
+			mmGetWaveform.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmGetWaveform.defaultExpectation.params)
+		}
+	}
+
+	return mmGetWaveform
+}
+
+// ExpectCtxParam1 sets up expected param ctx for Storage.GetWaveform
+func (mmGetWaveform *mStorageMockGetWaveform) ExpectCtxParam1(ctx context.Context) *mStorageMockGetWaveform {
+	if mmGetWaveform.mock.funcGetWaveform != nil {
+		mmGetWaveform.mock.t.Fatalf("StorageMock.GetWaveform mock is already set by Set")
+	}
+
+	if mmGetWaveform.defaultExpectation == nil {
+		mmGetWaveform.defaultExpectation = &StorageMockGetWaveformExpectation{}
+	}
+
+	if mmGetWaveform.defaultExpectation.params != nil {
+		mmGetWaveform.mock.t.Fatalf("StorageMock.GetWaveform mock is already set by Expect")
+	}
+
+	if mmGetWaveform.defaultExpectation.paramPtrs == nil {
+		mmGetWaveform.defaultExpectation.paramPtrs = &StorageMockGetWaveformParamPtrs{}
+	}
+	mmGetWaveform.defaultExpectation.paramPtrs.ctx = &ctx
+	mmGetWaveform.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmGetWaveform
+}
+
+// ExpectJobIDParam2 sets up expected param jobID for Storage.GetWaveform
+func (mmGetWaveform *mStorageMockGetWaveform) ExpectJobIDParam2(jobID string) *mStorageMockGetWaveform {
+	if mmGetWaveform.mock.funcGetWaveform != nil {
+		mmGetWaveform.mock.t.Fatalf("StorageMock.GetWaveform mock is already set by Set")
+	}
+
+	if mmGetWaveform.defaultExpectation == nil {
+		mmGetWaveform.defaultExpectation = &StorageMockGetWaveformExpectation{}
+	}
+
+	if mmGetWaveform.defaultExpectation.params != nil {
+		mmGetWaveform.mock.t.Fatalf("StorageMock.GetWaveform mock is already set by Expect")
+	}
+
+	if mmGetWaveform.defaultExpectation.paramPtrs == nil {
+		mmGetWaveform.defaultExpectation.paramPtrs = &StorageMockGetWaveformParamPtrs{}
+	}
+	mmGetWaveform.defaultExpectation.paramPtrs.jobID = &jobID
+	mmGetWaveform.defaultExpectation.expectationOrigins.originJobID = minimock.CallerInfo(1)
+
+	return mmGetWaveform
+}
+
+// ExpectPartParam3 sets up expected param part for Storage.GetWaveform
+func (mmGetWaveform *mStorageMockGetWaveform) ExpectPartParam3(part int) *mStorageMockGetWaveform {
+	if mmGetWaveform.mock.funcGetWaveform != nil {
+		mmGetWaveform.mock.t.Fatalf("StorageMock.GetWaveform mock is already set by Set")
+	}
+
+	if mmGetWaveform.defaultExpectation == nil {
+		mmGetWaveform.defaultExpectation = &StorageMockGetWaveformExpectation{}
+	}
+
+	if mmGetWaveform.defaultExpectation.params != nil {
+		mmGetWaveform.mock.t.Fatalf("StorageMock.GetWaveform mock is already set by Expect")
+	}
+
+	if mmGetWaveform.defaultExpectation.paramPtrs == nil {
+		mmGetWaveform.defaultExpectation.paramPtrs = &StorageMockGetWaveformParamPtrs{}
+	}
+	mmGetWaveform.defaultExpectation.paramPtrs.part = &part
+	mmGetWaveform.defaultExpectation.expectationOrigins.originPart = minimock.CallerInfo(1)
+
+	return mmGetWaveform
+}
+
+// Inspect accepts an inspector function that has same arguments as the Storage.GetWaveform
+func (mmGetWaveform *mStorageMockGetWaveform) Inspect(f func(ctx context.Context, jobID string, part int)) *mStorageMockGetWaveform {
+	if mmGetWaveform.mock.inspectFuncGetWaveform != nil {
+		mmGetWaveform.mock.t.Fatalf("Inspect function is already set for StorageMock.GetWaveform")
+	}
+
+	mmGetWaveform.mock.inspectFuncGetWaveform = f
+
+	return mmGetWaveform
+}
+
+// Return sets up results that will be returned by Storage.GetWaveform
+func (mmGetWaveform *mStorageMockGetWaveform) Return(wp1 *mm_service.WaveformPeaks, err error) *StorageMock {
+	if mmGetWaveform.mock.funcGetWaveform != nil {
+		mmGetWaveform.mock.t.Fatalf("StorageMock.GetWaveform mock is already set by Set")
+	}
+
+	if mmGetWaveform.defaultExpectation == nil {
+		mmGetWaveform.defaultExpectation = &StorageMockGetWaveformExpectation{mock: mmGetWaveform.mock}
+	}
+	mmGetWaveform.defaultExpectation.results = &StorageMockGetWaveformResults{wp1, err}
+	mmGetWaveform.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmGetWaveform.mock
+}
+
+// Set uses given function f to mock the Storage.GetWaveform method
+func (mmGetWaveform *mStorageMockGetWaveform) Set(f func(ctx context.Context, jobID string, part int) (wp1 *mm_service.WaveformPeaks, err error)) *StorageMock {
+	if mmGetWaveform.defaultExpectation != nil {
+		mmGetWaveform.mock.t.Fatalf("Default expectation is already set for the Storage.GetWaveform method")
+	}
+
+	if len(mmGetWaveform.expectations) > 0 {
+		mmGetWaveform.mock.t.Fatalf("Some expectations are already set for the Storage.GetWaveform method")
+	}
+
+	mmGetWaveform.mock.funcGetWaveform = f
+	mmGetWaveform.mock.funcGetWaveformOrigin = minimock.CallerInfo(1)
+	return mmGetWaveform.mock
+}
+
+// When sets expectation for the Storage.GetWaveform which will trigger the result defined by the following
+// Then helper
+func (mmGetWaveform *mStorageMockGetWaveform) When(ctx context.Context, jobID string, part int) *StorageMockGetWaveformExpectation {
+	if mmGetWaveform.mock.funcGetWaveform != nil {
+		mmGetWaveform.mock.t.Fatalf("StorageMock.GetWaveform mock is already set by Set")
+	}
+
+	expectation := &StorageMockGetWaveformExpectation{
+		mock:               mmGetWaveform.mock,
+		params:             &StorageMockGetWaveformParams{ctx, jobID, part},
+		expectationOrigins: StorageMockGetWaveformExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmGetWaveform.expectations = append(mmGetWaveform.expectations, expectation)
+	return expectation
+}
+
+// Then sets up Storage.GetWaveform return parameters for the expectation previously defined by the When method
+func (e *StorageMockGetWaveformExpectation) Then(wp1 *mm_service.WaveformPeaks, err error) *StorageMock {
+	e.results = &StorageMockGetWaveformResults{wp1, err}
+	return e.mock
+}
+
+// Times sets number of times Storage.GetWaveform should be invoked
+func (mmGetWaveform *mStorageMockGetWaveform) Times(n uint64) *mStorageMockGetWaveform {
+	if n == 0 {
+		mmGetWaveform.mock.t.Fatalf("Times of StorageMock.GetWaveform mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmGetWaveform.expectedInvocations, n)
+	mmGetWaveform.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmGetWaveform
+}
+
+func (mmGetWaveform *mStorageMockGetWaveform) invocationsDone() bool {
+	if len(mmGetWaveform.expectations) == 0 && mmGetWaveform.defaultExpectation == nil && mmGetWaveform.mock.funcGetWaveform == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmGetWaveform.mock.afterGetWaveformCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmGetWaveform.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// GetWaveform implements mm_service.Storage
+func (mmGetWaveform *StorageMock) GetWaveform(ctx context.Context, jobID string, part int) (wp1 *mm_service.WaveformPeaks, err error) {
+	mm_atomic.AddUint64(&mmGetWaveform.beforeGetWaveformCounter, 1)
+	defer mm_atomic.AddUint64(&mmGetWaveform.afterGetWaveformCounter, 1)
+
+	mmGetWaveform.t.Helper()
+
+	if mmGetWaveform.inspectFuncGetWaveform != nil {
+		mmGetWaveform.inspectFuncGetWaveform(ctx, jobID, part)
+	}
+
+	mm_params := StorageMockGetWaveformParams{ctx, jobID, part}
+
+	// Record call args
+	mmGetWaveform.GetWaveformMock.mutex.Lock()
+	mmGetWaveform.GetWaveformMock.callArgs = append(mmGetWaveform.GetWaveformMock.callArgs, &mm_params)
+	mmGetWaveform.GetWaveformMock.mutex.Unlock()
+
+	for _, e := range mmGetWaveform.GetWaveformMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.wp1, e.results.err
+		}
+	}
+
+	if mmGetWaveform.GetWaveformMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmGetWaveform.GetWaveformMock.defaultExpectation.Counter, 1)
+		mm_want := mmGetWaveform.GetWaveformMock.defaultExpectation.params
+		mm_want_ptrs := mmGetWaveform.GetWaveformMock.defaultExpectation.paramPtrs
+
+		mm_got := StorageMockGetWaveformParams{ctx, jobID, part}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmGetWaveform.t.Errorf("StorageMock.GetWaveform got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetWaveform.GetWaveformMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.jobID != nil && !minimock.Equal(*mm_want_ptrs.jobID, mm_got.jobID) {
+				mmGetWaveform.t.Errorf("StorageMock.GetWaveform got unexpected parameter jobID, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetWaveform.GetWaveformMock.defaultExpectation.expectationOrigins.originJobID, *mm_want_ptrs.jobID, mm_got.jobID, minimock.Diff(*mm_want_ptrs.jobID, mm_got.jobID))
+			}
+
+			if mm_want_ptrs.part != nil && !minimock.Equal(*mm_want_ptrs.part, mm_got.part) {
+				mmGetWaveform.t.Errorf("StorageMock.GetWaveform got unexpected parameter part, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetWaveform.GetWaveformMock.defaultExpectation.expectationOrigins.originPart, *mm_want_ptrs.part, mm_got.part, minimock.Diff(*mm_want_ptrs.part, mm_got.part))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmGetWaveform.t.Errorf("StorageMock.GetWaveform got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmGetWaveform.GetWaveformMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmGetWaveform.GetWaveformMock.defaultExpectation.results
+		if mm_results == nil {
+			mmGetWaveform.t.Fatal("No results are set for the StorageMock.GetWaveform")
+		}
+		return (*mm_results).wp1, (*mm_results).err
+	}
+	if mmGetWaveform.funcGetWaveform != nil {
+		return mmGetWaveform.funcGetWaveform(ctx, jobID, part)
+	}
+	mmGetWaveform.t.Fatalf("Unexpected call to StorageMock.GetWaveform. %v %v %v", ctx, jobID, part)
+	return
+}
+
+// GetWaveformAfterCounter returns a count of finished StorageMock.GetWaveform invocations
+func (mmGetWaveform *StorageMock) GetWaveformAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmGetWaveform.afterGetWaveformCounter)
+}
+
+// GetWaveformBeforeCounter returns a count of StorageMock.GetWaveform invocations
+func (mmGetWaveform *StorageMock) GetWaveformBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmGetWaveform.beforeGetWaveformCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorageMock.GetWaveform.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmGetWaveform *mStorageMockGetWaveform) Calls() []*StorageMockGetWaveformParams {
+	mmGetWaveform.mutex.RLock()
+
+	argCopy := make([]*StorageMockGetWaveformParams, len(mmGetWaveform.callArgs))
+	copy(argCopy, mmGetWaveform.callArgs)
+
+	mmGetWaveform.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockGetWaveformDone returns true if the count of the GetWaveform invocations corresponds
+// the number of defined expectations
+func (m *StorageMock) MinimockGetWaveformDone() bool {
+	if m.GetWaveformMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.GetWaveformMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.GetWaveformMock.invocationsDone()
+}
+
+// MinimockGetWaveformInspect logs each unmet expectation
+func (m *StorageMock) MinimockGetWaveformInspect() {
+	for _, e := range m.GetWaveformMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorageMock.GetWaveform at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterGetWaveformCounter := mm_atomic.LoadUint64(&m.afterGetWaveformCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.GetWaveformMock.defaultExpectation != nil && afterGetWaveformCounter < 1 {
+		if m.GetWaveformMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to StorageMock.GetWaveform at\n%s", m.GetWaveformMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to StorageMock.GetWaveform at\n%s with params: %#v", m.GetWaveformMock.defaultExpectation.expectationOrigins.origin, *m.GetWaveformMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcGetWaveform != nil && afterGetWaveformCounter < 1 {
+		m.t.Errorf("Expected call to StorageMock.GetWaveform at\n%s", m.funcGetWaveformOrigin)
+	}
+
+	if !m.GetWaveformMock.invocationsDone() && afterGetWaveformCounter > 0 {
+		m.t.Errorf("Expected %d calls to StorageMock.GetWaveform at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.GetWaveformMock.expectedInvocations), m.GetWaveformMock.expectedInvocationsOrigin, afterGetWaveformCounter)
+	}
+}
+
 type mStorageMockSaveJob struct {
 	optional           bool
 	mock               *StorageMock
@@ -1125,14 +1550,16 @@ type StorageMockSaveMetadataExpectation struct {
 
 // StorageMockSaveMetadataParams contains parameters of the Storage.SaveMetadata
 type StorageMockSaveMetadataParams struct {
-	ctx      context.Context
-	metadata *mm_service.Metadata
+	ctx        context.Context
+	downloader string
+	metadata   *mm_service.Metadata
 }
 
 // StorageMockSaveMetadataParamPtrs contains pointers to parameters of the Storage.SaveMetadata
 type StorageMockSaveMetadataParamPtrs struct {
-	ctx      *context.Context
-	metadata **mm_service.Metadata
+	ctx        *context.Context
+	downloader *string
+	metadata   **mm_service.Metadata
 }
 
 // StorageMockSaveMetadataResults contains results of the Storage.SaveMetadata
@@ -1142,9 +1569,10 @@ type StorageMockSaveMetadataResults struct {
 
 // StorageMockSaveMetadataOrigins contains origins of expectations of the Storage.SaveMetadata
 type StorageMockSaveMetadataExpectationOrigins struct {
-	origin         string
-	originCtx      string
-	originMetadata string
+	origin           string
+	originCtx        string
+	originDownloader string
+	originMetadata   string
 }
 
 // Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
@@ -1158,7 +1586,7 @@ func (mmSaveMetadata *mStorageMockSaveMetadata) Optional() *mStorageMockSaveMeta
 }
 
 // Expect sets up expected params for Storage.SaveMetadata
-func (mmSaveMetadata *mStorageMockSaveMetadata) Expect(ctx context.Context, metadata *mm_service.Metadata) *mStorageMockSaveMetadata {
+func (mmSaveMetadata *mStorageMockSaveMetadata) Expect(ctx context.Context, downloader string, metadata *mm_service.Metadata) *mStorageMockSaveMetadata {
 	if mmSaveMetadata.mock.funcSaveMetadata != nil {
 		mmSaveMetadata.mock.t.Fatalf("StorageMock.SaveMetadata mock is already set by Set")
 	}
@@ -1171,7 +1599,7 @@ func (mmSaveMetadata *mStorageMockSaveMetadata) Expect(ctx context.Context, meta
 		mmSaveMetadata.mock.t.Fatalf("StorageMock.SaveMetadata mock is already set by ExpectParams functions")
 	}
 
-	mmSaveMetadata.defaultExpectation.params = &StorageMockSaveMetadataParams{ctx, metadata}
+	mmSaveMetadata.defaultExpectation.params = &StorageMockSaveMetadataParams{ctx, downloader, metadata}
 	mmSaveMetadata.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
 	for _, e := range mmSaveMetadata.expectations {
 		if minimock.Equal(e.params, mmSaveMetadata.defaultExpectation.params) {
@@ -1205,8 +1633,31 @@ func (mmSaveMetadata *mStorageMockSaveMetadata) ExpectCtxParam1(ctx context.Cont
 	return mmSaveMetadata
 }
 
-// ExpectMetadataParam2 sets up expected param metadata for Storage.SaveMetadata
-func (mmSaveMetadata *mStorageMockSaveMetadata) ExpectMetadataParam2(metadata *mm_service.Metadata) *mStorageMockSaveMetadata {
+// ExpectDownloaderParam2 sets up expected param downloader for Storage.SaveMetadata
+func (mmSaveMetadata *mStorageMockSaveMetadata) ExpectDownloaderParam2(downloader string) *mStorageMockSaveMetadata {
+	if mmSaveMetadata.mock.funcSaveMetadata != nil {
+		mmSaveMetadata.mock.t.Fatalf("StorageMock.SaveMetadata mock is already set by Set")
+	}
+
+	if mmSaveMetadata.defaultExpectation == nil {
+		mmSaveMetadata.defaultExpectation = &StorageMockSaveMetadataExpectation{}
+	}
+
+	if mmSaveMetadata.defaultExpectation.params != nil {
+		mmSaveMetadata.mock.t.Fatalf("StorageMock.SaveMetadata mock is already set by Expect")
+	}
+
+	if mmSaveMetadata.defaultExpectation.paramPtrs == nil {
+		mmSaveMetadata.defaultExpectation.paramPtrs = &StorageMockSaveMetadataParamPtrs{}
+	}
+	mmSaveMetadata.defaultExpectation.paramPtrs.downloader = &downloader
+	mmSaveMetadata.defaultExpectation.expectationOrigins.originDownloader = minimock.CallerInfo(1)
+
+	return mmSaveMetadata
+}
+
+// ExpectMetadataParam3 sets up expected param metadata for Storage.SaveMetadata
+func (mmSaveMetadata *mStorageMockSaveMetadata) ExpectMetadataParam3(metadata *mm_service.Metadata) *mStorageMockSaveMetadata {
 	if mmSaveMetadata.mock.funcSaveMetadata != nil {
 		mmSaveMetadata.mock.t.Fatalf("StorageMock.SaveMetadata mock is already set by Set")
 	}
@@ -1229,7 +1680,7 @@ func (mmSaveMetadata *mStorageMockSaveMetadata) ExpectMetadataParam2(metadata *m
 }
 
 // Inspect accepts an inspector function that has same arguments as the Storage.SaveMetadata
-func (mmSaveMetadata *mStorageMockSaveMetadata) Inspect(f func(ctx context.Context, metadata *mm_service.Metadata)) *mStorageMockSaveMetadata {
+func (mmSaveMetadata *mStorageMockSaveMetadata) Inspect(f func(ctx context.Context, downloader string, metadata *mm_service.Metadata)) *mStorageMockSaveMetadata {
 	if mmSaveMetadata.mock.inspectFuncSaveMetadata != nil {
 		mmSaveMetadata.mock.t.Fatalf("Inspect function is already set for StorageMock.SaveMetadata")
 	}
@@ -1254,7 +1705,7 @@ func (mmSaveMetadata *mStorageMockSaveMetadata) Return(err error) *StorageMock {
 }
 
 // Set uses given function f to mock the Storage.SaveMetadata method
-func (mmSaveMetadata *mStorageMockSaveMetadata) Set(f func(ctx context.Context, metadata *mm_service.Metadata) (err error)) *StorageMock {
+func (mmSaveMetadata *mStorageMockSaveMetadata) Set(f func(ctx context.Context, downloader string, metadata *mm_service.Metadata) (err error)) *StorageMock {
 	if mmSaveMetadata.defaultExpectation != nil {
 		mmSaveMetadata.mock.t.Fatalf("Default expectation is already set for the Storage.SaveMetadata method")
 	}
@@ -1270,14 +1721,14 @@ func (mmSaveMetadata *mStorageMockSaveMetadata) Set(f func(ctx context.Context,
 
 // When sets expectation for the Storage.SaveMetadata which will trigger the result defined by the following
 // Then helper
-func (mmSaveMetadata *mStorageMockSaveMetadata) When(ctx context.Context, metadata *mm_service.Metadata) *StorageMockSaveMetadataExpectation {
+func (mmSaveMetadata *mStorageMockSaveMetadata) When(ctx context.Context, downloader string, metadata *mm_service.Metadata) *StorageMockSaveMetadataExpectation {
 	if mmSaveMetadata.mock.funcSaveMetadata != nil {
 		mmSaveMetadata.mock.t.Fatalf("StorageMock.SaveMetadata mock is already set by Set")
 	}
 
 	expectation := &StorageMockSaveMetadataExpectation{
 		mock:               mmSaveMetadata.mock,
-		params:             &StorageMockSaveMetadataParams{ctx, metadata},
+		params:             &StorageMockSaveMetadataParams{ctx, downloader, metadata},
 		expectationOrigins: StorageMockSaveMetadataExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
 	mmSaveMetadata.expectations = append(mmSaveMetadata.expectations, expectation)
@@ -1312,17 +1763,17 @@ func (mmSaveMetadata *mStorageMockSaveMetadata) invocationsDone() bool {
 }
 
 // SaveMetadata implements mm_service.Storage
-func (mmSaveMetadata *StorageMock) SaveMetadata(ctx context.Context, metadata *mm_service.Metadata) (err error) {
+func (mmSaveMetadata *StorageMock) SaveMetadata(ctx context.Context, downloader string, metadata *mm_service.Metadata) (err error) {
 	mm_atomic.AddUint64(&mmSaveMetadata.beforeSaveMetadataCounter, 1)
 	defer mm_atomic.AddUint64(&mmSaveMetadata.afterSaveMetadataCounter, 1)
 
 	mmSaveMetadata.t.Helper()
 
 	if mmSaveMetadata.inspectFuncSaveMetadata != nil {
-		mmSaveMetadata.inspectFuncSaveMetadata(ctx, metadata)
+		mmSaveMetadata.inspectFuncSaveMetadata(ctx, downloader, metadata)
 	}
 
-	mm_params := StorageMockSaveMetadataParams{ctx, metadata}
+	mm_params := StorageMockSaveMetadataParams{ctx, downloader, metadata}
 
 	// Record call args
 	mmSaveMetadata.SaveMetadataMock.mutex.Lock()
@@ -1341,7 +1792,7 @@ func (mmSaveMetadata *StorageMock) SaveMetadata(ctx context.Context, metadata *m
 		mm_want := mmSaveMetadata.SaveMetadataMock.defaultExpectation.params
 		mm_want_ptrs := mmSaveMetadata.SaveMetadataMock.defaultExpectation.paramPtrs
 
-		mm_got := StorageMockSaveMetadataParams{ctx, metadata}
+		mm_got := StorageMockSaveMetadataParams{ctx, downloader, metadata}
 
 		if mm_want_ptrs != nil {
 
@@ -1350,6 +1801,11 @@ func (mmSaveMetadata *StorageMock) SaveMetadata(ctx context.Context, metadata *m
 					mmSaveMetadata.SaveMetadataMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
 			}
 
+			if mm_want_ptrs.downloader != nil && !minimock.Equal(*mm_want_ptrs.downloader, mm_got.downloader) {
+				mmSaveMetadata.t.Errorf("StorageMock.SaveMetadata got unexpected parameter downloader, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmSaveMetadata.SaveMetadataMock.defaultExpectation.expectationOrigins.originDownloader, *mm_want_ptrs.downloader, mm_got.downloader, minimock.Diff(*mm_want_ptrs.downloader, mm_got.downloader))
+			}
+
 			if mm_want_ptrs.metadata != nil && !minimock.Equal(*mm_want_ptrs.metadata, mm_got.metadata) {
 				mmSaveMetadata.t.Errorf("StorageMock.SaveMetadata got unexpected parameter metadata, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
 					mmSaveMetadata.SaveMetadataMock.defaultExpectation.expectationOrigins.originMetadata, *mm_want_ptrs.metadata, mm_got.metadata, minimock.Diff(*mm_want_ptrs.metadata, mm_got.metadata))
@@ -1367,9 +1823,9 @@ func (mmSaveMetadata *StorageMock) SaveMetadata(ctx context.Context, metadata *m
 		return (*mm_results).err
 	}
 	if mmSaveMetadata.funcSaveMetadata != nil {
-		return mmSaveMetadata.funcSaveMetadata(ctx, metadata)
+		return mmSaveMetadata.funcSaveMetadata(ctx, downloader, metadata)
 	}
-	mmSaveMetadata.t.Fatalf("Unexpected call to StorageMock.SaveMetadata. %v %v", ctx, metadata)
+	mmSaveMetadata.t.Fatalf("Unexpected call to StorageMock.SaveMetadata. %v %v %v", ctx, downloader, metadata)
 	return
 }
 
@@ -1441,6 +1897,410 @@ func (m *StorageMock) MinimockSaveMetadataInspect() {
 	}
 }
 
+type mStorageMockSaveWaveform struct {
+	optional           bool
+	mock               *StorageMock
+	defaultExpectation *StorageMockSaveWaveformExpectation
+	expectations       []*StorageMockSaveWaveformExpectation
+
+	callArgs []*StorageMockSaveWaveformParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// StorageMockSaveWaveformExpectation specifies expectation struct of the Storage.SaveWaveform
+type StorageMockSaveWaveformExpectation struct {
+	mock               *StorageMock
+	params             *StorageMockSaveWaveformParams
+	paramPtrs          *StorageMockSaveWaveformParamPtrs
+	expectationOrigins StorageMockSaveWaveformExpectationOrigins
+	results            *StorageMockSaveWaveformResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// StorageMockSaveWaveformParams contains parameters of the Storage.SaveWaveform
+type StorageMockSaveWaveformParams struct {
+	ctx   context.Context
+	jobID string
+	part  int
+	peaks *mm_service.WaveformPeaks
+}
+
+// StorageMockSaveWaveformParamPtrs contains pointers to parameters of the Storage.SaveWaveform
+type StorageMockSaveWaveformParamPtrs struct {
+	ctx   *context.Context
+	jobID *string
+	part  *int
+	peaks **mm_service.WaveformPeaks
+}
+
+// StorageMockSaveWaveformResults contains results of the Storage.SaveWaveform
+type StorageMockSaveWaveformResults struct {
+	err error
+}
+
+// StorageMockSaveWaveformOrigins contains origins of expectations of the Storage.SaveWaveform
+type StorageMockSaveWaveformExpectationOrigins struct {
+	origin      string
+	originCtx   string
+	originJobID string
+	originPart  string
+	originPeaks string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmSaveWaveform *mStorageMockSaveWaveform) Optional() *mStorageMockSaveWaveform {
+	mmSaveWaveform.optional = true
+	return mmSaveWaveform
+}
+
+// Expect sets up expected params for Storage.SaveWaveform
+func (mmSaveWaveform *mStorageMockSaveWaveform) Expect(ctx context.Context, jobID string, part int, peaks *mm_service.WaveformPeaks) *mStorageMockSaveWaveform {
+	if mmSaveWaveform.mock.funcSaveWaveform != nil {
+		mmSaveWaveform.mock.t.Fatalf("StorageMock.SaveWaveform mock is already set by Set")
+	}
+
+	if mmSaveWaveform.defaultExpectation == nil {
+		mmSaveWaveform.defaultExpectation = &StorageMockSaveWaveformExpectation{}
+	}
+
+	if mmSaveWaveform.defaultExpectation.paramPtrs != nil {
+		mmSaveWaveform.mock.t.Fatalf("StorageMock.SaveWaveform mock is already set by ExpectParams functions")
+	}
+
+	mmSaveWaveform.defaultExpectation.params = &StorageMockSaveWaveformParams{ctx, jobID, part, peaks}
+	mmSaveWaveform.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmSaveWaveform.expectations {
+		if minimock.Equal(e.params, mmSaveWaveform.defaultExpectation.params) {
+			mmSaveWaveform.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmSaveWaveform.defaultExpectation.params)
+		}
+	}
+
+	return mmSaveWaveform
+}
+
+// ExpectCtxParam1 sets up expected param ctx for Storage.SaveWaveform
+func (mmSaveWaveform *mStorageMockSaveWaveform) ExpectCtxParam1(ctx context.Context) *mStorageMockSaveWaveform {
+	if mmSaveWaveform.mock.funcSaveWaveform != nil {
+		mmSaveWaveform.mock.t.Fatalf("StorageMock.SaveWaveform mock is already set by Set")
+	}
+
+	if mmSaveWaveform.defaultExpectation == nil {
+		mmSaveWaveform.defaultExpectation = &StorageMockSaveWaveformExpectation{}
+	}
+
+	if mmSaveWaveform.defaultExpectation.params != nil {
+		mmSaveWaveform.mock.t.Fatalf("StorageMock.SaveWaveform mock is already set by Expect")
+	}
+
+	if mmSaveWaveform.defaultExpectation.paramPtrs == nil {
+		mmSaveWaveform.defaultExpectation.paramPtrs = &StorageMockSaveWaveformParamPtrs{}
+	}
+	mmSaveWaveform.defaultExpectation.paramPtrs.ctx = &ctx
+	mmSaveWaveform.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmSaveWaveform
+}
+
+// ExpectJobIDParam2 sets up expected param jobID for Storage.SaveWaveform
+func (mmSaveWaveform *mStorageMockSaveWaveform) ExpectJobIDParam2(jobID string) *mStorageMockSaveWaveform {
+	if mmSaveWaveform.mock.funcSaveWaveform != nil {
+		mmSaveWaveform.mock.t.Fatalf("StorageMock.SaveWaveform mock is already set by Set")
+	}
+
+	if mmSaveWaveform.defaultExpectation == nil {
+		mmSaveWaveform.defaultExpectation = &StorageMockSaveWaveformExpectation{}
+	}
+
+	if mmSaveWaveform.defaultExpectation.params != nil {
+		mmSaveWaveform.mock.t.Fatalf("StorageMock.SaveWaveform mock is already set by Expect")
+	}
+
+	if mmSaveWaveform.defaultExpectation.paramPtrs == nil {
+		mmSaveWaveform.defaultExpectation.paramPtrs = &StorageMockSaveWaveformParamPtrs{}
+	}
+	mmSaveWaveform.defaultExpectation.paramPtrs.jobID = &jobID
+	mmSaveWaveform.defaultExpectation.expectationOrigins.originJobID = minimock.CallerInfo(1)
+
+	return mmSaveWaveform
+}
+
+// ExpectPartParam3 sets up expected param part for Storage.SaveWaveform
+func (mmSaveWaveform *mStorageMockSaveWaveform) ExpectPartParam3(part int) *mStorageMockSaveWaveform {
+	if mmSaveWaveform.mock.funcSaveWaveform != nil {
+		mmSaveWaveform.mock.t.Fatalf("StorageMock.SaveWaveform mock is already set by Set")
+	}
+
+	if mmSaveWaveform.defaultExpectation == nil {
+		mmSaveWaveform.defaultExpectation = &StorageMockSaveWaveformExpectation{}
+	}
+
+	if mmSaveWaveform.defaultExpectation.params != nil {
+		mmSaveWaveform.mock.t.Fatalf("StorageMock.SaveWaveform mock is already set by Expect")
+	}
+
+	if mmSaveWaveform.defaultExpectation.paramPtrs == nil {
+		mmSaveWaveform.defaultExpectation.paramPtrs = &StorageMockSaveWaveformParamPtrs{}
+	}
+	mmSaveWaveform.defaultExpectation.paramPtrs.part = &part
+	mmSaveWaveform.defaultExpectation.expectationOrigins.originPart = minimock.CallerInfo(1)
+
+	return mmSaveWaveform
+}
+
+// ExpectPeaksParam4 sets up expected param peaks for Storage.SaveWaveform
+func (mmSaveWaveform *mStorageMockSaveWaveform) ExpectPeaksParam4(peaks *mm_service.WaveformPeaks) *mStorageMockSaveWaveform {
+	if mmSaveWaveform.mock.funcSaveWaveform != nil {
+		mmSaveWaveform.mock.t.Fatalf("StorageMock.SaveWaveform mock is already set by Set")
+	}
+
+	if mmSaveWaveform.defaultExpectation == nil {
+		mmSaveWaveform.defaultExpectation = &StorageMockSaveWaveformExpectation{}
+	}
+
+	if mmSaveWaveform.defaultExpectation.params != nil {
+		mmSaveWaveform.mock.t.Fatalf("StorageMock.SaveWaveform mock is already set by Expect")
+	}
+
+	if mmSaveWaveform.defaultExpectation.paramPtrs == nil {
+		mmSaveWaveform.defaultExpectation.paramPtrs = &StorageMockSaveWaveformParamPtrs{}
+	}
+	mmSaveWaveform.defaultExpectation.paramPtrs.peaks = &peaks
+	mmSaveWaveform.defaultExpectation.expectationOrigins.originPeaks = minimock.CallerInfo(1)
+
+	return mmSaveWaveform
+}
+
+// Inspect accepts an inspector function that has same arguments as the Storage.SaveWaveform
+func (mmSaveWaveform *mStorageMockSaveWaveform) Inspect(f func(ctx context.Context, jobID string, part int, peaks *mm_service.WaveformPeaks)) *mStorageMockSaveWaveform {
+	if mmSaveWaveform.mock.inspectFuncSaveWaveform != nil {
+		mmSaveWaveform.mock.t.Fatalf("Inspect function is already set for StorageMock.SaveWaveform")
+	}
+
+	mmSaveWaveform.mock.inspectFuncSaveWaveform = f
+
+	return mmSaveWaveform
+}
+
+// Return sets up results that will be returned by Storage.SaveWaveform
+func (mmSaveWaveform *mStorageMockSaveWaveform) Return(err error) *StorageMock {
+	if mmSaveWaveform.mock.funcSaveWaveform != nil {
+		mmSaveWaveform.mock.t.Fatalf("StorageMock.SaveWaveform mock is already set by Set")
+	}
+
+	if mmSaveWaveform.defaultExpectation == nil {
+		mmSaveWaveform.defaultExpectation = &StorageMockSaveWaveformExpectation{mock: mmSaveWaveform.mock}
+	}
+	mmSaveWaveform.defaultExpectation.results = &StorageMockSaveWaveformResults{err}
+	mmSaveWaveform.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmSaveWaveform.mock
+}
+
+// Set uses given function f to mock the Storage.SaveWaveform method
+func (mmSaveWaveform *mStorageMockSaveWaveform) Set(f func(ctx context.Context, jobID string, part int, peaks *mm_service.WaveformPeaks) (err error)) *StorageMock {
+	if mmSaveWaveform.defaultExpectation != nil {
+		mmSaveWaveform.mock.t.Fatalf("Default expectation is already set for the Storage.SaveWaveform method")
+	}
+
+	if len(mmSaveWaveform.expectations) > 0 {
+		mmSaveWaveform.mock.t.Fatalf("Some expectations are already set for the Storage.SaveWaveform method")
+	}
+
+	mmSaveWaveform.mock.funcSaveWaveform = f
+	mmSaveWaveform.mock.funcSaveWaveformOrigin = minimock.CallerInfo(1)
+	return mmSaveWaveform.mock
+}
+
+// When sets expectation for the Storage.SaveWaveform which will trigger the result defined by the following
+// Then helper
+func (mmSaveWaveform *mStorageMockSaveWaveform) When(ctx context.Context, jobID string, part int, peaks *mm_service.WaveformPeaks) *StorageMockSaveWaveformExpectation {
+	if mmSaveWaveform.mock.funcSaveWaveform != nil {
+		mmSaveWaveform.mock.t.Fatalf("StorageMock.SaveWaveform mock is already set by Set")
+	}
+
+	expectation := &StorageMockSaveWaveformExpectation{
+		mock:               mmSaveWaveform.mock,
+		params:             &StorageMockSaveWaveformParams{ctx, jobID, part, peaks},
+		expectationOrigins: StorageMockSaveWaveformExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmSaveWaveform.expectations = append(mmSaveWaveform.expectations, expectation)
+	return expectation
+}
+
+// Then sets up Storage.SaveWaveform return parameters for the expectation previously defined by the When method
+func (e *StorageMockSaveWaveformExpectation) Then(err error) *StorageMock {
+	e.results = &StorageMockSaveWaveformResults{err}
+	return e.mock
+}
+
+// Times sets number of times Storage.SaveWaveform should be invoked
+func (mmSaveWaveform *mStorageMockSaveWaveform) Times(n uint64) *mStorageMockSaveWaveform {
+	if n == 0 {
+		mmSaveWaveform.mock.t.Fatalf("Times of StorageMock.SaveWaveform mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmSaveWaveform.expectedInvocations, n)
+	mmSaveWaveform.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmSaveWaveform
+}
+
+func (mmSaveWaveform *mStorageMockSaveWaveform) invocationsDone() bool {
+	if len(mmSaveWaveform.expectations) == 0 && mmSaveWaveform.defaultExpectation == nil && mmSaveWaveform.mock.funcSaveWaveform == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmSaveWaveform.mock.afterSaveWaveformCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmSaveWaveform.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// SaveWaveform implements mm_service.Storage
+func (mmSaveWaveform *StorageMock) SaveWaveform(ctx context.Context, jobID string, part int, peaks *mm_service.WaveformPeaks) (err error) {
+	mm_atomic.AddUint64(&mmSaveWaveform.beforeSaveWaveformCounter, 1)
+	defer mm_atomic.AddUint64(&mmSaveWaveform.afterSaveWaveformCounter, 1)
+
+	mmSaveWaveform.t.Helper()
+
+	if mmSaveWaveform.inspectFuncSaveWaveform != nil {
+		mmSaveWaveform.inspectFuncSaveWaveform(ctx, jobID, part, peaks)
+	}
+
+	mm_params := StorageMockSaveWaveformParams{ctx, jobID, part, peaks}
+
+	// Record call args
+	mmSaveWaveform.SaveWaveformMock.mutex.Lock()
+	mmSaveWaveform.SaveWaveformMock.callArgs = append(mmSaveWaveform.SaveWaveformMock.callArgs, &mm_params)
+	mmSaveWaveform.SaveWaveformMock.mutex.Unlock()
+
+	for _, e := range mmSaveWaveform.SaveWaveformMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmSaveWaveform.SaveWaveformMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmSaveWaveform.SaveWaveformMock.defaultExpectation.Counter, 1)
+		mm_want := mmSaveWaveform.SaveWaveformMock.defaultExpectation.params
+		mm_want_ptrs := mmSaveWaveform.SaveWaveformMock.defaultExpectation.paramPtrs
+
+		mm_got := StorageMockSaveWaveformParams{ctx, jobID, part, peaks}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmSaveWaveform.t.Errorf("StorageMock.SaveWaveform got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmSaveWaveform.SaveWaveformMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.jobID != nil && !minimock.Equal(*mm_want_ptrs.jobID, mm_got.jobID) {
+				mmSaveWaveform.t.Errorf("StorageMock.SaveWaveform got unexpected parameter jobID, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmSaveWaveform.SaveWaveformMock.defaultExpectation.expectationOrigins.originJobID, *mm_want_ptrs.jobID, mm_got.jobID, minimock.Diff(*mm_want_ptrs.jobID, mm_got.jobID))
+			}
+
+			if mm_want_ptrs.part != nil && !minimock.Equal(*mm_want_ptrs.part, mm_got.part) {
+				mmSaveWaveform.t.Errorf("StorageMock.SaveWaveform got unexpected parameter part, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmSaveWaveform.SaveWaveformMock.defaultExpectation.expectationOrigins.originPart, *mm_want_ptrs.part, mm_got.part, minimock.Diff(*mm_want_ptrs.part, mm_got.part))
+			}
+
+			if mm_want_ptrs.peaks != nil && !minimock.Equal(*mm_want_ptrs.peaks, mm_got.peaks) {
+				mmSaveWaveform.t.Errorf("StorageMock.SaveWaveform got unexpected parameter peaks, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmSaveWaveform.SaveWaveformMock.defaultExpectation.expectationOrigins.originPeaks, *mm_want_ptrs.peaks, mm_got.peaks, minimock.Diff(*mm_want_ptrs.peaks, mm_got.peaks))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmSaveWaveform.t.Errorf("StorageMock.SaveWaveform got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmSaveWaveform.SaveWaveformMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmSaveWaveform.SaveWaveformMock.defaultExpectation.results
+		if mm_results == nil {
+			mmSaveWaveform.t.Fatal("No results are set for the StorageMock.SaveWaveform")
+		}
+		return (*mm_results).err
+	}
+	if mmSaveWaveform.funcSaveWaveform != nil {
+		return mmSaveWaveform.funcSaveWaveform(ctx, jobID, part, peaks)
+	}
+	mmSaveWaveform.t.Fatalf("Unexpected call to StorageMock.SaveWaveform. %v %v %v %v", ctx, jobID, part, peaks)
+	return
+}
+
+// SaveWaveformAfterCounter returns a count of finished StorageMock.SaveWaveform invocations
+func (mmSaveWaveform *StorageMock) SaveWaveformAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmSaveWaveform.afterSaveWaveformCounter)
+}
+
+// SaveWaveformBeforeCounter returns a count of StorageMock.SaveWaveform invocations
+func (mmSaveWaveform *StorageMock) SaveWaveformBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmSaveWaveform.beforeSaveWaveformCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorageMock.SaveWaveform.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmSaveWaveform *mStorageMockSaveWaveform) Calls() []*StorageMockSaveWaveformParams {
+	mmSaveWaveform.mutex.RLock()
+
+	argCopy := make([]*StorageMockSaveWaveformParams, len(mmSaveWaveform.callArgs))
+	copy(argCopy, mmSaveWaveform.callArgs)
+
+	mmSaveWaveform.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockSaveWaveformDone returns true if the count of the SaveWaveform invocations corresponds
+// the number of defined expectations
+func (m *StorageMock) MinimockSaveWaveformDone() bool {
+	if m.SaveWaveformMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.SaveWaveformMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.SaveWaveformMock.invocationsDone()
+}
+
+// MinimockSaveWaveformInspect logs each unmet expectation
+func (m *StorageMock) MinimockSaveWaveformInspect() {
+	for _, e := range m.SaveWaveformMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorageMock.SaveWaveform at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterSaveWaveformCounter := mm_atomic.LoadUint64(&m.afterSaveWaveformCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.SaveWaveformMock.defaultExpectation != nil && afterSaveWaveformCounter < 1 {
+		if m.SaveWaveformMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to StorageMock.SaveWaveform at\n%s", m.SaveWaveformMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to StorageMock.SaveWaveform at\n%s with params: %#v", m.SaveWaveformMock.defaultExpectation.expectationOrigins.origin, *m.SaveWaveformMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcSaveWaveform != nil && afterSaveWaveformCounter < 1 {
+		m.t.Errorf("Expected call to StorageMock.SaveWaveform at\n%s", m.funcSaveWaveformOrigin)
+	}
+
+	if !m.SaveWaveformMock.invocationsDone() && afterSaveWaveformCounter > 0 {
+		m.t.Errorf("Expected %d calls to StorageMock.SaveWaveform at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.SaveWaveformMock.expectedInvocations), m.SaveWaveformMock.expectedInvocationsOrigin, afterSaveWaveformCounter)
+	}
+}
+
 // MinimockFinish checks that all mocked methods have been called the expected number of times
 func (m *StorageMock) MinimockFinish() {
 	m.finishOnce.Do(func() {
@@ -1449,9 +2309,13 @@ func (m *StorageMock) MinimockFinish() {
 
 			m.MinimockGetMetadataInspect()
 
+			m.MinimockGetWaveformInspect()
+
 			m.MinimockSaveJobInspect()
 
 			m.MinimockSaveMetadataInspect()
+
+			m.MinimockSaveWaveformInspect()
 		}
 	})
 }
@@ -1477,6 +2341,8 @@ func (m *StorageMock) minimockDone() bool {
 	return done &&
 		m.MinimockGetJobDone() &&
 		m.MinimockGetMetadataDone() &&
+		m.MinimockGetWaveformDone() &&
 		m.MinimockSaveJobDone() &&
-		m.MinimockSaveMetadataDone()
+		m.MinimockSaveMetadataDone() &&
+		m.MinimockSaveWaveformDone()
 }
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Normalization modes
+const (
+	// NormalizeOutput normalizes the result as a whole
+	NormalizeOutput = "output"
+	// NormalizeInputs normalizes every input before concatenation, so that chapters don't jump in volume
+	NormalizeInputs = "inputs"
+)
+
+// DefaultNormalizeOptions suit podcasts and audiobooks; EBU R128 broadcast target is -23 LUFS
+var DefaultNormalizeOptions = NormalizeOptions{IntegratedLUFS: -16, TruePeak: -1.5, LRA: 11}
+
+// NormalizeParams is the "normalize" job param, omitted targets take their defaults
+type NormalizeParams struct {
+	Mode           string   `json:"mode"`
+	IntegratedLUFS *float64 `json:"targetLufs"`
+	TruePeak       *float64 `json:"truePeak"`
+	LRA            *float64 `json:"lra"`
+}
+
+// Options validates targets against the ranges loudnorm accepts
+func (p *NormalizeParams) Options() (NormalizeOptions, error) {
+	switch p.Mode {
+	case "", NormalizeOutput, NormalizeInputs:
+	default:
+		return NormalizeOptions{}, fmt.Errorf("unknown normalization mode: %s", p.Mode)
+	}
+	opts := DefaultNormalizeOptions
+	for _, target := range []struct {
+		name     string
+		value    *float64
+		dest     *float64
+		min, max float64
+	}{
+		{"targetLufs", p.IntegratedLUFS, &opts.IntegratedLUFS, -70, -5},
+		{"truePeak", p.TruePeak, &opts.TruePeak, -9, 0},
+		{"lra", p.LRA, &opts.LRA, 1, 50},
+	} {
+		if target.value == nil {
+			continue
+		}
+		if *target.value < target.min || *target.value > target.max {
+			return NormalizeOptions{}, fmt.Errorf("%s should be within [%g, %g]", target.name, target.min, target.max)
+		}
+		*target.dest = *target.value
+	}
+	return opts, nil
+}
+
+// normalize normalizes loudness of the file, and returns the normalized copy along with measurements
+func (svc *Service) normalize(ctx context.Context, fp string, variant string, opts NormalizeOptions) (string, *LoudnessStats, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/service").Start(ctx, "service.Normalize",
+		trace.WithAttributes(attribute.String("variant", variant)),
+	)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Hour)
+	defer cancel()
+
+	resultFilepath, stats, err := svc.mediaProcessor.Normalize(ctx, fp, opts)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", nil, oops.With("filepath", fp, "variant", variant).Wrapf(err, "failed to normalize loudness")
+	}
+	stats.Variant = variant
+	return resultFilepath, stats, nil
+}
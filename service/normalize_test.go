@@ -0,0 +1,42 @@
+package service_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dir01/mediary/service"
+)
+
+func TestNormalizeParams_Options(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		json    string
+		want    service.NormalizeOptions
+		wantErr bool
+	}{
+		{name: "defaults", json: `{}`, want: service.DefaultNormalizeOptions},
+		{
+			name: "broadcast",
+			json: `{"mode": "inputs", "targetLufs": -23, "truePeak": 0, "lra": 7}`,
+			want: service.NormalizeOptions{IntegratedLUFS: -23, TruePeak: 0, LRA: 7},
+		},
+		{name: "unknown mode", json: `{"mode": "chapters"}`, wantErr: true},
+		{name: "too loud", json: `{"targetLufs": 0}`, wantErr: true},
+		{name: "positive true peak", json: `{"truePeak": 1}`, wantErr: true},
+		{name: "no range", json: `{"lra": 0}`, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var params service.NormalizeParams
+			if err := json.Unmarshal([]byte(tc.json), &params); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			got, err := params.Options()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Options() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && got != tc.want {
+				t.Errorf("Options() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
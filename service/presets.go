@@ -0,0 +1,78 @@
+package service
+
+import (
+	"fmt"
+)
+
+var ErrUnknownPreset = fmt.Errorf("unknown preset")
+
+func intPtr(i int) *int { return &i }
+
+// transcodePresets are named combinations of transcoding options, for the most common use cases
+var transcodePresets = map[string]TranscodeOptions{
+	"podcast-mono-64k":    {Container: "mp3", AudioCodec: "libmp3lame", Bitrate: "64k", SampleRate: 44100, Channels: 1},
+	"podcast-stereo-128k": {Container: "mp3", AudioCodec: "libmp3lame", Bitrate: "128k", SampleRate: 44100, Channels: 2},
+	"mp3-vbr-high":        {Container: "mp3", AudioCodec: "libmp3lame", Quality: intPtr(2)},
+	"audiobook-m4a-64k":   {Container: "m4a", AudioCodec: "aac", Bitrate: "64k", SampleRate: 44100, Channels: 1},
+	"speech-opus-24k":     {Container: "opus", AudioCodec: "libopus", Bitrate: "24k", SampleRate: 48000, Channels: 1},
+	"archival-flac":       {Container: "flac", AudioCodec: "flac"},
+	"archival-wav":        {Container: "wav", AudioCodec: "pcm_s16le"},
+}
+
+// ResolveTranscodeOptions applies explicitly set options on top of the preset (if any)
+// and fills the gaps with container defaults
+func ResolveTranscodeOptions(preset string, overrides TranscodeOptions) (TranscodeOptions, error) {
+	var opts TranscodeOptions
+	if preset != "" {
+		var ok bool
+		if opts, ok = transcodePresets[preset]; !ok {
+			return opts, fmt.Errorf("%w: %s", ErrUnknownPreset, preset)
+		}
+	}
+
+	if overrides.Container != "" && overrides.Container != opts.Container {
+		// preset's codec most likely doesn't fit into another container
+		opts.Container = overrides.Container
+		opts.AudioCodec = ""
+	}
+	if overrides.AudioCodec != "" {
+		opts.AudioCodec = overrides.AudioCodec
+	}
+	if overrides.Bitrate != "" {
+		opts.Bitrate = overrides.Bitrate
+		opts.Quality = nil
+	}
+	if overrides.Quality != nil {
+		opts.Quality = overrides.Quality
+		opts.Bitrate = ""
+	}
+	if overrides.SampleRate != 0 {
+		opts.SampleRate = overrides.SampleRate
+	}
+	if overrides.Channels != 0 {
+		opts.Channels = overrides.Channels
+	}
+	if overrides.Speed != 0 {
+		opts.Speed = overrides.Speed
+	}
+	if err := ValidateSpeed(opts.Speed); err != nil {
+		return opts, err
+	}
+
+	if opts.Container == "" {
+		return opts, fmt.Errorf("either preset or container is required")
+	}
+	container, err := LookupContainer(opts.Container)
+	if err != nil {
+		return opts, err
+	}
+	opts.Container = container.Name
+	if opts.AudioCodec == "" {
+		opts.AudioCodec = container.DefaultAudioCodec
+	}
+	if opts.AudioCodec == "copy" && changesSpeed(opts.Speed) {
+		// audio can't be sped up without being encoded again
+		return opts, fmt.Errorf("%w: %g needs audio to be encoded, not copied", ErrInvalidSpeed, opts.Speed)
+	}
+	return opts, nil
+}
@@ -0,0 +1,87 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dir01/mediary/service"
+)
+
+func TestResolveTranscodeOptions(t *testing.T) {
+	quality := 4
+	for _, tc := range []struct {
+		name      string
+		preset    string
+		overrides service.TranscodeOptions
+		want      service.TranscodeOptions
+		wantErr   error
+	}{
+		{
+			name:   "preset as is",
+			preset: "podcast-mono-64k",
+			want:   service.TranscodeOptions{Container: "mp3", AudioCodec: "libmp3lame", Bitrate: "64k", SampleRate: 44100, Channels: 1},
+		},
+		{
+			name:      "overrides win, quality replaces bitrate",
+			preset:    "podcast-mono-64k",
+			overrides: service.TranscodeOptions{Quality: &quality, Channels: 2},
+			want:      service.TranscodeOptions{Container: "mp3", AudioCodec: "libmp3lame", Quality: &quality, SampleRate: 44100, Channels: 2},
+		},
+		{
+			name:      "another container drops preset's codec",
+			preset:    "podcast-mono-64k",
+			overrides: service.TranscodeOptions{Container: "ogg"},
+			want:      service.TranscodeOptions{Container: "ogg", AudioCodec: "libvorbis", Bitrate: "64k", SampleRate: 44100, Channels: 1},
+		},
+		{
+			name:      "no preset, codec defaults to container's",
+			overrides: service.TranscodeOptions{Container: "webm"},
+			want:      service.TranscodeOptions{Container: "webm", AudioCodec: "libopus"},
+		},
+		{
+			name:    "unknown preset",
+			preset:  "nope",
+			wantErr: service.ErrUnknownPreset,
+		},
+		{
+			name:      "speed",
+			preset:    "podcast-mono-64k",
+			overrides: service.TranscodeOptions{Speed: 1.5},
+			want:      service.TranscodeOptions{Container: "mp3", AudioCodec: "libmp3lame", Bitrate: "64k", SampleRate: 44100, Channels: 1, Speed: 1.5},
+		},
+		{
+			name:      "too fast",
+			preset:    "podcast-mono-64k",
+			overrides: service.TranscodeOptions{Speed: 5},
+			wantErr:   service.ErrInvalidSpeed,
+		},
+		{
+			name:      "speed of copied audio",
+			overrides: service.TranscodeOptions{Container: "m4a", AudioCodec: "copy", Speed: 1.5},
+			wantErr:   service.ErrInvalidSpeed,
+		},
+		{
+			name:      "unknown container",
+			overrides: service.TranscodeOptions{Container: "wma"},
+			wantErr:   service.ErrUnknownContainer,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := service.ResolveTranscodeOptions(tc.preset, tc.overrides)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("expected %v, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Container != tc.want.Container || got.AudioCodec != tc.want.AudioCodec || got.Bitrate != tc.want.Bitrate ||
+				got.SampleRate != tc.want.SampleRate || got.Channels != tc.want.Channels || got.Speed != tc.want.Speed ||
+				(got.Quality == nil) != (tc.want.Quality == nil) || (got.Quality != nil && *got.Quality != *tc.want.Quality) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	maxContactSheetFrames = 100
+	maxPreviewSide        = 4096
+	maxPreviewClip        = time.Minute
+)
+
+var previewBitrateRe = regexp.MustCompile(`^[1-9][0-9]*[kM]?$`)
+
+// PreviewParams is the "previews" job param of jobs that may produce videos. Every preview is optional,
+// and is uploaded to its own URL; for jobs with several results, the URLs are templates with a {part} placeholder.
+type PreviewParams struct {
+	ContactSheet *ContactSheetParams `json:"contactSheet"`
+	Poster       *PosterParams       `json:"poster"`
+	Clip         *PreviewClipParams  `json:"clip"`
+}
+
+type ContactSheetParams struct {
+	// Frames is the number of frames, 12 by default, laid out in rows of Columns (4 by default)
+	Frames  int `json:"frames"`
+	Columns int `json:"columns"`
+	// Width of a single frame, 320 by default
+	Width     int    `json:"width"`
+	UploadURL string `json:"uploadUrl"`
+}
+
+type PosterParams struct {
+	// At is the time of the frame, a tenth into the video by default
+	At *Timestamp `json:"at"`
+	// Width of the poster, the video's own size by default
+	Width     int    `json:"width"`
+	UploadURL string `json:"uploadUrl"`
+}
+
+type PreviewClipParams struct {
+	// Start is a tenth into the video by default
+	Start *Timestamp `json:"start"`
+	// Duration is 10 seconds by default, and a minute at most
+	Duration Timestamp `json:"duration"`
+	// Height is 360 by default
+	Height int `json:"height"`
+	// Bitrate of the video, like "400k" (the default)
+	Bitrate   string `json:"bitrate"`
+	UploadURL string `json:"uploadUrl"`
+}
+
+// validate checks preview params of a job, which may have several results when multipart is set.
+// Nil params are valid.
+func (p *PreviewParams) validate(multipart bool) error {
+	if p == nil {
+		return nil
+	}
+	if p.ContactSheet == nil && p.Poster == nil && p.Clip == nil {
+		return fmt.Errorf("no previews requested")
+	}
+	var urls []string
+	if s := p.ContactSheet; s != nil {
+		if s.Frames < 0 || s.Frames > maxContactSheetFrames {
+			return fmt.Errorf("contact sheet frames should be within [1, %d]", maxContactSheetFrames)
+		}
+		if s.Columns < 0 || s.Columns > cmp.Or(s.Frames, 12) {
+			return fmt.Errorf("contact sheet columns should be within [1, frames]")
+		}
+		if s.Width < 0 || s.Width > maxPreviewSide {
+			return fmt.Errorf("contact sheet frame width should be within [1, %d]", maxPreviewSide)
+		}
+		urls = append(urls, s.UploadURL)
+	}
+	if poster := p.Poster; poster != nil {
+		if poster.Width < 0 || poster.Width > maxPreviewSide {
+			return fmt.Errorf("poster width should be within [1, %d]", maxPreviewSide)
+		}
+		urls = append(urls, poster.UploadURL)
+	}
+	if clip := p.Clip; clip != nil {
+		if time.Duration(clip.Duration) > maxPreviewClip {
+			return fmt.Errorf("preview clip should be at most %s long", maxPreviewClip)
+		}
+		if clip.Height < 0 || clip.Height > maxPreviewSide || clip.Height%2 != 0 {
+			return fmt.Errorf("preview clip height should be even and within [2, %d]", maxPreviewSide)
+		}
+		if clip.Bitrate != "" && !previewBitrateRe.MatchString(clip.Bitrate) {
+			return fmt.Errorf("invalid preview clip bitrate: %q", clip.Bitrate)
+		}
+		urls = append(urls, clip.UploadURL)
+	}
+	for _, url := range urls {
+		if url == "" {
+			return fmt.Errorf("every preview needs an upload URL")
+		}
+		if multipart && !strings.Contains(url, partPlaceholder) {
+			return fmt.Errorf("preview upload URLs should contain %s, since there are several results", partPlaceholder)
+		}
+	}
+	return nil
+}
+
+// makePreviews renders and uploads previews of the part-th of total results of the job,
+// which should have a video stream
+func (svc *Service) makePreviews(ctx context.Context, jobID string, fp string, part int, total int, params *PreviewParams) error {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/service").Start(ctx, "service.MakePreviews",
+		trace.WithAttributes(
+			attribute.String("job.id", jobID),
+			attribute.Int("part", part),
+			attribute.Bool("contact_sheet", params.ContactSheet != nil),
+			attribute.Bool("poster", params.Poster != nil),
+			attribute.Bool("clip", params.Clip != nil),
+		),
+	)
+	defer span.End()
+
+	errCtx := oops.With("jobID", jobID, "filepath", fp, "part", part)
+	fail := func(err error) error {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Hour)
+	defer cancel()
+
+	info, err := svc.mediaProcessor.GetInfo(ctx, fp)
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to get info about result file"))
+	}
+	if info.VideoStream() == nil {
+		return fail(errCtx.Errorf("previews need a video, the result has none"))
+	}
+	tenth := info.Duration / 10
+
+	type preview struct {
+		name      string
+		uploadURL string
+		render    func() (string, error)
+	}
+	var previews []preview
+	if s := params.ContactSheet; s != nil {
+		opts := ContactSheetOptions{Frames: cmp.Or(s.Frames, 12), Width: cmp.Or(s.Width, 320)}
+		opts.Columns = cmp.Or(s.Columns, min(4, opts.Frames))
+		previews = append(previews, preview{"contact sheet", s.UploadURL, func() (string, error) {
+			return svc.mediaProcessor.ContactSheet(ctx, fp, opts)
+		}})
+	}
+	if p := params.Poster; p != nil {
+		opts := PosterFrameOptions{At: tenth, Width: p.Width}
+		if p.At != nil {
+			opts.At = time.Duration(*p.At)
+		}
+		previews = append(previews, preview{"poster", p.UploadURL, func() (string, error) {
+			return svc.mediaProcessor.PosterFrame(ctx, fp, opts)
+		}})
+	}
+	if c := params.Clip; c != nil {
+		opts := PreviewClipOptions{
+			Start:    tenth,
+			Duration: cmp.Or(time.Duration(c.Duration), 10*time.Second),
+			Height:   cmp.Or(c.Height, 360),
+			Bitrate:  cmp.Or(c.Bitrate, "400k"),
+		}
+		if c.Start != nil {
+			opts.Start = time.Duration(*c.Start)
+		}
+		previews = append(previews, preview{"preview clip", c.UploadURL, func() (string, error) {
+			return svc.mediaProcessor.PreviewClip(ctx, fp, opts)
+		}})
+	}
+
+	for _, p := range previews {
+		previewFilepath, err := p.render()
+		if err != nil {
+			return fail(errCtx.Wrapf(err, "failed to make %s", p.name))
+		}
+		err = svc.uploader.Upload(ctx, previewFilepath, partUploadURL(nil, p.uploadURL, part, total))
+		_ = os.Remove(previewFilepath)
+		if err != nil {
+			return fail(errCtx.Wrapf(err, "failed to upload %s", p.name))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/service"
+	"github.com/dir01/mediary/service/mocks"
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestTranscodeFlow_Previews(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	mp.ExtractCoverArtMock.Optional().Return("", errors.New("no cover art"))
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-transcode-previews"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  "http://example.com/video",
+			Type: "transcode",
+			Params: map[string]interface{}{
+				"variant":   "talk.mkv",
+				"container": "mp4",
+				"uploadUrl": "http://example.com/upload/talk.mp4",
+				"previews": map[string]interface{}{
+					"contactSheet": map[string]interface{}{"frames": 6, "uploadUrl": "http://example.com/upload/sheet.jpg"},
+					"poster":       map[string]interface{}{"at": "1:30", "width": 640, "uploadUrl": "http://example.com/upload/poster.jpg"},
+					"clip":         map[string]interface{}{"duration": 5, "uploadUrl": "http://example.com/upload/preview.mp4"},
+				},
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+	storage.GetMetadataMock.Optional().Return(nil, nil)
+
+	dwn.DownloadMock.Return(map[string]string{"talk.mkv": "/tmp/dl/talk.mkv"}, nil)
+	mp.TranscodeMock.Return("/tmp/result/talk.mp4", nil)
+	mp.GetInfoMock.Return(&service.MediaInfo{
+		Duration:     10 * time.Minute,
+		FileLenBytes: 1000,
+		Streams:      []service.StreamInfo{{Type: service.StreamVideo, Codec: "h264", Width: 1920, Height: 1080}},
+	}, nil)
+	mp.ContactSheetMock.Set(func(_ context.Context, fp string, opts service.ContactSheetOptions) (string, error) {
+		if want := (service.ContactSheetOptions{Frames: 6, Columns: 4, Width: 320}); opts != want {
+			t.Errorf("contact sheet opts = %+v, want %+v", opts, want)
+		}
+		return "/tmp/sheet.jpg", nil
+	})
+	mp.PosterFrameMock.Set(func(_ context.Context, fp string, opts service.PosterFrameOptions) (string, error) {
+		if want := (service.PosterFrameOptions{At: 90 * time.Second, Width: 640}); opts != want {
+			t.Errorf("poster opts = %+v, want %+v", opts, want)
+		}
+		return "/tmp/poster.jpg", nil
+	})
+	mp.PreviewClipMock.Set(func(_ context.Context, fp string, opts service.PreviewClipOptions) (string, error) {
+		// starts a tenth into the video
+		want := service.PreviewClipOptions{Start: time.Minute, Duration: 5 * time.Second, Height: 360, Bitrate: "400k"}
+		if opts != want {
+			t.Errorf("preview clip opts = %+v, want %+v", opts, want)
+		}
+		return "/tmp/preview.mp4", nil
+	})
+	uploaded := map[string]string{}
+	upl.UploadMock.Set(func(_ context.Context, fp string, url string) error {
+		uploaded[fp] = url
+		return nil
+	})
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+
+	wantUploads := map[string]string{
+		"/tmp/result/talk.mp4": "http://example.com/upload/talk.mp4",
+		"/tmp/sheet.jpg":       "http://example.com/upload/sheet.jpg",
+		"/tmp/poster.jpg":      "http://example.com/upload/poster.jpg",
+		"/tmp/preview.mp4":     "http://example.com/upload/preview.mp4",
+	}
+	if !reflect.DeepEqual(uploaded, wantUploads) {
+		t.Errorf("uploaded %v, want %v", uploaded, wantUploads)
+	}
+}
+
+func TestSplitFlow_InvalidPreviews(t *testing.T) {
+	mc := minimock.NewController(t)
+	queue := mocks.NewJobsQueueMock(mc)
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, _ func(context.Context, []byte) error) {})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(mocks.NewDownloaderMock(mc), mocks.NewStorageMock(mc), queue,
+		mocks.NewMediaProcessorMock(mc), mocks.NewUploaderMock(mc), logger)
+	svc.Start()
+	defer svc.Stop()
+
+	for _, tc := range []struct {
+		name     string
+		previews map[string]interface{}
+	}{
+		{name: "nothing requested", previews: map[string]interface{}{}},
+		{name: "no upload url", previews: map[string]interface{}{"poster": map[string]interface{}{}}},
+		{name: "no part placeholder", previews: map[string]interface{}{
+			"poster": map[string]interface{}{"uploadUrl": "http://example.com/poster.jpg"},
+		}},
+		{name: "odd clip height", previews: map[string]interface{}{
+			"clip": map[string]interface{}{"height": 361, "uploadUrl": "http://example.com/{part}.mp4"},
+		}},
+		{name: "long clip", previews: map[string]interface{}{
+			"clip": map[string]interface{}{"duration": "2:00", "uploadUrl": "http://example.com/{part}.mp4"},
+		}},
+		{name: "bitrate with filter", previews: map[string]interface{}{
+			"clip": map[string]interface{}{"bitrate": "400k -vf drawtext", "uploadUrl": "http://example.com/{part}.mp4"},
+		}},
+		{name: "more columns than frames", previews: map[string]interface{}{
+			"contactSheet": map[string]interface{}{"frames": 4, "columns": 5, "uploadUrl": "http://example.com/{part}.jpg"},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.CreateJob(context.Background(), &service.JobParams{
+				URL:  "magnet:?xt=urn:btih:deadbeef",
+				Type: "split",
+				Params: map[string]interface{}{
+					"variant":           "talk.mkv",
+					"mode":              "chapters",
+					"uploadUrlTemplate": "http://example.com/upload/talk-{part}.mkv",
+					"previews":          tc.previews,
+				},
+			})
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
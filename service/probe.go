@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ProbeRequest struct {
+	URL     string
+	Variant string
+	// Downloader forces a specific downloader, instead of the first one that accepts the URL
+	Downloader string
+}
+
+// Probe tells what is inside a single variant: streams, codecs, tags and chapters.
+// Only parts of the variant are read when the downloader can stream it; otherwise, and for CUE tracks,
+// which are cut out of their rips, the variant is downloaded, for no longer than probeDownloadTimeout.
+func (svc *Service) Probe(ctx context.Context, req ProbeRequest) (*MediaInfo, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/service").Start(ctx, "service.Probe",
+		trace.WithAttributes(
+			attribute.String("url", req.URL),
+			attribute.String("variant", req.Variant),
+			attribute.String("downloader", req.Downloader),
+		),
+	)
+	defer span.End()
+
+	errCtx := oops.With("url", req.URL, "variant", req.Variant, "downloader", req.Downloader)
+	svc.log.Debug("probing variant", slog.String("url", req.URL), slog.String("variant", req.Variant))
+	fail := func(err error) (*MediaInfo, error) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	downloader, err := svc.selectDownloader(req.Downloader)
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to select downloader"))
+	}
+	if !downloader.AcceptsURL(req.URL) {
+		return fail(errCtx.Wrapf(ErrUrlNotSupported, "failed to probe"))
+	}
+
+	var info *MediaInfo
+	tracks := svc.cueTracksOf(ctx, req.URL, req.Downloader, []string{req.Variant})
+	if streamer, ok := downloader.(Streamer); ok && len(tracks) == 0 {
+		span.SetAttributes(attribute.Bool("partial", true))
+		if info, err = svc.probePartially(ctx, streamer, req.URL, req.Variant); err != nil {
+			return fail(errCtx.Wrapf(err, "failed to probe variant"))
+		}
+	} else {
+		// the request waits for the download, so it is not allowed to take forever
+		downloadCtx, cancel := context.WithTimeout(ctx, probeDownloadTimeout)
+		defer cancel()
+		filepathsMap, err := svc.download(downloadCtx, downloader, req.Downloader, req.URL, []string{req.Variant})
+		if err != nil {
+			return fail(errCtx.Wrapf(err, "failed to download variant"))
+		}
+		fp, ok := filepathsMap[req.Variant]
+		if !ok {
+			return fail(errCtx.Wrapf(ErrVariantNotFound, "failed to probe"))
+		}
+		if info, err = svc.mediaProcessor.GetInfo(ctx, fp); err != nil {
+			return fail(errCtx.Wrapf(err, "failed to probe variant"))
+		}
+	}
+	span.SetAttributes(
+		attribute.String("media.container", info.Container),
+		attribute.Int("media.streams", len(info.Streams)),
+	)
+	return info, nil
+}
+
+// Partial probing reads this much of the beginning of a variant, and of its end when that is not enough:
+// MP4 files keep their index (moov atom) at either end, and it gets large for long audiobooks
+const (
+	probeHeadBytes = 4 << 20
+	probeTailBytes = 8 << 20
+)
+
+// Probing variants is bounded, since it holds up the metadata of the URL: a torrent of a thousand tracks
+// gets the first maxProbedVariants of them probed, and the rest by the requests that follow
+const (
+	maxProbedVariants = 100
+	probeConcurrency  = 4
+	probeTimeout      = 5 * time.Minute
+)
+
+// probeDownloadTimeout bounds downloads of variants that can't be probed partially
+const probeDownloadTimeout = 10 * time.Minute
+
+// probeVariants fills in durations, codecs and bitrates of media variants that are not probed yet
+// and saves the metadata, so that they are only probed once. Only parts of variants are read,
+// through the downloader's stream. Variants that fail to probe are left as they are.
+func (svc *Service) probeVariants(ctx context.Context, req MetadataRequest, metadata *Metadata) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/service").Start(ctx, "service.ProbeVariants",
+		trace.WithAttributes(attribute.String("url", req.URL)),
+	)
+	defer span.End()
+
+	logAttrs := []any{slog.String("url", req.URL), slog.String("downloader", req.Downloader)}
+	downloader, err := svc.selectDownloader(req.Downloader)
+	if err != nil {
+		svc.log.Warn("failed to select downloader for probing", append([]any{slog.Any("error", err)}, logAttrs...)...)
+		return
+	}
+	streamer, ok := downloader.(Streamer)
+	if !ok {
+		svc.log.Debug("downloader can't stream, variants are not probed", logAttrs...)
+		return
+	}
+
+	var pending []int
+	for i, v := range metadata.Variants {
+		if _, ok := ContainerByExt(path.Ext(v.ID)); ok && v.Codec == "" {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) > maxProbedVariants {
+		svc.log.Debug("too many variants to probe at once",
+			append([]any{slog.Int("pending", len(pending))}, logAttrs...)...)
+		pending = pending[:maxProbedVariants]
+	}
+
+	// variants probed in time are saved all the same
+	probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	indexes := make(chan int)
+	var probed atomic.Int32
+	var wg sync.WaitGroup
+	for range min(probeConcurrency, len(pending)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				// every worker fills in variants of its own
+				v := &metadata.Variants[i]
+				info, err := svc.probePartially(probeCtx, streamer, req.URL, v.ID)
+				if err != nil {
+					svc.log.Warn("failed to probe variant",
+						append([]any{slog.String("variant", v.ID), slog.Any("error", err)}, logAttrs...)...)
+					continue
+				}
+				v.Duration = info.Duration
+				v.BitRate = info.BitRate
+				if audio := info.AudioStream(); audio != nil {
+					v.Codec = audio.Codec
+					if audio.BitRate != 0 {
+						v.BitRate = audio.BitRate
+					}
+				}
+				probed.Add(1)
+			}
+		}()
+	}
+loop:
+	for _, i := range pending {
+		select {
+		case indexes <- i:
+		case <-probeCtx.Done():
+			break loop
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	span.SetAttributes(
+		attribute.Int("variants.pending", len(pending)),
+		attribute.Int("variants.probed", int(probed.Load())),
+	)
+
+	if probed.Load() == 0 {
+		return
+	}
+	if err := svc.storage.SaveMetadata(ctx, req.Downloader, metadata); err != nil {
+		svc.log.Error("error saving probed metadata to storage", append([]any{slog.Any("error", err)}, logAttrs...)...)
+	}
+}
+
+// probePartially copies the head of the variant, and its tail if the head is not enough, into a sparse file
+// as large as the variant, and probes that. Keeping the full size matters: ffprobe estimates durations
+// of files without an index (like CBR MP3) from their size.
+func (svc *Service) probePartially(ctx context.Context, streamer Streamer, url string, variant string) (*MediaInfo, error) {
+	errCtx := oops.With("url", url, "variant", variant)
+
+	stream, err := streamer.Stream(ctx, url, variant)
+	if err != nil {
+		return nil, errCtx.Wrapf(err, "failed to open stream")
+	}
+	defer func() { _ = stream.Close() }()
+
+	file, err := os.CreateTemp("", "*-probe"+path.Ext(variant))
+	if err != nil {
+		return nil, errCtx.Wrapf(err, "failed to create temp file")
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+	defer func() { _ = file.Close() }()
+
+	if err := file.Truncate(stream.Size); err != nil {
+		return nil, errCtx.Wrapf(err, "failed to allocate sparse file")
+	}
+	head := min(probeHeadBytes, stream.Size)
+	if err := copyRange(file, stream, 0, head); err != nil {
+		return nil, errCtx.Wrapf(err, "failed to read head")
+	}
+	info, err := svc.mediaProcessor.GetInfo(ctx, file.Name())
+	if err == nil && info.Duration > 0 {
+		return info, nil
+	}
+	if head == stream.Size {
+		// the whole variant has been read, the tail won't help
+		if err != nil {
+			return nil, errCtx.Wrapf(err, "failed to probe")
+		}
+		return info, nil
+	}
+
+	if err := copyRange(file, stream, max(head, stream.Size-probeTailBytes), stream.Size); err != nil {
+		return nil, errCtx.Wrapf(err, "failed to read tail")
+	}
+	if info, err = svc.mediaProcessor.GetInfo(ctx, file.Name()); err != nil {
+		return nil, errCtx.Wrapf(err, "failed to probe")
+	}
+	return info, nil
+}
+
+// copyRange copies bytes [from, to) of src into the same place of dst
+func copyRange(dst *os.File, src io.ReadSeeker, from, to int64) error {
+	if _, err := src.Seek(from, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := dst.Seek(from, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := io.CopyN(dst, src, to-from)
+	return err
+}
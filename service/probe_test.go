@@ -0,0 +1,146 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/service"
+	"github.com/dir01/mediary/service/mocks"
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestProbe(t *testing.T) {
+	mc := minimock.NewController(t)
+	dwn := mocks.NewDownloaderMock(mc)
+	dwn.AcceptsURLMock.Return(true)
+	dwn.DownloadMock.Expect(minimock.AnyContext, "magnet:?xt=urn:btih:deadbeef", []string{"book.m4b"}).
+		Return(map[string]string{"book.m4b": "/tmp/dl/book.m4b"}, nil)
+	mp := mocks.NewMediaProcessorMock(mc)
+	want := &service.MediaInfo{
+		Duration:  time.Hour,
+		Container: "mov,mp4,m4a,3gp,3g2,mj2",
+		Streams:   []service.StreamInfo{{Type: service.StreamAudio, Codec: "aac", Channels: 2}},
+	}
+	mp.GetInfoMock.Expect(minimock.AnyContext, "/tmp/dl/book.m4b").Return(want, nil)
+
+	svc := service.NewService(
+		dwn, mocks.NewStorageMock(mc), mocks.NewJobsQueueMock(mc), mp, mocks.NewUploaderMock(mc), logger,
+	)
+
+	got, err := svc.Probe(context.Background(), service.ProbeRequest{URL: "magnet:?xt=urn:btih:deadbeef", Variant: "book.m4b"})
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestProbe_Streams(t *testing.T) {
+	mc := minimock.NewController(t)
+	// Download is not expected: the variant is read through its stream
+	dwn := streamingDownloader{
+		DownloaderMock: mocks.NewDownloaderMock(mc),
+		content:        map[string][]byte{"book.m4b": bytes.Repeat([]byte{'h'}, 1024)},
+	}
+	dwn.AcceptsURLMock.Return(true)
+	mp := mocks.NewMediaProcessorMock(mc)
+	want := &service.MediaInfo{Duration: time.Hour, Streams: []service.StreamInfo{{Type: service.StreamAudio, Codec: "aac"}}}
+	mp.GetInfoMock.Return(want, nil)
+
+	svc := service.NewService(
+		dwn, mocks.NewStorageMock(mc), mocks.NewJobsQueueMock(mc), mp, mocks.NewUploaderMock(mc), logger,
+	)
+
+	got, err := svc.Probe(context.Background(), service.ProbeRequest{URL: "magnet:?xt=urn:btih:deadbeef", Variant: "book.m4b"})
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestProbe_CueTrack(t *testing.T) {
+	url := "magnet:?xt=urn:btih:deadbeef"
+	mc := minimock.NewController(t)
+	// CUE tracks can't be streamed, they are cut out of their rips
+	dwn := streamingDownloader{DownloaderMock: mocks.NewDownloaderMock(mc)}
+	dwn.AcceptsURLMock.Return(true)
+	dwn.DownloadMock.Expect(minimock.AnyContext, url, []string{"rip.flac"}).
+		Return(map[string]string{"rip.flac": "/tmp/dl/rip.flac"}, nil)
+	storage := mocks.NewStorageMock(mc)
+	storage.GetMetadataMock.Return(&service.Metadata{URL: url, Variants: []service.VariantMetadata{
+		{ID: "rip.flac"},
+		{ID: "rip.flac#03", CueTrack: &service.CueTrack{Source: "rip.flac", Number: 3, Start: time.Minute, End: 2 * time.Minute}},
+	}}, nil)
+	mp := mocks.NewMediaProcessorMock(mc)
+	mp.ClipMock.Expect(minimock.AnyContext, "/tmp/dl/rip.flac", service.ClipOptions{
+		Ranges:    []service.TimeRange{{Start: time.Minute, End: 2 * time.Minute}},
+		Container: "flac",
+	}).Return([]string{"/tmp/clip001.flac"}, nil)
+	want := &service.MediaInfo{Duration: time.Minute}
+	mp.GetInfoMock.Expect(minimock.AnyContext, "/tmp/clip001.flac").Return(want, nil)
+
+	svc := service.NewService(dwn, storage, mocks.NewJobsQueueMock(mc), mp, mocks.NewUploaderMock(mc), logger)
+
+	got, err := svc.Probe(context.Background(), service.ProbeRequest{URL: url, Variant: "rip.flac#03"})
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestProbe_UnsupportedURL(t *testing.T) {
+	mc := minimock.NewController(t)
+	dwn := mocks.NewDownloaderMock(mc)
+	dwn.AcceptsURLMock.Return(false)
+
+	svc := service.NewService(
+		dwn, mocks.NewStorageMock(mc), mocks.NewJobsQueueMock(mc),
+		mocks.NewMediaProcessorMock(mc), mocks.NewUploaderMock(mc), logger,
+	)
+
+	_, err := svc.Probe(context.Background(), service.ProbeRequest{URL: "ftp://example.com/file.mp3", Variant: "file.mp3"})
+	if !errors.Is(err, service.ErrUrlNotSupported) {
+		t.Fatalf("expected ErrUrlNotSupported, got %v", err)
+	}
+}
+
+func TestSelectAudioStream(t *testing.T) {
+	info := &service.MediaInfo{Streams: []service.StreamInfo{
+		{Index: 0, Type: service.StreamVideo, Codec: "h264"},
+		{Index: 1, Type: service.StreamAudio, Codec: "aac", Language: "rus"},
+		{Index: 2, Type: service.StreamSubtitle, Codec: "subrip", Language: "eng"},
+		{Index: 3, Type: service.StreamAudio, Codec: "ac3", Language: "eng"},
+	}}
+	one := 1
+	for name, tc := range map[string]struct {
+		sel          service.AudioStreamSelector
+		wantPosition int
+		wantIndex    int
+	}{
+		"first by default":      {sel: service.AudioStreamSelector{}, wantPosition: 0, wantIndex: 1},
+		"by language":           {sel: service.AudioStreamSelector{Language: "ENG"}, wantPosition: 1, wantIndex: 3},
+		"by audio stream index": {sel: service.AudioStreamSelector{Index: &one}, wantPosition: 1, wantIndex: 3},
+	} {
+		t.Run(name, func(t *testing.T) {
+			position, stream, err := info.SelectAudioStream(tc.sel)
+			if err != nil {
+				t.Fatalf("SelectAudioStream: %v", err)
+			}
+			if position != tc.wantPosition || stream.Index != tc.wantIndex {
+				t.Errorf("got #%d (stream %d), want #%d (stream %d)", position, stream.Index, tc.wantPosition, tc.wantIndex)
+			}
+		})
+	}
+
+	if _, _, err := info.SelectAudioStream(service.AudioStreamSelector{Language: "fra"}); !errors.Is(err, service.ErrAudioStreamNotFound) {
+		t.Errorf("expected ErrAudioStreamNotFound, got %v", err)
+	}
+}
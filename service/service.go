@@ -1,9 +1,12 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
 
@@ -102,6 +105,31 @@ type Downloader interface {
 	Download(ctx context.Context, url string, filepaths []string) (filepathsMap map[string]string, err error)
 }
 
+//go:generate  go tool github.com/gojuno/minimock/v3/cmd/minimock -i SectionDownloader -o ./mocks/section_downloader_mock.go -g
+
+// SectionDownloader is implemented by downloaders that can download ranges of a variant
+// without downloading the whole of it
+type SectionDownloader interface {
+	// DownloadSections downloads each of the ranges into a file of its own, in order.
+	// Fast cuts at keyframes, like ClipOptions.Fast, instead of cutting at exact positions.
+	DownloadSections(ctx context.Context, url string, variant string, ranges []TimeRange, fast bool) (filepaths []string, err error)
+}
+
+// Streamer is implemented by downloaders that can serve the content of a single variant
+// while it is still being downloaded.
+type Streamer interface {
+	// Stream returns a seekable reader over the given variant.
+	// Reads block until the requested bytes are available.
+	Stream(ctx context.Context, url string, variant string) (*VariantStream, error)
+}
+
+type VariantStream struct {
+	io.ReadSeekCloser
+	// Name is the base name of the variant, used to guess its content type
+	Name string
+	Size int64
+}
+
 //go:generate  go tool github.com/gojuno/minimock/v3/cmd/minimock -i JobsQueue -o ./mocks/jobs_queue_mock.go -g
 type JobsQueue interface {
 	Publish(ctx context.Context, jobType string, payload any) error
@@ -112,30 +140,452 @@ type JobsQueue interface {
 
 //go:generate  go tool github.com/gojuno/minimock/v3/cmd/minimock -i Storage -o ./mocks/storage_mock.go -g
 type Storage interface {
-	GetMetadata(ctx context.Context, url string) (*Metadata, error)
-	SaveMetadata(ctx context.Context, metadata *Metadata) error
+	// GetMetadata returns metadata of the URL fetched with the named downloader, "" standing for the default one,
+	// nil if there is none
+	GetMetadata(ctx context.Context, url string, downloader string) (*Metadata, error)
+	SaveMetadata(ctx context.Context, downloader string, metadata *Metadata) error
 	GetJob(ctx context.Context, id string) (*Job, error)
 	SaveJob(ctx context.Context, job *Job) error
+	// GetWaveform returns peaks of the part-th result of the job, nil if there are none
+	GetWaveform(ctx context.Context, jobID string, part int) (*WaveformPeaks, error)
+	SaveWaveform(ctx context.Context, jobID string, part int, peaks *WaveformPeaks) error
 }
 
 //go:generate  go tool github.com/gojuno/minimock/v3/cmd/minimock -i MediaProcessor -o ./mocks/media_processor_mock.go -g
 type MediaProcessor interface {
-	Concatenate(ctx context.Context, filepaths []string, audioCodec string) (resultFilepath string, err error)
+	Concatenate(ctx context.Context, filepaths []string, opts ConcatenateOptions) (resultFilepath string, err error)
 	GetInfo(ctx context.Context, filepath string) (info *MediaInfo, err error)
 	AddChapterTags(ctx context.Context, filepath string, chapters []Chapter) error
+	// Transcode re-encodes the file into the given container, the result gets the container's extension
+	Transcode(ctx context.Context, filepath string, opts TranscodeOptions) (resultFilepath string, err error)
+	// WriteMetadata writes a copy of the file with the given tags, chapters and cover art, without re-encoding.
+	// Unlike AddChapterTags, chapters are written natively for the container (e.g. MP4 chapter atoms).
+	// The original file is left intact: it may well be a source file that is not ours to modify.
+	WriteMetadata(ctx context.Context, filepath string, metadata FileMetadata) (resultFilepath string, err error)
+	// ExtractCoverArt saves embedded cover art into a separate file.
+	// An error is returned if there is no cover art.
+	ExtractCoverArt(ctx context.Context, filepath string) (coverArtFilepath string, err error)
+	// Normalize writes a copy of the file with loudness normalized by two-pass EBU R128 loudnorm.
+	// Audio is re-encoded with the default codec of the file's container.
+	Normalize(ctx context.Context, filepath string, opts NormalizeOptions) (resultFilepath string, stats *LoudnessStats, err error)
+	// TrimSilence writes a copy of the file without leading and trailing silence, optionally shortening inner silences.
+	// The file itself is returned when there is nothing to trim.
+	TrimSilence(ctx context.Context, filepath string, opts TrimSilenceOptions) (resultFilepath string, err error)
+	// DetectSilenceChapters proposes chapters of the file, split at long pauses
+	DetectSilenceChapters(ctx context.Context, filepath string, opts SilenceChapterOptions) (chapters []Chapter, err error)
+	// Split cuts the file into parts without re-encoding
+	Split(ctx context.Context, filepath string, opts SplitOptions) (parts []SplitPart, err error)
+	// Clip cuts ranges out of the file, into a file per range
+	Clip(ctx context.Context, filepath string, opts ClipOptions) (filepaths []string, err error)
+	// ChangeSpeed writes a copy of the file played at the given speed, keeping the pitch; embedded chapters are scaled
+	ChangeSpeed(ctx context.Context, filepath string, opts SpeedOptions) (resultFilepath string, err error)
+	// ExtractAudio writes a single audio stream of the file into an audio-only container, keeping tags and chapters
+	ExtractAudio(ctx context.Context, filepath string, opts ExtractAudioOptions) (resultFilepath string, err error)
+	// ComputePeaks downsamples the first audio stream of the file, mixed down to mono, into waveform peaks
+	ComputePeaks(ctx context.Context, filepath string, opts WaveformOptions) (peaks *WaveformPeaks, err error)
+	// RenderWaveform renders a PNG waveform or spectrogram of the first audio stream of the file
+	RenderWaveform(ctx context.Context, filepath string, opts WaveformImageOptions) (imageFilepath string, err error)
+	// ContactSheet renders evenly spaced frames of the video into a single JPEG grid
+	ContactSheet(ctx context.Context, filepath string, opts ContactSheetOptions) (imageFilepath string, err error)
+	// PosterFrame saves a single frame of the video as a JPEG
+	PosterFrame(ctx context.Context, filepath string, opts PosterFrameOptions) (imageFilepath string, err error)
+	// PreviewClip cuts a short low-bitrate mp4 out of the video
+	PreviewClip(ctx context.Context, filepath string, opts PreviewClipOptions) (resultFilepath string, err error)
 }
 
+// MediaInfo is what ffprobe tells about a file
 type MediaInfo struct {
-	Duration     time.Duration
-	FileLenBytes int64
+	Duration     time.Duration `json:"duration"`
+	FileLenBytes int64         `json:"file_bytes"`
+	// Title is the title tag of the file (e.g. ID3 TIT2), empty if there is none
+	Title string `json:"title,omitempty"`
+	// Container is the format name as reported by ffprobe, like "mov,mp4,m4a,3gp,3g2,mj2"
+	Container string `json:"container"`
+	// BitRate is the overall bitrate in bits per second, zero when unknown
+	BitRate int64        `json:"bit_rate,omitempty"`
+	Streams []StreamInfo `json:"streams"`
+	// Tags are tags of the container, with lowercase keys
+	Tags     map[string]string `json:"tags,omitempty"`
+	Chapters []Chapter         `json:"chapters,omitempty"`
+}
+
+// AudioStream returns the first audio stream, or nil if there is none
+func (info *MediaInfo) AudioStream() *StreamInfo {
+	for i := range info.Streams {
+		if info.Streams[i].Type == StreamAudio {
+			return &info.Streams[i]
+		}
+	}
+	return nil
+}
+
+// VideoStream is the first video stream that is not an embedded picture, nil for audio
+func (info *MediaInfo) VideoStream() *StreamInfo {
+	for i := range info.Streams {
+		if info.Streams[i].Type == StreamVideo && !info.Streams[i].CoverArt {
+			return &info.Streams[i]
+		}
+	}
+	return nil
+}
+
+var ErrAudioStreamNotFound = fmt.Errorf("audio stream not found")
+
+// AudioStreamSelector picks one of several audio streams, like one of the dubs of a lecture.
+// The zero value picks the first audio stream.
+type AudioStreamSelector struct {
+	// Language is a language tag of the stream, usually ISO 639-2 like "eng"; the first stream in it is picked
+	Language string `json:"language,omitempty"`
+	// Index is a position among audio streams only, from zero
+	Index *int `json:"index,omitempty"`
+}
+
+func (sel AudioStreamSelector) Validate() error {
+	if sel.Language != "" && sel.Index != nil {
+		return fmt.Errorf("audio stream should be picked either by language or by index, not both")
+	}
+	if sel.Index != nil && *sel.Index < 0 {
+		return fmt.Errorf("audio stream index can't be negative")
+	}
+	return nil
+}
+
+// SelectAudioStream returns the selected stream along with its position among audio streams,
+// which is what ffmpeg stream specifiers like "0:a:1" refer to
+func (info *MediaInfo) SelectAudioStream(sel AudioStreamSelector) (int, *StreamInfo, error) {
+	position := 0
+	for i := range info.Streams {
+		stream := &info.Streams[i]
+		if stream.Type != StreamAudio {
+			continue
+		}
+		switch {
+		case sel.Index != nil && *sel.Index == position,
+			sel.Language != "" && strings.EqualFold(stream.Language, sel.Language),
+			sel.Index == nil && sel.Language == "":
+			return position, stream, nil
+		}
+		position++
+	}
+	if sel.Language != "" {
+		return 0, nil, fmt.Errorf("%w: no audio in %q", ErrAudioStreamNotFound, sel.Language)
+	}
+	if sel.Index != nil {
+		return 0, nil, fmt.Errorf("%w: there are %d audio streams, no #%d", ErrAudioStreamNotFound, position, *sel.Index)
+	}
+	return 0, nil, fmt.Errorf("%w: no audio streams", ErrAudioStreamNotFound)
+}
+
+// Stream types
+const (
+	StreamAudio      = "audio"
+	StreamVideo      = "video"
+	StreamSubtitle   = "subtitle"
+	StreamData       = "data"
+	StreamAttachment = "attachment"
+)
+
+type StreamInfo struct {
+	Index int `json:"index"`
+	// Type is one of StreamAudio, StreamVideo, StreamSubtitle, StreamData or StreamAttachment
+	Type  string `json:"type"`
+	Codec string `json:"codec"`
+	// BitRate is in bits per second, zero when unknown
+	BitRate       int64  `json:"bit_rate,omitempty"`
+	SampleRate    int    `json:"sample_rate,omitempty"`
+	Channels      int    `json:"channels,omitempty"`
+	ChannelLayout string `json:"channel_layout,omitempty"`
+	Width         int    `json:"width,omitempty"`
+	Height        int    `json:"height,omitempty"`
+	// FrameRate is in frames per second, zero when unknown
+	FrameRate float64 `json:"frame_rate,omitempty"`
+	// CoverArt is set for video streams that are embedded pictures rather than video
+	CoverArt bool   `json:"cover_art,omitempty"`
+	Language string `json:"language,omitempty"`
 }
 
 type Chapter struct {
+	Title     string        `json:"title,omitempty"`
+	StartTime time.Duration `json:"start_time"`
+	EndTime   time.Duration `json:"end_time"`
+}
+
+type Tags struct {
+	Title       string `json:"title,omitempty"`
+	Artist      string `json:"artist,omitempty"`
+	Album       string `json:"album,omitempty"`
+	AlbumArtist string `json:"albumArtist,omitempty"`
+	// Year is a year or a full date, like "2021" or "2021-03-15"
+	Year  string `json:"year,omitempty"`
+	Genre string `json:"genre,omitempty"`
+	// Track is a track number, optionally with total, like "3" or "3/12"
+	Track   string `json:"track,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	// Author is an audiobook author, used as artist unless one is set explicitly
+	Author string `json:"author,omitempty"`
+	// Narrator is an audiobook narrator, stored as composer by convention
+	Narrator string `json:"narrator,omitempty"`
+}
+
+func (t Tags) IsEmpty() bool {
+	return t == Tags{}
+}
+
+// WithDefaults fills in tags that are not set from defaults
+func (t Tags) WithDefaults(defaults Tags) Tags {
+	return Tags{
+		Title:       cmp.Or(t.Title, defaults.Title),
+		Artist:      cmp.Or(t.Artist, defaults.Artist),
+		Album:       cmp.Or(t.Album, defaults.Album),
+		AlbumArtist: cmp.Or(t.AlbumArtist, defaults.AlbumArtist),
+		Year:        cmp.Or(t.Year, defaults.Year),
+		Genre:       cmp.Or(t.Genre, defaults.Genre),
+		Track:       cmp.Or(t.Track, defaults.Track),
+		Comment:     cmp.Or(t.Comment, defaults.Comment),
+		Author:      cmp.Or(t.Author, defaults.Author),
+		Narrator:    cmp.Or(t.Narrator, defaults.Narrator),
+	}
+}
+
+type FileMetadata struct {
+	Tags     Tags
+	Chapters []Chapter
+	// CoverArtFilepath is an image to embed, if any
+	CoverArtFilepath string
+}
+
+// NormalizeOptions are EBU R128 loudness targets
+type NormalizeOptions struct {
+	// IntegratedLUFS is the target integrated loudness
+	IntegratedLUFS float64
+	// TruePeak is the maximum true peak, in dBTP
+	TruePeak float64
+	// LRA is the target loudness range, in LU
+	LRA float64
+}
+
+// LoudnessStats are loudness measurements of a file before and after normalization
+type LoudnessStats struct {
+	// Variant is set when the file is an input, normalized before concatenation
+	Variant     string  `json:"variant,omitempty"`
+	InputI      float64 `json:"input_i"`
+	InputTP     float64 `json:"input_tp"`
+	InputLRA    float64 `json:"input_lra"`
+	InputThresh float64 `json:"input_thresh"`
+	OutputI     float64 `json:"output_i"`
+	OutputTP    float64 `json:"output_tp"`
+	OutputLRA   float64 `json:"output_lra"`
+	// NormalizationType is "linear" when gain alone was enough, "dynamic" when loudness range had to be compressed,
+	// and "none" for silent files, which are left as they are
+	NormalizationType string  `json:"normalization_type"`
+	TargetOffset      float64 `json:"target_offset"`
+}
+
+// SilenceOptions describe what counts as silence
+type SilenceOptions struct {
+	// NoiseDB is the level below which audio is considered silent, like -50
+	NoiseDB float64
+	// MinSilence is the shortest pause that counts as silence
+	MinSilence time.Duration
+}
+
+type TrimSilenceOptions struct {
+	SilenceOptions
+	// MaxInnerSilence, when set, shortens longer silences inside the file down to it
+	MaxInnerSilence time.Duration
+}
+
+type SilenceChapterOptions struct {
+	SilenceOptions
+	// MinChapterLength prevents splitting at pauses that are closer than that to the previous split
+	MinChapterLength time.Duration
+}
+
+// TimeRange is a segment of media, End is zero when it lasts until the end
+type TimeRange struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+type ClipOptions struct {
+	Ranges []TimeRange
+	// Fast cuts at keyframes with stream copy, instead of re-encoding for frame-accurate cuts.
+	// Cuts of video might start up to a few seconds early. Fades require re-encoding anyway.
+	Fast    bool
+	FadeIn  time.Duration
+	FadeOut time.Duration
+	// Container of the clips, the original's when empty. Clips into another container are re-encoded.
+	Container string
+}
+
+// Split modes
+const (
+	SplitByDuration = "duration"
+	SplitBySize     = "size"
+	SplitByChapters = "chapters"
+)
+
+type SplitOptions struct {
+	Mode string
+	// MaxDuration is the longest part of SplitByDuration
+	MaxDuration time.Duration
+	// MaxBytes is the largest part of SplitBySize
+	MaxBytes int64
+	// SilenceWindow, when set, moves boundaries of duration and size splits back to a pause
+	// at most that far from them, so that parts don't end mid-word
+	SilenceWindow time.Duration
+	Silence       SilenceOptions
+}
+
+// SplitPart is a part of a split file, positioned within the original one
+type SplitPart struct {
+	Filepath string
+	// Title is the title of the chapter the part was cut along, if any
 	Title     string
 	StartTime time.Duration
 	EndTime   time.Duration
 }
 
+// ConcatenateOptions describe the result of concatenation, whatever the inputs are
+type ConcatenateOptions struct {
+	// Container of the result, like "mp3"
+	Container string
+	// AudioCodec is an ffmpeg encoder. "copy" or empty keeps the streams as they are when inputs allow,
+	// and falls back to the container's default codec when they don't
+	AudioCodec string
+	// Video only matters for video containers, where parts are concatenated as videos
+	Video VideoOptions
+	// AudioStream picks an audio stream of every video part; audio-only parts are expected to be extracted beforehand
+	AudioStream AudioStreamSelector
+	// Engine is ConcatEngineFFmpeg when empty
+	Engine string
+}
+
+// Concatenation engines
+const (
+	// ConcatEngineFFmpeg concatenates anything ffmpeg does
+	ConcatEngineFFmpeg = "ffmpeg"
+	// ConcatEngineNative joins MP3 frames as they are, without ffmpeg, rebuilding the Xing header of the result.
+	// It only makes mp3 of mp3 inputs, which have to share sample rate and being mono or not.
+	ConcatEngineNative = "native"
+)
+
+// MaxCRF is the largest constant rate factor of the supported encoders, the one of VP9
+const MaxCRF = 63
+
+// VideoOptions describe the video of a concatenation. Zero values keep video streams as they are when parts allow.
+type VideoOptions struct {
+	// Codec is an ffmpeg encoder. "copy" or empty keeps the streams as they are when parts share codec,
+	// resolution and frame rate, and falls back to the container's default codec when they don't
+	Codec string `json:"codec"`
+	// CRF is a constant rate factor, lower is better; the encoder's default when zero. Setting it re-encodes.
+	CRF int `json:"crf"`
+	// Width and Height scale every part, letterboxing it when aspect ratios differ; the size of the largest part when zero.
+	// Setting them re-encodes.
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+func (opts VideoOptions) Validate() error {
+	if opts.CRF < 0 || opts.CRF > MaxCRF {
+		return fmt.Errorf("crf must be within [0, %d], got %d", MaxCRF, opts.CRF)
+	}
+	if (opts.Width == 0) != (opts.Height == 0) {
+		return fmt.Errorf("both width and height must be set, got %dx%d", opts.Width, opts.Height)
+	}
+	if opts.Width < 0 || opts.Height < 0 || opts.Width%2 != 0 || opts.Height%2 != 0 {
+		return fmt.Errorf("width and height must be positive and even, got %dx%d", opts.Width, opts.Height)
+	}
+	if opts.Codec == "copy" && (opts.CRF != 0 || opts.Width != 0) {
+		return fmt.Errorf("crf and resolution need re-encoding, video codec can't be copy")
+	}
+	return nil
+}
+
+// Reencodes tells whether the options force re-encoding, whatever the parts are
+func (opts VideoOptions) Reencodes() bool {
+	return (opts.Codec != "" && opts.Codec != "copy") || opts.CRF != 0 || opts.Width != 0
+}
+
+// WaveformPeaks are minimum and maximum sample values of every pixel, in audiowaveform's JSON format (version 2),
+// see https://github.com/bbc/audiowaveform/blob/master/doc/DataFormat.md
+type WaveformPeaks struct {
+	Version         int `json:"version"`
+	Channels        int `json:"channels"`
+	SampleRate      int `json:"sample_rate"`
+	SamplesPerPixel int `json:"samples_per_pixel"`
+	Bits            int `json:"bits"`
+	// Length is the number of pixels
+	Length int `json:"length"`
+	// Data are min and max pairs of every pixel, Length pairs in total
+	Data []int16 `json:"data"`
+}
+
+type WaveformOptions struct {
+	PixelsPerSecond int
+	// Bits is the resolution of peak values, either 8 or 16
+	Bits int
+}
+
+type WaveformImageOptions struct {
+	// Kind is either WaveformImageWaveform or WaveformImageSpectrogram
+	Kind   string
+	Width  int
+	Height int
+	// Color is only used by waveforms, ffmpeg's default when empty
+	Color string
+}
+
+type ContactSheetOptions struct {
+	// Frames are spaced evenly, each one in the middle of its share of the video
+	Frames  int
+	Columns int
+	// Width of a single frame, its height keeps the aspect ratio
+	Width int
+}
+
+type PosterFrameOptions struct {
+	// At is the time of the frame, the last second of the video is taken when it is beyond the end
+	At time.Duration
+	// Width of the poster, its height keeps the aspect ratio; the video's own size when zero
+	Width int
+}
+
+type PreviewClipOptions struct {
+	Start    time.Duration
+	Duration time.Duration
+	// Height of the clip, videos that are smaller are not upscaled
+	Height int
+	// Bitrate of the video, like "400k"
+	Bitrate string
+}
+
+// ExtractAudioOptions describe an audio file extracted out of a video, or out of any other media
+type ExtractAudioOptions struct {
+	// Container is an audio-only container, like "mp3"; when empty, the one that holds the stream as it is
+	Container string
+	// AudioCodec is an ffmpeg encoder. "copy" or empty keeps the stream as it is when the container allows,
+	// and falls back to the container's default codec when it doesn't
+	AudioCodec string
+	// Bitrate, like "64k", is only used when re-encoding; the source bitrate is matched when empty
+	Bitrate     string
+	AudioStream AudioStreamSelector
+}
+
+// TranscodeOptions describe the desired output. Zero values mean "container's default".
+type TranscodeOptions struct {
+	Container  string `json:"container"`
+	AudioCodec string `json:"audioCodec"`
+	// Bitrate is a constant bitrate, like "64k"
+	Bitrate string `json:"bitrate"`
+	// Quality is a codec-specific VBR quality (ffmpeg's -q:a), used instead of Bitrate
+	Quality    *int `json:"quality"`
+	SampleRate int  `json:"sampleRate"`
+	Channels   int  `json:"channels"`
+	// Speed is a playback speed within [MinSpeed, MaxSpeed], pitch is preserved. Zero keeps the speed.
+	Speed float64 `json:"speed"`
+}
+
 //go:generate  go tool github.com/gojuno/minimock/v3/cmd/minimock -i Uploader -o ./mocks/uploader_mock.go -g
 type Uploader interface {
 	Upload(ctx context.Context, filepath string, url string) (err error)
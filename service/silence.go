@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultTrimSilenceOptions only trim leading and trailing silences
+var DefaultTrimSilenceOptions = TrimSilenceOptions{
+	SilenceOptions: SilenceOptions{NoiseDB: -50, MinSilence: 1 * time.Second},
+}
+
+// DefaultSilenceChapterOptions suit lectures, where pauses between topics are noticeably longer than between sentences
+var DefaultSilenceChapterOptions = SilenceChapterOptions{
+	SilenceOptions:   SilenceOptions{NoiseDB: -50, MinSilence: 2 * time.Second},
+	MinChapterLength: 5 * time.Minute,
+}
+
+// TrimSilenceParams is the "trimSilence" job param, omitted values take their defaults
+type TrimSilenceParams struct {
+	NoiseDB    float64   `json:"noiseDb"`
+	MinSilence Timestamp `json:"minSilence"`
+	// MaxInnerSilence, when set, shortens longer silences between the edges down to it
+	MaxInnerSilence Timestamp `json:"maxInnerSilence"`
+}
+
+func (p *TrimSilenceParams) Options() (TrimSilenceOptions, error) {
+	opts := DefaultTrimSilenceOptions
+	if err := applySilenceParams(p.NoiseDB, p.MinSilence, &opts.SilenceOptions); err != nil {
+		return TrimSilenceOptions{}, err
+	}
+	opts.MaxInnerSilence = time.Duration(p.MaxInnerSilence)
+	return opts, nil
+}
+
+// SilenceChapterParams is the "silenceChapters" job param, omitted values take their defaults
+type SilenceChapterParams struct {
+	NoiseDB          float64   `json:"noiseDb"`
+	MinSilence       Timestamp `json:"minSilence"`
+	MinChapterLength Timestamp `json:"minChapterLength"`
+}
+
+func (p *SilenceChapterParams) Options() (SilenceChapterOptions, error) {
+	opts := DefaultSilenceChapterOptions
+	if err := applySilenceParams(p.NoiseDB, p.MinSilence, &opts.SilenceOptions); err != nil {
+		return SilenceChapterOptions{}, err
+	}
+	if p.MinChapterLength != 0 {
+		opts.MinChapterLength = time.Duration(p.MinChapterLength)
+	}
+	return opts, nil
+}
+
+func applySilenceParams(noiseDB float64, minSilence Timestamp, opts *SilenceOptions) error {
+	if noiseDB != 0 {
+		if noiseDB < -90 || noiseDB > -10 {
+			return fmt.Errorf("noiseDb should be within [-90, -10]")
+		}
+		opts.NoiseDB = noiseDB
+	}
+	if minSilence != 0 {
+		opts.MinSilence = time.Duration(minSilence)
+	}
+	return nil
+}
+
+// trimSilence trims silences of the file, and returns either a trimmed copy or the file itself
+func (svc *Service) trimSilence(ctx context.Context, fp string, variant string, opts TrimSilenceOptions) (string, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/service").Start(ctx, "service.TrimSilence",
+		trace.WithAttributes(attribute.String("variant", variant)),
+	)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Hour)
+	defer cancel()
+
+	resultFilepath, err := svc.mediaProcessor.TrimSilence(ctx, fp, opts)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", oops.With("filepath", fp, "variant", variant).Wrapf(err, "failed to trim silence")
+	}
+	return resultFilepath, nil
+}
+
+// silenceChapters detects chapters of the file at long pauses, titled by user-supplied titles when there are any
+func (svc *Service) silenceChapters(ctx context.Context, fp string, opts ChapterOptions) ([]Chapter, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/service").Start(ctx, "service.DetectSilenceChapters")
+	defer span.End()
+
+	detectOpts, err := opts.SilenceChapters.Options()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Hour)
+	defer cancel()
+
+	chapters, err := svc.mediaProcessor.DetectSilenceChapters(ctx, fp, detectOpts)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, oops.With("filepath", fp).Wrapf(err, "failed to detect chapters")
+	}
+	for i := range chapters {
+		if i < len(opts.Chapters) && opts.Chapters[i].Title != "" {
+			chapters[i].Title = opts.Chapters[i].Title
+		}
+	}
+	span.SetAttributes(attribute.Int("chapters.count", len(chapters)))
+	return chapters, nil
+}
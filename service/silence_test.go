@@ -0,0 +1,60 @@
+package service_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/service"
+)
+
+func TestTrimSilenceParams_Options(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		json    string
+		want    service.TrimSilenceOptions
+		wantErr bool
+	}{
+		{name: "defaults", json: `{}`, want: service.DefaultTrimSilenceOptions},
+		{
+			name: "compress inner silences",
+			json: `{"noiseDb": -35, "minSilence": 0.5, "maxInnerSilence": "0:02"}`,
+			want: service.TrimSilenceOptions{
+				SilenceOptions:  service.SilenceOptions{NoiseDB: -35, MinSilence: 500 * time.Millisecond},
+				MaxInnerSilence: 2 * time.Second,
+			},
+		},
+		{name: "positive noise", json: `{"noiseDb": 5}`, wantErr: true},
+		{name: "below hearing", json: `{"noiseDb": -120}`, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var params service.TrimSilenceParams
+			if err := json.Unmarshal([]byte(tc.json), &params); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			got, err := params.Options()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Options() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && got != tc.want {
+				t.Errorf("Options() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSilenceChapterParams_Options(t *testing.T) {
+	var params service.SilenceChapterParams
+	if err := json.Unmarshal([]byte(`{"minChapterLength": "15:00"}`), &params); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	got, err := params.Options()
+	if err != nil {
+		t.Fatalf("Options() error = %v", err)
+	}
+	want := service.DefaultSilenceChapterOptions
+	want.MinChapterLength = 15 * time.Minute
+	if got != want {
+		t.Errorf("Options() = %+v, want %+v", got, want)
+	}
+}
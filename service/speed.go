@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Playback speed limits, beyond them speech becomes unintelligible
+const (
+	MinSpeed = 0.5
+	MaxSpeed = 4.0
+)
+
+var ErrInvalidSpeed = fmt.Errorf("invalid speed")
+
+type SpeedOptions struct {
+	Speed float64
+	// Container is the one to re-encode into when the file's own one can't be written, like APE
+	Container string
+}
+
+// ValidateSpeed accepts zero, which means the speed is left as it is
+func ValidateSpeed(speed float64) error {
+	if speed != 0 && (speed < MinSpeed || speed > MaxSpeed) {
+		return fmt.Errorf("%w: %g is not within [%g, %g]", ErrInvalidSpeed, speed, MinSpeed, MaxSpeed)
+	}
+	return nil
+}
+
+// changesSpeed tells whether the speed has to be changed at all
+func changesSpeed(speed float64) bool {
+	return speed != 0 && speed != 1
+}
+
+// ScaleChapters moves chapters to where they end up once media is played at the given speed
+func ScaleChapters(chapters []Chapter, speed float64) []Chapter {
+	if !changesSpeed(speed) {
+		return chapters
+	}
+	scale := func(d time.Duration) time.Duration {
+		return time.Duration(math.Round(float64(d) / speed))
+	}
+	scaled := make([]Chapter, len(chapters))
+	for i, ch := range chapters {
+		scaled[i] = Chapter{Title: ch.Title, StartTime: scale(ch.StartTime), EndTime: scale(ch.EndTime)}
+	}
+	return scaled
+}
+
+// changeSpeed speeds the file up, or slows it down, into a copy
+func (svc *Service) changeSpeed(ctx context.Context, fp string, opts SpeedOptions) (string, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/service").Start(ctx, "service.ChangeSpeed",
+		trace.WithAttributes(attribute.Float64("speed", opts.Speed), attribute.String("container", opts.Container)),
+	)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Hour)
+	defer cancel()
+
+	resultFilepath, err := svc.mediaProcessor.ChangeSpeed(ctx, fp, opts)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", oops.With("filepath", fp, "opts", opts).Wrapf(err, "failed to change speed")
+	}
+	return resultFilepath, nil
+}
@@ -0,0 +1,26 @@
+package service_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/service"
+)
+
+func TestScaleChapters(t *testing.T) {
+	chapters := []service.Chapter{
+		{Title: "One", StartTime: 0, EndTime: 90 * time.Second},
+		{Title: "Two", StartTime: 90 * time.Second, EndTime: 10 * time.Minute},
+	}
+	want := []service.Chapter{
+		{Title: "One", StartTime: 0, EndTime: 60 * time.Second},
+		{Title: "Two", StartTime: 60 * time.Second, EndTime: 400 * time.Second},
+	}
+	if got := service.ScaleChapters(chapters, 1.5); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if got := service.ScaleChapters(chapters, 0); !reflect.DeepEqual(got, chapters) {
+		t.Errorf("zero speed changed chapters: %+v", got)
+	}
+}
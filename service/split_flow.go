@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// partPlaceholder stands for the part number in upload URL templates
+const partPlaceholder = "{part}"
+
+// Smallest parts that make sense, anything less is most likely a mistake in units
+const (
+	minSplitDuration = 1 * time.Minute
+	minSplitBytes    = 1 << 20
+)
+
+// DefaultSplitSilenceWindow is how far back a boundary can move to reach a pause
+const DefaultSplitSilenceWindow = 2 * time.Minute
+
+// ResultPart is a file of split and clip job results
+type ResultPart struct {
+	Title     string        `json:"title,omitempty"`
+	StartTime time.Duration `json:"start_time"`
+	Duration  time.Duration `json:"duration"`
+	FileBytes int64         `json:"file_bytes"`
+	UploadURL string        `json:"upload_url"`
+}
+
+// SplitSilenceParams is the "atSilence" param of split jobs, omitted values take their defaults
+type SplitSilenceParams struct {
+	Window     Timestamp `json:"window"`
+	NoiseDB    float64   `json:"noiseDb"`
+	MinSilence Timestamp `json:"minSilence"`
+}
+
+func (svc *Service) newSplitFlow(jobID string, job *Job) (func(ctx context.Context) error, error) {
+	logAttrs := []any{slog.String("jobID", jobID), slog.Any("job", job)}
+	errCtx := oops.With("jobID", jobID, "job", job)
+	type Params struct {
+		Variant string `json:"variant"`
+		// Mode is one of SplitByDuration, SplitBySize or SplitByChapters
+		Mode        string    `json:"mode"`
+		MaxDuration Timestamp `json:"maxDuration"`
+		MaxBytes    int64     `json:"maxBytes"`
+		// AtSilence, when set, moves boundaries of duration and size splits to pauses nearby
+		AtSilence *SplitSilenceParams `json:"atSilence"`
+		// UploadURLs are URLs of parts, in order; there should be at least as many as there are parts
+		UploadURLs []string `json:"uploadUrls"`
+		// UploadURLTemplate is used instead of UploadURLs, with {part} replaced by the part number
+		UploadURLTemplate string `json:"uploadUrlTemplate"`
+		// Waveform, when set, makes waveform peaks and images of the result
+		Waveform *WaveformParams `json:"waveform"`
+		// Previews, when set, makes visual previews of the resulting video
+		Previews *PreviewParams `json:"previews"`
+	}
+	params := Params{}
+	if err := mapToStruct(job.Params, &params); err != nil {
+		return nil, errCtx.Wrapf(err, "failed to parse job params")
+	}
+	if err := params.Waveform.validate(true); err != nil {
+		return nil, errCtx.Wrapf(err, "invalid waveform")
+	}
+	if err := params.Previews.validate(true); err != nil {
+		return nil, errCtx.Wrapf(err, "invalid previews")
+	}
+	if params.Variant == "" {
+		return nil, errCtx.Errorf("no variant provided")
+	}
+	opts, err := splitOptions(params.Mode, time.Duration(params.MaxDuration), params.MaxBytes, params.AtSilence)
+	if err != nil {
+		return nil, errCtx.Wrapf(err, "invalid split")
+	}
+	if err := validatePartUploads(params.UploadURLs, params.UploadURLTemplate); err != nil {
+		return nil, errCtx.Wrap(err)
+	}
+	logAttrs = append(logAttrs, slog.Any("params", params))
+	errCtx = errCtx.With("params", params)
+	svc.log.Debug("parsed job params", logAttrs...)
+
+	return func(jobCtx context.Context) error {
+		jobCtx, span := otel.Tracer("github.com/dir01/mediary/service").Start(jobCtx, "service.SplitFlow",
+			trace.WithAttributes(
+				attribute.String("job.id", jobID),
+				attribute.String("variant", params.Variant),
+				attribute.String("mode", opts.Mode),
+			),
+		)
+		defer span.End()
+
+		ctx, cancel := context.WithTimeout(jobCtx, 10*time.Second)
+		defer cancel()
+		job, err := svc.storage.GetJob(ctx, jobID)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrapf(err, "failed to get job")
+		}
+
+		updateJobStatus := func(status string) {
+			statusCtx, statusCancel := context.WithTimeout(jobCtx, 10*time.Second)
+			defer statusCancel()
+			job.DisplayStatus = status
+			if err = svc.storage.SaveJob(statusCtx, job); err != nil {
+				attrs := append([]any{
+					slog.String("state", job.DisplayStatus),
+					slog.Any("error", err),
+				}, logAttrs...)
+				svc.log.Error("failed to save job state, proceeding", attrs...)
+			}
+		}
+
+		updateJobStatus(JobStatusDownloading)
+		svc.log.Debug("starting download", logAttrs...)
+
+		downloadCtx, downloadCancel := context.WithTimeout(jobCtx, 1*time.Hour)
+		defer downloadCancel()
+
+		downloadCtx, downloadSpan := otel.Tracer("github.com/dir01/mediary/service").Start(downloadCtx, "service.Download",
+			trace.WithAttributes(
+				attribute.String("job.id", jobID),
+				attribute.String("url", job.URL),
+				attribute.String("variant", params.Variant),
+			),
+		)
+		downloader, err := svc.selectDownloader(job.Downloader)
+		if err != nil {
+			downloadSpan.RecordError(err)
+			downloadSpan.SetStatus(codes.Error, err.Error())
+			downloadSpan.End()
+			return errCtx.Wrapf(err, "failed to select downloader")
+		}
+		filepathsMap, err := svc.download(downloadCtx, downloader, job.Downloader, job.URL, []string{params.Variant})
+		if err != nil {
+			downloadSpan.RecordError(err)
+			downloadSpan.SetStatus(codes.Error, err.Error())
+			downloadSpan.End()
+			return errCtx.Wrapf(err, "failed to download files")
+		}
+		downloadSpan.End()
+
+		downloadedFilepath := filepathsMap[params.Variant]
+		logAttrs = append(logAttrs, slog.String("downloadedFilepath", downloadedFilepath))
+		errCtx = errCtx.With("downloadedFilepath", downloadedFilepath)
+
+		updateJobStatus(JobStatusProcessing)
+		svc.log.Debug("starting split", logAttrs...)
+
+		splitCtx, splitCancel := context.WithTimeout(jobCtx, 1*time.Hour)
+		defer splitCancel()
+
+		parts, err := svc.mediaProcessor.Split(splitCtx, downloadedFilepath, opts)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrapf(err, "failed to split")
+		}
+		span.SetAttributes(attribute.Int("parts.count", len(parts)))
+		if len(params.UploadURLs) > 0 && len(params.UploadURLs) < len(parts) {
+			err := fmt.Errorf("got %d upload URLs for %d parts", len(params.UploadURLs), len(parts))
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrap(err)
+		}
+
+		job.ResultParts = make([]ResultPart, len(parts))
+		job.ResultMediaDuration, job.ResultFileBytes = 0, 0
+		for i, part := range parts {
+			info, err := svc.mediaProcessor.GetInfo(splitCtx, part.Filepath)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return errCtx.With("part", i+1).Wrapf(err, "failed to get info about part")
+			}
+			job.ResultParts[i] = ResultPart{
+				Title:     part.Title,
+				StartTime: part.StartTime,
+				Duration:  info.Duration,
+				FileBytes: info.FileLenBytes,
+				UploadURL: partUploadURL(params.UploadURLs, params.UploadURLTemplate, i, len(parts)),
+			}
+			job.ResultMediaDuration += info.Duration
+			job.ResultFileBytes += info.FileLenBytes
+		}
+		span.SetAttributes(
+			attribute.Int64("result.bytes", job.ResultFileBytes),
+			attribute.Float64("result.duration_seconds", job.ResultMediaDuration.Seconds()),
+		)
+
+		if params.Waveform != nil || params.Previews != nil {
+			updateJobStatus(JobStatusProcessing)
+			partFilepaths := make([]string, len(parts))
+			for i, part := range parts {
+				partFilepaths[i] = part.Filepath
+			}
+			if err := svc.makeArtifacts(jobCtx, jobID, partFilepaths, params.Waveform, params.Previews); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return errCtx.Wrap(err)
+			}
+		}
+
+		updateJobStatus(JobStatusUploading)
+		svc.log.Debug("starting upload", logAttrs...)
+
+		uploadCtx, uploadCancel := context.WithTimeout(jobCtx, 2*time.Hour)
+		defer uploadCancel()
+
+		for i, part := range parts {
+			if err := svc.uploader.Upload(uploadCtx, part.Filepath, job.ResultParts[i].UploadURL); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return errCtx.With("part", i+1).Wrapf(err, "failed to upload part")
+			}
+		}
+
+		updateJobStatus(JobStatusComplete)
+		svc.log.Debug("job complete", logAttrs...)
+		return nil
+	}, nil
+}
+
+func splitOptions(mode string, maxDuration time.Duration, maxBytes int64, atSilence *SplitSilenceParams) (SplitOptions, error) {
+	opts := SplitOptions{Mode: mode, MaxDuration: maxDuration, MaxBytes: maxBytes}
+	switch mode {
+	case SplitByDuration:
+		if maxDuration < minSplitDuration {
+			return opts, fmt.Errorf("maxDuration should be at least %s", minSplitDuration)
+		}
+	case SplitBySize:
+		if maxBytes < minSplitBytes {
+			return opts, fmt.Errorf("maxBytes should be at least %d", minSplitBytes)
+		}
+	case SplitByChapters:
+		if atSilence != nil {
+			return opts, fmt.Errorf("chapters are split as they are, atSilence doesn't apply")
+		}
+	default:
+		return opts, fmt.Errorf("unknown split mode: %q", mode)
+	}
+	if atSilence != nil {
+		opts.Silence = DefaultTrimSilenceOptions.SilenceOptions
+		if err := applySilenceParams(atSilence.NoiseDB, atSilence.MinSilence, &opts.Silence); err != nil {
+			return opts, err
+		}
+		opts.SilenceWindow = DefaultSplitSilenceWindow
+		if atSilence.Window != 0 {
+			opts.SilenceWindow = time.Duration(atSilence.Window)
+		}
+	}
+	return opts, nil
+}
+
+// validatePartUploads checks that parts have somewhere to go: either a list of URLs or a template
+func validatePartUploads(urls []string, template string) error {
+	switch {
+	case len(urls) > 0 && template != "":
+		return fmt.Errorf("either uploadUrls or uploadUrlTemplate should be provided, not both")
+	case len(urls) == 0 && template == "":
+		return fmt.Errorf("no upload URLs provided")
+	case template != "" && !strings.Contains(template, partPlaceholder):
+		return fmt.Errorf("uploadUrlTemplate should contain %s", partPlaceholder)
+	}
+	return nil
+}
+
+// partUploadURL returns the URL of the i-th part. Part numbers in templates are padded,
+// so that parts of a 12-part split are numbered 01 to 12 and keep their order when sorted.
+func partUploadURL(urls []string, template string, i int, total int) string {
+	if template == "" {
+		return urls[i]
+	}
+	width := len(fmt.Sprint(total))
+	return strings.ReplaceAll(template, partPlaceholder, fmt.Sprintf("%0*d", width, i+1))
+}
@@ -0,0 +1,131 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/service"
+	"github.com/dir01/mediary/service/mocks"
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestSplitFlow_UploadsPartsByTemplate(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-split"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  "magnet:?xt=urn:btih:deadbeef",
+			Type: "split",
+			Params: map[string]interface{}{
+				"variant":           "book.m4b",
+				"mode":              "duration",
+				"maxDuration":       "4:00:00",
+				"atSilence":         map[string]interface{}{"window": 60},
+				"uploadUrlTemplate": "http://example.com/upload/book-{part}.m4b",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+
+	dwn.DownloadMock.Return(map[string]string{"book.m4b": "/tmp/dl/book.m4b"}, nil)
+	mp.SplitMock.Set(func(_ context.Context, fp string, opts service.SplitOptions) ([]service.SplitPart, error) {
+		want := service.SplitOptions{
+			Mode:          service.SplitByDuration,
+			MaxDuration:   4 * time.Hour,
+			SilenceWindow: time.Minute,
+			Silence:       service.DefaultTrimSilenceOptions.SilenceOptions,
+		}
+		if fp != "/tmp/dl/book.m4b" || opts != want {
+			t.Errorf("unexpected split of %s with %+v", fp, opts)
+		}
+		return []service.SplitPart{
+			{Filepath: "/tmp/part1.m4b", StartTime: 0, EndTime: 3*time.Hour + 59*time.Minute},
+			{Filepath: "/tmp/part2.m4b", StartTime: 3*time.Hour + 59*time.Minute, EndTime: 6 * time.Hour},
+		}, nil
+	})
+	mp.GetInfoMock.Set(func(_ context.Context, fp string) (*service.MediaInfo, error) {
+		if fp == "/tmp/part1.m4b" {
+			return &service.MediaInfo{Duration: 3*time.Hour + 59*time.Minute, FileLenBytes: 2000}, nil
+		}
+		return &service.MediaInfo{Duration: 2*time.Hour + time.Minute, FileLenBytes: 1000}, nil
+	})
+	uploaded := map[string]string{}
+	upl.UploadMock.Set(func(_ context.Context, fp string, url string) error {
+		uploaded[fp] = url
+		return nil
+	})
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+
+	wantUploads := map[string]string{
+		"/tmp/part1.m4b": "http://example.com/upload/book-1.m4b",
+		"/tmp/part2.m4b": "http://example.com/upload/book-2.m4b",
+	}
+	if !reflect.DeepEqual(uploaded, wantUploads) {
+		t.Errorf("uploaded %v, want %v", uploaded, wantUploads)
+	}
+	if len(job.ResultParts) != 2 || job.ResultParts[1].StartTime != 3*time.Hour+59*time.Minute || job.ResultParts[1].FileBytes != 1000 {
+		t.Errorf("unexpected result parts: %+v", job.ResultParts)
+	}
+	if job.ResultMediaDuration != 6*time.Hour || job.ResultFileBytes != 3000 {
+		t.Errorf("unexpected totals: %v, %d bytes", job.ResultMediaDuration, job.ResultFileBytes)
+	}
+}
+
+func TestSplitFlow_InvalidParams(t *testing.T) {
+	mc := minimock.NewController(t)
+	queue := mocks.NewJobsQueueMock(mc)
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, _ func(context.Context, []byte) error) {})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(mocks.NewDownloaderMock(mc), mocks.NewStorageMock(mc), queue,
+		mocks.NewMediaProcessorMock(mc), mocks.NewUploaderMock(mc), logger)
+	svc.Start()
+	defer svc.Stop()
+
+	for name, params := range map[string]map[string]interface{}{
+		"no mode":        {"variant": "a.mp3", "uploadUrls": []interface{}{"u1"}},
+		"tiny parts":     {"variant": "a.mp3", "mode": "duration", "maxDuration": 5, "uploadUrls": []interface{}{"u1"}},
+		"no size":        {"variant": "a.mp3", "mode": "size", "uploadUrls": []interface{}{"u1"}},
+		"no urls":        {"variant": "a.mp3", "mode": "chapters"},
+		"no part":        {"variant": "a.mp3", "mode": "chapters", "uploadUrlTemplate": "http://example.com/a.mp3"},
+		"chapters pause": {"variant": "a.mp3", "mode": "chapters", "atSilence": map[string]interface{}{}, "uploadUrls": []interface{}{"u1"}},
+		"waveform part": {"variant": "a.mp3", "mode": "chapters", "uploadUrls": []interface{}{"u1"},
+			"waveform": map[string]interface{}{"peaksUploadUrl": "http://example.com/peaks.json"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.CreateJob(context.Background(), &service.JobParams{URL: "http://example.com", Type: "split", Params: params})
+			if err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
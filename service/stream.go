@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrStreamingNotSupported = fmt.Errorf("streaming not supported")
+	ErrVariantNotFound       = fmt.Errorf("variant not found")
+)
+
+// Stream opens a reader over a single variant of the given URL.
+// Unlike jobs, it does not wait for the whole variant to be downloaded,
+// so it can be used to start playback right away.
+// Caller is responsible for closing the returned stream.
+func (svc *Service) Stream(ctx context.Context, url string, variant string) (*VariantStream, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/service").Start(ctx, "service.Stream",
+		trace.WithAttributes(
+			attribute.String("url", url),
+			attribute.String("variant", variant),
+		),
+	)
+	defer span.End()
+
+	errCtx := oops.With("url", url, "variant", variant)
+	svc.log.Debug("opening stream", slog.String("url", url), slog.String("variant", variant))
+
+	if !svc.downloader.AcceptsURL(url) {
+		err := errCtx.Wrapf(ErrUrlNotSupported, "failed to open stream")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	streamer, ok := svc.downloader.(Streamer)
+	if !ok {
+		err := errCtx.Wrapf(ErrStreamingNotSupported, "failed to open stream")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	stream, err := streamer.Stream(ctx, url, variant)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, errCtx.Wrapf(err, "failed to open stream")
+	}
+	span.SetAttributes(attribute.Int64("stream.bytes", stream.Size))
+
+	return stream, nil
+}
@@ -0,0 +1,43 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dir01/mediary/service"
+	"github.com/dir01/mediary/service/mocks"
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestStream_DownloaderWithoutStreamingSupport(t *testing.T) {
+	mc := minimock.NewController(t)
+	dwn := mocks.NewDownloaderMock(mc)
+	dwn.AcceptsURLMock.Return(true)
+
+	svc := service.NewService(
+		dwn, mocks.NewStorageMock(mc), mocks.NewJobsQueueMock(mc),
+		mocks.NewMediaProcessorMock(mc), mocks.NewUploaderMock(mc), logger,
+	)
+
+	_, err := svc.Stream(context.Background(), "https://example.com/video", "Video (mp4)")
+	if !errors.Is(err, service.ErrStreamingNotSupported) {
+		t.Fatalf("expected ErrStreamingNotSupported, got %v", err)
+	}
+}
+
+func TestStream_UnsupportedURL(t *testing.T) {
+	mc := minimock.NewController(t)
+	dwn := mocks.NewDownloaderMock(mc)
+	dwn.AcceptsURLMock.Return(false)
+
+	svc := service.NewService(
+		dwn, mocks.NewStorageMock(mc), mocks.NewJobsQueueMock(mc),
+		mocks.NewMediaProcessorMock(mc), mocks.NewUploaderMock(mc), logger,
+	)
+
+	_, err := svc.Stream(context.Background(), "ftp://example.com/file.mp3", "file.mp3")
+	if !errors.Is(err, service.ErrUrlNotSupported) {
+		t.Fatalf("expected ErrUrlNotSupported, got %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func (svc *Service) newTranscodeFlow(jobID string, job *Job) (func(ctx context.Context) error, error) {
+	logAttrs := []any{
+		slog.String("jobID", jobID),
+		slog.Any("job", job),
+	}
+	errCtx := oops.With("jobID", jobID, "job", job)
+	type Params struct {
+		TranscodeOptions
+		Variant string `json:"variant"`
+		Preset  string `json:"preset"`
+		Tags    Tags   `json:"tags"`
+		// Normalize, when set, evens out loudness of the result
+		Normalize *NormalizeParams `json:"normalize"`
+		// CoverArt is a URL or a variant ID of an image, picked automatically when empty
+		CoverArt  string `json:"coverArt"`
+		UploadURL string `json:"uploadUrl"`
+		// Waveform, when set, makes waveform peaks and images of the result
+		Waveform *WaveformParams `json:"waveform"`
+		// Previews, when set, makes visual previews of the resulting video
+		Previews *PreviewParams `json:"previews"`
+	}
+	params := Params{}
+	err := mapToStruct(job.Params, &params)
+	if err != nil {
+		return nil, errCtx.Wrapf(err, "failed to parse job params")
+	}
+	if err := params.Waveform.validate(false); err != nil {
+		return nil, errCtx.Wrapf(err, "invalid waveform")
+	}
+	if err := params.Previews.validate(false); err != nil {
+		return nil, errCtx.Wrapf(err, "invalid previews")
+	}
+	if params.Variant == "" {
+		return nil, errCtx.Errorf("no variant provided")
+	}
+	opts, err := ResolveTranscodeOptions(params.Preset, params.TranscodeOptions)
+	if err != nil {
+		return nil, errCtx.Wrapf(err, "invalid transcoding options")
+	}
+	var normalizeOpts NormalizeOptions
+	if params.Normalize != nil {
+		if normalizeOpts, err = params.Normalize.Options(); err != nil {
+			return nil, errCtx.Wrapf(err, "invalid normalization")
+		}
+	}
+	logAttrs = append(logAttrs, slog.Any("params", params), slog.Any("opts", opts))
+	errCtx = errCtx.With("params", params, "opts", opts)
+	svc.log.Debug("parsed job params", logAttrs...)
+
+	return func(jobCtx context.Context) error {
+		jobCtx, span := otel.Tracer("github.com/dir01/mediary/service").Start(jobCtx, "service.TranscodeFlow",
+			trace.WithAttributes(
+				attribute.String("job.id", jobID),
+				attribute.String("variant", params.Variant),
+				attribute.String("container", opts.Container),
+				attribute.String("audio_codec", opts.AudioCodec),
+			),
+		)
+		defer span.End()
+
+		ctx, cancel := context.WithTimeout(jobCtx, 10*time.Second)
+		defer cancel()
+		job, err := svc.storage.GetJob(ctx, jobID)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrapf(err, "failed to get job")
+		}
+
+		updateJobStatus := func(status string) {
+			statusCtx, statusCancel := context.WithTimeout(jobCtx, 10*time.Second)
+			defer statusCancel()
+			job.DisplayStatus = status
+			if err = svc.storage.SaveJob(statusCtx, job); err != nil {
+				attrs := append([]any{
+					slog.String("state", job.DisplayStatus),
+					slog.Any("error", err),
+				}, logAttrs...)
+				svc.log.Error("failed to save job state, proceeding", attrs...)
+			}
+		}
+
+		updateJobStatus(JobStatusDownloading)
+		svc.log.Debug("starting download", logAttrs...)
+
+		downloadCtx, downloadCancel := context.WithTimeout(jobCtx, 1*time.Hour)
+		defer downloadCancel()
+
+		downloadCtx, downloadSpan := otel.Tracer("github.com/dir01/mediary/service").Start(downloadCtx, "service.Download",
+			trace.WithAttributes(
+				attribute.String("job.id", jobID),
+				attribute.String("url", job.URL),
+				attribute.String("variant", params.Variant),
+			),
+		)
+		downloader, err := svc.selectDownloader(job.Downloader)
+		if err != nil {
+			downloadSpan.RecordError(err)
+			downloadSpan.SetStatus(codes.Error, err.Error())
+			downloadSpan.End()
+			return errCtx.Wrapf(err, "failed to select downloader")
+		}
+		filepathsMap, err := svc.download(downloadCtx, downloader, job.Downloader, job.URL, []string{params.Variant})
+		if err != nil {
+			downloadSpan.RecordError(err)
+			downloadSpan.SetStatus(codes.Error, err.Error())
+			downloadSpan.End()
+			return errCtx.Wrapf(err, "failed to download files")
+		}
+		downloadSpan.End()
+
+		downloadedFilepath := filepathsMap[params.Variant]
+		// tracks of a CUE sheet are tagged from it, unless tags are set explicitly
+		tags := params.Tags.WithDefaults(svc.cueTags(downloadCtx, job.URL, job.Downloader, []string{params.Variant}))
+		logAttrs = append(logAttrs, slog.String("downloadedFilepath", downloadedFilepath))
+		errCtx = errCtx.With("downloadedFilepath", downloadedFilepath)
+
+		updateJobStatus(JobStatusProcessing)
+		svc.log.Debug("starting transcoding", logAttrs...)
+
+		transcodeCtx, transcodeCancel := context.WithTimeout(jobCtx, 1*time.Hour)
+		defer transcodeCancel()
+
+		resultFilepath, err := svc.mediaProcessor.Transcode(transcodeCtx, downloadedFilepath, opts)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrapf(err, "failed to transcode")
+		}
+
+		// the result is normalized rather than the source, since the source container might be anything
+		if params.Normalize != nil {
+			var stats *LoudnessStats
+			resultFilepath, stats, err = svc.normalize(jobCtx, resultFilepath, "", normalizeOpts)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return errCtx.Wrap(err)
+			}
+			job.ResultLoudness = append(job.ResultLoudness, *stats)
+		}
+
+		// transcoding drops cover art from audio-only containers, so it is put back, along with the tags
+		coverArt, err := svc.resolveCoverArt(transcodeCtx, downloader, job.Downloader, job.URL, params.CoverArt, []string{params.Variant}, []string{downloadedFilepath})
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrapf(err, "failed to get cover art")
+		}
+		if !tags.IsEmpty() || coverArt != "" {
+			resultFilepath, err = svc.mediaProcessor.WriteMetadata(transcodeCtx, resultFilepath, FileMetadata{
+				Tags:             tags,
+				CoverArtFilepath: coverArt,
+			})
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return errCtx.Wrapf(err, "failed to write tags")
+			}
+		}
+		logAttrs = append(logAttrs, slog.String("resultFilepath", resultFilepath))
+		errCtx = errCtx.With("resultFilepath", resultFilepath)
+
+		info, err := svc.mediaProcessor.GetInfo(transcodeCtx, resultFilepath)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrapf(err, "failed to get info about result file")
+		}
+		job.ResultMediaDuration = info.Duration
+		job.ResultFileBytes = info.FileLenBytes
+		job.ResultMediaInfo = info
+		span.SetAttributes(
+			attribute.Int64("result.bytes", info.FileLenBytes),
+			attribute.Float64("result.duration_seconds", info.Duration.Seconds()),
+		)
+
+		if params.Waveform != nil || params.Previews != nil {
+			updateJobStatus(JobStatusProcessing)
+			if err := svc.makeArtifacts(jobCtx, jobID, []string{resultFilepath}, params.Waveform, params.Previews); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return errCtx.Wrap(err)
+			}
+		}
+
+		updateJobStatus(JobStatusUploading)
+		svc.log.Debug("starting upload", logAttrs...)
+
+		uploadCtx, uploadCancel := context.WithTimeout(jobCtx, 2*time.Hour)
+		defer uploadCancel()
+
+		err = svc.uploader.Upload(uploadCtx, resultFilepath, params.UploadURL)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return errCtx.Wrapf(err, "failed to upload result")
+		}
+
+		updateJobStatus(JobStatusComplete)
+		svc.log.Debug("job complete", logAttrs...)
+		return nil
+	}, nil
+}
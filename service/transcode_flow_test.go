@@ -0,0 +1,166 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/service"
+	"github.com/dir01/mediary/service/mocks"
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestTranscodeFlow_PresetWithOverrides(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	mp.ExtractCoverArtMock.Optional().Return("", errors.New("no cover art"))
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-transcode"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  "http://example.com/audio",
+			Type: "transcode",
+			Params: map[string]interface{}{
+				"variant":   "lecture.wav",
+				"preset":    "podcast-mono-64k",
+				"bitrate":   "96k",
+				"uploadUrl": "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+	storage.GetMetadataMock.Optional().Return(nil, nil)
+
+	dwn.DownloadMock.Return(map[string]string{"lecture.wav": "/tmp/dl/lecture.wav"}, nil)
+
+	resultPath := "/tmp/result/lecture.mp3"
+	mp.TranscodeMock.Set(func(_ context.Context, fp string, opts service.TranscodeOptions) (string, error) {
+		if fp != "/tmp/dl/lecture.wav" {
+			t.Errorf("unexpected input %s", fp)
+		}
+		want := service.TranscodeOptions{Container: "mp3", AudioCodec: "libmp3lame", Bitrate: "96k", SampleRate: 44100, Channels: 1}
+		if opts != want {
+			t.Errorf("opts = %+v, want %+v", opts, want)
+		}
+		return resultPath, nil
+	})
+	mp.GetInfoMock.Return(&service.MediaInfo{Duration: time.Minute, FileLenBytes: 480000}, nil)
+	upl.UploadMock.Set(func(_ context.Context, fp string, url string) error {
+		if fp != resultPath {
+			t.Errorf("uploaded %s instead of transcoded file", fp)
+		}
+		return nil
+	})
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+	if job.DisplayStatus != service.JobStatusComplete || job.ResultFileBytes != 480000 {
+		t.Errorf("unexpected job state: %+v", job)
+	}
+}
+
+func TestTranscodeFlow_TagsAndCoverArtVariant(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-transcode-tags"
+	url := "magnet:?xt=urn:btih:deadbeef"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  url,
+			Type: "transcode",
+			Params: map[string]interface{}{
+				"variant":   "Album/CD1/01.flac",
+				"container": "mp3",
+				"tags":      map[string]interface{}{"title": "Song", "track": "1"},
+				"uploadUrl": "http://example.com/upload",
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+	storage.GetMetadataMock.Return(&service.Metadata{
+		URL: url,
+		Variants: []service.VariantMetadata{
+			{ID: "Album/CD1/01.flac"},
+			{ID: "Album/folder.jpg"},
+			{ID: "Album/CD1/Folder.jpg"},
+			{ID: "Album/CD2/cover.jpg"},
+		},
+	}, nil)
+
+	dwn.DownloadMock.Set(func(_ context.Context, _ string, variants []string) (map[string]string, error) {
+		return map[string]string{variants[0]: "/tmp/dl/" + variants[0]}, nil
+	})
+	mp.TranscodeMock.Return("/tmp/result/01.mp3", nil)
+	mp.ExtractCoverArtMock.Return("", errors.New("no cover art"))
+
+	var written service.FileMetadata
+	mp.WriteMetadataMock.Set(func(_ context.Context, fp string, metadata service.FileMetadata) (string, error) {
+		if fp != "/tmp/result/01.mp3" {
+			t.Errorf("metadata written into %s", fp)
+		}
+		written = metadata
+		return "/tmp/result/01.tagged.mp3", nil
+	})
+	mp.GetInfoMock.Return(&service.MediaInfo{Duration: time.Minute, FileLenBytes: 480000}, nil)
+	upl.UploadMock.Set(func(_ context.Context, fp string, url string) error {
+		if fp != "/tmp/result/01.tagged.mp3" {
+			t.Errorf("uploaded %s instead of tagged file", fp)
+		}
+		return nil
+	})
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+	if written.Tags != (service.Tags{Title: "Song", Track: "1"}) {
+		t.Errorf("unexpected tags: %+v", written.Tags)
+	}
+	// folder.jpg next to the track wins over cover.jpg from another directory
+	if written.CoverArtFilepath != "/tmp/dl/Album/CD1/Folder.jpg" {
+		t.Errorf("unexpected cover art: %s", written.CoverArtFilepath)
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Waveform image kinds
+const (
+	WaveformImageWaveform    = "waveform"
+	WaveformImageSpectrogram = "spectrogram"
+)
+
+const (
+	// DefaultPixelsPerSecond is enough for an overview of an hour-long file, and keeps its peaks under a megabyte
+	DefaultPixelsPerSecond = 20
+	MaxPixelsPerSecond     = 1000
+	// MaxWaveformPixels bounds peaks of long media, which get fewer pixels per second than requested,
+	// so that their peaks stay under a few dozen megabytes: that's over half an hour at MaxPixelsPerSecond
+	MaxWaveformPixels    = 1 << 21
+	maxWaveformImageSide = 8192
+)
+
+// waveformColorRe matches color names and hex colors, nothing that would break out of a filtergraph
+var waveformColorRe = regexp.MustCompile(`^([a-zA-Z]+|(0x|#)[0-9a-fA-F]{6}([0-9a-fA-F]{2})?)$`)
+
+// WaveformParams is the "waveform" job param, any job takes it to get waveform artifacts of its results.
+// Peaks are uploaded to PeaksUploadURL, or stored for retrieval with Service.GetWaveform when it is empty.
+// For jobs with several results, upload URLs are templates with a {part} placeholder, like uploadUrlTemplate.
+type WaveformParams struct {
+	PixelsPerSecond int `json:"pixelsPerSecond"`
+	// Bits is the resolution of peak values, either 8 (the default) or 16
+	Bits           int    `json:"bits"`
+	PeaksUploadURL string `json:"peaksUploadUrl"`
+	// Image, when set, renders a PNG of the given kind, either "waveform" or "spectrogram", to ImageUploadURL
+	Image  string `json:"image"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	// Color of the waveform in ffmpeg's syntax, like "0x3b82f6"
+	Color          string `json:"color"`
+	ImageUploadURL string `json:"imageUploadUrl"`
+}
+
+// Options validates the params, image options are nil when no image is requested
+func (p *WaveformParams) Options() (WaveformOptions, *WaveformImageOptions, error) {
+	opts := WaveformOptions{PixelsPerSecond: p.PixelsPerSecond, Bits: p.Bits}
+	if opts.PixelsPerSecond == 0 {
+		opts.PixelsPerSecond = DefaultPixelsPerSecond
+	}
+	if opts.PixelsPerSecond < 1 || opts.PixelsPerSecond > MaxPixelsPerSecond {
+		return opts, nil, fmt.Errorf("pixelsPerSecond should be within [1, %d]", MaxPixelsPerSecond)
+	}
+	if opts.Bits == 0 {
+		opts.Bits = 8
+	}
+	if opts.Bits != 8 && opts.Bits != 16 {
+		return opts, nil, fmt.Errorf("bits should be either 8 or 16, got %d", opts.Bits)
+	}
+	if p.Image == "" {
+		if p.ImageUploadURL != "" || p.Width != 0 || p.Height != 0 || p.Color != "" {
+			return opts, nil, fmt.Errorf("image options are given, but no image is requested")
+		}
+		return opts, nil, nil
+	}
+
+	imageOpts := &WaveformImageOptions{Kind: p.Image, Width: p.Width, Height: p.Height, Color: p.Color}
+	switch p.Image {
+	case WaveformImageWaveform:
+		imageOpts.Width, imageOpts.Height = cmp.Or(p.Width, 1800), cmp.Or(p.Height, 280)
+	case WaveformImageSpectrogram:
+		imageOpts.Width, imageOpts.Height = cmp.Or(p.Width, 1024), cmp.Or(p.Height, 512)
+	default:
+		return opts, nil, fmt.Errorf("unknown waveform image: %s", p.Image)
+	}
+	if imageOpts.Width < 1 || imageOpts.Height < 1 || imageOpts.Width > maxWaveformImageSide || imageOpts.Height > maxWaveformImageSide {
+		return opts, nil, fmt.Errorf("image size should be within [1, %d], got %dx%d",
+			maxWaveformImageSide, imageOpts.Width, imageOpts.Height)
+	}
+	if p.Color != "" && !waveformColorRe.MatchString(p.Color) {
+		return opts, nil, fmt.Errorf("invalid color: %q", p.Color)
+	}
+	if p.ImageUploadURL == "" {
+		return opts, nil, fmt.Errorf("no image upload URL provided")
+	}
+	return opts, imageOpts, nil
+}
+
+// validate checks waveform params of a job, which may have several results when multipart is set.
+// Several results shouldn't overwrite each other's artifacts. Nil params are valid.
+func (p *WaveformParams) validate(multipart bool) error {
+	if p == nil {
+		return nil
+	}
+	if _, _, err := p.Options(); err != nil {
+		return err
+	}
+	if !multipart {
+		return nil
+	}
+	for name, url := range map[string]string{"peaksUploadUrl": p.PeaksUploadURL, "imageUploadUrl": p.ImageUploadURL} {
+		if url != "" && !strings.Contains(url, partPlaceholder) {
+			return fmt.Errorf("%s should contain %s, since there are several results", name, partPlaceholder)
+		}
+	}
+	return nil
+}
+
+// makeWaveform computes peaks of the part-th of total results of the job, and renders its image when requested.
+// Peaks are uploaded or stored, the image is uploaded.
+func (svc *Service) makeWaveform(ctx context.Context, jobID string, fp string, part int, total int, params *WaveformParams) error {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/service").Start(ctx, "service.MakeWaveform",
+		trace.WithAttributes(
+			attribute.String("job.id", jobID),
+			attribute.Int("part", part),
+			attribute.String("image", params.Image),
+		),
+	)
+	defer span.End()
+
+	errCtx := oops.With("jobID", jobID, "filepath", fp, "part", part)
+	fail := func(err error) error {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	opts, imageOpts, err := params.Options()
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "invalid waveform"))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Hour)
+	defer cancel()
+
+	peaks, err := svc.mediaProcessor.ComputePeaks(ctx, fp, opts)
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to compute peaks"))
+	}
+	span.SetAttributes(attribute.Int("peaks.length", peaks.Length))
+
+	if params.PeaksUploadURL == "" {
+		if err := svc.storage.SaveWaveform(ctx, jobID, part, peaks); err != nil {
+			return fail(errCtx.Wrapf(err, "failed to save peaks"))
+		}
+	} else if err := svc.uploadPeaks(ctx, peaks, partUploadURL(nil, params.PeaksUploadURL, part, total)); err != nil {
+		return fail(errCtx.Wrap(err))
+	}
+
+	if imageOpts == nil {
+		return nil
+	}
+	imageFilepath, err := svc.mediaProcessor.RenderWaveform(ctx, fp, *imageOpts)
+	if err != nil {
+		return fail(errCtx.Wrapf(err, "failed to render waveform image"))
+	}
+	defer func() { _ = os.Remove(imageFilepath) }()
+	if err := svc.uploader.Upload(ctx, imageFilepath, partUploadURL(nil, params.ImageUploadURL, part, total)); err != nil {
+		return fail(errCtx.Wrapf(err, "failed to upload waveform image"))
+	}
+	return nil
+}
+
+func (svc *Service) uploadPeaks(ctx context.Context, peaks *WaveformPeaks, url string) error {
+	file, err := os.CreateTemp("", "*-peaks.json")
+	if err != nil {
+		return oops.Wrapf(err, "failed to create temp file")
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+	if err := json.NewEncoder(file).Encode(peaks); err != nil {
+		_ = file.Close()
+		return oops.Wrapf(err, "failed to write peaks")
+	}
+	if err := file.Close(); err != nil {
+		return oops.Wrapf(err, "failed to write peaks")
+	}
+	if err := svc.uploader.Upload(ctx, file.Name(), url); err != nil {
+		return oops.Wrapf(err, "failed to upload peaks")
+	}
+	return nil
+}
+
+// GetWaveform returns stored peaks of the part-th result of the job, from zero; nil if there are none
+func (svc *Service) GetWaveform(ctx context.Context, jobID string, part int) (*WaveformPeaks, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/service").Start(ctx, "service.GetWaveform",
+		trace.WithAttributes(attribute.String("job.id", jobID), attribute.Int("part", part)),
+	)
+	defer span.End()
+
+	peaks, err := svc.storage.GetWaveform(ctx, jobID, part)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return peaks, err
+}
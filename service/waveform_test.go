@@ -0,0 +1,153 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/service"
+	"github.com/dir01/mediary/service/mocks"
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestWaveformParams_Options(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		params    service.WaveformParams
+		want      service.WaveformOptions
+		wantImage *service.WaveformImageOptions
+		wantErr   bool
+	}{
+		{
+			name: "defaults",
+			want: service.WaveformOptions{PixelsPerSecond: service.DefaultPixelsPerSecond, Bits: 8},
+		},
+		{
+			name:      "spectrogram of default size",
+			params:    service.WaveformParams{PixelsPerSecond: 100, Bits: 16, Image: "spectrogram", ImageUploadURL: "http://example.com/s.png"},
+			want:      service.WaveformOptions{PixelsPerSecond: 100, Bits: 16},
+			wantImage: &service.WaveformImageOptions{Kind: "spectrogram", Width: 1024, Height: 512},
+		},
+		{
+			name:      "colored waveform",
+			params:    service.WaveformParams{Image: "waveform", Height: 100, Color: "#3b82f6", ImageUploadURL: "http://example.com/w.png"},
+			want:      service.WaveformOptions{PixelsPerSecond: service.DefaultPixelsPerSecond, Bits: 8},
+			wantImage: &service.WaveformImageOptions{Kind: "waveform", Width: 1800, Height: 100, Color: "#3b82f6"},
+		},
+		{name: "too detailed", params: service.WaveformParams{PixelsPerSecond: 5000}, wantErr: true},
+		{name: "24 bits", params: service.WaveformParams{Bits: 24}, wantErr: true},
+		{name: "unknown image", params: service.WaveformParams{Image: "sonogram", ImageUploadURL: "u"}, wantErr: true},
+		{name: "image without url", params: service.WaveformParams{Image: "waveform"}, wantErr: true},
+		{name: "url without image", params: service.WaveformParams{ImageUploadURL: "u"}, wantErr: true},
+		{name: "filter in color", params: service.WaveformParams{Image: "waveform", Color: "red,drawtext", ImageUploadURL: "u"}, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, gotImage, err := tc.params.Options()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Options() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if got != tc.want || !reflect.DeepEqual(gotImage, tc.wantImage) {
+				t.Errorf("got %+v, %+v; want %+v, %+v", got, gotImage, tc.want, tc.wantImage)
+			}
+		})
+	}
+}
+
+func TestSplitFlow_WaveformsOfParts(t *testing.T) {
+	mc := minimock.NewController(t)
+
+	storage := mocks.NewStorageMock(mc)
+	queue := mocks.NewJobsQueueMock(mc)
+	dwn := mocks.NewDownloaderMock(mc)
+	mp := mocks.NewMediaProcessorMock(mc)
+	upl := mocks.NewUploaderMock(mc)
+
+	var onJob func(ctx context.Context, payloadBytes []byte) error
+	queue.SubscribeMock.Set(func(_ context.Context, _ string, f func(context.Context, []byte) error) {
+		onJob = f
+	})
+	queue.RunMock.Set(func() {})
+	queue.ShutdownMock.Set(func() {})
+
+	svc := service.NewService(dwn, storage, queue, mp, upl, logger)
+	svc.Start()
+	defer svc.Stop()
+
+	jobID := "test-job-split-waveforms"
+	job := &service.Job{
+		JobParams: service.JobParams{
+			URL:  "magnet:?xt=urn:btih:deadbeef",
+			Type: "split",
+			Params: map[string]interface{}{
+				"variant":           "book.mp3",
+				"mode":              "chapters",
+				"uploadUrlTemplate": "http://example.com/upload/book-{part}.mp3",
+				"waveform": map[string]interface{}{
+					"pixelsPerSecond": 10,
+					"image":           "waveform",
+					"imageUploadUrl":  "http://example.com/upload/book-{part}.png",
+				},
+			},
+		},
+		ID:            jobID,
+		DisplayStatus: "created",
+	}
+	storage.GetJobMock.Return(job, nil)
+	storage.SaveJobMock.Return(nil)
+
+	dwn.DownloadMock.Return(map[string]string{"book.mp3": "/tmp/dl/book.mp3"}, nil)
+	mp.SplitMock.Return([]service.SplitPart{
+		{Filepath: "/tmp/part1.mp3", StartTime: 0, EndTime: time.Hour},
+		{Filepath: "/tmp/part2.mp3", StartTime: time.Hour, EndTime: 2 * time.Hour},
+	}, nil)
+	mp.GetInfoMock.Return(&service.MediaInfo{Duration: time.Hour, FileLenBytes: 1000}, nil)
+	peaksOf := map[string]*service.WaveformPeaks{
+		"/tmp/part1.mp3": {Version: 2, Channels: 1, SampleRate: 44100, SamplesPerPixel: 4410, Bits: 8, Length: 1, Data: []int16{-3, 5}},
+		"/tmp/part2.mp3": {Version: 2, Channels: 1, SampleRate: 44100, SamplesPerPixel: 4410, Bits: 8, Length: 1, Data: []int16{-7, 9}},
+	}
+	mp.ComputePeaksMock.Set(func(_ context.Context, fp string, opts service.WaveformOptions) (*service.WaveformPeaks, error) {
+		if opts != (service.WaveformOptions{PixelsPerSecond: 10, Bits: 8}) {
+			t.Errorf("unexpected peaks options: %+v", opts)
+		}
+		return peaksOf[fp], nil
+	})
+	mp.RenderWaveformMock.Set(func(_ context.Context, fp string, opts service.WaveformImageOptions) (string, error) {
+		return fp + ".png", nil
+	})
+	stored := map[int]*service.WaveformPeaks{}
+	storage.SaveWaveformMock.Set(func(_ context.Context, id string, part int, peaks *service.WaveformPeaks) error {
+		if id != jobID {
+			t.Errorf("peaks saved for job %s", id)
+		}
+		stored[part] = peaks
+		return nil
+	})
+	uploaded := map[string]string{}
+	upl.UploadMock.Set(func(_ context.Context, fp string, url string) error {
+		uploaded[fp] = url
+		return nil
+	})
+
+	payload, _ := json.Marshal(jobID)
+	if err := onJob(context.Background(), payload); err != nil {
+		t.Fatalf("onJob failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(stored, map[int]*service.WaveformPeaks{0: peaksOf["/tmp/part1.mp3"], 1: peaksOf["/tmp/part2.mp3"]}) {
+		t.Errorf("unexpected stored peaks: %v", stored)
+	}
+	wantUploads := map[string]string{
+		"/tmp/part1.mp3":     "http://example.com/upload/book-1.mp3",
+		"/tmp/part2.mp3":     "http://example.com/upload/book-2.mp3",
+		"/tmp/part1.mp3.png": "http://example.com/upload/book-1.png",
+		"/tmp/part2.mp3.png": "http://example.com/upload/book-2.png",
+	}
+	if !reflect.DeepEqual(uploaded, wantUploads) {
+		t.Errorf("uploaded %v, want %v", uploaded, wantUploads)
+	}
+}
@@ -9,16 +9,29 @@ import (
 
 func NewMemoryStorage() service.Storage {
 	return &MemoryStorage{
-		metadataMap: make(map[string]service.Metadata),
+		metadataMap: make(map[metadataKey]service.Metadata),
 		jobMap:      make(map[string]service.Job),
+		waveformMap: make(map[waveformKey]service.WaveformPeaks),
 	}
 }
 
 type MemoryStorage struct {
-	metadataMap   map[string]service.Metadata
+	metadataMap   map[metadataKey]service.Metadata
 	metadataMutex sync.RWMutex
 	jobMap        map[string]service.Job
 	jobMutex      sync.RWMutex
+	waveformMap   map[waveformKey]service.WaveformPeaks
+	waveformMutex sync.RWMutex
+}
+
+type metadataKey struct {
+	url        string
+	downloader string
+}
+
+type waveformKey struct {
+	jobID string
+	part  int
 }
 
 func (s *MemoryStorage) GetJob(ctx context.Context, id string) (*service.Job, error) {
@@ -41,23 +54,42 @@ func (s *MemoryStorage) SaveJob(ctx context.Context, job *service.Job) error {
 	return nil
 }
 
-func (s *MemoryStorage) GetMetadata(ctx context.Context, url string) (*service.Metadata, error) {
+func (s *MemoryStorage) GetMetadata(ctx context.Context, url string, downloader string) (*service.Metadata, error) {
 	s.metadataMutex.RLock()
 	defer s.metadataMutex.RUnlock()
 
-	if md, exists := s.metadataMap[url]; exists {
+	if md, exists := s.metadataMap[metadataKey{url: url, downloader: downloader}]; exists {
 		return &md, nil
 	} else {
 		return nil, nil
 	}
 }
 
-func (s *MemoryStorage) SaveMetadata(ctx context.Context, metadata *service.Metadata) error {
+func (s *MemoryStorage) SaveMetadata(ctx context.Context, downloader string, metadata *service.Metadata) error {
 	if metadata == nil {
 		return nil
 	}
 	s.metadataMutex.Lock()
 	defer s.metadataMutex.Unlock()
-	s.metadataMap[metadata.URL] = *metadata
+	s.metadataMap[metadataKey{url: metadata.URL, downloader: downloader}] = *metadata
+	return nil
+}
+
+func (s *MemoryStorage) GetWaveform(ctx context.Context, jobID string, part int) (*service.WaveformPeaks, error) {
+	s.waveformMutex.RLock()
+	defer s.waveformMutex.RUnlock()
+	if peaks, exists := s.waveformMap[waveformKey{jobID, part}]; exists {
+		return &peaks, nil
+	}
+	return nil, nil
+}
+
+func (s *MemoryStorage) SaveWaveform(ctx context.Context, jobID string, part int, peaks *service.WaveformPeaks) error {
+	if peaks == nil {
+		return nil
+	}
+	s.waveformMutex.Lock()
+	defer s.waveformMutex.Unlock()
+	s.waveformMap[waveformKey{jobID, part}] = *peaks
 	return nil
 }
@@ -32,9 +32,44 @@ func (s *SQLiteStorage) initSchema() error {
 			data BLOB NOT NULL
 		);
 		CREATE TABLE IF NOT EXISTS mediary_metadata (
-			url  TEXT PRIMARY KEY,
-			data BLOB NOT NULL
+			url        TEXT NOT NULL,
+			downloader TEXT NOT NULL DEFAULT '',
+			data       BLOB NOT NULL,
+			PRIMARY KEY (url, downloader)
+		);
+		CREATE TABLE IF NOT EXISTS mediary_waveforms (
+			job_id TEXT    NOT NULL,
+			part   INTEGER NOT NULL,
+			data   BLOB    NOT NULL,
+			PRIMARY KEY (job_id, part)
+		);
+	`)
+	if err != nil {
+		return err
+	}
+	return s.migrateMetadata()
+}
+
+// migrateMetadata rebuilds the metadata table of older databases, which was keyed by URL alone,
+// keeping what is there as fetched by the default downloader
+func (s *SQLiteStorage) migrateMetadata() error {
+	var keyed int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('mediary_metadata') WHERE name = 'downloader'`).Scan(&keyed)
+	if err != nil || keyed > 0 {
+		return err
+	}
+	_, err = s.db.Exec(`
+		BEGIN;
+		ALTER TABLE mediary_metadata RENAME TO mediary_metadata_old;
+		CREATE TABLE mediary_metadata (
+			url        TEXT NOT NULL,
+			downloader TEXT NOT NULL DEFAULT '',
+			data       BLOB NOT NULL,
+			PRIMARY KEY (url, downloader)
 		);
+		INSERT INTO mediary_metadata (url, data) SELECT url, data FROM mediary_metadata_old;
+		DROP TABLE mediary_metadata_old;
+		COMMIT;
 	`)
 	return err
 }
@@ -89,14 +124,14 @@ func (s *SQLiteStorage) SaveJob(ctx context.Context, job *service.Job) error {
 	return nil
 }
 
-func (s *SQLiteStorage) GetMetadata(ctx context.Context, url string) (*service.Metadata, error) {
+func (s *SQLiteStorage) GetMetadata(ctx context.Context, url string, downloader string) (*service.Metadata, error) {
 	ctx, span := otel.Tracer("github.com/dir01/mediary/storage").Start(ctx, "storage.GetMetadata",
-		trace.WithAttributes(attribute.String("url", url)),
+		trace.WithAttributes(attribute.String("url", url), attribute.String("downloader", downloader)),
 	)
 	defer span.End()
 
 	var data []byte
-	err := s.db.QueryRowContext(ctx, `SELECT data FROM mediary_metadata WHERE url = ?`, url).Scan(&data)
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM mediary_metadata WHERE url = ? AND downloader = ?`, url, downloader).Scan(&data)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -114,9 +149,9 @@ func (s *SQLiteStorage) GetMetadata(ctx context.Context, url string) (*service.M
 	return meta, nil
 }
 
-func (s *SQLiteStorage) SaveMetadata(ctx context.Context, metadata *service.Metadata) error {
+func (s *SQLiteStorage) SaveMetadata(ctx context.Context, downloader string, metadata *service.Metadata) error {
 	ctx, span := otel.Tracer("github.com/dir01/mediary/storage").Start(ctx, "storage.SaveMetadata",
-		trace.WithAttributes(attribute.String("url", metadata.URL)),
+		trace.WithAttributes(attribute.String("url", metadata.URL), attribute.String("downloader", downloader)),
 	)
 	defer span.End()
 
@@ -126,7 +161,54 @@ func (s *SQLiteStorage) SaveMetadata(ctx context.Context, metadata *service.Meta
 		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
-	_, err = s.db.ExecContext(ctx, `INSERT OR REPLACE INTO mediary_metadata (url, data) VALUES (?, ?)`, metadata.URL, data)
+	_, err = s.db.ExecContext(ctx, `INSERT OR REPLACE INTO mediary_metadata (url, downloader, data) VALUES (?, ?, ?)`,
+		metadata.URL, downloader, data)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) GetWaveform(ctx context.Context, jobID string, part int) (*service.WaveformPeaks, error) {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/storage").Start(ctx, "storage.GetWaveform",
+		trace.WithAttributes(attribute.String("job.id", jobID), attribute.Int("part", part)),
+	)
+	defer span.End()
+
+	var data []byte
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM mediary_waveforms WHERE job_id = ? AND part = ?`, jobID, part).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	peaks := &service.WaveformPeaks{}
+	if err := json.Unmarshal(data, peaks); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return peaks, nil
+}
+
+func (s *SQLiteStorage) SaveWaveform(ctx context.Context, jobID string, part int, peaks *service.WaveformPeaks) error {
+	ctx, span := otel.Tracer("github.com/dir01/mediary/storage").Start(ctx, "storage.SaveWaveform",
+		trace.WithAttributes(attribute.String("job.id", jobID), attribute.Int("part", part)),
+	)
+	defer span.End()
+
+	data, err := json.Marshal(peaks)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `INSERT OR REPLACE INTO mediary_waveforms (job_id, part, data) VALUES (?, ?, ?)`, jobID, part, data)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
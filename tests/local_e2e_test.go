@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dir01/mediary/downloader"
+	"github.com/dir01/mediary/downloader/local"
+	"github.com/dir01/mediary/media_processor"
+	"github.com/dir01/mediary/service"
+	jobsqueue "github.com/dir01/mediary/service/jobs_queue"
+	"github.com/dir01/mediary/storage"
+	"github.com/dir01/mediary/uploader"
+)
+
+// TestConcatenateLocalFiles runs the whole concatenate flow against files on local filesystem:
+// no torrent seeder, no S3, no network.
+func TestConcatenateLocalFiles(t *testing.T) {
+	quietLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	root := t.TempDir()
+	mp3Data := MakeMinimalMP3(t)
+	for _, name := range []string{"01.mp3", "02.mp3"} {
+		if err := os.WriteFile(filepath.Join(root, name), mp3Data, 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	var uploadedMu sync.Mutex
+	var uploaded []byte
+	uploadServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		uploadedMu.Lock()
+		uploaded = body
+		uploadedMu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer uploadServer.Close()
+
+	localDwn, err := local.New([]string{root}, quietLogger)
+	if err != nil {
+		t.Fatalf("error creating local downloader: %v", err)
+	}
+	dwn := downloader.NewCompositeDownloader([]service.Downloader{localDwn})
+
+	db, err := storage.OpenSQLiteDB(filepath.Join(t.TempDir(), "local_e2e.db"))
+	if err != nil {
+		t.Fatalf("error opening sqlite db: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	queue, err := jobsqueue.NewSQLJobsQueue(db, quietLogger)
+	if err != nil {
+		t.Fatalf("error initializing sql jobs queue: %v", err)
+	}
+	mediaProcessor, err := media_processor.NewFFMpegMediaProcessor(quietLogger)
+	if err != nil {
+		t.Fatalf("error creating media processor: %v", err)
+	}
+	upl, err := uploader.New()
+	if err != nil {
+		t.Fatalf("error creating uploader: %v", err)
+	}
+
+	svc := service.NewService(dwn, storage.NewMemoryStorage(), queue, mediaProcessor, upl, quietLogger)
+	svc.Start()
+	defer svc.Stop()
+
+	url := "file://" + filepath.ToSlash(root)
+	metadata, err := svc.GetMetadata(context.Background(), service.MetadataRequest{URL: url})
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if len(metadata.Variants) != 2 {
+		t.Fatalf("expected 2 variants, got %+v", metadata.Variants)
+	}
+
+	job, err := svc.CreateJob(context.Background(), &service.JobParams{
+		URL:  url,
+		Type: "concatenate",
+		Params: map[string]interface{}{
+			"variants":   []interface{}{"01.mp3", "02.mp3"},
+			"audioCodec": "copy",
+			"uploadUrl":  uploadServer.URL + "/result.mp3",
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateJob failed: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Minute)
+	for {
+		job, err = svc.GetJob(context.Background(), job.ID)
+		if err != nil {
+			t.Fatalf("GetJob failed: %v", err)
+		}
+		if job.DisplayStatus == service.JobStatusComplete {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job did not complete in time, last status: %s", job.DisplayStatus)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	uploadedMu.Lock()
+	defer uploadedMu.Unlock()
+	if int64(len(uploaded)) != job.ResultFileBytes || len(uploaded) == 0 {
+		t.Errorf("uploaded %d bytes, job reports %d", len(uploaded), job.ResultFileBytes)
+	}
+	if job.ResultMediaDuration <= 0 {
+		t.Errorf("expected positive result duration, got %v", job.ResultMediaDuration)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path"
+	"strings"
 
 	"github.com/dir01/mediary/service"
 	"go.opentelemetry.io/otel"
@@ -14,6 +16,14 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// artifactContentTypes are content types of what jobs upload besides media: waveform images and peaks, previews
+var artifactContentTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".json": "application/json",
+}
+
 func New() (*HTTPUploader, error) {
 	uploader := &HTTPUploader{}
 	var _ service.Uploader = uploader
@@ -52,6 +62,10 @@ func (u *HTTPUploader) Upload(ctx context.Context, filepath string, url string)
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 	req.ContentLength = fileStat.Size()
+	if contentType := contentTypeOf(filepath); contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+		span.SetAttributes(attribute.String("upload.content_type", contentType))
+	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -80,3 +94,12 @@ func (u *HTTPUploader) Upload(ctx context.Context, filepath string, url string)
 	span.SetStatus(codes.Error, err.Error())
 	return err
 }
+
+// contentTypeOf tells the content type by the extension, empty when it is unknown
+func contentTypeOf(filepath string) string {
+	ext := strings.ToLower(path.Ext(filepath))
+	if container, ok := service.ContainerByExt(ext); ok {
+		return container.ContentType
+	}
+	return artifactContentTypes[ext]
+}
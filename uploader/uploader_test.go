@@ -0,0 +1,18 @@
+package uploader
+
+import "testing"
+
+func TestContentTypeOf(t *testing.T) {
+	for fp, want := range map[string]string{
+		"/tmp/result.mp3":          "audio/mpeg",
+		"/tmp/123-waveform.png":    "image/png",
+		"/tmp/123-preview.JPG":     "image/jpeg",
+		"/tmp/123-peaks.json":      "application/json",
+		"/tmp/something.unknown":   "",
+		"/tmp/no-extension-at-all": "",
+	} {
+		if got := contentTypeOf(fp); got != want {
+			t.Errorf("contentTypeOf(%q) = %q, want %q", fp, got, want)
+		}
+	}
+}